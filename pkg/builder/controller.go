@@ -23,12 +23,13 @@ import (
 	"github.com/go-logr/logr"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	internalcontroller "sigs.k8s.io/controller-runtime/pkg/internal/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -60,6 +61,7 @@ type Builder struct {
 	ctrl             controller.Controller
 	ctrlOptions      controller.Options
 	name             string
+	engagedClusters  bool
 }
 
 // ControllerManagedBy returns a new controller builder that will be started by the provided Manager.
@@ -155,6 +157,14 @@ func (blder *Builder) WithLogConstructor(logConstructor func(*reconcile.Request)
 	return blder
 }
 
+// WithEventRecorderName overrides the controller options's EventRecorderName, the component
+// name of the events recorded with controller.EventRecorderFromContext. Defaults to the
+// name of the controller.
+func (blder *Builder) WithEventRecorderName(name string) *Builder {
+	blder.ctrlOptions.EventRecorderName = name
+	return blder
+}
+
 // Named sets the name of the controller to the given name.  The name shows up
 // in metrics, among other things, and thus should be a prometheus compatible name
 // (underscores and alphanumeric characters only).
@@ -165,7 +175,20 @@ func (blder *Builder) Named(name string) *Builder {
 	return blder
 }
 
+// InEngagedClusters makes the controller watch the For and Owns types in every cluster
+// engaged by the manager, see manager.Options.ClusterProvider, instead of in the cluster
+// of the manager. The requests are qualified with the name of their cluster, see
+// reconcile.Request.ClusterName, and the reconciler gets the cluster with
+// manager.GetCluster. The sources given to Watches are not changed.
+func (blder *Builder) InEngagedClusters() *Builder {
+	blder.engagedClusters = true
+	return blder
+}
+
 // Complete builds the Application Controller.
+//
+// An ObjectReconciler can be passed by wrapping it with reconcile.AsReconciler, e.g.
+// Complete(reconcile.AsReconciler[*appsv1.ReplicaSet](mgr.GetClient(), r)).
 func (blder *Builder) Complete(r reconcile.Reconciler) error {
 	_, err := blder.Build(r)
 	return err
@@ -223,7 +246,7 @@ func (blder *Builder) doWatch() error {
 	if err != nil {
 		return err
 	}
-	src := &source.Kind{Type: typeForSrc}
+	src := blder.kindSource(typeForSrc)
 	hdler := &handler.EnqueueRequestForObject{}
 	allPredicates := append(blder.globalPredicates, blder.forInput.predicates...)
 	if err := blder.ctrl.Watch(src, hdler, allPredicates...); err != nil {
@@ -236,7 +259,7 @@ func (blder *Builder) doWatch() error {
 		if err != nil {
 			return err
 		}
-		src := &source.Kind{Type: typeForSrc}
+		src := blder.kindSource(typeForSrc)
 		hdler := &handler.EnqueueRequestForOwner{
 			OwnerType:    blder.forInput.object,
 			IsController: true,
@@ -269,6 +292,15 @@ func (blder *Builder) doWatch() error {
 	return nil
 }
 
+// kindSource returns the source of the events for the given type, in the engaged
+// clusters if InEngagedClusters was called.
+func (blder *Builder) kindSource(obj client.Object) source.Source {
+	if blder.engagedClusters {
+		return source.ClusterKind(obj)
+	}
+	return &source.Kind{Type: obj}
+}
+
 func (blder *Builder) getControllerName(gvk schema.GroupVersionKind) string {
 	if blder.name != "" {
 		return blder.name
@@ -306,22 +338,27 @@ func (blder *Builder) doController(r reconcile.Reconciler) error {
 	}
 
 	controllerName := blder.getControllerName(gvk)
+	if ctrlOptions.GroupKind.Empty() {
+		ctrlOptions.GroupKind = gvk.GroupKind()
+	}
 
 	// Setup the logger.
 	if ctrlOptions.LogConstructor == nil {
-		log := blder.mgr.GetLogger().WithValues(
-			"controller", controllerName,
-			"controllerGroup", gvk.Group,
-			"controllerKind", gvk.Kind,
-		)
+		log := blder.mgr.GetLogger()
+		if logf.HasReconcileLogField(logf.ReconcileLogFieldController) {
+			log = log.WithValues("controller", controllerName)
+		}
+		if logf.HasReconcileLogField(logf.ReconcileLogFieldControllerGroup) {
+			log = log.WithValues("controllerGroup", gvk.Group)
+		}
+		if logf.HasReconcileLogField(logf.ReconcileLogFieldControllerKind) {
+			log = log.WithValues("controllerKind", gvk.Kind)
+		}
 
 		ctrlOptions.LogConstructor = func(req *reconcile.Request) logr.Logger {
 			log := log
 			if req != nil {
-				log = log.WithValues(
-					gvk.Kind, klog.KRef(req.Namespace, req.Name),
-					"namespace", req.Namespace, "name", req.Name,
-				)
+				log = log.WithValues(internalcontroller.RequestLogValues(gvk.Kind, *req)...)
 			}
 			return log
 		}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/pointer"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -53,6 +54,17 @@ func (typedNoop) Reconcile(context.Context, reconcile.Request) (reconcile.Result
 	return reconcile.Result{}, nil
 }
 
+// watchRecordingController records the sources it's asked to watch.
+type watchRecordingController struct {
+	controller.Controller
+	sources []source.Source
+}
+
+func (c *watchRecordingController) Watch(src source.Source, _ handler.EventHandler, _ ...predicate.Predicate) error {
+	c.sources = append(c.sources, src)
+	return nil
+}
+
 type testLogger struct {
 	logr.Logger
 }
@@ -87,7 +99,7 @@ var _ = Describe("application", func() {
 	Describe("New", func() {
 		It("should return success if given valid objects", func() {
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			instance, err := ControllerManagedBy(m).
@@ -100,7 +112,7 @@ var _ = Describe("application", func() {
 
 		It("should return error if given two apiType objects in For function", func() {
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			instance, err := ControllerManagedBy(m).
@@ -114,7 +126,7 @@ var _ = Describe("application", func() {
 
 		It("should return an error if For function is not called", func() {
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			instance, err := ControllerManagedBy(m).
@@ -126,7 +138,7 @@ var _ = Describe("application", func() {
 
 		It("should return an error if there is no GVK for an object, and thus we can't default the controller name", func() {
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			By("creating a controller with a bad For type")
@@ -149,7 +161,7 @@ var _ = Describe("application", func() {
 			}
 
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			instance, err := ControllerManagedBy(m).
@@ -172,7 +184,7 @@ var _ = Describe("application", func() {
 			}
 
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			instance, err := ControllerManagedBy(m).
@@ -200,6 +212,7 @@ var _ = Describe("application", func() {
 					GroupKindConcurrency: map[string]int{
 						"ReplicaSet.apps": maxConcurrentReconciles,
 					},
+					SkipNameValidation: pointer.Bool(true),
 				},
 			})
 			Expect(err).NotTo(HaveOccurred())
@@ -212,6 +225,31 @@ var _ = Describe("application", func() {
 			Expect(instance).NotTo(BeNil())
 		})
 
+		It("should watch the For and Owns types in the engaged clusters", func() {
+			var ctrl *watchRecordingController
+			newController = func(name string, mgr manager.Manager, options controller.Options) (
+				controller.Controller, error) {
+				c, err := controller.NewUnmanaged(name, mgr, options)
+				ctrl = &watchRecordingController{Controller: c}
+				return ctrl, err
+			}
+
+			By("creating a controller manager")
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ControllerManagedBy(m).
+				For(&appsv1.ReplicaSet{}).
+				Owns(&appsv1.ReplicaSet{}).
+				InEngagedClusters().
+				Build(noop)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ctrl.sources).To(HaveLen(2))
+			for _, src := range ctrl.sources {
+				Expect(src).To(BeAssignableToTypeOf(source.ClusterKind(&appsv1.ReplicaSet{})))
+			}
+		})
+
 		It("should override rate limiter during creation of controller", func() {
 			rateLimiter := workqueue.DefaultItemBasedRateLimiter()
 			newController = func(name string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
@@ -222,7 +260,7 @@ var _ = Describe("application", func() {
 			}
 
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			instance, err := ControllerManagedBy(m).
@@ -245,7 +283,7 @@ var _ = Describe("application", func() {
 			}
 
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			instance, err := ControllerManagedBy(m).
@@ -259,6 +297,26 @@ var _ = Describe("application", func() {
 			Expect(instance).NotTo(BeNil())
 		})
 
+		It("should override the event recorder name during creation of controller", func() {
+			newController = func(name string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
+				if options.EventRecorderName == "custom-component" {
+					return controller.New(name, mgr, options)
+				}
+				return nil, fmt.Errorf("event recorder name expected %q but found %q", "custom-component", options.EventRecorderName)
+			}
+
+			By("creating a controller manager")
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
+			Expect(err).NotTo(HaveOccurred())
+
+			instance, err := ControllerManagedBy(m).
+				For(&appsv1.ReplicaSet{}).
+				WithEventRecorderName("custom-component").
+				Build(noop)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instance).NotTo(BeNil())
+		})
+
 		It("should prefer reconciler from options during creation of controller", func() {
 			newController = func(name string, mgr manager.Manager, options controller.Options) (controller.Controller, error) {
 				if options.Reconciler != (typedNoop{}) {
@@ -268,7 +326,7 @@ var _ = Describe("application", func() {
 			}
 
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			instance, err := ControllerManagedBy(m).
@@ -282,7 +340,7 @@ var _ = Describe("application", func() {
 
 		It("should allow multiple controllers for the same kind", func() {
 			By("creating a controller manager")
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			By("registering the type in the Scheme")
@@ -311,7 +369,7 @@ var _ = Describe("application", func() {
 
 	Describe("Start with ControllerManagedBy", func() {
 		It("should Reconcile Owns objects", func() {
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			bldr := ControllerManagedBy(m).
@@ -324,7 +382,7 @@ var _ = Describe("application", func() {
 		})
 
 		It("should Reconcile Watches objects", func() {
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			bldr := ControllerManagedBy(m).
@@ -341,7 +399,7 @@ var _ = Describe("application", func() {
 
 	Describe("Set custom predicates", func() {
 		It("should execute registered predicates only for assigned kind", func() {
-			m, err := manager.New(cfg, manager.Options{})
+			m, err := manager.New(cfg, manager.Options{Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 
 			var (
@@ -405,7 +463,7 @@ var _ = Describe("application", func() {
 			// use a cache that intercepts requests for fully typed objects to
 			// ensure we use the projected versions
 			var err error
-			mgr, err = manager.New(cfg, manager.Options{NewCache: newNonTypedOnlyCache})
+			mgr, err = manager.New(cfg, manager.Options{NewCache: newNonTypedOnlyCache, Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)}})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
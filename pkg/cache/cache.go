@@ -73,6 +73,32 @@ type Informers interface {
 	client.FieldIndexer
 }
 
+// NamespaceScopedCache is implemented by caches that are scoped to a set of namespaces
+// which can be changed at runtime, such as the caches built with MultiNamespacedCacheBuilder.
+// The informers handed out by the cache keep their event handlers and indexers across
+// the changes, so that the controllers watching them follow the namespaces of the cache.
+type NamespaceScopedCache interface {
+	// Namespaces returns the sorted namespaces the cache is scoped to.
+	Namespaces() []string
+
+	// AddNamespace scopes the cache to another namespace. If the cache is started,
+	// it waits for the informers of the namespace to sync, or returns an error if
+	// the context is done first.
+	AddNamespace(ctx context.Context, namespace string) error
+
+	// RemoveNamespace stops the informers of a namespace, and removes it from the
+	// scope of the cache.
+	RemoveNamespace(namespace string) error
+}
+
+// InformerSyncStatus is implemented by caches that can report the sync status of their
+// informers without blocking and without creating informers, e.g. for readiness checks.
+type InformerSyncStatus interface {
+	// UnsyncedInformers returns whether the cache has been started, and the sorted
+	// GroupVersionKinds of its informers that have not synced yet.
+	UnsyncedInformers() (started bool, unsynced []schema.GroupVersionKind)
+}
+
 // Informer - informer allows you interact with the underlying informer.
 type Informer interface {
 	// AddEventHandler adds an event handler to the shared informer using the shared informer's resync
@@ -22,6 +22,7 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"sync"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -955,6 +956,62 @@ func CacheTest(createCacheFunc func(config *rest.Config, opts cache.Options) (ca
 					Expect(nodeList.Items).NotTo(BeEmpty())
 					Expect(len(nodeList.Items)).To(BeEquivalentTo(1))
 				})
+				It("should change the namespaces of a multinamespaced cache at runtime", func() {
+					By("creating a multinamespaced cache to watch a single namespace")
+					multi := cache.MultiNamespacedCacheBuilder([]string{testNamespaceOne})
+					m, err := multi(cfg, cache.Options{})
+					Expect(err).NotTo(HaveOccurred())
+					scoped, ok := m.(cache.NamespaceScopedCache)
+					Expect(ok).To(BeTrue())
+
+					By("indexing the pods and adding an event handler to their informer")
+					Expect(m.IndexField(informerCacheCtx, &corev1.Pod{}, "spec.restartPolicy", func(obj client.Object) []string {
+						return []string{string(obj.(*corev1.Pod).Spec.RestartPolicy)}
+					})).To(Succeed())
+					informer, err := m.GetInformer(informerCacheCtx, &corev1.Pod{})
+					Expect(err).NotTo(HaveOccurred())
+					var mu sync.Mutex
+					var added []string
+					informer.AddEventHandler(kcache.ResourceEventHandlerFuncs{
+						AddFunc: func(obj interface{}) {
+							mu.Lock()
+							defer mu.Unlock()
+							added = append(added, obj.(*corev1.Pod).Name)
+						},
+					})
+					addedPods := func() []string {
+						mu.Lock()
+						defer mu.Unlock()
+						return append([]string(nil), added...)
+					}
+
+					By("running the cache and waiting it for sync")
+					go func() {
+						defer GinkgoRecover()
+						Expect(m.Start(informerCacheCtx)).To(Succeed())
+					}()
+					Expect(m.WaitForCacheSync(informerCacheCtx)).To(BeTrue())
+					Eventually(addedPods).Should(ConsistOf("test-pod-1", "test-pod-5"))
+
+					By("adding a namespace")
+					Expect(scoped.AddNamespace(informerCacheCtx, testNamespaceTwo)).To(Succeed())
+					Expect(scoped.Namespaces()).To(Equal([]string{testNamespaceOne, testNamespaceTwo}))
+					Eventually(addedPods).Should(ConsistOf("test-pod-1", "test-pod-5", "test-pod-2", "test-pod-3", "test-pod-6"))
+					Expect(informer.HasSynced()).To(BeTrue())
+
+					By("listing the pods of the added namespace by the indexed field")
+					pods := &corev1.PodList{}
+					Expect(m.List(context.Background(), pods, client.InNamespace(testNamespaceTwo), client.MatchingFields{"spec.restartPolicy": "Always"})).To(Succeed())
+					Expect(pods.Items).To(HaveLen(2))
+
+					By("removing a namespace")
+					Expect(scoped.RemoveNamespace(testNamespaceOne)).To(Succeed())
+					Expect(scoped.Namespaces()).To(Equal([]string{testNamespaceTwo}))
+					Expect(m.Get(context.Background(), client.ObjectKeyFromObject(knownPod1), &corev1.Pod{})).NotTo(Succeed())
+					Expect(m.List(context.Background(), pods)).To(Succeed())
+					Expect(pods.Items).To(HaveLen(3))
+					Expect(scoped.RemoveNamespace(testNamespaceOne)).NotTo(Succeed())
+				})
 			})
 			Context("with metadata-only objects", func() {
 				It("should be able to list objects that haven't been watched previously", func() {
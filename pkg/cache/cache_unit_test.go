@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"reflect"
 	"time"
 
@@ -448,6 +449,75 @@ var _ = Describe("cache.inheritFrom", func() {
 	})
 })
 
+var _ = Describe("multiNamespaceCache", func() {
+	It("should change its namespaces while the informers of a kind are being got", func() {
+		gvk := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+		unblock := make(chan struct{})
+		unblocked := make(chan struct{})
+		close(unblocked)
+		c := &multiNamespaceCache{
+			namespaceToCache:  map[string]Cache{"a": &blockingCache{unblock: unblock}},
+			namespaceToCancel: map[string]context.CancelFunc{},
+			informers:         map[informerKey]*multiNamespaceInformer{},
+			newNamespacedCache: func(string) (Cache, error) {
+				return &blockingCache{unblock: unblocked}, nil
+			},
+		}
+
+		informers := make(chan Informer, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				defer GinkgoRecover()
+				informer, err := c.namespacedInformer(context.Background(), informerKey{gvk: gvk}, func(ctx context.Context, cache Cache) (Informer, error) {
+					return cache.GetInformerForKind(ctx, gvk)
+				})
+				Expect(err).NotTo(HaveOccurred())
+				informers <- informer
+			}()
+		}
+		Eventually(func() int {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+			return len(c.informers)
+		}).Should(Equal(1))
+
+		By("adding a namespace while the informer of namespace a is blocked")
+		added := make(chan error)
+		go func() {
+			added <- c.AddNamespace(context.Background(), "b")
+		}()
+		Eventually(added).Should(Receive(BeNil()))
+		Consistently(informers, "50ms").ShouldNot(Receive())
+
+		close(unblock)
+		for i := 0; i < 2; i++ {
+			var informer Informer
+			Eventually(informers).Should(Receive(&informer))
+			Expect(informer.(*multiNamespaceInformer).namespaceToInformer).To(SatisfyAll(HaveKey("a"), HaveKey("b")))
+		}
+	})
+})
+
+// blockingCache is a cache whose GetInformerForKind blocks until unblock is closed.
+type blockingCache struct {
+	Cache
+	unblock chan struct{}
+}
+
+func (c *blockingCache) GetInformerForKind(ctx context.Context, _ schema.GroupVersionKind) (Informer, error) {
+	select {
+	case <-c.unblock:
+		return &nopInformer{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// nopInformer is an informer that no handlers or indexers are added to.
+type nopInformer struct {
+	Informer
+}
+
 func checkError[T any](v T, err error) T {
 	Expect(err).To(BeNil())
 	return v
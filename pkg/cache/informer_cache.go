@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"reflect"
-	"strings"
 
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -33,9 +32,10 @@ import (
 )
 
 var (
-	_ Informers     = &informerCache{}
-	_ client.Reader = &informerCache{}
-	_ Cache         = &informerCache{}
+	_ Informers          = &informerCache{}
+	_ client.Reader      = &informerCache{}
+	_ Cache              = &informerCache{}
+	_ InformerSyncStatus = &informerCache{}
 )
 
 // ErrCacheNotStarted is returned when trying to read from the cache that wasn't started.
@@ -91,16 +91,12 @@ func (ip *informerCache) List(ctx context.Context, out client.ObjectList, opts .
 // for a single object corresponding to the passed-in list type. We need them
 // because they are used as cache map key.
 func (ip *informerCache) objectTypeForListObject(list client.ObjectList) (*schema.GroupVersionKind, runtime.Object, error) {
-	gvk, err := apiutil.GVKForObject(list, ip.Scheme)
+	// we need the non-list GVK, as it's used as cache map key
+	gvk, err := apiutil.GVKForList(list, ip.Scheme)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// we need the non-list GVK, so chop off the "List" from the end of the kind
-	if strings.HasSuffix(gvk.Kind, "List") && apimeta.IsListType(list) {
-		gvk.Kind = gvk.Kind[:len(gvk.Kind)-4]
-	}
-
 	_, isUnstructured := list.(*unstructured.UnstructuredList)
 	var cacheTypeObj runtime.Object
 	if isUnstructured {
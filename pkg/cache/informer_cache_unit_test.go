@@ -17,6 +17,8 @@ limitations under the License.
 package cache
 
 import (
+	"errors"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -27,6 +29,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache/internal"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
 	crscheme "sigs.k8s.io/controller-runtime/pkg/scheme"
 )
@@ -91,4 +94,25 @@ var _ = Describe("ip.objectTypeForListObject", func() {
 			Expect(obj).To(Equal(referenceObject))
 		})
 	})
+	It("should return an error for unstructured lists without kind", func() {
+		_, _, err := ip.objectTypeForListObject(&unstructured.UnstructuredList{})
+		Expect(runtime.IsMissingKind(err)).To(BeTrue())
+	})
+
+	It("should pick the GVK set on a list whose type is registered in several groups", func() {
+		s := runtime.NewScheme()
+		Expect(scheme.AddToScheme(s)).To(Succeed())
+		vendored := schema.GroupVersion{Group: "vendored.example.com", Version: "v1"}
+		s.AddKnownTypes(vendored, &corev1.Pod{}, &corev1.PodList{})
+		ip := &informerCache{InformersMap: &internal.InformersMap{Scheme: s}}
+
+		_, _, err := ip.objectTypeForListObject(&corev1.PodList{})
+		Expect(errors.As(err, new(*apiutil.AmbiguousGVKError))).To(BeTrue())
+
+		list := &corev1.PodList{}
+		list.SetGroupVersionKind(vendored.WithKind("PodList"))
+		gvk, _, err := ip.objectTypeForListObject(list)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*gvk).To(Equal(vendored.WithKind("Pod")))
+	})
 })
@@ -18,6 +18,7 @@ package informertest
 
 import (
 	"context"
+	"sort"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -29,6 +30,7 @@ import (
 )
 
 var _ cache.Cache = &FakeInformers{}
+var _ cache.InformerSyncStatus = &FakeInformers{}
 
 // FakeInformers is a fake implementation of Informers.
 type FakeInformers struct {
@@ -87,6 +89,19 @@ func (c *FakeInformers) WaitForCacheSync(ctx context.Context) bool {
 	return *c.Synced
 }
 
+// UnsyncedInformers implements cache.InformerSyncStatus. The fake is always started,
+// and its informers are unsynced if their HasSynced returns false.
+func (c *FakeInformers) UnsyncedInformers() (bool, []schema.GroupVersionKind) {
+	var unsynced []schema.GroupVersionKind
+	for gvk, informer := range c.InformersByGVK {
+		if !informer.HasSynced() {
+			unsynced = append(unsynced, gvk)
+		}
+	}
+	sort.Slice(unsynced, func(i, j int) bool { return unsynced[i].String() < unsynced[j].String() })
+	return true, unsynced
+}
+
 // FakeInformerFor implements Informers.
 func (c *FakeInformers) FakeInformerFor(obj runtime.Object) (*controllertest.FakeInformer, error) {
 	if c.Scheme == nil {
@@ -18,6 +18,7 @@ package internal
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -90,6 +91,32 @@ func (m *InformersMap) WaitForCacheSync(ctx context.Context) bool {
 	return cache.WaitForCacheSync(ctx.Done(), syncedFuncs...)
 }
 
+// UnsyncedInformers returns whether all the informers have been started, and the sorted
+// GroupVersionKinds of the informers that have not synced yet. It doesn't block.
+func (m *InformersMap) UnsyncedInformers() (bool, []schema.GroupVersionKind) {
+	started := true
+	gvks := map[schema.GroupVersionKind]bool{}
+	for _, ip := range []*specificInformersMap{m.structured, m.unstructured, m.metadata} {
+		if !ip.hasStarted() {
+			started = false
+		}
+		for _, gvk := range ip.unsyncedGVKs() {
+			gvks[gvk] = true
+		}
+	}
+	return started, sortedGVKs(gvks)
+}
+
+// sortedGVKs returns the given GroupVersionKinds sorted by their string representation.
+func sortedGVKs(gvks map[schema.GroupVersionKind]bool) []schema.GroupVersionKind {
+	sorted := make([]schema.GroupVersionKind, 0, len(gvks))
+	for gvk := range gvks {
+		sorted = append(sorted, gvk)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+	return sorted
+}
+
 // Get will create a new Informer and add it to the map of InformersMap if none exists.  Returns
 // the Informer from the map.
 func (m *InformersMap) Get(ctx context.Context, gvk schema.GroupVersionKind, obj runtime.Object) (bool, *MapEntry, error) {
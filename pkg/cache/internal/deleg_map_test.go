@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ = Describe("InformersMap.UnsyncedInformers", func() {
+	var m *InformersMap
+
+	BeforeEach(func() {
+		m = NewInformersMap(&rest.Config{}, scheme.Scheme, nil, time.Hour, "", nil, nil, nil)
+	})
+
+	It("should report that it hasn't been started before Start", func() {
+		started, unsynced := m.UnsyncedInformers()
+		Expect(started).To(BeFalse())
+		Expect(unsynced).To(BeEmpty())
+	})
+
+	It("should report the informers that never synced", func() {
+		gvk := corev1.SchemeGroupVersion.WithKind("Pod")
+		// The list never returns, so the informer never syncs.
+		informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				select {}
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}, &corev1.Pod{}, 0, cache.Indexers{})
+		m.structured.informersByGVK[gvk] = &MapEntry{Informer: informer}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(m.Start(ctx)).To(Succeed())
+		}()
+
+		Eventually(func() bool {
+			started, _ := m.UnsyncedInformers()
+			return started
+		}).Should(BeTrue())
+		_, unsynced := m.UnsyncedInformers()
+		Expect(unsynced).To(ConsistOf(gvk))
+	})
+})
@@ -154,8 +154,8 @@ func (ip *specificInformersMap) Start(ctx context.Context) {
 		ip.stop = ctx.Done()
 
 		// Start each informer
-		for _, informer := range ip.informersByGVK {
-			go informer.Informer.Run(ctx.Done())
+		for gvk, informer := range ip.informersByGVK {
+			go runInformer(gvk, informer.Informer, ctx.Done())
 		}
 
 		// Set started to true so we immediately start any informers added later.
@@ -174,6 +174,29 @@ func (ip *specificInformersMap) waitForStarted(ctx context.Context) bool {
 	}
 }
 
+// hasStarted returns whether the informers have been started, without blocking.
+func (ip *specificInformersMap) hasStarted() bool {
+	select {
+	case <-ip.startWait:
+		return true
+	default:
+		return false
+	}
+}
+
+// unsyncedGVKs returns the GroupVersionKinds of the informers in this map that have not synced yet.
+func (ip *specificInformersMap) unsyncedGVKs() []schema.GroupVersionKind {
+	ip.mu.RLock()
+	defer ip.mu.RUnlock()
+	var gvks []schema.GroupVersionKind
+	for gvk, informer := range ip.informersByGVK {
+		if !informer.Informer.HasSynced() {
+			gvks = append(gvks, gvk)
+		}
+	}
+	return gvks
+}
+
 // HasSyncedFuncs returns all the HasSynced functions for the informers in this map.
 func (ip *specificInformersMap) HasSyncedFuncs() []cache.InformerSynced {
 	ip.mu.RLock()
@@ -230,7 +253,7 @@ func (ip *specificInformersMap) addInformerToMap(gvk schema.GroupVersionKind, ob
 	if err != nil {
 		return nil, false, err
 	}
-	ni := cache.NewSharedIndexInformer(lw, obj, resyncPeriod(ip.resync)(), cache.Indexers{
+	ni := cache.NewSharedIndexInformer(newInstrumentedListWatch(gvk, lw), obj, resyncPeriod(ip.resync)(), cache.Indexers{
 		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
 	})
 
@@ -259,7 +282,7 @@ func (ip *specificInformersMap) addInformerToMap(gvk schema.GroupVersionKind, ob
 	// TODO(seans): write thorough tests and document what happens here - can you add indexers?
 	// can you add eventhandlers?
 	if ip.started {
-		go i.Informer.Run(ip.stop)
+		go runInformer(gvk, i.Informer, ip.stop)
 	}
 	return i, ip.started, nil
 }
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	internalmetrics "sigs.k8s.io/controller-runtime/pkg/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// CacheSyncDuration is a prometheus metric which keeps track of the time it
+	// took each informer to sync for the first time.
+	CacheSyncDuration = internalmetrics.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_runtime_cache_sync_duration_seconds",
+		Help:    "Time it took an informer to sync for the first time per group, version and kind",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600},
+	}, []string{"group", "version", "kind"})
+
+	// CacheLastRelist is a prometheus metric which holds the unix timestamp
+	// of the last successful full list of each informer.
+	CacheLastRelist = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_runtime_cache_last_relist_timestamp_seconds",
+		Help: "Unix timestamp of the last successful full list per group, version and kind",
+	}, []string{"group", "version", "kind"})
+
+	// CacheWatchRestarts is a prometheus metric which holds the number of times
+	// the watch of each informer was re-established.
+	CacheWatchRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_runtime_cache_watch_restarts_total",
+		Help: "Total number of watch re-establishments per group, version and kind",
+	}, []string{"group", "version", "kind"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		CacheSyncDuration,
+		CacheLastRelist,
+		CacheWatchRestarts,
+	)
+}
+
+// instrumentedListWatch wraps a ListerWatcher to record relists and watch restarts.
+type instrumentedListWatch struct {
+	cache.ListerWatcher
+	gvk schema.GroupVersionKind
+
+	mu      sync.Mutex
+	watched bool
+}
+
+func newInstrumentedListWatch(gvk schema.GroupVersionKind, lw cache.ListerWatcher) *instrumentedListWatch {
+	return &instrumentedListWatch{ListerWatcher: lw, gvk: gvk}
+}
+
+// List implements cache.Lister.
+func (lw *instrumentedListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	res, err := lw.ListerWatcher.List(options)
+	if err == nil {
+		CacheLastRelist.WithLabelValues(lw.gvk.Group, lw.gvk.Version, lw.gvk.Kind).SetToCurrentTime()
+	}
+	return res, err
+}
+
+// Watch implements cache.Watcher. Every call but the first one is counted as a restart.
+func (lw *instrumentedListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	lw.mu.Lock()
+	if lw.watched {
+		CacheWatchRestarts.WithLabelValues(lw.gvk.Group, lw.gvk.Version, lw.gvk.Kind).Inc()
+	}
+	lw.watched = true
+	lw.mu.Unlock()
+
+	return lw.ListerWatcher.Watch(options)
+}
+
+// runInformer runs the informer until stop is closed, and records the time
+// it took the informer to sync for the first time.
+func runInformer(gvk schema.GroupVersionKind, informer cache.SharedIndexInformer, stop <-chan struct{}) {
+	start := time.Now()
+	go func() {
+		if cache.WaitForCacheSync(stop, informer.HasSynced) {
+			CacheSyncDuration.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Observe(time.Since(start).Seconds())
+		}
+	}()
+	informer.Run(stop)
+}
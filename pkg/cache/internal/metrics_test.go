@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ = Describe("cache metrics", func() {
+	var fakeLW *cache.ListWatch
+
+	BeforeEach(func() {
+		fakeLW = &cache.ListWatch{
+			ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+				time.Sleep(200 * time.Millisecond)
+				return &corev1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}}, nil
+			},
+			WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}
+	})
+
+	It("should record the time it took an informer to sync", func() {
+		gvk := schema.GroupVersionKind{Group: "sync.test", Version: "v1", Kind: "Pod"}
+		informer := cache.NewSharedIndexInformer(newInstrumentedListWatch(gvk, fakeLW), &corev1.Pod{}, 0, cache.Indexers{})
+
+		stop := make(chan struct{})
+		defer close(stop)
+		go runInformer(gvk, informer, stop)
+
+		var metric dto.Metric
+		Eventually(func() uint64 {
+			hist := CacheSyncDuration.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).(prometheus.Histogram)
+			Expect(hist.Write(&metric)).To(Succeed())
+			return metric.GetHistogram().GetSampleCount()
+		}).Should(Equal(uint64(1)))
+		Expect(metric.GetHistogram().GetSampleSum()).To(BeNumerically(">=", 0.2))
+	})
+
+	It("should record the time of the last successful list", func() {
+		gvk := schema.GroupVersionKind{Group: "relist.test", Version: "v1", Kind: "Pod"}
+		lw := newInstrumentedListWatch(gvk, fakeLW)
+
+		before := time.Now()
+		_, err := lw.List(metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		var metric dto.Metric
+		Expect(CacheLastRelist.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Write(&metric)).To(Succeed())
+		Expect(metric.GetGauge().GetValue()).To(BeNumerically(">=", float64(before.Unix())))
+	})
+
+	It("should count every watch but the first one as a restart", func() {
+		gvk := schema.GroupVersionKind{Group: "restart.test", Version: "v1", Kind: "Pod"}
+		lw := newInstrumentedListWatch(gvk, fakeLW)
+
+		var metric dto.Metric
+		for i := 0; i < 3; i++ {
+			_, err := lw.Watch(metav1.ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(CacheWatchRestarts.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind).Write(&metric)).To(Succeed())
+		Expect(metric.GetCounter().GetValue()).To(Equal(2.0))
+	})
+})
@@ -19,6 +19,9 @@ package cache
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +31,7 @@ import (
 	"k8s.io/client-go/rest"
 	toolscache "k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/internal/objectutil"
 )
 
@@ -43,6 +47,8 @@ const globalCache = "_cluster-scope"
 // a global cache for cluster scoped resource. Note that this is not intended
 // to be used for excluding namespaces, this is better done via a Predicate. Also note that
 // you may face performance issues when using this with a high number of namespaces.
+//
+// The namespaces of the cache can be changed at runtime, see NamespaceScopedCache.
 func MultiNamespacedCacheBuilder(namespaces []string) NewCacheFunc {
 	return func(config *rest.Config, opts Options) (Cache, error) {
 		opts, err := defaultOpts(config, opts)
@@ -50,23 +56,33 @@ func MultiNamespacedCacheBuilder(namespaces []string) NewCacheFunc {
 			return nil, err
 		}
 
-		caches := map[string]Cache{}
-
 		// create a cache for cluster scoped resources
 		gCache, err := New(config, opts)
 		if err != nil {
 			return nil, fmt.Errorf("error creating global cache: %w", err)
 		}
 
+		c := &multiNamespaceCache{
+			namespaceToCache:  map[string]Cache{},
+			namespaceToCancel: map[string]context.CancelFunc{},
+			informers:         map[informerKey]*multiNamespaceInformer{},
+			Scheme:            opts.Scheme,
+			RESTMapper:        opts.Mapper,
+			clusterCache:      gCache,
+			newNamespacedCache: func(namespace string) (Cache, error) {
+				opts := opts
+				opts.Namespace = namespace
+				return New(config, opts)
+			},
+		}
 		for _, ns := range namespaces {
-			opts.Namespace = ns
-			c, err := New(config, opts)
+			cache, err := c.newNamespacedCache(ns)
 			if err != nil {
 				return nil, err
 			}
-			caches[ns] = c
+			c.namespaceToCache[ns] = cache
 		}
-		return &multiNamespaceCache{namespaceToCache: caches, Scheme: opts.Scheme, RESTMapper: opts.Mapper, clusterCache: gCache}, nil
+		return c, nil
 	}
 }
 
@@ -75,13 +91,50 @@ func MultiNamespacedCacheBuilder(namespaces []string) NewCacheFunc {
 // operator to a list of namespaces instead of watching every namespace
 // in the cluster.
 type multiNamespaceCache struct {
+	Scheme       *runtime.Scheme
+	RESTMapper   apimeta.RESTMapper
+	clusterCache Cache
+
+	// newNamespacedCache creates the cache of a namespace.
+	newNamespacedCache func(namespace string) (Cache, error)
+
+	// scopeMu serializes the changes of the namespaces with Start, and with the
+	// creation of the informers and indexes that are replicated to the namespaces
+	// that are added.
+	scopeMu sync.Mutex
+
+	mu               sync.RWMutex
 	namespaceToCache map[string]Cache
-	Scheme           *runtime.Scheme
-	RESTMapper       apimeta.RESTMapper
-	clusterCache     Cache
+	// namespaceToCancel stops the caches of the namespaces once they're started.
+	namespaceToCancel map[string]context.CancelFunc
+	// ctx is the context the cache was started with, nil until it's started.
+	ctx context.Context
+	// informers are the informers of namespaced objects that were handed out,
+	// which get the informer of every namespace that is added.
+	informers map[informerKey]*multiNamespaceInformer
+	// indexes are the fields of namespaced objects that were indexed, which are
+	// indexed in every namespace that is added.
+	indexes []fieldIndex
+}
+
+// informerKey identifies the informers of a kind of namespaced objects. The objects
+// of the same kind can be structured, unstructured or metadata-only, depending on the
+// type of the object the informer was got for, which is nil for GetInformerForKind.
+type informerKey struct {
+	gvk     schema.GroupVersionKind
+	objType reflect.Type
+}
+
+// fieldIndex is a field of namespaced objects indexed with IndexField.
+type fieldIndex struct {
+	obj          client.Object
+	field        string
+	extractValue client.IndexerFunc
 }
 
 var _ Cache = &multiNamespaceCache{}
+var _ InformerSyncStatus = &multiNamespaceCache{}
+var _ NamespaceScopedCache = &multiNamespaceCache{}
 
 // Methods for multiNamespaceCache to conform to the Informers interface.
 func (c *multiNamespaceCache) GetInformer(ctx context.Context, obj client.Object) (Informer, error) {
@@ -103,15 +156,14 @@ func (c *multiNamespaceCache) GetInformer(ctx context.Context, obj client.Object
 		return &multiNamespaceInformer{namespaceToInformer: informers}, nil
 	}
 
-	for ns, cache := range c.namespaceToCache {
-		informer, err := cache.GetInformer(ctx, obj)
-		if err != nil {
-			return nil, err
-		}
-		informers[ns] = informer
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme)
+	if err != nil {
+		return nil, err
 	}
-
-	return &multiNamespaceInformer{namespaceToInformer: informers}, nil
+	obj = obj.DeepCopyObject().(client.Object)
+	return c.namespacedInformer(ctx, informerKey{gvk: gvk, objType: reflect.TypeOf(obj)}, func(ctx context.Context, cache Cache) (Informer, error) {
+		return cache.GetInformer(ctx, obj)
+	})
 }
 
 func (c *multiNamespaceCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (Informer, error) {
@@ -133,18 +185,70 @@ func (c *multiNamespaceCache) GetInformerForKind(ctx context.Context, gvk schema
 		return &multiNamespaceInformer{namespaceToInformer: informers}, nil
 	}
 
-	for ns, cache := range c.namespaceToCache {
-		informer, err := cache.GetInformerForKind(ctx, gvk)
-		if err != nil {
-			return nil, err
+	return c.namespacedInformer(ctx, informerKey{gvk: gvk}, func(ctx context.Context, cache Cache) (Informer, error) {
+		return cache.GetInformerForKind(ctx, gvk)
+	})
+}
+
+// namespacedInformer returns the informer of namespaced objects with the given key,
+// which is made of the informers got from the cache of each namespace with get. As
+// getting them blocks until they're synced once the caches are started, the informer
+// is registered beforehand, so that the namespaces can be changed meanwhile: the
+// namespaces that are added get their informer like for the informers handed out.
+func (c *multiNamespaceCache) namespacedInformer(ctx context.Context, key informerKey, get func(context.Context, Cache) (Informer, error)) (Informer, error) {
+	c.mu.RLock()
+	informer, ok := c.informers[key]
+	c.mu.RUnlock()
+	if ok {
+		return informer.waitReady(ctx)
+	}
+
+	c.scopeMu.Lock()
+	c.mu.Lock()
+	informer, ok = c.informers[key]
+	caches := make(map[string]Cache, len(c.namespaceToCache))
+	if !ok {
+		informer = &multiNamespaceInformer{namespaceToInformer: map[string]Informer{}, get: get, ready: make(chan struct{})}
+		c.informers[key] = informer
+		for ns, cache := range c.namespaceToCache {
+			caches[ns] = cache
 		}
-		informers[ns] = informer
+	}
+	c.mu.Unlock()
+	c.scopeMu.Unlock()
+	if ok {
+		return informer.waitReady(ctx)
 	}
 
-	return &multiNamespaceInformer{namespaceToInformer: informers}, nil
+	for ns, cache := range caches {
+		nsInformer, err := get(ctx, cache)
+		if err == nil {
+			c.mu.RLock()
+			// The namespace may have been removed meanwhile.
+			if c.namespaceToCache[ns] == cache {
+				err = informer.addNamespace(ns, nsInformer)
+			}
+			c.mu.RUnlock()
+		}
+		if err != nil {
+			informer.err = err
+			break
+		}
+	}
+	if informer.err != nil {
+		c.mu.Lock()
+		delete(c.informers, key)
+		c.mu.Unlock()
+	}
+	close(informer.ready)
+	return informer.waitReady(ctx)
 }
 
 func (c *multiNamespaceCache) Start(ctx context.Context) error {
+	c.scopeMu.Lock()
+	c.mu.Lock()
+	c.ctx = ctx
+
 	// start global cache
 	go func() {
 		err := c.clusterCache.Start(ctx)
@@ -155,21 +259,151 @@ func (c *multiNamespaceCache) Start(ctx context.Context) error {
 
 	// start namespaced caches
 	for ns, cache := range c.namespaceToCache {
-		go func(ns string, cache Cache) {
-			err := cache.Start(ctx)
-			if err != nil {
-				log.Error(err, "multinamespace cache failed to start namespaced informer", "namespace", ns)
-			}
-		}(ns, cache)
+		c.startNamespacedCache(ns, cache)
 	}
+	c.mu.Unlock()
+	c.scopeMu.Unlock()
 
 	<-ctx.Done()
 	return nil
 }
 
+// startNamespacedCache starts the cache of a namespace with a context that is cancelled
+// when the namespace is removed. It must be called with the lock held, once the cache
+// is started.
+func (c *multiNamespaceCache) startNamespacedCache(ns string, cache Cache) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.namespaceToCancel[ns] = cancel
+	go func() {
+		err := cache.Start(ctx)
+		if err != nil {
+			log.Error(err, "multinamespace cache failed to start namespaced informer", "namespace", ns)
+		}
+	}()
+}
+
+// namespacedCaches returns the caches of the namespaces.
+func (c *multiNamespaceCache) namespacedCaches() []Cache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	caches := make([]Cache, 0, len(c.namespaceToCache))
+	for _, cache := range c.namespaceToCache {
+		caches = append(caches, cache)
+	}
+	return caches
+}
+
+// Namespaces implements NamespaceScopedCache.
+func (c *multiNamespaceCache) Namespaces() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	namespaces := make([]string, 0, len(c.namespaceToCache))
+	for ns := range c.namespaceToCache {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// AddNamespace implements NamespaceScopedCache.
+func (c *multiNamespaceCache) AddNamespace(ctx context.Context, namespace string) error {
+	c.scopeMu.Lock()
+	defer c.scopeMu.Unlock()
+
+	c.mu.RLock()
+	_, ok := c.namespaceToCache[namespace]
+	started := c.ctx != nil
+	informers := make([]*multiNamespaceInformer, 0, len(c.informers))
+	for _, informer := range c.informers {
+		informers = append(informers, informer)
+	}
+	indexes := c.indexes
+	c.mu.RUnlock()
+	if ok {
+		return nil
+	}
+
+	cache, err := c.newNamespacedCache(namespace)
+	if err != nil {
+		return err
+	}
+
+	// The indexes and informers are added before the cache is started, as indexers
+	// can't be added to informers that are started.
+	for _, index := range indexes {
+		if err := cache.IndexField(ctx, index.obj, index.field, index.extractValue); err != nil {
+			return err
+		}
+	}
+	removeInformers := func() {
+		for _, informer := range informers {
+			informer.removeNamespace(namespace)
+		}
+	}
+	for _, informer := range informers {
+		nsInformer, err := informer.get(ctx, cache)
+		if err == nil {
+			err = informer.addNamespace(namespace, nsInformer)
+		}
+		if err != nil {
+			removeInformers()
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	if started {
+		c.startNamespacedCache(namespace, cache)
+	}
+	cancel := c.namespaceToCancel[namespace]
+	c.mu.Unlock()
+
+	if started && !cache.WaitForCacheSync(ctx) {
+		c.mu.Lock()
+		delete(c.namespaceToCancel, namespace)
+		c.mu.Unlock()
+		cancel()
+		removeInformers()
+		return fmt.Errorf("unable to sync the cache of namespace %q", namespace)
+	}
+
+	c.mu.Lock()
+	c.namespaceToCache[namespace] = cache
+	c.mu.Unlock()
+	return nil
+}
+
+// RemoveNamespace implements NamespaceScopedCache.
+func (c *multiNamespaceCache) RemoveNamespace(namespace string) error {
+	c.scopeMu.Lock()
+	defer c.scopeMu.Unlock()
+
+	c.mu.Lock()
+	if _, ok := c.namespaceToCache[namespace]; !ok {
+		c.mu.Unlock()
+		return fmt.Errorf("unable to remove namespace %q because it's unknown to the cache", namespace)
+	}
+	delete(c.namespaceToCache, namespace)
+	cancel := c.namespaceToCancel[namespace]
+	delete(c.namespaceToCancel, namespace)
+	informers := make([]*multiNamespaceInformer, 0, len(c.informers))
+	for _, informer := range c.informers {
+		informers = append(informers, informer)
+	}
+	c.mu.Unlock()
+
+	for _, informer := range informers {
+		informer.removeNamespace(namespace)
+	}
+	if cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
 func (c *multiNamespaceCache) WaitForCacheSync(ctx context.Context) bool {
 	synced := true
-	for _, cache := range c.namespaceToCache {
+	for _, cache := range c.namespacedCaches() {
 		if s := cache.WaitForCacheSync(ctx); !s {
 			synced = s
 		}
@@ -182,6 +416,34 @@ func (c *multiNamespaceCache) WaitForCacheSync(ctx context.Context) bool {
 	return synced
 }
 
+// UnsyncedInformers implements InformerSyncStatus.
+func (c *multiNamespaceCache) UnsyncedInformers() (bool, []schema.GroupVersionKind) {
+	caches := append([]Cache{c.clusterCache}, c.namespacedCaches()...)
+
+	started := true
+	gvks := map[schema.GroupVersionKind]bool{}
+	for _, cache := range caches {
+		status, ok := cache.(InformerSyncStatus)
+		if !ok {
+			continue
+		}
+		cacheStarted, unsynced := status.UnsyncedInformers()
+		if !cacheStarted {
+			started = false
+		}
+		for _, gvk := range unsynced {
+			gvks[gvk] = true
+		}
+	}
+
+	unsynced := make([]schema.GroupVersionKind, 0, len(gvks))
+	for gvk := range gvks {
+		unsynced = append(unsynced, gvk)
+	}
+	sort.Slice(unsynced, func(i, j int) bool { return unsynced[i].String() < unsynced[j].String() })
+	return started, unsynced
+}
+
 func (c *multiNamespaceCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
 	isNamespaced, err := objectutil.IsAPINamespaced(obj, c.Scheme, c.RESTMapper)
 	if err != nil {
@@ -192,11 +454,16 @@ func (c *multiNamespaceCache) IndexField(ctx context.Context, obj client.Object,
 		return c.clusterCache.IndexField(ctx, obj, field, extractValue)
 	}
 
-	for _, cache := range c.namespaceToCache {
+	c.scopeMu.Lock()
+	defer c.scopeMu.Unlock()
+	for _, cache := range c.namespacedCaches() {
 		if err := cache.IndexField(ctx, obj, field, extractValue); err != nil {
 			return err
 		}
 	}
+	c.mu.Lock()
+	c.indexes = append(c.indexes, fieldIndex{obj: obj, field: field, extractValue: extractValue})
+	c.mu.Unlock()
 	return nil
 }
 
@@ -211,7 +478,9 @@ func (c *multiNamespaceCache) Get(ctx context.Context, key client.ObjectKey, obj
 		return c.clusterCache.Get(ctx, key, obj)
 	}
 
+	c.mu.RLock()
 	cache, ok := c.namespaceToCache[key.Namespace]
+	c.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("unable to get: %v because of unknown namespace for the cache", key)
 	}
@@ -234,7 +503,9 @@ func (c *multiNamespaceCache) List(ctx context.Context, list client.ObjectList,
 	}
 
 	if listOpts.Namespace != corev1.NamespaceAll {
+		c.mu.RLock()
 		cache, ok := c.namespaceToCache[listOpts.Namespace]
+		c.mu.RUnlock()
 		if !ok {
 			return fmt.Errorf("unable to get: %v because of unknown namespace for the cache", listOpts.Namespace)
 		}
@@ -254,7 +525,7 @@ func (c *multiNamespaceCache) List(ctx context.Context, list client.ObjectList,
 	limitSet := listOpts.Limit > 0
 
 	var resourceVersion string
-	for _, cache := range c.namespaceToCache {
+	for _, cache := range c.namespacedCaches() {
 		listObj := list.DeepCopyObject().(client.ObjectList)
 		err = cache.List(ctx, listObj, &listOpts)
 		if err != nil {
@@ -290,38 +561,65 @@ func (c *multiNamespaceCache) List(ctx context.Context, list client.ObjectList,
 
 // multiNamespaceInformer knows how to handle interacting with the underlying informer across multiple namespaces.
 type multiNamespaceInformer struct {
+	// get gets the informer of a namespace from its cache, for the informers of
+	// namespaced objects.
+	get func(ctx context.Context, cache Cache) (Informer, error)
+	// ready is closed once the informers of the namespaces the informer was created
+	// with are got, or err is set, for the informers of namespaced objects.
+	ready chan struct{}
+	err   error
+
+	mu                  sync.RWMutex
 	namespaceToInformer map[string]Informer
+	// handlers add the event handlers that were added to the informer of a namespace
+	// that is added.
+	handlers []func(Informer)
+	indexers []toolscache.Indexers
 }
 
 var _ Informer = &multiNamespaceInformer{}
 
 // AddEventHandler adds the handler to each namespaced informer.
 func (i *multiNamespaceInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
-	for _, informer := range i.namespaceToInformer {
+	i.addHandler(func(informer Informer) {
 		informer.AddEventHandler(handler)
-	}
+	})
 }
 
 // AddEventHandlerWithResyncPeriod adds the handler with a resync period to each namespaced informer.
 func (i *multiNamespaceInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) {
-	for _, informer := range i.namespaceToInformer {
+	i.addHandler(func(informer Informer) {
 		informer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	})
+}
+
+func (i *multiNamespaceInformer) addHandler(add func(Informer)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.handlers = append(i.handlers, add)
+	for _, informer := range i.namespaceToInformer {
+		add(informer)
 	}
 }
 
 // AddIndexers adds the indexer for each namespaced informer.
 func (i *multiNamespaceInformer) AddIndexers(indexers toolscache.Indexers) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
 	for _, informer := range i.namespaceToInformer {
 		err := informer.AddIndexers(indexers)
 		if err != nil {
 			return err
 		}
 	}
+	i.indexers = append(i.indexers, indexers)
 	return nil
 }
 
 // HasSynced checks if each namespaced informer has synced.
 func (i *multiNamespaceInformer) HasSynced() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	for _, informer := range i.namespaceToInformer {
 		if ok := informer.HasSynced(); !ok {
 			return ok
@@ -329,3 +627,44 @@ func (i *multiNamespaceInformer) HasSynced() bool {
 	}
 	return true
 }
+
+// waitReady returns the informer once it's ready, or the error of getting the informers
+// of its namespaces.
+func (i *multiNamespaceInformer) waitReady(ctx context.Context) (Informer, error) {
+	if i.ready == nil {
+		return i, nil
+	}
+	select {
+	case <-i.ready:
+		if i.err != nil {
+			return nil, i.err
+		}
+		return i, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// addNamespace adds the informer of a namespace, with the indexers and event handlers
+// that were added so far.
+func (i *multiNamespaceInformer) addNamespace(ns string, informer Informer) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, indexers := range i.indexers {
+		if err := informer.AddIndexers(indexers); err != nil {
+			return err
+		}
+	}
+	for _, add := range i.handlers {
+		add(informer)
+	}
+	i.namespaceToInformer[ns] = informer
+	return nil
+}
+
+// removeNamespace removes the informer of a namespace.
+func (i *multiNamespaceInformer) removeNamespace(ns string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.namespaceToInformer, ns)
+}
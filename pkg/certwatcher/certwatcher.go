@@ -18,36 +18,116 @@ package certwatcher
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher/metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 )
 
 var log = logf.RuntimeLog.WithName("certwatcher")
 
+// readCertificateBackoff is the backoff of retrying to read the certificate and key after
+// they changed, as they may be read mid-rotation, e.g. after the key was written but
+// before the certificate was.
+var readCertificateBackoff = wait.Backoff{
+	Duration: 100 * time.Millisecond,
+	Factor:   2,
+	Steps:    5,
+}
+
 // CertWatcher watches certificate and key files for changes.  When either file
 // changes, it reads and parses both and calls an optional callback with the new
 // certificate.
+//
+// It watches the files and their directories, and the targets of the files and their
+// directories if the files are symlinks, so that the files are reloaded when Kubernetes
+// atomically swaps the "..data" symlink of a secret or projected volume.
 type CertWatcher struct {
 	sync.RWMutex
 
-	currentCert *tls.Certificate
+	currentCert   *tls.Certificate
+	currentCAPool *x509.CertPool
+	// currentHash is the hash of the files the current certificate was read from.
+	currentHash [sha256.Size]byte
 	watcher     *fsnotify.Watcher
 
-	certPath string
-	keyPath  string
+	certPath        string
+	keyPath         string
+	caPath          string
+	name            string
+	pollingInterval time.Duration
+}
+
+// Options are the options of a CertWatcher.
+type Options struct {
+	// PollingInterval is the interval at which the certificate and key files are read to
+	// reload them if their content changed, in addition to watching them. It can be set
+	// on filesystems on which changes of the files are not notified, e.g. some network
+	// or CSI filesystems. Defaults to 0, which disables polling.
+	PollingInterval time.Duration
+
+	// Name is the value of the name label of the metrics of the CertWatcher, which
+	// distinguishes them from the metrics of other CertWatchers. Defaults to the path
+	// of the certificate.
+	Name string
+
+	// CAFile is the path of a PEM encoded CA bundle that is reloaded together with the
+	// certificate and key, see GetCAPool.
+	CAFile string
+}
+
+// Option can be used to manipulate Options.
+type Option func(*Options)
+
+// WithPollingInterval sets the PollingInterval of a CertWatcher.
+func WithPollingInterval(interval time.Duration) Option {
+	return func(o *Options) {
+		o.PollingInterval = interval
+	}
+}
+
+// WithName sets the Name of a CertWatcher.
+func WithName(name string) Option {
+	return func(o *Options) {
+		o.Name = name
+	}
+}
+
+// WithCAFile sets the CAFile of a CertWatcher.
+func WithCAFile(path string) Option {
+	return func(o *Options) {
+		o.CAFile = path
+	}
 }
 
 // New returns a new CertWatcher watching the given certificate and key.
-func New(certPath, keyPath string) (*CertWatcher, error) {
+func New(certPath, keyPath string, opts ...Option) (*CertWatcher, error) {
 	var err error
 
+	options := Options{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.Name == "" {
+		options.Name = certPath
+	}
+
 	cw := &CertWatcher{
-		certPath: certPath,
-		keyPath:  keyPath,
+		certPath:        certPath,
+		keyPath:         keyPath,
+		caPath:          options.CAFile,
+		name:            options.Name,
+		pollingInterval: options.PollingInterval,
 	}
 
 	// Initial read of certificate and key.
@@ -70,26 +150,97 @@ func (cw *CertWatcher) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate,
 	return cw.currentCert, nil
 }
 
+// GetCAPool fetches the currently loaded CA bundle, which is nil unless a CA file is
+// configured. It is replaced together with the certificate, e.g. to verify client
+// certificates with the current CA bundle from tls.Config.GetConfigForClient.
+func (cw *CertWatcher) GetCAPool() *x509.CertPool {
+	cw.RLock()
+	defer cw.RUnlock()
+	return cw.currentCAPool
+}
+
 // Start starts the watch on the certificate and key files.
 func (cw *CertWatcher) Start(ctx context.Context) error {
-	files := []string{cw.certPath, cw.keyPath}
-
-	for _, f := range files {
-		if err := cw.watcher.Add(f); err != nil {
-			return err
-		}
+	if err := cw.addWatches(); err != nil {
+		return err
 	}
 
 	go cw.Watch()
 
-	log.Info("Starting certificate watcher")
+	if cw.pollingInterval > 0 {
+		go wait.Until(cw.reloadIfChanged, cw.pollingInterval, ctx.Done())
+	}
+
+	log.Info("Starting certificate watcher", "pollingInterval", cw.pollingInterval)
 
 	// Block until the context is done.
 	<-ctx.Done()
 
+	metrics.CertificateExpirySeconds.DeleteLabelValues(cw.name)
+
 	return cw.watcher.Close()
 }
 
+// addWatches watches the files, the targets of their symlinks and the directories of
+// all of them. Paths that are already watched are not watched twice.
+func (cw *CertWatcher) addWatches() error {
+	for _, path := range cw.watchedPaths() {
+		if err := cw.watcher.Add(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchedPaths returns the files, every symlink they resolve through and the directories
+// of all of them, e.g. of both symlinks of a file that is a symlink to a file in a secret
+// volume. The directories are watched for symlinks being swapped, and the files for
+// filesystems that don't notify about changes of files in directories.
+func (cw *CertWatcher) watchedPaths() []string {
+	var paths []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for _, path := range cw.files() {
+		// Limit the number of symlinks followed, like the kernel does.
+		for i := 0; i < 40; i++ {
+			dir := filepath.Dir(path)
+			add(dir)
+
+			info, err := os.Lstat(path)
+			if err != nil {
+				break
+			}
+			add(path)
+			if info.Mode()&os.ModeSymlink == 0 {
+				break
+			}
+			target, err := os.Readlink(path)
+			if err != nil {
+				break
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(dir, target)
+			}
+			path = target
+		}
+	}
+	return paths
+}
+
+// files returns the paths of the certificate, the key and the CA bundle if it's configured.
+func (cw *CertWatcher) files() []string {
+	files := []string{cw.certPath, cw.keyPath}
+	if cw.caPath != "" {
+		files = append(files, cw.caPath)
+	}
+	return files
+}
+
 // Watch reads events from the watcher's channel and reacts to changes.
 func (cw *CertWatcher) Watch() {
 	for {
@@ -118,39 +269,119 @@ func (cw *CertWatcher) Watch() {
 // is invoked with the new certificate.
 func (cw *CertWatcher) ReadCertificate() error {
 	metrics.ReadCertificateTotal.Inc()
-	cert, err := tls.LoadX509KeyPair(cw.certPath, cw.keyPath)
+	files, err := cw.readFiles()
+	if err == nil {
+		err = cw.load(files)
+	}
 	if err != nil {
 		metrics.ReadCertificateErrors.Inc()
+		metrics.CertificateReloadErrors.WithLabelValues(cw.name).Inc()
 		return err
 	}
 
+	log.Info("Updated current TLS certificate")
+
+	return nil
+}
+
+// load parses the certificate, key and CA bundle, and replaces the current ones
+// at once, so that TLS handshakes never see a mix of old and new ones.
+func (cw *CertWatcher) load(files [][]byte) error {
+	cert, err := tls.X509KeyPair(files[0], files[1])
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+	var caPool *x509.CertPool
+	if cw.caPath != "" {
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(files[2]) {
+			return fmt.Errorf("no certificates found in CA file %s", cw.caPath)
+		}
+	}
+
 	cw.Lock()
 	cw.currentCert = &cert
+	cw.currentCAPool = caPool
+	cw.currentHash = hash(files)
 	cw.Unlock()
 
-	log.Info("Updated current TLS certificate")
+	metrics.CertificateReloadTotal.WithLabelValues(cw.name).Inc()
+	metrics.CertificateExpirySeconds.WithLabelValues(cw.name).Set(float64(leaf.NotAfter.Unix()))
+	return nil
+}
 
+// readFiles reads the files returned by files, in the same order.
+func (cw *CertWatcher) readFiles() ([][]byte, error) {
+	var files [][]byte
+	for _, path := range cw.files() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, data)
+	}
+	return files, nil
+}
+
+// readCertificateWithRetry reads the certificate, retrying with backoff if it fails.
+func (cw *CertWatcher) readCertificateWithRetry() error {
+	var lastErr error
+	err := wait.ExponentialBackoff(readCertificateBackoff, func() (bool, error) {
+		lastErr = cw.ReadCertificate()
+		return lastErr == nil, nil
+	})
+	if err != nil {
+		return lastErr
+	}
 	return nil
 }
 
+// reloadIfChanged reads the certificate again unless the content of the files is unchanged.
+func (cw *CertWatcher) reloadIfChanged() {
+	if files, err := cw.readFiles(); err == nil {
+		cw.RLock()
+		unchanged := hash(files) == cw.currentHash
+		cw.RUnlock()
+		if unchanged {
+			return
+		}
+	}
+
+	log.V(1).Info("certificate changed")
+	if err := cw.readCertificateWithRetry(); err != nil {
+		log.Error(err, "error re-reading certificate")
+	}
+}
+
+func hash(files [][]byte) [sha256.Size]byte {
+	h := sha256.New()
+	for _, data := range files {
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
 func (cw *CertWatcher) handleEvent(event fsnotify.Event) {
-	// Only care about events which may modify the contents of the file.
-	if !(isWrite(event) || isRemove(event) || isCreate(event)) {
+	// Only care about events which may modify the contents of the files.
+	if !(isWrite(event) || isRemove(event) || isCreate(event) || isRename(event)) {
 		return
 	}
 
 	log.V(1).Info("certificate event", "event", event)
 
-	// If the file was removed, re-add the watch.
-	if isRemove(event) {
-		if err := cw.watcher.Add(event.Name); err != nil {
-			log.Error(err, "error re-watching file")
-		}
+	// If a file was replaced or a symlink was swapped, watch the new files.
+	if err := cw.addWatches(); err != nil {
+		log.Error(err, "error re-watching files")
 	}
 
-	if err := cw.ReadCertificate(); err != nil {
-		log.Error(err, "error re-reading certificate")
-	}
+	cw.reloadIfChanged()
 }
 
 func isWrite(event fsnotify.Event) bool {
@@ -164,3 +395,7 @@ func isCreate(event fsnotify.Event) bool {
 func isRemove(event fsnotify.Event) bool {
 	return event.Op&fsnotify.Remove == fsnotify.Remove
 }
+
+func isRename(event fsnotify.Event) bool {
+	return event.Op&fsnotify.Rename == fsnotify.Rename
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher/metrics"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var _ = Describe("CertWatcher", func() {
@@ -170,6 +171,72 @@ var _ = Describe("CertWatcher", func() {
 				Eventually(doneCh, "4s").Should(BeClosed())
 			})
 		})
+
+		Context("prometheus metric certwatcher_certificate_expiry_seconds", func() {
+			// gather returns the values of the metric family with the given name by the name label.
+			gather := func(family string) map[string]float64 {
+				families, err := ctrlmetrics.Registry.Gather()
+				Expect(err).NotTo(HaveOccurred())
+				values := map[string]float64{}
+				for _, f := range families {
+					if f.GetName() != family {
+						continue
+					}
+					for _, m := range f.GetMetric() {
+						for _, l := range m.GetLabel() {
+							if l.GetName() == "name" {
+								values[l.GetValue()] = m.GetGauge().GetValue() + m.GetCounter().GetValue()
+							}
+						}
+					}
+				}
+				return values
+			}
+			notAfter := func() float64 {
+				certPEM, err := os.ReadFile(certPath)
+				Expect(err).NotTo(HaveOccurred())
+				block, _ := pem.Decode(certPEM)
+				cert, err := x509.ParseCertificate(block.Bytes)
+				Expect(err).NotTo(HaveOccurred())
+				return float64(cert.NotAfter.Unix())
+			}
+
+			It("should expose the expiry of the current certificate of each watcher until it stops", func() {
+				named, err := certwatcher.New(certPath, keyPath, certwatcher.WithName("webhook"))
+				Expect(err).NotTo(HaveOccurred())
+				namedCtx, namedCancel := context.WithCancel(ctx)
+				namedDone := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					defer close(namedDone)
+					Expect(named.Start(namedCtx)).To(Succeed())
+				}()
+				doneCh := startWatcher()
+
+				expiry := gather("certwatcher_certificate_expiry_seconds")
+				Expect(expiry).To(HaveKeyWithValue("webhook", notAfter()))
+				Expect(expiry).To(HaveKeyWithValue(certPath, notAfter()))
+				reloadsBefore := gather("certwatcher_certificate_reloads_total")["webhook"]
+
+				By("rotating the certificate")
+				time.Sleep(time.Second) // the expiry has a precision of seconds
+				Expect(writeCerts(certPath, keyPath, "192.168.0.1")).To(Succeed())
+				Eventually(func() map[string]float64 {
+					return gather("certwatcher_certificate_expiry_seconds")
+				}, "4s").Should(And(HaveKeyWithValue("webhook", notAfter()), HaveKeyWithValue(certPath, notAfter())))
+				Expect(gather("certwatcher_certificate_reloads_total")["webhook"]).To(BeNumerically(">", reloadsBefore))
+
+				By("stopping one of the watchers")
+				namedCancel()
+				Eventually(namedDone, "4s").Should(BeClosed())
+				expiry = gather("certwatcher_certificate_expiry_seconds")
+				Expect(expiry).NotTo(HaveKey("webhook"))
+				Expect(expiry).To(HaveKey(certPath))
+
+				ctxCancel()
+				Eventually(doneCh, "4s").Should(BeClosed())
+			})
+		})
 	})
 })
 
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certwatcher
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/util/cert"
+
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher/metrics"
+)
+
+var _ = Describe("CertWatcher reloadIfChanged", func() {
+	var (
+		certPath, keyPath string
+		watcher           *CertWatcher
+	)
+
+	// writeFile writes the file without creating a new one, like a filesystem that doesn't
+	// notify about changes would show it to the watcher.
+	writeFile := func(path string, data []byte) {
+		Expect(os.WriteFile(path, data, 0600)).To(Succeed())
+	}
+	newPair := func(host string) (certPEM, keyPEM []byte) {
+		certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey(host, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		return certPEM, keyPEM
+	}
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		certPath = filepath.Join(dir, "tls.crt")
+		keyPath = filepath.Join(dir, "tls.key")
+
+		certPEM, keyPEM := newPair("first.example.com")
+		writeFile(certPath, certPEM)
+		writeFile(keyPath, keyPEM)
+
+		var err error
+		watcher, err = New(certPath, keyPath, WithPollingInterval(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watcher.pollingInterval).To(Equal(time.Minute))
+	})
+
+	It("should only reload the certificate if the files changed", func() {
+		first, _ := watcher.GetCertificate(nil)
+
+		watcher.reloadIfChanged()
+		Expect(watcher.GetCertificate(nil)).To(BeIdenticalTo(first))
+
+		certPEM, keyPEM := newPair("second.example.com")
+		writeFile(certPath, certPEM)
+		writeFile(keyPath, keyPEM)
+		watcher.reloadIfChanged()
+		second, _ := watcher.GetCertificate(nil)
+		Expect(second).NotTo(BeIdenticalTo(first))
+		Expect(second.Certificate).NotTo(Equal(first.Certificate))
+	})
+
+	It("should retry reading the certificate while it's rotated", func() {
+		first, _ := watcher.GetCertificate(nil)
+
+		By("writing the key before the certificate")
+		certPEM, keyPEM := newPair("second.example.com")
+		writeFile(keyPath, keyPEM)
+		go func() {
+			defer GinkgoRecover()
+			time.Sleep(2 * readCertificateBackoff.Duration)
+			writeFile(certPath, certPEM)
+		}()
+
+		errorsBefore := testutil.ToFloat64(metrics.ReadCertificateErrors)
+		watcher.reloadIfChanged()
+		Expect(testutil.ToFloat64(metrics.ReadCertificateErrors)).To(BeNumerically(">", errorsBefore))
+		second, _ := watcher.GetCertificate(nil)
+		Expect(second.Certificate).NotTo(Equal(first.Certificate))
+	})
+})
+
+var _ = Describe("CertWatcher in a secret volume", func() {
+	var (
+		volumeDir, mountDir string
+		generation          int
+	)
+
+	// writeGeneration writes a new certificate, key and CA bundle into a new directory
+	// of the volume and atomically swaps the ..data symlink to it, like the kubelet does.
+	writeGeneration := func() (caPEM []byte) {
+		generation++
+		dataDir := fmt.Sprintf("..gen_%d", generation)
+		Expect(os.Mkdir(filepath.Join(volumeDir, dataDir), 0700)).To(Succeed())
+		certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey(fmt.Sprintf("gen-%d.example.com", generation), nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		caPEM, _, err = cert.GenerateSelfSignedCertKey(fmt.Sprintf("ca-%d.example.com", generation), nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		for name, data := range map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM, "ca.crt": caPEM} {
+			Expect(os.WriteFile(filepath.Join(volumeDir, dataDir, name), data, 0600)).To(Succeed())
+		}
+
+		Expect(os.Symlink(dataDir, filepath.Join(volumeDir, "..data_tmp"))).To(Succeed())
+		Expect(os.Rename(filepath.Join(volumeDir, "..data_tmp"), filepath.Join(volumeDir, "..data"))).To(Succeed())
+		if generation > 1 {
+			Expect(os.RemoveAll(filepath.Join(volumeDir, fmt.Sprintf("..gen_%d", generation-1)))).To(Succeed())
+		}
+		return caPEM
+	}
+
+	BeforeEach(func() {
+		generation = 0
+		volumeDir = GinkgoT().TempDir()
+		mountDir = GinkgoT().TempDir()
+	})
+
+	It("should reload the certificate and CA bundle when the ..data symlink is swapped", func() {
+		firstCA := writeGeneration()
+		for _, name := range []string{"tls.crt", "tls.key", "ca.crt"} {
+			// The files of the volume link to ..data, and the files of the mount to the
+			// files of the volume, like in a projected volume.
+			Expect(os.Symlink(filepath.Join("..data", name), filepath.Join(volumeDir, name))).To(Succeed())
+			Expect(os.Symlink(filepath.Join(volumeDir, name), filepath.Join(mountDir, name))).To(Succeed())
+		}
+
+		watcher, err := New(filepath.Join(mountDir, "tls.crt"), filepath.Join(mountDir, "tls.key"), WithCAFile(filepath.Join(mountDir, "ca.crt")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watcher.watchedPaths()).To(ContainElements(mountDir, volumeDir, filepath.Join(volumeDir, "..data")))
+
+		first, _ := watcher.GetCertificate(nil)
+		firstPool := x509.NewCertPool()
+		Expect(firstPool.AppendCertsFromPEM(firstCA)).To(BeTrue())
+		Expect(watcher.GetCAPool().Equal(firstPool)).To(BeTrue())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(watcher.Start(ctx)).To(Succeed())
+		}()
+		defer func() {
+			cancel()
+			Eventually(done).Should(BeClosed())
+		}()
+		// Start watches the files asynchronously.
+		Eventually(watcher.watcher.WatchList).Should(ContainElement(mountDir))
+
+		By("swapping the ..data symlink")
+		secondCA := writeGeneration()
+		secondPool := x509.NewCertPool()
+		Expect(secondPool.AppendCertsFromPEM(secondCA)).To(BeTrue())
+		Eventually(func() bool {
+			return watcher.GetCAPool().Equal(secondPool)
+		}, "4s").Should(BeTrue())
+		second, _ := watcher.GetCertificate(nil)
+		Expect(second.Certificate).NotTo(Equal(first.Certificate))
+	})
+
+	It("should fail if the CA file has no certificates", func() {
+		writeGeneration()
+		Expect(os.WriteFile(filepath.Join(mountDir, "ca.crt"), []byte("not a certificate"), 0600)).To(Succeed())
+
+		_, err := New(filepath.Join(volumeDir, "..data", "tls.crt"), filepath.Join(volumeDir, "..data", "tls.key"), WithCAFile(filepath.Join(mountDir, "ca.crt")))
+		Expect(err).To(MatchError(ContainSubstring("no certificates found in CA file")))
+	})
+})
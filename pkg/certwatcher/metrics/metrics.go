@@ -35,11 +35,35 @@ var (
 		Name: "certwatcher_read_certificate_errors_total",
 		Help: "Total number of certificate read errors",
 	})
+
+	// CertificateExpirySeconds is a prometheus gauge metrics which holds the expiry of the
+	// current certificate of each certwatcher as a unix timestamp.
+	CertificateExpirySeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "certwatcher_certificate_expiry_seconds",
+		Help: "Expiry of the current certificate as a unix timestamp in seconds",
+	}, []string{"name"})
+
+	// CertificateReloadTotal is a prometheus counter metrics which holds the total
+	// number of successful certificate reads of each certwatcher.
+	CertificateReloadTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "certwatcher_certificate_reloads_total",
+		Help: "Total number of successful certificate reads, including the initial one",
+	}, []string{"name"})
+
+	// CertificateReloadErrors is a prometheus counter metrics which holds the total
+	// number of errors from certificate reads of each certwatcher.
+	CertificateReloadErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "certwatcher_certificate_reload_errors_total",
+		Help: "Total number of certificate read errors",
+	}, []string{"name"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		ReadCertificateTotal,
 		ReadCertificateErrors,
+		CertificateExpirySeconds,
+		CertificateReloadTotal,
+		CertificateReloadErrors,
 	)
 }
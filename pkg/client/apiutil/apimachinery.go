@@ -22,6 +22,8 @@ package apiutil
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -72,7 +74,32 @@ func NewDiscoveryRESTMapper(c *rest.Config) (meta.RESTMapper, error) {
 	return restmapper.NewDiscoveryRESTMapper(gr), nil
 }
 
+// AmbiguousGVKError is returned by GVKForObject when the type of an object is registered
+// with several GroupVersionKinds, e.g. because a vendored copy of a type is registered
+// next to the original one, and the object doesn't pick one of them with its TypeMeta.
+type AmbiguousGVKError struct {
+	// Type is the Go type of the object.
+	Type reflect.Type
+	// GVKs are the GroupVersionKinds the type is registered with, sorted.
+	GVKs []schema.GroupVersionKind
+}
+
+func (e *AmbiguousGVKError) Error() string {
+	gvks := make([]string, 0, len(e.GVKs))
+	for _, gvk := range e.GVKs {
+		gvks = append(gvks, gvk.String())
+	}
+	return fmt.Sprintf("multiple group-version-kinds associated with type %v, refusing to guess at one: [%s]; "+
+		"set the GroupVersionKind of the object to one of them", e.Type, strings.Join(gvks, ", "))
+}
+
 // GVKForObject finds the GroupVersionKind associated with the given object, if there is only a single such GVK.
+//
+// The GroupVersionKind of unstructured objects and PartialObjectMetadata, and of their
+// lists, is the one they have set, and it's an error if it's not set. When a typed object
+// is registered with several GroupVersionKinds, the one set on the object is picked if
+// it's one of them, otherwise an *AmbiguousGVKError is returned. The scheme is only used
+// for typed objects.
 func GVKForObject(obj runtime.Object, scheme *runtime.Scheme) (schema.GroupVersionKind, error) {
 	// TODO(directxman12): do we want to generalize this to arbitrary container types?
 	// I think we'd need a generalized form of scheme or something.  It's a
@@ -80,17 +107,17 @@ func GVKForObject(obj runtime.Object, scheme *runtime.Scheme) (schema.GroupVersi
 	// for unpopulated static types and populated "dynamic" types
 	// (unstructured, partial, etc)
 
-	// check for PartialObjectMetadata, which is analogous to unstructured, but isn't handled by ObjectKinds
-	_, isPartial := obj.(*metav1.PartialObjectMetadata) //nolint:ifshort
-	_, isPartialList := obj.(*metav1.PartialObjectMetadataList)
-	if isPartial || isPartialList {
+	// PartialObjectMetadata is analogous to unstructured, but isn't handled by ObjectKinds,
+	// and an unstructured object without kind should fail the same way.
+	switch obj.(type) {
+	case *metav1.PartialObjectMetadata, *metav1.PartialObjectMetadataList, runtime.Unstructured:
 		// we require that the GVK be populated in order to recognize the object
 		gvk := obj.GetObjectKind().GroupVersionKind()
 		if len(gvk.Kind) == 0 {
-			return schema.GroupVersionKind{}, runtime.NewMissingKindErr("unstructured object has no kind")
+			return schema.GroupVersionKind{}, runtime.NewMissingKindErr(fmt.Sprintf("object of type %T has no kind set", obj))
 		}
 		if len(gvk.Version) == 0 {
-			return schema.GroupVersionKind{}, runtime.NewMissingVersionErr("unstructured object has no version")
+			return schema.GroupVersionKind{}, runtime.NewMissingVersionErr(fmt.Sprintf("object of type %T has no version set", obj))
 		}
 		return gvk, nil
 	}
@@ -107,14 +134,44 @@ func GVKForObject(obj runtime.Object, scheme *runtime.Scheme) (schema.GroupVersi
 		return schema.GroupVersionKind{}, fmt.Errorf("no group-version-kinds associated with type %T", obj)
 	}
 	if len(gvks) > 1 {
-		// this should only trigger for things like metav1.XYZ --
-		// normal versioned types should be fine
-		return schema.GroupVersionKind{}, fmt.Errorf(
-			"multiple group-version-kinds associated with type %T, refusing to guess at one", obj)
+		// this triggers for things like metav1.XYZ, and for types registered in several
+		// groups, so let the object disambiguate with its TypeMeta.
+		if set := obj.GetObjectKind().GroupVersionKind(); !set.Empty() {
+			for _, gvk := range gvks {
+				if gvk == set {
+					return gvk, nil
+				}
+			}
+		}
+		sort.Slice(gvks, func(i, j int) bool {
+			return gvks[i].String() < gvks[j].String()
+		})
+		return schema.GroupVersionKind{}, &AmbiguousGVKError{Type: reflect.TypeOf(obj), GVKs: gvks}
 	}
 	return gvks[0], nil
 }
 
+// GVKForList finds the GroupVersionKind of the items of the given list, which is the
+// GroupVersionKind of the list with the "List" suffix of its kind removed. Dynamic lists
+// whose kind is set to the kind of their items are accepted as well. It returns an
+// error if the object isn't a list.
+func GVKForList(list runtime.Object, scheme *runtime.Scheme) (schema.GroupVersionKind, error) {
+	if !meta.IsListType(list) {
+		return schema.GroupVersionKind{}, fmt.Errorf("%T is not a list", list)
+	}
+	gvk, err := GVKForObject(list, scheme)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	// TODO: this is producing unsafe guesses that don't actually work,
+	// but it matches ~99% of the cases out there.
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	if gvk.Kind == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("cannot find the kind of the items of %T from its kind List", list)
+	}
+	return gvk, nil
+}
+
 // RESTClientForGVK constructs a new rest.Interface capable of accessing the resource associated
 // with the given GroupVersionKind. The REST client will be configured to use the negotiated serializer from
 // baseConfig, if set, otherwise a default serializer will be set.
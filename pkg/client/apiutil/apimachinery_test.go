@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiutil
+
+import (
+	"errors"
+	"reflect"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("GVKForObject", func() {
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+	// vendoredPodGVK is the GVK of a vendored copy of the pod type.
+	vendoredPodGVK := schema.GroupVersionKind{Group: "vendored.example.com", Version: "v1", Kind: "Pod"}
+
+	var s *runtime.Scheme
+	BeforeEach(func() {
+		s = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+	})
+
+	It("should return the GVK of typed objects and lists", func() {
+		Expect(GVKForObject(&corev1.Pod{}, s)).To(Equal(podGVK))
+		Expect(GVKForObject(&corev1.PodList{}, s)).To(Equal(corev1.SchemeGroupVersion.WithKind("PodList")))
+	})
+
+	It("should return an error listing the GVKs of a type registered several times", func() {
+		s.AddKnownTypeWithName(vendoredPodGVK, &corev1.Pod{})
+
+		_, err := GVKForObject(&corev1.Pod{}, s)
+		ambiguous := &AmbiguousGVKError{}
+		Expect(errors.As(err, &ambiguous)).To(BeTrue())
+		Expect(ambiguous.Type).To(Equal(reflect.TypeOf(&corev1.Pod{})))
+		Expect(ambiguous.GVKs).To(Equal([]schema.GroupVersionKind{podGVK, vendoredPodGVK}))
+		Expect(err.Error()).To(ContainSubstring("/v1, Kind=Pod"))
+		Expect(err.Error()).To(ContainSubstring("vendored.example.com/v1, Kind=Pod"))
+	})
+
+	It("should pick the GVK set on an object whose type is registered several times", func() {
+		s.AddKnownTypeWithName(vendoredPodGVK, &corev1.Pod{})
+
+		pod := &corev1.Pod{}
+		pod.SetGroupVersionKind(vendoredPodGVK)
+		Expect(GVKForObject(pod, s)).To(Equal(vendoredPodGVK))
+		pod.SetGroupVersionKind(podGVK)
+		Expect(GVKForObject(pod, s)).To(Equal(podGVK))
+
+		By("not picking a GVK the type isn't registered with")
+		pod.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Pod"))
+		_, err := GVKForObject(pod, s)
+		Expect(errors.As(err, new(*AmbiguousGVKError))).To(BeTrue())
+	})
+
+	DescribeTable("should honor the GVK set on dynamic objects",
+		func(obj runtime.Object) {
+			gvk := appsv1.SchemeGroupVersion.WithKind("Deployment")
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+			Expect(GVKForObject(obj, s)).To(Equal(gvk))
+			Expect(GVKForObject(obj, nil)).To(Equal(gvk))
+		},
+		Entry("PartialObjectMetadata", &metav1.PartialObjectMetadata{}),
+		Entry("PartialObjectMetadataList", &metav1.PartialObjectMetadataList{}),
+		Entry("Unstructured", &unstructured.Unstructured{}),
+		Entry("UnstructuredList", &unstructured.UnstructuredList{}),
+	)
+
+	DescribeTable("should return an error if the GVK of dynamic objects isn't set",
+		func(obj runtime.Object) {
+			_, err := GVKForObject(obj, s)
+			Expect(runtime.IsMissingKind(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("%T has no kind set", obj))
+
+			obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{Kind: "Deployment"})
+			_, err = GVKForObject(obj, s)
+			Expect(runtime.IsMissingVersion(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("%T has no version set", obj))
+		},
+		Entry("PartialObjectMetadata", &metav1.PartialObjectMetadata{}),
+		Entry("PartialObjectMetadataList", &metav1.PartialObjectMetadataList{}),
+		Entry("Unstructured", &unstructured.Unstructured{}),
+		Entry("UnstructuredList", &unstructured.UnstructuredList{}),
+	)
+})
+
+var _ = Describe("GVKForList", func() {
+	var s *runtime.Scheme
+	BeforeEach(func() {
+		s = runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+	})
+
+	It("should return the GVK of the items of typed lists", func() {
+		Expect(GVKForList(&corev1.PodList{}, s)).To(Equal(corev1.SchemeGroupVersion.WithKind("Pod")))
+	})
+
+	It("should return the GVK of the items of dynamic lists", func() {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("DeploymentList"))
+		Expect(GVKForList(list, nil)).To(Equal(appsv1.SchemeGroupVersion.WithKind("Deployment")))
+
+		partialList := &metav1.PartialObjectMetadataList{}
+		partialList.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("DeploymentList"))
+		Expect(GVKForList(partialList, nil)).To(Equal(appsv1.SchemeGroupVersion.WithKind("Deployment")))
+	})
+
+	It("should return an error for objects that aren't lists", func() {
+		_, err := GVKForList(&corev1.Pod{}, s)
+		Expect(err).To(MatchError("*v1.Pod is not a list"))
+
+		partial := &metav1.PartialObjectMetadata{}
+		partial.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Pod"))
+		_, err = GVKForList(partial, s)
+		Expect(err).To(MatchError("*v1.PartialObjectMetadata is not a list"))
+	})
+
+	It("should accept dynamic lists whose kind is the kind of their items", func() {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		Expect(GVKForList(list, nil)).To(Equal(appsv1.SchemeGroupVersion.WithKind("Deployment")))
+	})
+
+	It("should return an error for the generic List kind", func() {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("List"))
+		_, err := GVKForList(list, nil)
+		Expect(err).To(MatchError(ContainSubstring("cannot find the kind of the items")))
+	})
+
+	It("should return an error for lists without kind", func() {
+		_, err := GVKForList(&unstructured.UnstructuredList{}, nil)
+		Expect(runtime.IsMissingKind(err)).To(BeTrue())
+	})
+})
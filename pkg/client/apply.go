@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ApplyConfiguration is the configuration of an object that is applied with server-side
+// apply: either a typed apply configuration, such as the ones of the
+// k8s.io/client-go/applyconfigurations packages, or an *unstructured.Unstructured. It must
+// be a pointer, and set the apiVersion, the kind and the name of the object.
+type ApplyConfiguration interface{}
+
+// applyConfigurationToUnstructured returns the object of an apply configuration, as it's
+// sent to the API server.
+func applyConfigurationToUnstructured(obj ApplyConfiguration) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal apply configuration %T: %w", obj, err)
+	}
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to convert apply configuration %T: %w", obj, err)
+	}
+	if u.GetName() == "" {
+		return nil, fmt.Errorf("apply configuration %T has no name", obj)
+	}
+	return u, nil
+}
+
+// applyConfigurationFromUnstructured updates an apply configuration with the object
+// returned by the API server. It does nothing if the configuration is the object.
+func applyConfigurationFromUnstructured(u *unstructured.Unstructured, obj ApplyConfiguration) error {
+	if u == obj {
+		return nil
+	}
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, obj)
+}
@@ -19,7 +19,7 @@ package client
 import (
 	"context"
 	"fmt"
-	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,6 +47,13 @@ type WarningHandlerOptions struct {
 	// log.WarningHandlerOptions for considerations
 	// regarding deduplication
 	AllowDuplicateLogs bool
+	// DeduplicateTTL is the time after which a deduplicated
+	// warning message is logged again. Zero means that it
+	// is only logged once. See log.KubeAPIWarningLoggerOptions.
+	DeduplicateTTL time.Duration
+	// ErrorSubstrings escalates warning messages that contain
+	// any of these substrings to the error level.
+	ErrorSubstrings []string
 }
 
 // Options are creation options for a Client.
@@ -60,6 +67,10 @@ type Options struct {
 	// Opts is used to configure the warning handler responsible for
 	// surfacing and handling warnings messages sent by the API server.
 	Opts WarningHandlerOptions
+
+	// RateLimit configures the rate limiting of the requests of the client, instead
+	// of the one of the rest.Config.
+	RateLimit RateLimitOptions
 }
 
 // New returns a new Client using the provided config and Options.
@@ -81,6 +92,8 @@ func newClient(config *rest.Config, options Options) (*client, error) {
 		return nil, fmt.Errorf("must provide non-nil rest.Config to client.New")
 	}
 
+	config = withRateLimit(config, options.RateLimit)
+
 	if !options.Opts.SuppressWarnings {
 		// surface warnings
 		logger := log.Log.WithName("KubeAPIWarningLogger")
@@ -88,11 +101,12 @@ func newClient(config *rest.Config, options Options) (*client, error) {
 		// is log.KubeAPIWarningLogger with deduplication enabled.
 		// See log.KubeAPIWarningLoggerOptions for considerations
 		// regarding deduplication.
-		config = rest.CopyConfig(config)
 		config.WarningHandler = log.NewKubeAPIWarningLogger(
 			logger,
 			log.KubeAPIWarningLoggerOptions{
-				Deduplicate: !options.Opts.AllowDuplicateLogs,
+				Deduplicate:     !options.Opts.AllowDuplicateLogs,
+				DeduplicateTTL:  options.Opts.DeduplicateTTL,
+				ErrorSubstrings: options.Opts.ErrorSubstrings,
 			},
 		)
 	}
@@ -239,6 +253,16 @@ func (c *client) Patch(ctx context.Context, obj Object, patch Patch, opts ...Pat
 	}
 }
 
+// Apply implements client.Client.
+func (c *client) Apply(ctx context.Context, obj ApplyConfiguration, opts ...ApplyOption) error {
+	switch obj.(type) {
+	case *unstructured.Unstructured:
+		return c.unstructuredClient.Apply(ctx, obj, opts...)
+	default:
+		return c.typedClient.Apply(ctx, obj, opts...)
+	}
+}
+
 // Get implements client.Client.
 func (c *client) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
 	switch obj.(type) {
@@ -261,6 +285,10 @@ func (c *client) List(ctx context.Context, obj ObjectList, opts ...ListOption) e
 	case *metav1.PartialObjectMetadataList:
 		// Metadata only object should always preserve the GVK.
 		gvk := obj.GetObjectKind().GroupVersionKind()
+		itemGVK, err := apiutil.GVKForList(obj, c.scheme)
+		if err != nil {
+			return err
+		}
 		defer c.resetGroupVersionKind(obj, gvk)
 
 		// Call the list client.
@@ -269,13 +297,6 @@ func (c *client) List(ctx context.Context, obj ObjectList, opts ...ListOption) e
 		}
 
 		// Restore the GVK for each item in the list.
-		itemGVK := schema.GroupVersionKind{
-			Group:   gvk.Group,
-			Version: gvk.Version,
-			// TODO: this is producing unsafe guesses that don't actually work,
-			// but it matches ~99% of the cases out there.
-			Kind: strings.TrimSuffix(gvk.Kind, "List"),
-		}
 		for i := range x.Items {
 			item := &x.Items[i]
 			item.SetGroupVersionKind(itemGVK)
@@ -325,3 +346,68 @@ func (sw *statusWriter) Patch(ctx context.Context, obj Object, patch Patch, opts
 		return sw.client.typedClient.PatchStatus(ctx, obj, patch, opts...)
 	}
 }
+
+// SubResource implements client.SubResourceClientConstructor.
+func (c *client) SubResource(subResource string) SubResourceClient {
+	return &subResourceClient{client: c, subResource: subResource}
+}
+
+// subResourceClient is client.SubResourceClient that reads and writes a subresource.
+type subResourceClient struct {
+	client      *client
+	subResource string
+}
+
+// ensure subResourceClient implements client.SubResourceClient.
+var _ SubResourceClient = &subResourceClient{}
+
+// Get implements client.SubResourceReader.
+func (sc *subResourceClient) Get(ctx context.Context, obj, subResource Object, opts ...SubResourceGetOption) error {
+	switch obj.(type) {
+	case *unstructured.Unstructured:
+		return sc.client.unstructuredClient.GetSubResource(ctx, obj, subResource, sc.subResource, opts...)
+	case *metav1.PartialObjectMetadata:
+		return fmt.Errorf("cannot get subresource using only metadata")
+	default:
+		return sc.client.typedClient.GetSubResource(ctx, obj, subResource, sc.subResource, opts...)
+	}
+}
+
+// Create implements client.SubResourceWriter.
+func (sc *subResourceClient) Create(ctx context.Context, obj, subResource Object, opts ...SubResourceCreateOption) error {
+	defer sc.client.resetGroupVersionKind(subResource, subResource.GetObjectKind().GroupVersionKind())
+	switch obj.(type) {
+	case *unstructured.Unstructured:
+		return sc.client.unstructuredClient.CreateSubResource(ctx, obj, subResource, sc.subResource, opts...)
+	case *metav1.PartialObjectMetadata:
+		return fmt.Errorf("cannot create subresource using only metadata")
+	default:
+		return sc.client.typedClient.CreateSubResource(ctx, obj, subResource, sc.subResource, opts...)
+	}
+}
+
+// Update implements client.SubResourceWriter.
+func (sc *subResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	defer sc.client.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
+	switch obj.(type) {
+	case *unstructured.Unstructured:
+		return sc.client.unstructuredClient.UpdateSubResource(ctx, obj, sc.subResource, opts...)
+	case *metav1.PartialObjectMetadata:
+		return fmt.Errorf("cannot update subresource using only metadata -- did you mean to patch?")
+	default:
+		return sc.client.typedClient.UpdateSubResource(ctx, obj, sc.subResource, opts...)
+	}
+}
+
+// Patch implements client.SubResourceWriter.
+func (sc *subResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	defer sc.client.resetGroupVersionKind(obj, obj.GetObjectKind().GroupVersionKind())
+	switch obj.(type) {
+	case *unstructured.Unstructured:
+		return sc.client.unstructuredClient.PatchSubResource(ctx, obj, sc.subResource, patch, opts...)
+	case *metav1.PartialObjectMetadata:
+		return fmt.Errorf("cannot patch subresource using only metadata")
+	default:
+		return sc.client.typedClient.PatchSubResource(ctx, obj, sc.subResource, patch, opts...)
+	}
+}
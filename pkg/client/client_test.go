@@ -24,18 +24,24 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
 
 	"sigs.k8s.io/controller-runtime/examples/crd/pkg"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/internal/metrics"
 )
 
 func deleteDeployment(ctx context.Context, dep *appsv1.Deployment, ns string) {
@@ -704,6 +710,77 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("Apply", func() {
+		It("should apply a typed apply configuration", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			name := fmt.Sprintf("applied-configmap-%v", count)
+			cm := corev1ac.ConfigMap(name, ns).WithData(map[string]string{"foo": "bar"})
+			Expect(cl.Apply(ctx, cm, client.FieldOwner("test-owner"))).To(Succeed())
+			defer func() {
+				Expect(clientset.CoreV1().ConfigMaps(ns).Delete(ctx, name, metav1.DeleteOptions{})).To(Succeed())
+			}()
+
+			By("validating the apply configuration was updated with the object of the server")
+			Expect(cm.UID).NotTo(BeNil())
+			Expect(cm.ResourceVersion).NotTo(BeNil())
+
+			By("validating the object is owned by the field manager")
+			actual, err := clientset.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual.Data).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(actual.ManagedFields).To(ContainElement(WithTransform(func(f metav1.ManagedFieldsEntry) string { return f.Manager }, Equal("test-owner"))))
+
+			By("failing to apply a conflicting field without forcing the ownership")
+			conflicting := corev1ac.ConfigMap(name, ns).WithData(map[string]string{"foo": "baz"})
+			err = cl.Apply(ctx, conflicting, client.FieldOwner("other-owner"))
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+			Expect(cl.Apply(ctx, conflicting, client.FieldOwner("other-owner"), client.ForceOwnership)).To(Succeed())
+			Expect(conflicting.Data).To(Equal(map[string]string{"foo": "baz"}))
+		})
+
+		It("should apply an unstructured object", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			name := fmt.Sprintf("applied-unstructured-configmap-%v", count)
+			u := &unstructured.Unstructured{}
+			u.SetAPIVersion("v1")
+			u.SetKind("ConfigMap")
+			u.SetName(name)
+			u.SetNamespace(ns)
+			Expect(unstructured.SetNestedStringMap(u.Object, map[string]string{"foo": "bar"}, "data")).To(Succeed())
+			Expect(cl.Apply(ctx, u, client.FieldOwner("test-owner"))).To(Succeed())
+			defer func() {
+				Expect(clientset.CoreV1().ConfigMaps(ns).Delete(ctx, name, metav1.DeleteOptions{})).To(Succeed())
+			}()
+			Expect(u.GetUID()).NotTo(BeEmpty())
+
+			actual, err := clientset.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual.Data).To(Equal(map[string]string{"foo": "bar"}))
+		})
+
+		It("should not persist the object with DryRunAll", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			name := fmt.Sprintf("dry-run-applied-configmap-%v", count)
+			Expect(cl.Apply(ctx, corev1ac.ConfigMap(name, ns), client.FieldOwner("test-owner"), client.DryRunAll)).To(Succeed())
+
+			_, err = clientset.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should fail if the apply configuration has no name", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cl.Apply(ctx, corev1ac.ConfigMap("", ns), client.FieldOwner("test-owner"))).To(MatchError(ContainSubstring("has no name")))
+		})
+	})
+
 	Describe("StatusClient", func() {
 		Context("with structured objects", func() {
 			It("should update status of an existing object", func() {
@@ -1025,6 +1102,92 @@ var _ = Describe("Client", func() {
 		})
 	})
 
+	Describe("SubResourceClient", func() {
+		It("should get and update the scale of a Deployment", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("initially creating a Deployment")
+			dep, err := clientset.AppsV1().Deployments(ns).Create(ctx, dep, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("getting the scale of the Deployment")
+			scale := &autoscalingv1.Scale{}
+			Expect(cl.SubResource("scale").Get(ctx, dep, scale)).To(Succeed())
+			Expect(scale.Spec.Replicas).To(BeEquivalentTo(replicaCount))
+
+			By("updating the scale of the Deployment")
+			scale.Spec.Replicas = 3
+			Expect(cl.SubResource("scale").Update(ctx, dep, client.WithSubResourceBody(scale))).To(Succeed())
+			Expect(scale.Spec.Replicas).To(BeEquivalentTo(3))
+
+			actual, err := clientset.AppsV1().Deployments(ns).Get(ctx, dep.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*actual.Spec.Replicas).To(BeEquivalentTo(3))
+		})
+
+		It("should patch the scale of a Deployment", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("initially creating a Deployment")
+			dep, err := clientset.AppsV1().Deployments(ns).Create(ctx, dep, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("patching the scale of the Deployment")
+			scale := &autoscalingv1.Scale{}
+			patch := client.RawPatch(types.MergePatchType, []byte(`{"spec":{"replicas":4}}`))
+			Expect(cl.SubResource("scale").Patch(ctx, dep, patch, client.WithSubResourceBody(scale))).To(Succeed())
+			Expect(scale.Spec.Replicas).To(BeEquivalentTo(4))
+		})
+
+		It("should update the status of a Deployment", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("initially creating a Deployment")
+			dep, err := clientset.AppsV1().Deployments(ns).Create(ctx, dep, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("updating the status of the Deployment")
+			dep.Status.Replicas = 1
+			Expect(cl.SubResource("status").Update(ctx, dep)).To(Succeed())
+
+			actual, err := clientset.AppsV1().Deployments(ns).Get(ctx, dep.Name, metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual.Status.Replicas).To(BeEquivalentTo(1))
+		})
+
+		It("should evict a Pod", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("initially creating a Pod")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("evicted-pod-%v", count), Namespace: ns},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}},
+			}
+			Expect(cl.Create(ctx, pod)).To(Succeed())
+
+			By("evicting the Pod")
+			eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: ns}}
+			Expect(cl.SubResource("eviction").Create(ctx, pod, eviction)).To(Succeed())
+
+			Eventually(func() bool {
+				_, err := clientset.CoreV1().Pods(ns).Get(ctx, pod.Name, metav1.GetOptions{})
+				return apierrors.IsNotFound(err)
+			}).Should(BeTrue())
+		})
+
+		It("should fail to get a subresource using only metadata", func() {
+			cl, err := client.New(cfg, client.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			metadata := metaOnlyFromObj(dep, scheme)
+			Expect(cl.SubResource("scale").Get(ctx, metadata, &autoscalingv1.Scale{})).NotTo(Succeed())
+		})
+	})
+
 	Describe("Delete", func() {
 		Context("with structured objects", func() {
 			It("should delete an existing object from a go struct", func() {
@@ -3078,6 +3241,63 @@ var _ = Describe("Client", func() {
 			Expect(po.AsPatchOptions()).To(Equal(&metav1.PatchOptions{}))
 		})
 	})
+	Describe("SubResourceOptions", func() {
+		It("should allow setting DryRun to 'all'", func() {
+			all := []string{metav1.DryRunAll}
+			co := &client.SubResourceCreateOptions{}
+			client.DryRunAll.ApplyToSubResourceCreate(co)
+			Expect(co.AsCreateOptions().DryRun).To(Equal(all))
+			uo := &client.SubResourceUpdateOptions{}
+			client.DryRunAll.ApplyToSubResourceUpdate(uo)
+			Expect(uo.AsUpdateOptions().DryRun).To(Equal(all))
+			po := &client.SubResourcePatchOptions{}
+			client.DryRunAll.ApplyToSubResourcePatch(po)
+			Expect(po.AsPatchOptions().DryRun).To(Equal(all))
+		})
+
+		It("should allow setting the body of the subresource", func() {
+			scale := &autoscalingv1.Scale{}
+			uo := &client.SubResourceUpdateOptions{}
+			uo.ApplyOptions([]client.SubResourceUpdateOption{client.WithSubResourceBody(scale)})
+			Expect(uo.SubResourceBody).To(BeIdenticalTo(scale))
+			po := &client.SubResourcePatchOptions{}
+			po.ApplyOptions([]client.SubResourcePatchOption{client.WithSubResourceBody(scale), client.ForceOwnership})
+			Expect(po.SubResourceBody).To(BeIdenticalTo(scale))
+			Expect(*po.AsPatchOptions().Force).To(BeTrue())
+		})
+
+		It("should produce empty metav1.GetOptions if nil", func() {
+			var o *client.SubResourceGetOptions
+			Expect(o.AsGetOptions()).To(Equal(&metav1.GetOptions{}))
+		})
+	})
+
+	Describe("ApplyOptions", func() {
+		It("should allow setting DryRun to 'all'", func() {
+			ao := &client.ApplyOptions{}
+			client.DryRunAll.ApplyToApply(ao)
+			Expect(ao.AsPatchOptions().DryRun).To(Equal([]string{metav1.DryRunAll}))
+		})
+
+		It("should allow setting Force to 'true'", func() {
+			ao := &client.ApplyOptions{}
+			client.ForceOwnership.ApplyToApply(ao)
+			mpo := ao.AsPatchOptions()
+			Expect(mpo.Force).NotTo(BeNil())
+			Expect(*mpo.Force).To(BeTrue())
+		})
+
+		It("should allow setting the field manager", func() {
+			ao := &client.ApplyOptions{}
+			client.FieldOwner("some-owner").ApplyToApply(ao)
+			Expect(ao.AsPatchOptions().FieldManager).To(Equal("some-owner"))
+		})
+
+		It("should produce empty metav1.PatchOptions if nil", func() {
+			var ao *client.ApplyOptions
+			Expect(ao.AsPatchOptions()).To(Equal(&metav1.PatchOptions{}))
+		})
+	})
 })
 
 var _ = Describe("DelegatingClient", func() {
@@ -3171,6 +3391,67 @@ var _ = Describe("DelegatingClient", func() {
 				Expect(1).To(Equal(cachedReader.Called))
 			})
 		})
+
+		When("falling back to the API server on cache misses", func() {
+			var (
+				cm        *corev1.ConfigMap
+				key       client.ObjectKey
+				live      client.Client
+				fallbacks func(result string) float64
+			)
+
+			BeforeEach(func() {
+				cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "just-created"}}
+				key = client.ObjectKeyFromObject(cm)
+				live = fake.NewClientBuilder().WithObjects(cm).Build()
+				fallbacks = func(result string) float64 {
+					return testutil.ToFloat64(metrics.CacheMissFallbacks.WithLabelValues("", "v1", "ConfigMap", result))
+				}
+			})
+
+			It("should get the objects not found in the cache from the API server", func() {
+				dReader, err := client.NewDelegatingClient(client.NewDelegatingClientInput{
+					CacheReader:       fake.NewClientBuilder().Build(),
+					Client:            live,
+					CacheMissFallback: client.AlwaysFallBackOnCacheMiss,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				found := fallbacks("found")
+				Expect(dReader.Get(context.TODO(), key, &corev1.ConfigMap{})).To(Succeed())
+				Expect(fallbacks("found")).To(Equal(found + 1))
+
+				notFound := fallbacks("not_found")
+				err = dReader.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "missing"}, &corev1.ConfigMap{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				Expect(fallbacks("not_found")).To(Equal(notFound + 1))
+			})
+
+			It("should only fall back for the objects of the policy", func() {
+				dReader, err := client.NewDelegatingClient(client.NewDelegatingClientInput{
+					CacheReader: fake.NewClientBuilder().Build(),
+					Client:      live,
+					CacheMissFallback: func(gvk schema.GroupVersionKind, _ client.ObjectKey) bool {
+						return gvk.Kind == "Secret"
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = dReader.Get(context.TODO(), key, &corev1.ConfigMap{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should not fall back without a policy", func() {
+				dReader, err := client.NewDelegatingClient(client.NewDelegatingClientInput{
+					CacheReader: fake.NewClientBuilder().Build(),
+					Client:      live,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = dReader.Get(context.TODO(), key, &corev1.ConfigMap{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
 	})
 	Describe("List", func() {
 		It("should call cache reader when structured object", func() {
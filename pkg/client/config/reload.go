@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// defaultReloadPeriod is how often the credentials of a reloading config are reloaded
+// if no period is given.
+const defaultReloadPeriod = time.Minute
+
+// NewReloadingConfig returns a *rest.Config that talks to the API server with the
+// credentials of the config returned by load, e.g. GetConfig. The config is loaded
+// again every period, defaulting to a minute, and right after a request is rejected
+// as unauthorized, so that the clients and informers built from the returned config
+// pick up rotated bearer tokens and client certificates without being rebuilt.
+//
+// Only the credentials are reloaded: the host and the other settings of the returned
+// config are the ones of the config loaded first. If a reload fails, the current
+// credentials are kept.
+//
+// Bearer token files, client certificate files and exec credential plugins are already
+// reloaded by client-go, this is meant for the credentials that are loaded inline,
+// e.g. from a kubeconfig file that is rewritten when they're rotated.
+func NewReloadingConfig(load func() (*rest.Config, error), period time.Duration) (*rest.Config, error) {
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Transport != nil {
+		return nil, errors.New("unable to reload the credentials of a config with a custom Transport")
+	}
+	if period <= 0 {
+		period = defaultReloadPeriod
+	}
+
+	rt := &reloadingTransport{load: load, period: period}
+	if err := rt.update(cfg); err != nil {
+		return nil, err
+	}
+
+	// The requests are authenticated by the transport of the config that was loaded
+	// last, so the returned config has no credentials of its own.
+	reloading := rest.AnonymousClientConfig(cfg)
+	reloading.WrapTransport = func(http.RoundTripper) http.RoundTripper {
+		return rt
+	}
+	return reloading, nil
+}
+
+// credentials are the settings of a config that its transport authenticates with.
+type credentials struct {
+	BearerToken     string
+	BearerTokenFile string
+	Username        string
+	Password        string
+	TLSClientConfig rest.TLSClientConfig
+	Impersonate     rest.ImpersonationConfig
+	AuthProvider    *clientcmdapi.AuthProviderConfig
+	ExecProvider    *clientcmdapi.ExecConfig
+}
+
+func credentialsOf(cfg *rest.Config) credentials {
+	return credentials{
+		BearerToken:     cfg.BearerToken,
+		BearerTokenFile: cfg.BearerTokenFile,
+		Username:        cfg.Username,
+		Password:        cfg.Password,
+		TLSClientConfig: cfg.TLSClientConfig,
+		Impersonate:     cfg.Impersonate,
+		AuthProvider:    cfg.AuthProvider,
+		ExecProvider:    cfg.ExecProvider,
+	}
+}
+
+// reloadingTransport sends the requests with the transport of the config that was
+// loaded last.
+type reloadingTransport struct {
+	load   func() (*rest.Config, error)
+	period time.Duration
+
+	mu          sync.Mutex
+	transport   http.RoundTripper
+	credentials credentials
+	loadedAt    time.Time
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *reloadingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.current().RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		// The credentials may have been rotated, so reload them for the next request.
+		t.mu.Lock()
+		t.loadedAt = time.Time{}
+		t.mu.Unlock()
+	}
+	return resp, err
+}
+
+// current returns the transport of the config, reloading it if it's due.
+func (t *reloadingTransport) current() http.RoundTripper {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if time.Since(t.loadedAt) >= t.period {
+		cfg, err := t.load()
+		if err == nil {
+			err = t.update(cfg)
+		}
+		if err != nil {
+			log.Error(err, "unable to reload the credentials of the config, keeping the current ones")
+			t.loadedAt = time.Now()
+		}
+	}
+	return t.transport
+}
+
+// update builds the transport of a loaded config, if its credentials changed. It must
+// be called with the lock held.
+func (t *reloadingTransport) update(cfg *rest.Config) error {
+	creds := credentialsOf(cfg)
+	if t.transport == nil || !reflect.DeepEqual(creds, t.credentials) {
+		transport, err := rest.TransportFor(cfg)
+		if err != nil {
+			return err
+		}
+		if t.transport != nil {
+			utilnet.CloseIdleConnectionsFor(t.transport)
+		}
+		t.transport, t.credentials = transport, creds
+	}
+	t.loadedAt = time.Now()
+	return nil
+}
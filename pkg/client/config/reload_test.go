@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("NewReloadingConfig", func() {
+	var (
+		server *httptest.Server
+		mu     sync.Mutex
+		token  string
+		loads  int
+	)
+
+	setToken := func(t string) {
+		mu.Lock()
+		defer mu.Unlock()
+		token = t
+	}
+
+	load := func() (*rest.Config, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		loads++
+		return &rest.Config{Host: server.URL, BearerToken: token}, nil
+	}
+
+	BeforeEach(func() {
+		setToken("first")
+		loads = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(cfg *rest.Config) func() int {
+		client, err := rest.HTTPClientFor(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		return func() int {
+			resp, err := client.Get(server.URL)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+	}
+
+	It("should reload the credentials periodically", func() {
+		cfg, err := NewReloadingConfig(load, 50*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.BearerToken).To(BeEmpty())
+		status := get(cfg)
+		Expect(status()).To(Equal(http.StatusOK))
+
+		setToken("second")
+		Eventually(status).Should(Equal(http.StatusOK))
+	})
+
+	It("should reload the credentials after a request is unauthorized", func() {
+		cfg, err := NewReloadingConfig(load, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		status := get(cfg)
+		Expect(status()).To(Equal(http.StatusOK))
+
+		setToken("second")
+		Expect(status()).To(Equal(http.StatusUnauthorized))
+		Expect(status()).To(Equal(http.StatusOK))
+		Expect(status()).To(Equal(http.StatusOK))
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(loads).To(Equal(2))
+	})
+
+	It("should return the error of the first load", func() {
+		_, err := NewReloadingConfig(func() (*rest.Config, error) {
+			return nil, errors.New("expected error")
+		}, time.Minute)
+		Expect(err).To(MatchError("expected error"))
+	})
+})
@@ -21,11 +21,16 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // NewDryRunClient wraps an existing client and enforces DryRun mode
-// on all mutating api calls.
+// on all mutating api calls. If the client is a WithWatch, so is the
+// returned one.
 func NewDryRunClient(c Client) Client {
+	if w, ok := c.(WithWatch); ok {
+		return &dryRunWatchingClient{dryRunClient: &dryRunClient{client: c}, watcher: w}
+	}
 	return &dryRunClient{client: c}
 }
 
@@ -36,6 +41,19 @@ type dryRunClient struct {
 	client Client
 }
 
+// dryRunWatchingClient is a dryRunClient that wraps a WithWatch.
+type dryRunWatchingClient struct {
+	*dryRunClient
+	watcher WithWatch
+}
+
+var _ WithWatch = &dryRunWatchingClient{}
+
+// Watch implements client.WithWatch.
+func (c *dryRunWatchingClient) Watch(ctx context.Context, list ObjectList, opts ...ListOption) (watch.Interface, error) {
+	return c.watcher.Watch(ctx, list, opts...)
+}
+
 // Scheme returns the scheme this client is using.
 func (c *dryRunClient) Scheme() *runtime.Scheme {
 	return c.client.Scheme()
@@ -71,6 +89,11 @@ func (c *dryRunClient) Patch(ctx context.Context, obj Object, patch Patch, opts
 	return c.client.Patch(ctx, obj, patch, append(opts, DryRunAll)...)
 }
 
+// Apply implements client.Client.
+func (c *dryRunClient) Apply(ctx context.Context, obj ApplyConfiguration, opts ...ApplyOption) error {
+	return c.client.Apply(ctx, obj, append(opts, DryRunAll)...)
+}
+
 // Get implements client.Client.
 func (c *dryRunClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
 	return c.client.Get(ctx, key, obj, opts...)
@@ -104,3 +127,37 @@ func (sw *dryRunStatusWriter) Update(ctx context.Context, obj Object, opts ...Up
 func (sw *dryRunStatusWriter) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
 	return sw.client.Patch(ctx, obj, patch, append(opts, DryRunAll)...)
 }
+
+// SubResource implements client.SubResourceClientConstructor.
+func (c *dryRunClient) SubResource(subResource string) SubResourceClient {
+	return &dryRunSubResourceClient{client: c.client.SubResource(subResource)}
+}
+
+// ensure dryRunSubResourceClient implements client.SubResourceClient.
+var _ SubResourceClient = &dryRunSubResourceClient{}
+
+// dryRunSubResourceClient is client.SubResourceClient that writes a subresource with
+// dryRun mode enforced.
+type dryRunSubResourceClient struct {
+	client SubResourceClient
+}
+
+// Get implements client.SubResourceReader.
+func (sc *dryRunSubResourceClient) Get(ctx context.Context, obj, subResource Object, opts ...SubResourceGetOption) error {
+	return sc.client.Get(ctx, obj, subResource, opts...)
+}
+
+// Create implements client.SubResourceWriter.
+func (sc *dryRunSubResourceClient) Create(ctx context.Context, obj, subResource Object, opts ...SubResourceCreateOption) error {
+	return sc.client.Create(ctx, obj, subResource, append(opts, DryRunAll)...)
+}
+
+// Update implements client.SubResourceWriter.
+func (sc *dryRunSubResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	return sc.client.Update(ctx, obj, append(opts, DryRunAll)...)
+}
+
+// Patch implements client.SubResourceWriter.
+func (sc *dryRunSubResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	return sc.client.Patch(ctx, obj, patch, append(opts, DryRunAll)...)
+}
@@ -23,9 +23,9 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
-	"strings"
 	"sync"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/watch"
@@ -412,13 +413,11 @@ func (c *fakeClient) Get(ctx context.Context, key client.ObjectKey, obj client.O
 }
 
 func (c *fakeClient) Watch(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
-	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	gvk, err := apiutil.GVKForList(list, c.scheme)
 	if err != nil {
 		return nil, err
 	}
 
-	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
-
 	listOpts := client.ListOptions{}
 	listOpts.ApplyOptions(opts)
 
@@ -427,14 +426,12 @@ func (c *fakeClient) Watch(ctx context.Context, list client.ObjectList, opts ...
 }
 
 func (c *fakeClient) List(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
-	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	gvk, err := apiutil.GVKForList(obj, c.scheme)
 	if err != nil {
 		return err
 	}
 
-	originalKind := gvk.Kind
-
-	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	originalKind := gvk.Kind + "List"
 
 	if _, isUnstructuredList := obj.(*unstructured.UnstructuredList); isUnstructuredList && !c.scheme.Recognizes(gvk) {
 		// We need to register the ListKind with UnstructuredList:
@@ -752,6 +749,62 @@ func (c *fakeClient) Patch(ctx context.Context, obj client.Object, patch client.
 	return err
 }
 
+// Apply emulates server-side apply: the object is created from the apply configuration if
+// it doesn't exist, or merge patched with it otherwise. Unlike the API server, the fake client
+// doesn't track the managers of the fields, so it never reports conflicts and doesn't remove
+// the fields that are no longer applied.
+func (c *fakeClient) Apply(ctx context.Context, obj client.ApplyConfiguration, opts ...client.ApplyOption) error {
+	applyOptions := &client.ApplyOptions{}
+	applyOptions.ApplyOptions(opts)
+
+	for _, dryRunOpt := range applyOptions.DryRun {
+		if dryRunOpt == metav1.DryRunAll {
+			return nil
+		}
+	}
+
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		u = &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(data); err != nil {
+			return err
+		}
+	}
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(u.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(u), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := c.Create(ctx, u); err != nil {
+			return err
+		}
+	} else {
+		if err := c.Patch(ctx, existing, client.RawPatch(types.MergePatchType, data)); err != nil {
+			return err
+		}
+		existing.DeepCopyInto(u)
+	}
+
+	if u == obj {
+		return nil
+	}
+	data, err = u.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, obj)
+}
+
 func (c *fakeClient) Status() client.StatusWriter {
 	return &fakeStatusWriter{client: c}
 }
@@ -798,6 +851,60 @@ func (sw *fakeStatusWriter) Patch(ctx context.Context, obj client.Object, patch
 	return sw.client.Patch(ctx, obj, patch, opts...)
 }
 
+func (c *fakeClient) SubResource(subResource string) client.SubResourceClient {
+	return &fakeSubResourceClient{client: c, subResource: subResource}
+}
+
+// fakeSubResourceClient supports the status subresource, which is read and written along
+// with the object, and the eviction of Pods, which deletes them.
+type fakeSubResourceClient struct {
+	client      *fakeClient
+	subResource string
+}
+
+func (sc *fakeSubResourceClient) Get(ctx context.Context, obj, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	if sc.subResource != "status" {
+		return sc.notSupported()
+	}
+	return sc.client.Get(ctx, client.ObjectKeyFromObject(obj), subResource)
+}
+
+func (sc *fakeSubResourceClient) Create(ctx context.Context, obj, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if _, isPod := obj.(*corev1.Pod); !isPod || sc.subResource != "eviction" {
+		return sc.notSupported()
+	}
+	createOptions := &client.SubResourceCreateOptions{}
+	createOptions.ApplyOptions(opts)
+	for _, dryRunOpt := range createOptions.DryRun {
+		if dryRunOpt == metav1.DryRunAll {
+			return nil
+		}
+	}
+	return sc.client.Delete(ctx, obj)
+}
+
+func (sc *fakeSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	updateOptions := &client.SubResourceUpdateOptions{}
+	updateOptions.ApplyOptions(opts)
+	if sc.subResource != "status" || updateOptions.SubResourceBody != nil {
+		return sc.notSupported()
+	}
+	return sc.client.Status().Update(ctx, obj, &updateOptions.UpdateOptions)
+}
+
+func (sc *fakeSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	patchOptions := &client.SubResourcePatchOptions{}
+	patchOptions.ApplyOptions(opts)
+	if sc.subResource != "status" || patchOptions.SubResourceBody != nil {
+		return sc.notSupported()
+	}
+	return sc.client.Status().Patch(ctx, obj, patch, &patchOptions.PatchOptions)
+}
+
+func (sc *fakeSubResourceClient) notSupported() error {
+	return fmt.Errorf("the %q subresource is not supported by the fake client", sc.subResource)
+}
+
 func allowsUnconditionalUpdate(gvk schema.GroupVersionKind) bool {
 	switch gvk.Group {
 	case "apps":
@@ -28,11 +28,13 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
 	appsv1 "k8s.io/api/apps/v1"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -154,6 +156,12 @@ var _ = Describe("Fake client", func() {
 			Expect(list.Items).To(HaveLen(2))
 		})
 
+		It("should return an error when listing using an unstructured list without kind", func() {
+			list := &unstructured.UnstructuredList{}
+			err := cl.List(context.Background(), list, client.InNamespace("ns1"))
+			Expect(runtime.IsMissingKind(err)).To(BeTrue())
+		})
+
 		It("should be able to retrieve registered objects that got manipulated as unstructured", func() {
 			list := func() {
 				By("Listing all endpoints in a namespace")
@@ -325,6 +333,57 @@ var _ = Describe("Fake client", func() {
 			Expect(apierrors.IsNotFound(err)).To(BeTrue())
 		})
 
+		It("should be able to Apply an apply configuration", func() {
+			By("Applying a new object")
+			applied := corev1ac.ConfigMap("applied-cm", "ns2").WithData(map[string]string{"foo": "bar"})
+			Expect(cl.Apply(context.Background(), applied, client.FieldOwner("test-owner"))).To(Succeed())
+			Expect(applied.ResourceVersion).NotTo(BeNil())
+
+			obj := &corev1.ConfigMap{}
+			Expect(cl.Get(context.Background(), client.ObjectKey{Namespace: "ns2", Name: "applied-cm"}, obj)).To(Succeed())
+			Expect(obj.Data).To(Equal(map[string]string{"foo": "bar"}))
+
+			By("Applying an existing object")
+			existing := corev1ac.ConfigMap("test-cm", "ns2").WithData(map[string]string{"foo": "bar"})
+			Expect(cl.Apply(context.Background(), existing, client.FieldOwner("test-owner"))).To(Succeed())
+			Expect(existing.Data).To(Equal(map[string]string{"test-key": "test-value", "foo": "bar"}))
+
+			Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(cm), obj)).To(Succeed())
+			Expect(obj.Data).To(Equal(map[string]string{"test-key": "test-value", "foo": "bar"}))
+		})
+
+		It("should not persist an Apply with DryRunAll", func() {
+			applied := corev1ac.ConfigMap("applied-cm", "ns2").WithData(map[string]string{"foo": "bar"})
+			Expect(cl.Apply(context.Background(), applied, client.FieldOwner("test-owner"), client.DryRunAll)).To(Succeed())
+
+			err := cl.Get(context.Background(), client.ObjectKey{Namespace: "ns2", Name: "applied-cm"}, &corev1.ConfigMap{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should be able to update the status subresource", func() {
+			obj := cm.DeepCopy()
+			obj.Annotations = map[string]string{"foo": "bar"}
+			Expect(cl.SubResource("status").Update(context.Background(), obj)).To(Succeed())
+
+			actual := &corev1.ConfigMap{}
+			Expect(cl.SubResource("status").Get(context.Background(), cm, actual)).To(Succeed())
+			Expect(actual.Annotations).To(HaveKeyWithValue("foo", "bar"))
+		})
+
+		It("should be able to evict a Pod", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "evicted-pod", Namespace: "ns1"}}
+			Expect(cl.Create(context.Background(), pod)).To(Succeed())
+
+			Expect(cl.SubResource("eviction").Create(context.Background(), pod, &policyv1.Eviction{})).To(Succeed())
+			err := cl.Get(context.Background(), client.ObjectKeyFromObject(pod), &corev1.Pod{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should return an error for the unsupported subresources", func() {
+			err := cl.SubResource("scale").Get(context.Background(), dep, &unstructured.Unstructured{})
+			Expect(err).To(MatchError(ContainSubstring(`the "scale" subresource is not supported`)))
+		})
+
 		It("should support filtering by labels and their values", func() {
 			By("Listing deployments with a particular label and value")
 			list := &appsv1.DeploymentList{}
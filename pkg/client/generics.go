@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// GetObject retrieves the object of type T with the given key, e.g.
+// GetObject[*corev1.Pod](ctx, c, key). T must be a pointer to a struct.
+func GetObject[T Object](ctx context.Context, c Reader, key ObjectKey, opts ...GetOption) (T, error) {
+	obj, err := newObject[T]()
+	if err != nil {
+		return obj, err
+	}
+	if err := c.Get(ctx, key, obj, opts...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return obj, nil
+}
+
+// ListItems retrieves the objects of type T matching the given options, e.g.
+// ListItems[*corev1.Pod](ctx, c, InNamespace("default")). T must be a pointer to a
+// struct registered in the scheme of the client, along with its list type.
+func ListItems[T Object](ctx context.Context, c Client, opts ...ListOption) ([]T, error) {
+	obj, err := newObject[T]()
+	if err != nil {
+		return nil, err
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+	gvk.Kind += "List"
+	listObj, err := c.Scheme().New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := listObj.(ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a client.ObjectList", listObj)
+	}
+	if err := c.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+
+	objs, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]T, 0, len(objs))
+	for _, o := range objs {
+		item, ok := o.(T)
+		if !ok {
+			return nil, fmt.Errorf("item %T of %T is not a %T", o, list, obj)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// newObject returns a new object of type T, which must be a pointer to a struct.
+func newObject[T Object]() (T, error) {
+	var zero T
+	t := reflect.TypeOf(zero)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return zero, fmt.Errorf("%v is not a pointer to a struct", t)
+	}
+	return reflect.New(t.Elem()).Interface().(T), nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Generic helpers", func() {
+	var cl client.Client
+
+	BeforeEach(func() {
+		cl = fake.NewClientBuilder().WithObjects(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo", Labels: map[string]string{"app": "foo"}}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "baz"}},
+		).Build()
+	})
+
+	Describe("GetObject", func() {
+		It("should get the object of the given type", func() {
+			cm, err := client.GetObject[*corev1.ConfigMap](context.Background(), cl, client.ObjectKey{Namespace: "default", Name: "foo"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cm.Name).To(Equal("foo"))
+		})
+
+		It("should return the error of the client", func() {
+			cm, err := client.GetObject[*corev1.ConfigMap](context.Background(), cl, client.ObjectKey{Namespace: "default", Name: "missing"})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(cm).To(BeNil())
+		})
+
+		It("should return an error if the type isn't a pointer to a struct", func() {
+			_, err := client.GetObject[client.Object](context.Background(), cl, client.ObjectKey{Namespace: "default", Name: "foo"})
+			Expect(err).To(MatchError(ContainSubstring("is not a pointer to a struct")))
+		})
+	})
+
+	Describe("ListItems", func() {
+		It("should list the objects of the given type", func() {
+			cms, err := client.ListItems[*corev1.ConfigMap](context.Background(), cl, client.InNamespace("default"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cms).To(HaveLen(2))
+			names := []string{cms[0].Name, cms[1].Name}
+			Expect(names).To(ConsistOf("foo", "bar"))
+		})
+
+		It("should pass the options to the client", func() {
+			cms, err := client.ListItems[*corev1.ConfigMap](context.Background(), cl, client.MatchingLabels{"app": "foo"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cms).To(HaveLen(1))
+			Expect(cms[0].Name).To(Equal("foo"))
+		})
+	})
+})
@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Interceptor is a function around the calls of a client, e.g. to audit them, to record
+// metrics, to inject faults or to mutate the requests. It must call next to proceed with
+// the call, unless it fails it. The object of the call can be mutated before calling next,
+// and holds the content returned by the Server once next returned.
+type Interceptor func(ctx context.Context, call Call, next func(ctx context.Context) error) error
+
+// Call is a call of a client that is intercepted by an Interceptor.
+type Call struct {
+	// Verb is the verb of the call, i.e. "get", "list", "watch", "create", "update",
+	// "patch", "apply", "delete" or "deletecollection".
+	Verb string
+
+	// SubResource is the subresource of the call, e.g. "status", or empty if the call
+	// is about the object itself.
+	SubResource string
+
+	// Object is the object of the call, or the list of a list call. It's nil for
+	// apply calls.
+	Object runtime.Object
+
+	// Key is the key of the object of a get call.
+	Key ObjectKey
+
+	// Patch is the patch of a patch call.
+	Patch Patch
+
+	// ApplyConfiguration is the apply configuration of an apply call.
+	ApplyConfiguration ApplyConfiguration
+}
+
+// WithInterceptors returns a client that calls the given interceptors around every call
+// of the given client, including the calls of its status and subresource clients. The
+// first interceptor is the outermost one. If the client is a WithWatch, so is the
+// returned one.
+func WithInterceptors(c Client, interceptors ...Interceptor) Client {
+	if len(interceptors) == 0 {
+		return c
+	}
+	if w, ok := c.(WithWatch); ok {
+		return &interceptedWatchingClient{interceptedClient: &interceptedClient{Client: c, interceptors: interceptors}, watcher: w}
+	}
+	return &interceptedClient{Client: c, interceptors: interceptors}
+}
+
+type interceptedClient struct {
+	Client
+	interceptors []Interceptor
+}
+
+var _ Client = &interceptedClient{}
+
+// interceptedWatchingClient is an interceptedClient that wraps a WithWatch.
+type interceptedWatchingClient struct {
+	*interceptedClient
+	watcher WithWatch
+}
+
+var _ WithWatch = &interceptedWatchingClient{}
+
+// Watch implements client.WithWatch.
+func (c *interceptedWatchingClient) Watch(ctx context.Context, list ObjectList, opts ...ListOption) (w watch.Interface, err error) {
+	err = c.intercept(ctx, Call{Verb: "watch", Object: list}, func(ctx context.Context) error {
+		w, err = c.watcher.Watch(ctx, list, opts...)
+		return err
+	})
+	return w, err
+}
+
+// intercept calls fn through the interceptors.
+func (c *interceptedClient) intercept(ctx context.Context, call Call, fn func(ctx context.Context) error) error {
+	next := fn
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := c.interceptors[i], next
+		next = func(ctx context.Context) error {
+			return interceptor(ctx, call, inner)
+		}
+	}
+	return next(ctx)
+}
+
+// Get implements client.Client.
+func (c *interceptedClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
+	return c.intercept(ctx, Call{Verb: "get", Object: obj, Key: key}, func(ctx context.Context) error {
+		return c.Client.Get(ctx, key, obj, opts...)
+	})
+}
+
+// List implements client.Client.
+func (c *interceptedClient) List(ctx context.Context, list ObjectList, opts ...ListOption) error {
+	return c.intercept(ctx, Call{Verb: "list", Object: list}, func(ctx context.Context) error {
+		return c.Client.List(ctx, list, opts...)
+	})
+}
+
+// Create implements client.Client.
+func (c *interceptedClient) Create(ctx context.Context, obj Object, opts ...CreateOption) error {
+	return c.intercept(ctx, Call{Verb: "create", Object: obj}, func(ctx context.Context) error {
+		return c.Client.Create(ctx, obj, opts...)
+	})
+}
+
+// Delete implements client.Client.
+func (c *interceptedClient) Delete(ctx context.Context, obj Object, opts ...DeleteOption) error {
+	return c.intercept(ctx, Call{Verb: "delete", Object: obj}, func(ctx context.Context) error {
+		return c.Client.Delete(ctx, obj, opts...)
+	})
+}
+
+// Update implements client.Client.
+func (c *interceptedClient) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	return c.intercept(ctx, Call{Verb: "update", Object: obj}, func(ctx context.Context) error {
+		return c.Client.Update(ctx, obj, opts...)
+	})
+}
+
+// Patch implements client.Client.
+func (c *interceptedClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	return c.intercept(ctx, Call{Verb: "patch", Object: obj, Patch: patch}, func(ctx context.Context) error {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+// Apply implements client.Client.
+func (c *interceptedClient) Apply(ctx context.Context, obj ApplyConfiguration, opts ...ApplyOption) error {
+	return c.intercept(ctx, Call{Verb: "apply", ApplyConfiguration: obj}, func(ctx context.Context) error {
+		return c.Client.Apply(ctx, obj, opts...)
+	})
+}
+
+// DeleteAllOf implements client.Client.
+func (c *interceptedClient) DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error {
+	return c.intercept(ctx, Call{Verb: "deletecollection", Object: obj}, func(ctx context.Context) error {
+		return c.Client.DeleteAllOf(ctx, obj, opts...)
+	})
+}
+
+// Status implements client.StatusClient.
+func (c *interceptedClient) Status() StatusWriter {
+	return &interceptedStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+// SubResource implements client.SubResourceClientConstructor.
+func (c *interceptedClient) SubResource(subResource string) SubResourceClient {
+	return &interceptedSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), client: c, subResource: subResource}
+}
+
+type interceptedStatusWriter struct {
+	StatusWriter
+	client *interceptedClient
+}
+
+// Update implements client.StatusWriter.
+func (sw *interceptedStatusWriter) Update(ctx context.Context, obj Object, opts ...UpdateOption) error {
+	return sw.client.intercept(ctx, Call{Verb: "update", SubResource: "status", Object: obj}, func(ctx context.Context) error {
+		return sw.StatusWriter.Update(ctx, obj, opts...)
+	})
+}
+
+// Patch implements client.StatusWriter.
+func (sw *interceptedStatusWriter) Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error {
+	return sw.client.intercept(ctx, Call{Verb: "patch", SubResource: "status", Object: obj, Patch: patch}, func(ctx context.Context) error {
+		return sw.StatusWriter.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+type interceptedSubResourceClient struct {
+	SubResourceClient
+	client      *interceptedClient
+	subResource string
+}
+
+// Get implements client.SubResourceReader.
+func (sc *interceptedSubResourceClient) Get(ctx context.Context, obj, subResource Object, opts ...SubResourceGetOption) error {
+	return sc.client.intercept(ctx, Call{Verb: "get", SubResource: sc.subResource, Object: obj, Key: ObjectKeyFromObject(obj)}, func(ctx context.Context) error {
+		return sc.SubResourceClient.Get(ctx, obj, subResource, opts...)
+	})
+}
+
+// Create implements client.SubResourceWriter.
+func (sc *interceptedSubResourceClient) Create(ctx context.Context, obj, subResource Object, opts ...SubResourceCreateOption) error {
+	return sc.client.intercept(ctx, Call{Verb: "create", SubResource: sc.subResource, Object: obj}, func(ctx context.Context) error {
+		return sc.SubResourceClient.Create(ctx, obj, subResource, opts...)
+	})
+}
+
+// Update implements client.SubResourceWriter.
+func (sc *interceptedSubResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	return sc.client.intercept(ctx, Call{Verb: "update", SubResource: sc.subResource, Object: obj}, func(ctx context.Context) error {
+		return sc.SubResourceClient.Update(ctx, obj, opts...)
+	})
+}
+
+// Patch implements client.SubResourceWriter.
+func (sc *interceptedSubResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	return sc.client.intercept(ctx, Call{Verb: "patch", SubResource: sc.subResource, Object: obj, Patch: patch}, func(ctx context.Context) error {
+		return sc.SubResourceClient.Patch(ctx, obj, patch, opts...)
+	})
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("WithInterceptors", func() {
+	var (
+		ctx   context.Context
+		cm    *corev1.ConfigMap
+		calls []string
+	)
+
+	recording := func(name string) client.Interceptor {
+		return func(ctx context.Context, call client.Call, next func(context.Context) error) error {
+			calls = append(calls, name+" "+call.Verb+" "+call.SubResource)
+			return next(ctx)
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "intercepted"}}
+		calls = nil
+	})
+
+	It("should call the interceptors around every call, the first one being the outermost", func() {
+		cl := client.WithInterceptors(fake.NewClientBuilder().Build(), recording("first"), recording("second"))
+
+		Expect(cl.Create(ctx, cm)).To(Succeed())
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+		Expect(cl.List(ctx, &corev1.ConfigMapList{})).To(Succeed())
+		Expect(cl.Update(ctx, cm)).To(Succeed())
+		Expect(cl.Status().Update(ctx, cm)).To(Succeed())
+		Expect(cl.SubResource("status").Patch(ctx, cm, client.MergeFrom(cm.DeepCopy()))).To(Succeed())
+		Expect(cl.Delete(ctx, cm)).To(Succeed())
+
+		Expect(calls).To(Equal([]string{
+			"first create ", "second create ",
+			"first get ", "second get ",
+			"first list ", "second list ",
+			"first update ", "second update ",
+			"first update status", "second update status",
+			"first patch status", "second patch status",
+			"first delete ", "second delete ",
+		}))
+	})
+
+	It("should let the interceptors fail the calls and mutate the requests", func() {
+		cl := client.WithInterceptors(fake.NewClientBuilder().Build(), func(ctx context.Context, call client.Call, next func(context.Context) error) error {
+			if call.Verb == "delete" {
+				return errors.New("deletes are not allowed")
+			}
+			if obj, ok := call.Object.(client.Object); ok && call.Verb == "create" {
+				obj.SetLabels(map[string]string{"intercepted": "true"})
+			}
+			return next(ctx)
+		})
+
+		Expect(cl.Create(ctx, cm)).To(Succeed())
+		Expect(cl.Delete(ctx, cm)).To(MatchError("deletes are not allowed"))
+
+		actual := &corev1.ConfigMap{}
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(cm), actual)).To(Succeed())
+		Expect(actual.Labels).To(HaveKeyWithValue("intercepted", "true"))
+	})
+
+	It("should call the interceptors around the watches of a client.WithWatch", func() {
+		cl := client.WithInterceptors(fake.NewClientBuilder().Build(), recording("first"))
+		watcher, ok := cl.(client.WithWatch)
+		Expect(ok).To(BeTrue())
+
+		w, err := watcher.Watch(ctx, &corev1.ConfigMapList{})
+		Expect(err).NotTo(HaveOccurred())
+		w.Stop()
+		Expect(calls).To(Equal([]string{"first watch "}))
+	})
+
+	It("should return the client if there are no interceptors", func() {
+		cl := fake.NewClientBuilder().Build()
+		Expect(client.WithInterceptors(cl)).To(BeIdenticalTo(cl))
+	})
+})
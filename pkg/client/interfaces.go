@@ -75,6 +75,11 @@ type Writer interface {
 	// struct pointer so that obj can be updated with the content returned by the Server.
 	Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error
 
+	// Apply applies the given apply configuration to the Kubernetes cluster with server-side
+	// apply. obj must be a struct pointer so that obj can be updated with the content returned
+	// by the Server. The field manager must be set, e.g. with FieldOwner.
+	Apply(ctx context.Context, obj ApplyConfiguration, opts ...ApplyOption) error
+
 	// DeleteAllOf deletes all objects of the given type matching the given options.
 	DeleteAllOf(ctx context.Context, obj Object, opts ...DeleteAllOfOption) error
 }
@@ -98,11 +103,53 @@ type StatusWriter interface {
 	Patch(ctx context.Context, obj Object, patch Patch, opts ...PatchOption) error
 }
 
+// SubResourceClientConstructor knows how to create a client for a subresource of
+// Kubernetes objects.
+type SubResourceClientConstructor interface {
+	// SubResource returns a client for the subresource with the given name, e.g.
+	// "status", "scale", "ephemeralcontainers", "eviction" or "token".
+	SubResource(subResource string) SubResourceClient
+}
+
+// SubResourceClient knows how to read and write a subresource of Kubernetes objects.
+type SubResourceClient interface {
+	SubResourceReader
+	SubResourceWriter
+}
+
+// SubResourceReader knows how to read a subresource of Kubernetes objects.
+type SubResourceReader interface {
+	// Get reads the subresource of obj into subResource, e.g. the autoscalingv1.Scale
+	// of a Deployment. subResource must be a struct pointer so that it can be updated
+	// with the content returned by the Server.
+	Get(ctx context.Context, obj Object, subResource Object, opts ...SubResourceGetOption) error
+}
+
+// SubResourceWriter knows how to write a subresource of Kubernetes objects.
+type SubResourceWriter interface {
+	// Create creates subResource for obj, e.g. the policyv1.Eviction of a Pod or the
+	// authenticationv1.TokenRequest of a ServiceAccount. subResource must be a struct
+	// pointer so that it can be updated with the content returned by the Server.
+	Create(ctx context.Context, obj Object, subResource Object, opts ...SubResourceCreateOption) error
+
+	// Update updates the subresource of obj. The body of the request is obj, unless
+	// another one is set with WithSubResourceBody, e.g. an autoscalingv1.Scale. The body
+	// must be a struct pointer so that it can be updated with the content returned by
+	// the Server.
+	Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error
+
+	// Patch patches the subresource of obj. The patch is computed from obj, unless
+	// another body is set with WithSubResourceBody. The body must be a struct pointer
+	// so that it can be updated with the content returned by the Server.
+	Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error
+}
+
 // Client knows how to perform CRUD operations on Kubernetes objects.
 type Client interface {
 	Reader
 	Writer
 	StatusClient
+	SubResourceClientConstructor
 
 	// Scheme returns the scheme this client is using.
 	Scheme() *runtime.Scheme
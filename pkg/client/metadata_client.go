@@ -19,12 +19,13 @@ package client
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/metadata"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 // TODO(directxman12): we could rewrite this on top of the low-level REST
@@ -148,8 +149,10 @@ func (mc *metadataClient) List(ctx context.Context, obj ObjectList, opts ...List
 		return fmt.Errorf("metadata client did not understand object: %T", obj)
 	}
 
-	gvk := metadata.GroupVersionKind()
-	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	gvk, err := apiutil.GVKForList(metadata, nil)
+	if err != nil {
+		return err
+	}
 
 	listOpts := ListOptions{}
 	listOpts.ApplyOptions(opts)
@@ -137,6 +137,31 @@ func (n *namespacedClient) Patch(ctx context.Context, obj Object, patch Patch, o
 	return n.client.Patch(ctx, obj, patch, opts...)
 }
 
+// Apply implements client.Client.
+func (n *namespacedClient) Apply(ctx context.Context, obj ApplyConfiguration, opts ...ApplyOption) error {
+	u, err := applyConfigurationToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	isNamespaceScoped, err := objectutil.IsAPINamespaced(u, n.Scheme(), n.RESTMapper())
+	if err != nil {
+		return fmt.Errorf("error finding the scope of the object: %w", err)
+	}
+
+	objectNamespace := u.GetNamespace()
+	if objectNamespace != n.namespace && objectNamespace != "" {
+		return fmt.Errorf("namespace %s of the object %s does not match the namespace %s on the client", objectNamespace, u.GetName(), n.namespace)
+	}
+
+	if isNamespaceScoped && objectNamespace == "" {
+		u.SetNamespace(n.namespace)
+	}
+	if err := n.client.Apply(ctx, u, opts...); err != nil {
+		return err
+	}
+	return applyConfigurationFromUnstructured(u, obj)
+}
+
 // Get implements client.Client.
 func (n *namespacedClient) Get(ctx context.Context, key ObjectKey, obj Object, opts ...GetOption) error {
 	isNamespaceScoped, err := objectutil.IsAPINamespaced(obj, n.Scheme(), n.RESTMapper())
@@ -211,3 +236,68 @@ func (nsw *namespacedClientStatusWriter) Patch(ctx context.Context, obj Object,
 	}
 	return nsw.StatusClient.Patch(ctx, obj, patch, opts...)
 }
+
+// SubResource implements client.SubResourceClientConstructor.
+func (n *namespacedClient) SubResource(subResource string) SubResourceClient {
+	return &namespacedClientSubResourceClient{client: n.client.SubResource(subResource), namespace: n.namespace, namespacedclient: n}
+}
+
+// ensure namespacedClientSubResourceClient implements client.SubResourceClient.
+var _ SubResourceClient = &namespacedClientSubResourceClient{}
+
+type namespacedClientSubResourceClient struct {
+	client           SubResourceClient
+	namespace        string
+	namespacedclient Client
+}
+
+// setNamespace sets the namespace of the client on obj if it's namespaced, and returns
+// an error if obj is in another namespace.
+func (nsc *namespacedClientSubResourceClient) setNamespace(obj Object) error {
+	isNamespaceScoped, err := objectutil.IsAPINamespaced(obj, nsc.namespacedclient.Scheme(), nsc.namespacedclient.RESTMapper())
+	if err != nil {
+		return fmt.Errorf("error finding the scope of the object: %w", err)
+	}
+
+	objectNamespace := obj.GetNamespace()
+	if objectNamespace != nsc.namespace && objectNamespace != "" {
+		return fmt.Errorf("namespace %s of the object %s does not match the namespace %s on the client", objectNamespace, obj.GetName(), nsc.namespace)
+	}
+
+	if isNamespaceScoped && objectNamespace == "" {
+		obj.SetNamespace(nsc.namespace)
+	}
+	return nil
+}
+
+// Get implements client.SubResourceReader.
+func (nsc *namespacedClientSubResourceClient) Get(ctx context.Context, obj, subResource Object, opts ...SubResourceGetOption) error {
+	if err := nsc.setNamespace(obj); err != nil {
+		return err
+	}
+	return nsc.client.Get(ctx, obj, subResource, opts...)
+}
+
+// Create implements client.SubResourceWriter.
+func (nsc *namespacedClientSubResourceClient) Create(ctx context.Context, obj, subResource Object, opts ...SubResourceCreateOption) error {
+	if err := nsc.setNamespace(obj); err != nil {
+		return err
+	}
+	return nsc.client.Create(ctx, obj, subResource, opts...)
+}
+
+// Update implements client.SubResourceWriter.
+func (nsc *namespacedClientSubResourceClient) Update(ctx context.Context, obj Object, opts ...SubResourceUpdateOption) error {
+	if err := nsc.setNamespace(obj); err != nil {
+		return err
+	}
+	return nsc.client.Update(ctx, obj, opts...)
+}
+
+// Patch implements client.SubResourceWriter.
+func (nsc *namespacedClientSubResourceClient) Patch(ctx context.Context, obj Object, patch Patch, opts ...SubResourcePatchOption) error {
+	if err := nsc.setNamespace(obj); err != nil {
+		return err
+	}
+	return nsc.client.Patch(ctx, obj, patch, opts...)
+}
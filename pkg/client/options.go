@@ -61,6 +61,40 @@ type PatchOption interface {
 	ApplyToPatch(*PatchOptions)
 }
 
+// ApplyOption is some configuration that modifies options for an apply request.
+type ApplyOption interface {
+	// ApplyToApply applies this configuration to the given apply options.
+	ApplyToApply(*ApplyOptions)
+}
+
+// SubResourceGetOption is some configuration that modifies options for a get request
+// of a subresource.
+type SubResourceGetOption interface {
+	// ApplyToSubResourceGet applies this configuration to the given get options.
+	ApplyToSubResourceGet(*SubResourceGetOptions)
+}
+
+// SubResourceCreateOption is some configuration that modifies options for a create
+// request of a subresource.
+type SubResourceCreateOption interface {
+	// ApplyToSubResourceCreate applies this configuration to the given create options.
+	ApplyToSubResourceCreate(*SubResourceCreateOptions)
+}
+
+// SubResourceUpdateOption is some configuration that modifies options for an update
+// request of a subresource.
+type SubResourceUpdateOption interface {
+	// ApplyToSubResourceUpdate applies this configuration to the given update options.
+	ApplyToSubResourceUpdate(*SubResourceUpdateOptions)
+}
+
+// SubResourcePatchOption is some configuration that modifies options for a patch
+// request of a subresource.
+type SubResourcePatchOption interface {
+	// ApplyToSubResourcePatch applies this configuration to the given patch options.
+	ApplyToSubResourcePatch(*SubResourcePatchOptions)
+}
+
 // DeleteAllOfOption is some configuration that modifies options for a delete request.
 type DeleteAllOfOption interface {
 	// ApplyToDeleteAllOf applies this configuration to the given deletecollection options.
@@ -100,6 +134,26 @@ func (dryRunAll) ApplyToDeleteAllOf(opts *DeleteAllOfOptions) {
 	opts.DryRun = []string{metav1.DryRunAll}
 }
 
+// ApplyToApply applies this configuration to the given apply options.
+func (dryRunAll) ApplyToApply(opts *ApplyOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
+
+// ApplyToSubResourceCreate applies this configuration to the given create options.
+func (dryRunAll) ApplyToSubResourceCreate(opts *SubResourceCreateOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
+
+// ApplyToSubResourceUpdate applies this configuration to the given update options.
+func (dryRunAll) ApplyToSubResourceUpdate(opts *SubResourceUpdateOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
+
+// ApplyToSubResourcePatch applies this configuration to the given patch options.
+func (dryRunAll) ApplyToSubResourcePatch(opts *SubResourcePatchOptions) {
+	opts.DryRun = []string{metav1.DryRunAll}
+}
+
 // FieldOwner set the field manager name for the given server-side apply patch.
 type FieldOwner string
 
@@ -118,6 +172,26 @@ func (f FieldOwner) ApplyToUpdate(opts *UpdateOptions) {
 	opts.FieldManager = string(f)
 }
 
+// ApplyToApply applies this configuration to the given apply options.
+func (f FieldOwner) ApplyToApply(opts *ApplyOptions) {
+	opts.FieldManager = string(f)
+}
+
+// ApplyToSubResourceCreate applies this configuration to the given create options.
+func (f FieldOwner) ApplyToSubResourceCreate(opts *SubResourceCreateOptions) {
+	opts.FieldManager = string(f)
+}
+
+// ApplyToSubResourceUpdate applies this configuration to the given update options.
+func (f FieldOwner) ApplyToSubResourceUpdate(opts *SubResourceUpdateOptions) {
+	opts.FieldManager = string(f)
+}
+
+// ApplyToSubResourcePatch applies this configuration to the given patch options.
+func (f FieldOwner) ApplyToSubResourcePatch(opts *SubResourcePatchOptions) {
+	opts.FieldManager = string(f)
+}
+
 // }}}
 
 // {{{ Create Options
@@ -709,6 +783,202 @@ func (forceOwnership) ApplyToPatch(opts *PatchOptions) {
 	opts.Force = &definitelyTrue
 }
 
+func (forceOwnership) ApplyToApply(opts *ApplyOptions) {
+	definitelyTrue := true
+	opts.Force = &definitelyTrue
+}
+
+func (forceOwnership) ApplyToSubResourcePatch(opts *SubResourcePatchOptions) {
+	definitelyTrue := true
+	opts.Force = &definitelyTrue
+}
+
+// }}}
+
+// {{{ Apply Options
+
+// ApplyOptions contains options for server-side apply requests.
+type ApplyOptions struct {
+	// When present, indicates that modifications should not be
+	// persisted. An invalid or unrecognized dryRun directive will
+	// result in an error response and no further processing of the
+	// request. Valid values are:
+	// - All: all dry run stages will be processed
+	DryRun []string
+
+	// Force is going to "force" Apply requests. It means user will
+	// re-acquire conflicting fields owned by other people.
+	// +optional
+	Force *bool
+
+	// FieldManager is the name of the user or component submitting
+	// this request. It must be set.
+	FieldManager string
+}
+
+// ApplyOptions applies the given apply options on these options,
+// and then returns itself (for convenient chaining).
+func (o *ApplyOptions) ApplyOptions(opts []ApplyOption) *ApplyOptions {
+	for _, opt := range opts {
+		opt.ApplyToApply(o)
+	}
+	return o
+}
+
+// AsPatchOptions returns these options as a metav1.PatchOptions, as an
+// apply request is an apply patch.
+func (o *ApplyOptions) AsPatchOptions() *metav1.PatchOptions {
+	if o == nil {
+		return &metav1.PatchOptions{}
+	}
+	return &metav1.PatchOptions{
+		DryRun:       o.DryRun,
+		Force:        o.Force,
+		FieldManager: o.FieldManager,
+	}
+}
+
+var _ ApplyOption = &ApplyOptions{}
+
+// ApplyToApply implements ApplyOption.
+func (o *ApplyOptions) ApplyToApply(ao *ApplyOptions) {
+	if o.DryRun != nil {
+		ao.DryRun = o.DryRun
+	}
+	if o.Force != nil {
+		ao.Force = o.Force
+	}
+	if o.FieldManager != "" {
+		ao.FieldManager = o.FieldManager
+	}
+}
+
+// }}}
+
+// {{{ SubResource Options
+
+// SubResourceGetOptions contains options for get requests of a subresource.
+type SubResourceGetOptions struct {
+	// Raw represents raw GetOptions, as passed to the API server.
+	Raw *metav1.GetOptions
+}
+
+// ApplyOptions applies the given get options on these options,
+// and then returns itself (for convenient chaining).
+func (o *SubResourceGetOptions) ApplyOptions(opts []SubResourceGetOption) *SubResourceGetOptions {
+	for _, opt := range opts {
+		opt.ApplyToSubResourceGet(o)
+	}
+	return o
+}
+
+// AsGetOptions returns these options as a metav1.GetOptions.
+func (o *SubResourceGetOptions) AsGetOptions() *metav1.GetOptions {
+	if o == nil || o.Raw == nil {
+		return &metav1.GetOptions{}
+	}
+	return o.Raw
+}
+
+// ApplyToSubResourceGet implements SubResourceGetOption.
+func (o *SubResourceGetOptions) ApplyToSubResourceGet(so *SubResourceGetOptions) {
+	if o.Raw != nil {
+		so.Raw = o.Raw
+	}
+}
+
+// SubResourceCreateOptions contains options for create requests of a subresource.
+type SubResourceCreateOptions struct {
+	CreateOptions
+}
+
+// ApplyOptions applies the given create options on these options,
+// and then returns itself (for convenient chaining).
+func (o *SubResourceCreateOptions) ApplyOptions(opts []SubResourceCreateOption) *SubResourceCreateOptions {
+	for _, opt := range opts {
+		opt.ApplyToSubResourceCreate(o)
+	}
+	return o
+}
+
+// ApplyToSubResourceCreate implements SubResourceCreateOption.
+func (o *SubResourceCreateOptions) ApplyToSubResourceCreate(so *SubResourceCreateOptions) {
+	o.CreateOptions.ApplyToCreate(&so.CreateOptions)
+}
+
+// SubResourceUpdateOptions contains options for update requests of a subresource.
+type SubResourceUpdateOptions struct {
+	UpdateOptions
+
+	// SubResourceBody is the body of the request, if it's not the object itself,
+	// e.g. an autoscalingv1.Scale.
+	SubResourceBody Object
+}
+
+// ApplyOptions applies the given update options on these options,
+// and then returns itself (for convenient chaining).
+func (o *SubResourceUpdateOptions) ApplyOptions(opts []SubResourceUpdateOption) *SubResourceUpdateOptions {
+	for _, opt := range opts {
+		opt.ApplyToSubResourceUpdate(o)
+	}
+	return o
+}
+
+// ApplyToSubResourceUpdate implements SubResourceUpdateOption.
+func (o *SubResourceUpdateOptions) ApplyToSubResourceUpdate(so *SubResourceUpdateOptions) {
+	o.UpdateOptions.ApplyToUpdate(&so.UpdateOptions)
+	if o.SubResourceBody != nil {
+		so.SubResourceBody = o.SubResourceBody
+	}
+}
+
+// SubResourcePatchOptions contains options for patch requests of a subresource.
+type SubResourcePatchOptions struct {
+	PatchOptions
+
+	// SubResourceBody is the object the patch is computed from and the result is
+	// returned into, if it's not the object itself, e.g. an autoscalingv1.Scale.
+	SubResourceBody Object
+}
+
+// ApplyOptions applies the given patch options on these options,
+// and then returns itself (for convenient chaining).
+func (o *SubResourcePatchOptions) ApplyOptions(opts []SubResourcePatchOption) *SubResourcePatchOptions {
+	for _, opt := range opts {
+		opt.ApplyToSubResourcePatch(o)
+	}
+	return o
+}
+
+// ApplyToSubResourcePatch implements SubResourcePatchOption.
+func (o *SubResourcePatchOptions) ApplyToSubResourcePatch(so *SubResourcePatchOptions) {
+	o.PatchOptions.ApplyToPatch(&so.PatchOptions)
+	if o.SubResourceBody != nil {
+		so.SubResourceBody = o.SubResourceBody
+	}
+}
+
+// WithSubResourceBody sets the body of the update or patch request of a subresource,
+// if it's not the object itself, e.g. the autoscalingv1.Scale of a Deployment.
+func WithSubResourceBody(body Object) SubResourceBody {
+	return SubResourceBody{Body: body}
+}
+
+// SubResourceBody is the body of the update or patch request of a subresource.
+type SubResourceBody struct {
+	Body Object
+}
+
+// ApplyToSubResourceUpdate applies this configuration to the given update options.
+func (b SubResourceBody) ApplyToSubResourceUpdate(opts *SubResourceUpdateOptions) {
+	opts.SubResourceBody = b.Body
+}
+
+// ApplyToSubResourcePatch applies this configuration to the given patch options.
+func (b SubResourceBody) ApplyToSubResourcePatch(opts *SubResourcePatchOptions) {
+	opts.SubResourceBody = b.Body
+}
+
 // }}}
 
 // {{{ DeleteAllOf Options
@@ -0,0 +1,72 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// RateLimitOptions configures the rate limiting of the requests of a client, instead of
+// the one of its rest.Config.
+//
+// The requests the API server asks to retry later with a Retry-After header, e.g. when
+// it's overloaded, are retried by client-go, up to 10 times. The retries are rate limited
+// like the other requests of the client.
+type RateLimitOptions struct {
+	// RateLimiter, if provided, limits the rate of the requests of the client. It's
+	// shared by the clients it's given to. QPS and Burst are ignored if it's set.
+	RateLimiter flowcontrol.RateLimiter
+
+	// QPS, if not zero, is the maximum QPS of the client, instead of the QPS of its
+	// rest.Config.
+	QPS float32
+
+	// Burst, if not zero, is the maximum burst of the client, instead of the burst of
+	// its rest.Config.
+	Burst int
+}
+
+// withRateLimit returns a copy of the config with the rate limiting of the options.
+func withRateLimit(config *rest.Config, options RateLimitOptions) *rest.Config {
+	config = rest.CopyConfig(config)
+	switch {
+	case options.RateLimiter != nil:
+		config.RateLimiter = options.RateLimiter
+	case options.QPS != 0 || options.Burst != 0:
+		if options.QPS != 0 {
+			config.QPS = options.QPS
+		}
+		if options.Burst != 0 {
+			config.Burst = options.Burst
+		}
+		// The rate limiter is made here rather than by rest.RESTClientFor, which would
+		// make one per REST client, i.e. per kind of objects of the client.
+		config.RateLimiter = nil
+		qps, burst := config.QPS, config.Burst
+		if qps == 0 {
+			qps = rest.DefaultQPS
+		}
+		if burst == 0 {
+			burst = rest.DefaultBurst
+		}
+		if qps > 0 {
+			config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+		}
+	}
+	return config
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+var _ = Describe("withRateLimit", func() {
+	It("should use the rate limiter of the options instead of the one of the config", func() {
+		limiter := flowcontrol.NewFakeAlwaysRateLimiter()
+		config := &rest.Config{QPS: 5, Burst: 10, RateLimiter: flowcontrol.NewFakeNeverRateLimiter()}
+
+		actual := withRateLimit(config, RateLimitOptions{RateLimiter: limiter, QPS: 100})
+		Expect(actual.RateLimiter).To(BeIdenticalTo(limiter))
+		Expect(actual.QPS).To(BeEquivalentTo(5))
+		Expect(config.RateLimiter).NotTo(BeIdenticalTo(limiter))
+	})
+
+	It("should make a rate limiter with the QPS and burst of the options instead of the ones of the config", func() {
+		config := &rest.Config{QPS: 5, Burst: 10, RateLimiter: flowcontrol.NewFakeNeverRateLimiter()}
+
+		actual := withRateLimit(config, RateLimitOptions{QPS: 100})
+		Expect(actual.RateLimiter).NotTo(BeNil())
+		Expect(actual.RateLimiter.QPS()).To(BeEquivalentTo(100))
+		Expect(actual.QPS).To(BeEquivalentTo(100))
+		Expect(actual.Burst).To(Equal(10))
+		Expect(config.QPS).To(BeEquivalentTo(5))
+	})
+
+	It("should share the rate limiter of the QPS and burst of the options between the kinds of objects", func() {
+		config := withRateLimit(&rest.Config{Host: "http://localhost"}, RateLimitOptions{QPS: 100, Burst: 200})
+
+		pods, err := apiutil.RESTClientForGVK(corev1.SchemeGroupVersion.WithKind("Pod"), false, config, kscheme.Codecs)
+		Expect(err).NotTo(HaveOccurred())
+		deployments, err := apiutil.RESTClientForGVK(appsv1.SchemeGroupVersion.WithKind("Deployment"), false, config, kscheme.Codecs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pods.GetRateLimiter()).To(BeIdenticalTo(config.RateLimiter))
+		Expect(deployments.GetRateLimiter()).To(BeIdenticalTo(config.RateLimiter))
+	})
+
+	It("should not rate limit the requests with a negative QPS", func() {
+		actual := withRateLimit(&rest.Config{RateLimiter: flowcontrol.NewFakeNeverRateLimiter()}, RateLimitOptions{QPS: -1})
+		Expect(actual.RateLimiter).To(BeNil())
+	})
+})
@@ -0,0 +1,69 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/retry"
+)
+
+// UpdateWithRetry calls mutate on the object and updates it. If the update conflicts
+// with another change of the object, the object is read again from the client and the
+// mutation is applied to it again, with the bounded backoff of retry.DefaultRetry.
+// Mutate must thus be idempotent, and its error is returned as is.
+func UpdateWithRetry(ctx context.Context, c Client, obj Object, mutate func() error, opts ...UpdateOption) error {
+	return withRetryOnConflict(ctx, c, obj, func() error {
+		if err := mutate(); err != nil {
+			return err
+		}
+		return c.Update(ctx, obj, opts...)
+	})
+}
+
+// PatchStatusWithRetry calls mutate on the object and patches its status with the
+// changes of mutate. The patch fails on conflicts with other changes of the object,
+// in which case the object is read again from the client and the mutation is applied
+// to it again, with the bounded backoff of retry.DefaultRetry. Mutate must thus be
+// idempotent, and its error is returned as is.
+func PatchStatusWithRetry(ctx context.Context, c Client, obj Object, mutate func() error, opts ...PatchOption) error {
+	return withRetryOnConflict(ctx, c, obj, func() error {
+		patch := MergeFromWithOptions(obj.DeepCopyObject().(Object), MergeFromWithOptimisticLock{})
+		if err := mutate(); err != nil {
+			return err
+		}
+		return c.Status().Patch(ctx, obj, patch, opts...)
+	})
+}
+
+// withRetryOnConflict calls fn, and on conflicts reads the object again before calling
+// it again. It stops retrying once the context is done.
+func withRetryOnConflict(ctx context.Context, c Client, obj Object, fn func() error) error {
+	first := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !first {
+			if err := c.Get(ctx, ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		first = false
+		return fn()
+	})
+}
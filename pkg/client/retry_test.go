@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Retry on conflict helpers", func() {
+	var (
+		ctx   context.Context
+		cl    client.Client
+		stale *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		cl = fake.NewClientBuilder().WithObjects(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}}).Build()
+
+		stale = &corev1.Pod{}
+		Expect(cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, stale)).To(Succeed())
+
+		// Change the object behind the back of the stale copy.
+		current := stale.DeepCopy()
+		current.Labels = map[string]string{"changed": "true"}
+		Expect(cl.Update(ctx, current)).To(Succeed())
+	})
+
+	Describe("UpdateWithRetry", func() {
+		It("should get the object again and mutate it again on conflicts", func() {
+			calls := 0
+			Expect(client.UpdateWithRetry(ctx, cl, stale, func() error {
+				calls++
+				if stale.Annotations == nil {
+					stale.Annotations = map[string]string{}
+				}
+				stale.Annotations["mutated"] = "true"
+				return nil
+			})).To(Succeed())
+			Expect(calls).To(Equal(2))
+
+			actual := &corev1.Pod{}
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(stale), actual)).To(Succeed())
+			Expect(actual.Labels).To(HaveKeyWithValue("changed", "true"))
+			Expect(actual.Annotations).To(HaveKeyWithValue("mutated", "true"))
+		})
+
+		It("should return the error of the mutation", func() {
+			Expect(client.UpdateWithRetry(ctx, cl, stale, func() error {
+				return errors.New("mutation failed")
+			})).To(MatchError("mutation failed"))
+		})
+
+		It("should stop retrying once the context is done", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			calls := 0
+			Expect(client.UpdateWithRetry(ctx, cl, stale, func() error {
+				calls++
+				cancel()
+				return nil
+			})).To(MatchError(context.Canceled))
+			Expect(calls).To(Equal(1))
+		})
+	})
+
+	Describe("PatchStatusWithRetry", func() {
+		It("should get the object again and mutate it again on conflicts", func() {
+			calls := 0
+			Expect(client.PatchStatusWithRetry(ctx, cl, stale, func() error {
+				calls++
+				stale.Status.Message = "mutated"
+				return nil
+			})).To(Succeed())
+			Expect(calls).To(Equal(2))
+
+			actual := &corev1.Pod{}
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(stale), actual)).To(Succeed())
+			Expect(actual.Labels).To(HaveKeyWithValue("changed", "true"))
+			Expect(actual.Status.Message).To(Equal("mutated"))
+		})
+	})
+})
@@ -18,14 +18,16 @@ package client
 
 import (
 	"context"
-	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/internal/metrics"
 )
 
 // NewDelegatingClientInput encapsulates the input parameters to create a new delegating client.
@@ -34,6 +36,21 @@ type NewDelegatingClientInput struct {
 	Client            Client
 	UncachedObjects   []Object
 	CacheUnstructured bool
+
+	// CacheMissFallback, if set, decides whether the gets of the objects that are not
+	// found in the cache are retried against the API server, e.g. to not conclude that
+	// an object that was just created doesn't exist because the cache hasn't seen it yet.
+	CacheMissFallback CacheMissFallbackPolicy
+}
+
+// CacheMissFallbackPolicy decides whether a get of the object of the given kind and key,
+// that was not found in the cache, falls back to a get from the API server.
+type CacheMissFallbackPolicy func(gvk schema.GroupVersionKind, key ObjectKey) bool
+
+// AlwaysFallBackOnCacheMiss is a CacheMissFallbackPolicy that falls back to a get from
+// the API server for all the objects not found in the cache.
+func AlwaysFallBackOnCacheMiss(schema.GroupVersionKind, ObjectKey) bool {
+	return true
 }
 
 // NewDelegatingClient creates a new delegating client.
@@ -41,6 +58,9 @@ type NewDelegatingClientInput struct {
 // A delegating client forms a Client by composing separate reader, writer and
 // statusclient interfaces.  This way, you can have an Client that reads from a
 // cache and writes to the API server.
+//
+// If the Client of the input is a WithWatch, so is the delegating client. Its
+// watches are served by the API server, not by the cache.
 func NewDelegatingClient(in NewDelegatingClientInput) (Client, error) {
 	uncachedGVKs := map[schema.GroupVersionKind]struct{}{}
 	for _, obj := range in.UncachedObjects {
@@ -51,7 +71,7 @@ func NewDelegatingClient(in NewDelegatingClientInput) (Client, error) {
 		uncachedGVKs[gvk] = struct{}{}
 	}
 
-	return &delegatingClient{
+	c := &delegatingClient{
 		scheme: in.Client.Scheme(),
 		mapper: in.Client.RESTMapper(),
 		Reader: &delegatingReader{
@@ -60,16 +80,23 @@ func NewDelegatingClient(in NewDelegatingClientInput) (Client, error) {
 			scheme:            in.Client.Scheme(),
 			uncachedGVKs:      uncachedGVKs,
 			cacheUnstructured: in.CacheUnstructured,
+			cacheMissFallback: in.CacheMissFallback,
 		},
-		Writer:       in.Client,
-		StatusClient: in.Client,
-	}, nil
+		Writer:                       in.Client,
+		StatusClient:                 in.Client,
+		SubResourceClientConstructor: in.Client,
+	}
+	if w, ok := in.Client.(WithWatch); ok {
+		return &delegatingWatchingClient{delegatingClient: c, watcher: w}, nil
+	}
+	return c, nil
 }
 
 type delegatingClient struct {
 	Reader
 	Writer
 	StatusClient
+	SubResourceClientConstructor
 
 	scheme *runtime.Scheme
 	mapper meta.RESTMapper
@@ -85,6 +112,20 @@ func (d *delegatingClient) RESTMapper() meta.RESTMapper {
 	return d.mapper
 }
 
+// delegatingWatchingClient is a delegatingClient that serves its watches with the
+// watcher, i.e. with the API server.
+type delegatingWatchingClient struct {
+	*delegatingClient
+	watcher WithWatch
+}
+
+var _ WithWatch = &delegatingWatchingClient{}
+
+// Watch implements client.WithWatch.
+func (d *delegatingWatchingClient) Watch(ctx context.Context, list ObjectList, opts ...ListOption) (watch.Interface, error) {
+	return d.watcher.Watch(ctx, list, opts...)
+}
+
 // delegatingReader forms a Reader that will cause Get and List requests for
 // unstructured types to use the ClientReader while requests for any other type
 // of object with use the CacheReader.  This avoids accidentally caching the
@@ -97,18 +138,20 @@ type delegatingReader struct {
 	uncachedGVKs      map[schema.GroupVersionKind]struct{}
 	scheme            *runtime.Scheme
 	cacheUnstructured bool
+	cacheMissFallback CacheMissFallbackPolicy
 }
 
 func (d *delegatingReader) shouldBypassCache(obj runtime.Object) (bool, error) {
-	gvk, err := apiutil.GVKForObject(obj, d.scheme)
+	var gvk schema.GroupVersionKind
+	var err error
+	if meta.IsListType(obj) {
+		gvk, err = apiutil.GVKForList(obj, d.scheme)
+	} else {
+		gvk, err = apiutil.GVKForObject(obj, d.scheme)
+	}
 	if err != nil {
 		return false, err
 	}
-	// TODO: this is producing unsafe guesses that don't actually work,
-	// but it matches ~99% of the cases out there.
-	if meta.IsListType(obj) {
-		gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
-	}
 	if _, isUncached := d.uncachedGVKs[gvk]; isUncached {
 		return true, nil
 	}
@@ -127,7 +170,25 @@ func (d *delegatingReader) Get(ctx context.Context, key ObjectKey, obj Object, o
 	} else if isUncached {
 		return d.ClientReader.Get(ctx, key, obj, opts...)
 	}
-	return d.CacheReader.Get(ctx, key, obj, opts...)
+	err := d.CacheReader.Get(ctx, key, obj, opts...)
+	if d.cacheMissFallback == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+	gvk, gvkErr := apiutil.GVKForObject(obj, d.scheme)
+	if gvkErr != nil || !d.cacheMissFallback(gvk, key) {
+		return err
+	}
+
+	err = d.ClientReader.Get(ctx, key, obj, opts...)
+	result := "found"
+	switch {
+	case apierrors.IsNotFound(err):
+		result = "not_found"
+	case err != nil:
+		result = "error"
+	}
+	metrics.CacheMissFallbacks.WithLabelValues(gvk.Group, gvk.Version, gvk.Kind, result).Inc()
+	return err
 }
 
 // List retrieves list of objects for a given namespace and list options.
@@ -18,13 +18,14 @@ package client
 
 import (
 	"context"
-	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
 // NewWithWatch returns a new WithWatch.
@@ -68,8 +69,10 @@ func (w *watchingClient) listOpts(opts ...ListOption) ListOptions {
 }
 
 func (w *watchingClient) metadataWatch(ctx context.Context, obj *metav1.PartialObjectMetadataList, opts ...ListOption) (watch.Interface, error) {
-	gvk := obj.GroupVersionKind()
-	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	gvk, err := apiutil.GVKForList(obj, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	listOpts := w.listOpts(opts...)
 
@@ -82,8 +85,9 @@ func (w *watchingClient) metadataWatch(ctx context.Context, obj *metav1.PartialO
 }
 
 func (w *watchingClient) unstructuredWatch(ctx context.Context, obj *unstructured.UnstructuredList, opts ...ListOption) (watch.Interface, error) {
-	gvk := obj.GroupVersionKind()
-	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	if _, err := apiutil.GVKForList(obj, nil); err != nil {
+		return nil, err
+	}
 
 	r, err := w.client.unstructuredClient.cache.getResource(obj)
 	if err != nil {
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+)
+
+// Aware is implemented by components that act on clusters which are engaged and
+// disengaged at runtime, such as the manager and the controllers added to it.
+type Aware interface {
+	// Engage starts acting on the cluster with the given name, e.g. by watching objects
+	// in it. For components added to the manager, the context is canceled once the
+	// cluster is disengaged or the manager stops.
+	Engage(ctx context.Context, name string, cl Cluster) error
+
+	// Disengage stops acting on the cluster with the given name.
+	Disengage(ctx context.Context, name string) error
+}
+
+// Provider engages and disengages clusters at runtime, e.g. the member clusters of a
+// fleet as they register and deregister.
+type Provider interface {
+	// Run engages and disengages clusters with the given Aware, which is the manager if
+	// the Provider is set in its options, until the context is canceled. The clusters
+	// are started by the manager, not by the Provider.
+	Run(ctx context.Context, aware Aware) error
+}
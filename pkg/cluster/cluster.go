@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
+	"sigs.k8s.io/controller-runtime/pkg/recorder"
 )
 
 // Cluster provides various methods to interact with a cluster.
@@ -51,7 +52,7 @@ type Cluster interface {
 	// GetClient returns a client configured with the Config. This client may
 	// not be a fully "direct" client -- it may read from a cache, for
 	// instance.  See Options.NewClient for more information on how the default
-	// implementation works.
+	// implementation works. The default client is also a client.WithWatch.
 	GetClient() client.Client
 
 	// GetFieldIndexer returns a client.FieldIndexer configured with the client
@@ -109,6 +110,13 @@ type Options struct {
 	// by the manager. If not set this will use the default new cache function.
 	NewCache cache.NewCacheFunc
 
+	// LazyCacheStart defers starting the cache until it's needed, i.e. until an object
+	// is read from it, e.g. with the client, or one of its informers is requested, e.g.
+	// by a controller watching a kind. A cluster that is only used to serve webhooks,
+	// or to read objects with the APIReader, then doesn't list and watch any object.
+	// Defaults to false.
+	LazyCacheStart bool
+
 	// NewClient is the func that creates the client to be used by the manager.
 	// If not set this will create the default DelegatingClient that will
 	// use the cache for reads and the client for writes.
@@ -118,6 +126,27 @@ type Options struct {
 	// for the given objects.
 	ClientDisableCacheFor []client.Object
 
+	// ClientCacheMissFallback, if set, decides whether the gets of the client of the
+	// cluster that don't find an object in the cache fall back to a get from the API
+	// server, so that reconcilers don't act on a stale "not found" right after the
+	// object was created. It's only used by the default NewClient.
+	ClientCacheMissFallback client.CacheMissFallbackPolicy
+
+	// ClientInterceptors are called around every call of the client and of the
+	// APIReader of the cluster, whether it's served by the cache or by the API server,
+	// e.g. to audit the calls, to record metrics, or to inject faults in tests. The
+	// first interceptor is the outermost one.
+	ClientInterceptors []client.Interceptor
+
+	// ClientWarningHandlerOptions configures how the clients of the cluster
+	// surface the warnings sent by the API server, e.g. how they are deduplicated.
+	ClientWarningHandlerOptions client.WarningHandlerOptions
+
+	// ClientRateLimitOptions configures the rate limiting of the requests of the
+	// clients of the cluster, e.g. their own QPS and burst instead of the ones of the
+	// rest.Config.
+	ClientRateLimitOptions client.RateLimitOptions
+
 	// DryRunClient specifies whether the client should be configured to enforce
 	// dryRun mode.
 	DryRunClient bool
@@ -129,6 +158,11 @@ type Options struct {
 	// is shorter than the lifetime of your process.
 	EventBroadcaster record.EventBroadcaster
 
+	// EventBroadcasterOptions configures how the event recorders of the cluster record
+	// events, e.g. the rate limits of the event correlator or the sink events are written
+	// to. The correlator options are ignored if EventBroadcaster is set.
+	EventBroadcasterOptions recorder.BroadcasterOptions
+
 	// makeBroadcaster allows deferring the creation of the broadcaster to
 	// avoid leaking goroutines if we never call Start on this manager.  It also
 	// returns whether or not this is a "owned" broadcaster, and as such should be
@@ -136,7 +170,7 @@ type Options struct {
 	makeBroadcaster intrec.EventBroadcasterProducer
 
 	// Dependency injection for testing
-	newRecorderProvider func(config *rest.Config, scheme *runtime.Scheme, logger logr.Logger, makeBroadcaster intrec.EventBroadcasterProducer) (*intrec.Provider, error)
+	newRecorderProvider func(config *rest.Config, scheme *runtime.Scheme, logger logr.Logger, makeBroadcaster intrec.EventBroadcasterProducer, options recorder.BroadcasterOptions) (*intrec.Provider, error)
 }
 
 // Option can be used to manipulate Options.
@@ -166,13 +200,17 @@ func New(config *rest.Config, opts ...Option) (Cluster, error) {
 	if err != nil {
 		return nil, err
 	}
+	if options.LazyCacheStart {
+		cache = newLazyCache(cache, options.Logger)
+	}
 
-	clientOptions := client.Options{Scheme: options.Scheme, Mapper: mapper}
+	clientOptions := client.Options{Scheme: options.Scheme, Mapper: mapper, Opts: options.ClientWarningHandlerOptions, RateLimit: options.ClientRateLimitOptions}
 
-	apiReader, err := client.New(config, clientOptions)
+	apiClient, err := client.New(config, clientOptions)
 	if err != nil {
 		return nil, err
 	}
+	apiReader := client.WithInterceptors(apiClient, options.ClientInterceptors...)
 
 	writeObj, err := options.NewClient(cache, config, clientOptions, options.ClientDisableCacheFor...)
 	if err != nil {
@@ -182,11 +220,12 @@ func New(config *rest.Config, opts ...Option) (Cluster, error) {
 	if options.DryRunClient {
 		writeObj = client.NewDryRunClient(writeObj)
 	}
+	writeObj = client.WithInterceptors(writeObj, options.ClientInterceptors...)
 
 	// Create the recorder provider to inject event recorders for the components.
 	// TODO(directxman12): the log for the event provider should have a context (name, tags, etc) specific
 	// to the particular controller that it's being injected into, rather than a generic one like is here.
-	recorderProvider, err := options.newRecorderProvider(config, options.Scheme, options.Logger.WithName("events"), options.makeBroadcaster)
+	recorderProvider, err := options.newRecorderProvider(config, options.Scheme, options.Logger.WithName("events"), options.makeBroadcaster, options.EventBroadcasterOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -220,6 +259,11 @@ func setOptionsDefaults(options Options) Options {
 	// Allow users to define how to create a new client
 	if options.NewClient == nil {
 		options.NewClient = DefaultNewClient
+		if fallback := options.ClientCacheMissFallback; fallback != nil {
+			options.NewClient = func(cache cache.Cache, config *rest.Config, clientOptions client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+				return newDelegatingClient(cache, config, clientOptions, fallback, uncachedObjects...)
+			}
+		}
 	}
 
 	// Allow newCache to be mocked
@@ -237,7 +281,7 @@ func setOptionsDefaults(options Options) Options {
 	if options.EventBroadcaster == nil {
 		// defer initialization to avoid leaking by default
 		options.makeBroadcaster = func() (record.EventBroadcaster, bool) {
-			return record.NewBroadcaster(), true
+			return record.NewBroadcasterWithCorrelatorOptions(options.EventBroadcasterOptions.CorrelatorOptions), true
 		}
 	} else {
 		options.makeBroadcaster = func() (record.EventBroadcaster, bool) {
@@ -255,16 +299,22 @@ func setOptionsDefaults(options Options) Options {
 // NewClientFunc allows a user to define how to create a client.
 type NewClientFunc func(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error)
 
-// DefaultNewClient creates the default caching client.
+// DefaultNewClient creates the default caching client. It's a client.WithWatch, whose
+// watches are served by the API server.
 func DefaultNewClient(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
-	c, err := client.New(config, options)
+	return newDelegatingClient(cache, config, options, nil, uncachedObjects...)
+}
+
+func newDelegatingClient(cache cache.Cache, config *rest.Config, options client.Options, cacheMissFallback client.CacheMissFallbackPolicy, uncachedObjects ...client.Object) (client.Client, error) {
+	c, err := client.NewWithWatch(config, options)
 	if err != nil {
 		return nil, err
 	}
 
 	return client.NewDelegatingClient(client.NewDelegatingClientInput{
-		CacheReader:     cache,
-		Client:          c,
-		UncachedObjects: uncachedObjects,
+		CacheReader:       cache,
+		Client:            c,
+		UncachedObjects:   uncachedObjects,
+		CacheMissFallback: cacheMissFallback,
 	})
 }
@@ -25,14 +25,17 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/goleak"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
+	"sigs.k8s.io/controller-runtime/pkg/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 )
 
@@ -88,9 +91,40 @@ var _ = Describe("cluster.Cluster", func() {
 			Expect(c.GetClient()).To(BeNil())
 		})
 
+		It("should create a client that's a client.WithWatch, even in dry run mode", func() {
+			c, err := New(cfg)
+			Expect(err).NotTo(HaveOccurred())
+			_, ok := c.GetClient().(client.WithWatch)
+			Expect(ok).To(BeTrue())
+
+			c, err = New(cfg, func(o *Options) { o.DryRunClient = true })
+			Expect(err).NotTo(HaveOccurred())
+			_, ok = c.GetClient().(client.WithWatch)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("should call the ClientInterceptors around the calls of the client and of the APIReader", func() {
+			var verbs []string
+			c, err := New(cfg, func(o *Options) {
+				o.ClientInterceptors = []client.Interceptor{
+					func(ctx context.Context, call client.Call, next func(context.Context) error) error {
+						verbs = append(verbs, call.Verb)
+						return errors.New("intercepted")
+					},
+				}
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			key := client.ObjectKey{Namespace: "default", Name: "foo"}
+			Expect(c.GetClient().Get(context.Background(), key, &corev1.ConfigMap{})).To(MatchError("intercepted"))
+			Expect(c.GetAPIReader().Get(context.Background(), key, &corev1.ConfigMap{})).To(MatchError("intercepted"))
+			Expect(c.GetClient().Delete(context.Background(), &corev1.ConfigMap{})).To(MatchError("intercepted"))
+			Expect(verbs).To(Equal([]string{"get", "get", "delete"}))
+		})
+
 		It("should return an error it can't create a recorder.Provider", func() {
 			c, err := New(cfg, func(o *Options) {
-				o.newRecorderProvider = func(_ *rest.Config, _ *runtime.Scheme, _ logr.Logger, _ intrec.EventBroadcasterProducer) (*intrec.Provider, error) {
+				o.newRecorderProvider = func(_ *rest.Config, _ *runtime.Scheme, _ logr.Logger, _ intrec.EventBroadcasterProducer, _ recorder.BroadcasterOptions) (*intrec.Provider, error) {
 					return nil, fmt.Errorf("expected error")
 				}
 			})
@@ -111,6 +145,59 @@ var _ = Describe("cluster.Cluster", func() {
 		})
 	})
 
+	Describe("LazyCacheStart", func() {
+		var (
+			fake *startRecordingCache
+			c    Cluster
+			ctx  context.Context
+		)
+
+		BeforeEach(func() {
+			fake = &startRecordingCache{FakeInformers: &informertest.FakeInformers{}, started: make(chan struct{})}
+			var err error
+			c, err = New(cfg, func(o *Options) {
+				o.LazyCacheStart = true
+				o.NewCache = func(_ *rest.Config, _ cache.Options) (cache.Cache, error) {
+					return fake, nil
+				}
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(context.Background())
+			DeferCleanup(cancel)
+			go func() {
+				defer GinkgoRecover()
+				Expect(c.Start(ctx)).To(Succeed())
+			}()
+			Expect(c.GetCache().WaitForCacheSync(ctx)).To(BeTrue())
+			Consistently(fake.started).ShouldNot(BeClosed())
+		})
+
+		It("should start the cache once an informer is requested", func() {
+			_, err := c.GetCache().GetInformer(ctx, &corev1.Pod{})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(fake.started).Should(BeClosed())
+		})
+
+		It("should start the cache once an object is read with the client", func() {
+			Expect(c.GetClient().Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, &corev1.Pod{})).To(Succeed())
+			Eventually(fake.started).Should(BeClosed())
+		})
+
+		It("should not wait for the other informers of the cache to sync to read an object", func() {
+			_, err := c.GetCache().GetInformer(ctx, &corev1.ConfigMap{})
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(fake.started).Should(BeClosed())
+
+			read := make(chan error)
+			go func() {
+				read <- c.GetClient().Get(ctx, client.ObjectKey{Namespace: "default", Name: "foo"}, &corev1.Pod{})
+			}()
+			Eventually(read).Should(Receive(BeNil()))
+		})
+	})
+
 	Describe("SetFields", func() {
 		It("should inject field values", func() {
 			c, err := New(cfg, func(o *Options) {
@@ -295,3 +382,31 @@ func (i *injectable) InjectLogger(log logr.Logger) error {
 func (i *injectable) Start(<-chan struct{}) error {
 	return nil
 }
+
+// startRecordingCache is a fake cache that records whether it was started.
+type startRecordingCache struct {
+	*informertest.FakeInformers
+	started chan struct{}
+}
+
+func (c *startRecordingCache) Start(ctx context.Context) error {
+	close(c.started)
+	<-ctx.Done()
+	return nil
+}
+
+// UnsyncedInformers reports whether the cache is started.
+func (c *startRecordingCache) UnsyncedInformers() (bool, []schema.GroupVersionKind) {
+	select {
+	case <-c.started:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// WaitForCacheSync blocks until the context is done, as if an informer never synced.
+func (c *startRecordingCache) WaitForCacheSync(ctx context.Context) bool {
+	<-ctx.Done()
+	return false
+}
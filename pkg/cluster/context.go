@@ -0,0 +1,38 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+)
+
+type clusterKey struct{}
+
+// IntoContext returns a copy of ctx that holds the cluster, e.g. to call a reconciler
+// in tests.
+func IntoContext(ctx context.Context, cl Cluster) context.Context {
+	return context.WithValue(ctx, clusterKey{}, cl)
+}
+
+// FromContext returns the cluster held by ctx, and false if there is none. Controllers
+// pass the cluster of the request to each reconciliation via its context: the engaged
+// cluster named by reconcile.Request.ClusterName, or the cluster of the manager, so that
+// reconcilers of the requests of several clusters use the client of the right one.
+func FromContext(ctx context.Context) (Cluster, bool) {
+	cl, ok := ctx.Value(clusterKey{}).(Cluster)
+	return cl, ok
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+)
+
+const (
+	// defaultKubeconfigSecretKey is the key of the kubeconfig in the Secrets, as in the
+	// kubeconfig Secrets of Cluster API.
+	defaultKubeconfigSecretKey = "value"
+
+	defaultConnectTimeout = time.Minute
+	defaultMinBackoff     = time.Second
+	defaultMaxBackoff     = 5 * time.Minute
+
+	// kubeconfigSecretWorkers is the number of Secrets whose clusters are engaged at the
+	// same time, so that an unreachable cluster doesn't hold up the other ones.
+	kubeconfigSecretWorkers = 4
+)
+
+// KubeconfigSecretProviderOptions are the options of a provider of the clusters whose
+// kubeconfigs are stored in Secrets.
+type KubeconfigSecretProviderOptions struct {
+	// Namespace is the namespace of the Secrets. Defaults to all namespaces.
+	Namespace string
+
+	// LabelSelector selects the Secrets holding kubeconfigs, e.g. the Secrets with the
+	// "cluster.x-k8s.io/cluster-name" label. Defaults to all the Secrets.
+	LabelSelector labels.Selector
+
+	// Key is the key of the kubeconfig in the data of the Secrets. Defaults to "value",
+	// as in the kubeconfig Secrets of Cluster API.
+	Key string
+
+	// ClusterName returns the name the cluster of a Secret is engaged with. Defaults to
+	// the name of the Secret if Namespace is set, or to its namespace and name otherwise.
+	ClusterName func(secret *corev1.Secret) string
+
+	// ClusterOptions are the options the clusters are created with.
+	ClusterOptions []Option
+
+	// ConnectTimeout is how long the cache of a cluster is given to sync when it's
+	// engaged. Defaults to a minute.
+	ConnectTimeout time.Duration
+
+	// MinBackoff and MaxBackoff bound the exponential backoff with which the connection
+	// to a cluster is retried after it failed, e.g. because the cluster is unreachable.
+	// Default to a second and 5 minutes.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Logger is the logger of the provider. Defaults to the cluster logger.
+	Logger logr.Logger
+
+	// newCluster creates the clusters, it's replaced in tests.
+	newCluster func(config *rest.Config, opts ...Option) (Cluster, error)
+}
+
+// NewKubeconfigSecretProvider returns a Provider that engages the clusters whose
+// kubeconfigs are stored in the Secrets of the cluster of the given config, e.g. the
+// workload clusters of Cluster API. A cluster is engaged once its Secret appears,
+// engaged again once its kubeconfig changes, and disengaged once its Secret disappears.
+// The connection to a cluster is retried with an exponential backoff while it fails.
+func NewKubeconfigSecretProvider(config *rest.Config, options KubeconfigSecretProviderOptions) (Provider, error) {
+	if config == nil {
+		return nil, errors.New("must specify Config")
+	}
+	if options.Key == "" {
+		options.Key = defaultKubeconfigSecretKey
+	}
+	if options.ClusterName == nil {
+		if options.Namespace != "" {
+			options.ClusterName = func(secret *corev1.Secret) string { return secret.Name }
+		} else {
+			options.ClusterName = func(secret *corev1.Secret) string { return secret.Namespace + "/" + secret.Name }
+		}
+	}
+	if options.ConnectTimeout <= 0 {
+		options.ConnectTimeout = defaultConnectTimeout
+	}
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = defaultMinBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = defaultMaxBackoff
+	}
+	if options.Logger.GetSink() == nil {
+		options.Logger = logf.RuntimeLog.WithName("cluster").WithName("kubeconfig-secrets")
+	}
+	if options.newCluster == nil {
+		options.newCluster = New
+	}
+	return &kubeconfigSecretProvider{
+		config:  config,
+		options: options,
+		engaged: map[string]engagedSecret{},
+	}, nil
+}
+
+type kubeconfigSecretProvider struct {
+	config  *rest.Config
+	options KubeconfigSecretProviderOptions
+
+	// engaged are the clusters engaged by the key of their Secret.
+	mu      sync.Mutex
+	engaged map[string]engagedSecret
+}
+
+// engagedSecret is a cluster engaged from a Secret.
+type engagedSecret struct {
+	name       string
+	kubeconfig []byte
+}
+
+// Run implements Provider.
+func (p *kubeconfigSecretProvider) Run(ctx context.Context, aware Aware) error {
+	secrets, err := cache.New(p.config, cache.Options{
+		Scheme:    scheme.Scheme,
+		Namespace: p.options.Namespace,
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.Secret{}: {Label: p.options.LabelSelector},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	informer, err := secrets.GetInformer(ctx, &corev1.Secret{})
+	if err != nil {
+		return err
+	}
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(p.options.MinBackoff, p.options.MaxBackoff))
+	enqueue := func(obj interface{}) {
+		key, err := toolscache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			p.options.Logger.Error(err, "unable to get the key of a Secret")
+			return
+		}
+		queue.Add(key)
+	}
+	informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, obj interface{}) { enqueue(obj) },
+		DeleteFunc: enqueue,
+	})
+
+	go func() {
+		if err := secrets.Start(ctx); err != nil {
+			p.options.Logger.Error(err, "unable to watch the kubeconfig Secrets")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		queue.ShutDown()
+	}()
+	if !secrets.WaitForCacheSync(ctx) {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(kubeconfigSecretWorkers)
+	for i := 0; i < kubeconfigSecretWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for p.processNextSecret(ctx, aware, secrets, queue) {
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// processNextSecret engages the cluster of the next Secret of the queue, and returns false
+// once the queue is shut down.
+func (p *kubeconfigSecretProvider) processNextSecret(ctx context.Context, aware Aware, secrets client.Reader, queue workqueue.RateLimitingInterface) bool {
+	item, shutdown := queue.Get()
+	if shutdown {
+		return false
+	}
+	defer queue.Done(item)
+
+	key := item.(string)
+	if err := p.sync(ctx, aware, secrets, key); err != nil {
+		p.options.Logger.Error(err, "unable to engage the cluster of a Secret, retrying", "secret", key, "retries", queue.NumRequeues(item))
+		queue.AddRateLimited(item)
+		return true
+	}
+	queue.Forget(item)
+	return true
+}
+
+// sync engages the cluster of the Secret with the given key, or disengages it if the
+// Secret is gone.
+func (p *kubeconfigSecretProvider) sync(ctx context.Context, aware Aware, secrets client.Reader, key string) error {
+	namespace, name, err := toolscache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{}
+	if err := secrets.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return p.disengage(ctx, aware, key)
+		}
+		return err
+	}
+
+	kubeconfig := secret.Data[p.options.Key]
+	if len(kubeconfig) == 0 {
+		p.options.Logger.Info("Secret has no kubeconfig, ignoring it", "secret", key, "key", p.options.Key)
+		return p.disengage(ctx, aware, key)
+	}
+	clusterName := p.options.ClusterName(secret)
+
+	p.mu.Lock()
+	existing, ok := p.engaged[key]
+	p.mu.Unlock()
+	if ok && existing.name == clusterName && bytes.Equal(existing.kubeconfig, kubeconfig) {
+		return nil
+	}
+	// The cluster is engaged again with the new kubeconfig.
+	if err := p.disengage(ctx, aware, key); err != nil {
+		return err
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to load the kubeconfig of Secret %s: %w", key, err)
+	}
+	cl, err := p.options.newCluster(config, p.options.ClusterOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster %q: %w", clusterName, err)
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, p.options.ConnectTimeout)
+	defer cancel()
+	if err := aware.Engage(connectCtx, clusterName, cl); err != nil {
+		return fmt.Errorf("failed to engage cluster %q: %w", clusterName, err)
+	}
+
+	p.mu.Lock()
+	p.engaged[key] = engagedSecret{name: clusterName, kubeconfig: kubeconfig}
+	p.mu.Unlock()
+	p.options.Logger.Info("Engaged cluster", "cluster", clusterName, "secret", key)
+	return nil
+}
+
+// disengage disengages the cluster of the Secret with the given key, if it's engaged.
+func (p *kubeconfigSecretProvider) disengage(ctx context.Context, aware Aware, key string) error {
+	p.mu.Lock()
+	existing, ok := p.engaged[key]
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	// The cluster is forgotten even if disengaging it fails, e.g. because it stopped
+	// already, so that it's engaged again when the Secret is retried.
+	p.mu.Lock()
+	delete(p.engaged, key)
+	p.mu.Unlock()
+	if err := aware.Disengage(ctx, existing.name); err != nil {
+		return fmt.Errorf("failed to disengage cluster %q: %w", existing.name, err)
+	}
+	p.options.Logger.Info("Disengaged cluster", "cluster", existing.name, "secret", key)
+	return nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("KubeconfigSecretProvider", func() {
+	var (
+		ctx      context.Context
+		secrets  client.Client
+		aware    *recordingAware
+		provider *kubeconfigSecretProvider
+		hosts    []string
+	)
+
+	kubeconfig := func(server string) []byte {
+		return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: member
+  cluster:
+    server: %s
+contexts:
+- name: member
+  context:
+    cluster: member
+current-context: member
+`, server))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		secrets = fake.NewClientBuilder().Build()
+		aware = &recordingAware{}
+		hosts = nil
+		p, err := NewKubeconfigSecretProvider(cfg, KubeconfigSecretProviderOptions{
+			Namespace: "fleet",
+			newCluster: func(config *rest.Config, _ ...Option) (Cluster, error) {
+				hosts = append(hosts, config.Host)
+				return &cluster{config: config}, nil
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		provider = p.(*kubeconfigSecretProvider)
+	})
+
+	It("should engage the cluster of a Secret until it's deleted", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "fleet", Name: "member-kubeconfig"},
+			Data:       map[string][]byte{"value": kubeconfig("https://member-1.example.com")},
+		}
+		Expect(secrets.Create(ctx, secret)).To(Succeed())
+
+		By("engaging the cluster once")
+		Expect(provider.sync(ctx, aware, secrets, "fleet/member-kubeconfig")).To(Succeed())
+		Expect(provider.sync(ctx, aware, secrets, "fleet/member-kubeconfig")).To(Succeed())
+		Expect(aware.calls).To(Equal([]string{"engage member-kubeconfig"}))
+		Expect(hosts).To(Equal([]string{"https://member-1.example.com"}))
+
+		By("engaging the cluster again once its kubeconfig changes")
+		secret.Data["value"] = kubeconfig("https://member-2.example.com")
+		Expect(secrets.Update(ctx, secret)).To(Succeed())
+		Expect(provider.sync(ctx, aware, secrets, "fleet/member-kubeconfig")).To(Succeed())
+		Expect(aware.calls).To(Equal([]string{"engage member-kubeconfig", "disengage member-kubeconfig", "engage member-kubeconfig"}))
+		Expect(hosts).To(Equal([]string{"https://member-1.example.com", "https://member-2.example.com"}))
+
+		By("disengaging the cluster once the Secret is deleted")
+		Expect(secrets.Delete(ctx, secret)).To(Succeed())
+		Expect(provider.sync(ctx, aware, secrets, "fleet/member-kubeconfig")).To(Succeed())
+		Expect(aware.calls).To(HaveLen(4))
+		Expect(aware.calls[3]).To(Equal("disengage member-kubeconfig"))
+	})
+
+	It("should return an error to retry the Secret if the cluster can't be engaged", func() {
+		Expect(secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "fleet", Name: "member-kubeconfig"},
+			Data:       map[string][]byte{"value": kubeconfig("https://member.example.com")},
+		})).To(Succeed())
+
+		aware.engageErr = errors.New("unreachable")
+		Expect(provider.sync(ctx, aware, secrets, "fleet/member-kubeconfig")).To(MatchError(ContainSubstring("unreachable")))
+
+		aware.engageErr = nil
+		Expect(provider.sync(ctx, aware, secrets, "fleet/member-kubeconfig")).To(Succeed())
+		Expect(aware.calls).To(Equal([]string{"engage member-kubeconfig", "engage member-kubeconfig"}))
+	})
+
+	It("should ignore the Secrets without a kubeconfig", func() {
+		Expect(secrets.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "fleet", Name: "other"},
+			Data:       map[string][]byte{"token": []byte("foo")},
+		})).To(Succeed())
+
+		Expect(provider.sync(ctx, aware, secrets, "fleet/other")).To(Succeed())
+		Expect(aware.calls).To(BeEmpty())
+	})
+})
+
+// recordingAware records the clusters it's asked to engage and disengage.
+type recordingAware struct {
+	calls     []string
+	engageErr error
+}
+
+func (a *recordingAware) Engage(_ context.Context, name string, _ Cluster) error {
+	a.calls = append(a.calls, "engage "+name)
+	return a.engageErr
+}
+
+func (a *recordingAware) Disengage(_ context.Context, name string) error {
+	a.calls = append(a.calls, "disengage "+name)
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// startPollInterval is the interval at which a lazyCache checks whether the underlying
+// cache is started, which happens right after it's needed.
+const startPollInterval = 10 * time.Millisecond
+
+// lazyCache defers starting a cache until it's needed, i.e. until an object is read
+// from it or one of its informers is requested, e.g. by a controller watching a kind.
+type lazyCache struct {
+	cache.Cache
+	log logr.Logger
+
+	// started is closed when the cache is started, and needed when it's needed.
+	startOnce sync.Once
+	started   chan struct{}
+	needOnce  sync.Once
+	needed    chan struct{}
+
+	// ready is closed once the underlying cache has been started.
+	readyOnce sync.Once
+	ready     chan struct{}
+}
+
+var _ cache.InformerSyncStatus = &lazyCache{}
+
+// newLazyCache returns the cache wrapped to be started lazily. The cache keeps being
+// a NamespaceScopedCache if it was one.
+func newLazyCache(c cache.Cache, log logr.Logger) cache.Cache {
+	lazy := &lazyCache{
+		Cache:   c,
+		log:     log,
+		started: make(chan struct{}),
+		needed:  make(chan struct{}),
+		ready:   make(chan struct{}),
+	}
+	if namespaced, ok := c.(cache.NamespaceScopedCache); ok {
+		return &lazyNamespaceScopedCache{lazyCache: lazy, NamespaceScopedCache: namespaced}
+	}
+	return lazy
+}
+
+// lazyNamespaceScopedCache is a lazyCache of a NamespaceScopedCache.
+type lazyNamespaceScopedCache struct {
+	*lazyCache
+	cache.NamespaceScopedCache
+}
+
+// Start waits for the cache to be needed before starting it. It blocks until the
+// context is closed.
+func (c *lazyCache) Start(ctx context.Context) error {
+	c.startOnce.Do(func() { close(c.started) })
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-c.needed:
+	}
+	c.log.Info("Starting the cache, as it's needed")
+	return c.Cache.Start(ctx)
+}
+
+// WaitForCacheSync returns right away while the cache isn't needed, as there is
+// nothing to sync.
+func (c *lazyCache) WaitForCacheSync(ctx context.Context) bool {
+	select {
+	case <-c.needed:
+		return c.Cache.WaitForCacheSync(ctx)
+	default:
+		return true
+	}
+}
+
+// Get implements client.Reader.
+func (c *lazyCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.need(ctx)
+	return c.Cache.Get(ctx, key, obj, opts...)
+}
+
+// List implements client.Reader.
+func (c *lazyCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.need(ctx)
+	return c.Cache.List(ctx, list, opts...)
+}
+
+// GetInformer implements cache.Informers.
+func (c *lazyCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	c.need(ctx)
+	return c.Cache.GetInformer(ctx, obj)
+}
+
+// GetInformerForKind implements cache.Informers.
+func (c *lazyCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	c.need(ctx)
+	return c.Cache.GetInformerForKind(ctx, gvk)
+}
+
+// UnsyncedInformers implements cache.InformerSyncStatus. The cache has no informer to
+// sync until it's needed.
+func (c *lazyCache) UnsyncedInformers() (bool, []schema.GroupVersionKind) {
+	select {
+	case <-c.needed:
+	default:
+		select {
+		case <-c.started:
+			return true, nil
+		default:
+			return false, nil
+		}
+	}
+	if status, ok := c.Cache.(cache.InformerSyncStatus); ok {
+		return status.UnsyncedInformers()
+	}
+	return true, nil
+}
+
+// need starts the cache if it wasn't needed yet. If the cache was started already,
+// it waits for the underlying cache to be started, so that reads don't fail because
+// the underlying cache isn't started yet. Otherwise, the reads fail as usual. It doesn't
+// wait for the whole cache to sync, as the informers of the kinds being read wait for
+// their own sync.
+func (c *lazyCache) need(ctx context.Context) {
+	c.needOnce.Do(func() { close(c.needed) })
+
+	select {
+	case <-c.ready:
+		return
+	case <-c.started:
+	default:
+		return
+	}
+	status, ok := c.Cache.(cache.InformerSyncStatus)
+	if !ok {
+		return
+	}
+	if err := wait.PollImmediateUntilWithContext(ctx, startPollInterval, func(context.Context) (bool, error) {
+		started, _ := status.UnsyncedInformers()
+		return started, nil
+	}); err == nil {
+		c.readyOnce.Do(func() { close(c.ready) })
+	}
+}
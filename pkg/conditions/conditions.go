@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StatusConditionsPath is the path of the conditions in the status of objects that
+// follow the API conventions, as used by PatchStatusConditions.
+const StatusConditionsPath = "status.conditions"
+
+// SetCondition sets the condition in conditions, which are the conditions of obj, and
+// returns whether they changed. The ObservedGeneration of the condition is set to the
+// generation of obj. If there is a condition of the same type already, its
+// LastTransitionTime is kept unless its status changes, in which case it is set to the
+// LastTransitionTime of the given condition, or to now if that's unset.
+func SetCondition(obj client.Object, conditions *[]metav1.Condition, cond metav1.Condition) (changed bool) {
+	cond.ObservedGeneration = obj.GetGeneration()
+	if existing := meta.FindStatusCondition(*conditions, cond.Type); existing != nil &&
+		existing.Status == cond.Status &&
+		existing.Reason == cond.Reason &&
+		existing.Message == cond.Message &&
+		existing.ObservedGeneration == cond.ObservedGeneration {
+		return false
+	}
+	meta.SetStatusCondition(conditions, cond)
+	return true
+}
+
+// SetConditionUnstructured sets the condition in the conditions of the unstructured
+// object at the given dot-separated path, such as StatusConditionsPath, like
+// SetCondition, and returns whether they changed.
+func SetConditionUnstructured(u *unstructured.Unstructured, path string, cond metav1.Condition) (changed bool, err error) {
+	fields := strings.Split(path, ".")
+	rawConditions, _, err := unstructured.NestedSlice(u.Object, fields...)
+	if err != nil {
+		return false, err
+	}
+	conditions := make([]metav1.Condition, len(rawConditions))
+	for i, rawCondition := range rawConditions {
+		content, ok := rawCondition.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("%s[%d] is of the type %T, expected map[string]interface{}", path, i, rawCondition)
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &conditions[i]); err != nil {
+			return false, fmt.Errorf("%s[%d] is not a condition: %w", path, i, err)
+		}
+	}
+
+	if !SetCondition(u, &conditions, cond) {
+		return false, nil
+	}
+
+	rawConditions = make([]interface{}, len(conditions))
+	for i := range conditions {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err != nil {
+			return false, err
+		}
+		rawConditions[i] = content
+	}
+	if err := unstructured.SetNestedSlice(u.Object, rawConditions, fields...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// IsTrue returns whether the condition of the given type is present and true.
+func IsTrue(conditions []metav1.Condition, conditionType string) bool {
+	return meta.IsStatusConditionTrue(conditions, conditionType)
+}
+
+// GetReason returns the reason of the condition of the given type, or an empty
+// string if it's not present.
+func GetReason(conditions []metav1.Condition, conditionType string) string {
+	if cond := meta.FindStatusCondition(conditions, conditionType); cond != nil {
+		return cond.Reason
+	}
+	return ""
+}
+
+// PatchStatusConditions sets the given conditions in the conditions at
+// StatusConditionsPath of the object, and persists only the conditions with a patch of
+// the status subresource if they changed. The conditions are replaced as a whole, so the
+// patch fails with a conflict if the object was modified since it was read. The object
+// can be typed or unstructured, and reflects the persisted state afterwards.
+func PatchStatusConditions(ctx context.Context, c client.Client, obj client.Object, conds ...metav1.Condition) error {
+	base := obj.DeepCopyObject().(client.Object)
+
+	u, isUnstructured := obj.(*unstructured.Unstructured)
+	if !isUnstructured {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		u = &unstructured.Unstructured{Object: content}
+	}
+
+	changed := false
+	for _, cond := range conds {
+		condChanged, err := SetConditionUnstructured(u, StatusConditionsPath, cond)
+		if err != nil {
+			return err
+		}
+		changed = changed || condChanged
+	}
+	if !changed {
+		return nil
+	}
+
+	if !isUnstructured {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+			return err
+		}
+	}
+	return c.Status().Patch(ctx, obj, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/conditions"
+)
+
+var _ = Describe("SetCondition", func() {
+	var (
+		pdb   *policyv1.PodDisruptionBudget
+		then  metav1.Time
+		ready metav1.Condition
+	)
+
+	BeforeEach(func() {
+		pdb = &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "default", Generation: 2}}
+		then = metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		ready = metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Available", Message: "ready", LastTransitionTime: then}
+	})
+
+	It("should add the condition with the generation of the object", func() {
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeTrue())
+		Expect(pdb.Status.Conditions).To(HaveLen(1))
+		Expect(pdb.Status.Conditions[0].ObservedGeneration).To(BeEquivalentTo(2))
+		Expect(pdb.Status.Conditions[0].LastTransitionTime).To(Equal(then))
+	})
+
+	It("should default the LastTransitionTime of a new condition to now", func() {
+		ready.LastTransitionTime = metav1.Time{}
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeTrue())
+		Expect(pdb.Status.Conditions[0].LastTransitionTime.Time).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	It("should report no change if the condition is the same", func() {
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeTrue())
+		ready.LastTransitionTime = metav1.Now()
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeFalse())
+		Expect(pdb.Status.Conditions[0].LastTransitionTime).To(Equal(then))
+	})
+
+	It("should keep the LastTransitionTime if the status didn't change", func() {
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeTrue())
+
+		pdb.Generation = 3
+		ready.Reason = "StillAvailable"
+		ready.Message = "still ready"
+		ready.LastTransitionTime = metav1.Time{}
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeTrue())
+		Expect(pdb.Status.Conditions).To(HaveLen(1))
+		Expect(pdb.Status.Conditions[0].Reason).To(Equal("StillAvailable"))
+		Expect(pdb.Status.Conditions[0].Message).To(Equal("still ready"))
+		Expect(pdb.Status.Conditions[0].ObservedGeneration).To(BeEquivalentTo(3))
+		Expect(pdb.Status.Conditions[0].LastTransitionTime).To(Equal(then))
+	})
+
+	It("should bump the LastTransitionTime if the status changed", func() {
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeTrue())
+
+		By("defaulting it to now")
+		notReady := metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Unavailable"}
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, notReady)).To(BeTrue())
+		Expect(pdb.Status.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+		Expect(pdb.Status.Conditions[0].LastTransitionTime.Time).To(BeTemporally("~", time.Now(), time.Minute))
+
+		By("using the given one")
+		ready.LastTransitionTime = metav1.NewTime(then.Add(time.Minute))
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeTrue())
+		Expect(pdb.Status.Conditions[0].LastTransitionTime).To(Equal(ready.LastTransitionTime))
+	})
+
+	It("should leave the conditions of other types alone", func() {
+		progressing := metav1.Condition{Type: "Progressing", Status: metav1.ConditionFalse, Reason: "Done", LastTransitionTime: then}
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, progressing)).To(BeTrue())
+		Expect(conditions.SetCondition(pdb, &pdb.Status.Conditions, ready)).To(BeTrue())
+		Expect(pdb.Status.Conditions).To(HaveLen(2))
+		Expect(conditions.IsTrue(pdb.Status.Conditions, "Ready")).To(BeTrue())
+		Expect(conditions.IsTrue(pdb.Status.Conditions, "Progressing")).To(BeFalse())
+		Expect(conditions.IsTrue(pdb.Status.Conditions, "Unknown")).To(BeFalse())
+		Expect(conditions.GetReason(pdb.Status.Conditions, "Progressing")).To(Equal("Done"))
+		Expect(conditions.GetReason(pdb.Status.Conditions, "Unknown")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("SetConditionUnstructured", func() {
+	var (
+		u    *unstructured.Unstructured
+		then metav1.Time
+	)
+
+	BeforeEach(func() {
+		u = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Example",
+			"metadata":   map[string]interface{}{"name": "example", "generation": int64(4)},
+		}}
+		then = metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	})
+
+	It("should set the condition at the path and keep the LastTransitionTime if the status didn't change", func() {
+		ready := metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Available", LastTransitionTime: then}
+		Expect(conditions.SetConditionUnstructured(u, "status.health.conditions", ready)).To(BeTrue())
+		Expect(conditions.SetConditionUnstructured(u, "status.health.conditions", ready)).To(BeFalse())
+
+		ready.Reason = "StillAvailable"
+		ready.LastTransitionTime = metav1.Now()
+		Expect(conditions.SetConditionUnstructured(u, "status.health.conditions", ready)).To(BeTrue())
+
+		raw, found, err := unstructured.NestedSlice(u.Object, "status", "health", "conditions")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(raw).To(ConsistOf(map[string]interface{}{
+			"type":               "Ready",
+			"status":             "True",
+			"reason":             "StillAvailable",
+			"message":            "",
+			"observedGeneration": int64(4),
+			"lastTransitionTime": then.UTC().Format(time.RFC3339),
+		}))
+	})
+
+	It("should fail if the path doesn't contain conditions", func() {
+		Expect(unstructured.SetNestedField(u.Object, "invalid", "status", "conditions")).To(Succeed())
+		_, err := conditions.SetConditionUnstructured(u, conditions.StatusConditionsPath, metav1.Condition{Type: "Ready"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("PatchStatusConditions", func() {
+	var (
+		c   client.Client
+		pdb *policyv1.PodDisruptionBudget
+	)
+
+	BeforeEach(func() {
+		pdb = &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "default", Generation: 1}}
+		c = fake.NewClientBuilder().WithObjects(pdb.DeepCopy()).Build()
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(pdb), pdb)).To(Succeed())
+	})
+
+	It("should persist only the conditions", func() {
+		pdb.Status.DisruptionsAllowed = 3
+		pdb.Labels = map[string]string{"not": "persisted"}
+		Expect(conditions.PatchStatusConditions(context.TODO(), c, pdb,
+			metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Available"},
+			metav1.Condition{Type: "Progressing", Status: metav1.ConditionFalse, Reason: "Done"},
+		)).To(Succeed())
+
+		fetched := &policyv1.PodDisruptionBudget{}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(pdb), fetched)).To(Succeed())
+		Expect(conditions.IsTrue(fetched.Status.Conditions, "Ready")).To(BeTrue())
+		Expect(conditions.GetReason(fetched.Status.Conditions, "Progressing")).To(Equal("Done"))
+		Expect(fetched.Status.Conditions[0].ObservedGeneration).To(BeEquivalentTo(1))
+		Expect(fetched.Status.DisruptionsAllowed).To(BeZero())
+		Expect(fetched.Labels).To(BeEmpty())
+		Expect(fetched.ResourceVersion).To(Equal(pdb.ResourceVersion))
+	})
+
+	It("should not patch if the conditions didn't change", func() {
+		ready := metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Available"}
+		Expect(conditions.PatchStatusConditions(context.TODO(), c, pdb, ready)).To(Succeed())
+		resourceVersion := pdb.ResourceVersion
+		Expect(conditions.PatchStatusConditions(context.TODO(), c, pdb, ready)).To(Succeed())
+		Expect(pdb.ResourceVersion).To(Equal(resourceVersion))
+	})
+
+	It("should fail with a conflict if the object was modified since it was read", func() {
+		other := pdb.DeepCopy()
+		other.Labels = map[string]string{"modified": "concurrently"}
+		Expect(c.Update(context.TODO(), other)).To(Succeed())
+
+		err := conditions.PatchStatusConditions(context.TODO(), c, pdb, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Available"})
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+	})
+
+	It("should persist the conditions of unstructured objects", func() {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"))
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(pdb), u)).To(Succeed())
+		Expect(conditions.PatchStatusConditions(context.TODO(), c, u, metav1.Condition{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Unavailable"})).To(Succeed())
+
+		fetched := &policyv1.PodDisruptionBudget{}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(pdb), fetched)).To(Succeed())
+		Expect(conditions.GetReason(fetched.Status.Conditions, "Ready")).To(Equal("Unavailable"))
+	})
+})
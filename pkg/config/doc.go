@@ -22,4 +22,10 @@ limitations under the License.
 // This uses a deferred file decoding allowing you to chain your configuration
 // setup. You can pass this into manager.Options#File and it will load your
 // config.
+//
+// # Loader
+//
+// The loader package loads manager.Options from a versioned configuration
+// file, with strict decoding and validation, overlaying the values of the file
+// onto programmatic defaults.
 package config
@@ -0,0 +1,312 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loader loads the Options of a manager from a configuration file, e.g. a
+// mounted ConfigMap, instead of an ever-growing list of flags:
+//
+//	apiVersion: controller-runtime.sigs.k8s.io/v1
+//	kind: ManagerConfiguration
+//	leaderElection:
+//	  enabled: true
+//	  id: my-operator
+//	cache:
+//	  namespaces: [team-a, team-b]
+//	controllers:
+//	  ReplicaSet.apps:
+//	    maxConcurrentReconciles: 5
+//
+// The values of the file are overlaid onto base Options holding the programmatic
+// defaults: a value set in the file replaces the value of the base, while the values
+// that aren't set in the file are kept. To give command line flags precedence over the
+// file, apply the flags that were set to the returned Options.
+package loader
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// LoadOptions loads the configuration file at path, and overlays its values onto base.
+func LoadOptions(path string, base manager.Options) (manager.Options, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return base, err
+	}
+	return cfg.ApplyTo(base)
+}
+
+// Load reads, defaults and validates the configuration file at path. Unknown and
+// duplicate fields are rejected.
+func Load(path string) (*Configuration, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+	cfg := &Configuration{}
+	if err := yaml.UnmarshalStrict(content, cfg); err != nil {
+		return nil, fmt.Errorf("could not decode config file %s: %w", path, err)
+	}
+	cfg.Default()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Default sets the apiVersion and kind of the configuration if they're not set.
+func (c *Configuration) Default() {
+	if c.APIVersion == "" && c.Kind == "" {
+		c.APIVersion = APIVersion
+		c.Kind = Kind
+	}
+}
+
+// Validate returns an error listing the invalid fields of the configuration by their
+// YAML path.
+func (c *Configuration) Validate() error {
+	var errs field.ErrorList
+	if c.APIVersion != APIVersion {
+		errs = append(errs, field.NotSupported(field.NewPath("apiVersion"), c.APIVersion, []string{APIVersion}))
+	}
+	if c.Kind != Kind {
+		errs = append(errs, field.NotSupported(field.NewPath("kind"), c.Kind, []string{Kind}))
+	}
+
+	if le := c.LeaderElection; le != nil {
+		path := field.NewPath("leaderElection")
+		errs = append(errs, validatePositive(path.Child("leaseDuration"), le.LeaseDuration)...)
+		errs = append(errs, validatePositive(path.Child("renewDeadline"), le.RenewDeadline)...)
+		errs = append(errs, validatePositive(path.Child("retryPeriod"), le.RetryPeriod)...)
+		if le.LeaseDuration != nil && le.RenewDeadline != nil && le.RenewDeadline.Duration >= le.LeaseDuration.Duration {
+			errs = append(errs, field.Invalid(path.Child("renewDeadline"), le.RenewDeadline.Duration.String(), "must be less than leaseDuration"))
+		}
+		if le.RenewDeadline != nil && le.RetryPeriod != nil && le.RetryPeriod.Duration >= le.RenewDeadline.Duration {
+			errs = append(errs, field.Invalid(path.Child("retryPeriod"), le.RetryPeriod.Duration.String(), "must be less than renewDeadline"))
+		}
+	}
+
+	if ca := c.Cache; ca != nil {
+		path := field.NewPath("cache")
+		errs = append(errs, validatePositive(path.Child("syncPeriod"), ca.SyncPeriod)...)
+		seen := map[string]bool{}
+		for i, ns := range ca.Namespaces {
+			for _, msg := range validation.IsDNS1123Label(ns) {
+				errs = append(errs, field.Invalid(path.Child("namespaces").Index(i), ns, msg))
+			}
+			if seen[ns] {
+				errs = append(errs, field.Duplicate(path.Child("namespaces").Index(i), ns))
+			}
+			seen[ns] = true
+		}
+		if _, err := labels.Parse(ca.LabelSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("labelSelector"), ca.LabelSelector, err.Error()))
+		}
+		if _, err := fields.ParseSelector(ca.FieldSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("fieldSelector"), ca.FieldSelector, err.Error()))
+		}
+	}
+
+	if wh := c.Webhook; wh != nil && wh.Port != nil {
+		for _, msg := range validation.IsValidPortNum(*wh.Port) {
+			errs = append(errs, field.Invalid(field.NewPath("webhook", "port"), *wh.Port, msg))
+		}
+	}
+
+	if c.Controller != nil {
+		errs = append(errs, validatePositive(field.NewPath("controller", "cacheSyncTimeout"), c.Controller.CacheSyncTimeout)...)
+		if total := c.Controller.MaxConcurrentReconcilesTotal; total != nil && *total < 1 {
+			errs = append(errs, field.Invalid(field.NewPath("controller", "maxConcurrentReconcilesTotal"), *total, "must be at least 1"))
+		}
+	}
+
+	for key, override := range c.Controllers {
+		path := field.NewPath("controllers").Key(key)
+		if schema.ParseGroupKind(key).Kind == "" {
+			errs = append(errs, field.Invalid(path, key, "must be a group kind, e.g. ReplicaSet.apps"))
+		}
+		if override.MaxConcurrentReconciles != nil && *override.MaxConcurrentReconciles < 1 {
+			errs = append(errs, field.Invalid(path.Child("maxConcurrentReconciles"), *override.MaxConcurrentReconciles, "must be at least 1"))
+		}
+	}
+
+	return errs.ToAggregate()
+}
+
+func validatePositive(path *field.Path, d *metav1.Duration) field.ErrorList {
+	if d != nil && d.Duration <= 0 {
+		return field.ErrorList{field.Invalid(path, d.Duration.String(), "must be positive")}
+	}
+	return nil
+}
+
+// ApplyTo returns a copy of base with the values that are set in the configuration.
+// Per-controller overrides are added to the GroupKindConcurrency of base.
+func (c *Configuration) ApplyTo(base manager.Options) (manager.Options, error) {
+	o := base
+
+	if le := c.LeaderElection; le != nil {
+		if le.Enabled != nil {
+			o.LeaderElection = *le.Enabled
+		}
+		if le.ResourceLock != "" {
+			o.LeaderElectionResourceLock = le.ResourceLock
+		}
+		if le.Namespace != "" {
+			o.LeaderElectionNamespace = le.Namespace
+		}
+		if le.ID != "" {
+			o.LeaderElectionID = le.ID
+		}
+		if le.LeaseDuration != nil {
+			o.LeaseDuration = durationPtr(le.LeaseDuration.Duration)
+		}
+		if le.RenewDeadline != nil {
+			o.RenewDeadline = durationPtr(le.RenewDeadline.Duration)
+		}
+		if le.RetryPeriod != nil {
+			o.RetryPeriod = durationPtr(le.RetryPeriod.Duration)
+		}
+		if le.ReleaseOnCancel != nil {
+			o.LeaderElectionReleaseOnCancel = *le.ReleaseOnCancel
+		}
+	}
+
+	if ca := c.Cache; ca != nil {
+		if ca.SyncPeriod != nil {
+			o.SyncPeriod = durationPtr(ca.SyncPeriod.Duration)
+		}
+		if err := applyCache(ca, &o); err != nil {
+			return base, err
+		}
+	}
+
+	if c.Metrics != nil && c.Metrics.BindAddress != "" {
+		o.MetricsBindAddress = c.Metrics.BindAddress
+	}
+
+	if h := c.Health; h != nil {
+		if h.BindAddress != "" {
+			o.HealthProbeBindAddress = h.BindAddress
+		}
+		if h.ReadinessEndpointName != "" {
+			o.ReadinessEndpointName = h.ReadinessEndpointName
+		}
+		if h.LivenessEndpointName != "" {
+			o.LivenessEndpointName = h.LivenessEndpointName
+		}
+	}
+
+	if wh := c.Webhook; wh != nil {
+		if wh.Port != nil {
+			o.Port = *wh.Port
+		}
+		if wh.Host != "" {
+			o.Host = wh.Host
+		}
+		if wh.CertDir != "" {
+			o.CertDir = wh.CertDir
+		}
+	}
+
+	if c.GracefulShutdownTimeout != nil {
+		o.GracefulShutdownTimeout = durationPtr(c.GracefulShutdownTimeout.Duration)
+	}
+
+	if c.Controller != nil && c.Controller.CacheSyncTimeout != nil {
+		o.Controller.CacheSyncTimeout = durationPtr(c.Controller.CacheSyncTimeout.Duration)
+	}
+	if c.Controller != nil && c.Controller.MaxConcurrentReconcilesTotal != nil {
+		o.Controller.MaxConcurrentReconcilesTotal = *c.Controller.MaxConcurrentReconcilesTotal
+	}
+
+	if len(c.Controllers) > 0 {
+		concurrency := make(map[string]int, len(base.Controller.GroupKindConcurrency)+len(c.Controllers))
+		for groupKind, n := range base.Controller.GroupKindConcurrency {
+			concurrency[groupKind] = n
+		}
+		for groupKind, override := range c.Controllers {
+			if override.MaxConcurrentReconciles != nil {
+				concurrency[groupKind] = *override.MaxConcurrentReconciles
+			}
+		}
+		o.Controller.GroupKindConcurrency = concurrency
+	}
+
+	return o, nil
+}
+
+// applyCache sets the namespaces and selectors of the cache configuration on o. A single
+// namespace is set as Namespace, while several namespaces replace NewCache with a
+// multi-namespace cache.
+func applyCache(ca *Cache, o *manager.Options) error {
+	selector := cache.ObjectSelector{}
+	if ca.LabelSelector != "" {
+		sel, err := labels.Parse(ca.LabelSelector)
+		if err != nil {
+			return err
+		}
+		selector.Label = sel
+	}
+	if ca.FieldSelector != "" {
+		sel, err := fields.ParseSelector(ca.FieldSelector)
+		if err != nil {
+			return err
+		}
+		selector.Field = sel
+	}
+
+	newCache := o.NewCache
+	switch len(ca.Namespaces) {
+	case 0:
+	case 1:
+		o.Namespace = ca.Namespaces[0]
+	default:
+		o.Namespace = ""
+		newCache = cache.MultiNamespacedCacheBuilder(ca.Namespaces)
+	}
+	if newCache == nil {
+		newCache = cache.New
+	}
+
+	if selector.Label == nil && selector.Field == nil {
+		if len(ca.Namespaces) > 1 {
+			o.NewCache = newCache
+		}
+		return nil
+	}
+	o.NewCache = func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		opts.DefaultSelector = selector
+		return newCache(config, opts)
+	}
+	return nil
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/controller-runtime/pkg/config/loader"
+	"sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var _ = Describe("loader", func() {
+	duration := func(d time.Duration) *time.Duration { return &d }
+
+	// writeConfig writes the configuration to a file and returns its path.
+	writeConfig := func(content string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+
+	Describe("LoadOptions", func() {
+		It("should overlay the values of the file onto the base options", func() {
+			base := manager.Options{
+				LeaderElectionID:   "base-id",
+				MetricsBindAddress: ":9090",
+				Namespace:          "base",
+				Port:               9443,
+				Host:               "base-host",
+				Controller: v1alpha1.ControllerConfigurationSpec{
+					GroupKindConcurrency: map[string]int{"Pod": 3, "ReplicaSet.apps": 1},
+				},
+			}
+
+			o, err := loader.LoadOptions("./testdata/config.yaml", base)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(o.LeaderElection).To(BeTrue())
+			Expect(o.LeaderElectionResourceLock).To(Equal("leases"))
+			Expect(o.LeaderElectionNamespace).To(Equal("operators"))
+			Expect(o.LeaderElectionID).To(Equal("my-operator"))
+			Expect(o.LeaseDuration).To(Equal(duration(30 * time.Second)))
+			Expect(o.RenewDeadline).To(Equal(duration(20 * time.Second)))
+			Expect(o.RetryPeriod).To(Equal(duration(5 * time.Second)))
+			Expect(o.LeaderElectionReleaseOnCancel).To(BeTrue())
+			Expect(o.SyncPeriod).To(Equal(duration(time.Hour)))
+			Expect(o.Namespace).To(BeEmpty())
+			Expect(o.NewCache).NotTo(BeNil())
+			Expect(o.MetricsBindAddress).To(Equal(":8081"))
+			Expect(o.HealthProbeBindAddress).To(Equal(":8082"))
+			Expect(o.ReadinessEndpointName).To(Equal("ready"))
+			Expect(o.LivenessEndpointName).To(BeEmpty())
+			Expect(o.Port).To(Equal(9444))
+			Expect(o.CertDir).To(Equal("/certs"))
+			Expect(o.GracefulShutdownTimeout).To(Equal(duration(45 * time.Second)))
+			Expect(o.Controller.CacheSyncTimeout).To(Equal(duration(5 * time.Minute)))
+			Expect(o.Controller.MaxConcurrentReconcilesTotal).To(Equal(10))
+
+			By("keeping the base values that aren't set in the file")
+			Expect(o.Host).To(Equal("base-host"))
+			Expect(o.Controller.GroupKindConcurrency).To(Equal(map[string]int{"Pod": 3, "ReplicaSet.apps": 5, "ConfigMap": 2}))
+			Expect(base.Controller.GroupKindConcurrency).To(Equal(map[string]int{"Pod": 3, "ReplicaSet.apps": 1}))
+		})
+
+		It("should set a single namespace as the namespace of the cache", func() {
+			path := writeConfig(`
+cache:
+  namespaces: [team-a]
+`)
+			o, err := loader.LoadOptions(path, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o.Namespace).To(Equal("team-a"))
+			Expect(o.NewCache).To(BeNil())
+		})
+
+		It("should return the base options if the file can't be loaded", func() {
+			base := manager.Options{LeaderElectionID: "base-id"}
+			o, err := loader.LoadOptions("./testdata/missing.yaml", base)
+			Expect(err).To(HaveOccurred())
+			Expect(o.LeaderElectionID).To(Equal("base-id"))
+		})
+	})
+
+	Describe("Load", func() {
+		It("should round-trip the configuration file", func() {
+			cfg, err := loader.Load("./testdata/config.yaml")
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := yaml.Marshal(cfg)
+			Expect(err).NotTo(HaveOccurred())
+			roundTripped, err := loader.Load(writeConfig(string(content)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(roundTripped).To(Equal(cfg))
+		})
+
+		It("should default the apiVersion and kind", func() {
+			cfg, err := loader.Load(writeConfig("metrics:\n  bindAddress: \"0\"\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.APIVersion).To(Equal(loader.APIVersion))
+			Expect(cfg.Kind).To(Equal(loader.Kind))
+		})
+
+		It("should reject unknown fields", func() {
+			_, err := loader.Load(writeConfig(`
+leaderElection:
+  enabled: true
+  leaderElect: true
+`))
+			Expect(err).To(MatchError(ContainSubstring(`unknown field "leaderElect"`)))
+		})
+
+		It("should reject other versions of the configuration", func() {
+			_, err := loader.Load(writeConfig(`
+apiVersion: controller-runtime.sigs.k8s.io/v1alpha1
+kind: ControllerManagerConfiguration
+`))
+			Expect(err).To(MatchError(ContainSubstring(`apiVersion: Unsupported value: "controller-runtime.sigs.k8s.io/v1alpha1"`)))
+			Expect(err).To(MatchError(ContainSubstring(`kind: Unsupported value: "ControllerManagerConfiguration"`)))
+		})
+
+		It("should name the YAML path of invalid fields", func() {
+			_, err := loader.Load(writeConfig(`
+leaderElection:
+  leaseDuration: 10s
+  renewDeadline: 15s
+cache:
+  namespaces: [team-a, Team_B, team-a]
+  labelSelector: "app in (foo"
+webhook:
+  port: 70000
+controller:
+  cacheSyncTimeout: 0s
+  maxConcurrentReconcilesTotal: 0
+controllers:
+  ReplicaSet.apps:
+    maxConcurrentReconciles: 0
+`))
+			Expect(err).To(HaveOccurred())
+			for _, path := range []string{
+				"leaderElection.renewDeadline",
+				"cache.namespaces[1]",
+				"cache.namespaces[2]: Duplicate value",
+				"cache.labelSelector",
+				"webhook.port",
+				"controller.cacheSyncTimeout",
+				"controller.maxConcurrentReconcilesTotal",
+				"controllers[ReplicaSet.apps].maxConcurrentReconciles",
+			} {
+				Expect(err.Error()).To(ContainSubstring(path))
+			}
+		})
+	})
+})
@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// APIVersion is the version of the configuration file format.
+	APIVersion = "controller-runtime.sigs.k8s.io/v1"
+	// Kind is the kind of the configuration file format.
+	Kind = "ManagerConfiguration"
+)
+
+// Configuration is the format of the configuration file of a manager. All the fields
+// are optional, and the fields that are not set keep the value of the base Options.
+type Configuration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// LeaderElection configures the leader election of the manager.
+	// +optional
+	LeaderElection *LeaderElection `json:"leaderElection,omitempty"`
+
+	// Cache configures the cache of the manager.
+	// +optional
+	Cache *Cache `json:"cache,omitempty"`
+
+	// Metrics configures the metrics endpoint of the manager.
+	// +optional
+	Metrics *Metrics `json:"metrics,omitempty"`
+
+	// Health configures the health probes of the manager.
+	// +optional
+	Health *Health `json:"health,omitempty"`
+
+	// Webhook configures the webhook server of the manager.
+	// +optional
+	Webhook *Webhook `json:"webhook,omitempty"`
+
+	// GracefulShutdownTimeout is the duration given to runnables to stop before the
+	// manager returns. A negative duration waits without timeout.
+	// +optional
+	GracefulShutdownTimeout *metav1.Duration `json:"gracefulShutdownTimeout,omitempty"`
+
+	// Controller configures the defaults of the controllers of the manager.
+	// +optional
+	Controller *Controller `json:"controller,omitempty"`
+
+	// Controllers overrides the configuration of individual controllers, keyed by the
+	// group kind of the object they reconcile, e.g. `ReplicaSet.apps`, which is how
+	// controllers created with the builder are matched.
+	// +optional
+	Controllers map[string]ControllerOverride `json:"controllers,omitempty"`
+}
+
+// LeaderElection configures the leader election of the manager.
+type LeaderElection struct {
+	// Enabled enables leader election.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// ResourceLock is the type of the resource used as lock, e.g. `leases`.
+	// +optional
+	ResourceLock string `json:"resourceLock,omitempty"`
+
+	// Namespace is the namespace of the lock.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// ID is the name of the lock.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// LeaseDuration is the duration non-leaders wait to force acquire leadership.
+	// +optional
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+
+	// RenewDeadline is the duration the leader retries refreshing leadership before
+	// giving it up. It must be less than LeaseDuration.
+	// +optional
+	RenewDeadline *metav1.Duration `json:"renewDeadline,omitempty"`
+
+	// RetryPeriod is the duration clients wait between tries of actions. It must be
+	// less than RenewDeadline.
+	// +optional
+	RetryPeriod *metav1.Duration `json:"retryPeriod,omitempty"`
+
+	// ReleaseOnCancel makes the leader step down when the manager is stopped.
+	// +optional
+	ReleaseOnCancel *bool `json:"releaseOnCancel,omitempty"`
+}
+
+// Cache configures the cache of the manager.
+type Cache struct {
+	// SyncPeriod is the minimum frequency at which watched resources are reconciled.
+	// +optional
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+
+	// Namespaces restricts the cache to the given namespaces.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// LabelSelector restricts the cache to the objects matching the label selector.
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// FieldSelector restricts the cache to the objects matching the field selector.
+	// +optional
+	FieldSelector string `json:"fieldSelector,omitempty"`
+}
+
+// Metrics configures the metrics endpoint of the manager.
+type Metrics struct {
+	// BindAddress is the address the metrics endpoint binds to, or "0" to disable it.
+	// +optional
+	BindAddress string `json:"bindAddress,omitempty"`
+}
+
+// Health configures the health probes of the manager.
+type Health struct {
+	// BindAddress is the address the health probes bind to, or "0" to disable them.
+	// +optional
+	BindAddress string `json:"bindAddress,omitempty"`
+
+	// ReadinessEndpointName is the path of the readiness probe.
+	// +optional
+	ReadinessEndpointName string `json:"readinessEndpointName,omitempty"`
+
+	// LivenessEndpointName is the path of the liveness probe.
+	// +optional
+	LivenessEndpointName string `json:"livenessEndpointName,omitempty"`
+}
+
+// Webhook configures the webhook server of the manager.
+type Webhook struct {
+	// Port is the port the webhook server serves at.
+	// +optional
+	Port *int `json:"port,omitempty"`
+
+	// Host is the hostname the webhook server binds to.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// CertDir is the directory that contains the server key and certificate.
+	// +optional
+	CertDir string `json:"certDir,omitempty"`
+}
+
+// Controller configures the defaults of the controllers of the manager.
+type Controller struct {
+	// CacheSyncTimeout is the time limit set to wait for syncing caches.
+	// +optional
+	CacheSyncTimeout *metav1.Duration `json:"cacheSyncTimeout,omitempty"`
+
+	// MaxConcurrentReconcilesTotal caps the total number of concurrent reconciliations of
+	// the controllers of the manager.
+	// +optional
+	MaxConcurrentReconcilesTotal *int `json:"maxConcurrentReconcilesTotal,omitempty"`
+}
+
+// ControllerOverride configures an individual controller.
+type ControllerOverride struct {
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciliations of
+	// the controller.
+	// +optional
+	MaxConcurrentReconciles *int `json:"maxConcurrentReconciles,omitempty"`
+}
@@ -94,6 +94,21 @@ type ControllerConfigurationSpec struct {
 	// Defaults to 2 minutes if not set.
 	// +optional
 	CacheSyncTimeout *time.Duration `json:"cacheSyncTimeout,omitempty"`
+
+	// MaxConcurrentReconcilesTotal caps the total number of concurrent reconciliations of
+	// the controllers of the manager, on top of the MaxConcurrentReconciles of each
+	// controller. Workers wait for a free slot in the order they requested it, so that
+	// a controller with a large backlog doesn't starve the others.
+	// Defaults to 0, i.e. no cap.
+	// +optional
+	MaxConcurrentReconcilesTotal int `json:"maxConcurrentReconcilesTotal,omitempty"`
+
+	// SkipNameValidation allows skipping the name validation that ensures that every controller
+	// name is unique within the process, e.g. for tests that create controllers with the same
+	// name in different managers. Unique names are important to tell the logs and metrics of
+	// the controllers apart. Defaults to false.
+	// +optional
+	SkipNameValidation *bool `json:"skipNameValidation,omitempty"`
 }
 
 // ControllerMetrics defines the metrics configs.
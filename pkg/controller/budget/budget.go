@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package budget defines a budget of concurrent reconciliations shared by
+// several controllers, which caps their total concurrency on top of the
+// MaxConcurrentReconciles of each controller.
+package budget
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+	internalmetrics "sigs.k8s.io/controller-runtime/pkg/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var log = logf.RuntimeLog.WithName("budget")
+
+var (
+	// defaultVecs holds the metrics of the budgets that report into metrics.Registry.
+	defaultVecs = newBudgetVecs()
+
+	// vecsByRegistry holds the metrics of the budgets by the registry they report into.
+	vecsByRegistry = func() *internalmetrics.PerRegistry[*budgetVecs] {
+		p := internalmetrics.NewPerRegistry(func(reg prometheus.Registerer) *budgetVecs {
+			v := newBudgetVecs()
+			reg.MustRegister(v.capacity, v.inUse, v.waiting)
+			return v
+		})
+		// The metrics of metrics.Registry are registered in init.
+		p.Set(metrics.Registry, defaultVecs)
+		return p
+	}()
+)
+
+// budgetVecs are the metric vectors of the budgets that report into a registry.
+type budgetVecs struct {
+	capacity *prometheus.GaugeVec
+	inUse    *prometheus.GaugeVec
+	waiting  *prometheus.GaugeVec
+}
+
+func newBudgetVecs() *budgetVecs {
+	return &budgetVecs{
+		capacity: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "controller_runtime_reconcile_budget_capacity",
+			Help: "Maximum number of concurrent reconciles of the controllers sharing a budget",
+		}, []string{"budget"}),
+		inUse: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "controller_runtime_reconcile_budget_in_use",
+			Help: "Number of concurrent reconciles of the controllers sharing a budget",
+		}, []string{"budget"}),
+		waiting: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "controller_runtime_reconcile_budget_waiting",
+			Help: "Number of workers of the controllers sharing a budget waiting for a reconcile slot",
+		}, []string{"budget"}),
+	}
+}
+
+func init() {
+	metrics.Registry.MustRegister(defaultVecs.capacity, defaultVecs.inUse, defaultVecs.waiting)
+}
+
+// Budget is a semaphore limiting the number of concurrent reconciliations of the
+// controllers sharing it. The workers of the controllers acquire a slot of the budget
+// before each reconciliation and release it afterwards.
+//
+// Slots are handed out in the order they were requested, regardless of the controller
+// requesting them. As a controller requests at most one slot per worker, a controller
+// with a large backlog doesn't starve the others.
+type Budget struct {
+	name     string
+	capacity int
+
+	mu    sync.Mutex
+	inUse int
+	// waiters are the channels of the workers waiting for a slot, in the order they
+	// requested it. A channel is closed once the slot it requested is handed over.
+	waiters list.List
+
+	inUseGauge   prometheus.Gauge
+	waitingGauge prometheus.Gauge
+}
+
+// New returns a budget of the given number of concurrent reconciliations, whose metrics
+// report into metrics.Registry. The name is the value of the budget label of its metrics.
+func New(name string, capacity int) (*Budget, error) {
+	return NewForRegistry(nil, name, capacity)
+}
+
+// NewForRegistry is like New, but the metrics of the budget report into the given registry,
+// e.g. the one of a manager. The registry defaults to metrics.Registry.
+func NewForRegistry(reg prometheus.Registerer, name string, capacity int) (*Budget, error) {
+	if capacity < 1 {
+		return nil, fmt.Errorf("capacity of budget %q must be at least 1, got %d", name, capacity)
+	}
+	if reg == nil {
+		reg = metrics.Registry
+	}
+	vecs := vecsByRegistry.For(reg)
+	vecs.capacity.WithLabelValues(name).Set(float64(capacity))
+	b := &Budget{
+		name:         name,
+		capacity:     capacity,
+		inUseGauge:   vecs.inUse.WithLabelValues(name),
+		waitingGauge: vecs.waiting.WithLabelValues(name),
+	}
+	b.inUseGauge.Set(0)
+	b.waitingGauge.Set(0)
+	return b, nil
+}
+
+// Name returns the name of the budget.
+func (b *Budget) Name() string {
+	return b.name
+}
+
+// Capacity returns the maximum number of concurrent reconciliations.
+func (b *Budget) Capacity() int {
+	return b.capacity
+}
+
+// InUse returns the number of slots that are acquired.
+func (b *Budget) InUse() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.inUse
+}
+
+// Acquire blocks until a slot is available and acquires it, or returns the error of
+// the context if it's done first.
+func (b *Budget) Acquire(ctx context.Context) error {
+	b.mu.Lock()
+	if b.inUse < b.capacity && b.waiters.Len() == 0 {
+		b.inUse++
+		b.inUseGauge.Inc()
+		b.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	elem := b.waiters.PushBack(ready)
+	b.waitingGauge.Inc()
+	b.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		select {
+		case <-ready:
+			// The slot was handed over concurrently, so give it back.
+			b.mu.Unlock()
+			b.Release()
+		default:
+			b.waiters.Remove(elem)
+			b.waitingGauge.Dec()
+			b.mu.Unlock()
+		}
+		return ctx.Err()
+	}
+}
+
+// Release releases a slot acquired with Acquire, and hands it over to the worker that
+// has been waiting the longest, if any. Releasing more slots than were acquired is a bug
+// of the caller, which is logged and ignored.
+func (b *Budget) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if front := b.waiters.Front(); front != nil {
+		b.waiters.Remove(front)
+		b.waitingGauge.Dec()
+		close(front.Value.(chan struct{}))
+		return
+	}
+	if b.inUse == 0 {
+		log.Error(nil, "Released more slots than were acquired, ignoring", "budget", b.name)
+		return
+	}
+	b.inUse--
+	b.inUseGauge.Dec()
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package budget
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Budget", func() {
+	It("should reject a capacity below 1", func() {
+		_, err := New("invalid", 0)
+		Expect(err).To(MatchError(`capacity of budget "invalid" must be at least 1, got 0`))
+	})
+
+	It("should hand out the released slots in the order they were requested", func() {
+		b, err := New("fifo", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Acquire(context.Background())).To(Succeed())
+
+		acquired := make(chan int, 3)
+		for i := 0; i < 3; i++ {
+			go func(i int) {
+				defer GinkgoRecover()
+				Expect(b.Acquire(context.Background())).To(Succeed())
+				acquired <- i
+			}(i)
+			// Wait for the worker to queue up before starting the next one.
+			Eventually(func() float64 { return testutil.ToFloat64(b.waitingGauge) }).Should(BeEquivalentTo(i + 1))
+		}
+
+		for i := 0; i < 3; i++ {
+			Consistently(acquired, "50ms").ShouldNot(Receive())
+			b.Release()
+			Eventually(acquired).Should(Receive(Equal(i)))
+		}
+		Expect(b.InUse()).To(Equal(1))
+		b.Release()
+		Expect(b.InUse()).To(Equal(0))
+	})
+
+	It("should stop waiting once the context is done", func() {
+		b, err := New("cancel", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Acquire(context.Background())).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- b.Acquire(ctx)
+		}()
+		Eventually(func() float64 { return testutil.ToFloat64(b.waitingGauge) }).Should(BeEquivalentTo(1))
+		cancel()
+		Eventually(done).Should(Receive(MatchError(context.Canceled)))
+		Expect(testutil.ToFloat64(b.waitingGauge)).To(BeEquivalentTo(0))
+
+		b.Release()
+		Expect(b.InUse()).To(Equal(0))
+	})
+
+	It("should never exceed its capacity", func() {
+		b, err := New("load", 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(testutil.ToFloat64(defaultVecs.capacity.WithLabelValues("load"))).To(BeEquivalentTo(3))
+
+		var inFlight, maxInFlight int64
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					Expect(b.Acquire(context.Background())).To(Succeed())
+					n := atomic.AddInt64(&inFlight, 1)
+					for {
+						m := atomic.LoadInt64(&maxInFlight)
+						if n <= m || atomic.CompareAndSwapInt64(&maxInFlight, m, n) {
+							break
+						}
+					}
+					Expect(testutil.ToFloat64(b.inUseGauge)).To(BeNumerically("<=", 3))
+					atomic.AddInt64(&inFlight, -1)
+					b.Release()
+				}
+			}()
+		}
+		wg.Wait()
+		Expect(maxInFlight).To(BeNumerically("<=", 3))
+		Expect(b.InUse()).To(Equal(0))
+		Expect(testutil.ToFloat64(b.inUseGauge)).To(BeEquivalentTo(0))
+	})
+
+	It("should ignore the release of more slots than were acquired", func() {
+		b, err := New("over-release", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Release).NotTo(Panic())
+		Expect(b.InUse()).To(Equal(0))
+		Expect(testutil.ToFloat64(b.inUseGauge)).To(BeEquivalentTo(0))
+
+		Expect(b.Acquire(context.Background())).To(Succeed())
+		Expect(b.InUse()).To(Equal(1))
+	})
+
+	It("should report into the given registry", func() {
+		reg := prometheus.NewRegistry()
+		b, err := NewForRegistry(reg, "registry", 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(b.Acquire(context.Background())).To(Succeed())
+
+		Expect(testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP controller_runtime_reconcile_budget_capacity Maximum number of concurrent reconciles of the controllers sharing a budget
+# TYPE controller_runtime_reconcile_budget_capacity gauge
+controller_runtime_reconcile_budget_capacity{budget="registry"} 2
+# HELP controller_runtime_reconcile_budget_in_use Number of concurrent reconciles of the controllers sharing a budget
+# TYPE controller_runtime_reconcile_budget_in_use gauge
+controller_runtime_reconcile_budget_in_use{budget="registry"} 1
+`), "controller_runtime_reconcile_budget_capacity", "controller_runtime_reconcile_budget_in_use")).To(Succeed())
+		Expect(testutil.ToFloat64(defaultVecs.capacity.WithLabelValues("registry"))).To(BeEquivalentTo(0))
+	})
+})
@@ -0,0 +1,238 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// memoryCluster is a cluster whose cache consists of fake informers of config maps.
+type memoryCluster struct {
+	cluster.Cluster
+	cache     *informertest.FakeInformers
+	informer  *controllertest.FakeInformer
+	stoppedCh chan struct{}
+}
+
+func newMemoryCluster() *memoryCluster {
+	c := &memoryCluster{cache: &informertest.FakeInformers{}, stoppedCh: make(chan struct{})}
+	// The informer is created upfront, as the fake informers aren't safe for concurrent use.
+	informer, err := c.cache.FakeInformerFor(&corev1.ConfigMap{})
+	Expect(err).NotTo(HaveOccurred())
+	c.informer = informer
+	return c
+}
+
+func (c *memoryCluster) GetCache() cache.Cache {
+	return c.cache
+}
+
+func (c *memoryCluster) Start(ctx context.Context) error {
+	<-ctx.Done()
+	close(c.stoppedCh)
+	return nil
+}
+
+// addConfigMap emits an add event of a config map with the given name.
+func (c *memoryCluster) addConfigMap(name string) {
+	c.informer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}})
+}
+
+// awareProvider hands out the cluster.Aware it's run with, to engage and disengage
+// clusters from the specs.
+type awareProvider struct {
+	aware chan cluster.Aware
+}
+
+func (p *awareProvider) Run(ctx context.Context, aware cluster.Aware) error {
+	p.aware <- aware
+	<-ctx.Done()
+	return nil
+}
+
+var _ = Describe("controller with engaged clusters", func() {
+	var (
+		ctx        context.Context
+		cancel     context.CancelFunc
+		mgr        manager.Manager
+		aware      cluster.Aware
+		reconciled chan reconcile.Request
+		// blocked blocks the reconciliation of requests for objects named "block" until it's closed.
+		blocked chan struct{}
+		// clusters are the clusters passed to the reconciliations, by request.
+		clustersMu sync.Mutex
+		clusters   map[reconcile.Request]cluster.Cluster
+	)
+
+	request := func(clusterName, name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}, ClusterName: clusterName}
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+
+		provider := &awareProvider{aware: make(chan cluster.Aware, 1)}
+		var err error
+		mgr, err = manager.New(cfg, manager.Options{
+			MetricsBindAddress: "0",
+			ClusterProvider:    provider,
+			Controller:         v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		reconciled = make(chan reconcile.Request, 10)
+		blocked = make(chan struct{})
+		clusters = map[reconcile.Request]cluster.Cluster{}
+		c, err := controller.New("multi-cluster", mgr, controller.Options{
+			Reconciler: reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				if req.Name == "block" {
+					<-blocked
+				}
+				if cl, ok := cluster.FromContext(ctx); ok {
+					clustersMu.Lock()
+					clusters[req] = cl
+					clustersMu.Unlock()
+				}
+				reconciled <- req
+				return reconcile.Result{}, nil
+			}),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Watch(source.ClusterKind(&corev1.ConfigMap{}), &handler.EnqueueRequestForObject{})).To(Succeed())
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(mgr.Start(ctx)).To(Succeed())
+		}()
+		Eventually(provider.aware).Should(Receive(&aware))
+	})
+
+	It("should reconcile the clusters while they're engaged", func() {
+		a, b := newMemoryCluster(), newMemoryCluster()
+		Expect(aware.Engage(ctx, "a", a)).To(Succeed())
+		Expect(aware.Engage(ctx, "b", b)).To(Succeed())
+		Expect(aware.Engage(ctx, "b", b)).NotTo(Succeed())
+
+		a.addConfigMap("foo")
+		Eventually(reconciled).Should(Receive(Equal(request("a", "foo"))))
+		b.addConfigMap("foo")
+		Eventually(reconciled).Should(Receive(Equal(request("b", "foo"))))
+
+		cl, err := mgr.GetCluster("a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cl).To(BeIdenticalTo(a))
+		cl, err = mgr.GetCluster("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cl.GetConfig()).To(Equal(mgr.GetConfig()))
+
+		By("disengaging a cluster")
+		Expect(aware.Disengage(ctx, "a")).To(Succeed())
+		Expect(a.stoppedCh).To(BeClosed())
+		_, err = mgr.GetCluster("a")
+		Expect(err).To(HaveOccurred())
+
+		a.addConfigMap("bar")
+		b.addConfigMap("bar")
+		Eventually(reconciled).Should(Receive(Equal(request("b", "bar"))))
+		Consistently(reconciled, "100ms").ShouldNot(Receive())
+
+		By("engaging a cluster with the same name again")
+		a2 := newMemoryCluster()
+		Expect(aware.Engage(ctx, "a", a2)).To(Succeed())
+		a2.addConfigMap("baz")
+		Eventually(reconciled).Should(Receive(Equal(request("a", "baz"))))
+	})
+
+	It("should pass the cluster of each request to its reconciliation", func() {
+		a, b := newMemoryCluster(), newMemoryCluster()
+		Expect(aware.Engage(ctx, "a", a)).To(Succeed())
+		Expect(aware.Engage(ctx, "b", b)).To(Succeed())
+
+		a.addConfigMap("foo")
+		Eventually(reconciled).Should(Receive(Equal(request("a", "foo"))))
+		b.addConfigMap("foo")
+		Eventually(reconciled).Should(Receive(Equal(request("b", "foo"))))
+
+		clustersMu.Lock()
+		defer clustersMu.Unlock()
+		Expect(clusters[request("a", "foo")]).To(BeIdenticalTo(a))
+		Expect(clusters[request("b", "foo")]).To(BeIdenticalTo(b))
+	})
+
+	It("should drop the queued requests of a disengaged cluster", func() {
+		a, b := newMemoryCluster(), newMemoryCluster()
+		Expect(aware.Engage(ctx, "a", a)).To(Succeed())
+		Expect(aware.Engage(ctx, "b", b)).To(Succeed())
+
+		By("queueing a request of a while the only worker is busy with b")
+		b.addConfigMap("block")
+		a.addConfigMap("foo")
+		Expect(aware.Disengage(ctx, "a")).To(Succeed())
+
+		close(blocked)
+		Eventually(reconciled).Should(Receive(Equal(request("b", "block"))))
+		Consistently(reconciled, "100ms").ShouldNot(Receive())
+	})
+
+	It("should drop the queued requests of a disengaged cluster that is engaged again", func() {
+		a, b := newMemoryCluster(), newMemoryCluster()
+		Expect(aware.Engage(ctx, "a", a)).To(Succeed())
+		Expect(aware.Engage(ctx, "b", b)).To(Succeed())
+
+		By("queueing a request of a while the only worker is busy with b")
+		b.addConfigMap("block")
+		a.addConfigMap("foo")
+		Expect(aware.Disengage(ctx, "a")).To(Succeed())
+		Expect(aware.Engage(ctx, "a", newMemoryCluster())).To(Succeed())
+
+		close(blocked)
+		Eventually(reconciled).Should(Receive(Equal(request("b", "block"))))
+		Consistently(reconciled, "100ms").ShouldNot(Receive())
+	})
+
+	It("should reject engaging a cluster without a name and disengaging an unknown one", func() {
+		Expect(mgr.Engage(ctx, "", newMemoryCluster())).NotTo(Succeed())
+		Expect(mgr.Disengage(ctx, "unknown")).NotTo(Succeed())
+	})
+
+	It("should stop the engaged clusters with the manager", func() {
+		a := newMemoryCluster()
+		Expect(aware.Engage(ctx, "a", a)).To(Succeed())
+		cancel()
+		Eventually(a.stoppedCh).Should(BeClosed())
+	})
+})
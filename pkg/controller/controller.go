@@ -22,11 +22,18 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog/v2"
 
+	"sigs.k8s.io/controller-runtime/pkg/controller/budget"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/internal/controller"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
@@ -35,48 +42,252 @@ import (
 )
 
 // Options are the arguments for creating a new Controller.
-type Options struct {
+type Options = TypedOptions[reconcile.Request]
+
+// TypedOptions are the arguments for creating a new TypedController.
+type TypedOptions[request comparable] struct {
 	// MaxConcurrentReconciles is the maximum number of concurrent Reconciles which can be run. Defaults to 1.
 	MaxConcurrentReconciles int
 
 	// Reconciler reconciles an object
-	Reconciler reconcile.Reconciler
+	Reconciler reconcile.TypedReconciler[request]
+
+	// ReconcilerMiddleware are wrapped around the Reconciler, e.g. the middlewares of package
+	// reconcile/middleware, to layer cross-cutting concerns such as logging or tracing around
+	// each reconciliation. The first middleware is the outermost one, as with reconcile.Chain.
+	// They are applied once, when the controller is created, and run with the context the
+	// controller passes to the Reconciler. Their panics are recovered if RecoverPanic is set.
+	ReconcilerMiddleware []func(reconcile.TypedReconciler[request]) reconcile.TypedReconciler[request]
 
 	// RateLimiter is used to limit how frequently requests may be queued.
 	// Defaults to MaxOfRateLimiter which has both overall and per-item rate limiting.
 	// The overall is a token bucket and the per-item is exponential.
 	RateLimiter ratelimiter.RateLimiter
 
+	// NeedWarmup starts the sources of the controller and waits for their caches to sync once the
+	// manager's caches are synced, before leader election, rather than when the controller is
+	// started. A newly elected leader then reconciles right away, with the requests queued while
+	// it was a standby.
+	NeedWarmup bool
+
+	// ShardedWorkers hashes the key of each request to one of the MaxConcurrentReconciles workers,
+	// which processes the requests of its keys in the order they are handed out by the queue. The
+	// queue never hands out a request while the same request is being processed, so even without
+	// sharding the reconciliations of a key never overlap, but they may run on any worker; with
+	// sharding, a key sticks to a worker, e.g. for reconcilers keeping per-worker state. A worker
+	// busy with a slow reconciliation holds up the other keys of its shard. The workers of a
+	// started controller with sharded workers can't be resized with SetMaxConcurrentReconciles.
+	ShardedWorkers bool
+
+	// MaxBatchSize is the maximum number of requests handed to a single call of ReconcileBatch if the
+	// Reconciler implements reconcile.TypedBatchReconciler. Each worker then takes the requests that
+	// are queued, up to MaxBatchSize, and reconciles them with a single call of ReconcileBatch instead
+	// of calling Reconcile for each of them. The ReconcilerMiddleware doesn't apply to the batches,
+	// which can't be used with ShardedWorkers. Defaults to 100.
+	MaxBatchSize int
+
+	// NewQueue constructs the queue of the controller once it's started, given the name of
+	// the controller and its RateLimiter. If the queue implements priorityqueue.PriorityQueue,
+	// the controller hands out requests by priority, see UsePriorityQueue.
+	// Defaults to a workqueue.NewNamedRateLimitingQueue, or to a priorityqueue.New if
+	// UsePriorityQueue is set.
+	NewQueue func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface
+
+	// UsePriorityQueue makes the controller use a priority queue, which hands out requests
+	// with a higher priority first. Requests are queued at most once, with the highest of the
+	// priorities they were added with. The handlers of package handler add the requests of
+	// resyncs with handler.LowPriority, so that they don't delay the requests of changes,
+	// and Result.Priority sets the priority of requeues. It is ignored if NewQueue is set.
+	UsePriorityQueue bool
+
+	// SkipNameValidation allows skipping the name validation that ensures that every controller
+	// name is unique within the process, e.g. for tests that create several managers with the
+	// same controllers. Defaults to the SkipNameValidation of the controller options of the
+	// manager, and to false if unset.
+	SkipNameValidation *bool
+
 	// LogConstructor is used to construct a logger used for this controller and passed
 	// to each reconciliation via the context field.
-	LogConstructor func(request *reconcile.Request) logr.Logger
+	// The default adds the name of the controller, the group and kind of GroupKind if set,
+	// and the reference, namespace and name of the request, limited to the fields configured
+	// with log.SetReconcileLogFields. Requests of a custom type are logged as is under the
+	// "request" key.
+	LogConstructor func(req *request) logr.Logger
+
+	// GroupKind is the group and kind of the objects reconciled by the controller. The default
+	// LogConstructor logs them as controllerGroup and controllerKind, and logs the reference
+	// of the requests under the kind instead of "object". The builder sets it to the group and
+	// kind of the For object.
+	GroupKind schema.GroupKind
 
 	// CacheSyncTimeout refers to the time limit set to wait for syncing caches.
 	// Defaults to 2 minutes if not set.
 	CacheSyncTimeout time.Duration
 
 	// RecoverPanic indicates whether the panic caused by reconcile should be recovered.
+	// A recovered panic is handled like an error returned by the reconciler, i.e. the request
+	// is requeued with backoff and the worker continues with the next request. Panics are
+	// counted by the controller_runtime_reconcile_panics_total metric either way.
 	RecoverPanic bool
+
+	// OnPanic, if set, is called with each panic recovered when RecoverPanic is set, along with
+	// the context and request of the reconciliation, e.g. to record an event with the recorder
+	// from EventRecorderFromContext. It must not panic.
+	OnPanic func(ctx context.Context, req request, recovered interface{})
+
+	// MaxRetries is the number of times a request is requeued after the Reconciler returns an error,
+	// before the request is dropped, counted by the controller_runtime_reconcile_dead_letters_total
+	// metric and handed to the DeadLetterHandler, e.g. to stop retrying a poisoned key forever.
+	// A request is then reconciled at most MaxRetries+1 times in a row. Its retries are reset once
+	// it's reconciled without an error, and are counted by the RateLimiter, so a custom
+	// RateLimiter must count them for MaxRetries to take effect. Requeues requested through the
+	// Result are not retries. Defaults to 0, i.e. requests are retried forever.
+	MaxRetries int
+
+	// DeadLetterHandler, if set, is called with the requests that are dropped after exceeding
+	// MaxRetries, along with the context of their last reconciliation and the error it returned,
+	// e.g. to record an event with the recorder from EventRecorderFromContext. The request is
+	// reconciled again the next time it's queued, e.g. on the next change of its object.
+	DeadLetterHandler func(ctx context.Context, req request, err error)
+
+	// OnStartedWorkers, if set, is called once the controller has started its workers, with the
+	// context the controller was started with, e.g. to set up the resources used by the
+	// Reconciler, such as connection pools or watchers of external systems. It is not called if
+	// the controller fails to start.
+	OnStartedWorkers func(ctx context.Context)
+
+	// OnStoppedWorkers, if set, is called once the context the controller was started with is done
+	// and all its workers are finished, i.e. no reconciliation is running anymore, with that
+	// context. It is called only if OnStartedWorkers would have been, e.g. to tear down the
+	// resources set up by it.
+	OnStoppedWorkers func(ctx context.Context)
+
+	// ReconcileTimeout, if set, cancels the context passed to each reconciliation after the given
+	// duration. Reconciliations that exceed it are counted by the
+	// controller_runtime_reconcile_timeouts_total metric and logged, and their Result and error
+	// are handled as usual, so a reconciler that returns the error of its context is retried.
+	// Defaults to 0, i.e. no timeout.
+	ReconcileTimeout time.Duration
+
+	// ReconcileExemplar enables recording exemplars with the reconcile time histogram, e.g. to link
+	// slow reconciliations to their traces. It is called with the context of each reconciliation and
+	// returns the labels of the exemplar, such as {"trace_id": "..."}, or false to record no exemplar.
+	// Exemplars are only exposed if the metrics endpoint is scraped in the OpenMetrics format.
+	ReconcileExemplar func(ctx context.Context) (prometheus.Labels, bool)
+
+	// MetricLabels are extra labels added to all metrics of the controller, including the
+	// metrics of its workqueue, e.g. to tell apart shards of a controller running in different
+	// deployments. At most 5 labels are allowed, their values must be valid Kubernetes label
+	// values and their names must not conflict with the labels the metrics already have.
+	MetricLabels map[string]string
+
+	// RequeueJitter randomly perturbs each Result.RequeueAfter by up to the given fraction of
+	// its value in either direction, e.g. 0.2 turns a RequeueAfter of 10 minutes into a delay
+	// between 8 and 12 minutes. This prevents objects requeued on the same interval from
+	// synchronizing over time. Immediate requeues through Result.Requeue are not affected.
+	// Must be between 0 and 1. Defaults to 0, i.e. no jitter.
+	RequeueJitter float64
+
+	// DebounceWindow delays the requests enqueued by the EventHandlers by the given duration, so that
+	// the events of an object within the window, e.g. the frequent updates of a scaled workload,
+	// collapse into a single reconciliation. The requeues requested by the Reconciler are not delayed.
+	// Defaults to 0, i.e. no delay.
+	DebounceWindow time.Duration
+
+	// EventRecorderName is the component name of the events recorded with the recorder passed
+	// to each reconciliation via the context, see EventRecorderFromContext.
+	// Defaults to the name of the controller.
+	EventRecorderName string
+
+	// AnnotateEventsWithReconcileID adds the ID of the reconciliation to the annotations of the
+	// events recorded with the recorder from the context, under the
+	// "controller-runtime.sigs.k8s.io/reconcile-id" key.
+	AnnotateEventsWithReconcileID bool
+
+	// ReconcileBudget is a budget of concurrent reconciliations shared with other controllers,
+	// which each worker acquires a slot of before reconciling. It defaults to the budget of the
+	// manager if its Options.Controller.MaxConcurrentReconcilesTotal is set.
+	ReconcileBudget *budget.Budget
+
+	// AcquireSlot, if set, is called by each worker before reconciling, after acquiring a slot of the
+	// ReconcileBudget, to plug in limits shared across controllers or processes, e.g. a semaphore or
+	// the quota of a cloud API. The release function it returns is called once the reconciliation is
+	// done. If it returns an error, e.g. as the quota is exhausted, the request is requeued with
+	// backoff without being reconciled. A batch of requests of a BatchReconciler takes a single slot.
+	AcquireSlot func(ctx context.Context) (release func(), err error)
+}
+
+// QueueStatus is a snapshot of the queue of a controller and of the requests being reconciled.
+type QueueStatus = controller.QueueStatus
+
+// InFlightRequest is a request being reconciled by a controller.
+type InFlightRequest = controller.InFlightRequest
+
+// reconcileBudgetProvider is implemented by managers whose controllers share a budget of
+// concurrent reconciliations.
+type reconcileBudgetProvider interface {
+	GetReconcileBudget() *budget.Budget
 }
 
 // Controller implements a Kubernetes API.  A Controller manages a work queue fed reconcile.Requests
 // from source.Sources.  Work is performed through the reconcile.Reconciler for each enqueued item.
 // Work typically is reads and writes Kubernetes objects to make the system state match the state specified
 // in the object Spec.
-type Controller interface {
+type Controller = TypedController[reconcile.Request]
+
+// TypedController is a Controller of requests of a custom type, e.g. to reconcile external
+// resources keyed by their ARN. Its EventHandlers must enqueue requests of that type, e.g.
+// with handler.TypedEnqueueRequestsFromMapFunc, as the queue items of other types are dropped.
+type TypedController[request comparable] interface {
 	// Reconciler is called to reconcile an object by Namespace/Name
-	reconcile.Reconciler
+	reconcile.TypedReconciler[request]
 
 	// Watch takes events provided by a Source and uses the EventHandler to
-	// enqueue reconcile.Requests in response to the events.
+	// enqueue requests in response to the events.
 	//
 	// Watch may be provided one or more Predicates to filter events before
 	// they are given to the EventHandler.  Events will be passed to the
 	// EventHandler if all provided Predicates evaluate to true.
 	Watch(src source.Source, eventhandler handler.EventHandler, predicates ...predicate.Predicate) error
 
+	// Unwatch removes the watches of the given Source, which must be the value passed to Watch,
+	// e.g. to stop watching a type whose CustomResourceDefinition was deleted. The Source is
+	// stopped by cancelling the context it was started with, and the events it delivers
+	// afterwards are dropped. It returns an error if the Source isn't watched.
+	//
+	// The informers of the shared cache that the Source got its events from are not removed,
+	// as they may be used by other controllers and clients.
+	Unwatch(src source.Source) error
+
+	// SetMaxConcurrentReconciles sets the maximum number of concurrent Reconciles, e.g. on a
+	// config reload. Workers are added or removed right away if the controller is running.
+	// A removed worker completes its reconciliation in flight, if any, or the reconciliation
+	// of the next request it was waiting for.
+	SetMaxConcurrentReconciles(n int) error
+
+	// Pause stops the controller from starting new reconciliations, e.g. during a maintenance
+	// window, until Resume is called. The reconciliations in flight complete, and the sources
+	// keep running, so the requests are queued and reconciled once the controller is resumed.
+	// A controller can be paused before it's started.
+	Pause()
+
+	// Resume resumes a paused controller.
+	Resume()
+
+	// IsPaused returns whether the controller is paused.
+	IsPaused() bool
+
+	// QueueStatus returns a snapshot of the queue of the controller and of the requests being
+	// reconciled, e.g. to diagnose a stuck controller. The queue is empty until the controller
+	// is started.
+	QueueStatus() QueueStatus
+
 	// Start starts the controller.  Start blocks until the context is closed or a
 	// controller has an error starting.
+	//
+	// Once the context is closed and Start returned, the controller can be started again
+	// with a new context, which starts its watches again with a new queue, e.g. to engage
+	// and disengage the controller as clusters come and go.
 	Start(ctx context.Context) error
 
 	// GetLogger returns this controller logger prefilled with basic information.
@@ -98,6 +309,23 @@ func New(name string, mgr manager.Manager, options Options) (Controller, error)
 // NewUnmanaged returns a new controller without adding it to the manager. The
 // caller is responsible for starting the returned controller.
 func NewUnmanaged(name string, mgr manager.Manager, options Options) (Controller, error) {
+	return NewTypedUnmanaged(name, mgr, options)
+}
+
+// NewTyped returns a new TypedController registered with the Manager, see New.
+func NewTyped[request comparable](name string, mgr manager.Manager, options TypedOptions[request]) (TypedController[request], error) {
+	c, err := NewTypedUnmanaged(name, mgr, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add the controller as a Manager components
+	return c, mgr.Add(c)
+}
+
+// NewTypedUnmanaged returns a new TypedController without adding it to the manager, see
+// NewUnmanaged.
+func NewTypedUnmanaged[request comparable](name string, mgr manager.Manager, options TypedOptions[request]) (TypedController[request], error) {
 	if options.Reconciler == nil {
 		return nil, fmt.Errorf("must specify Reconciler")
 	}
@@ -107,18 +335,29 @@ func NewUnmanaged(name string, mgr manager.Manager, options Options) (Controller
 	}
 
 	if options.LogConstructor == nil {
-		log := mgr.GetLogger().WithValues(
-			"controller", name,
-		)
-		options.LogConstructor = func(req *reconcile.Request) logr.Logger {
+		log := mgr.GetLogger()
+		if logf.HasReconcileLogField(logf.ReconcileLogFieldController) {
+			log = log.WithValues("controller", name)
+		}
+		objectKey := "object"
+		if !options.GroupKind.Empty() {
+			if logf.HasReconcileLogField(logf.ReconcileLogFieldControllerGroup) {
+				log = log.WithValues("controllerGroup", options.GroupKind.Group)
+			}
+			if logf.HasReconcileLogField(logf.ReconcileLogFieldControllerKind) {
+				log = log.WithValues("controllerKind", options.GroupKind.Kind)
+			}
+			objectKey = options.GroupKind.Kind
+		}
+		options.LogConstructor = func(req *request) logr.Logger {
 			log := log
-			if req != nil {
-				log = log.WithValues(
-					"object", klog.KRef(req.Namespace, req.Name),
-					"namespace", req.Namespace, "name", req.Name,
-				)
+			if req == nil {
+				return log
 			}
-			return log
+			if r, ok := any(*req).(reconcile.Request); ok {
+				return log.WithValues(controller.RequestLogValues(objectKey, r)...)
+			}
+			return log.WithValues("request", *req)
 		}
 	}
 
@@ -130,26 +369,138 @@ func NewUnmanaged(name string, mgr manager.Manager, options Options) (Controller
 		options.CacheSyncTimeout = 2 * time.Minute
 	}
 
+	if options.ReconcileTimeout < 0 {
+		return nil, fmt.Errorf("ReconcileTimeout must not be negative, got %v", options.ReconcileTimeout)
+	}
+
+	if options.MaxRetries < 0 {
+		return nil, fmt.Errorf("MaxRetries must not be negative, got %d", options.MaxRetries)
+	}
+
+	if options.DebounceWindow < 0 {
+		return nil, fmt.Errorf("DebounceWindow must not be negative, got %v", options.DebounceWindow)
+	}
+
+	if options.MaxBatchSize < 0 {
+		return nil, fmt.Errorf("MaxBatchSize must not be negative, got %d", options.MaxBatchSize)
+	}
+
+	batchReconciler, _ := options.Reconciler.(reconcile.TypedBatchReconciler[request])
+	if batchReconciler != nil && options.ShardedWorkers {
+		return nil, fmt.Errorf("ShardedWorkers can't be used with a batch reconciler")
+	}
+
+	if options.MaxBatchSize == 0 {
+		options.MaxBatchSize = 100
+	}
+
+	if options.RequeueJitter < 0 || options.RequeueJitter > 1 {
+		return nil, fmt.Errorf("RequeueJitter must be between 0 and 1, got %v", options.RequeueJitter)
+	}
+
+	if err := ctrlmetrics.ValidateExtraLabels(options.MetricLabels); err != nil {
+		return nil, fmt.Errorf("invalid MetricLabels: %w", err)
+	}
+
 	if options.RateLimiter == nil {
 		options.RateLimiter = workqueue.DefaultControllerRateLimiter()
 	}
 
+	if options.NewQueue == nil {
+		options.NewQueue = func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+			if options.UsePriorityQueue {
+				return priorityqueue.New(controllerName, priorityqueue.Options{RateLimiter: rateLimiter})
+			}
+			return workqueue.NewNamedRateLimitingQueue(rateLimiter, controllerName)
+		}
+	}
+
+	if options.EventRecorderName == "" {
+		options.EventRecorderName = name
+	}
+
+	if options.ReconcileBudget == nil {
+		if provider, ok := mgr.(reconcileBudgetProvider); ok {
+			options.ReconcileBudget = provider.GetReconcileBudget()
+		}
+	}
+
 	// Inject dependencies into Reconciler
 	if err := mgr.SetFields(options.Reconciler); err != nil {
 		return nil, err
 	}
 
+	reconciler := options.Reconciler
+	for i := len(options.ReconcilerMiddleware) - 1; i >= 0; i-- {
+		reconciler = options.ReconcilerMiddleware[i](reconciler)
+	}
+
+	metrics, err := ctrlmetrics.ForController(mgr.GetMetricsRegistry(), name, options.MetricLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.SkipNameValidation == nil {
+		options.SkipNameValidation = mgr.GetControllerOptions().SkipNameValidation
+	}
+
+	// The name is only reserved once the controller is sure to be created.
+	if options.SkipNameValidation == nil || !*options.SkipNameValidation {
+		if err := checkName(name); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create controller with dependencies set
-	return &controller.Controller{
-		Do: options.Reconciler,
+	return &controller.Controller[request]{
+		Do:      reconciler,
+		BatchDo: batchReconciler,
 		MakeQueue: func() workqueue.RateLimitingInterface {
-			return workqueue.NewNamedRateLimitingQueue(options.RateLimiter, name)
+			return options.NewQueue(name, options.RateLimiter)
 		},
-		MaxConcurrentReconciles: options.MaxConcurrentReconciles,
-		CacheSyncTimeout:        options.CacheSyncTimeout,
-		SetFields:               mgr.SetFields,
-		Name:                    name,
-		LogConstructor:          options.LogConstructor,
-		RecoverPanic:            options.RecoverPanic,
+		MaxConcurrentReconciles:       options.MaxConcurrentReconciles,
+		ShardedWorkers:                options.ShardedWorkers,
+		MaxBatchSize:                  options.MaxBatchSize,
+		NeedWarmup:                    options.NeedWarmup,
+		CacheSyncTimeout:              options.CacheSyncTimeout,
+		SetFields:                     mgr.SetFields,
+		Name:                          name,
+		LogConstructor:                options.LogConstructor,
+		RecoverPanic:                  options.RecoverPanic,
+		OnPanic:                       options.OnPanic,
+		MaxRetries:                    options.MaxRetries,
+		DeadLetterHandler:             options.DeadLetterHandler,
+		OnStartedWorkers:              options.OnStartedWorkers,
+		OnStoppedWorkers:              options.OnStoppedWorkers,
+		ReconcileTimeout:              options.ReconcileTimeout,
+		RequeueJitter:                 options.RequeueJitter,
+		DebounceWindow:                options.DebounceWindow,
+		EventRecorder:                 mgr.GetEventRecorderFor(options.EventRecorderName),
+		Cluster:                       mgr,
+		AnnotateEventsWithReconcileID: options.AnnotateEventsWithReconcileID,
+		ReconcileExemplar:             options.ReconcileExemplar,
+		ReconcileBudget:               options.ReconcileBudget,
+		AcquireSlot:                   options.AcquireSlot,
+		Metrics:                       metrics,
 	}, nil
 }
+
+// EventRecorderFromContext returns the event recorder passed to a reconciliation via
+// its context, whose events are attributed to the controller. It returns nil if ctx
+// isn't the context of a reconciliation.
+func EventRecorderFromContext(ctx context.Context) record.EventRecorder {
+	return controller.EventRecorderFromContext(ctx)
+}
+
+// EventRecorderIntoContext returns a copy of ctx that holds the event recorder, e.g. to
+// call a reconciler in tests.
+func EventRecorderIntoContext(ctx context.Context, recorder record.EventRecorder) context.Context {
+	return controller.EventRecorderIntoContext(ctx, recorder)
+}
+
+// ReconcileIDFromContext returns the ID of the reconciliation whose context is ctx, which
+// is logged as reconcileID, e.g. to correlate it with traces. It returns "" if ctx isn't
+// the context of a reconciliation.
+func ReconcileIDFromContext(ctx context.Context) types.UID {
+	return controller.ReconcileIDFromContext(ctx)
+}
@@ -19,19 +19,38 @@ package controller_test
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/go-logr/logr/funcr"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/goleak"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/pointer"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/budget"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile/middleware"
+	"sigs.k8s.io/controller-runtime/pkg/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
@@ -59,6 +78,225 @@ var _ = Describe("controller.Controller", func() {
 			Expect(err.Error()).To(ContainSubstring("must specify Reconciler"))
 		})
 
+		It("should return an error if RequeueJitter is out of range", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{Reconciler: rec, RequeueJitter: 1.5})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("RequeueJitter must be between 0 and 1")))
+
+			c, err = controller.New("foo", m, controller.Options{Reconciler: rec, RequeueJitter: -0.1})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("RequeueJitter must be between 0 and 1")))
+		})
+
+		It("should return an error if ReconcileTimeout is negative", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{Reconciler: rec, ReconcileTimeout: -time.Second})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("ReconcileTimeout must not be negative")))
+		})
+
+		It("should return an error if MaxRetries is negative", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{Reconciler: rec, MaxRetries: -1})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("MaxRetries must not be negative")))
+		})
+
+		It("should return an error if DebounceWindow is negative", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{Reconciler: rec, DebounceWindow: -time.Second})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("DebounceWindow must not be negative")))
+		})
+
+		It("should return an error if MaxBatchSize is negative", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{Reconciler: rec, MaxBatchSize: -1})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("MaxBatchSize must not be negative")))
+		})
+
+		It("should return an error if ShardedWorkers are used with a batch reconciler", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.New("foo", m, controller.Options{Reconciler: batchRec{}, ShardedWorkers: true})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("ShardedWorkers can't be used with a batch reconciler")))
+		})
+
+		It("should return an error if the name is already used by another controller", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controller.NewUnmanaged("duplicate-name", m, controller.Options{Reconciler: rec})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.NewUnmanaged("duplicate-name", m, controller.Options{Reconciler: rec})
+			Expect(c).To(BeNil())
+			Expect(err).To(MatchError(ContainSubstring("controller with name duplicate-name already exists")))
+		})
+
+		It("should allow reusing a name with SkipNameValidation", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controller.NewUnmanaged("reused-name", m, controller.Options{Reconciler: rec})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.NewUnmanaged("reused-name", m, controller.Options{Reconciler: rec, SkipNameValidation: pointer.Bool(true)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(BeNil())
+		})
+
+		It("should default SkipNameValidation to the controller options of the manager", func() {
+			m, err := manager.New(cfg, manager.Options{
+				Controller: v1alpha1.ControllerConfigurationSpec{SkipNameValidation: pointer.Bool(true)},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = controller.NewUnmanaged("reused-by-manager", m, controller.Options{Reconciler: rec})
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := controller.NewUnmanaged("reused-by-manager", m, controller.Options{Reconciler: rec})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(BeNil())
+		})
+
+		It("should return an error if MetricLabels are invalid", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, labels := range []map[string]string{
+				{"controller": "foo"},
+				{"result": "foo"},
+				{"name": "foo"},
+				{"__shard": "0"},
+				{"sh-ard": "0"},
+				{"shard": ""},
+				{"shard": "a/b"},
+				{"a": "0", "b": "0", "c": "0", "d": "0", "e": "0", "f": "0"},
+			} {
+				c, err := controller.New("foo", m, controller.Options{Reconciler: rec, MetricLabels: labels})
+				Expect(c).To(BeNil())
+				Expect(err).To(MatchError(ContainSubstring("invalid MetricLabels")), "labels: %v", labels)
+			}
+		})
+
+		It("should add MetricLabels to the metrics of the controller and its workqueue, keeping controllers with different labels apart", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			c0, err := controller.NewUnmanaged("sharded", m, controller.Options{Reconciler: rec, MetricLabels: map[string]string{"shard": "0", "tenant": "a"}})
+			Expect(err).NotTo(HaveOccurred())
+			c1, err := controller.NewUnmanaged("sharded", m, controller.Options{Reconciler: rec, MetricLabels: map[string]string{"shard": "1", "tenant": "a"}, SkipNameValidation: pointer.Bool(true)})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			for _, c := range []controller.Controller{c0, c1} {
+				c := c
+				go func() {
+					defer GinkgoRecover()
+					Expect(c.Start(ctx)).To(Succeed())
+				}()
+			}
+
+			// labelsOf returns the labels of all metrics of the family with the given name.
+			labelsOf := func(name string) []map[string]string {
+				families, err := metrics.Registry.Gather()
+				Expect(err).NotTo(HaveOccurred())
+				var labels []map[string]string
+				for _, family := range families {
+					if family.GetName() != name {
+						continue
+					}
+					for _, metric := range family.GetMetric() {
+						pairs := map[string]string{}
+						for _, pair := range metric.GetLabel() {
+							pairs[pair.GetName()] = pair.GetValue()
+						}
+						labels = append(labels, pairs)
+					}
+				}
+				return labels
+			}
+
+			Eventually(func() []map[string]string { return labelsOf("controller_runtime_max_concurrent_reconciles") }).Should(ContainElements(
+				map[string]string{"controller": "sharded", "shard": "0", "tenant": "a"},
+				map[string]string{"controller": "sharded", "shard": "1", "tenant": "a"},
+			))
+			Expect(labelsOf("controller_runtime_reconcile_total")).To(ContainElements(
+				map[string]string{"controller": "sharded", "result": "success", "shard": "0", "tenant": "a"},
+				map[string]string{"controller": "sharded", "result": "success", "shard": "1", "tenant": "a"},
+			))
+			Expect(labelsOf("workqueue_depth")).To(ContainElements(
+				map[string]string{"name": "sharded", "shard": "0", "tenant": "a"},
+				map[string]string{"name": "sharded", "shard": "1", "tenant": "a"},
+			))
+		})
+
+		It("should report into the metrics registry of its manager", func() {
+			registries := []*prometheus.Registry{prometheus.NewRegistry(), prometheus.NewRegistry()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			for i, registry := range registries {
+				m, err := manager.New(cfg, manager.Options{Metrics: manager.MetricsOptions{Registry: registry}})
+				Expect(err).NotTo(HaveOccurred())
+				c, err := controller.NewUnmanaged(fmt.Sprintf("registry-%d", i), m, controller.Options{Reconciler: rec})
+				Expect(err).NotTo(HaveOccurred())
+				go func() {
+					defer GinkgoRecover()
+					Expect(c.Start(ctx)).To(Succeed())
+				}()
+			}
+
+			// valuesIn returns the values of the label with the given name of the
+			// metric family with the given name in the given registry.
+			valuesIn := func(registry prometheus.Gatherer, family, label string) []string {
+				families, err := registry.Gather()
+				Expect(err).NotTo(HaveOccurred())
+				var values []string
+				for _, f := range families {
+					if f.GetName() != family {
+						continue
+					}
+					for _, metric := range f.GetMetric() {
+						for _, pair := range metric.GetLabel() {
+							if pair.GetName() == label {
+								values = append(values, pair.GetValue())
+							}
+						}
+					}
+				}
+				return values
+			}
+
+			Eventually(func() []string {
+				return valuesIn(registries[0], "controller_runtime_max_concurrent_reconciles", "controller")
+			}).Should(ConsistOf("registry-0"))
+			Eventually(func() []string {
+				return valuesIn(registries[1], "controller_runtime_max_concurrent_reconciles", "controller")
+			}).Should(ConsistOf("registry-1"))
+			Expect(valuesIn(registries[0], "workqueue_depth", "name")).To(ConsistOf("registry-0"))
+			Expect(valuesIn(registries[1], "workqueue_depth", "name")).To(ConsistOf("registry-1"))
+
+			By("not reporting into the global registry")
+			Expect(valuesIn(metrics.Registry, "controller_runtime_max_concurrent_reconciles", "controller")).NotTo(ContainElements("registry-0", "registry-1"))
+			Expect(valuesIn(metrics.Registry, "workqueue_depth", "name")).NotTo(ContainElements("registry-0", "registry-1"))
+		})
+
 		It("NewController should return an error if injecting Reconciler fails", func() {
 			m, err := manager.New(cfg, manager.Options{})
 			Expect(err).NotTo(HaveOccurred())
@@ -82,6 +320,402 @@ var _ = Describe("controller.Controller", func() {
 			Expect(c2).ToNot(BeNil())
 		})
 
+		It("should wrap the Reconciler with the ReconcilerMiddleware, the first one outermost", func() {
+			m, err := manager.New(cfg, manager.Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			var calls []string
+			record := func(name string) func(reconcile.Reconciler) reconcile.Reconciler {
+				return func(next reconcile.Reconciler) reconcile.Reconciler {
+					return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+						calls = append(calls, name)
+						return next.Reconcile(ctx, req)
+					})
+				}
+			}
+			c, err := controller.NewUnmanaged("middleware", m, controller.Options{
+				Reconciler: reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+					_, hasDeadline := ctx.Deadline()
+					Expect(hasDeadline).To(BeTrue())
+					calls = append(calls, "reconciler")
+					return reconcile.Result{Requeue: true}, nil
+				}),
+				ReconcilerMiddleware: []func(reconcile.Reconciler) reconcile.Reconciler{
+					record("outer"), middleware.WithTimeout(time.Minute), record("inner"),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			result, err := c.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{Requeue: true}))
+			Expect(calls).To(Equal([]string{"outer", "inner", "reconciler"}))
+		})
+
+		It("should only add the configured fields to the loggers of reconciliations", func() {
+			logf.SetReconcileLogFields(logf.ReconcileLogFieldController, logf.ReconcileLogFieldName, logf.ReconcileLogFieldReconcileID)
+			defer logf.SetReconcileLogFields(logf.DefaultReconcileLogFields()...)
+
+			var mu sync.Mutex
+			var logged []string
+			logger := funcr.New(func(prefix, args string) {
+				mu.Lock()
+				defer mu.Unlock()
+				logged = append(logged, args)
+			}, funcr.Options{})
+			loggedWith := func(msg string) []string {
+				mu.Lock()
+				defer mu.Unlock()
+				var matching []string
+				for _, l := range logged {
+					if strings.Contains(l, fmt.Sprintf(`"msg"=%q`, msg)) {
+						matching = append(matching, l)
+					}
+				}
+				return matching
+			}
+
+			watchChan := make(chan event.GenericEvent, 1)
+			watchChan <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}}
+			rec := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				logf.FromContext(ctx).Info("Reconciling")
+				return reconcile.Result{}, fmt.Errorf("expected error")
+			})
+
+			m, err := manager.New(cfg, manager.Options{Logger: logger})
+			Expect(err).NotTo(HaveOccurred())
+			c, err := controller.New("log-fields", m, controller.Options{Reconciler: rec})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Watch(&source.Channel{Source: watchChan}, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(func() []string { return loggedWith("Reconciler error") }).ShouldNot(BeEmpty())
+			for _, l := range append(loggedWith("Reconciling"), loggedWith("Reconciler error")...) {
+				Expect(l).To(ContainSubstring(`"controller"="log-fields"`))
+				Expect(l).To(ContainSubstring(`"name"="foo"`))
+				Expect(l).To(ContainSubstring(`"reconcileID"=`))
+				Expect(l).NotTo(ContainSubstring(`"namespace"=`))
+				Expect(l).NotTo(ContainSubstring(`"object"=`))
+			}
+		})
+
+		It("should add the group and kind of the controller to the loggers of reconciliations", func() {
+			var mu sync.Mutex
+			var logged []string
+			logger := funcr.New(func(prefix, args string) {
+				mu.Lock()
+				defer mu.Unlock()
+				logged = append(logged, args)
+			}, funcr.Options{})
+			loggedWith := func(msg string) []string {
+				mu.Lock()
+				defer mu.Unlock()
+				var matching []string
+				for _, l := range logged {
+					if strings.Contains(l, fmt.Sprintf(`"msg"=%q`, msg)) {
+						matching = append(matching, l)
+					}
+				}
+				return matching
+			}
+
+			watchChan := make(chan event.GenericEvent, 1)
+			watchChan <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}}
+			rec := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				logf.FromContext(ctx).Info("Reconciling")
+				return reconcile.Result{}, fmt.Errorf("expected error")
+			})
+
+			m, err := manager.New(cfg, manager.Options{Logger: logger})
+			Expect(err).NotTo(HaveOccurred())
+			c, err := controller.New("log-group-kind", m, controller.Options{
+				Reconciler: rec,
+				GroupKind:  schema.GroupKind{Group: "apps", Kind: "Deployment"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Watch(&source.Channel{Source: watchChan}, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(func() []string { return loggedWith("Reconciler error") }).ShouldNot(BeEmpty())
+			for _, l := range append(loggedWith("Reconciling"), loggedWith("Reconciler error")...) {
+				Expect(l).To(ContainSubstring(`"controllerGroup"="apps"`))
+				Expect(l).To(ContainSubstring(`"controllerKind"="Deployment"`))
+				Expect(l).To(ContainSubstring(`"Deployment"={"name":"foo","namespace":"bar"}`))
+				Expect(l).NotTo(ContainSubstring(`"object"=`))
+			}
+		})
+
+		It("should pass a recorder named after the controller to reconciliations", func() {
+			sink := &eventSink{}
+			watchChan := make(chan event.GenericEvent, 1)
+			watchChan <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}}
+			reconcileIDs := make(chan types.UID, 1)
+			rec := reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				reconcileIDs <- controller.ReconcileIDFromContext(ctx)
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+				controller.EventRecorderFromContext(ctx).Event(pod, corev1.EventTypeNormal, "Reconciled", "reconciled")
+				return reconcile.Result{}, nil
+			})
+
+			m, err := manager.New(cfg, manager.Options{EventBroadcasterOptions: recorder.BroadcasterOptions{Sink: sink}})
+			Expect(err).NotTo(HaveOccurred())
+			c, err := controller.New("event-recorder", m, controller.Options{Reconciler: rec, AnnotateEventsWithReconcileID: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Watch(&source.Channel{Source: watchChan}, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(sink.recorded).Should(HaveLen(1))
+			evt := sink.recorded()[0]
+			Expect(evt.Source.Component).To(Equal("event-recorder"))
+			Expect(evt.InvolvedObject.Name).To(Equal("foo"))
+			Expect(evt.Reason).To(Equal("Reconciled"))
+			var reconcileID types.UID
+			Expect(reconcileIDs).To(Receive(&reconcileID))
+			Expect(reconcileID).NotTo(BeEmpty())
+			Expect(evt.Annotations).To(HaveKeyWithValue("controller-runtime.sigs.k8s.io/reconcile-id", string(reconcileID)))
+		})
+
+		It("should name the recorder passed to reconciliations after EventRecorderName", func() {
+			sink := &eventSink{}
+			watchChan := make(chan event.GenericEvent, 1)
+			watchChan <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}}
+			rec := reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+				controller.EventRecorderFromContext(ctx).Eventf(pod, corev1.EventTypeNormal, "Reconciled", "reconciled %s", req.Name)
+				return reconcile.Result{}, nil
+			})
+
+			m, err := manager.New(cfg, manager.Options{EventBroadcasterOptions: recorder.BroadcasterOptions{Sink: sink}})
+			Expect(err).NotTo(HaveOccurred())
+			c, err := controller.New("event-recorder-name", m, controller.Options{Reconciler: rec, EventRecorderName: "custom-component"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Watch(&source.Channel{Source: watchChan}, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(sink.recorded).Should(HaveLen(1))
+			evt := sink.recorded()[0]
+			Expect(evt.Source.Component).To(Equal("custom-component"))
+			Expect(evt.Message).To(Equal("reconciled foo"))
+			Expect(evt.Annotations).NotTo(HaveKey("controller-runtime.sigs.k8s.io/reconcile-id"))
+		})
+
+		It("should cap the concurrent reconciles of all controllers to the budget of the manager", func() {
+			const total = 3
+			m, err := manager.New(cfg, manager.Options{
+				MetricsBindAddress: "0",
+				Controller:         v1alpha1.ControllerConfigurationSpec{MaxConcurrentReconcilesTotal: total},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			var inFlight, maxInFlight int64
+			var reconciled sync.WaitGroup
+			rec := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				n := atomic.AddInt64(&inFlight, 1)
+				defer atomic.AddInt64(&inFlight, -1)
+				for {
+					max := atomic.LoadInt64(&maxInFlight)
+					if n <= max || atomic.CompareAndSwapInt64(&maxInFlight, max, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				reconciled.Done()
+				return reconcile.Result{}, nil
+			})
+
+			const controllers, requests = 4, 20
+			for i := 0; i < controllers; i++ {
+				c, err := controller.New(fmt.Sprintf("budget-%d", i), m, controller.Options{Reconciler: rec, MaxConcurrentReconciles: 3})
+				Expect(err).NotTo(HaveOccurred())
+				events := make(chan event.GenericEvent, requests)
+				for j := 0; j < requests; j++ {
+					events <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", j), Namespace: "default"}}}
+				}
+				Expect(c.Watch(&source.Channel{Source: events}, &handler.EnqueueRequestForObject{})).To(Succeed())
+			}
+			reconciled.Add(controllers * requests)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			done := make(chan struct{})
+			go func() {
+				reconciled.Wait()
+				close(done)
+			}()
+			Eventually(done, "10s").Should(BeClosed())
+			Expect(atomic.LoadInt64(&maxInFlight)).To(BeNumerically("<=", total))
+		})
+
+		It("should not starve a controller behind the backlog of another one sharing its budget", func() {
+			reconcileBudget, err := budget.New("starvation", 1)
+			Expect(err).NotTo(HaveOccurred())
+			m, err := manager.New(cfg, manager.Options{MetricsBindAddress: "0"})
+			Expect(err).NotTo(HaveOccurred())
+
+			backlog := make(chan event.GenericEvent, 100)
+			for i := 0; i < cap(backlog); i++ {
+				backlog <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), Namespace: "default"}}}
+			}
+			var backlogReconciled int64
+			busy, err := controller.New("budget-busy", m, controller.Options{
+				MaxConcurrentReconciles: 5,
+				ReconcileBudget:         reconcileBudget,
+				Reconciler: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					time.Sleep(5 * time.Millisecond)
+					atomic.AddInt64(&backlogReconciled, 1)
+					return reconcile.Result{}, nil
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(busy.Watch(&source.Channel{Source: backlog}, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			single := make(chan event.GenericEvent, 1)
+			singleReconciled := make(chan int64, 1)
+			quiet, err := controller.New("budget-quiet", m, controller.Options{
+				ReconcileBudget: reconcileBudget,
+				Reconciler: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					singleReconciled <- atomic.LoadInt64(&backlogReconciled)
+					return reconcile.Result{}, nil
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(quiet.Watch(&source.Channel{Source: single}, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(func() int64 { return atomic.LoadInt64(&backlogReconciled) }).Should(BeNumerically(">", 0))
+			single <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "single", Namespace: "default"}}}
+			var reconciledBefore int64
+			Eventually(singleReconciled).Should(Receive(&reconciledBefore))
+			// The single request waits at most for the slots requested by the workers of the
+			// busy controller before it.
+			Expect(reconciledBefore).To(BeNumerically("<", cap(backlog)/2))
+		})
+
+		It("should reconcile requests by priority with a priority queue", func() {
+			m, err := manager.New(cfg, manager.Options{MetricsBindAddress: "0"})
+			Expect(err).NotTo(HaveOccurred())
+
+			var queue priorityqueue.PriorityQueue
+			queueCreated := make(chan struct{})
+			unblock := make(chan struct{})
+			reconciled := make(chan string, 10)
+			_, err = controller.New("priority", m, controller.Options{
+				NewQueue: func(controllerName string, rateLimiter ratelimiter.RateLimiter) workqueue.RateLimitingInterface {
+					defer close(queueCreated)
+					Expect(controllerName).To(Equal("priority"))
+					queue = priorityqueue.New(controllerName, priorityqueue.Options{RateLimiter: rateLimiter})
+					return queue
+				},
+				Reconciler: reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+					if req.Name == "blocking" {
+						<-unblock
+					}
+					reconciled <- req.Name
+					return reconcile.Result{}, nil
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			Eventually(queueCreated).Should(BeClosed())
+			request := func(name string) reconcile.Request {
+				return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: name}}
+			}
+			queue.Add(request("blocking"))
+			Eventually(queue.Len).Should(BeZero())
+			queue.AddWithOpts(priorityqueue.AddOpts{Priority: handler.LowPriority}, request("resync"))
+			queue.Add(request("change"))
+			queue.AddWithOpts(priorityqueue.AddOpts{Priority: 10}, request("urgent"))
+			close(unblock)
+
+			var order []string
+			for i := 0; i < 4; i++ {
+				var name string
+				Eventually(reconciled).Should(Receive(&name))
+				order = append(order, name)
+			}
+			Expect(order).To(Equal([]string{"blocking", "urgent", "change", "resync"}))
+		})
+
+		It("should reconcile requests of a custom type with a typed controller", func() {
+			type externalRequest struct {
+				ARN string
+			}
+
+			m, err := manager.New(cfg, manager.Options{MetricsBindAddress: "0"})
+			Expect(err).NotTo(HaveOccurred())
+
+			reconciled := make(chan externalRequest)
+			c, err := controller.NewTyped("typed", m, controller.TypedOptions[externalRequest]{
+				Reconciler: reconcile.TypedFunc[externalRequest](func(ctx context.Context, req externalRequest) (reconcile.Result, error) {
+					reconciled <- req
+					return reconcile.Result{}, nil
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			events := make(chan event.GenericEvent, 1)
+			Expect(c.Watch(&source.Channel{Source: events}, handler.TypedEnqueueRequestsFromMapFunc(func(obj client.Object) []externalRequest {
+				return []externalRequest{{ARN: "arn:aws:s3:::" + obj.GetName()}}
+			}))).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).To(Succeed())
+			}()
+
+			events <- event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "bucket", Namespace: "default"}}}
+			Eventually(reconciled).Should(Receive(Equal(externalRequest{ARN: "arn:aws:s3:::bucket"})))
+		})
+
+		It("should return no recorder outside of reconciliations", func() {
+			Expect(controller.EventRecorderFromContext(context.Background())).To(BeNil())
+			Expect(controller.ReconcileIDFromContext(context.Background())).To(BeEmpty())
+		})
+
 		It("should not leak goroutines when stopped", func() {
 			currentGRs := goleak.IgnoreCurrent()
 
@@ -107,7 +741,7 @@ var _ = Describe("controller.Controller", func() {
 			m, err := manager.New(cfg, manager.Options{})
 			Expect(err).NotTo(HaveOccurred())
 
-			c, err := controller.New("new-controller", m, controller.Options{Reconciler: rec})
+			c, err := controller.New("new-controller", m, controller.Options{Reconciler: rec, SkipNameValidation: pointer.Bool(true)})
 			Expect(c.Watch(watch, &handler.EnqueueRequestForObject{})).To(Succeed())
 			Expect(err).NotTo(HaveOccurred())
 
@@ -134,7 +768,7 @@ var _ = Describe("controller.Controller", func() {
 			m, err := manager.New(cfg, manager.Options{})
 			Expect(err).NotTo(HaveOccurred())
 
-			_, err = controller.New("new-controller", m, controller.Options{Reconciler: rec})
+			_, err = controller.New("new-controller", m, controller.Options{Reconciler: rec, SkipNameValidation: pointer.Bool(true)})
 			Expect(err).NotTo(HaveOccurred())
 
 			// force-close keep-alive connections.  These'll time anyway (after
@@ -157,3 +791,40 @@ func (*failRec) Reconcile(context.Context, reconcile.Request) (reconcile.Result,
 func (*failRec) InjectClient(client.Client) error {
 	return fmt.Errorf("expected error")
 }
+
+// eventSink captures the events written to it.
+type batchRec struct{}
+
+func (batchRec) Reconcile(context.Context, reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+func (batchRec) ReconcileBatch(context.Context, []reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
+
+type eventSink struct {
+	mu     sync.Mutex
+	events []*corev1.Event
+}
+
+func (s *eventSink) recorded() []*corev1.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*corev1.Event(nil), s.events...)
+}
+
+func (s *eventSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return event, nil
+}
+
+func (s *eventSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	return s.Create(event)
+}
+
+func (s *eventSink) Patch(event *corev1.Event, _ []byte) (*corev1.Event, error) {
+	return s.Create(event)
+}
@@ -18,6 +18,7 @@ package controllerutil
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 
@@ -27,6 +28,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
@@ -51,38 +54,74 @@ func newAlreadyOwnedError(obj metav1.Object, owner metav1.OwnerReference) *Alrea
 	}
 }
 
+// OwnerReferenceOption configures how SetOwnerReference and SetControllerReference
+// set the owner reference.
+type OwnerReferenceOption func(*ownerReferenceOptions)
+
+type ownerReferenceOptions struct {
+	blockOwnerDeletion *bool
+	overrideController bool
+}
+
+// WithBlockOwnerDeletion sets the BlockOwnerDeletion flag of the owner reference.
+// SetControllerReference sets it to true by default, while SetOwnerReference keeps
+// the flag of an existing reference to the owner, or leaves it unset.
+func WithBlockOwnerDeletion(blockOwnerDeletion bool) OwnerReferenceOption {
+	return func(o *ownerReferenceOptions) {
+		o.blockOwnerDeletion = pointer.BoolPtr(blockOwnerDeletion)
+	}
+}
+
+// OverrideControllerReference makes SetControllerReference take over the object if
+// it's controlled by another owner: the Controller flag of the other owner's reference
+// is cleared instead of returning an AlreadyOwnedError. This allows handing over the
+// ownership between controllers. SetOwnerReference ignores it.
+func OverrideControllerReference() OwnerReferenceOption {
+	return func(o *ownerReferenceOptions) {
+		o.overrideController = true
+	}
+}
+
 // SetControllerReference sets owner as a Controller OwnerReference on controlled.
 // This is used for garbage collection of the controlled object and for
 // reconciling the owner object on changes to controlled (with a Watch + EnqueueRequestForOwner).
 // Since only one OwnerReference can be a controller, it returns an error if
-// there is another OwnerReference with Controller flag set.
-func SetControllerReference(owner, controlled metav1.Object, scheme *runtime.Scheme) error {
+// there is another OwnerReference with Controller flag set, unless the
+// OverrideControllerReference option is passed.
+func SetControllerReference(owner, controlled metav1.Object, scheme *runtime.Scheme, opts ...OwnerReferenceOption) error {
 	// Validate the owner.
-	ro, ok := owner.(runtime.Object)
-	if !ok {
+	if _, ok := owner.(runtime.Object); !ok {
 		return fmt.Errorf("%T is not a runtime.Object, cannot call SetControllerReference", owner)
 	}
 	if err := validateOwner(owner, controlled); err != nil {
 		return err
 	}
+	options := ownerReferenceOptions{blockOwnerDeletion: pointer.BoolPtr(true)}
+	for _, opt := range opts {
+		opt(&options)
+	}
 
 	// Create a new controller ref.
-	gvk, err := apiutil.GVKForObject(ro, scheme)
+	ref, err := ownerRefFor(owner, scheme, "SetControllerReference")
 	if err != nil {
 		return err
 	}
-	ref := metav1.OwnerReference{
-		APIVersion:         gvk.GroupVersion().String(),
-		Kind:               gvk.Kind,
-		Name:               owner.GetName(),
-		UID:                owner.GetUID(),
-		BlockOwnerDeletion: pointer.BoolPtr(true),
-		Controller:         pointer.BoolPtr(true),
-	}
+	ref.BlockOwnerDeletion = options.blockOwnerDeletion
+	ref.Controller = pointer.BoolPtr(true)
 
-	// Return early with an error if the object is already controlled.
+	// Return early with an error if the object is already controlled, unless it's
+	// taken over.
 	if existing := metav1.GetControllerOf(controlled); existing != nil && !referSameObject(*existing, ref) {
-		return newAlreadyOwnedError(controlled, *existing)
+		if !options.overrideController {
+			return newAlreadyOwnedError(controlled, *existing)
+		}
+		owners := controlled.GetOwnerReferences()
+		for i := range owners {
+			if referSameObject(owners[i], *existing) {
+				owners[i].Controller = nil
+			}
+		}
+		controlled.SetOwnerReferences(owners)
 	}
 
 	// Update owner references and return.
@@ -92,46 +131,133 @@ func SetControllerReference(owner, controlled metav1.Object, scheme *runtime.Sch
 
 // SetOwnerReference is a helper method to make sure the given object contains an object reference to the object provided.
 // This allows you to declare that owner has a dependency on the object without specifying it as a controller.
-// If a reference to the same object already exists, it'll be overwritten with the newly provided version.
-func SetOwnerReference(owner, object metav1.Object, scheme *runtime.Scheme) error {
+// If a reference to the same object already exists, it'll be overwritten with the newly provided version,
+// keeping its Controller flag and, unless the WithBlockOwnerDeletion option is passed, its BlockOwnerDeletion flag.
+func SetOwnerReference(owner, object metav1.Object, scheme *runtime.Scheme, opts ...OwnerReferenceOption) error {
 	// Validate the owner.
-	ro, ok := owner.(runtime.Object)
-	if !ok {
+	if _, ok := owner.(runtime.Object); !ok {
 		return fmt.Errorf("%T is not a runtime.Object, cannot call SetOwnerReference", owner)
 	}
 	if err := validateOwner(owner, object); err != nil {
 		return err
 	}
+	options := ownerReferenceOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
 
 	// Create a new owner ref.
-	gvk, err := apiutil.GVKForObject(ro, scheme)
+	ref, err := ownerRefFor(owner, scheme, "SetOwnerReference")
 	if err != nil {
 		return err
 	}
-	ref := metav1.OwnerReference{
-		APIVersion: gvk.GroupVersion().String(),
-		Kind:       gvk.Kind,
-		UID:        owner.GetUID(),
-		Name:       owner.GetName(),
-	}
+	ref.BlockOwnerDeletion = options.blockOwnerDeletion
 
 	// Update owner references and return.
 	upsertOwnerRef(ref, object)
 	return nil
 }
 
+// RemoveOwnerReference removes the owner references to owner from object. References
+// are matched by the group, kind and name of owner, but not by its UID, so owner doesn't
+// need to be the exact instance that was referenced. Other references are preserved, and
+// it returns an error if object has no reference to owner.
+func RemoveOwnerReference(owner, object metav1.Object, scheme *runtime.Scheme) error {
+	ref, err := ownerRefFor(owner, scheme, "RemoveOwnerReference")
+	if err != nil {
+		return err
+	}
+	ref.UID = ""
+
+	owners := object.GetOwnerReferences()
+	remaining := make([]metav1.OwnerReference, 0, len(owners))
+	for _, r := range owners {
+		if !referSameObject(r, ref) {
+			remaining = append(remaining, r)
+		}
+	}
+	if len(remaining) == len(owners) {
+		return fmt.Errorf("%T %s has no owner reference to %s %s", object, object.GetName(), ref.Kind, ref.Name)
+	}
+	object.SetOwnerReferences(remaining)
+	return nil
+}
+
+// HasOwnerReference returns whether ownerRefs contains a reference to owner. Like
+// RemoveOwnerReference, references are matched by the group, kind and name of owner.
+func HasOwnerReference(ownerRefs []metav1.OwnerReference, owner metav1.Object, scheme *runtime.Scheme) (bool, error) {
+	ref, err := ownerRefFor(owner, scheme, "HasOwnerReference")
+	if err != nil {
+		return false, err
+	}
+	ref.UID = ""
+	return indexOwnerRef(ownerRefs, ref) != -1, nil
+}
+
+// HasControllerReference returns whether object has an owner reference with the
+// Controller flag set.
+func HasControllerReference(object metav1.Object) bool {
+	return metav1.GetControllerOf(object) != nil
+}
+
+// ownerRefFor returns a reference to owner without any flags set. The caller is
+// reported if owner isn't a runtime.Object.
+func ownerRefFor(owner metav1.Object, scheme *runtime.Scheme, caller string) (metav1.OwnerReference, error) {
+	ro, ok := owner.(runtime.Object)
+	if !ok {
+		return metav1.OwnerReference{}, fmt.Errorf("%T is not a runtime.Object, cannot call %s", owner, caller)
+	}
+	gvk, err := apiutil.GVKForObject(ro, scheme)
+	if err != nil {
+		return metav1.OwnerReference{}, err
+	}
+	return metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}, nil
+}
+
+// upsertOwnerRef adds the owner reference to the object, or updates the existing
+// reference to the same owner in place. Flags that are unset on the given reference
+// are kept from the existing one, and other references to the same owner are removed.
 func upsertOwnerRef(ref metav1.OwnerReference, object metav1.Object) {
 	owners := object.GetOwnerReferences()
-	if idx := indexOwnerRef(owners, ref); idx == -1 {
-		owners = append(owners, ref)
-	} else {
-		owners[idx] = ref
+	idx := indexOwnerRef(owners, ref)
+	if idx == -1 {
+		object.SetOwnerReferences(append(owners, ref))
+		return
+	}
+
+	if ref.Controller == nil {
+		ref.Controller = owners[idx].Controller
+	}
+	if ref.BlockOwnerDeletion == nil {
+		ref.BlockOwnerDeletion = owners[idx].BlockOwnerDeletion
 	}
-	object.SetOwnerReferences(owners)
+	upserted := make([]metav1.OwnerReference, 0, len(owners))
+	for i, r := range owners {
+		switch {
+		case i == idx:
+			upserted = append(upserted, ref)
+		case !referSameObject(r, ref):
+			upserted = append(upserted, r)
+		}
+	}
+	object.SetOwnerReferences(upserted)
 }
 
 // indexOwnerRef returns the index of the owner reference in the slice if found, or -1.
+// References with the same UID are preferred over references with the same name.
 func indexOwnerRef(ownerReferences []metav1.OwnerReference, ref metav1.OwnerReference) int {
+	if ref.UID != "" {
+		for index, r := range ownerReferences {
+			if r.UID == ref.UID {
+				return index
+			}
+		}
+	}
 	for index, r := range ownerReferences {
 		if referSameObject(r, ref) {
 			return index
@@ -154,8 +280,13 @@ func validateOwner(owner, object metav1.Object) error {
 	return nil
 }
 
-// Returns true if a and b point to the same object.
+// Returns true if a and b point to the same object, either because they have the same
+// UID, or the same group, kind and name.
 func referSameObject(a, b metav1.OwnerReference) bool {
+	if a.UID != "" && a.UID == b.UID {
+		return true
+	}
+
 	aGV, err := schema.ParseGroupVersion(a.APIVersion)
 	if err != nil {
 		return false
@@ -193,33 +324,59 @@ const ( // They should complete the sentence "Deployment default/foo has been ..
 //
 // It returns the executed operation and an error.
 func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, f MutateFn) (OperationResult, error) {
+	result, _, err := createOrUpdate(ctx, c, obj, f, false)
+	return result, err
+}
+
+// CreateOrUpdateWithChanges is like CreateOrUpdate, but also returns the paths of the
+// fields that were changed if the object was updated, such as
+// "spec.template.spec.containers[0].image". Only the paths are returned, never the
+// values, and at most maxChangedPaths of them followed by "...".
+func CreateOrUpdateWithChanges(ctx context.Context, c client.Client, obj client.Object, f MutateFn) (OperationResult, []string, error) {
+	return createOrUpdate(ctx, c, obj, f, true)
+}
+
+func createOrUpdate(ctx context.Context, c client.Client, obj client.Object, f MutateFn, collectChanges bool) (OperationResult, []string, error) {
 	key := client.ObjectKeyFromObject(obj)
 	if err := c.Get(ctx, key, obj); err != nil {
 		if !apierrors.IsNotFound(err) {
-			return OperationResultNone, err
+			return OperationResultNone, nil, err
 		}
 		if err := mutate(f, key, obj); err != nil {
-			return OperationResultNone, err
+			return OperationResultNone, nil, err
 		}
 		if err := c.Create(ctx, obj); err != nil {
-			return OperationResultNone, err
+			return OperationResultNone, nil, err
 		}
-		return OperationResultCreated, nil
+		return OperationResultCreated, nil, nil
 	}
 
 	existing := obj.DeepCopyObject() //nolint
 	if err := mutate(f, key, obj); err != nil {
-		return OperationResultNone, err
+		return OperationResultNone, nil, err
 	}
 
 	if equality.Semantic.DeepEqual(existing, obj) {
-		return OperationResultNone, nil
+		return OperationResultNone, nil, nil
+	}
+
+	var changed []string
+	if collectChanges {
+		before, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+		if err != nil {
+			return OperationResultNone, nil, err
+		}
+		after, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return OperationResultNone, nil, err
+		}
+		changed = truncatePaths(changedPaths(after, before, false, "", nil))
 	}
 
 	if err := c.Update(ctx, obj); err != nil {
-		return OperationResultNone, err
+		return OperationResultNone, nil, err
 	}
-	return OperationResultUpdated, nil
+	return OperationResultUpdated, changed, nil
 }
 
 // CreateOrPatch creates or patches the given object in the Kubernetes
@@ -228,22 +385,44 @@ func CreateOrUpdate(ctx context.Context, c client.Client, obj client.Object, f M
 //
 // The MutateFn is called regardless of creating or updating an object.
 //
+// The object is only patched if the MutateFn changed it semantically: nil and
+// empty maps and slices are considered equal, and fields outside of the metadata
+// that are set on the fetched object but not after the MutateFn ran are assumed
+// to be defaulted by the server and ignored, so a MutateFn building the desired
+// state from scratch doesn't cause a write. Lists that the MutateFn emptied and
+// the labels, annotations, finalizers and owner references it removed are
+// written, as the server doesn't default them. Unsetting other fields is only
+// written along with other changes. The status is patched separately through the
+// status subresource if the MutateFn changed it, where unset fields aren't ignored.
+//
 // It returns the executed operation and an error.
 func CreateOrPatch(ctx context.Context, c client.Client, obj client.Object, f MutateFn) (OperationResult, error) {
+	result, _, err := createOrPatch(ctx, c, obj, f, false)
+	return result, err
+}
+
+// CreateOrPatchWithChanges is like CreateOrPatch, but also returns the paths of the
+// fields that were changed if the object or its status was patched, like
+// CreateOrUpdateWithChanges.
+func CreateOrPatchWithChanges(ctx context.Context, c client.Client, obj client.Object, f MutateFn) (OperationResult, []string, error) {
+	return createOrPatch(ctx, c, obj, f, true)
+}
+
+func createOrPatch(ctx context.Context, c client.Client, obj client.Object, f MutateFn, collectChanges bool) (OperationResult, []string, error) {
 	key := client.ObjectKeyFromObject(obj)
 	if err := c.Get(ctx, key, obj); err != nil {
 		if !apierrors.IsNotFound(err) {
-			return OperationResultNone, err
+			return OperationResultNone, nil, err
 		}
 		if f != nil {
 			if err := mutate(f, key, obj); err != nil {
-				return OperationResultNone, err
+				return OperationResultNone, nil, err
 			}
 		}
 		if err := c.Create(ctx, obj); err != nil {
-			return OperationResultNone, err
+			return OperationResultNone, nil, err
 		}
-		return OperationResultCreated, nil
+		return OperationResultCreated, nil, nil
 	}
 
 	// Create patches for the object and its possible status.
@@ -254,13 +433,13 @@ func CreateOrPatch(ctx context.Context, c client.Client, obj client.Object, f Mu
 	// unstructured data.
 	before, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
 	if err != nil {
-		return OperationResultNone, err
+		return OperationResultNone, nil, err
 	}
 
 	// Attempt to extract the status from the resource for easier comparison later
 	beforeStatus, hasBeforeStatus, err := unstructured.NestedFieldCopy(before, "status")
 	if err != nil {
-		return OperationResultNone, err
+		return OperationResultNone, nil, err
 	}
 
 	// If the resource contains a status then remove it from the unstructured
@@ -272,20 +451,20 @@ func CreateOrPatch(ctx context.Context, c client.Client, obj client.Object, f Mu
 	// Mutate the original object.
 	if f != nil {
 		if err := mutate(f, key, obj); err != nil {
-			return OperationResultNone, err
+			return OperationResultNone, nil, err
 		}
 	}
 
 	// Convert the resource to unstructured to compare against our before copy.
 	after, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
-		return OperationResultNone, err
+		return OperationResultNone, nil, err
 	}
 
 	// Attempt to extract the status from the resource for easier comparison later
 	afterStatus, hasAfterStatus, err := unstructured.NestedFieldCopy(after, "status")
 	if err != nil {
-		return OperationResultNone, err
+		return OperationResultNone, nil, err
 	}
 
 	// If the resource contains a status then remove it from the unstructured
@@ -294,35 +473,49 @@ func CreateOrPatch(ctx context.Context, c client.Client, obj client.Object, f Mu
 		unstructured.RemoveNestedField(after, "status")
 	}
 
+	// The metadata isn't defaulted by the server, so unset fields of the metadata
+	// are compared, apart from the ones set by the server.
+	afterMeta, beforeMeta := popMetadata(after), popMetadata(before)
+
+	var changed []string
+	if collectChanges {
+		changed = changedPaths(afterMeta, beforeMeta, false, "metadata", nil)
+		changed = changedPaths(after, before, true, "", changed)
+		if hasBeforeStatus || hasAfterStatus {
+			changed = changedPaths(afterStatus, beforeStatus, false, "status", changed)
+		}
+		changed = truncatePaths(changed)
+	}
+
 	result := OperationResultNone
 
-	if !reflect.DeepEqual(before, after) {
+	if !semanticallyEqual(afterMeta, beforeMeta, false) || !semanticallyEqual(after, before, true) {
 		// Only issue a Patch if the before and after resources (minus status) differ
 		if err := c.Patch(ctx, obj, objPatch); err != nil {
-			return result, err
+			return result, nil, err
 		}
 		result = OperationResultUpdated
 	}
 
-	if (hasBeforeStatus || hasAfterStatus) && !reflect.DeepEqual(beforeStatus, afterStatus) {
+	if (hasBeforeStatus || hasAfterStatus) && !semanticallyEqual(afterStatus, beforeStatus, false) {
 		// Only issue a Status Patch if the resource has a status and the beforeStatus
 		// and afterStatus copies differ
 		if result == OperationResultUpdated {
 			// If Status was replaced by Patch before, set it to afterStatus
 			objectAfterPatch, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 			if err != nil {
-				return result, err
+				return result, nil, err
 			}
 			if err = unstructured.SetNestedField(objectAfterPatch, afterStatus, "status"); err != nil {
-				return result, err
+				return result, nil, err
 			}
 			// If Status was replaced by Patch before, restore patched structure to the obj
 			if err = runtime.DefaultUnstructuredConverter.FromUnstructured(objectAfterPatch, obj); err != nil {
-				return result, err
+				return result, nil, err
 			}
 		}
 		if err := c.Status().Patch(ctx, obj, statusPatch); err != nil {
-			return result, err
+			return result, nil, err
 		}
 		if result == OperationResultUpdated {
 			result = OperationResultUpdatedStatus
@@ -331,7 +524,240 @@ func CreateOrPatch(ctx context.Context, c client.Client, obj client.Object, f Mu
 		}
 	}
 
-	return result, nil
+	return result, changed, nil
+}
+
+// CreateOrApply creates or updates the given object in the Kubernetes cluster with a
+// server-side apply patch owned by fieldOwner. Unlike CreateOrUpdate, the object isn't
+// fetched before the MutateFn is called: the MutateFn must build the desired state,
+// i.e. all the fields owned by fieldOwner, and fields that were owned by fieldOwner but
+// aren't set anymore are removed or released by the server. Read-only metadata, such as
+// the resource version, and the status are stripped from the patch.
+//
+// Conflicts with other field managers are returned as conflict errors, unless
+// client.ForceOwnership is passed in opts. The object reflects the persisted state afterwards.
+//
+// The object must be unstructured, so that the patch only contains the fields the MutateFn
+// sets: a typed object would also set the zero values of its other fields, and take their
+// ownership. Typed objects are applied with client.Apply and an apply configuration instead.
+//
+// It returns the executed operation, depending on whether the resource version of the
+// object changed, and an error.
+func CreateOrApply(ctx context.Context, c client.Client, obj client.Object, fieldOwner string, f MutateFn, opts ...client.PatchOption) (OperationResult, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return OperationResultNone, fmt.Errorf("CreateOrApply requires an *unstructured.Unstructured object, got %T: use client.Apply with an apply configuration for typed objects", obj)
+	}
+
+	key := client.ObjectKeyFromObject(obj)
+	if err := mutate(f, key, obj); err != nil {
+		return OperationResultNone, err
+	}
+
+	gvk := u.GroupVersionKind()
+	if gvk.Version == "" || gvk.Kind == "" {
+		return OperationResultNone, fmt.Errorf("the apiVersion and the kind of object %s must be set", key)
+	}
+	desired := applyPatchFor(u)
+
+	// The existing object is read as unstructured, which isn't served from the cache by
+	// default, to compare the resource version with the current one.
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gvk)
+	existingVersion := ""
+	if err := c.Get(ctx, key, existing); err == nil {
+		existingVersion = existing.GetResourceVersion()
+	} else if !apierrors.IsNotFound(err) {
+		return OperationResultNone, err
+	}
+
+	opts = append([]client.PatchOption{client.FieldOwner(fieldOwner)}, opts...)
+	if err := c.Patch(ctx, desired, client.Apply, opts...); err != nil {
+		return OperationResultNone, err
+	}
+	u.Object = desired.Object
+
+	switch {
+	case existingVersion == "":
+		return OperationResultCreated, nil
+	case desired.GetResourceVersion() != existingVersion:
+		return OperationResultUpdated, nil
+	default:
+		return OperationResultNone, nil
+	}
+}
+
+// applyPatchFor returns a copy of the object without the fields that must not be part
+// of an apply patch.
+func applyPatchFor(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	content := runtime.DeepCopyJSON(obj.Object)
+	delete(content, "status")
+	for _, field := range serverSetMetadataFields {
+		unstructured.RemoveNestedField(content, "metadata", field)
+	}
+	return &unstructured.Unstructured{Object: content}
+}
+
+// serverSetMetadataFields are the fields of the metadata that are set by the server.
+var serverSetMetadataFields = []string{
+	"creationTimestamp", "deletionGracePeriodSeconds", "deletionTimestamp", "generation",
+	"managedFields", "resourceVersion", "selfLink", "uid",
+}
+
+// popMetadata removes the metadata from the unstructured object, and returns it
+// without the fields set by the server.
+func popMetadata(obj map[string]interface{}) map[string]interface{} {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	delete(obj, "metadata")
+	for _, field := range serverSetMetadataFields {
+		delete(metadata, field)
+	}
+	return metadata
+}
+
+// semanticallyEqual returns whether the unstructured values after and before are
+// semantically equal, treating nil and empty maps and slices as equal. If ignoreUnset
+// is true, fields that are set in before but not in after are ignored, unless they
+// are non-empty lists.
+func semanticallyEqual(after, before interface{}, ignoreUnset bool) bool {
+	if isEmpty(after) && isEmpty(before) {
+		return true
+	}
+	switch after := after.(type) {
+	case map[string]interface{}:
+		before, ok := before.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range after {
+			if !semanticallyEqual(v, before[k], ignoreUnset) {
+				return false
+			}
+		}
+		for k, v := range before {
+			if _, ok := after[k]; !ok && !semanticallyEqual(nil, v, ignoreUnset) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		before, ok := before.([]interface{})
+		if !ok || len(after) != len(before) {
+			return false
+		}
+		for i := range after {
+			if !semanticallyEqual(after[i], before[i], ignoreUnset) {
+				return false
+			}
+		}
+		return true
+	case nil:
+		return ignoreUnset && !isList(before)
+	default:
+		return reflect.DeepEqual(after, before)
+	}
+}
+
+// isList returns whether the unstructured value is a list.
+func isList(v interface{}) bool {
+	_, ok := v.([]interface{})
+	return ok
+}
+
+// isEmpty returns whether the unstructured value is nil or an empty map or slice.
+func isEmpty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// maxChangedPaths is the maximum number of paths of changed fields that are returned.
+const maxChangedPaths = 20
+
+// changedPaths appends the paths of the fields that differ between the unstructured
+// values after and before to paths, with the semantics of semanticallyEqual. Lists are
+// compared by index. It stops once more than maxChangedPaths paths were found.
+func changedPaths(after, before interface{}, ignoreUnset bool, path string, paths []string) []string {
+	if len(paths) > maxChangedPaths || isEmpty(after) && isEmpty(before) {
+		return paths
+	}
+	switch after := after.(type) {
+	case map[string]interface{}:
+		if before == nil {
+			before = map[string]interface{}{}
+		}
+		before, ok := before.(map[string]interface{})
+		if !ok {
+			return append(paths, path)
+		}
+		keys := sets.StringKeySet(after).Insert(sets.StringKeySet(before).UnsortedList()...)
+		for _, k := range keys.List() {
+			paths = changedPaths(after[k], before[k], ignoreUnset, fieldPath(path, k), paths)
+		}
+		return paths
+	case []interface{}:
+		if before == nil {
+			before = []interface{}{}
+		}
+		before, ok := before.([]interface{})
+		if !ok {
+			return append(paths, path)
+		}
+		for i := 0; i < len(after) || i < len(before); i++ {
+			indexPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(before), i >= len(after):
+				paths = append(paths, indexPath)
+			default:
+				paths = changedPaths(after[i], before[i], ignoreUnset, indexPath, paths)
+			}
+		}
+		return paths
+	case nil:
+		if ignoreUnset && !isList(before) {
+			return paths
+		}
+		return append(paths, path)
+	default:
+		if reflect.DeepEqual(after, before) {
+			return paths
+		}
+		return append(paths, path)
+	}
+}
+
+// fieldPath returns the path of the field with the given key in the map at path. Keys
+// that aren't simple names, like most label keys, are quoted.
+func fieldPath(path, key string) string {
+	simple := key != ""
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			simple = false
+			break
+		}
+	}
+	switch {
+	case !simple:
+		return fmt.Sprintf("%s[%q]", path, key)
+	case path == "":
+		return key
+	default:
+		return path + "." + key
+	}
+}
+
+// truncatePaths returns at most maxChangedPaths paths, followed by "..." if there are more.
+func truncatePaths(paths []string) []string {
+	if len(paths) > maxChangedPaths {
+		return append(paths[:maxChangedPaths:maxChangedPaths], "...")
+	}
+	return paths
 }
 
 // mutate wraps a MutateFn and applies validation to its result.
@@ -387,6 +813,70 @@ func ContainsFinalizer(o client.Object, finalizer string) bool {
 	return false
 }
 
+// EnsureFinalizer adds the provided finalizer to the Object if not present, and
+// persists only the change of the finalizers with a patch. The patch fails if
+// the Object was modified since it was read, in which case it's read again and
+// the patch is retried once. Afterwards the Object reflects the persisted state.
+// It returns whether it changed the finalizers of the Object.
+func EnsureFinalizer(ctx context.Context, c client.Client, obj client.Object, finalizer string) (changed bool, err error) {
+	return patchFinalizers(ctx, c, obj, func() bool {
+		return AddFinalizer(obj, finalizer)
+	})
+}
+
+// RemoveFinalizerAndPatch removes the provided finalizer from the Object if present,
+// and persists only the change of the finalizers with a patch like EnsureFinalizer.
+// It returns whether it changed the finalizers of the Object.
+func RemoveFinalizerAndPatch(ctx context.Context, c client.Client, obj client.Object, finalizer string) (changed bool, err error) {
+	return patchFinalizers(ctx, c, obj, func() bool {
+		return RemoveFinalizer(obj, finalizer)
+	})
+}
+
+// patchFinalizers changes the finalizers of the object with the given function, and
+// patches them if it changed them, retrying once with the current object on conflict.
+func patchFinalizers(ctx context.Context, c client.Client, obj client.Object, change func() bool) (bool, error) {
+	for retried := false; ; retried = true {
+		finalizers := append([]string(nil), obj.GetFinalizers()...)
+		if !change() {
+			return false, nil
+		}
+
+		patch, err := finalizersPatch(obj)
+		if err == nil {
+			err = c.Patch(ctx, obj, patch)
+		}
+		if err == nil {
+			return true, nil
+		}
+		obj.SetFinalizers(finalizers)
+		if retried || !apierrors.IsConflict(err) {
+			return false, err
+		}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return false, err
+		}
+	}
+}
+
+// finalizersPatch returns a JSON patch replacing the finalizers with the ones of the
+// object. It sets the resource version of the object too, so it's rejected with a
+// conflict if the object was modified since, like MergeFromWithOptimisticLock.
+func finalizersPatch(obj client.Object) (client.Patch, error) {
+	finalizers := obj.GetFinalizers()
+	if finalizers == nil {
+		finalizers = []string{}
+	}
+	data, err := json.Marshal([]map[string]interface{}{
+		{"op": "replace", "path": "/metadata/resourceVersion", "value": obj.GetResourceVersion()},
+		{"op": "add", "path": "/metadata/finalizers", "value": finalizers},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.RawPatch(types.JSONPatchType, data), nil
+}
+
 // Object allows functions to work indistinctly with any resource that
 // implements both Object interfaces.
 //
@@ -26,12 +26,16 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -105,6 +109,104 @@ var _ = Describe("Controllerutil", func() {
 		})
 	})
 
+	Describe("SetOwnerReference and SetControllerReference with existing references", func() {
+		ownerRef := func(name string, uid types.UID, controller, blockOwnerDeletion *bool) metav1.OwnerReference {
+			return metav1.OwnerReference{
+				Name:               name,
+				Kind:               "Deployment",
+				APIVersion:         "extensions/v1beta1",
+				UID:                uid,
+				Controller:         controller,
+				BlockOwnerDeletion: blockOwnerDeletion,
+			}
+		}
+		t, f := pointer.BoolPtr(true), pointer.BoolPtr(false)
+		dep := &extensionsv1beta1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "foo-uid"}}
+
+		DescribeTable("SetOwnerReference",
+			func(existing []metav1.OwnerReference, opts []controllerutil.OwnerReferenceOption, expected []metav1.OwnerReference) {
+				rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", OwnerReferences: existing}}
+				Expect(controllerutil.SetOwnerReference(dep, rs, scheme.Scheme, opts...)).To(Succeed())
+				Expect(rs.OwnerReferences).To(Equal(expected))
+			},
+			Entry("no references",
+				nil, nil,
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", nil, nil)}),
+			Entry("no references and blockOwnerDeletion",
+				nil, []controllerutil.OwnerReferenceOption{controllerutil.WithBlockOwnerDeletion(true)},
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", nil, t)}),
+			Entry("a controller reference to the owner",
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, t)}, nil,
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, t)}),
+			Entry("a controller reference to the owner and blockOwnerDeletion",
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, t)}, []controllerutil.OwnerReferenceOption{controllerutil.WithBlockOwnerDeletion(false)},
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, f)}),
+			Entry("a reference to the owner under another name",
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", nil, nil), ownerRef("old-foo", "foo-uid", nil, t)}, nil,
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", nil, nil), ownerRef("foo", "foo-uid", nil, t)}),
+			Entry("a reference to a previous owner with the same name",
+				[]metav1.OwnerReference{ownerRef("foo", "old-foo-uid", nil, nil)}, nil,
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", nil, nil)}),
+			Entry("duplicate references to the owner differing in flags",
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", nil, t), ownerRef("bar", "bar-uid", nil, nil), ownerRef("foo", "foo-uid", f, f)}, nil,
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", nil, t), ownerRef("bar", "bar-uid", nil, nil)}),
+			Entry("a controller reference to another owner",
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", t, t)}, []controllerutil.OwnerReferenceOption{controllerutil.OverrideControllerReference()},
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", t, t), ownerRef("foo", "foo-uid", nil, nil)}),
+		)
+
+		DescribeTable("SetControllerReference",
+			func(existing []metav1.OwnerReference, opts []controllerutil.OwnerReferenceOption, expected []metav1.OwnerReference, expectAlreadyOwned bool) {
+				rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", OwnerReferences: existing}}
+				err := controllerutil.SetControllerReference(dep, rs, scheme.Scheme, opts...)
+				if expectAlreadyOwned {
+					Expect(err).To(BeAssignableToTypeOf(&controllerutil.AlreadyOwnedError{}))
+				} else {
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(rs.OwnerReferences).To(Equal(expected))
+			},
+			Entry("no references",
+				nil, nil,
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, t)}, false),
+			Entry("no references and no blockOwnerDeletion",
+				nil, []controllerutil.OwnerReferenceOption{controllerutil.WithBlockOwnerDeletion(false)},
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, f)}, false),
+			Entry("a reference to another owner",
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", nil, nil)}, nil,
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", nil, nil), ownerRef("foo", "foo-uid", t, t)}, false),
+			Entry("a controller reference to another owner",
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", t, t)}, nil,
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", t, t)}, true),
+			Entry("a controller reference to another owner that is overridden",
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", t, t)}, []controllerutil.OwnerReferenceOption{controllerutil.OverrideControllerReference()},
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", nil, t), ownerRef("foo", "foo-uid", t, t)}, false),
+			Entry("a controller reference to another owner that is overridden without blockOwnerDeletion",
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", t, t)}, []controllerutil.OwnerReferenceOption{controllerutil.OverrideControllerReference(), controllerutil.WithBlockOwnerDeletion(false)},
+				[]metav1.OwnerReference{ownerRef("bar", "bar-uid", nil, t), ownerRef("foo", "foo-uid", t, f)}, false),
+			Entry("a controller reference to another owner that is overridden and a reference to the owner",
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", nil, nil), ownerRef("bar", "bar-uid", t, t)}, []controllerutil.OwnerReferenceOption{controllerutil.OverrideControllerReference()},
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, t), ownerRef("bar", "bar-uid", nil, t)}, false),
+			Entry("a reference to the owner",
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", f, nil)}, nil,
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, t)}, false),
+			Entry("a controller reference to the owner that is overridden",
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, f)}, []controllerutil.OwnerReferenceOption{controllerutil.OverrideControllerReference()},
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, t)}, false),
+			Entry("duplicate references to the owner differing in flags",
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", f, nil), ownerRef("foo", "foo-uid", t, t)}, nil,
+				[]metav1.OwnerReference{ownerRef("foo", "foo-uid", t, t)}, false),
+		)
+
+		It("should return an error if it can't find the group version kind of the owner with options", func() {
+			rs := &appsv1.ReplicaSet{}
+			opts := []controllerutil.OwnerReferenceOption{controllerutil.OverrideControllerReference(), controllerutil.WithBlockOwnerDeletion(false)}
+			Expect(controllerutil.SetOwnerReference(dep, rs, runtime.NewScheme(), opts...)).To(HaveOccurred())
+			Expect(controllerutil.SetControllerReference(dep, rs, runtime.NewScheme(), opts...)).To(HaveOccurred())
+			Expect(rs.OwnerReferences).To(BeEmpty())
+		})
+	})
+
 	Describe("SetControllerReference", func() {
 		It("should set the OwnerReference if it can find the group version kind", func() {
 			rs := &appsv1.ReplicaSet{}
@@ -258,6 +360,75 @@ var _ = Describe("Controllerutil", func() {
 		})
 	})
 
+	Describe("RemoveOwnerReference, HasOwnerReference and HasControllerReference", func() {
+		var (
+			rs         *appsv1.ReplicaSet
+			appsDep    *appsv1.Deployment
+			extDep     *extensionsv1beta1.Deployment
+			otherOwner *corev1.ConfigMap
+		)
+
+		BeforeEach(func() {
+			rs = &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "default"}}
+			appsDep = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "apps-uid"}}
+			extDep = &extensionsv1beta1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "ext-uid"}}
+			otherOwner = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "cm-uid"}}
+
+			Expect(controllerutil.SetOwnerReference(otherOwner, rs, scheme.Scheme)).To(Succeed())
+			Expect(controllerutil.SetControllerReference(appsDep, rs, scheme.Scheme)).To(Succeed())
+			Expect(controllerutil.SetOwnerReference(extDep, rs, scheme.Scheme)).To(Succeed())
+		})
+
+		It("should remove only the reference to the owner of the same group", func() {
+			Expect(controllerutil.HasControllerReference(rs)).To(BeTrue())
+			Expect(controllerutil.RemoveOwnerReference(appsDep, rs, scheme.Scheme)).To(Succeed())
+			Expect(controllerutil.HasControllerReference(rs)).To(BeFalse())
+			Expect(rs.OwnerReferences).To(ConsistOf(
+				metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "foo", UID: "cm-uid"},
+				metav1.OwnerReference{APIVersion: "extensions/v1beta1", Kind: "Deployment", Name: "foo", UID: "ext-uid"},
+			))
+
+			By("reporting which owners are still referenced")
+			Expect(controllerutil.HasOwnerReference(rs.OwnerReferences, appsDep, scheme.Scheme)).To(BeFalse())
+			Expect(controllerutil.HasOwnerReference(rs.OwnerReferences, extDep, scheme.Scheme)).To(BeTrue())
+			Expect(controllerutil.HasOwnerReference(rs.OwnerReferences, otherOwner, scheme.Scheme)).To(BeTrue())
+		})
+
+		It("should match the owner by group, kind and name rather than UID", func() {
+			recreated := &extensionsv1beta1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "new-uid"}}
+			Expect(controllerutil.HasOwnerReference(rs.OwnerReferences, recreated, scheme.Scheme)).To(BeTrue())
+			Expect(controllerutil.RemoveOwnerReference(recreated, rs, scheme.Scheme)).To(Succeed())
+			Expect(controllerutil.HasOwnerReference(rs.OwnerReferences, extDep, scheme.Scheme)).To(BeFalse())
+			Expect(controllerutil.HasOwnerReference(rs.OwnerReferences, appsDep, scheme.Scheme)).To(BeTrue())
+			Expect(controllerutil.HasControllerReference(rs)).To(BeTrue())
+
+			By("matching owners of a different version of the same group")
+			v1beta2Dep := &unstructured.Unstructured{}
+			v1beta2Dep.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1beta2", Kind: "Deployment"})
+			v1beta2Dep.SetName("foo")
+			Expect(controllerutil.HasOwnerReference(rs.OwnerReferences, v1beta2Dep, scheme.Scheme)).To(BeTrue())
+		})
+
+		It("should return an error if the owner isn't referenced", func() {
+			Expect(controllerutil.RemoveOwnerReference(extDep, rs, scheme.Scheme)).To(Succeed())
+			Expect(controllerutil.RemoveOwnerReference(extDep, rs, scheme.Scheme)).To(MatchError(ContainSubstring("has no owner reference to Deployment foo")))
+			Expect(rs.OwnerReferences).To(HaveLen(2))
+		})
+
+		It("should return an error if it can't find the group version kind of the owner", func() {
+			Expect(controllerutil.RemoveOwnerReference(appsDep, rs, runtime.NewScheme())).To(HaveOccurred())
+			_, err := controllerutil.HasOwnerReference(rs.OwnerReferences, appsDep, runtime.NewScheme())
+			Expect(err).To(HaveOccurred())
+			Expect(rs.OwnerReferences).To(HaveLen(3))
+		})
+
+		It("should return an error if the owner isn't a runtime.Object", func() {
+			Expect(controllerutil.RemoveOwnerReference(&errMetaObj{}, rs, scheme.Scheme)).To(HaveOccurred())
+			_, err := controllerutil.HasOwnerReference(rs.OwnerReferences, &errMetaObj{}, scheme.Scheme)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Describe("CreateOrUpdate", func() {
 		var deploy *appsv1.Deployment
 		var deplSpec appsv1.DeploymentSpec
@@ -339,6 +510,27 @@ var _ = Describe("Controllerutil", func() {
 			Expect(*fetched.Spec.Replicas).To(Equal(scale))
 		})
 
+		It("returns the paths of the changed fields", func() {
+			op, changed, err := controllerutil.CreateOrUpdateWithChanges(context.TODO(), c, deploy, specr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+			Expect(changed).To(BeEmpty())
+
+			op, changed, err = controllerutil.CreateOrUpdateWithChanges(context.TODO(), c, deploy, func() error {
+				deploy.Spec.Replicas = pointer.Int32Ptr(5)
+				deploy.Spec.Template.Spec.Containers[0].Image = "nginx"
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdated))
+			Expect(changed).To(Equal([]string{"spec.replicas", "spec.template.spec.containers[0].image"}))
+
+			op, changed, err = controllerutil.CreateOrUpdateWithChanges(context.TODO(), c, deploy, deploymentIdentity)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultNone))
+			Expect(changed).To(BeEmpty())
+		})
+
 		It("updates only changed objects", func() {
 			op, err := controllerutil.CreateOrUpdate(context.TODO(), c, deploy, specr)
 
@@ -512,6 +704,24 @@ var _ = Describe("Controllerutil", func() {
 			assertLocalDeployWasUpdated(fetched)
 		})
 
+		It("returns the paths of the changed fields", func() {
+			op, changed, err := controllerutil.CreateOrPatchWithChanges(context.TODO(), c, deploy, specr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+			Expect(changed).To(BeEmpty())
+
+			op, changed, err = controllerutil.CreateOrPatchWithChanges(context.TODO(), c, deploy, func() error {
+				deploy.Spec.Replicas = pointer.Int32Ptr(5)
+				deploy.Spec.Template.Spec.Containers[0].Image = "nginx"
+				deploy.Status.Replicas = 1
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdatedStatus))
+			Expect(changed).To(Equal([]string{"spec.replicas", "spec.template.spec.containers[0].image", "status.replicas"}))
+			assertLocalDeployWasUpdated(nil)
+		})
+
 		It("patches only changed objects", func() {
 			op, err := controllerutil.CreateOrPatch(context.TODO(), c, deploy, specr)
 
@@ -548,6 +758,122 @@ var _ = Describe("Controllerutil", func() {
 			assertLocalDeployWasUpdated(nil)
 		})
 
+		It("doesn't write if nothing changed semantically", func() {
+			op, err := controllerutil.CreateOrPatch(context.TODO(), c, deploy, specr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+
+			By("building the spec from scratch, without the fields defaulted by the server")
+			counter := &writeCounter{Client: c}
+			op, err = controllerutil.CreateOrPatch(context.TODO(), counter, deploy, func() error {
+				deploy.Labels = map[string]string{}
+				deploy.Spec = *deplSpec.DeepCopy()
+				deploy.Spec.Template.Spec.Volumes = []corev1.Volume{}
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("returning OperationResultNone without any writes")
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultNone))
+			Expect(counter.writes).To(BeEmpty())
+		})
+
+		It("patches the removal of labels, annotations, finalizers and list items", func() {
+			fakeClient := fake.NewClientBuilder().Build()
+			deploy.Labels = map[string]string{"app": "foo"}
+			deploy.Annotations = map[string]string{"example.com/owner": "foo"}
+			deploy.Finalizers = []string{"example.com/cleanup"}
+			deplSpec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "FOO", Value: "bar"}}
+			op, err := controllerutil.CreateOrPatch(context.TODO(), fakeClient, deploy, specr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+
+			removals := map[string]controllerutil.MutateFn{
+				"the only finalizer": func() error {
+					controllerutil.RemoveFinalizer(deploy, "example.com/cleanup")
+					return nil
+				},
+				"the only label": func() error {
+					delete(deploy.Labels, "app")
+					return nil
+				},
+				"the only annotation": func() error {
+					deploy.Annotations = nil
+					return nil
+				},
+				"the only item of a list": func() error {
+					deploy.Spec.Template.Spec.Containers[0].Env = nil
+					return nil
+				},
+			}
+			for name, removal := range removals {
+				By("removing " + name)
+				counter := &writeCounter{Client: fakeClient}
+				op, err = controllerutil.CreateOrPatch(context.TODO(), counter, deploy, removal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdated))
+				Expect(counter.writes).To(Equal([]string{"patch"}))
+			}
+
+			fetched := &appsv1.Deployment{}
+			Expect(fakeClient.Get(context.TODO(), deplKey, fetched)).To(Succeed())
+			Expect(fetched.Finalizers).To(BeEmpty())
+			Expect(fetched.Labels).To(BeEmpty())
+			Expect(fetched.Annotations).To(BeEmpty())
+			Expect(fetched.Spec.Template.Spec.Containers[0].Env).To(BeEmpty())
+		})
+
+		It("returns the paths of the removed metadata and list items", func() {
+			fakeClient := fake.NewClientBuilder().Build()
+			deploy.Finalizers = []string{"example.com/cleanup"}
+			deplSpec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "FOO", Value: "bar"}}
+			_, err := controllerutil.CreateOrPatch(context.TODO(), fakeClient, deploy, specr)
+			Expect(err).NotTo(HaveOccurred())
+
+			op, changed, err := controllerutil.CreateOrPatchWithChanges(context.TODO(), fakeClient, deploy, func() error {
+				deploy.Finalizers = nil
+				deploy.Spec.Template.Spec.Containers[0].Env = nil
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdated))
+			Expect(changed).To(ConsistOf("metadata.finalizers", "spec.template.spec.containers[0].env"))
+		})
+
+		It("only patches the object if only the spec changed", func() {
+			op, err := controllerutil.CreateOrPatch(context.TODO(), c, deploy, specr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+
+			counter := &writeCounter{Client: c}
+			op, err = controllerutil.CreateOrPatch(context.TODO(), counter, deploy, deploymentScaler(deploy, 3))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdated))
+			Expect(counter.writes).To(Equal([]string{"patch"}))
+			assertLocalDeployWasUpdated(nil)
+		})
+
+		It("only patches the status if only the status changed", func() {
+			op, err := controllerutil.CreateOrPatch(context.TODO(), c, deploy, specr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+
+			counter := &writeCounter{Client: c}
+			op, err = controllerutil.CreateOrPatch(context.TODO(), counter, deploy, deploymentStatusr(deploy, appsv1.DeploymentStatus{Replicas: 1}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdatedStatusOnly))
+			Expect(counter.writes).To(Equal([]string{"status patch"}))
+			assertLocalDeployWasUpdated(nil)
+
+			By("patching the status if a field was unset")
+			counter = &writeCounter{Client: c}
+			op, err = controllerutil.CreateOrPatch(context.TODO(), counter, deploy, deploymentStatusr(deploy, appsv1.DeploymentStatus{}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdatedStatusOnly))
+			Expect(counter.writes).To(Equal([]string{"status patch"}))
+			assertLocalDeployWasUpdated(nil)
+		})
+
 		It("patches resource and status", func() {
 			op, err := controllerutil.CreateOrPatch(context.TODO(), c, deploy, specr)
 
@@ -666,6 +992,130 @@ var _ = Describe("Controllerutil", func() {
 		})
 	})
 
+	Describe("CreateOrApply", func() {
+		var name string
+
+		BeforeEach(func() {
+			name = fmt.Sprintf("cm-%d", rand.Int31()) //nolint:gosec
+		})
+
+		// newConfigMap returns an unstructured config map with the name of the test.
+		newConfigMap := func() *unstructured.Unstructured {
+			u := &unstructured.Unstructured{}
+			u.SetAPIVersion("v1")
+			u.SetKind("ConfigMap")
+			u.SetName(name)
+			u.SetNamespace("default")
+			return u
+		}
+
+		// dataSetter returns a MutateFn setting the data of the config map.
+		dataSetter := func(obj *unstructured.Unstructured, data map[string]string) controllerutil.MutateFn {
+			return func() error {
+				return unstructured.SetNestedStringMap(obj.Object, data, "data")
+			}
+		}
+
+		// getData returns the persisted data of the config map.
+		getData := func() map[string]string {
+			cm := &corev1.ConfigMap{}
+			Expect(c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, cm)).To(Succeed())
+			return cm.Data
+		}
+
+		// applyAs applies the data to the config map as the given field manager.
+		applyAs := func(fieldOwner string, data map[string]string) {
+			other := newConfigMap()
+			_, err := controllerutil.CreateOrApply(context.TODO(), c, other, fieldOwner, dataSetter(other, data))
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		It("creates, updates and leaves the object unchanged depending on the resource version", func() {
+			cm := newConfigMap()
+			op, err := controllerutil.CreateOrApply(context.TODO(), c, cm, "test", dataSetter(cm, map[string]string{"a": "1"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultCreated))
+			Expect(cm.GetUID()).NotTo(BeEmpty())
+
+			By("applying the same desired state onto the returned object")
+			op, err = controllerutil.CreateOrApply(context.TODO(), c, cm, "test", dataSetter(cm, map[string]string{"a": "1"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultNone))
+
+			By("applying a changed desired state onto a new object")
+			desired := newConfigMap()
+			op, err = controllerutil.CreateOrApply(context.TODO(), c, desired, "test", dataSetter(desired, map[string]string{"a": "2"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdated))
+			Expect(desired.GetResourceVersion()).NotTo(Equal(cm.GetResourceVersion()))
+			Expect(getData()).To(Equal(map[string]string{"a": "2"}))
+		})
+
+		It("only applies the fields set by the MutateFn", func() {
+			recorder := &patchRecorder{Client: c}
+			cm := newConfigMap()
+			_, err := controllerutil.CreateOrApply(context.TODO(), recorder, cm, "test", dataSetter(cm, map[string]string{"a": "1"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recorder.patches).To(HaveLen(1))
+			Expect(recorder.patches[0]).To(MatchJSON(`{
+				"apiVersion": "v1",
+				"kind": "ConfigMap",
+				"metadata": {"name": "` + name + `", "namespace": "default"},
+				"data": {"a": "1"}
+			}`))
+		})
+
+		It("shares the object with other field managers", func() {
+			applyAs("test", map[string]string{"a": "1", "b": "1"})
+			applyAs("other", map[string]string{"c": "1"})
+
+			By("removing only the fields it stopped owning")
+			applyAs("test", map[string]string{"a": "1"})
+			Expect(getData()).To(Equal(map[string]string{"a": "1", "c": "1"}))
+
+			By("failing with a conflict when changing a field owned by another manager")
+			desired := newConfigMap()
+			_, err := controllerutil.CreateOrApply(context.TODO(), c, desired, "test", dataSetter(desired, map[string]string{"a": "1", "c": "2"}))
+			Expect(apierrors.IsConflict(err)).To(BeTrue())
+
+			By("taking over the field when forcing the ownership")
+			op, err := controllerutil.CreateOrApply(context.TODO(), c, desired, "test", dataSetter(desired, map[string]string{"a": "1", "c": "2"}), client.ForceOwnership)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultUpdated))
+			data, _, err := unstructured.NestedStringMap(desired.Object, "data")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(map[string]string{"a": "1", "c": "2"}))
+		})
+
+		It("errors when MutateFn renames an object", func() {
+			cm := newConfigMap()
+			_, err := controllerutil.CreateOrApply(context.TODO(), c, cm, "test", func() error {
+				cm.SetName("renamed")
+				return nil
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("errors for typed objects", func() {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+			op, err := controllerutil.CreateOrApply(context.TODO(), c, cm, "test", func() error {
+				defer GinkgoRecover()
+				Fail("the MutateFn of a typed object must not be called")
+				return nil
+			})
+			Expect(err).To(MatchError(ContainSubstring("requires an *unstructured.Unstructured object")))
+			Expect(op).To(BeEquivalentTo(controllerutil.OperationResultNone))
+		})
+
+		It("errors for objects without an apiVersion or a kind", func() {
+			u := &unstructured.Unstructured{}
+			u.SetName(name)
+			u.SetNamespace("default")
+			_, err := controllerutil.CreateOrApply(context.TODO(), c, u, "test", func() error { return nil })
+			Expect(err).To(MatchError(ContainSubstring("the apiVersion and the kind of object default/" + name + " must be set")))
+		})
+	})
+
 	Describe("Finalizers", func() {
 		var deploy *appsv1.Deployment
 
@@ -767,6 +1217,112 @@ var _ = Describe("Controllerutil", func() {
 				Expect(controllerutil.ContainsFinalizer(deploy, testFinalizer)).To(Equal(false))
 			})
 		})
+
+		Describe("EnsureFinalizer and RemoveFinalizerAndPatch", func() {
+			var cm *corev1.ConfigMap
+
+			BeforeEach(func() {
+				cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+					Name:       fmt.Sprintf("cm-%d", rand.Int31()), //nolint:gosec
+					Namespace:  "default",
+					Finalizers: []string{testFinalizer1},
+				}}
+			})
+
+			// modifyConcurrently modifies the object like another writer would, so cm is stale.
+			modifyConcurrently := func(c client.Client) {
+				other := cm.DeepCopy()
+				other.Labels = map[string]string{"modified": "concurrently"}
+				Expect(c.Update(context.TODO(), other)).To(Succeed())
+			}
+
+			// assertFinalizersAndPatches asserts the behavior shared by the API server and the
+			// fake client, which is returned by getClient.
+			assertFinalizersAndPatches := func(getClient func() client.Client) {
+				var c client.Client
+				BeforeEach(func() {
+					c = getClient()
+				})
+
+				It("should add and remove the finalizer, persisting only the finalizers", func() {
+					Expect(c.Create(context.TODO(), cm)).To(Succeed())
+					cm.Data = map[string]string{"not": "persisted"}
+
+					counter := &writeCounter{Client: c}
+					Expect(controllerutil.EnsureFinalizer(context.TODO(), counter, cm, testFinalizer)).To(BeTrue())
+					Expect(cm.Finalizers).To(Equal([]string{testFinalizer1, testFinalizer}))
+					Expect(cm.Data).To(BeEmpty())
+					Expect(controllerutil.EnsureFinalizer(context.TODO(), counter, cm, testFinalizer)).To(BeFalse())
+					Expect(counter.writes).To(Equal([]string{"patch"}))
+
+					fetched := &corev1.ConfigMap{}
+					Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(cm), fetched)).To(Succeed())
+					Expect(fetched.Finalizers).To(Equal([]string{testFinalizer1, testFinalizer}))
+					Expect(fetched.ResourceVersion).To(Equal(cm.ResourceVersion))
+
+					Expect(controllerutil.RemoveFinalizerAndPatch(context.TODO(), counter, cm, testFinalizer1)).To(BeTrue())
+					Expect(cm.Finalizers).To(Equal([]string{testFinalizer}))
+					Expect(controllerutil.RemoveFinalizerAndPatch(context.TODO(), counter, cm, testFinalizer1)).To(BeFalse())
+					Expect(controllerutil.RemoveFinalizerAndPatch(context.TODO(), counter, cm, testFinalizer)).To(BeTrue())
+					Expect(cm.Finalizers).To(BeEmpty())
+					Expect(counter.writes).To(Equal([]string{"patch", "patch", "patch"}))
+				})
+
+				It("should retry once with the current object on conflict", func() {
+					Expect(c.Create(context.TODO(), cm)).To(Succeed())
+					modifyConcurrently(c)
+
+					Expect(controllerutil.EnsureFinalizer(context.TODO(), c, cm, testFinalizer)).To(BeTrue())
+					Expect(cm.Labels).To(HaveKeyWithValue("modified", "concurrently"))
+					Expect(cm.Finalizers).To(Equal([]string{testFinalizer1, testFinalizer}))
+
+					modifyConcurrently(c)
+					Expect(controllerutil.RemoveFinalizerAndPatch(context.TODO(), c, cm, testFinalizer1)).To(BeTrue())
+					Expect(controllerutil.RemoveFinalizerAndPatch(context.TODO(), c, cm, testFinalizer)).To(BeTrue())
+
+					fetched := &corev1.ConfigMap{}
+					Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(cm), fetched)).To(Succeed())
+					Expect(fetched.Finalizers).To(BeEmpty())
+				})
+			}
+
+			Context("with the API server", func() {
+				assertFinalizersAndPatches(func() client.Client { return c })
+			})
+
+			Context("with the fake client", func() {
+				var fakeClient client.Client
+				BeforeEach(func() {
+					fakeClient = fake.NewClientBuilder().Build()
+				})
+
+				assertFinalizersAndPatches(func() client.Client { return fakeClient })
+
+				It("should delete the object once the last finalizer of a deleted object is removed", func() {
+					Expect(fakeClient.Create(context.TODO(), cm)).To(Succeed())
+					Expect(controllerutil.EnsureFinalizer(context.TODO(), fakeClient, cm, testFinalizer)).To(BeTrue())
+					Expect(fakeClient.Delete(context.TODO(), cm)).To(Succeed())
+					Expect(fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+					Expect(cm.DeletionTimestamp).NotTo(BeNil())
+
+					Expect(controllerutil.RemoveFinalizerAndPatch(context.TODO(), fakeClient, cm, testFinalizer1)).To(BeTrue())
+					Expect(controllerutil.RemoveFinalizerAndPatch(context.TODO(), fakeClient, cm, testFinalizer)).To(BeTrue())
+					err := fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				})
+
+				It("should fail if the retry conflicts too", func() {
+					Expect(fakeClient.Create(context.TODO(), cm)).To(Succeed())
+					counter := &writeCounter{Client: conflictingPatcher{fakeClient}}
+
+					changed, err := controllerutil.EnsureFinalizer(context.TODO(), counter, cm, testFinalizer)
+					Expect(apierrors.IsConflict(err)).To(BeTrue())
+					Expect(changed).To(BeFalse())
+					Expect(cm.Finalizers).To(Equal([]string{testFinalizer1}))
+					Expect(counter.writes).To(Equal([]string{"patch", "patch"}))
+				})
+			})
+		})
 	})
 })
 
@@ -835,3 +1391,62 @@ type errorReader struct {
 func (e errorReader) Get(ctx context.Context, key client.ObjectKey, into client.Object, opts ...client.GetOption) error {
 	return fmt.Errorf("unexpected error")
 }
+
+// writeCounter records the writes issued through the client.
+type writeCounter struct {
+	client.Client
+	writes []string
+}
+
+func (w *writeCounter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	w.writes = append(w.writes, "update")
+	return w.Client.Update(ctx, obj, opts...)
+}
+
+func (w *writeCounter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	w.writes = append(w.writes, "patch")
+	return w.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (w *writeCounter) Status() client.StatusWriter {
+	return &statusWriteCounter{StatusWriter: w.Client.Status(), counter: w}
+}
+
+type statusWriteCounter struct {
+	client.StatusWriter
+	counter *writeCounter
+}
+
+func (w *statusWriteCounter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	w.counter.writes = append(w.counter.writes, "status update")
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func (w *statusWriteCounter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	w.counter.writes = append(w.counter.writes, "status patch")
+	return w.StatusWriter.Patch(ctx, obj, patch, opts...)
+}
+
+// conflictingPatcher fails all patches with a conflict.
+type conflictingPatcher struct {
+	client.Client
+}
+
+func (c conflictingPatcher) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), fmt.Errorf("object was modified"))
+}
+
+// patchRecorder records the data of the patches issued through the client.
+type patchRecorder struct {
+	client.Client
+	patches []string
+}
+
+func (r *patchRecorder) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return err
+	}
+	r.patches = append(r.patches, string(data))
+	return r.Client.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("changedPaths", func() {
+	It("should report changed, added and removed fields of maps", func() {
+		before := map[string]interface{}{
+			"a": "a",
+			"b": map[string]interface{}{"c": int64(1), "d": int64(2)},
+			"e": "e",
+		}
+		after := map[string]interface{}{
+			"a": "a",
+			"b": map[string]interface{}{"c": int64(3)},
+			"f": map[string]interface{}{"g": "g"},
+		}
+		Expect(changedPaths(after, before, false, "", nil)).To(Equal([]string{"b.c", "b.d", "e", "f.g"}))
+
+		By("ignoring unset fields")
+		Expect(changedPaths(after, before, true, "", nil)).To(Equal([]string{"b.c", "f.g"}))
+	})
+
+	It("should report changed, added and removed items of lists by index", func() {
+		before := map[string]interface{}{"l": []interface{}{
+			map[string]interface{}{"image": "a"},
+			map[string]interface{}{"image": "b"},
+			map[string]interface{}{"image": "c"},
+		}}
+		after := map[string]interface{}{"l": []interface{}{
+			map[string]interface{}{"image": "a"},
+			map[string]interface{}{"image": "d"},
+		}}
+		Expect(changedPaths(after, before, false, "spec", nil)).To(Equal([]string{"spec.l[1].image", "spec.l[2]"}))
+		Expect(changedPaths(before, after, false, "spec", nil)).To(Equal([]string{"spec.l[1].image", "spec.l[2]"}))
+	})
+
+	It("should consider nil and empty maps and lists equal", func() {
+		before := map[string]interface{}{"m": nil, "l": []interface{}{}}
+		after := map[string]interface{}{"m": map[string]interface{}{}, "n": []interface{}{}}
+		Expect(changedPaths(after, before, false, "", nil)).To(BeEmpty())
+	})
+
+	It("should quote keys that aren't simple names", func() {
+		after := map[string]interface{}{"labels": map[string]interface{}{"app.kubernetes.io/name": "a"}}
+		Expect(changedPaths(after, nil, false, "metadata", nil)).To(Equal([]string{`metadata.labels["app.kubernetes.io/name"]`}))
+	})
+
+	It("should cap the number of paths", func() {
+		after := map[string]interface{}{}
+		for i := 0; i < 2*maxChangedPaths; i++ {
+			after[fmt.Sprintf("key%02d", i)] = int64(i)
+		}
+		paths := truncatePaths(changedPaths(after, nil, false, "", nil))
+		Expect(paths).To(HaveLen(maxChangedPaths + 1))
+		Expect(paths[0]).To(Equal("key00"))
+		Expect(paths[maxChangedPaths]).To(Equal("..."))
+	})
+})
+
+func BenchmarkCreateOrUpdate(b *testing.B) {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "busybox", Image: "busybox"}}},
+			},
+		},
+	}
+	ctx := context.Background()
+
+	for _, bench := range []struct {
+		name           string
+		collectChanges bool
+	}{
+		{name: "WithoutChanges", collectChanges: false},
+		{name: "WithChanges", collectChanges: true},
+	} {
+		bench := bench
+		b.Run(bench.name, func(b *testing.B) {
+			c := fake.NewClientBuilder().WithObjects(deploy.DeepCopy()).Build()
+			obj := deploy.DeepCopy()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				replicas := int32(i)
+				if _, _, err := createOrUpdate(ctx, c, obj, func() error {
+					obj.Spec.Replicas = &replicas
+					return nil
+				}, bench.collectChanges); err != nil {
+					b.Fatalf("expected no error, got %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// PrunedObject identifies an object that was deleted by PruneOwned, or would have
+// been in a dry run.
+type PrunedObject struct {
+	GroupVersionKind schema.GroupVersionKind
+	Key              client.ObjectKey
+}
+
+// String returns the kind, namespace and name of the object.
+func (p PrunedObject) String() string {
+	return fmt.Sprintf("%s %s", p.GroupVersionKind.GroupKind(), p.Key)
+}
+
+// PruneOption configures how PruneOwned lists and deletes objects.
+type PruneOption func(*pruneOptions)
+
+type pruneOptions struct {
+	listOptions       []client.ListOption
+	propagationPolicy *metav1.DeletionPropagation
+	dryRun            bool
+}
+
+// WithPruneListOptions sets options used to list the candidates, such as a label
+// selector restricting them to the set of objects managed by the caller. If a limit
+// is set, all the pages are listed.
+func WithPruneListOptions(opts ...client.ListOption) PruneOption {
+	return func(o *pruneOptions) {
+		o.listOptions = append(o.listOptions, opts...)
+	}
+}
+
+// WithPrunePropagationPolicy sets the propagation policy used to delete the objects.
+// The default policy of the objects is used if it's not set.
+func WithPrunePropagationPolicy(policy metav1.DeletionPropagation) PruneOption {
+	return func(o *pruneOptions) {
+		o.propagationPolicy = &policy
+	}
+}
+
+// PruneDryRun makes PruneOwned return the objects it would delete without deleting them.
+func PruneDryRun() PruneOption {
+	return func(o *pruneOptions) {
+		o.dryRun = true
+	}
+}
+
+// PruneOwned deletes the objects controlled by owner that aren't desired anymore. The
+// candidates are listed with each of the given list types, in the namespace of owner
+// if it's namespaced and in all namespaces otherwise, and only the ones with a controller
+// reference to owner are considered. Candidates are matched against the desired objects
+// by group, kind, namespace and name, so the desired objects don't need to exist yet,
+// and an empty desired set deletes all the controlled objects.
+//
+// It returns the objects that were deleted, or that would have been with PruneDryRun.
+// Objects that are gone or were recreated in the meantime are skipped.
+func PruneOwned(ctx context.Context, c client.Client, owner client.Object, desired []client.Object, listTypes []client.ObjectList, opts ...PruneOption) ([]PrunedObject, error) {
+	options := pruneOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	keep := make(map[PrunedObject]struct{}, len(desired))
+	for _, obj := range desired {
+		gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+		if err != nil {
+			return nil, err
+		}
+		keep[pruneKey(gvk, client.ObjectKeyFromObject(obj))] = struct{}{}
+	}
+
+	listOpts := options.listOptions
+	if owner.GetNamespace() != "" {
+		listOpts = append([]client.ListOption{client.InNamespace(owner.GetNamespace())}, listOpts...)
+	}
+
+	var pruned []PrunedObject
+	for _, list := range listTypes {
+		orphans, err := listOrphans(ctx, c, owner, list, keep, listOpts)
+		if err != nil {
+			return pruned, err
+		}
+		for _, orphan := range orphans {
+			if !options.dryRun {
+				uid := orphan.GetUID()
+				deleteOpts := []client.DeleteOption{client.Preconditions{UID: &uid}}
+				if options.propagationPolicy != nil {
+					deleteOpts = append(deleteOpts, client.PropagationPolicy(*options.propagationPolicy))
+				}
+				if err := c.Delete(ctx, orphan, deleteOpts...); err != nil {
+					if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+						continue
+					}
+					return pruned, err
+				}
+			}
+			gvk, err := apiutil.GVKForObject(orphan, c.Scheme())
+			if err != nil {
+				return pruned, err
+			}
+			pruned = append(pruned, PrunedObject{GroupVersionKind: gvk, Key: client.ObjectKeyFromObject(orphan)})
+		}
+	}
+	return pruned, nil
+}
+
+// listOrphans lists all the pages of the list type, and returns the items controlled by
+// owner that aren't kept.
+func listOrphans(ctx context.Context, c client.Client, owner client.Object, list client.ObjectList, keep map[PrunedObject]struct{}, opts []client.ListOption) ([]client.Object, error) {
+	gvk, err := apiutil.GVKForList(list, c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []client.Object
+	pageOpts := opts
+	for {
+		if err := c.List(ctx, list, pageOpts...); err != nil {
+			return nil, err
+		}
+		if err := meta.EachListItem(list, func(item runtime.Object) error {
+			obj, ok := item.(client.Object)
+			if !ok {
+				return fmt.Errorf("%T is not a client.Object", item)
+			}
+			if ref := metav1.GetControllerOf(obj); ref == nil || ref.UID != owner.GetUID() {
+				return nil
+			}
+			if _, ok := keep[pruneKey(gvk, client.ObjectKeyFromObject(obj))]; !ok {
+				orphans = append(orphans, obj.DeepCopyObject().(client.Object))
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		next := list.GetContinue()
+		if next == "" {
+			return orphans, nil
+		}
+		pageOpts = append(opts[:len(opts):len(opts)], client.Continue(next))
+	}
+}
+
+// pruneKey returns the key of an object when matching candidates against the desired
+// objects, which ignores the version.
+func pruneKey(gvk schema.GroupVersionKind, key client.ObjectKey) PrunedObject {
+	return PrunedObject{GroupVersionKind: schema.GroupVersionKind{Group: gvk.Group, Kind: gvk.Kind}, Key: key}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllerutil_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("PruneOwned", func() {
+	var (
+		c     client.Client
+		owner *appsv1.Deployment
+	)
+
+	configMap := func(namespace, name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	}
+	service := func(namespace, name string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+	// ownedBy sets the controller reference of the objects to the owner.
+	ownedBy := func(owner client.Object, objs ...client.Object) []client.Object {
+		for _, obj := range objs {
+			Expect(controllerutil.SetControllerReference(owner, obj, scheme.Scheme)).To(Succeed())
+		}
+		return objs
+	}
+	// remaining returns the names of the config maps and services that still exist.
+	remaining := func() []string {
+		var names []string
+		cms := &corev1.ConfigMapList{}
+		Expect(c.List(context.TODO(), cms)).To(Succeed())
+		for _, cm := range cms.Items {
+			names = append(names, "cm/"+cm.Namespace+"/"+cm.Name)
+		}
+		svcs := &corev1.ServiceList{}
+		Expect(c.List(context.TODO(), svcs)).To(Succeed())
+		for _, svc := range svcs.Items {
+			names = append(names, "svc/"+svc.Namespace+"/"+svc.Name)
+		}
+		return names
+	}
+	listTypes := []client.ObjectList{&corev1.ConfigMapList{}, &corev1.ServiceList{}}
+
+	BeforeEach(func() {
+		owner = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "owner", UID: "owner-uid"}}
+		other := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other", UID: "other-uid"}}
+
+		objs := ownedBy(owner,
+			configMap("default", "a", map[string]string{"app": "owner"}),
+			configMap("default", "b", map[string]string{"app": "owner"}),
+			configMap("default", "unlabeled", nil),
+			service("default", "a"),
+			service("default", "b"),
+		)
+		objs = append(objs, ownedBy(other, configMap("default", "other", map[string]string{"app": "owner"}))...)
+		objs = append(objs, configMap("default", "unowned", map[string]string{"app": "owner"}))
+		c = fake.NewClientBuilder().WithObjects(objs...).Build()
+	})
+
+	It("should delete the controlled objects of all the list types that aren't desired", func() {
+		pruned, err := controllerutil.PruneOwned(context.TODO(), c, owner,
+			[]client.Object{configMap("default", "a", nil), service("default", "a")}, listTypes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(ConsistOf(
+			controllerutil.PrunedObject{GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap"), Key: client.ObjectKey{Namespace: "default", Name: "b"}},
+			controllerutil.PrunedObject{GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap"), Key: client.ObjectKey{Namespace: "default", Name: "unlabeled"}},
+			controllerutil.PrunedObject{GroupVersionKind: corev1.SchemeGroupVersion.WithKind("Service"), Key: client.ObjectKey{Namespace: "default", Name: "b"}},
+		))
+		Expect(remaining()).To(ConsistOf("cm/default/a", "cm/default/other", "cm/default/unowned", "svc/default/a"))
+	})
+
+	It("should match the desired objects by kind as well as name", func() {
+		_, err := controllerutil.PruneOwned(context.TODO(), c, owner,
+			[]client.Object{configMap("default", "a", nil), configMap("default", "b", nil), configMap("default", "unlabeled", nil)}, listTypes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(remaining()).To(ConsistOf("cm/default/a", "cm/default/b", "cm/default/unlabeled", "cm/default/other", "cm/default/unowned"))
+	})
+
+	It("should delete all the controlled objects if none is desired", func() {
+		pruned, err := controllerutil.PruneOwned(context.TODO(), c, owner, nil, listTypes,
+			controllerutil.WithPrunePropagationPolicy(metav1.DeletePropagationForeground))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(HaveLen(5))
+		Expect(remaining()).To(ConsistOf("cm/default/other", "cm/default/unowned"))
+	})
+
+	It("should only consider the candidates matching the list options", func() {
+		pruned, err := controllerutil.PruneOwned(context.TODO(), c, owner, nil, listTypes,
+			controllerutil.WithPruneListOptions(client.MatchingLabels{"app": "owner"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(HaveLen(2))
+		Expect(remaining()).To(ConsistOf("cm/default/unlabeled", "cm/default/other", "cm/default/unowned", "svc/default/a", "svc/default/b"))
+	})
+
+	It("should return the objects that would be deleted in a dry run", func() {
+		pruned, err := controllerutil.PruneOwned(context.TODO(), c, owner, nil, listTypes, controllerutil.PruneDryRun())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(HaveLen(5))
+		Expect(pruned[0].String()).To(Equal("ConfigMap default/a"))
+		Expect(remaining()).To(HaveLen(7))
+	})
+
+	It("should consider the children in all namespaces of cluster-scoped owners", func() {
+		clusterOwner := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "cluster-owner", UID: "cluster-owner-uid"}}
+		for _, obj := range ownedBy(clusterOwner, configMap("ns1", "a", nil), configMap("ns2", "a", nil), configMap("ns2", "b", nil)) {
+			Expect(c.Create(context.TODO(), obj)).To(Succeed())
+		}
+
+		pruned, err := controllerutil.PruneOwned(context.TODO(), c, clusterOwner, []client.Object{configMap("ns2", "a", nil)}, listTypes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(HaveLen(2))
+		Expect(remaining()).To(ContainElement("cm/ns2/a"))
+		Expect(remaining()).NotTo(ContainElements("cm/ns1/a", "cm/ns2/b"))
+		Expect(remaining()).To(HaveLen(8))
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package priorityqueue defines the interface of workqueues that hand out
+// items in order of their priority, and an implementation of it that can be
+// used as the queue of a controller, see controller.Options.UsePriorityQueue.
+package priorityqueue
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// AddOpts describes how an item is added to a PriorityQueue.
+type AddOpts struct {
+	// After delays adding the item by the given duration.
+	After time.Duration
+
+	// RateLimited delays adding the item by the duration returned
+	// from the queue's rate limiter. It is ignored if After is set.
+	RateLimited bool
+
+	// Priority of the item. Items with a higher priority are handed
+	// out first. Defaults to 0.
+	Priority int
+}
+
+// PriorityQueue is a workqueue.RateLimitingInterface that orders items by
+// priority. Controllers detect queues implementing it and use the
+// priority-aware methods instead of their workqueue.RateLimitingInterface
+// counterparts.
+type PriorityQueue interface {
+	workqueue.RateLimitingInterface
+
+	// AddWithOpts adds the items to the queue with the given options.
+	// If an item is already queued, it keeps the higher of the two
+	// priorities.
+	AddWithOpts(o AddOpts, items ...interface{})
+
+	// GetWithPriority returns the next item to process along with
+	// the priority it was queued with.
+	GetWithPriority() (item interface{}, priority int, shutdown bool)
+}
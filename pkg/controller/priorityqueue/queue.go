@@ -0,0 +1,393 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorityqueue
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+)
+
+// Options are the options of a PriorityQueue created with New.
+type Options struct {
+	// RateLimiter computes the delay of the items added with AddRateLimited.
+	// Defaults to workqueue.DefaultControllerRateLimiter().
+	RateLimiter ratelimiter.RateLimiter
+
+	// MetricsProvider creates the metrics of the queue, which are named after
+	// the queue. Defaults to metrics.WorkqueueMetricsProvider, so that the queue
+	// reports the same metrics as the workqueues of controllers.
+	MetricsProvider workqueue.MetricsProvider
+}
+
+// unfinishedWorkUpdatePeriod is how often the metrics of the items being processed
+// are updated.
+const unfinishedWorkUpdatePeriod = 500 * time.Millisecond
+
+// New returns a PriorityQueue with the given name.
+//
+// Like the workqueues of client-go, an item is queued at most once: adding an item
+// that is already queued only raises its priority if the new one is higher, and an
+// item added while it's being processed is queued again once it's Done. Items of
+// the same priority are handed out in the order they were added.
+func New(name string, o Options) PriorityQueue {
+	if o.RateLimiter == nil {
+		o.RateLimiter = workqueue.DefaultControllerRateLimiter()
+	}
+	if o.MetricsProvider == nil {
+		o.MetricsProvider = metrics.WorkqueueMetricsProvider{}
+	}
+
+	q := &priorityQueue{
+		rateLimiter: o.RateLimiter,
+		items:       map[interface{}]*entry{},
+		processing:  map[interface{}]time.Time{},
+		waiting:     map[interface{}]*waiter{},
+		stopped:     make(chan struct{}),
+
+		depth:                   o.MetricsProvider.NewDepthMetric(name),
+		adds:                    o.MetricsProvider.NewAddsMetric(name),
+		latency:                 o.MetricsProvider.NewLatencyMetric(name),
+		workDuration:            o.MetricsProvider.NewWorkDurationMetric(name),
+		unfinishedWorkSeconds:   o.MetricsProvider.NewUnfinishedWorkSecondsMetric(name),
+		longestRunningProcessor: o.MetricsProvider.NewLongestRunningProcessorSecondsMetric(name),
+		retries:                 o.MetricsProvider.NewRetriesMetric(name),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	go q.updateUnfinishedWorkLoop()
+	return q
+}
+
+// entry is an item that is queued, or that was added again while being processed.
+type entry struct {
+	item     interface{}
+	priority int
+	// seq orders the entries of the same priority by the time they were added.
+	seq     uint64
+	addedAt time.Time
+	// index is the index of the entry in the heap, or -1 if it waits for the
+	// processing of its item to be done.
+	index int
+}
+
+// waiter is an item whose addition is delayed.
+type waiter struct {
+	readyAt  time.Time
+	priority int
+	timer    *time.Timer
+}
+
+type priorityQueue struct {
+	rateLimiter ratelimiter.RateLimiter
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// heap holds the queued entries, the one with the highest priority first.
+	heap entryHeap
+	// items holds the entries of the items that are queued or that were added
+	// again while being processed.
+	items map[interface{}]*entry
+	// processing holds the items that are being processed, with the time their
+	// processing started.
+	processing map[interface{}]time.Time
+	// waiting holds the items whose addition is delayed.
+	waiting      map[interface{}]*waiter
+	seq          uint64
+	shuttingDown bool
+	drain        bool
+	stopped      chan struct{}
+
+	depth                   workqueue.GaugeMetric
+	adds                    workqueue.CounterMetric
+	latency                 workqueue.HistogramMetric
+	workDuration            workqueue.HistogramMetric
+	unfinishedWorkSeconds   workqueue.SettableGaugeMetric
+	longestRunningProcessor workqueue.SettableGaugeMetric
+	retries                 workqueue.CounterMetric
+}
+
+var _ PriorityQueue = &priorityQueue{}
+
+// Add adds an item with the default priority.
+func (q *priorityQueue) Add(item interface{}) {
+	q.AddWithOpts(AddOpts{}, item)
+}
+
+// AddAfter adds an item with the default priority after the given delay.
+func (q *priorityQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.AddWithOpts(AddOpts{After: duration}, item)
+}
+
+// AddRateLimited adds an item with the default priority once the rate limiter says it's ok.
+func (q *priorityQueue) AddRateLimited(item interface{}) {
+	q.AddWithOpts(AddOpts{RateLimited: true}, item)
+}
+
+// AddWithOpts implements PriorityQueue.
+func (q *priorityQueue) AddWithOpts(o AddOpts, items ...interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, item := range items {
+		if q.shuttingDown {
+			return
+		}
+		delay := o.After
+		if delay <= 0 && o.RateLimited {
+			q.retries.Inc()
+			delay = q.rateLimiter.When(item)
+		}
+		if delay <= 0 {
+			q.add(item, o.Priority)
+			continue
+		}
+		q.addAfter(item, o.Priority, delay)
+	}
+}
+
+// add adds an item right away. It must be called with the lock held.
+func (q *priorityQueue) add(item interface{}, priority int) {
+	if e, ok := q.items[item]; ok {
+		if priority > e.priority {
+			e.priority = priority
+			if e.index >= 0 {
+				heap.Fix(&q.heap, e.index)
+			}
+		}
+		return
+	}
+
+	q.adds.Inc()
+	q.seq++
+	e := &entry{item: item, priority: priority, seq: q.seq, addedAt: time.Now(), index: -1}
+	q.items[item] = e
+	if _, ok := q.processing[item]; ok {
+		// The item is queued once its processing is done.
+		return
+	}
+	q.push(e)
+}
+
+// addAfter adds an item after the given delay. An item whose addition is already
+// delayed is added at the earlier of the two times, with the higher of the two
+// priorities. It must be called with the lock held.
+func (q *priorityQueue) addAfter(item interface{}, priority int, delay time.Duration) {
+	readyAt := time.Now().Add(delay)
+	w, ok := q.waiting[item]
+	if ok {
+		if priority > w.priority {
+			w.priority = priority
+		}
+		if !readyAt.Before(w.readyAt) {
+			return
+		}
+		w.timer.Stop()
+	} else {
+		w = &waiter{priority: priority}
+		q.waiting[item] = w
+	}
+	w.readyAt = readyAt
+	w.timer = time.AfterFunc(delay, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		// The waiter was replaced if its time was moved earlier.
+		if q.waiting[item] != w || q.shuttingDown {
+			return
+		}
+		delete(q.waiting, item)
+		q.add(item, w.priority)
+	})
+}
+
+// push queues an entry. It must be called with the lock held.
+func (q *priorityQueue) push(e *entry) {
+	heap.Push(&q.heap, e)
+	q.depth.Inc()
+	q.cond.Signal()
+}
+
+// Get returns the next item with the default priority of 0, see GetWithPriority.
+func (q *priorityQueue) Get() (interface{}, bool) {
+	item, _, shutdown := q.GetWithPriority()
+	return item, shutdown
+}
+
+// GetWithPriority implements PriorityQueue. It blocks until an item is queued, and
+// returns the queued item with the highest priority, which must be marked Done once
+// processed.
+func (q *priorityQueue) GetWithPriority() (interface{}, int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.heap) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.heap) == 0 {
+		// The queue is shut down.
+		return nil, 0, true
+	}
+
+	e := heap.Pop(&q.heap).(*entry)
+	delete(q.items, e.item)
+	q.depth.Dec()
+	q.latency.Observe(time.Since(e.addedAt).Seconds())
+	q.processing[e.item] = time.Now()
+	return e.item, e.priority, false
+}
+
+// Done marks an item as processed, and queues it again if it was added during its
+// processing.
+func (q *priorityQueue) Done(item interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if startedAt, ok := q.processing[item]; ok {
+		q.workDuration.Observe(time.Since(startedAt).Seconds())
+		delete(q.processing, item)
+	}
+	if e, ok := q.items[item]; ok && e.index < 0 {
+		q.push(e)
+	}
+	if len(q.processing) == 0 {
+		// Wakes up ShutDownWithDrain.
+		q.cond.Broadcast()
+	}
+}
+
+// Forget makes the rate limiter forget about the item.
+func (q *priorityQueue) Forget(item interface{}) {
+	q.rateLimiter.Forget(item)
+}
+
+// NumRequeues returns the number of times the item was requeued by the rate limiter.
+func (q *priorityQueue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+// Len returns the number of queued items.
+func (q *priorityQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.heap)
+}
+
+// ShutDown makes the queue ignore the items added from now on, and makes the
+// workers return once the queued items are handed out.
+func (q *priorityQueue) ShutDown() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shutDown()
+}
+
+// ShutDownWithDrain is like ShutDown, but blocks until the processing of all the
+// items handed out is done.
+func (q *priorityQueue) ShutDownWithDrain() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.drain = true
+	q.shutDown()
+	for q.drain && len(q.processing) > 0 {
+		q.cond.Wait()
+	}
+}
+
+// shutDown must be called with the lock held.
+func (q *priorityQueue) shutDown() {
+	if !q.shuttingDown {
+		q.shuttingDown = true
+		for item, w := range q.waiting {
+			w.timer.Stop()
+			delete(q.waiting, item)
+		}
+		close(q.stopped)
+	}
+	q.cond.Broadcast()
+}
+
+// ShuttingDown returns whether the queue is shutting down.
+func (q *priorityQueue) ShuttingDown() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.shuttingDown
+}
+
+// updateUnfinishedWorkLoop updates the metrics of the items being processed until
+// the queue is shut down.
+func (q *priorityQueue) updateUnfinishedWorkLoop() {
+	ticker := time.NewTicker(unfinishedWorkUpdatePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stopped:
+			return
+		case <-ticker.C:
+		}
+
+		q.mu.Lock()
+		now := time.Now()
+		var total, longest float64
+		for _, startedAt := range q.processing {
+			running := now.Sub(startedAt).Seconds()
+			total += running
+			if running > longest {
+				longest = running
+			}
+		}
+		q.mu.Unlock()
+		q.unfinishedWorkSeconds.Set(total)
+		q.longestRunningProcessor.Set(longest)
+	}
+}
+
+// entryHeap implements heap.Interface, ordering entries by descending priority
+// and then by the order they were added.
+type entryHeap []*entry
+
+func (h entryHeap) Len() int { return len(h) }
+
+func (h entryHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h entryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *entryHeap) Push(x interface{}) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *entryHeap) Pop() interface{} {
+	old := *h
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	e.index = -1
+	*h = old[:len(old)-1]
+	return e
+}
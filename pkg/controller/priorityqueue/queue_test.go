@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priorityqueue
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var _ = Describe("PriorityQueue", func() {
+	var q PriorityQueue
+
+	BeforeEach(func() {
+		q = New("test", Options{RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(10*time.Millisecond, time.Second)})
+	})
+	AfterEach(func() {
+		q.ShutDown()
+	})
+
+	getAll := func(n int) []interface{} {
+		var items []interface{}
+		for i := 0; i < n; i++ {
+			item, _, shutdown := q.GetWithPriority()
+			Expect(shutdown).To(BeFalse())
+			items = append(items, item)
+			q.Done(item)
+		}
+		return items
+	}
+
+	It("should hand out items by descending priority and in the order they were added", func() {
+		q.AddWithOpts(AddOpts{Priority: -1}, "low")
+		q.Add("first")
+		q.AddWithOpts(AddOpts{Priority: 10}, "high")
+		q.Add("second")
+
+		Expect(q.Len()).To(Equal(4))
+		Expect(getAll(4)).To(Equal([]interface{}{"high", "first", "second", "low"}))
+		Expect(q.Len()).To(BeZero())
+	})
+
+	It("should queue an item once with the highest of its priorities", func() {
+		q.Add("other")
+		q.AddWithOpts(AddOpts{Priority: 5}, "item")
+		q.AddWithOpts(AddOpts{Priority: -5}, "item")
+		Expect(q.Len()).To(Equal(2))
+
+		item, priority, _ := q.GetWithPriority()
+		Expect(item).To(Equal("item"))
+		Expect(priority).To(Equal(5))
+		q.Done(item)
+
+		q.AddWithOpts(AddOpts{Priority: 10}, "other")
+		item, priority, _ = q.GetWithPriority()
+		Expect(item).To(Equal("other"))
+		Expect(priority).To(Equal(10))
+	})
+
+	It("should queue an item added while it's processed once it's done", func() {
+		q.Add("item")
+		item, _ := q.Get()
+		q.AddWithOpts(AddOpts{Priority: 3}, "item")
+		Expect(q.Len()).To(BeZero())
+
+		q.Done(item)
+		Expect(q.Len()).To(Equal(1))
+		item, priority, _ := q.GetWithPriority()
+		Expect(item).To(Equal("item"))
+		Expect(priority).To(Equal(3))
+	})
+
+	It("should add delayed items after their delay", func() {
+		q.AddWithOpts(AddOpts{After: 100 * time.Millisecond, Priority: 1}, "item")
+		q.AddAfter("item", time.Hour)
+		Expect(q.Len()).To(BeZero())
+
+		Eventually(q.Len).Should(Equal(1))
+		item, priority, _ := q.GetWithPriority()
+		Expect(item).To(Equal("item"))
+		Expect(priority).To(Equal(1))
+	})
+
+	It("should delay rate limited items with the rate limiter", func() {
+		q.AddRateLimited("item")
+		q.AddRateLimited("item")
+		Expect(q.NumRequeues("item")).To(Equal(2))
+		Eventually(q.Len).Should(Equal(1))
+
+		q.Forget("item")
+		Expect(q.NumRequeues("item")).To(BeZero())
+	})
+
+	It("should hand out the queued items and then return once shut down", func() {
+		q.Add("item")
+		q.ShutDown()
+		Expect(q.ShuttingDown()).To(BeTrue())
+		q.Add("ignored")
+
+		item, shutdown := q.Get()
+		Expect(item).To(Equal("item"))
+		Expect(shutdown).To(BeFalse())
+		_, shutdown = q.Get()
+		Expect(shutdown).To(BeTrue())
+	})
+
+	It("should wait for the items being processed to be done when shut down with drain", func() {
+		q.Add("item")
+		item, _ := q.Get()
+
+		drained := make(chan struct{})
+		go func() {
+			defer close(drained)
+			q.ShutDownWithDrain()
+		}()
+		Consistently(drained, 100*time.Millisecond).ShouldNot(BeClosed())
+
+		q.Done(item)
+		Eventually(drained).Should(BeClosed())
+	})
+})
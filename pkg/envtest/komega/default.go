@@ -79,6 +79,40 @@ func UpdateStatus(obj client.Object, f func(), opts ...client.UpdateOption) func
 	return defaultK.UpdateStatus(obj, f, opts...)
 }
 
+// UpdateStatusWith returns a function that fetches a resource, applies the provided mutate function and then updates the resource's status.
+// Unlike with UpdateStatus, the mutate function can fail, e.g. when the fetched resource isn't in the expected state yet.
+// It can be used with gomega.Eventually() like this:
+//
+//	deployment := appsv1.Deployment{ ... }
+//	gomega.Eventually(k.UpdateStatusWith(&deployment, func() error {
+//	  if deployment.Status.ObservedGeneration != deployment.Generation {
+//	    return errors.New("not observed yet")
+//	  }
+//	  deployment.Status.AvailableReplicas = 1
+//	  return nil
+//	})).To(gomega.Succeed())
+//
+// By calling the returned function directly it can also be used as gomega.Expect(k.UpdateStatusWith(...)()).To(...)
+func UpdateStatusWith(obj client.Object, f func() error, opts ...client.UpdateOption) func() error {
+	checkDefaultClient()
+	return defaultK.UpdateStatusWith(obj, f, opts...)
+}
+
+// Patch returns a function that fetches a resource, applies the provided patch function and then patches the resource
+// with a merge patch of the changes. The patch is rejected if the resource changed in the meantime.
+// It can be used with gomega.Eventually() like this:
+//
+//	deployment := appsv1.Deployment{ ... }
+//	gomega.Eventually(k.Patch(&deployment, func() {
+//	  deployment.Spec.Replicas = pointer.Int32(3)
+//	})).To(gomega.Succeed())
+//
+// By calling the returned function directly it can also be used as gomega.Expect(k.Patch(...)()).To(...)
+func Patch(obj client.Object, f func(), opts ...client.PatchOption) func() error {
+	checkDefaultClient()
+	return defaultK.Patch(obj, f, opts...)
+}
+
 // Object returns a function that fetches a resource and returns the object.
 // It can be used with gomega.Eventually() like this:
 //
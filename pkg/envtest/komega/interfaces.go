@@ -40,6 +40,8 @@ type Komega interface {
 	List(client.ObjectList, ...client.ListOption) func() error
 
 	// Update returns a function that fetches a resource, applies the provided update function and then updates the resource.
+	// The write helpers fetch the resource again and retry a few times if the write fails with a conflict, e.g. because a
+	// controller updated the resource concurrently, and return the last conflict otherwise.
 	// It can be used with gomega.Eventually() like this:
 	//   deployment := appsv1.Deployment{ ... }
 	//   gomega.Eventually(k.Update(&deployment, func (o client.Object) {
@@ -59,6 +61,30 @@ type Komega interface {
 	// By calling the returned function directly it can also be used as gomega.Expect(k.UpdateStatus(...)()).To(...)
 	UpdateStatus(client.Object, func(), ...client.UpdateOption) func() error
 
+	// UpdateStatusWith returns a function that fetches a resource, applies the provided mutate function and then updates the resource's status.
+	// Unlike with UpdateStatus, the mutate function can fail, e.g. when the fetched resource isn't in the expected state yet.
+	// It can be used with gomega.Eventually() like this:
+	//   deployment := appsv1.Deployment{ ... }
+	//   gomega.Eventually(k.UpdateStatusWith(&deployment, func() error {
+	//     if deployment.Status.ObservedGeneration != deployment.Generation {
+	//       return errors.New("not observed yet")
+	//     }
+	//     deployment.Status.AvailableReplicas = 1
+	//     return nil
+	//   })).To(gomega.Succeed())
+	// By calling the returned function directly it can also be used as gomega.Expect(k.UpdateStatusWith(...)()).To(...)
+	UpdateStatusWith(client.Object, func() error, ...client.UpdateOption) func() error
+
+	// Patch returns a function that fetches a resource, applies the provided patch function and then patches the resource
+	// with a merge patch of the changes. The patch is rejected if the resource changed in the meantime.
+	// It can be used with gomega.Eventually() like this:
+	//   deployment := appsv1.Deployment{ ... }
+	//   gomega.Eventually(k.Patch(&deployment, func() {
+	//     deployment.Spec.Replicas = pointer.Int32(3)
+	//   })).To(gomega.Succeed())
+	// By calling the returned function directly it can also be used as gomega.Expect(k.Patch(...)()).To(...)
+	Patch(client.Object, func(), ...client.PatchOption) func() error
+
 	// Object returns a function that fetches a resource and returns the object.
 	// It can be used with gomega.Eventually() like this:
 	//   deployment := appsv1.Deployment{ ... }
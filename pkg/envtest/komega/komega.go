@@ -18,7 +18,9 @@ package komega
 
 import (
 	"context"
+	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -64,35 +66,63 @@ func (k *komega) List(obj client.ObjectList, opts ...client.ListOption) func() e
 	}
 }
 
+// maxConflictRetries is the number of times the write helpers retry on conflicts within
+// a single call of the returned function.
+const maxConflictRetries = 5
+
 // Update returns a function that fetches a resource, applies the provided update function and then updates the resource.
 func (k *komega) Update(obj client.Object, updateFunc func(), opts ...client.UpdateOption) func() error {
-	key := types.NamespacedName{
-		Name:      obj.GetName(),
-		Namespace: obj.GetNamespace(),
-	}
-	return func() error {
-		err := k.client.Get(k.ctx, key, obj)
-		if err != nil {
-			return err
-		}
+	return k.retryOnConflict(obj, func() error {
 		updateFunc()
 		return k.client.Update(k.ctx, obj, opts...)
-	}
+	})
 }
 
 // UpdateStatus returns a function that fetches a resource, applies the provided update function and then updates the resource's status.
 func (k *komega) UpdateStatus(obj client.Object, updateFunc func(), opts ...client.UpdateOption) func() error {
+	return k.retryOnConflict(obj, func() error {
+		updateFunc()
+		return k.client.Status().Update(k.ctx, obj, opts...)
+	})
+}
+
+// UpdateStatusWith returns a function that fetches a resource, applies the provided mutate function and then updates the resource's status.
+func (k *komega) UpdateStatusWith(obj client.Object, mutate func() error, opts ...client.UpdateOption) func() error {
+	return k.retryOnConflict(obj, func() error {
+		if err := mutate(); err != nil {
+			return err
+		}
+		return k.client.Status().Update(k.ctx, obj, opts...)
+	})
+}
+
+// Patch returns a function that fetches a resource, applies the provided patch function and then patches the resource.
+func (k *komega) Patch(obj client.Object, patchFunc func(), opts ...client.PatchOption) func() error {
+	return k.retryOnConflict(obj, func() error {
+		base := obj.DeepCopyObject().(client.Object)
+		patchFunc()
+		return k.client.Patch(k.ctx, obj, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}), opts...)
+	})
+}
+
+// retryOnConflict returns a function that fetches the resource and calls write, and
+// does so again if write fails with a conflict, up to maxConflictRetries times.
+func (k *komega) retryOnConflict(obj client.Object, write func() error) func() error {
 	key := types.NamespacedName{
 		Name:      obj.GetName(),
 		Namespace: obj.GetNamespace(),
 	}
 	return func() error {
-		err := k.client.Get(k.ctx, key, obj)
-		if err != nil {
-			return err
+		var err error
+		for i := 0; i <= maxConflictRetries; i++ {
+			if err = k.client.Get(k.ctx, key, obj); err != nil {
+				return err
+			}
+			if err = write(); !apierrors.IsConflict(err) {
+				return err
+			}
 		}
-		updateFunc()
-		return k.client.Status().Update(k.ctx, obj, opts...)
+		return fmt.Errorf("failed to write %s after %d conflicts: %w", key, maxConflictRetries+1, err)
 	}
 }
 
@@ -1,11 +1,15 @@
 package komega
 
 import (
+	"context"
+	"errors"
+	"strconv"
 	"testing"
 
 	_ "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
@@ -136,3 +140,166 @@ func TestObjectList(t *testing.T) {
 		)),
 	))
 }
+
+func TestUpdateStatusWith(t *testing.T) {
+	g := NewWithT(t)
+
+	fc := createFakeClient()
+	k := New(fc)
+
+	updateDeployment := appsv1.Deployment{
+		ObjectMeta: exampleDeployment().ObjectMeta,
+	}
+	g.Expect(k.UpdateStatusWith(&updateDeployment, func() error {
+		return errors.New("not ready")
+	})()).To(MatchError("not ready"))
+
+	g.Eventually(k.UpdateStatusWith(&updateDeployment, func() error {
+		updateDeployment.Status.AvailableReplicas = *updateDeployment.Spec.Replicas
+		return nil
+	})).Should(Succeed())
+
+	fetched := appsv1.Deployment{
+		ObjectMeta: exampleDeployment().ObjectMeta,
+	}
+	g.Expect(k.Object(&fetched)()).To(HaveField("Status.AvailableReplicas", BeEquivalentTo(5)))
+}
+
+func TestPatch(t *testing.T) {
+	g := NewWithT(t)
+
+	fc := createFakeClient()
+	k := New(fc)
+
+	patchDeployment := appsv1.Deployment{
+		ObjectMeta: exampleDeployment().ObjectMeta,
+	}
+	g.Eventually(k.Patch(&patchDeployment, func() {
+		patchDeployment.Annotations = map[string]string{"patched": "true"}
+	})).Should(Succeed())
+
+	fetched := appsv1.Deployment{
+		ObjectMeta: exampleDeployment().ObjectMeta,
+	}
+	g.Expect(k.Object(&fetched)()).To(And(
+		HaveField("ObjectMeta.Annotations", HaveKeyWithValue("patched", "true")),
+		HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(5))),
+	))
+}
+
+// conflictingClient fails the given number of writes with a conflict.
+type conflictingClient struct {
+	client.Client
+	conflicts int
+}
+
+func (c *conflictingClient) conflict(obj client.Object) error {
+	if c.conflicts == 0 {
+		return nil
+	}
+	c.conflicts--
+	return apierrors.NewConflict(appsv1.Resource("deployments"), obj.GetName(), errors.New("the object has been modified"))
+}
+
+func (c *conflictingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.conflict(obj); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *conflictingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.conflict(obj); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *conflictingClient) Status() client.StatusWriter {
+	return &conflictingStatusWriter{StatusWriter: c.Client.Status(), c: c}
+}
+
+type conflictingStatusWriter struct {
+	client.StatusWriter
+	c *conflictingClient
+}
+
+func (w *conflictingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := w.c.conflict(obj); err != nil {
+		return err
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+func TestWriteRetriesOnConflict(t *testing.T) {
+	g := NewWithT(t)
+
+	deployment := appsv1.Deployment{
+		ObjectMeta: exampleDeployment().ObjectMeta,
+	}
+	writes := map[string]func(k Komega) func() error{
+		"Update": func(k Komega) func() error {
+			return k.Update(&deployment, func() { deployment.Annotations = map[string]string{"updated": "true"} })
+		},
+		"UpdateStatus": func(k Komega) func() error {
+			return k.UpdateStatus(&deployment, func() { deployment.Status.AvailableReplicas = 1 })
+		},
+		"UpdateStatusWith": func(k Komega) func() error {
+			return k.UpdateStatusWith(&deployment, func() error { deployment.Status.AvailableReplicas = 1; return nil })
+		},
+		"Patch": func(k Komega) func() error {
+			return k.Patch(&deployment, func() { deployment.Annotations = map[string]string{"patched": "true"} })
+		},
+	}
+	for name, write := range writes {
+		k := New(&conflictingClient{Client: createFakeClient(), conflicts: maxConflictRetries})
+		g.Expect(write(k)()).To(Succeed(), name)
+
+		k = New(&conflictingClient{Client: createFakeClient(), conflicts: maxConflictRetries + 1})
+		err := write(k)()
+		g.Expect(apierrors.IsConflict(err)).To(BeTrue(), name)
+		g.Expect(err).To(MatchError(ContainSubstring("failed to write default/test after 6 conflicts")), name)
+		g.Expect(err).To(MatchError(ContainSubstring("the object has been modified")), name)
+	}
+}
+
+func TestUpdateWithCompetingWriter(t *testing.T) {
+	g := NewWithT(t)
+
+	fc := createFakeClient()
+	k := New(fc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ctx.Err() == nil; i++ {
+			competing := appsv1.Deployment{}
+			if err := fc.Get(ctx, client.ObjectKeyFromObject(exampleDeployment()), &competing); err != nil {
+				continue
+			}
+			competing.Labels = map[string]string{"writes": strconv.Itoa(i)}
+			_ = fc.Update(ctx, &competing)
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		updateDeployment := appsv1.Deployment{
+			ObjectMeta: exampleDeployment().ObjectMeta,
+		}
+		g.Eventually(k.Update(&updateDeployment, func() {
+			if updateDeployment.Annotations == nil {
+				updateDeployment.Annotations = map[string]string{}
+			}
+			updateDeployment.Annotations[strconv.Itoa(i)] = "true"
+		})).Should(Succeed())
+	}
+	cancel()
+	<-done
+
+	fetched := appsv1.Deployment{
+		ObjectMeta: exampleDeployment().ObjectMeta,
+	}
+	g.Expect(k.Object(&fetched)()).To(HaveField("ObjectMeta.Annotations", HaveLen(20)))
+}
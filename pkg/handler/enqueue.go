@@ -19,6 +19,7 @@ package handler
 import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -28,6 +29,30 @@ var enqueueLog = logf.RuntimeLog.WithName("eventhandler").WithName("EnqueueReque
 
 type empty struct{}
 
+// LowPriority is the priority with which the handlers of this package add the requests of
+// resyncs to queues implementing priorityqueue.PriorityQueue, so that they are handed out
+// after the requests of actual changes.
+const LowPriority = -100
+
+// updatePriority returns the priority of the requests of an update event, which is
+// LowPriority if the object didn't change, i.e. if the event is a resync.
+func updatePriority(evt event.UpdateEvent) int {
+	if evt.ObjectOld != nil && evt.ObjectNew != nil &&
+		evt.ObjectOld.GetResourceVersion() == evt.ObjectNew.GetResourceVersion() {
+		return LowPriority
+	}
+	return 0
+}
+
+// addWithPriority adds a request to q, with the given priority if q supports priorities.
+func addWithPriority(q workqueue.RateLimitingInterface, priority int, req interface{}) {
+	if pq, ok := q.(priorityqueue.PriorityQueue); ok {
+		pq.AddWithOpts(priorityqueue.AddOpts{Priority: priority}, req)
+		return
+	}
+	q.Add(req)
+}
+
 var _ EventHandler = &EnqueueRequestForObject{}
 
 // EnqueueRequestForObject enqueues a Request containing the Name and Namespace of the object that is the source of the Event.
@@ -51,7 +76,7 @@ func (e *EnqueueRequestForObject) Create(evt event.CreateEvent, q workqueue.Rate
 func (e *EnqueueRequestForObject) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
 	switch {
 	case evt.ObjectNew != nil:
-		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+		addWithPriority(q, updatePriority(evt), reconcile.Request{NamespacedName: types.NamespacedName{
 			Name:      evt.ObjectNew.GetName(),
 			Namespace: evt.ObjectNew.GetNamespace(),
 		}})
@@ -28,6 +28,10 @@ import (
 // This type is usually used with EnqueueRequestsFromMapFunc when registering an event handler.
 type MapFunc func(client.Object) []reconcile.Request
 
+// TypedMapFunc is the signature required for enqueueing requests of a custom type from a generic
+// function, see TypedEnqueueRequestsFromMapFunc.
+type TypedMapFunc[request comparable] func(client.Object) []request
+
 // EnqueueRequestsFromMapFunc enqueues Requests by running a transformation function that outputs a collection
 // of reconcile.Requests on each Event.  The reconcile.Requests may be for an arbitrary set of objects
 // defined by some user specified transformation of the source Event.  (e.g. trigger Reconciler for a set of objects
@@ -39,48 +43,57 @@ type MapFunc func(client.Object) []reconcile.Request
 // For UpdateEvents which contain both a new and old object, the transformation function is run on both
 // objects and both sets of Requests are enqueue.
 func EnqueueRequestsFromMapFunc(fn MapFunc) EventHandler {
-	return &enqueueRequestsFromMapFunc{
+	return &enqueueRequestsFromMapFunc[reconcile.Request]{
+		toRequests: TypedMapFunc[reconcile.Request](fn),
+	}
+}
+
+// TypedEnqueueRequestsFromMapFunc is like EnqueueRequestsFromMapFunc, but enqueues requests of a
+// custom type, e.g. for a controller.TypedController.
+func TypedEnqueueRequestsFromMapFunc[request comparable](fn TypedMapFunc[request]) EventHandler {
+	return &enqueueRequestsFromMapFunc[request]{
 		toRequests: fn,
 	}
 }
 
-var _ EventHandler = &enqueueRequestsFromMapFunc{}
+var _ EventHandler = &enqueueRequestsFromMapFunc[reconcile.Request]{}
 
-type enqueueRequestsFromMapFunc struct {
+type enqueueRequestsFromMapFunc[request comparable] struct {
 	// Mapper transforms the argument into a slice of keys to be reconciled
-	toRequests MapFunc
+	toRequests TypedMapFunc[request]
 }
 
 // Create implements EventHandler.
-func (e *enqueueRequestsFromMapFunc) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
-	reqs := map[reconcile.Request]empty{}
-	e.mapAndEnqueue(q, evt.Object, reqs)
+func (e *enqueueRequestsFromMapFunc[request]) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	reqs := map[request]empty{}
+	e.mapAndEnqueue(q, evt.Object, reqs, 0)
 }
 
 // Update implements EventHandler.
-func (e *enqueueRequestsFromMapFunc) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
-	reqs := map[reconcile.Request]empty{}
-	e.mapAndEnqueue(q, evt.ObjectOld, reqs)
-	e.mapAndEnqueue(q, evt.ObjectNew, reqs)
+func (e *enqueueRequestsFromMapFunc[request]) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	reqs := map[request]empty{}
+	priority := updatePriority(evt)
+	e.mapAndEnqueue(q, evt.ObjectOld, reqs, priority)
+	e.mapAndEnqueue(q, evt.ObjectNew, reqs, priority)
 }
 
 // Delete implements EventHandler.
-func (e *enqueueRequestsFromMapFunc) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
-	reqs := map[reconcile.Request]empty{}
-	e.mapAndEnqueue(q, evt.Object, reqs)
+func (e *enqueueRequestsFromMapFunc[request]) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	reqs := map[request]empty{}
+	e.mapAndEnqueue(q, evt.Object, reqs, 0)
 }
 
 // Generic implements EventHandler.
-func (e *enqueueRequestsFromMapFunc) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
-	reqs := map[reconcile.Request]empty{}
-	e.mapAndEnqueue(q, evt.Object, reqs)
+func (e *enqueueRequestsFromMapFunc[request]) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	reqs := map[request]empty{}
+	e.mapAndEnqueue(q, evt.Object, reqs, 0)
 }
 
-func (e *enqueueRequestsFromMapFunc) mapAndEnqueue(q workqueue.RateLimitingInterface, object client.Object, reqs map[reconcile.Request]empty) {
+func (e *enqueueRequestsFromMapFunc[request]) mapAndEnqueue(q workqueue.RateLimitingInterface, object client.Object, reqs map[request]empty, priority int) {
 	for _, req := range e.toRequests(object) {
 		_, ok := reqs[req]
 		if !ok {
-			q.Add(req)
+			addWithPriority(q, priority, req)
 			reqs[req] = empty{}
 		}
 	}
@@ -89,7 +102,7 @@ func (e *enqueueRequestsFromMapFunc) mapAndEnqueue(q workqueue.RateLimitingInter
 // EnqueueRequestsFromMapFunc can inject fields into the mapper.
 
 // InjectFunc implements inject.Injector.
-func (e *enqueueRequestsFromMapFunc) InjectFunc(f inject.Func) error {
+func (e *enqueueRequestsFromMapFunc[request]) InjectFunc(f inject.Func) error {
 	if f == nil {
 		return nil
 	}
@@ -71,8 +71,9 @@ func (e *EnqueueRequestForOwner) Update(evt event.UpdateEvent, q workqueue.RateL
 	reqs := map[reconcile.Request]empty{}
 	e.getOwnerReconcileRequest(evt.ObjectOld, reqs)
 	e.getOwnerReconcileRequest(evt.ObjectNew, reqs)
+	priority := updatePriority(evt)
 	for req := range reqs {
-		q.Add(req)
+		addWithPriority(q, priority, req)
 	}
 }
 
@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -301,6 +302,36 @@ var _ = Describe("Eventhandler", func() {
 		})
 	})
 
+	Describe("TypedEnqueueRequestsFromMapFunc", func() {
+		type externalRequest struct {
+			ARN string
+		}
+
+		It("should enqueue the requests of a custom type, deduplicated across both objects of the UpdateEvent.", func() {
+			instance := handler.TypedEnqueueRequestsFromMapFunc(func(a client.Object) []externalRequest {
+				return []externalRequest{{ARN: "arn:aws:s3:::shared"}, {ARN: "arn:aws:s3:::" + a.GetName()}}
+			})
+
+			newPod := pod.DeepCopy()
+			newPod.Name = "baz2"
+			evt := event.UpdateEvent{
+				ObjectOld: pod,
+				ObjectNew: newPod,
+			}
+			instance.Update(evt, q)
+			Expect(q.Len()).To(Equal(3))
+
+			i1, _ := q.Get()
+			i2, _ := q.Get()
+			i3, _ := q.Get()
+			Expect([]interface{}{i1, i2, i3}).To(ConsistOf(
+				externalRequest{ARN: "arn:aws:s3:::shared"},
+				externalRequest{ARN: "arn:aws:s3:::baz"},
+				externalRequest{ARN: "arn:aws:s3:::baz2"},
+			))
+		})
+	})
+
 	Describe("EnqueueRequestForOwner", func() {
 		It("should enqueue a Request with the Owner of the object in the CreateEvent.", func() {
 			instance := handler.EnqueueRequestForOwner{
@@ -906,4 +937,59 @@ var _ = Describe("Eventhandler", func() {
 			instance.Generic(evt, q)
 		})
 	})
+
+	Describe("with a priority queue", func() {
+		var pq priorityqueue.PriorityQueue
+		BeforeEach(func() {
+			pq = priorityqueue.New("handler-test", priorityqueue.Options{})
+			pod.ResourceVersion = "1"
+		})
+		AfterEach(func() {
+			pq.ShutDown()
+		})
+
+		changed := func(obj *corev1.Pod) event.UpdateEvent {
+			newObj := obj.DeepCopy()
+			newObj.ResourceVersion = "2"
+			return event.UpdateEvent{ObjectOld: obj, ObjectNew: newObj}
+		}
+
+		It("should enqueue the Request of a resync with LowPriority.", func() {
+			resync := event.UpdateEvent{ObjectOld: pod, ObjectNew: pod}
+			instance.Update(resync, pq)
+
+			item, priority, _ := pq.GetWithPriority()
+			Expect(item).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "baz"}}))
+			Expect(priority).To(Equal(handler.LowPriority))
+		})
+
+		It("should hand out the Requests of changes before the Requests of resyncs.", func() {
+			otherPod := pod.DeepCopy()
+			otherPod.Name = "other"
+			resync := event.UpdateEvent{ObjectOld: pod, ObjectNew: pod}
+			instance.Update(resync, pq)
+			instance.Update(changed(otherPod), pq)
+
+			item, priority, _ := pq.GetWithPriority()
+			Expect(item).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "other"}}))
+			Expect(priority).To(Equal(0))
+			item, priority, _ = pq.GetWithPriority()
+			Expect(item).To(Equal(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "biz", Name: "baz"}}))
+			Expect(priority).To(Equal(handler.LowPriority))
+		})
+
+		It("should enqueue the Requests mapped from a resync with LowPriority.", func() {
+			instance := handler.EnqueueRequestsFromMapFunc(func(a client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "foo", Name: a.GetName()}}}
+			})
+			resync := event.UpdateEvent{ObjectOld: pod, ObjectNew: pod}
+			instance.Update(resync, pq)
+			instance.Update(changed(pod), pq)
+
+			// The request of the change raises the priority of the queued request.
+			Expect(pq.Len()).To(Equal(1))
+			_, priority, _ := pq.GetWithPriority()
+			Expect(priority).To(Equal(0))
+		})
+	})
 })
@@ -0,0 +1,230 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// defaultInitialTimeout is how long a cached checker waits for a check by default
+// if it has no result to serve.
+const defaultInitialTimeout = 500 * time.Millisecond
+
+// errNotCheckedYet is returned by cached and periodic checkers before their first
+// check completed.
+var errNotCheckedYet = errors.New("health check has not completed yet")
+
+// result is the result of a check that is served from memory.
+type result struct {
+	err       error
+	checkedAt time.Time
+}
+
+// serve returns the error of the result, or an error if it's older than maxAge.
+func (r *result) serve(req *http.Request, maxAge time.Duration) error {
+	recordLastChecked(req, r.checkedAt)
+	if age := time.Since(r.checkedAt); age > maxAge {
+		return fmt.Errorf("health check result is stale: last checked %s ago", age.Round(time.Second))
+	}
+	return r.err
+}
+
+// CacheOptions are the options of WithCache.
+type CacheOptions struct {
+	// InitialTimeout is how long a probe waits for the check if there is no result
+	// to serve, either because there is none yet or because it's too stale.
+	// Defaults to 500ms.
+	InitialTimeout time.Duration
+}
+
+// CacheOption configures the options of WithCache.
+type CacheOption func(*CacheOptions)
+
+// WithInitialTimeout sets how long a probe waits for the check if there is no
+// result to serve.
+func WithInitialTimeout(timeout time.Duration) CacheOption {
+	return func(o *CacheOptions) {
+		o.InitialTimeout = timeout
+	}
+}
+
+// WithCache returns a checker that serves the last result of the given checker until
+// it's older than the ttl, and then refreshes it in the background while still serving
+// it. Results older than twice the ttl aren't served anymore: the probe waits for the
+// refresh up to the initial timeout, like it does for the first check, and fails if it
+// doesn't complete in time.
+func WithCache(checker Checker, ttl time.Duration, opts ...CacheOption) Checker {
+	options := CacheOptions{InitialTimeout: defaultInitialTimeout}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	c := &cachedChecker{checker: checker, ttl: ttl, initialTimeout: options.InitialTimeout}
+	return c.check
+}
+
+// cachedChecker is the checker returned by WithCache.
+type cachedChecker struct {
+	checker        Checker
+	ttl            time.Duration
+	initialTimeout time.Duration
+
+	mu   sync.Mutex
+	last *result
+	// refreshed is closed once the running refresh completes, and nil if none is running.
+	refreshed chan struct{}
+}
+
+func (c *cachedChecker) check(req *http.Request) error {
+	c.mu.Lock()
+	last := c.last
+	if last != nil && time.Since(last.checkedAt) < c.ttl {
+		c.mu.Unlock()
+		return last.serve(req, c.ttl)
+	}
+	refreshed := c.refreshLocked(req)
+	c.mu.Unlock()
+
+	if last != nil && time.Since(last.checkedAt) <= 2*c.ttl {
+		return last.serve(req, 2*c.ttl)
+	}
+
+	timer := time.NewTimer(c.initialTimeout)
+	defer timer.Stop()
+	var done <-chan struct{}
+	if req != nil {
+		done = req.Context().Done()
+	}
+	select {
+	case <-refreshed:
+		c.mu.Lock()
+		last = c.last
+		c.mu.Unlock()
+	case <-timer.C:
+	case <-done:
+	}
+	if last == nil {
+		return errNotCheckedYet
+	}
+	return last.serve(req, 2*c.ttl)
+}
+
+// refreshLocked starts refreshing the result unless a refresh is running already, and
+// returns a channel that is closed once it completes. The refresh doesn't use the
+// context of the probe, which may be canceled before it completes.
+func (c *cachedChecker) refreshLocked(req *http.Request) <-chan struct{} {
+	if c.refreshed != nil {
+		return c.refreshed
+	}
+	refreshed := make(chan struct{})
+	c.refreshed = refreshed
+	go func() {
+		err := c.checker(backgroundRequest(context.Background(), req))
+		c.mu.Lock()
+		c.last = &result{err: err, checkedAt: time.Now()}
+		c.refreshed = nil
+		c.mu.Unlock()
+		close(refreshed)
+	}()
+	return refreshed
+}
+
+// PeriodicChecker runs a check on an interval, independent of how often it's served.
+// It must be added to the manager, which runs it on every replica.
+type PeriodicChecker struct {
+	checker  Checker
+	interval time.Duration
+
+	mu   sync.RWMutex
+	last *result
+}
+
+// Periodic returns a PeriodicChecker running the given checker every interval once
+// started. Its Check serves the latest result, and fails if it's older than twice
+// the interval, e.g. because the check hangs.
+func Periodic(checker Checker, interval time.Duration) *PeriodicChecker {
+	return &PeriodicChecker{checker: checker, interval: interval}
+}
+
+// Check is the Checker serving the latest result.
+func (p *PeriodicChecker) Check(req *http.Request) error {
+	p.mu.RLock()
+	last := p.last
+	p.mu.RUnlock()
+	if last == nil {
+		return errNotCheckedYet
+	}
+	return last.serve(req, 2*p.interval)
+}
+
+// Start runs the check every interval until the context is canceled.
+func (p *PeriodicChecker) Start(ctx context.Context) error {
+	req := backgroundRequest(ctx, nil)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		err := p.checker(req)
+		p.mu.Lock()
+		p.last = &result{err: err, checkedAt: time.Now()}
+		p.mu.Unlock()
+	}, p.interval)
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, as health checks
+// must run on every replica.
+func (p *PeriodicChecker) NeedLeaderElection() bool {
+	return false
+}
+
+// backgroundRequest returns a copy of the given request with the given context, or a
+// new request if there is none.
+func backgroundRequest(ctx context.Context, req *http.Request) *http.Request {
+	if req != nil {
+		return req.Clone(ctx)
+	}
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	return req
+}
+
+// checkDetailsKey is the context key of the checkDetails of a request.
+type checkDetailsKey struct{}
+
+// checkDetails are the details of a check that are reported in the verbose output.
+type checkDetails struct {
+	lastChecked time.Time
+}
+
+// withCheckDetails returns a copy of the request that records the details of the check.
+func withCheckDetails(req *http.Request, details *checkDetails) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), checkDetailsKey{}, details))
+}
+
+// recordLastChecked records when the served result was checked, if the request records
+// the details of the check.
+func recordLastChecked(req *http.Request, t time.Time) {
+	if req == nil {
+		return
+	}
+	if details, ok := req.Context().Value(checkDetailsKey{}).(*checkDetails); ok {
+		details.lastChecked = t
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+var _ = Describe("WithCache", func() {
+	var (
+		calls int64
+		// counting succeeds, counting its calls.
+		counting healthz.Checker
+	)
+
+	BeforeEach(func() {
+		atomic.StoreInt64(&calls, 0)
+		counting = func(_ *http.Request) error {
+			atomic.AddInt64(&calls, 1)
+			return nil
+		}
+	})
+
+	// blockAfterFirstCall makes all but the first call of counting block until the spec ends.
+	blockAfterFirstCall := func() {
+		release := make(chan struct{})
+		counting = func(_ *http.Request) error {
+			if atomic.AddInt64(&calls, 1) > 1 {
+				<-release
+			}
+			return nil
+		}
+		DeferCleanup(func() { close(release) })
+	}
+
+	It("should serve the last result until the ttl expires", func() {
+		checker := healthz.WithCache(counting, time.Hour)
+		Expect(checker(nil)).To(Succeed())
+		Expect(checker(nil)).To(Succeed())
+		Expect(atomic.LoadInt64(&calls)).To(BeEquivalentTo(1))
+	})
+
+	It("should serve the last error until the ttl expires", func() {
+		checker := healthz.WithCache(func(_ *http.Request) error { return errors.New("unhealthy") }, time.Hour)
+		Expect(checker(nil)).To(MatchError("unhealthy"))
+		Expect(checker(nil)).To(MatchError("unhealthy"))
+	})
+
+	It("should refresh the result in the background once the ttl expired", func() {
+		blockAfterFirstCall()
+		checker := healthz.WithCache(counting, 100*time.Millisecond)
+		Expect(checker(nil)).To(Succeed())
+
+		time.Sleep(120 * time.Millisecond)
+		By("serving the last result without waiting for the refresh")
+		Expect(checker(nil)).To(Succeed())
+		Eventually(func() int64 { return atomic.LoadInt64(&calls) }).Should(BeEquivalentTo(2))
+
+		By("not starting another refresh while one is running")
+		Expect(checker(nil)).To(Succeed())
+		Consistently(func() int64 { return atomic.LoadInt64(&calls) }, "50ms").Should(BeEquivalentTo(2))
+	})
+
+	It("should fail if the first check doesn't complete within the initial timeout", func() {
+		release := make(chan struct{})
+		defer close(release)
+		checker := healthz.WithCache(func(_ *http.Request) error {
+			<-release
+			return nil
+		}, time.Hour, healthz.WithInitialTimeout(10*time.Millisecond))
+		Expect(checker(nil)).To(MatchError("health check has not completed yet"))
+	})
+
+	It("should fail if the last result is too stale to be served", func() {
+		blockAfterFirstCall()
+		checker := healthz.WithCache(counting, 10*time.Millisecond, healthz.WithInitialTimeout(10*time.Millisecond))
+		Expect(checker(nil)).To(Succeed())
+
+		time.Sleep(30 * time.Millisecond)
+		Expect(checker(nil)).To(MatchError(ContainSubstring("health check result is stale: last checked")))
+	})
+
+	It("should run the check once for concurrent probes", func() {
+		checker := healthz.WithCache(counting, time.Hour)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(checker(nil)).To(Succeed())
+			}()
+		}
+		wg.Wait()
+		Expect(atomic.LoadInt64(&calls)).To(BeEquivalentTo(1))
+	})
+
+	It("should report when the result was checked in the verbose output", func() {
+		handler := &healthz.Handler{Checks: map[string]healthz.Checker{
+			"cached": healthz.WithCache(counting, time.Hour),
+		}}
+		resp := requestTo(handler, "/?verbose=true")
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(Equal("[+]cached ok (last checked 0s ago)\nhealthz check passed\n"))
+	})
+})
+
+var _ = Describe("Periodic", func() {
+	It("should fail before the first check completed", func() {
+		checker := healthz.Periodic(func(_ *http.Request) error { return nil }, time.Hour)
+		Expect(checker.Check(nil)).To(MatchError("health check has not completed yet"))
+		Expect(checker.NeedLeaderElection()).To(BeFalse())
+	})
+
+	It("should run the check on the interval and serve the latest result", func() {
+		var calls int64
+		checker := healthz.Periodic(func(_ *http.Request) error {
+			if atomic.AddInt64(&calls, 1) > 2 {
+				return errors.New("unhealthy")
+			}
+			return nil
+		}, 10*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			defer close(done)
+			Expect(checker.Start(ctx)).To(Succeed())
+		}()
+		defer func() {
+			cancel()
+			Eventually(done).Should(BeClosed())
+		}()
+
+		check := func() error { return checker.Check(nil) }
+		Eventually(check).Should(Succeed())
+		Eventually(check).Should(MatchError("unhealthy"))
+
+		handler := &healthz.Handler{Checks: map[string]healthz.Checker{"periodic": checker.Check}, IncludeErrors: true}
+		resp := requestTo(handler, "/")
+		Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+		Expect(resp.Body.String()).To(ContainSubstring("[-]periodic failed: unhealthy (last checked 0s ago)"))
+	})
+
+	It("should fail if the check stopped completing", func() {
+		var calls int64
+		release := make(chan struct{})
+		defer close(release)
+		checker := healthz.Periodic(func(_ *http.Request) error {
+			if atomic.AddInt64(&calls, 1) > 1 {
+				<-release
+			}
+			return nil
+		}, 10*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(checker.Start(ctx)).To(Succeed())
+		}()
+
+		Eventually(func() error { return checker.Check(nil) }).Should(MatchError(ContainSubstring("health check result is stale")))
+	})
+})
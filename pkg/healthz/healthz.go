@@ -17,22 +17,66 @@ limitations under the License.
 package healthz
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"path"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 )
 
 // Handler is an http.Handler that aggregates the results of the given
 // checkers to the root path, and supports calling individual checkers on
 // subpaths of the name of the checker.
 //
-// Adding checks on the fly is *not* threadsafe -- use a wrapper.
+// Setting Checks on the fly is *not* threadsafe -- use AddCheck and RemoveCheck
+// to change the checks of a handler that is serving.
 type Handler struct {
 	Checks map[string]Checker
+
+	// IncludeErrors makes the aggregated output include the errors of failed checks,
+	// instead of withholding them. It should only be set if the endpoint is not public.
+	IncludeErrors bool
+
+	mu sync.RWMutex
+}
+
+// AddCheck adds a check to the handler, replacing the check of the same name if any.
+// It's safe to call while the handler is serving.
+func (h *Handler) AddCheck(name string, check Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.Checks == nil {
+		h.Checks = map[string]Checker{}
+	}
+	h.Checks[name] = check
+}
+
+// RemoveCheck removes the check of the given name from the handler, and returns whether
+// there was one. It's safe to call while the handler is serving.
+func (h *Handler) RemoveCheck(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := h.Checks[name]
+	delete(h.Checks, name)
+	return ok
+}
+
+// checks returns a copy of the checks of the handler.
+func (h *Handler) checks() map[string]Checker {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	checks := make(map[string]Checker, len(h.Checks))
+	for name, check := range h.Checks {
+		checks[name] = check
+	}
+	return checks
 }
 
 // checkStatus holds the output of a particular check.
@@ -40,33 +84,38 @@ type checkStatus struct {
 	name     string
 	healthy  bool
 	excluded bool
+	err      error
+	// lastChecked is when the result of a cached or periodic check was checked.
+	lastChecked time.Time
 }
 
 func (h *Handler) serveAggregated(resp http.ResponseWriter, req *http.Request) {
 	failed := false
 	excluded := getExcludedChecks(req)
+	checks := h.checks()
 
-	parts := make([]checkStatus, 0, len(h.Checks))
+	parts := make([]checkStatus, 0, len(checks))
 
 	// calculate the results...
-	for checkName, check := range h.Checks {
+	for checkName, check := range checks {
 		// no-op the check if we've specified we want to exclude the check
 		if excluded.Has(checkName) {
 			excluded.Delete(checkName)
 			parts = append(parts, checkStatus{name: checkName, healthy: true, excluded: true})
 			continue
 		}
-		if err := check(req); err != nil {
+		details := &checkDetails{}
+		if err := check(withCheckDetails(req, details)); err != nil {
 			log.V(1).Info("healthz check failed", "checker", checkName, "error", err)
-			parts = append(parts, checkStatus{name: checkName, healthy: false})
+			parts = append(parts, checkStatus{name: checkName, healthy: false, err: err, lastChecked: details.lastChecked})
 			failed = true
 		} else {
-			parts = append(parts, checkStatus{name: checkName, healthy: true})
+			parts = append(parts, checkStatus{name: checkName, healthy: true, lastChecked: details.lastChecked})
 		}
 	}
 
 	// ...default a check if none is present...
-	if len(h.Checks) == 0 {
+	if len(checks) == 0 {
 		parts = append(parts, checkStatus{name: "ping", healthy: true})
 	}
 
@@ -78,17 +127,34 @@ func (h *Handler) serveAggregated(resp http.ResponseWriter, req *http.Request) {
 	sort.Slice(parts, func(i, j int) bool { return parts[i].name < parts[j].name })
 
 	// ...and write out the result
-	// TODO(directxman12): this should also accept a request for JSON content (via a accept header)
+	if acceptsJSON(req) {
+		writeStatusesAsJSON(resp, parts, excluded, failed, h.IncludeErrors)
+		return
+	}
 	_, forceVerbose := req.URL.Query()["verbose"]
-	writeStatusesAsText(resp, parts, excluded, failed, forceVerbose)
+	writeStatusesAsText(resp, parts, excluded, failed, forceVerbose, h.IncludeErrors)
+}
+
+// acceptsJSON returns whether the request accepts JSON content.
+func acceptsJSON(req *http.Request) bool {
+	for _, accept := range req.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType = strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0])
+			if mediaType == "application/json" {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // writeStatusAsText writes out the given check statuses in some semi-arbitrary
 // bespoke text format that we copied from Kubernetes.  unknownExcludes lists
 // any checks that the user requested to have excluded, but weren't actually
 // known checks.  writeStatusAsText is always verbose on failure, and can be
-// forced to be verbose on success using the given argument.
-func writeStatusesAsText(resp http.ResponseWriter, parts []checkStatus, unknownExcludes sets.String, failed, forceVerbose bool) {
+// forced to be verbose on success using the given argument.  The errors of
+// failed checks are only written if includeErrors is set.
+func writeStatusesAsText(resp http.ResponseWriter, parts []checkStatus, unknownExcludes sets.String, failed, forceVerbose, includeErrors bool) {
 	resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	resp.Header().Set("X-Content-Type-Options", "nosniff")
 
@@ -108,15 +174,21 @@ func writeStatusesAsText(resp http.ResponseWriter, parts []checkStatus, unknownE
 	// we're always verbose on failure, so from this point on we're guaranteed to be verbose
 
 	for _, checkOut := range parts {
+		var lastChecked string
+		if !checkOut.lastChecked.IsZero() {
+			lastChecked = fmt.Sprintf(" (last checked %s ago)", time.Since(checkOut.lastChecked).Round(time.Second))
+		}
 		switch {
 		case checkOut.excluded:
 			fmt.Fprintf(resp, "[+]%s excluded: ok\n", checkOut.name)
 		case checkOut.healthy:
-			fmt.Fprintf(resp, "[+]%s ok\n", checkOut.name)
+			fmt.Fprintf(resp, "[+]%s ok%s\n", checkOut.name, lastChecked)
+		case includeErrors:
+			fmt.Fprintf(resp, "[-]%s failed: %v%s\n", checkOut.name, checkOut.err, lastChecked)
 		default:
 			// don't include the error since this endpoint is public.  If someone wants more detail
 			// they should have explicit permission to the detailed checks.
-			fmt.Fprintf(resp, "[-]%s failed: reason withheld\n", checkOut.name)
+			fmt.Fprintf(resp, "[-]%s failed: reason withheld%s\n", checkOut.name, lastChecked)
 		}
 	}
 
@@ -132,6 +204,58 @@ func writeStatusesAsText(resp http.ResponseWriter, parts []checkStatus, unknownE
 	}
 }
 
+// jsonStatus is the JSON rendering of the aggregated check statuses.
+type jsonStatus struct {
+	// Status is "ok" if all checks succeeded and "failed" otherwise.
+	Status string      `json:"status"`
+	Checks []jsonCheck `json:"checks"`
+	// UnknownExcludes are the checks that were requested to be excluded but don't exist.
+	UnknownExcludes []string `json:"unknownExcludes,omitempty"`
+}
+
+// jsonCheck is the JSON rendering of the status of a single check.
+type jsonCheck struct {
+	Name string `json:"name"`
+	// Status is "ok", "failed" or "excluded".
+	Status string `json:"status"`
+	// Error is the error of a failed check, if errors are included.
+	Error string `json:"error,omitempty"`
+}
+
+// writeStatusesAsJSON writes out the given check statuses as JSON, which is always
+// verbose.  The errors of failed checks are only written if includeErrors is set.
+func writeStatusesAsJSON(resp http.ResponseWriter, parts []checkStatus, unknownExcludes sets.String, failed, includeErrors bool) {
+	out := jsonStatus{Status: "ok", Checks: make([]jsonCheck, 0, len(parts)), UnknownExcludes: unknownExcludes.List()}
+	if failed {
+		log.Info("healthz check failed", "statuses", parts)
+		out.Status = "failed"
+	}
+	for _, checkOut := range parts {
+		check := jsonCheck{Name: checkOut.name, Status: "ok"}
+		switch {
+		case checkOut.excluded:
+			check.Status = "excluded"
+		case !checkOut.healthy:
+			check.Status = "failed"
+			if includeErrors {
+				check.Error = checkOut.err.Error()
+			}
+		}
+		out.Checks = append(out.Checks, check)
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("X-Content-Type-Options", "nosniff")
+	if failed {
+		resp.WriteHeader(http.StatusInternalServerError)
+	} else {
+		resp.WriteHeader(http.StatusOK)
+	}
+	if err := json.NewEncoder(resp).Encode(out); err != nil {
+		log.Error(err, "unable to write healthz statuses")
+	}
+}
+
 func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	// clean up the request (duplicating the internal logic of http.ServeMux a bit)
 	// clean up the path a bit
@@ -150,14 +274,15 @@ func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	}
 
 	// ...the default check (if nothing else is present)...
-	if len(h.Checks) == 0 && reqPath[1:] == "ping" {
+	checks := h.checks()
+	if len(checks) == 0 && reqPath[1:] == "ping" {
 		CheckHandler{Checker: Ping}.ServeHTTP(resp, req)
 		return
 	}
 
 	// ...or an individual checker
 	checkName := reqPath[1:] // ignore the leading slash
-	checker, known := h.Checks[checkName]
+	checker, known := checks[checkName]
 	if !known {
 		http.NotFoundHandler().ServeHTTP(resp, req)
 		return
@@ -186,6 +311,30 @@ type Checker func(req *http.Request) error
 // Ping returns true automatically when checked.
 var Ping Checker = func(_ *http.Request) error { return nil }
 
+// CacheSyncChecker returns a checker that fails until the given cache has been started
+// and all of its informers have synced, listing the ones that haven't. It doesn't wait
+// for the cache to sync, nor does it create informers.
+func CacheSyncChecker(c cache.Cache) Checker {
+	return func(_ *http.Request) error {
+		status, ok := c.(cache.InformerSyncStatus)
+		if !ok {
+			return fmt.Errorf("cache %T doesn't report the sync status of its informers", c)
+		}
+		started, unsynced := status.UnsyncedInformers()
+		if !started {
+			return fmt.Errorf("cache has not been started")
+		}
+		if len(unsynced) > 0 {
+			names := make([]string, 0, len(unsynced))
+			for _, gvk := range unsynced {
+				names = append(names, gvk.String())
+			}
+			return fmt.Errorf("informers have not synced: %s", strings.Join(names, ", "))
+		}
+		return nil
+	}
+}
+
 // getExcludedChecks extracts the health check names to be excluded from the query param.
 func getExcludedChecks(r *http.Request) sets.String {
 	checks, found := r.URL.Query()["exclude"]
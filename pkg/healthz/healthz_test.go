@@ -23,6 +23,15 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 )
 
@@ -40,6 +49,22 @@ func requestTo(handler http.Handler, dest string) *httptest.ResponseRecorder {
 }
 
 var _ = Describe("Healthz Handler", func() {
+	Describe("adding and removing checks", func() {
+		It("should serve the checks added and not the ones removed", func() {
+			handler := &healthz.Handler{}
+			handler.AddCheck("bad1", func(req *http.Request) error {
+				return errors.New("blech")
+			})
+			Expect(requestTo(handler, "/").Code).To(Equal(http.StatusInternalServerError))
+			Expect(requestTo(handler, "/bad1").Code).To(Equal(http.StatusInternalServerError))
+
+			Expect(handler.RemoveCheck("bad1")).To(BeTrue())
+			Expect(handler.RemoveCheck("bad1")).To(BeFalse())
+			Expect(requestTo(handler, "/").Code).To(Equal(http.StatusOK))
+			Expect(requestTo(handler, "/bad1").Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
 	Describe("the aggregated endpoint", func() {
 		It("should return healthy if all checks succeed", func() {
 			handler := &healthz.Handler{Checks: map[string]healthz.Checker{
@@ -138,6 +163,80 @@ var _ = Describe("Healthz Handler", func() {
 			Expect(resp.Body.String()).To(Equal("[-]bad1 failed: reason withheld\n[+]ok1 ok\nhealthz check failed\n"))
 		})
 
+		Context("when errors are included", func() {
+			It("should return the errors of failed checks", func() {
+				handler := &healthz.Handler{IncludeErrors: true, Checks: map[string]healthz.Checker{
+					"ok1": healthz.Ping,
+					"bad1": func(req *http.Request) error {
+						return errors.New("blech")
+					},
+				}}
+
+				resp := requestTo(handler, "/")
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(Equal("[-]bad1 failed: blech\n[+]ok1 ok\nhealthz check failed\n"))
+			})
+		})
+
+		Context("when JSON is accepted", func() {
+			requestJSON := func(handler http.Handler, dest string) *httptest.ResponseRecorder {
+				req, err := http.NewRequest("GET", dest, nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", "text/plain;q=0.5, application/json")
+				resp := httptest.NewRecorder()
+				handler.ServeHTTP(resp, req)
+				return resp
+			}
+
+			It("should return the statuses of all checks, sorted by name", func() {
+				handler := &healthz.Handler{Checks: map[string]healthz.Checker{
+					"ok2": healthz.Ping,
+					"ok1": healthz.Ping,
+				}}
+
+				resp := requestJSON(handler, "/")
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+				Expect(resp.Body.String()).To(MatchJSON(`{"status":"ok","checks":[{"name":"ok1","status":"ok"},{"name":"ok2","status":"ok"}]}`))
+			})
+
+			It("should return failed and excluded checks", func() {
+				handler := &healthz.Handler{Checks: map[string]healthz.Checker{
+					"ok1": healthz.Ping,
+					"bad1": func(req *http.Request) error {
+						return errors.New("blech")
+					},
+					"bad2": func(req *http.Request) error {
+						return errors.New("blech")
+					},
+				}}
+
+				resp := requestJSON(handler, "/?exclude=bad2&exclude=nonexistent")
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(MatchJSON(`{
+					"status": "failed",
+					"checks": [
+						{"name": "bad1", "status": "failed"},
+						{"name": "bad2", "status": "excluded"},
+						{"name": "ok1", "status": "ok"}
+					],
+					"unknownExcludes": ["nonexistent"]
+				}`))
+			})
+
+			It("should return the errors of failed checks if errors are included", func() {
+				handler := &healthz.Handler{IncludeErrors: true, Checks: map[string]healthz.Checker{
+					"bad1": func(req *http.Request) error {
+						return errors.New("blech")
+					},
+				}}
+
+				resp := requestJSON(handler, "/")
+				Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+				Expect(resp.Body.String()).To(MatchJSON(`{"status":"failed","checks":[{"name":"bad1","status":"failed","error":"blech"}]}`))
+			})
+		})
+
 		It("should always return a ping endpoint if no other ones are present", func() {
 			resp := requestTo(&healthz.Handler{}, "/?verbose=true")
 			Expect(resp.Code).To(Equal(http.StatusOK))
@@ -201,3 +300,41 @@ var _ = Describe("Healthz Handler", func() {
 		})
 	})
 })
+
+var _ = Describe("CacheSyncChecker", func() {
+	It("should pass once all the informers synced", func() {
+		c := &informertest.FakeInformers{}
+		informer, err := c.FakeInformerFor(&corev1.Pod{})
+		Expect(err).NotTo(HaveOccurred())
+		informer.Synced = true
+
+		Expect(healthz.CacheSyncChecker(c)(nil)).To(Succeed())
+	})
+
+	It("should list the informers that never synced", func() {
+		gvk := corev1.SchemeGroupVersion.WithKind("Pod")
+		// The list never returns, so the informer never syncs.
+		informer := toolscache.NewSharedIndexInformer(&toolscache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				select {}
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return watch.NewFake(), nil
+			},
+		}, &corev1.Pod{}, 0, toolscache.Indexers{})
+		stop := make(chan struct{})
+		defer close(stop)
+		go informer.Run(stop)
+
+		c := &informertest.FakeInformers{InformersByGVK: map[schema.GroupVersionKind]toolscache.SharedIndexInformer{gvk: informer}}
+		Expect(healthz.CacheSyncChecker(c)(nil)).To(MatchError("informers have not synced: /v1, Kind=Pod"))
+
+		resp := requestTo(&healthz.Handler{Checks: map[string]healthz.Checker{"cacheSync": healthz.CacheSyncChecker(c)}, IncludeErrors: true}, "/")
+		Expect(resp.Code).To(Equal(http.StatusInternalServerError))
+		Expect(resp.Body.String()).To(ContainSubstring("/v1, Kind=Pod"))
+	})
+
+	It("should fail if the cache doesn't report the sync status of its informers", func() {
+		Expect(healthz.CacheSyncChecker(struct{ cache.Cache }{})(nil)).To(MatchError(ContainSubstring("doesn't report the sync status")))
+	})
+})
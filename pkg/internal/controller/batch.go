@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// batchItem is an item of a batch taken from the queue.
+type batchItem struct {
+	obj      interface{}
+	priority int
+}
+
+// processNextBatch takes the items that are queued, up to MaxBatchSize, and reconciles
+// them with a single call of BatchDo. It returns false if the worker should stop.
+func (c *Controller[request]) processNextBatch(ctx context.Context) bool {
+	// The requests stay queued while the controller is paused.
+	if !c.waitWhilePaused(ctx) {
+		return false
+	}
+
+	batch, shutdown := c.getNextBatch()
+	if shutdown {
+		// Stop working
+		return false
+	}
+	for _, item := range batch {
+		defer c.Queue.Done(item.obj)
+	}
+
+	// The controller may have been paused while the worker waited for the requests,
+	// in which case the requests are held until the controller is resumed.
+	if !c.waitWhilePaused(ctx) {
+		return false
+	}
+
+	// A batch is a single reconciliation, so it takes a single slot of the budget.
+	if c.ReconcileBudget != nil {
+		if err := c.ReconcileBudget.Acquire(ctx); err != nil {
+			// The controller is stopping, and so is the queue.
+			return true
+		}
+		defer c.ReconcileBudget.Release()
+	}
+
+	objs := make([]interface{}, 0, len(batch))
+	for _, item := range batch {
+		objs = append(objs, item.obj)
+	}
+	release, ok := c.acquireSlot(ctx, objs...)
+	if !ok {
+		return true
+	}
+	defer release()
+
+	c.metrics().ActiveWorkers.WithLabelValues().Add(1)
+	defer c.metrics().ActiveWorkers.WithLabelValues().Add(-1)
+	for _, item := range batch {
+		defer c.trackInFlight(item.obj)()
+	}
+
+	c.batchReconcileHandler(ctx, batch)
+	return true
+}
+
+// getNextBatch blocks until an item is queued, and returns it along with the other items
+// that are queued, up to MaxBatchSize.
+func (c *Controller[request]) getNextBatch() ([]batchItem, bool) {
+	// The workers take their batches in turn, so that the queued items counted by Len
+	// are still there when they're taken.
+	c.batchMu.Lock()
+	defer c.batchMu.Unlock()
+
+	obj, priority, shutdown := c.getNextWorkItem()
+	if shutdown {
+		return nil, true
+	}
+	batch := []batchItem{{obj: obj, priority: priority}}
+	for len(batch) < c.MaxBatchSize && c.Queue.Len() > 0 {
+		obj, priority, shutdown := c.getNextWorkItem()
+		if shutdown {
+			break
+		}
+		batch = append(batch, batchItem{obj: obj, priority: priority})
+	}
+	return batch, false
+}
+
+func (c *Controller[request]) batchReconcileHandler(ctx context.Context, batch []batchItem) {
+	// Update metrics after processing each batch
+	reconcileStartTS := time.Now()
+	defer func() {
+		c.updateMetrics(ctx, time.Since(reconcileStartTS))
+	}()
+
+	items := make([]batchItem, 0, len(batch))
+	reqs := make([]request, 0, len(batch))
+	for _, item := range batch {
+		if req, ok := c.requestOf(item.obj); ok {
+			items = append(items, item)
+			reqs = append(reqs, req)
+		}
+	}
+	if len(reqs) == 0 {
+		return
+	}
+
+	log := c.LogConstructor(nil).WithValues("batchSize", len(reqs))
+	ctx, log = c.reconcileContext(ctx, log)
+	result, err := c.reconcileWithTimeout(ctx, log, func(ctx context.Context) (reconcile.Result, error) {
+		return c.reconcileBatch(ctx, reqs)
+	})
+	for i, req := range reqs {
+		c.handleResult(ctx, c.LogConstructor(&req), items[i].obj, req, items[i].priority, result, err)
+	}
+}
+
+// reconcileBatch calls BatchDo, and handles its panics like Reconcile does.
+func (c *Controller[request]) reconcileBatch(ctx context.Context, reqs []request) (_ reconcile.Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.metrics().ReconcilePanics.WithLabelValues().Inc()
+			if c.RecoverPanic {
+				for _, fn := range utilruntime.PanicHandlers {
+					fn(r)
+				}
+				if c.OnPanic != nil {
+					for _, req := range reqs {
+						c.OnPanic(ctx, req, r)
+					}
+				}
+				err = &panicError{recovered: r}
+				return
+			}
+
+			c.metrics().ReconcileTotal.WithLabelValues(labelPanic).Inc()
+
+			log := logf.FromContext(ctx)
+			log.Info(fmt.Sprintf("Observed a panic in batch reconciler: %v", r))
+			panic(r)
+		}
+	}()
+	return c.BatchDo.ReconcileBatch(ctx, reqs)
+}
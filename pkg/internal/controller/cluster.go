@@ -0,0 +1,269 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var _ cluster.Aware = &Controller[reconcile.Request]{}
+
+// engagedCluster is a cluster engaged by the controller.
+type engagedCluster struct {
+	name    string
+	cluster cluster.Cluster
+	// generation tells apart the engagements of clusters with the same name, so that the
+	// requests queued for a cluster that is disengaged aren't reconciled once it's engaged again.
+	generation uint64
+	// ctx is canceled once the cluster is disengaged, which stops its sources.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// watches are the watches started on the cluster, which are stopped by Unwatch.
+	watches []runningWatch
+}
+
+// Engage implements cluster.Aware. It starts the watches of the ClusterSources of the
+// controller on the cluster, or defers it until the controller is started.
+func (c *Controller[request]) Engage(ctx context.Context, name string, cl cluster.Cluster) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	ec := &engagedCluster{name: name, cluster: cl, ctx: ctx, cancel: cancel}
+
+	// The cluster is engaged before its watches are started, so that the requests they
+	// queue aren't dropped.
+	c.clustersMu.Lock()
+	if _, ok := c.clusters[name]; ok {
+		c.clustersMu.Unlock()
+		cancel()
+		return fmt.Errorf("cluster %q is already engaged", name)
+	}
+	if c.clusters == nil {
+		c.clusters = map[string]*engagedCluster{}
+	}
+	c.clusterGeneration++
+	ec.generation = c.clusterGeneration
+	c.clusters[name] = ec
+	c.clustersMu.Unlock()
+
+	if !c.Started {
+		return nil
+	}
+	if err := c.startClusterWatches(ec, c.clusterWatches); err != nil {
+		c.disengage(name)
+		return err
+	}
+	return nil
+}
+
+// Disengage implements cluster.Aware. It stops the watches on the cluster, and drops
+// the requests of the cluster that are queued.
+func (c *Controller[request]) Disengage(_ context.Context, name string) error {
+	if !c.disengage(name) {
+		return fmt.Errorf("cluster %q is not engaged", name)
+	}
+	c.LogConstructor(nil).Info("Disengaged cluster", "cluster", name)
+	return nil
+}
+
+// disengage removes the cluster and stops its watches, and returns whether it was engaged.
+func (c *Controller[request]) disengage(name string) bool {
+	c.clustersMu.Lock()
+	defer c.clustersMu.Unlock()
+	ec, ok := c.clusters[name]
+	if !ok {
+		return false
+	}
+	delete(c.clusters, name)
+	ec.cancel()
+	return true
+}
+
+// isOfDisengagedCluster returns whether the queue item is a request of a cluster that is
+// not engaged, e.g. as it was disengaged since the request was queued, or a request queued
+// for a former engagement of a cluster with the same name.
+func (c *Controller[request]) isOfDisengagedCluster(obj interface{}) bool {
+	var (
+		name       string
+		generation uint64
+	)
+	switch r := obj.(type) {
+	case clusterRequest:
+		name, generation = r.ClusterName, r.generation
+	case reconcile.Request:
+		name = r.ClusterName
+	}
+	if name == "" {
+		return false
+	}
+
+	c.clustersMu.RLock()
+	defer c.clustersMu.RUnlock()
+	ec, ok := c.clusters[name]
+	return !ok || (generation != 0 && ec.generation != generation)
+}
+
+// clusterContext returns a copy of ctx that holds the cluster of req: the engaged cluster
+// of a reconcile.Request qualified with a cluster name, or the cluster of the manager.
+func (c *Controller[request]) clusterContext(ctx context.Context, req request) context.Context {
+	cl := c.Cluster
+	if r, ok := any(req).(reconcile.Request); ok && r.ClusterName != "" {
+		c.clustersMu.RLock()
+		ec, engaged := c.clusters[r.ClusterName]
+		c.clustersMu.RUnlock()
+		if !engaged {
+			return ctx
+		}
+		cl = ec.cluster
+	}
+	if cl == nil {
+		return ctx
+	}
+	return cluster.IntoContext(ctx, cl)
+}
+
+// engagedClusters returns the engaged clusters.
+func (c *Controller[request]) engagedClusters() []*engagedCluster {
+	c.clustersMu.RLock()
+	defer c.clustersMu.RUnlock()
+	clusters := make([]*engagedCluster, 0, len(c.clusters))
+	for _, ec := range c.clusters {
+		clusters = append(clusters, ec)
+	}
+	return clusters
+}
+
+// startClusterWatches starts the watches of ClusterSources on the engaged cluster, and
+// waits for the sources to sync. It must be called with c.mu held once the controller
+// is started.
+func (c *Controller[request]) startClusterWatches(ec *engagedCluster, watches []watchDescription) error {
+	var syncingSources []source.SyncingSource
+	for _, watch := range watches {
+		src, err := watch.src.(source.ClusterSource).ForCluster(ec.name, ec.cluster)
+		if err != nil {
+			return err
+		}
+		c.LogConstructor(nil).Info("Starting EventSource", "source", src, "cluster", ec.name)
+		watchCtx, cancel := context.WithCancel(ec.ctx)
+		ec.watches = append(ec.watches, runningWatch{src: watch.src, cancel: cancel})
+		if err := src.Start(watchCtx, watch.handler, c.clusterQueue(ec, watchCtx), watch.predicates...); err != nil {
+			return err
+		}
+		if syncingSource, ok := src.(source.SyncingSource); ok {
+			syncingSources = append(syncingSources, syncingSource)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ec.ctx, c.CacheSyncTimeout)
+	defer cancel()
+	for _, syncingSource := range syncingSources {
+		if err := syncingSource.WaitForSync(ctx); err != nil {
+			return fmt.Errorf("failed to wait for %s caches of cluster %q to sync: %w", c.Name, ec.name, err)
+		}
+	}
+	return nil
+}
+
+// clusterRequest is the queue item of a reconcile.Request of an engaged cluster, which
+// records the engagement of the cluster it was queued for.
+type clusterRequest struct {
+	reconcile.Request
+	generation uint64
+}
+
+// clusterQueue returns the queue of the sources of the engaged cluster, which is stopped
+// along with ctx.
+func (c *Controller[request]) clusterQueue(ec *engagedCluster, ctx context.Context) workqueue.RateLimitingInterface {
+	queue := c.sourceQueue()
+	q := &clusterQueue{RateLimitingInterface: queue, name: ec.name, generation: ec.generation, ctx: ctx}
+	if pq, ok := queue.(priorityqueue.PriorityQueue); ok {
+		return &clusterPriorityQueue{clusterQueue: q, pq: pq}
+	}
+	return q
+}
+
+// clusterQueue is the queue of the sources of an engaged cluster. It qualifies the requests
+// that are added with the engagement of the cluster, and drops them once the cluster is
+// disengaged.
+type clusterQueue struct {
+	workqueue.RateLimitingInterface
+	name       string
+	generation uint64
+	ctx        context.Context
+}
+
+func (q *clusterQueue) Add(item interface{}) {
+	if item, ok := q.item(item); ok {
+		q.RateLimitingInterface.Add(item)
+	}
+}
+
+func (q *clusterQueue) AddAfter(item interface{}, duration time.Duration) {
+	if item, ok := q.item(item); ok {
+		q.RateLimitingInterface.AddAfter(item, duration)
+	}
+}
+
+func (q *clusterQueue) AddRateLimited(item interface{}) {
+	if item, ok := q.item(item); ok {
+		q.RateLimitingInterface.AddRateLimited(item)
+	}
+}
+
+// item returns the item to add to the queue, and false if the cluster was disengaged.
+func (q *clusterQueue) item(item interface{}) (interface{}, bool) {
+	if q.ctx.Err() != nil {
+		return nil, false
+	}
+	if req, ok := item.(reconcile.Request); ok {
+		req.ClusterName = q.name
+		return clusterRequest{Request: req, generation: q.generation}, true
+	}
+	return item, true
+}
+
+// clusterPriorityQueue is a clusterQueue that keeps the priorities of the items.
+type clusterPriorityQueue struct {
+	*clusterQueue
+	pq priorityqueue.PriorityQueue
+}
+
+var _ priorityqueue.PriorityQueue = &clusterPriorityQueue{}
+
+func (q *clusterPriorityQueue) AddWithOpts(o priorityqueue.AddOpts, items ...interface{}) {
+	clusterItems := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		if item, ok := q.item(item); ok {
+			clusterItems = append(clusterItems, item)
+		}
+	}
+	q.pq.AddWithOpts(o, clusterItems...)
+}
+
+func (q *clusterPriorityQueue) GetWithPriority() (interface{}, int, bool) {
+	return q.pq.GetWithPriority()
+}
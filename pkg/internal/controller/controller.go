@@ -20,26 +20,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller/budget"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-var _ inject.Injector = &Controller{}
+var _ inject.Injector = &Controller[reconcile.Request]{}
 
-// Controller implements controller.Controller.
-type Controller struct {
+// Controller implements controller.TypedController for requests of type request.
+type Controller[request comparable] struct {
 	// Name is used to uniquely identify a Controller in tracing, logging and monitoring.  Name is required.
 	Name string
 
@@ -49,7 +58,7 @@ type Controller struct {
 	// Reconciler is a function that can be called at any time with the Name / Namespace of an object and
 	// ensures that the state of the system matches the state specified in the object.
 	// Defaults to the DefaultReconcileFunc.
-	Do reconcile.Reconciler
+	Do reconcile.TypedReconciler[request]
 
 	// MakeQueue constructs the queue for this controller once the controller is ready to start.
 	// This exists because the standard Kubernetes workqueues start themselves immediately, which
@@ -82,16 +91,134 @@ type Controller struct {
 	CacheSyncTimeout time.Duration
 
 	// startWatches maintains a list of sources, handlers, and predicates to start when the controller is started.
+	// They are kept once started, to start them again if the controller is restarted.
 	startWatches []watchDescription
 
+	// clusterWatches are the watches of ClusterSources, which are started on every engaged cluster.
+	clusterWatches []watchDescription
+
+	// runningWatches are the watches whose sources were started, which are stopped by Unwatch.
+	// Unlike the watch descriptions, they don't hold the handlers and predicates of the watches.
+	runningWatches []runningWatch
+
+	// clusters are the engaged clusters by name, guarded by clustersMu rather than mu
+	// as they're read by the workers.
+	clusters   map[string]*engagedCluster
+	clustersMu sync.RWMutex
+	// clusterGeneration is the generation of the last engaged cluster.
+	clusterGeneration uint64
+
 	// LogConstructor is used to construct a logger to then log messages to users during reconciliation,
 	// or for example when a watch is started.
 	// Note: LogConstructor has to be able to handle nil requests as we are also using it
 	// outside the context of a reconciliation.
-	LogConstructor func(request *reconcile.Request) logr.Logger
+	LogConstructor func(req *request) logr.Logger
+
+	// EventRecorder is passed to each reconciliation via the context, see EventRecorderFromContext.
+	EventRecorder record.EventRecorder
+
+	// Cluster is the cluster of the manager, which is passed to the reconciliations of the
+	// requests that aren't of an engaged cluster via the context, see cluster.FromContext.
+	Cluster cluster.Cluster
+
+	// AnnotateEventsWithReconcileID adds the ID of the reconciliation to the events recorded
+	// with the recorder from the context, see ReconcileIDAnnotation.
+	AnnotateEventsWithReconcileID bool
 
 	// RecoverPanic indicates whether the panic caused by reconcile should be recovered.
 	RecoverPanic bool
+
+	// MaxRetries, if set, is the number of times a request is requeued after an error before
+	// it's dropped and handed to the DeadLetterHandler.
+	MaxRetries int
+
+	// DeadLetterHandler, if set, is called with the requests that are dropped after exceeding
+	// MaxRetries, along with the context of their last reconciliation and its error.
+	DeadLetterHandler func(ctx context.Context, req request, err error)
+
+	// OnPanic, if set, is called with each panic of the reconciler that is recovered, along with
+	// the context and request of the reconciliation.
+	OnPanic func(ctx context.Context, req request, recovered interface{})
+
+	// OnStartedWorkers, if set, is called once the workers of the controller are started, with
+	// the context the controller was started with.
+	OnStartedWorkers func(ctx context.Context)
+
+	// OnStoppedWorkers, if set, is called once all the workers of the controller are finished
+	// after the context the controller was started with is done, with that context.
+	OnStoppedWorkers func(ctx context.Context)
+
+	// ReconcileExemplar returns the labels of the exemplar recorded with the reconcile time of
+	// a reconciliation, e.g. its trace ID, given the context it was run with.
+	// Exemplars are not recorded if it is nil or returns false.
+	ReconcileExemplar func(ctx context.Context) (prometheus.Labels, bool)
+
+	// ReconcileTimeout, if set, is the duration after which the context passed to each
+	// reconciliation is cancelled.
+	ReconcileTimeout time.Duration
+
+	// DebounceWindow, if set, is the delay of the requests added by the sources, during which
+	// the requests for the same object collapse into one.
+	DebounceWindow time.Duration
+
+	// RequeueJitter is the fraction by which each Result.RequeueAfter is randomly increased or decreased.
+	RequeueJitter float64
+
+	// ReconcileBudget, if set, is acquired by the workers before each reconciliation, to cap the
+	// concurrent reconciliations of the controllers sharing it.
+	ReconcileBudget *budget.Budget
+
+	// AcquireSlot, if set, is called by the workers before each reconciliation, after acquiring the
+	// ReconcileBudget, and the function it returns is called once the reconciliation is done.
+	AcquireSlot func(ctx context.Context) (release func(), err error)
+
+	// workers are the stop channels of the running workers, which are closed to stop
+	// them when the number of workers is decreased.
+	workers   []chan struct{}
+	workersWG sync.WaitGroup
+
+	// NeedWarmup makes Warmup start the sources of the watches before the controller is started.
+	NeedWarmup bool
+
+	// startedEventSources is true once the sources of the watches were started, by Start or Warmup.
+	startedEventSources bool
+
+	// BatchDo, if set, reconciles the requests instead of Do, in batches of the requests that
+	// are queued at the same time.
+	BatchDo reconcile.TypedBatchReconciler[request]
+
+	// MaxBatchSize is the maximum number of requests reconciled by a call of BatchDo.
+	MaxBatchSize int
+
+	// batchMu is held by the workers taking a batch from the queue.
+	batchMu sync.Mutex
+
+	// ShardedWorkers makes each worker process the requests of a fixed shard of the keys, so
+	// that the requests for the same key are always processed by the same worker.
+	ShardedWorkers bool
+
+	// queueMu guards the Queue for QueueStatus, which can't take mu as it's held while
+	// the sources are syncing.
+	queueMu sync.RWMutex
+
+	// inFlight holds the items being processed, with the time their processing started.
+	inFlight   map[interface{}]time.Time
+	inFlightMu sync.Mutex
+
+	// resumed is closed when the controller is resumed, and nil if it's not paused.
+	resumed chan struct{}
+	pauseMu sync.Mutex
+
+	// Metrics holds the metrics of the controller, including its extra labels.
+	// Defaults to the metrics of the controller without extra labels.
+	Metrics *ctrlmetrics.ControllerMetrics
+
+	// metricsOnce is used to default Metrics.
+	metricsOnce sync.Once
+
+	// randFloat64 returns a pseudo-random number in [0.0,1.0) used to compute the requeue jitter.
+	// Defaults to rand.Float64.
+	randFloat64 func() float64
 }
 
 // watchDescription contains all the information necessary to start a watch.
@@ -101,18 +228,24 @@ type watchDescription struct {
 	predicates []predicate.Predicate
 }
 
-// Reconcile implements reconcile.Reconciler.
-func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (_ reconcile.Result, err error) {
+// Reconcile implements reconcile.TypedReconciler.
+func (c *Controller[request]) Reconcile(ctx context.Context, req request) (_ reconcile.Result, err error) {
 	defer func() {
 		if r := recover(); r != nil {
+			c.metrics().ReconcilePanics.WithLabelValues().Inc()
 			if c.RecoverPanic {
 				for _, fn := range utilruntime.PanicHandlers {
 					fn(r)
 				}
-				err = fmt.Errorf("panic: %v [recovered]", r)
+				if c.OnPanic != nil {
+					c.OnPanic(ctx, req, r)
+				}
+				err = &panicError{recovered: r}
 				return
 			}
 
+			c.metrics().ReconcileTotal.WithLabelValues(labelPanic).Inc()
+
 			log := logf.FromContext(ctx)
 			log.Info(fmt.Sprintf("Observed a panic in reconciler: %v", r))
 			panic(r)
@@ -121,8 +254,17 @@ func (c *Controller) Reconcile(ctx context.Context, req reconcile.Request) (_ re
 	return c.Do.Reconcile(ctx, req)
 }
 
+// panicError is returned by Reconcile for a recovered panic.
+type panicError struct {
+	recovered interface{}
+}
+
+func (p *panicError) Error() string {
+	return fmt.Sprintf("panic: %v [recovered]", p.recovered)
+}
+
 // Watch implements controller.Controller.
-func (c *Controller) Watch(src source.Source, evthdler handler.EventHandler, prct ...predicate.Predicate) error {
+func (c *Controller[request]) Watch(src source.Source, evthdler handler.EventHandler, prct ...predicate.Predicate) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -139,24 +281,45 @@ func (c *Controller) Watch(src source.Source, evthdler handler.EventHandler, prc
 		}
 	}
 
-	// Controller hasn't started yet, store the watches locally and return.
-	//
-	// These watches are going to be held on the controller struct until the manager or user calls Start(...).
-	if !c.Started {
-		c.startWatches = append(c.startWatches, watchDescription{src: src, handler: evthdler, predicates: prct})
+	// The events handled by the handler are counted and logged.
+	evthdler = c.instrumentHandler(evthdler)
+
+	// Watches of ClusterSources are started on every engaged cluster instead.
+	if _, ok := src.(source.ClusterSource); ok {
+		watch := watchDescription{src: src, handler: evthdler, predicates: prct}
+		c.clusterWatches = append(c.clusterWatches, watch)
+		if !c.Started {
+			return nil
+		}
+		for _, ec := range c.engagedClusters() {
+			if err := c.startClusterWatches(ec, []watchDescription{watch}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// These watches are going to be held on the controller struct until the manager or user calls Start(...),
+	// or Warmup(...), and are started again if the controller is restarted.
+	watch := watchDescription{src: src, handler: evthdler, predicates: prct}
+	c.startWatches = append(c.startWatches, watch)
+
+	// Controller hasn't started yet, the watch is started along with the others.
+	if !c.startedEventSources {
 		return nil
 	}
 
 	c.LogConstructor(nil).Info("Starting EventSource", "source", src)
-	return src.Start(c.ctx, evthdler, c.Queue, prct...)
+	return c.startWatch(c.ctx, watch)
 }
 
 // Start implements controller.Controller.
-func (c *Controller) Start(ctx context.Context) error {
+func (c *Controller[request]) Start(ctx context.Context) error {
 	// use an IIFE to get proper lock handling
 	// but lock outside to get proper handling of the queue shutdown
 	c.mu.Lock()
 	if c.Started {
+		c.mu.Unlock()
 		return errors.New("controller was started more than once. This is likely to be caused by being added to a manager multiple times")
 	}
 
@@ -165,75 +328,44 @@ func (c *Controller) Start(ctx context.Context) error {
 	// Set the internal context.
 	c.ctx = ctx
 
-	c.Queue = c.MakeQueue()
+	// The queue was made by Warmup if it started the event sources.
+	if !c.startedEventSources {
+		c.makeQueue()
+	}
+	queue := c.Queue
 	go func() {
 		<-ctx.Done()
-		c.Queue.ShutDown()
+		queue.ShutDown()
 	}()
 
-	wg := &sync.WaitGroup{}
 	err := func() error {
 		defer c.mu.Unlock()
 
 		// TODO(pwittrock): Reconsider HandleCrash
 		defer utilruntime.HandleCrash()
 
-		// NB(directxman12): launch the sources *before* trying to wait for the
-		// caches to sync so that they have a chance to register their intendeded
-		// caches.
-		for _, watch := range c.startWatches {
-			c.LogConstructor(nil).Info("Starting EventSource", "source", fmt.Sprintf("%s", watch.src))
-
-			if err := watch.src.Start(ctx, watch.handler, c.Queue, watch.predicates...); err != nil {
-				return err
-			}
+		if err := c.startEventSources(ctx); err != nil {
+			return err
 		}
 
-		// Start the SharedIndexInformer factories to begin populating the SharedIndexInformer caches
-		c.LogConstructor(nil).Info("Starting Controller")
-
-		for _, watch := range c.startWatches {
-			syncingSource, ok := watch.src.(source.SyncingSource)
-			if !ok {
-				continue
-			}
-
-			if err := func() error {
-				// use a context with timeout for launching sources and syncing caches.
-				sourceStartCtx, cancel := context.WithTimeout(ctx, c.CacheSyncTimeout)
-				defer cancel()
-
-				// WaitForSync waits for a definitive timeout, and returns if there
-				// is an error or a timeout
-				if err := syncingSource.WaitForSync(sourceStartCtx); err != nil {
-					err := fmt.Errorf("failed to wait for %s caches to sync: %w", c.Name, err)
-					c.LogConstructor(nil).Error(err, "Could not wait for Cache to sync")
-					return err
-				}
-
-				return nil
-			}(); err != nil {
-				return err
+		// Start the watches on the clusters that were engaged before the controller started.
+		// A cluster whose watches can't be started doesn't prevent the controller from
+		// reconciling the other ones.
+		for _, ec := range c.engagedClusters() {
+			if err := c.startClusterWatches(ec, c.clusterWatches); err != nil {
+				c.LogConstructor(nil).Error(err, "Could not start watches, disengaging cluster", "cluster", ec.name)
+				c.disengage(ec.name)
 			}
 		}
 
-		// All the watches have been started, we can reset the local slice.
-		//
-		// We should never hold watches more than necessary, each watch source can hold a backing cache,
-		// which won't be garbage collected if we hold a reference to it.
-		c.startWatches = nil
-
 		// Launch workers to process resources
 		c.LogConstructor(nil).Info("Starting workers", "worker count", c.MaxConcurrentReconciles)
-		wg.Add(c.MaxConcurrentReconciles)
-		for i := 0; i < c.MaxConcurrentReconciles; i++ {
-			go func() {
-				defer wg.Done()
-				// Run a worker thread that just dequeues items, processes them, and marks them done.
-				// It enforces that the reconcileHandler is never invoked concurrently with the same object.
-				for c.processNextWorkItem(ctx) {
-				}
-			}()
+		if c.ShardedWorkers {
+			c.startShardedWorkers(ctx)
+		} else {
+			for i := 0; i < c.MaxConcurrentReconciles; i++ {
+				c.startWorker(ctx)
+			}
 		}
 
 		c.Started = true
@@ -242,23 +374,156 @@ func (c *Controller) Start(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if c.OnStartedWorkers != nil {
+		c.OnStartedWorkers(ctx)
+	}
 
 	<-ctx.Done()
 	c.LogConstructor(nil).Info("Shutdown signal received, waiting for all workers to finish")
-	wg.Wait()
+	// Taking the lock waits for a concurrent SetMaxConcurrentReconciles to be done starting
+	// workers, the later ones don't start any.
+	c.mu.Lock()
+	c.workers = nil
+	c.mu.Unlock()
+	c.workersWG.Wait()
 	c.LogConstructor(nil).Info("All workers finished")
+
+	c.mu.Lock()
+	c.stop()
+	c.mu.Unlock()
+	if c.OnStoppedWorkers != nil {
+		c.OnStoppedWorkers(ctx)
+	}
+	return nil
+}
+
+// Warmup implements manager.WarmupRunnable. If NeedWarmup is set, it makes the queue and
+// starts the sources of the watches with the given context, so that the caches are synced
+// and the requests are queued by the time the controller is started.
+func (c *Controller[request]) Warmup(ctx context.Context) error {
+	if !c.NeedWarmup {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Started || c.startedEventSources {
+		return nil
+	}
+
+	c.ctx = ctx
+	queue := c.makeQueue()
+	go func() {
+		<-ctx.Done()
+		queue.ShutDown()
+	}()
+	return c.startEventSources(ctx)
+}
+
+// makeQueue makes the Queue of the controller and returns it. It must be called with the
+// lock held.
+func (c *Controller[request]) makeQueue() workqueue.RateLimitingInterface {
+	queue := c.metrics().MakeQueue(c.MakeQueue)
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+	c.Queue = queue
+	return queue
+}
+
+// startEventSources starts the sources of the watches and waits for their caches to sync,
+// unless Warmup already did. It must be called with the lock held.
+func (c *Controller[request]) startEventSources(ctx context.Context) error {
+	if c.startedEventSources {
+		return nil
+	}
+	c.startedEventSources = true
+
+	// NB(directxman12): launch the sources *before* trying to wait for the
+	// caches to sync so that they have a chance to register their intendeded
+	// caches.
+	for _, watch := range c.startWatches {
+		c.LogConstructor(nil).Info("Starting EventSource", "source", fmt.Sprintf("%s", watch.src))
+
+		if err := c.startWatch(ctx, watch); err != nil {
+			return err
+		}
+	}
+
+	// Start the SharedIndexInformer factories to begin populating the SharedIndexInformer caches
+	c.LogConstructor(nil).Info("Starting Controller")
+
+	cacheSyncStart := time.Now()
+	for _, watch := range c.startWatches {
+		syncingSource, ok := watch.src.(source.SyncingSource)
+		if !ok {
+			continue
+		}
+
+		if err := func() error {
+			// use a context with timeout for launching sources and syncing caches.
+			sourceStartCtx, cancel := context.WithTimeout(ctx, c.CacheSyncTimeout)
+			defer cancel()
+
+			// WaitForSync waits for a definitive timeout, and returns if there
+			// is an error or a timeout
+			if err := syncingSource.WaitForSync(sourceStartCtx); err != nil {
+				err := fmt.Errorf("failed to wait for %s caches to sync: %w", c.Name, err)
+				c.LogConstructor(nil).Error(err, "Could not wait for Cache to sync")
+				return err
+			}
+
+			return nil
+		}(); err != nil {
+			return err
+		}
+	}
+
+	c.metrics().CacheSyncWait.WithLabelValues().Set(time.Since(cacheSyncStart).Seconds())
 	return nil
 }
 
+// stop stops the sources started by the controller, including the ones of the engaged
+// clusters, so that it can be started again with a new context and queue. It must be
+// called with the lock held once the workers are finished.
+func (c *Controller[request]) stop() {
+	for _, watch := range c.runningWatches {
+		watch.cancel()
+	}
+	c.runningWatches = nil
+	for _, ec := range c.engagedClusters() {
+		for _, watch := range ec.watches {
+			watch.cancel()
+		}
+		ec.watches = nil
+	}
+	c.startedEventSources = false
+	c.Started = false
+}
+
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the reconcileHandler.
-func (c *Controller) processNextWorkItem(ctx context.Context) bool {
-	obj, shutdown := c.Queue.Get()
+func (c *Controller[request]) processNextWorkItem(ctx context.Context) bool {
+	if c.BatchDo != nil {
+		return c.processNextBatch(ctx)
+	}
+
+	// The requests stay queued while the controller is paused.
+	if !c.waitWhilePaused(ctx) {
+		return false
+	}
+
+	obj, priority, shutdown := c.getNextWorkItem()
 	if shutdown {
 		// Stop working
 		return false
 	}
 
+	return c.processWorkItem(ctx, obj, priority)
+}
+
+// processWorkItem processes an item taken from the queue, and returns false if the
+// worker should stop.
+func (c *Controller[request]) processWorkItem(ctx context.Context, obj interface{}, priority int) bool {
 	// We call Done here so the workqueue knows we have finished
 	// processing this item. We also must remember to call Forget if we
 	// do not want this work item being re-queued. For example, we do
@@ -267,62 +532,200 @@ func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	// period.
 	defer c.Queue.Done(obj)
 
-	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Add(1)
-	defer ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Add(-1)
+	// The controller may have been paused while the worker waited for the request,
+	// in which case the request is held until the controller is resumed.
+	if !c.waitWhilePaused(ctx) {
+		return false
+	}
+
+	if c.ReconcileBudget != nil {
+		if err := c.ReconcileBudget.Acquire(ctx); err != nil {
+			// The controller is stopping, and so is the queue.
+			return true
+		}
+		defer c.ReconcileBudget.Release()
+	}
+
+	release, ok := c.acquireSlot(ctx, obj)
+	if !ok {
+		return true
+	}
+	defer release()
 
-	c.reconcileHandler(ctx, obj)
+	c.metrics().ActiveWorkers.WithLabelValues().Add(1)
+	defer c.metrics().ActiveWorkers.WithLabelValues().Add(-1)
+	defer c.trackInFlight(obj)()
+
+	c.reconcileHandler(ctx, obj, priority)
 	return true
 }
 
+// acquireSlot acquires a slot with AcquireSlot, if set, before reconciling the given items,
+// and returns the function releasing it. If no slot can be acquired, the items are requeued
+// with backoff, unless the controller is stopping, and false is returned.
+func (c *Controller[request]) acquireSlot(ctx context.Context, objs ...interface{}) (func(), bool) {
+	if c.AcquireSlot == nil {
+		return func() {}, true
+	}
+	release, err := c.AcquireSlot(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.LogConstructor(nil).Error(err, "Failed to acquire a slot to reconcile, requeueing", "count", len(objs))
+			for _, obj := range objs {
+				c.Queue.AddRateLimited(obj)
+			}
+		}
+		return nil, false
+	}
+	if release == nil {
+		release = func() {}
+	}
+	return release, true
+}
+
+// getNextWorkItem returns the next item from the queue, along with its priority
+// if the queue supports priorities.
+func (c *Controller[request]) getNextWorkItem() (interface{}, int, bool) {
+	if pq, ok := c.Queue.(priorityqueue.PriorityQueue); ok {
+		return pq.GetWithPriority()
+	}
+	obj, shutdown := c.Queue.Get()
+	return obj, 0, shutdown
+}
+
 const (
-	labelError        = "error"
-	labelRequeueAfter = "requeue_after"
-	labelRequeue      = "requeue"
-	labelSuccess      = "success"
+	labelError         = "error"
+	labelTerminalError = "terminal_error"
+	labelPanic         = "panic"
+	labelRequeueAfter  = "requeue_after"
+	labelRequeue       = "requeue"
+	labelSuccess       = "success"
 )
 
-func (c *Controller) initMetrics() {
-	ctrlmetrics.ActiveWorkers.WithLabelValues(c.Name).Set(0)
-	ctrlmetrics.ReconcileErrors.WithLabelValues(c.Name).Add(0)
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelError).Add(0)
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeueAfter).Add(0)
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeue).Add(0)
-	ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelSuccess).Add(0)
-	ctrlmetrics.WorkerCount.WithLabelValues(c.Name).Set(float64(c.MaxConcurrentReconciles))
+func (c *Controller[request]) initMetrics() {
+	c.metrics().ActiveWorkers.WithLabelValues().Set(0)
+	c.metrics().ReconcileErrors.WithLabelValues().Add(0)
+	c.metrics().TerminalReconcileErrors.WithLabelValues().Add(0)
+	c.metrics().ReconcileTimeouts.WithLabelValues().Add(0)
+	c.metrics().ReconcilePanics.WithLabelValues().Add(0)
+	c.metrics().DeadLetters.WithLabelValues().Add(0)
+	c.metrics().ReconcileTotal.WithLabelValues(labelError).Add(0)
+	c.metrics().ReconcileTotal.WithLabelValues(labelTerminalError).Add(0)
+	c.metrics().ReconcileTotal.WithLabelValues(labelPanic).Add(0)
+	c.metrics().ReconcileTotal.WithLabelValues(labelRequeueAfter).Add(0)
+	c.metrics().ReconcileTotal.WithLabelValues(labelRequeue).Add(0)
+	c.metrics().ReconcileTotal.WithLabelValues(labelSuccess).Add(0)
+	c.metrics().Events.WithLabelValues(labelCreate).Add(0)
+	c.metrics().Events.WithLabelValues(labelUpdate).Add(0)
+	c.metrics().Events.WithLabelValues(labelDelete).Add(0)
+	c.metrics().Events.WithLabelValues(labelGeneric).Add(0)
+	c.metrics().WorkerCount.WithLabelValues().Set(float64(c.MaxConcurrentReconciles))
+	if c.IsPaused() {
+		c.metrics().Paused.WithLabelValues().Set(1)
+	} else {
+		c.metrics().Paused.WithLabelValues().Set(0)
+	}
 }
 
-func (c *Controller) reconcileHandler(ctx context.Context, obj interface{}) {
+func (c *Controller[request]) reconcileHandler(ctx context.Context, obj interface{}, priority int) {
 	// Update metrics after processing each item
 	reconcileStartTS := time.Now()
 	defer func() {
-		c.updateMetrics(time.Since(reconcileStartTS))
+		c.updateMetrics(ctx, time.Since(reconcileStartTS))
 	}()
 
+	req, ok := c.requestOf(obj)
+	if !ok {
+		// Return true, don't take a break
+		return
+	}
+
+	log := c.LogConstructor(&req)
+	ctx, log = c.reconcileContext(ctx, log)
+	ctx = c.clusterContext(ctx, req)
+
+	// RunInformersAndControllers the syncHandler, passing it the Namespace/Name string of the
+	// resource to be synced.
+	result, err := c.reconcileWithTimeout(ctx, log, func(ctx context.Context) (reconcile.Result, error) {
+		return c.Reconcile(ctx, req)
+	})
+	c.handleResult(ctx, log, obj, req, priority, result, err)
+}
+
+// requestOf returns the request of an item taken from the queue, and false if the
+// item is dropped, as it's not a request or the request of a disengaged cluster.
+func (c *Controller[request]) requestOf(obj interface{}) (request, bool) {
+	item := obj
+	if cr, ok := obj.(clusterRequest); ok {
+		item = cr.Request
+	}
+
 	// Make sure that the object is a valid request.
-	req, ok := obj.(reconcile.Request)
+	req, ok := item.(request)
 	if !ok {
 		// As the item in the workqueue is actually invalid, we call
 		// Forget here else we'd go into a loop of attempting to
 		// process a work item that is invalid.
 		c.Queue.Forget(obj)
 		c.LogConstructor(nil).Error(nil, "Queue item was not a Request", "type", fmt.Sprintf("%T", obj), "value", obj)
-		// Return true, don't take a break
-		return
+		return req, false
 	}
 
-	log := c.LogConstructor(&req)
+	if c.isOfDisengagedCluster(obj) {
+		// The cluster was disengaged since the request was queued.
+		c.Queue.Forget(obj)
+		c.LogConstructor(&req).V(1).Info("Dropping request of a disengaged cluster")
+		return req, false
+	}
+	return req, true
+}
 
-	log = log.WithValues("reconcileID", uuid.NewUUID())
+// reconcileContext returns the context of a reconciliation, which holds the logger, the
+// reconcile ID and the event recorder, along with the logger.
+func (c *Controller[request]) reconcileContext(ctx context.Context, log logr.Logger) (context.Context, logr.Logger) {
+	reconcileID := uuid.NewUUID()
+	if logf.HasReconcileLogField(logf.ReconcileLogFieldReconcileID) {
+		log = log.WithValues("reconcileID", reconcileID)
+	}
 	ctx = logf.IntoContext(ctx, log)
+	ctx = ReconcileIDIntoContext(ctx, reconcileID)
 
-	// RunInformersAndControllers the syncHandler, passing it the Namespace/Name string of the
-	// resource to be synced.
-	result, err := c.Reconcile(ctx, req)
+	if c.EventRecorder != nil {
+		recorder := c.EventRecorder
+		if c.AnnotateEventsWithReconcileID {
+			recorder = &reconcileIDRecorder{EventRecorder: recorder, reconcileID: reconcileID}
+		}
+		ctx = EventRecorderIntoContext(ctx, recorder)
+	}
+	return ctx, log
+}
+
+// handleResult requeues or forgets the item of a request depending on the result of its
+// reconciliation, and records it in the metrics.
+func (c *Controller[request]) handleResult(ctx context.Context, log logr.Logger, obj interface{}, req request, priority int, result reconcile.Result, err error) {
 	switch {
+	case err != nil && errors.Is(err, reconcile.TerminalError(nil)):
+		// Terminal errors are not retried, so the Result is ignored and the
+		// rate limiter entry is dropped. They are still counted as errors.
+		c.Queue.Forget(obj)
+		c.metrics().TerminalReconcileErrors.WithLabelValues().Inc()
+		c.metrics().ReconcileErrors.WithLabelValues().Inc()
+		c.metrics().ReconcileTotal.WithLabelValues(labelTerminalError).Inc()
+		log.V(1).Info("Reconciler returned a terminal error, not requeueing", "error", err)
+	case err != nil && c.MaxRetries > 0 && c.Queue.NumRequeues(obj) >= c.MaxRetries:
+		// The request is out of retries, so it's dropped like for a terminal error.
+		c.Queue.Forget(obj)
+		c.metrics().DeadLetters.WithLabelValues().Inc()
+		c.metrics().ReconcileErrors.WithLabelValues().Inc()
+		c.metrics().ReconcileTotal.WithLabelValues(errorLabel(err)).Inc()
+		log.Error(err, "Reconciler error, dropping the request after exceeding the maximum number of retries", "maxRetries", c.MaxRetries)
+		if c.DeadLetterHandler != nil {
+			c.DeadLetterHandler(ctx, req, err)
+		}
 	case err != nil:
-		c.Queue.AddRateLimited(req)
-		ctrlmetrics.ReconcileErrors.WithLabelValues(c.Name).Inc()
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelError).Inc()
+		c.requeue(obj, priorityqueue.AddOpts{RateLimited: true, Priority: priority})
+		c.metrics().ReconcileErrors.WithLabelValues().Inc()
+		c.metrics().ReconcileTotal.WithLabelValues(errorLabel(err)).Inc()
 		log.Error(err, "Reconciler error")
 	case result.RequeueAfter > 0:
 		// The result.RequeueAfter request will be lost, if it is returned
@@ -330,31 +733,162 @@ func (c *Controller) reconcileHandler(ctx context.Context, obj interface{}) {
 		// We need to drive to stable reconcile loops before queuing due
 		// to result.RequestAfter
 		c.Queue.Forget(obj)
-		c.Queue.AddAfter(req, result.RequeueAfter)
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeueAfter).Inc()
+		requeueAfter := c.jitter(result.RequeueAfter)
+		if c.RequeueJitter > 0 {
+			log.V(2).Info("Applied jitter to RequeueAfter", "requeueAfter", result.RequeueAfter, "delay", requeueAfter)
+		}
+		c.requeue(obj, priorityqueue.AddOpts{After: requeueAfter, Priority: resultPriority(result, priority)})
+		c.metrics().ReconcileTotal.WithLabelValues(labelRequeueAfter).Inc()
+		c.metrics().RequeueAfter.WithLabelValues().Observe(result.RequeueAfter.Seconds())
 	case result.Requeue:
-		c.Queue.AddRateLimited(req)
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelRequeue).Inc()
+		c.requeue(obj, priorityqueue.AddOpts{RateLimited: true, Priority: resultPriority(result, priority)})
+		c.metrics().ReconcileTotal.WithLabelValues(labelRequeue).Inc()
 	default:
 		// Finally, if no error occurs we Forget this item so it does not
 		// get queued again until another change happens.
 		c.Queue.Forget(obj)
-		ctrlmetrics.ReconcileTotal.WithLabelValues(c.Name, labelSuccess).Inc()
+		c.metrics().ReconcileTotal.WithLabelValues(labelSuccess).Inc()
+	}
+}
+
+// errorLabel returns the label of the reconcile_total metric of a reconciliation that
+// returned the given error.
+func errorLabel(err error) string {
+	var pe *panicError
+	if errors.As(err, &pe) {
+		return labelPanic
+	}
+	return labelError
+}
+
+// reconcileWithTimeout calls fn with a context that is cancelled after the
+// ReconcileTimeout, if set, and reports reconciliations that exceeded it.
+func (c *Controller[request]) reconcileWithTimeout(ctx context.Context, log logr.Logger, fn func(context.Context) (reconcile.Result, error)) (reconcile.Result, error) {
+	if c.ReconcileTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	reconcileCtx, cancel := context.WithTimeout(ctx, c.ReconcileTimeout)
+	defer cancel()
+	result, err := fn(reconcileCtx)
+	// The deadline of the parent context, e.g. on shutdown, isn't a timeout of the reconciliation.
+	if errors.Is(reconcileCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		c.metrics().ReconcileTimeouts.WithLabelValues().Inc()
+		log.Info("Reconcile aborted for exceeding the reconcile timeout", "timeout", c.ReconcileTimeout, "error", err)
+	}
+	return result, err
+}
+
+// requeue adds the item of a request back to the queue. The priority in opts is only
+// honored if the queue supports priorities.
+func (c *Controller[request]) requeue(obj interface{}, opts priorityqueue.AddOpts) {
+	if pq, ok := c.Queue.(priorityqueue.PriorityQueue); ok {
+		pq.AddWithOpts(opts, obj)
+		return
+	}
+	switch {
+	case opts.After > 0:
+		c.Queue.AddAfter(obj, opts.After)
+	case opts.RateLimited:
+		c.Queue.AddRateLimited(obj)
+	default:
+		c.Queue.Add(obj)
 	}
 }
 
+// jitter randomly perturbs d by up to RequeueJitter of its value in either
+// direction. It never returns a negative duration.
+func (c *Controller[request]) jitter(d time.Duration) time.Duration {
+	if c.RequeueJitter <= 0 {
+		return d
+	}
+	randFloat64 := c.randFloat64
+	if randFloat64 == nil {
+		randFloat64 = rand.Float64
+	}
+	jittered := time.Duration(float64(d) * (1 + c.RequeueJitter*(2*randFloat64()-1)))
+	if jittered < 0 {
+		return 0
+	}
+	return jittered
+}
+
+// resultPriority returns the priority requested by result, defaulting to the
+// priority the request was dequeued with.
+func resultPriority(result reconcile.Result, dequeued int) int {
+	if result.Priority != nil {
+		return *result.Priority
+	}
+	return dequeued
+}
+
 // GetLogger returns this controller's logger.
-func (c *Controller) GetLogger() logr.Logger {
+func (c *Controller[request]) GetLogger() logr.Logger {
 	return c.LogConstructor(nil)
 }
 
+// Configz returns the configuration of the controller, which the manager reports in
+// its Configz.
+func (c *Controller[request]) Configz() manager.ControllerConfigz {
+	c.mu.Lock()
+	maxConcurrentReconciles := c.MaxConcurrentReconciles
+	c.mu.Unlock()
+	return manager.ControllerConfigz{
+		Name:                    c.Name,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		RecoverPanic:            c.RecoverPanic,
+		CacheSyncTimeout:        metav1.Duration{Duration: c.CacheSyncTimeout},
+		Paused:                  c.IsPaused(),
+	}
+}
+
 // InjectFunc implement SetFields.Injector.
-func (c *Controller) InjectFunc(f inject.Func) error {
+func (c *Controller[request]) InjectFunc(f inject.Func) error {
 	c.SetFields = f
 	return nil
 }
 
+// metrics returns the metrics of the controller.
+func (c *Controller[request]) metrics() *ctrlmetrics.ControllerMetrics {
+	c.metricsOnce.Do(func() {
+		if c.Metrics == nil {
+			// Without extra labels, this can't fail.
+			c.Metrics, _ = ctrlmetrics.ForController(nil, c.Name, nil)
+		}
+	})
+	return c.Metrics
+}
+
 // updateMetrics updates prometheus metrics within the controller.
-func (c *Controller) updateMetrics(reconcileTime time.Duration) {
-	ctrlmetrics.ReconcileTime.WithLabelValues(c.Name).Observe(reconcileTime.Seconds())
+func (c *Controller[request]) updateMetrics(ctx context.Context, reconcileTime time.Duration) {
+	observer := c.metrics().ReconcileTime.WithLabelValues()
+	if c.ReconcileExemplar != nil {
+		if exemplar, ok := c.ReconcileExemplar(ctx); ok {
+			if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+				exemplarObserver.ObserveWithExemplar(reconcileTime.Seconds(), exemplar)
+				return
+			}
+		}
+	}
+	observer.Observe(reconcileTime.Seconds())
+}
+
+// RequestLogValues returns the keys and values of the fields of the given request that
+// are logged according to log.SetReconcileLogFields. The reference to the object is
+// keyed by objectKey. The cluster is always logged if it's set.
+func RequestLogValues(objectKey string, req reconcile.Request) []interface{} {
+	var keysAndValues []interface{}
+	if logf.HasReconcileLogField(logf.ReconcileLogFieldObject) {
+		keysAndValues = append(keysAndValues, objectKey, klog.KRef(req.Namespace, req.Name))
+	}
+	if logf.HasReconcileLogField(logf.ReconcileLogFieldNamespace) {
+		keysAndValues = append(keysAndValues, "namespace", req.Namespace)
+	}
+	if logf.HasReconcileLogField(logf.ReconcileLogFieldName) {
+		keysAndValues = append(keysAndValues, "name", req.Name)
+	}
+	if req.ClusterName != "" {
+		keysAndValues = append(keysAndValues, "cluster", req.ClusterName)
+	}
+	return keysAndValues
 }
@@ -20,10 +20,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
@@ -37,10 +40,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/internal/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
@@ -49,7 +54,7 @@ import (
 
 var _ = Describe("controller", func() {
 	var fakeReconcile *fakeReconciler
-	var ctrl *Controller
+	var ctrl *Controller[reconcile.Request]
 	var queue *controllertest.Queue
 	var informers *informertest.FakeInformers
 	var reconciled chan reconcile.Request
@@ -67,7 +72,7 @@ var _ = Describe("controller", func() {
 			Interface: workqueue.New(),
 		}
 		informers = &informertest.FakeInformers{}
-		ctrl = &Controller{
+		ctrl = &Controller[reconcile.Request]{
 			MaxConcurrentReconciles: 1,
 			Do:                      fakeReconcile,
 			MakeQueue:               func() workqueue.RateLimitingInterface { return queue },
@@ -124,6 +129,103 @@ var _ = Describe("controller", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("[recovered]"))
 		})
+
+		It("should call OnPanic with the recovered panic and count it", func() {
+			panics := func() float64 {
+				var m dto.Metric
+				Expect(ctrlmetrics.ReconcilePanics.WithLabelValues(ctrl.Name).Write(&m)).To(Succeed())
+				return m.GetCounter().GetValue()
+			}
+			before := panics()
+
+			type ctxKey struct{}
+			ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}}
+			var onPanicCalled bool
+			ctrl.RecoverPanic = true
+			ctrl.OnPanic = func(ctx context.Context, r reconcile.Request, recovered interface{}) {
+				onPanicCalled = true
+				Expect(ctx.Value(ctxKey{})).To(Equal("value"))
+				Expect(r).To(Equal(req))
+				Expect(recovered).To(Equal("expected panic: reconcile"))
+			}
+			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				panic("expected panic: reconcile")
+			})
+			_, err := ctrl.Reconcile(ctx, req)
+			Expect(err).To(MatchError(ContainSubstring("[recovered]")))
+			Expect(onPanicCalled).To(BeTrue())
+			Expect(panics()).To(Equal(before + 1))
+		})
+
+		It("should count panics that are not recovered without calling OnPanic", func() {
+			var m dto.Metric
+			Expect(ctrlmetrics.ReconcilePanics.WithLabelValues(ctrl.Name).Write(&m)).To(Succeed())
+			before := m.GetCounter().GetValue()
+
+			ctrl.OnPanic = func(context.Context, reconcile.Request, interface{}) {
+				Fail("OnPanic should not have been called")
+			}
+			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				panic("expected panic: reconcile")
+			})
+			Expect(func() {
+				_, _ = ctrl.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "bar"}})
+			}).To(PanicWith("expected panic: reconcile"))
+
+			Expect(ctrlmetrics.ReconcilePanics.WithLabelValues(ctrl.Name).Write(&m)).To(Succeed())
+			Expect(m.GetCounter().GetValue()).To(Equal(before + 1))
+		})
+	})
+
+	Describe("jitter", func() {
+		It("should not change the duration if RequeueJitter is not set", func() {
+			Expect(ctrl.jitter(time.Minute)).To(Equal(time.Minute))
+		})
+
+		It("should spread the durations within the RequeueJitter", func() {
+			ctrl.RequeueJitter = 0.2
+			ctrl.randFloat64 = rand.New(rand.NewSource(42)).Float64 //nolint:gosec
+
+			var below, above int
+			for i := 0; i < 1000; i++ {
+				d := ctrl.jitter(time.Minute)
+				Expect(d).To(BeNumerically(">=", 48*time.Second))
+				Expect(d).To(BeNumerically("<", 72*time.Second))
+				switch {
+				case d < 54*time.Second:
+					below++
+				case d > 66*time.Second:
+					above++
+				}
+			}
+			// Each of the outer quarters of the range holds about 250 samples.
+			Expect(below).To(BeNumerically(">", 150))
+			Expect(above).To(BeNumerically(">", 150))
+		})
+
+		It("should never return a negative duration", func() {
+			ctrl.RequeueJitter = 1
+			ctrl.randFloat64 = func() float64 { return 0 }
+			Expect(ctrl.jitter(time.Minute)).To(Equal(time.Duration(0)))
+		})
+	})
+
+	Describe("Configz", func() {
+		It("should report the configuration of the controller", func() {
+			ctrl.Name = "configz"
+			ctrl.RecoverPanic = true
+			ctrl.CacheSyncTimeout = time.Minute
+			Expect(ctrl.SetMaxConcurrentReconciles(3)).To(Succeed())
+			ctrl.Pause()
+			Expect(ctrl.Configz()).To(Equal(manager.ControllerConfigz{
+				Name:                    "configz",
+				MaxConcurrentReconciles: 3,
+				RecoverPanic:            true,
+				CacheSyncTimeout:        metav1.Duration{Duration: time.Minute},
+				Paused:                  true,
+			}))
+		})
 	})
 
 	Describe("Start", func() {
@@ -157,6 +259,27 @@ var _ = Describe("controller", func() {
 			Expect(err.Error()).To(ContainSubstring("failed to wait for testcontroller caches to sync: timed out waiting for cache to be synced"))
 		})
 
+		It("should record the time spent waiting for caches to sync", func() {
+			ctrl.CacheSyncTimeout = 10 * time.Second
+			ctrl.Name = "cache-sync-wait"
+			ctrl.startWatches = []watchDescription{{
+				src: &delayedSyncingSource{delay: 100 * time.Millisecond},
+			}}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).To(Succeed())
+			}()
+
+			var cacheSyncWait dto.Metric
+			Eventually(func() float64 {
+				Expect(ctrlmetrics.CacheSyncWait.WithLabelValues(ctrl.Name).Write(&cacheSyncWait)).To(Succeed())
+				return cacheSyncWait.GetGauge().GetValue()
+			}).Should(BeNumerically(">=", 0.1))
+		})
+
 		It("should not error when context cancelled", func() {
 			ctrl.CacheSyncTimeout = 1 * time.Second
 
@@ -292,7 +415,8 @@ var _ = Describe("controller", func() {
 			started := false
 			src := source.Func(func(ctx context.Context, e handler.EventHandler, q workqueue.RateLimitingInterface, p ...predicate.Predicate) error {
 				defer GinkgoRecover()
-				Expect(e).To(Equal(evthdl))
+				Expect(e).To(BeAssignableToTypeOf(&instrumentedHandler{}))
+				Expect(e.(*instrumentedHandler).handler).To(Equal(evthdl))
 				Expect(q).To(Equal(ctrl.Queue))
 				Expect(p).To(ConsistOf(pr1, pr2))
 
@@ -324,18 +448,134 @@ var _ = Describe("controller", func() {
 		})
 
 		It("should return an error if it gets started more than once", func() {
-			// Use a cancelled context so Start doesn't block
 			ctx, cancel := context.WithCancel(context.Background())
-			cancel()
-			Expect(ctrl.Start(ctx)).To(BeNil())
+			defer cancel()
+			started := make(chan struct{})
+			ctrl.OnStartedWorkers = func(context.Context) { close(started) }
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).To(Succeed())
+			}()
+			<-started
+
 			err := ctrl.Start(ctx)
 			Expect(err).NotTo(BeNil())
 			Expect(err.Error()).To(Equal("controller was started more than once. This is likely to be caused by being added to a manager multiple times"))
 		})
 
+		It("should be restartable with a new context once stopped", func() {
+			ctrl.MakeQueue = func() workqueue.RateLimitingInterface {
+				return &controllertest.Queue{Interface: workqueue.New()}
+			}
+			src := &recordingSource{}
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			// Use a cancelled context so Start doesn't block
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(ctrl.Start(ctx)).To(Succeed())
+			Expect(src.ctx.Err()).To(HaveOccurred())
+			Expect(ctrl.Queue.ShuttingDown()).To(BeTrue())
+
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+			started := make(chan struct{})
+			ctrl.OnStartedWorkers = func(context.Context) { close(started) }
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).To(Succeed())
+			}()
+			<-started
+
+			Expect(src.ctx.Err()).NotTo(HaveOccurred())
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			ctrl.Queue.Add(request)
+			Expect(<-reconciled).To(Equal(request))
+		})
+
+		It("should keep delivering the events of a channel source once restarted", func() {
+			ctrl.MakeQueue = func() workqueue.RateLimitingInterface {
+				return &controllertest.Queue{Interface: workqueue.New()}
+			}
+			ch := make(chan event.GenericEvent, 1)
+			src := &source.Channel{Source: ch}
+			stop := make(chan struct{})
+			defer close(stop)
+			Expect(inject.StopChannelInto(stop, src)).To(BeTrue())
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: request.Namespace, Name: request.Name}}
+
+			for i := 0; i < 2; i++ {
+				By(fmt.Sprintf("starting the controller, attempt %d", i+1))
+				ctx, cancel := context.WithCancel(context.Background())
+				started := make(chan struct{})
+				stopped := make(chan struct{})
+				ctrl.OnStartedWorkers = func(context.Context) { close(started) }
+				go func() {
+					defer GinkgoRecover()
+					defer close(stopped)
+					Expect(ctrl.Start(ctx)).To(Succeed())
+				}()
+				<-started
+
+				fakeReconcile.AddResult(reconcile.Result{}, nil)
+				ch <- event.GenericEvent{Object: pod}
+				Eventually(reconciled).Should(Receive(Equal(request)))
+
+				cancel()
+				<-stopped
+			}
+		})
+
 	})
 
 	Describe("Watch", func() {
+		It("should count and log the events handled by the event handlers by their type", func() {
+			ctrl.Name = "instrumented-events"
+			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{}, nil
+			})
+			events := func(eventType string) float64 {
+				var m dto.Metric
+				Expect(ctrlmetrics.Events.WithLabelValues(ctrl.Name, eventType).Write(&m)).To(Succeed())
+				return m.GetCounter().GetValue()
+			}
+
+			var logged []string
+			var loggedMu sync.Mutex
+			ctrl.LogConstructor = func(*reconcile.Request) logr.Logger {
+				return funcr.New(func(_, args string) {
+					loggedMu.Lock()
+					defer loggedMu.Unlock()
+					logged = append(logged, args)
+				}, funcr.Options{Verbosity: 5})
+			}
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"}}
+			src := source.Func(func(_ context.Context, h handler.EventHandler, q workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+				h.Create(event.CreateEvent{Object: pod}, q)
+				h.Update(event.UpdateEvent{ObjectOld: pod, ObjectNew: pod}, q)
+				h.Update(event.UpdateEvent{ObjectOld: pod, ObjectNew: pod}, q)
+				return nil
+			})
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(ctrl.Start(ctx)).To(Succeed())
+
+			Expect(events("create")).To(BeEquivalentTo(1))
+			Expect(events("update")).To(BeEquivalentTo(2))
+			Expect(events("delete")).To(BeEquivalentTo(0))
+			loggedMu.Lock()
+			defer loggedMu.Unlock()
+			Expect(logged).To(ContainElement(SatisfyAll(
+				ContainSubstring(`"msg"="Handling event"`),
+				ContainSubstring(`"event"="create"`),
+				ContainSubstring(`"object"={"name":"pod","namespace":"default"}`),
+			)))
+		})
+
 		It("should inject dependencies into the Source", func() {
 			src := &source.Kind{Type: &corev1.Pod{}}
 			Expect(src.InjectCache(informers)).To(Succeed())
@@ -453,6 +693,136 @@ var _ = Describe("controller", func() {
 			}
 			Expect(ctrl.Watch(src, evthdl, pr1, pr2)).To(Equal(expected))
 		})
+
+		It("should not start sources removed with Unwatch before the controller is started", func() {
+			src := &recordingSource{}
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+			Expect(ctrl.Unwatch(src)).To(Succeed())
+
+			// Use a cancelled context so Start doesn't block
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(ctrl.Start(ctx)).To(Succeed())
+			Expect(src.ctx).To(BeNil())
+		})
+
+		It("should stop sources removed with Unwatch", func() {
+			removed, kept := &recordingSource{}, &recordingSource{}
+			Expect(ctrl.Watch(removed, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).To(Succeed())
+			}()
+			Eventually(func() bool {
+				ctrl.mu.Lock()
+				defer ctrl.mu.Unlock()
+				return ctrl.Started
+			}).Should(BeTrue())
+			Expect(ctrl.Watch(kept, &handler.EnqueueRequestForObject{})).To(Succeed())
+
+			Expect(ctrl.Unwatch(removed)).To(Succeed())
+			Expect(removed.ctx.Done()).To(BeClosed())
+			Expect(kept.ctx.Err()).NotTo(HaveOccurred())
+			Expect(ctrl.Unwatch(removed)).To(MatchError(ContainSubstring("is not watched")))
+		})
+
+		It("should return an error when removing a source that isn't watched", func() {
+			Expect(ctrl.Unwatch(&recordingSource{})).To(MatchError(ContainSubstring("is not watched")))
+		})
+
+		It("should call OnStartedWorkers once the workers are started and OnStoppedWorkers once they are finished", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var hooks []string
+			var hooksMu sync.Mutex
+			record := func(hook string) {
+				hooksMu.Lock()
+				defer hooksMu.Unlock()
+				hooks = append(hooks, hook)
+			}
+			getHooks := func() []string {
+				hooksMu.Lock()
+				defer hooksMu.Unlock()
+				return append([]string(nil), hooks...)
+			}
+
+			release := make(chan struct{})
+			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				record("reconcile")
+				<-release
+				return reconcile.Result{}, nil
+			})
+			ctrl.OnStartedWorkers = func(hookCtx context.Context) {
+				Expect(hookCtx).To(Equal(ctx))
+				record("started")
+			}
+			ctrl.OnStoppedWorkers = func(hookCtx context.Context) {
+				Expect(hookCtx.Err()).To(HaveOccurred())
+				record("stopped")
+			}
+
+			done := make(chan error)
+			go func() {
+				defer GinkgoRecover()
+				done <- ctrl.Start(ctx)
+			}()
+			Eventually(getHooks).Should(Equal([]string{"started"}))
+
+			queue.Add(request)
+			Eventually(getHooks).Should(Equal([]string{"started", "reconcile"}))
+
+			cancel()
+			Consistently(getHooks).Should(Equal([]string{"started", "reconcile"}))
+			close(release)
+			Eventually(done).Should(Receive(BeNil()))
+			Expect(getHooks()).To(Equal([]string{"started", "reconcile", "stopped"}))
+		})
+	})
+
+	Describe("Warmup", func() {
+		It("should not start the sources without NeedWarmup", func() {
+			src := &recordingSource{}
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+			Expect(ctrl.Warmup(context.Background())).To(Succeed())
+			Expect(src.ctx).To(BeNil())
+		})
+
+		It("should start the sources with NeedWarmup and queue their requests until the controller is started", func() {
+			ctrl.NeedWarmup = true
+			warmupCtx, warmupCancel := context.WithCancel(context.Background())
+			defer warmupCancel()
+
+			src := &recordingSource{}
+			Expect(ctrl.Watch(src, &handler.EnqueueRequestForObject{})).To(Succeed())
+			Expect(ctrl.Warmup(warmupCtx)).To(Succeed())
+			Expect(src.ctx).NotTo(BeNil())
+			startCtx := src.ctx
+
+			By("Queueing the requests of the warmed up sources")
+			Expect(ctrl.Queue).To(Equal(queue))
+			queue.Add(request)
+			Consistently(reconciled, "50ms").ShouldNot(Receive())
+
+			By("Reconciling them once started, without starting the sources again")
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Expect(src.ctx).To(Equal(startCtx))
+
+			By("Starting the sources of the watches added afterwards right away")
+			later := &recordingSource{}
+			Expect(ctrl.Watch(later, &handler.EnqueueRequestForObject{})).To(Succeed())
+			Expect(later.ctx).NotTo(BeNil())
+		})
 	})
 
 	Describe("Processing queue items from a Controller", func() {
@@ -474,6 +844,69 @@ var _ = Describe("controller", func() {
 			Eventually(func() int { return queue.NumRequeues(request) }).Should(Equal(0))
 		})
 
+		It("should reconcile the queued requests in batches with BatchDo", func() {
+			batches := make(chan []reconcile.Request, 2)
+			batchReconciler := &fakeBatchReconciler{batches: batches}
+			ctrl.Do = batchReconciler
+			ctrl.BatchDo = batchReconciler
+			ctrl.MaxBatchSize = 2
+
+			requests := []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "default", Name: "a"}},
+				{NamespacedName: types.NamespacedName{Namespace: "default", Name: "b"}},
+				{NamespacedName: types.NamespacedName{Namespace: "default", Name: "c"}},
+			}
+			for _, req := range requests {
+				queue.Add(req)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			Expect(<-batches).To(Equal(requests[:2]))
+			Expect(<-batches).To(Equal(requests[2:]))
+			Eventually(queue.Len).Should(Equal(0))
+		})
+
+		It("should acquire a slot with AcquireSlot before reconciling and release it afterwards", func() {
+			var acquired, released atomic.Int32
+			slotErr := errors.New("quota exhausted")
+			ctrl.AcquireSlot = func(context.Context) (func(), error) {
+				if acquired.Add(1) == 1 {
+					return nil, slotErr
+				}
+				return func() { released.Add(1) }, nil
+			}
+			ctrl.MakeQueue = func() workqueue.RateLimitingInterface {
+				return workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Millisecond))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			Eventually(func() workqueue.RateLimitingInterface {
+				ctrl.mu.Lock()
+				defer ctrl.mu.Unlock()
+				return ctrl.Queue
+			}).ShouldNot(BeNil())
+			ctrl.Queue.Add(request)
+
+			By("Requeueing the request without reconciling it if no slot is acquired")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Expect(acquired.Load()).To(BeEquivalentTo(2))
+
+			By("Releasing the slot once reconciled")
+			Eventually(released.Load).Should(BeEquivalentTo(1))
+		})
+
 		It("should continue to process additional queue items after the first", func() {
 			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
 				defer GinkgoRecover()
@@ -526,6 +959,52 @@ var _ = Describe("controller", func() {
 
 		// TODO(directxman12): we should ensure that backoff occurrs with error requeue
 
+		It("should drop a Request and call the DeadLetterHandler once it exceeds MaxRetries", func() {
+			queue := workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, time.Millisecond))
+			ctrl.MakeQueue = func() workqueue.RateLimitingInterface { return queue }
+			ctrl.MaxRetries = 2
+
+			var attempts int32
+			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				atomic.AddInt32(&attempts, 1)
+				return reconcile.Result{}, fmt.Errorf("expected error: reconcile")
+			})
+			type deadLetter struct {
+				req reconcile.Request
+				err error
+			}
+			deadLetters := make(chan deadLetter, 2)
+			ctrl.DeadLetterHandler = func(ctx context.Context, req reconcile.Request, err error) {
+				defer GinkgoRecover()
+				Expect(ReconcileIDFromContext(ctx)).NotTo(BeEmpty())
+				deadLetters <- deadLetter{req: req, err: err}
+			}
+
+			var m dto.Metric
+			Expect(ctrlmetrics.DeadLetters.WithLabelValues(ctrl.Name).Write(&m)).To(Succeed())
+			before := m.GetCounter().GetValue()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			queue.Add(request)
+
+			var dl deadLetter
+			Eventually(deadLetters).Should(Receive(&dl))
+			Expect(dl.req).To(Equal(request))
+			Expect(dl.err).To(MatchError("expected error: reconcile"))
+			Consistently(deadLetters, "50ms").ShouldNot(Receive())
+			Expect(atomic.LoadInt32(&attempts)).To(BeEquivalentTo(3))
+			Expect(queue.Len()).To(Equal(0))
+			Expect(queue.NumRequeues(request)).To(Equal(0))
+
+			Expect(ctrlmetrics.DeadLetters.WithLabelValues(ctrl.Name).Write(&m)).To(Succeed())
+			Expect(m.GetCounter().GetValue()).To(Equal(before + 1))
+		})
+
 		It("should not reset backoff until there's a non-error result", func() {
 			dq := &DelegatingQueue{RateLimitingInterface: ctrl.MakeQueue()}
 			ctrl.MakeQueue = func() workqueue.RateLimitingInterface { return dq }
@@ -651,45 +1130,416 @@ var _ = Describe("controller", func() {
 			Eventually(func() int { return dq.NumRequeues(request) }).Should(Equal(0))
 		})
 
-		PIt("should return if the queue is shutdown", func() {
-			// TODO(community): write this test
-		})
+		It("should not requeue a Request if the error is terminal, regardless of Requeue", func() {
+			dq := &DelegatingQueue{RateLimitingInterface: ctrl.MakeQueue()}
+			ctrl.MakeQueue = func() workqueue.RateLimitingInterface { return dq }
 
-		PIt("should wait for informers to be synced before processing items", func() {
-			// TODO(community): write this test
-		})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
 
-		PIt("should create a new go routine for MaxConcurrentReconciles", func() {
-			// TODO(community): write this test
-		})
+			dq.Add(request)
+			Expect(dq.getCounts()).To(Equal(countInfo{Trying: 1}))
 
-		Context("prometheus metric reconcile_total", func() {
-			var reconcileTotal dto.Metric
+			By("Invoking Reconciler which will ask for requeue with a terminal error")
+			fakeReconcile.AddResult(reconcile.Result{Requeue: true}, reconcile.TerminalError(fmt.Errorf("expected error: reconcile")))
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(dq.getCounts).Should(Equal(countInfo{Trying: 0}))
 
-			BeforeEach(func() {
-				ctrlmetrics.ReconcileTotal.Reset()
-				reconcileTotal.Reset()
-			})
+			By("Removing the item from the queue")
+			Eventually(dq.Len).Should(Equal(0))
+			Eventually(func() int { return dq.NumRequeues(request) }).Should(Equal(0))
+		})
 
-			It("should get updated on successful reconciliation", func() {
-				Expect(func() error {
-					Expect(ctrlmetrics.ReconcileTotal.WithLabelValues(ctrl.Name, "success").Write(&reconcileTotal)).To(Succeed())
-					if reconcileTotal.GetCounter().GetValue() != 0.0 {
-						return fmt.Errorf("metric reconcile total not reset")
-					}
-					return nil
-				}()).Should(Succeed())
+		It("should apply the RequeueJitter to RequeueAfter", func() {
+			pq := &fakePriorityQueue{RateLimitingInterface: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())}
+			ctrl.MakeQueue = func() workqueue.RateLimitingInterface { return pq }
+			ctrl.RequeueJitter = 0.2
+			ctrl.randFloat64 = func() float64 { return 1 }
 
-				ctx, cancel := context.WithCancel(context.Background())
-				defer cancel()
-				go func() {
-					defer GinkgoRecover()
-					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
-				}()
-				By("Invoking Reconciler which will succeed")
-				queue.Add(request)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
 
-				fakeReconcile.AddResult(reconcile.Result{}, nil)
+			pq.Add(request)
+			By("Invoking Reconciler which will ask for a requeueafter")
+			fakeReconcile.AddResult(reconcile.Result{RequeueAfter: 10 * time.Second}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(pq.getAdded).Should(Equal([]priorityqueue.AddOpts{{After: 12 * time.Second}}))
+		})
+
+		It("should not apply the RequeueJitter to Requeue", func() {
+			pq := &fakePriorityQueue{RateLimitingInterface: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())}
+			ctrl.MakeQueue = func() workqueue.RateLimitingInterface { return pq }
+			ctrl.RequeueJitter = 0.2
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			pq.Add(request)
+			By("Invoking Reconciler which will ask for requeue")
+			fakeReconcile.AddResult(reconcile.Result{Requeue: true}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(pq.getAdded).Should(Equal([]priorityqueue.AddOpts{{RateLimited: true}}))
+		})
+
+		It("should collapse the requests added by the sources within the DebounceWindow", func() {
+			ctrl.DebounceWindow = 200 * time.Millisecond
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			Eventually(func() bool {
+				ctrl.mu.Lock()
+				defer ctrl.mu.Unlock()
+				return ctrl.Started
+			}).Should(BeTrue())
+
+			By("Adding the request several times within the window")
+			q := ctrl.sourceQueue()
+			for i := 0; i < 3; i++ {
+				q.Add(request)
+				time.Sleep(20 * time.Millisecond)
+			}
+			Expect(queue.Len()).To(Equal(0))
+
+			By("Reconciling the request once")
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Eventually(reconciled).Should(Receive(Equal(request)))
+			Consistently(reconciled, "300ms").ShouldNot(Receive())
+		})
+
+		It("should delay the requests added by the sources to a priority queue by the DebounceWindow, keeping their priority", func() {
+			pq := &fakePriorityQueue{RateLimitingInterface: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())}
+			ctrl.Queue = pq
+			ctrl.DebounceWindow = time.Minute
+
+			q, ok := ctrl.sourceQueue().(priorityqueue.PriorityQueue)
+			Expect(ok).To(BeTrue())
+			q.AddWithOpts(priorityqueue.AddOpts{Priority: 5}, request)
+			q.AddWithOpts(priorityqueue.AddOpts{Priority: 5, After: time.Hour}, request)
+			Expect(pq.getAdded()).To(Equal([]priorityqueue.AddOpts{{Priority: 5, After: time.Minute}, {Priority: 5, After: time.Hour}}))
+		})
+
+		It("should hand the queue to the sources as is without a DebounceWindow", func() {
+			ctrl.Queue = queue
+			Expect(ctrl.sourceQueue()).To(BeIdenticalTo(queue))
+		})
+
+		It("should report the queue depth and the requests in flight with QueueStatus", func() {
+			Expect(ctrl.QueueStatus()).To(Equal(QueueStatus{}))
+
+			// The requeues are counted by the rate limiter of the queue.
+			queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			ctrl.MakeQueue = func() workqueue.RateLimitingInterface { return queue }
+			unblock := make(chan struct{})
+			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				<-unblock
+				return reconcile.Result{}, nil
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			queue.AddRateLimited(request)
+			Eventually(func() []InFlightRequest { return ctrl.QueueStatus().InFlight }).Should(HaveLen(1))
+			other := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}}
+			queue.Add(other)
+
+			status := ctrl.QueueStatus()
+			Expect(status.Depth).To(Equal(1))
+			Expect(status.InFlight[0].Request).To(Equal(request))
+			Expect(status.InFlight[0].Retries).To(Equal(1))
+			Expect(status.InFlight[0].Started).To(BeTemporally("<=", time.Now()))
+			Expect(status.OldestInFlightAge).To(BeNumerically(">", 0))
+
+			close(unblock)
+			Eventually(ctrl.QueueStatus).Should(Equal(QueueStatus{}))
+		})
+
+		It("should report the queue status while the sources are syncing", func() {
+			ctrl.CacheSyncTimeout = time.Hour
+			ctrl.startWatches = []watchDescription{{src: &delayedSyncingSource{delay: time.Hour}}}
+			queue.Add(request)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				_ = ctrl.Start(ctx)
+			}()
+
+			// QueueStatus is called in the background, so that the spec fails rather than hangs
+			// if it blocks.
+			Eventually(func() int {
+				status := make(chan QueueStatus, 1)
+				go func() {
+					status <- ctrl.QueueStatus()
+				}()
+				select {
+				case s := <-status:
+					return s.Depth
+				case <-time.After(100 * time.Millisecond):
+					return -1
+				}
+			}).Should(Equal(1))
+		})
+
+		It("should retain the queued requests while paused and reconcile them once resumed", func() {
+			ctrl.Pause()
+			Expect(ctrl.IsPaused()).To(BeTrue())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			queue.Add(request)
+
+			By("Not invoking the Reconciler while paused")
+			Consistently(reconciled, "100ms").ShouldNot(Receive())
+			Expect(queue.Len()).To(Equal(1))
+
+			By("Invoking the Reconciler once resumed")
+			ctrl.Resume()
+			Expect(ctrl.IsPaused()).To(BeFalse())
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect(<-reconciled).To(Equal(request))
+			Eventually(queue.Len).Should(Equal(0))
+
+			By("Holding the requests queued after pausing the controller again")
+			ctrl.Pause()
+			queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: "baz"}})
+			Consistently(reconciled, "100ms").ShouldNot(Receive())
+			ctrl.Resume()
+			fakeReconcile.AddResult(reconcile.Result{}, nil)
+			Expect((<-reconciled).Name).To(Equal("baz"))
+		})
+
+		It("should stop while paused", func() {
+			ctrl.Pause()
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() {
+				done <- ctrl.Start(ctx)
+			}()
+			queue.Add(request)
+			Consistently(reconciled, "50ms").ShouldNot(Receive())
+
+			cancel()
+			Eventually(done).Should(Receive(BeNil()))
+		})
+
+		Context("with a priority queue", func() {
+			var pq *fakePriorityQueue
+
+			BeforeEach(func() {
+				pq = &fakePriorityQueue{RateLimitingInterface: workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())}
+				ctrl.MakeQueue = func() workqueue.RateLimitingInterface { return pq }
+			})
+
+			priority := func(p int) *int { return &p }
+
+			DescribeTable("should requeue with the expected options",
+				func(result reconcile.Result, err error, expected []priorityqueue.AddOpts) {
+					ctx, cancel := context.WithCancel(context.Background())
+					defer cancel()
+					go func() {
+						defer GinkgoRecover()
+						Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+					}()
+
+					pq.AddWithOpts(priorityqueue.AddOpts{Priority: 5}, request)
+					fakeReconcile.AddResult(result, err)
+					Expect(<-reconciled).To(Equal(request))
+					Eventually(pq.getAdded).Should(Equal(append([]priorityqueue.AddOpts{{Priority: 5}}, expected...)))
+					Consistently(pq.getAdded, "50ms").Should(HaveLen(len(expected) + 1))
+				},
+				Entry("no requeue without Requeue or RequeueAfter, even with a Priority",
+					reconcile.Result{Priority: priority(10)}, nil, nil),
+				Entry("rate limited requeue at the dequeued priority for Requeue",
+					reconcile.Result{Requeue: true}, nil, []priorityqueue.AddOpts{{RateLimited: true, Priority: 5}}),
+				Entry("rate limited requeue at the result priority for Requeue with Priority",
+					reconcile.Result{Requeue: true, Priority: priority(-1)}, nil, []priorityqueue.AddOpts{{RateLimited: true, Priority: -1}}),
+				Entry("delayed requeue at the dequeued priority for RequeueAfter",
+					reconcile.Result{RequeueAfter: time.Hour}, nil, []priorityqueue.AddOpts{{After: time.Hour, Priority: 5}}),
+				Entry("delayed requeue at the result priority for RequeueAfter with Requeue and Priority",
+					reconcile.Result{RequeueAfter: time.Hour, Requeue: true, Priority: priority(10)}, nil, []priorityqueue.AddOpts{{After: time.Hour, Priority: 10}}),
+				Entry("rate limited requeue at the dequeued priority for an error, ignoring the result",
+					reconcile.Result{RequeueAfter: time.Hour, Priority: priority(10)}, fmt.Errorf("expected error: reconcile"), []priorityqueue.AddOpts{{RateLimited: true, Priority: 5}}),
+				Entry("no requeue for a terminal error, ignoring the result",
+					reconcile.Result{Requeue: true, Priority: priority(10)}, reconcile.TerminalError(fmt.Errorf("expected error: reconcile")), nil),
+			)
+
+			It("should keep the priorities of the requests of the sources of engaged clusters", func() {
+				ctrl.Queue = pq
+				q, ok := ctrl.clusterQueue(&engagedCluster{name: "a", generation: 1}, context.Background()).(priorityqueue.PriorityQueue)
+				Expect(ok).To(BeTrue())
+
+				q.AddWithOpts(priorityqueue.AddOpts{Priority: 5}, request)
+				Expect(pq.getAdded()).To(Equal([]priorityqueue.AddOpts{{Priority: 5}}))
+				item, priority, _ := pq.GetWithPriority()
+				Expect(item).To(Equal(clusterRequest{
+					Request:    reconcile.Request{NamespacedName: request.NamespacedName, ClusterName: "a"},
+					generation: 1,
+				}))
+				Expect(priority).To(Equal(5))
+			})
+		})
+
+		PIt("should return if the queue is shutdown", func() {
+			// TODO(community): write this test
+		})
+
+		PIt("should wait for informers to be synced before processing items", func() {
+			// TODO(community): write this test
+		})
+
+		PIt("should create a new go routine for MaxConcurrentReconciles", func() {
+			// TODO(community): write this test
+		})
+
+		It("should resize the workers with SetMaxConcurrentReconciles", func() {
+			var running int32
+			release := make(chan struct{})
+			ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				<-release
+				return reconcile.Result{}, nil
+			})
+			getRunning := func() int32 { return atomic.LoadInt32(&running) }
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			for i := 0; i < 4; i++ {
+				queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: fmt.Sprintf("bar%d", i)}})
+			}
+
+			By("Running a single reconciliation with a single worker")
+			Eventually(getRunning).Should(BeEquivalentTo(1))
+			Consistently(getRunning, "50ms").Should(BeEquivalentTo(1))
+
+			By("Running more reconciliations once the workers are added")
+			Expect(ctrl.SetMaxConcurrentReconciles(3)).To(Succeed())
+			Eventually(getRunning).Should(BeEquivalentTo(3))
+			Consistently(getRunning, "50ms").Should(BeEquivalentTo(3))
+
+			By("Running fewer reconciliations once the workers are removed")
+			Expect(ctrl.SetMaxConcurrentReconciles(1)).To(Succeed())
+			release <- struct{}{}
+			release <- struct{}{}
+			release <- struct{}{}
+			Eventually(getRunning).Should(BeEquivalentTo(1))
+			Consistently(getRunning, "50ms").Should(BeEquivalentTo(1))
+			close(release)
+			Eventually(queue.Len).Should(Equal(0))
+		})
+
+		It("should process the requests of a shard on its worker with ShardedWorkers", func() {
+			ctrl.ShardedWorkers = true
+			ctrl.MaxConcurrentReconciles = 2
+			req := func(name string) reconcile.Request {
+				return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "foo", Name: name}}
+			}
+
+			By("Finding a request in the shard of the blocking request and one in the other shard")
+			blocking := req("blocking")
+			var sameShard, otherShard reconcile.Request
+			for i := 0; sameShard.Name == "" || otherShard.Name == ""; i++ {
+				r := req(fmt.Sprintf("bar%d", i))
+				if shardOf(r, 2) == shardOf(blocking, 2) {
+					sameShard = r
+				} else {
+					otherShard = r
+				}
+			}
+
+			unblock := make(chan struct{})
+			processed := make(chan reconcile.Request, 3)
+			ctrl.Do = reconcile.Func(func(_ context.Context, r reconcile.Request) (reconcile.Result, error) {
+				if r == blocking {
+					<-unblock
+				}
+				processed <- r
+				return reconcile.Result{}, nil
+			})
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+			}()
+
+			queue.Add(blocking)
+			Eventually(queue.Len).Should(BeZero())
+			queue.Add(sameShard)
+			queue.Add(otherShard)
+
+			By("Processing the request of the other shard, but not the one queued behind the blocking request")
+			Eventually(processed).Should(Receive(Equal(otherShard)))
+			Consistently(processed, "100ms").ShouldNot(Receive())
+
+			close(unblock)
+			Eventually(processed).Should(Receive(Equal(blocking)))
+			Eventually(processed).Should(Receive(Equal(sameShard)))
+
+			By("Refusing to resize the sharded workers")
+			Expect(ctrl.SetMaxConcurrentReconciles(3)).To(MatchError(ContainSubstring("can't be resized")))
+		})
+
+		It("should reject a MaxConcurrentReconciles lower than 1", func() {
+			Expect(ctrl.SetMaxConcurrentReconciles(0)).To(MatchError(ContainSubstring("must be at least 1")))
+			Expect(ctrl.MaxConcurrentReconciles).To(Equal(1))
+		})
+
+		Context("prometheus metric reconcile_total", func() {
+			var reconcileTotal dto.Metric
+
+			BeforeEach(func() {
+				ctrlmetrics.ReconcileTotal.Reset()
+				reconcileTotal.Reset()
+			})
+
+			It("should get updated on successful reconciliation", func() {
+				Expect(func() error {
+					Expect(ctrlmetrics.ReconcileTotal.WithLabelValues(ctrl.Name, "success").Write(&reconcileTotal)).To(Succeed())
+					if reconcileTotal.GetCounter().GetValue() != 0.0 {
+						return fmt.Errorf("metric reconcile total not reset")
+					}
+					return nil
+				}()).Should(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				By("Invoking Reconciler which will succeed")
+				queue.Add(request)
+
+				fakeReconcile.AddResult(reconcile.Result{}, nil)
 				Expect(<-reconciled).To(Equal(request))
 				Eventually(func() error {
 					Expect(ctrlmetrics.ReconcileTotal.WithLabelValues(ctrl.Name, "success").Write(&reconcileTotal)).To(Succeed())
@@ -787,6 +1637,56 @@ var _ = Describe("controller", func() {
 					return nil
 				}, 2.0).Should(Succeed())
 			})
+			It("should get updated when reconcile returns a terminal error", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				By("Invoking Reconciler which will return a terminal error")
+				queue.Add(request)
+
+				fakeReconcile.AddResult(reconcile.Result{}, reconcile.TerminalError(fmt.Errorf("expected error: reconcile")))
+				Expect(<-reconciled).To(Equal(request))
+				Eventually(func() error {
+					Expect(ctrlmetrics.ReconcileTotal.WithLabelValues(ctrl.Name, "terminal_error").Write(&reconcileTotal)).To(Succeed())
+					if actual := reconcileTotal.GetCounter().GetValue(); actual != 1.0 {
+						return fmt.Errorf("metric reconcile total expected: %v and got: %v", 1.0, actual)
+					}
+					return nil
+				}, 2.0).Should(Succeed())
+			})
+
+			It("should get updated when reconcile panics and RecoverPanic is true", func() {
+				ctrl.RecoverPanic = true
+				ctrl.Do = reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					defer func() { reconciled <- request }()
+					panic("expected panic: reconcile")
+				})
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				By("Invoking Reconciler which will panic")
+				queue.Add(request)
+
+				Expect(<-reconciled).To(Equal(request))
+				Eventually(func() error {
+					Expect(ctrlmetrics.ReconcileTotal.WithLabelValues(ctrl.Name, "panic").Write(&reconcileTotal)).To(Succeed())
+					if actual := reconcileTotal.GetCounter().GetValue(); actual != 1.0 {
+						return fmt.Errorf("metric reconcile total expected: %v and got: %v", 1.0, actual)
+					}
+					Expect(ctrlmetrics.ReconcileTotal.WithLabelValues(ctrl.Name, "error").Write(&reconcileTotal)).To(Succeed())
+					if actual := reconcileTotal.GetCounter().GetValue(); actual != 0.0 {
+						return fmt.Errorf("metric reconcile total expected: %v and got: %v", 0.0, actual)
+					}
+					return nil
+				}, 2.0).Should(Succeed())
+			})
 		})
 
 		Context("should update prometheus metrics", func() {
@@ -829,6 +1729,109 @@ var _ = Describe("controller", func() {
 				Eventually(func() int { return queue.NumRequeues(request) }).Should(Equal(0))
 			})
 
+			It("should count terminal errors as errors, terminal errors and terminal_error results", func() {
+				var reconcileErrs, terminalErrs, reconcileTotal dto.Metric
+				ctrlmetrics.ReconcileErrors.Reset()
+				ctrlmetrics.TerminalReconcileErrors.Reset()
+				ctrlmetrics.ReconcileTotal.Reset()
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				queue.Add(request)
+
+				By("Invoking Reconciler which will give a terminal error")
+				fakeReconcile.AddResult(reconcile.Result{}, reconcile.TerminalError(fmt.Errorf("expected error: reconcile")))
+				Expect(<-reconciled).To(Equal(request))
+				Eventually(func() error {
+					Expect(ctrlmetrics.TerminalReconcileErrors.WithLabelValues(ctrl.Name).Write(&terminalErrs)).To(Succeed())
+					if terminalErrs.GetCounter().GetValue() != 1.0 {
+						return fmt.Errorf("terminal error metric not updated")
+					}
+					Expect(ctrlmetrics.ReconcileErrors.WithLabelValues(ctrl.Name).Write(&reconcileErrs)).To(Succeed())
+					if reconcileErrs.GetCounter().GetValue() != 1.0 {
+						return fmt.Errorf("error metric not updated")
+					}
+					Expect(ctrlmetrics.ReconcileTotal.WithLabelValues(ctrl.Name, labelTerminalError).Write(&reconcileTotal)).To(Succeed())
+					if reconcileTotal.GetCounter().GetValue() != 1.0 {
+						return fmt.Errorf("reconcile total metric not updated")
+					}
+					return nil
+				}, 2.0).Should(Succeed())
+
+				By("Removing the item from the queue")
+				Eventually(queue.Len).Should(Equal(0))
+				Eventually(func() int { return queue.NumRequeues(request) }).Should(Equal(0))
+			})
+
+			It("should cancel the context of reconciliations exceeding the ReconcileTimeout and count them", func() {
+				var timeouts dto.Metric
+				ctrlmetrics.ReconcileTimeouts.Reset()
+				ctrl.ReconcileTimeout = 50 * time.Millisecond
+				reconcileErrs := make(chan error, 2)
+				ctrl.Do = reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+					if req.Name == "slow" {
+						<-ctx.Done()
+						reconcileErrs <- ctx.Err()
+						return reconcile.Result{}, ctx.Err()
+					}
+					reconcileErrs <- ctx.Err()
+					return reconcile.Result{}, nil
+				})
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+
+				By("Invoking a Reconciler that completes within the timeout")
+				queue.Add(request)
+				Eventually(reconcileErrs).Should(Receive(BeNil()))
+
+				By("Invoking a Reconciler that exceeds the timeout")
+				queue.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "slow"}})
+				Eventually(reconcileErrs).Should(Receive(MatchError(context.DeadlineExceeded)))
+				Eventually(func() float64 {
+					Expect(ctrlmetrics.ReconcileTimeouts.WithLabelValues(ctrl.Name).Write(&timeouts)).To(Succeed())
+					return timeouts.GetCounter().GetValue()
+				}).Should(Equal(1.0))
+			})
+
+			It("should add the requested RequeueAfter to the requeue after histogram", func() {
+				var requeueAfter dto.Metric
+				ctrlmetrics.RequeueAfter.Reset()
+				ctrl.RequeueJitter = 0.5
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				queue.Add(request)
+
+				By("Invoking Reconciler which will ask for a requeueafter")
+				fakeReconcile.AddResult(reconcile.Result{RequeueAfter: 5 * time.Hour}, nil)
+				Expect(<-reconciled).To(Equal(request))
+
+				Eventually(func() error {
+					hist := ctrlmetrics.RequeueAfter.WithLabelValues(ctrl.Name).(prometheus.Histogram)
+					Expect(hist.Write(&requeueAfter)).To(Succeed())
+					if requeueAfter.GetHistogram().GetSampleCount() != uint64(1) {
+						return fmt.Errorf("metrics not updated")
+					}
+					if actual := requeueAfter.GetHistogram().GetSampleSum(); actual != (5 * time.Hour).Seconds() {
+						return fmt.Errorf("metric requeue after expected: %v and got: %v", (5 * time.Hour).Seconds(), actual)
+					}
+					return nil
+				}, 2.0).Should(Succeed())
+			})
+
 			It("should add a reconcile time to the reconcile time histogram", func() {
 				var reconcileTime dto.Metric
 				ctrlmetrics.ReconcileTime.Reset()
@@ -869,6 +1872,45 @@ var _ = Describe("controller", func() {
 					return nil
 				}, 2.0).Should(Succeed())
 			})
+
+			It("should record the ReconcileExemplar with the reconcile time", func() {
+				var reconcileTime dto.Metric
+				ctrlmetrics.ReconcileTime.Reset()
+
+				type traceIDKey struct{}
+				ctrl.ReconcileExemplar = func(ctx context.Context) (prometheus.Labels, bool) {
+					traceID, ok := ctx.Value(traceIDKey{}).(string)
+					return prometheus.Labels{"trace_id": traceID}, ok
+				}
+
+				ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceIDKey{}, "0af7651916cd43dd8448eb211c80319c"))
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(ctrl.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				queue.Add(request)
+
+				By("Invoking Reconciler")
+				fakeReconcile.AddResult(reconcile.Result{}, nil)
+				Expect(<-reconciled).To(Equal(request))
+
+				Eventually(func() error {
+					hist := ctrlmetrics.ReconcileTime.WithLabelValues(ctrl.Name).(prometheus.Histogram)
+					Expect(hist.Write(&reconcileTime)).To(Succeed())
+					for _, bucket := range reconcileTime.GetHistogram().GetBucket() {
+						exemplar := bucket.GetExemplar()
+						if exemplar == nil {
+							continue
+						}
+						Expect(exemplar.GetLabel()).To(HaveLen(1))
+						Expect(exemplar.GetLabel()[0].GetName()).To(Equal("trace_id"))
+						Expect(exemplar.GetLabel()[0].GetValue()).To(Equal("0af7651916cd43dd8448eb211c80319c"))
+						return nil
+					}
+					return fmt.Errorf("exemplar not recorded")
+				}, 2.0).Should(Succeed())
+			})
 		})
 	})
 })
@@ -914,6 +1956,48 @@ func (q *DelegatingQueue) Forget(item interface{}) {
 	q.RateLimitingInterface.Forget(item)
 }
 
+type fakePriorityQueue struct {
+	workqueue.RateLimitingInterface
+	mu sync.Mutex
+
+	priorities map[interface{}]int
+	added      []priorityqueue.AddOpts
+}
+
+var _ priorityqueue.PriorityQueue = &fakePriorityQueue{}
+
+func (q *fakePriorityQueue) AddWithOpts(o priorityqueue.AddOpts, items ...interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.priorities == nil {
+		q.priorities = map[interface{}]int{}
+	}
+	for _, item := range items {
+		q.added = append(q.added, o)
+		q.priorities[item] = o.Priority
+		// Delayed and rate limited items are only recorded, so that they are not reconciled again.
+		if o.After == 0 && !o.RateLimited {
+			q.RateLimitingInterface.Add(item)
+		}
+	}
+}
+
+func (q *fakePriorityQueue) GetWithPriority() (interface{}, int, bool) {
+	item, shutdown := q.RateLimitingInterface.Get()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return item, q.priorities[item], shutdown
+}
+
+func (q *fakePriorityQueue) getAdded() []priorityqueue.AddOpts {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]priorityqueue.AddOpts(nil), q.added...)
+}
+
 type countInfo struct {
 	Trying, AddAfter, AddRateLimited int
 }
@@ -963,6 +2047,26 @@ func (s *singnallingSourceWrapper) WaitForSync(ctx context.Context) error {
 	return s.SyncingSource.WaitForSync(ctx)
 }
 
+var _ source.SyncingSource = &delayedSyncingSource{}
+
+// delayedSyncingSource is a source that takes the given delay to sync.
+type delayedSyncingSource struct {
+	delay time.Duration
+}
+
+func (s *delayedSyncingSource) Start(context.Context, handler.EventHandler, workqueue.RateLimitingInterface, ...predicate.Predicate) error {
+	return nil
+}
+
+func (s *delayedSyncingSource) WaitForSync(ctx context.Context) error {
+	select {
+	case <-time.After(s.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 var _ cache.Cache = &cacheWithIndefinitelyBlockingGetInformer{}
 
 // cacheWithIndefinitelyBlockingGetInformer has a GetInformer implementation that blocks indefinitely or until its
@@ -979,3 +2083,26 @@ func (c *cacheWithIndefinitelyBlockingGetInformer) GetInformer(ctx context.Conte
 	<-ctx.Done()
 	return nil, errors.New("GetInformer timed out")
 }
+
+// recordingSource is a source that records the context it was started with.
+type recordingSource struct {
+	ctx context.Context
+}
+
+func (s *recordingSource) Start(ctx context.Context, _ handler.EventHandler, _ workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+	s.ctx = ctx
+	return nil
+}
+
+type fakeBatchReconciler struct {
+	batches chan []reconcile.Request
+}
+
+func (f *fakeBatchReconciler) Reconcile(context.Context, reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, errors.New("Reconcile must not be called for a batch reconciler")
+}
+
+func (f *fakeBatchReconciler) ReconcileBatch(_ context.Context, reqs []reconcile.Request) (reconcile.Result, error) {
+	f.batches <- reqs
+	return reconcile.Result{}, nil
+}
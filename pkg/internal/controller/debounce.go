@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+)
+
+// sourceQueue returns the queue the sources add requests to, which delays them by the
+// DebounceWindow, if set. The requeues of the controller are added to Queue directly.
+func (c *Controller[request]) sourceQueue() workqueue.RateLimitingInterface {
+	if c.DebounceWindow <= 0 {
+		return c.Queue
+	}
+	q := &debounceQueue{RateLimitingInterface: c.Queue, window: c.DebounceWindow}
+	if pq, ok := c.Queue.(priorityqueue.PriorityQueue); ok {
+		return &debouncePriorityQueue{debounceQueue: q, pq: pq}
+	}
+	return q
+}
+
+// debounceQueue delays the items that are added by a window. As the queue keeps the
+// earliest time an item is added after, the items added during the window of an item
+// collapse into it.
+type debounceQueue struct {
+	workqueue.RateLimitingInterface
+	window time.Duration
+}
+
+func (q *debounceQueue) Add(item interface{}) {
+	q.RateLimitingInterface.AddAfter(item, q.window)
+}
+
+func (q *debounceQueue) AddAfter(item interface{}, duration time.Duration) {
+	if duration < q.window {
+		duration = q.window
+	}
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+// debouncePriorityQueue is a debounceQueue that keeps the priorities of the items.
+type debouncePriorityQueue struct {
+	*debounceQueue
+	pq priorityqueue.PriorityQueue
+}
+
+var _ priorityqueue.PriorityQueue = &debouncePriorityQueue{}
+
+func (q *debouncePriorityQueue) AddWithOpts(o priorityqueue.AddOpts, items ...interface{}) {
+	if !o.RateLimited && o.After < q.window {
+		o.After = q.window
+	}
+	q.pq.AddWithOpts(o, items...)
+}
+
+func (q *debouncePriorityQueue) GetWithPriority() (interface{}, int, bool) {
+	return q.pq.GetWithPriority()
+}
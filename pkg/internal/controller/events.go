@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+const (
+	labelCreate  = "create"
+	labelUpdate  = "update"
+	labelDelete  = "delete"
+	labelGeneric = "generic"
+)
+
+// eventLogLevel is the verbosity at which the events handled by the event handlers are logged.
+const eventLogLevel = 5
+
+// instrumentedHandler is the event handler of a watch, which counts the events it handles
+// by their type, and logs them at eventLogLevel along with their object, which tells why
+// the requests they queue are reconciled.
+type instrumentedHandler struct {
+	handler handler.EventHandler
+	events  *prometheus.CounterVec
+	log     logr.Logger
+}
+
+var _ handler.EventHandler = &instrumentedHandler{}
+
+// instrumentHandler returns the handler of a watch, instrumented with the metrics and
+// logger of the controller.
+func (c *Controller[request]) instrumentHandler(h handler.EventHandler) handler.EventHandler {
+	return &instrumentedHandler{
+		handler: h,
+		events:  c.metrics().Events,
+		log:     c.LogConstructor(nil),
+	}
+}
+
+// Create implements handler.EventHandler.
+func (h *instrumentedHandler) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.observe(labelCreate, evt.Object)
+	h.handler.Create(evt, q)
+}
+
+// Update implements handler.EventHandler.
+func (h *instrumentedHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.observe(labelUpdate, evt.ObjectNew)
+	h.handler.Update(evt, q)
+}
+
+// Delete implements handler.EventHandler.
+func (h *instrumentedHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	h.observe(labelDelete, evt.Object)
+	h.handler.Delete(evt, q)
+}
+
+// Generic implements handler.EventHandler.
+func (h *instrumentedHandler) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.observe(labelGeneric, evt.Object)
+	h.handler.Generic(evt, q)
+}
+
+func (h *instrumentedHandler) observe(eventType string, obj client.Object) {
+	h.events.WithLabelValues(eventType).Inc()
+	if log := h.log.V(eventLogLevel); log.Enabled() && obj != nil {
+		log.Info("Handling event", "event", eventType, "objectType", fmt.Sprintf("%T", obj), "object", klog.KObj(obj))
+	}
+}
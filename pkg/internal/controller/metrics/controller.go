@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/workqueue"
+
+	internalmetrics "sigs.k8s.io/controller-runtime/pkg/internal/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// MaxExtraLabels is the maximum number of extra labels a controller can add to its metrics.
+const MaxExtraLabels = 5
+
+// reservedLabels are the label names used by the metrics of controllers and their workqueues.
+var reservedLabels = map[string]bool{
+	"controller": true,
+	"result":     true,
+	"event":      true,
+	"name":       true,
+	"le":         true,
+	"quantile":   true,
+}
+
+var labelNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ControllerMetrics holds the metrics of a single controller. Its metric vectors
+// are curried with the controller name and the extra labels of the controller.
+type ControllerMetrics struct {
+	// ReconcileTotal is left with the result label.
+	ReconcileTotal *prometheus.CounterVec
+
+	// Events is left with the event label.
+	Events *prometheus.CounterVec
+
+	// The following are left without labels.
+	ReconcileErrors         *prometheus.CounterVec
+	TerminalReconcileErrors *prometheus.CounterVec
+	ReconcileTimeouts       *prometheus.CounterVec
+	ReconcilePanics         *prometheus.CounterVec
+	DeadLetters             *prometheus.CounterVec
+	ReconcileTime           prometheus.ObserverVec
+	RequeueAfter            prometheus.ObserverVec
+	CacheSyncWait           *prometheus.GaugeVec
+	WorkerCount             *prometheus.GaugeVec
+	ActiveWorkers           *prometheus.GaugeVec
+	Paused                  *prometheus.GaugeVec
+
+	registry    prometheus.Registerer
+	name        string
+	extraLabels prometheus.Labels
+}
+
+// MakeQueue calls makeQueue, making the metrics of the workqueue it creates report into
+// the registry of the controller and have the extra labels of the controller.
+func (m *ControllerMetrics) MakeQueue(makeQueue func() workqueue.RateLimitingInterface) workqueue.RateLimitingInterface {
+	var queue workqueue.RateLimitingInterface
+	opts := internalmetrics.WorkqueueOptions{Registry: m.registry, ExtraLabels: m.extraLabels}
+	internalmetrics.WithWorkqueueOptions(m.name, opts, func() {
+		queue = makeQueue()
+	})
+	return queue
+}
+
+// ForController returns the metrics of the controller with the given name, which
+// report into the given registry and have the given extra labels in addition to
+// the controller label. The registry defaults to metrics.Registry.
+func ForController(reg prometheus.Registerer, name string, extraLabels map[string]string) (*ControllerMetrics, error) {
+	if err := ValidateExtraLabels(extraLabels); err != nil {
+		return nil, err
+	}
+	if reg == nil {
+		reg = metrics.Registry
+	}
+
+	labels := prometheus.Labels{}
+	for k, v := range extraLabels {
+		labels[k] = v
+	}
+	vecs := vecsByRegistry.For(reg).defaults
+	if len(labels) > 0 {
+		vecs = vecsByRegistry.For(reg).labelled.For(labels)
+	}
+
+	controllerLabel := prometheus.Labels{"controller": name}
+	return &ControllerMetrics{
+		registry:                reg,
+		name:                    name,
+		extraLabels:             labels,
+		ReconcileTotal:          vecs.ReconcileTotal.MustCurryWith(controllerLabel),
+		ReconcileErrors:         vecs.ReconcileErrors.MustCurryWith(controllerLabel),
+		TerminalReconcileErrors: vecs.TerminalReconcileErrors.MustCurryWith(controllerLabel),
+		ReconcileTimeouts:       vecs.ReconcileTimeouts.MustCurryWith(controllerLabel),
+		ReconcilePanics:         vecs.ReconcilePanics.MustCurryWith(controllerLabel),
+		DeadLetters:             vecs.DeadLetters.MustCurryWith(controllerLabel),
+		Events:                  vecs.Events.MustCurryWith(controllerLabel),
+		ReconcileTime:           vecs.ReconcileTime.MustCurryWith(controllerLabel),
+		RequeueAfter:            vecs.RequeueAfter.MustCurryWith(controllerLabel),
+		CacheSyncWait:           vecs.CacheSyncWait.MustCurryWith(controllerLabel),
+		WorkerCount:             vecs.WorkerCount.MustCurryWith(controllerLabel),
+		ActiveWorkers:           vecs.ActiveWorkers.MustCurryWith(controllerLabel),
+		Paused:                  vecs.Paused.MustCurryWith(controllerLabel),
+	}, nil
+}
+
+// ValidateExtraLabels validates the extra labels of the metrics of a controller. To keep
+// the cardinality of the metrics low, there can be at most MaxExtraLabels of them, and their
+// values must be valid Kubernetes label values. Their names must be valid Prometheus label
+// names that are not used by the metrics already.
+func ValidateExtraLabels(extraLabels map[string]string) error {
+	if len(extraLabels) > MaxExtraLabels {
+		return fmt.Errorf("at most %d extra metric labels are allowed, got %d", MaxExtraLabels, len(extraLabels))
+	}
+	for k, v := range extraLabels {
+		if !labelNameRegexp.MatchString(k) || strings.HasPrefix(k, "__") {
+			return fmt.Errorf("invalid metric label name %q", k)
+		}
+		if reservedLabels[k] {
+			return fmt.Errorf("metric label %q conflicts with a built-in label", k)
+		}
+		if v == "" {
+			return fmt.Errorf("value of metric label %q must not be empty", k)
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q of metric label %q: %s", v, k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
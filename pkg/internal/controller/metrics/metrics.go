@@ -19,6 +19,7 @@ package metrics
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
+	internalmetrics "sigs.k8s.io/controller-runtime/pkg/internal/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
@@ -26,50 +27,221 @@ var (
 	// ReconcileTotal is a prometheus counter metrics which holds the total
 	// number of reconciliations per controller. It has two labels. controller label refers
 	// to the controller name and result label refers to the reconcile result i.e
-	// success, error, requeue, requeue_after.
-	ReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
-		Name: "controller_runtime_reconcile_total",
-		Help: "Total number of reconciliations per controller",
-	}, []string{"controller", "result"})
+	// success, error, terminal_error, panic, requeue, requeue_after.
+	// The priority of requeues is deliberately not a label, as priorities are arbitrary
+	// integers chosen by reconcilers and would make the cardinality of this metric unbounded.
+	ReconcileTotal = defaultVecs.ReconcileTotal
 
 	// ReconcileErrors is a prometheus counter metrics which holds the total
 	// number of errors from the Reconciler.
-	ReconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
-		Name: "controller_runtime_reconcile_errors_total",
-		Help: "Total number of reconciliation errors per controller",
-	}, []string{"controller"})
+	ReconcileErrors = defaultVecs.ReconcileErrors
+
+	// TerminalReconcileErrors is a prometheus counter metrics which holds the total
+	// number of terminal errors from the Reconciler.
+	TerminalReconcileErrors = defaultVecs.TerminalReconcileErrors
+
+	// ReconcileTimeouts is a prometheus counter metrics which holds the total
+	// number of reconciliations aborted for exceeding the ReconcileTimeout of the controller.
+	ReconcileTimeouts = defaultVecs.ReconcileTimeouts
+
+	// ReconcilePanics is a prometheus counter metrics which holds the total
+	// number of panics from the Reconciler, whether they were recovered or not.
+	ReconcilePanics = defaultVecs.ReconcilePanics
+
+	// DeadLetters is a prometheus counter metrics which holds the total number of
+	// requests dropped after exceeding the MaxRetries of the controller.
+	DeadLetters = defaultVecs.DeadLetters
+
+	// Events is a prometheus counter metrics which holds the total number of events
+	// handled by the event handlers of the watches per controller. Its event label
+	// refers to the type of the event i.e. create, update, delete, generic.
+	Events = defaultVecs.Events
 
 	// ReconcileTime is a prometheus metric which keeps track of the duration
-	// of reconciliations.
-	ReconcileTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
-		Name: "controller_runtime_reconcile_time_seconds",
-		Help: "Length of time per reconciliation per controller",
-		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.15, 0.2, 0.25, 0.3, 0.35, 0.4, 0.45, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0,
-			1.25, 1.5, 1.75, 2.0, 2.5, 3.0, 3.5, 4.0, 4.5, 5, 6, 7, 8, 9, 10, 15, 20, 25, 30, 40, 50, 60},
-	}, []string{"controller"})
+	// of reconciliations. Its buckets can be configured with metrics.SetReconcileTimeBuckets.
+	ReconcileTime = defaultVecs.ReconcileTime
+
+	// RequeueAfter is a prometheus metric which keeps track of the
+	// Result.RequeueAfter durations returned by reconciliations.
+	RequeueAfter = defaultVecs.RequeueAfter
+
+	// CacheSyncWait is a prometheus metric which holds the time the controller
+	// spent waiting for the caches of its sources to sync when it was started.
+	CacheSyncWait = defaultVecs.CacheSyncWait
 
 	// WorkerCount is a prometheus metric which holds the number of
 	// concurrent reconciles per controller.
-	WorkerCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Name: "controller_runtime_max_concurrent_reconciles",
-		Help: "Maximum number of concurrent reconciles per controller",
-	}, []string{"controller"})
+	WorkerCount = defaultVecs.WorkerCount
 
 	// ActiveWorkers is a prometheus metric which holds the number
 	// of active workers per controller.
-	ActiveWorkers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Name: "controller_runtime_active_workers",
-		Help: "Number of currently used workers per controller",
-	}, []string{"controller"})
+	ActiveWorkers = defaultVecs.ActiveWorkers
+
+	// Paused is a prometheus metric which is 1 while the controller is paused
+	// and 0 otherwise.
+	Paused = defaultVecs.Paused
 )
 
+var (
+	// defaultVecs holds the metrics of controllers without extra labels.
+	defaultVecs = newControllerVecs(nil)
+
+	// labelledVecs holds the metrics of controllers with extra labels, by their labels.
+	labelledVecs = internalmetrics.NewLabelledCollectors(newControllerVecs)
+
+	// vecsByRegistry holds the metrics of controllers by the registry they report into.
+	vecsByRegistry = func() *internalmetrics.PerRegistry[*registryVecs] {
+		p := internalmetrics.NewPerRegistry(newRegistryVecs)
+		// The metrics of metrics.Registry are registered in init.
+		p.Set(metrics.Registry, &registryVecs{defaults: defaultVecs, labelled: labelledVecs})
+		return p
+	}()
+)
+
+// registryVecs are the metric vectors of the controllers that report into a registry.
+type registryVecs struct {
+	defaults *controllerVecs
+	labelled *internalmetrics.LabelledCollectors[*controllerVecs]
+}
+
+func newRegistryVecs(reg prometheus.Registerer) *registryVecs {
+	v := &registryVecs{
+		defaults: newControllerVecs(nil),
+		labelled: internalmetrics.NewLabelledCollectors(newControllerVecs),
+	}
+	reg.MustRegister(v.defaults.collectors()...)
+	reg.MustRegister(v.labelled)
+	return v
+}
+
+// controllerVecs are the metric vectors of controllers with the same extra labels,
+// which are set as constant labels.
+type controllerVecs struct {
+	ReconcileTotal          *prometheus.CounterVec
+	ReconcileErrors         *prometheus.CounterVec
+	TerminalReconcileErrors *prometheus.CounterVec
+	ReconcileTimeouts       *prometheus.CounterVec
+	ReconcilePanics         *prometheus.CounterVec
+	DeadLetters             *prometheus.CounterVec
+	Events                  *prometheus.CounterVec
+	ReconcileTime           *internalmetrics.HistogramVec
+	RequeueAfter            *internalmetrics.HistogramVec
+	CacheSyncWait           *prometheus.GaugeVec
+	WorkerCount             *prometheus.GaugeVec
+	ActiveWorkers           *prometheus.GaugeVec
+	Paused                  *prometheus.GaugeVec
+}
+
+func newControllerVecs(constLabels prometheus.Labels) *controllerVecs {
+	return &controllerVecs{
+		ReconcileTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "controller_runtime_reconcile_total",
+			Help:        "Total number of reconciliations per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller", "result"}),
+		ReconcileErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "controller_runtime_reconcile_errors_total",
+			Help:        "Total number of reconciliation errors per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		TerminalReconcileErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "controller_runtime_terminal_reconcile_errors_total",
+			Help:        "Total number of terminal reconciliation errors per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		ReconcileTimeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "controller_runtime_reconcile_timeouts_total",
+			Help:        "Total number of reconciliations aborted for exceeding the reconcile timeout per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		ReconcilePanics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "controller_runtime_reconcile_panics_total",
+			Help:        "Total number of reconciliation panics per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		DeadLetters: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "controller_runtime_reconcile_dead_letters_total",
+			Help:        "Total number of requests dropped after exceeding the maximum number of retries per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		Events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "controller_runtime_controller_events_total",
+			Help:        "Total number of events handled by the event handlers per controller and event type",
+			ConstLabels: constLabels,
+		}, []string{"controller", "event"}),
+		ReconcileTime: internalmetrics.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "controller_runtime_reconcile_time_seconds",
+			Help: "Length of time per reconciliation per controller",
+			Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.15, 0.2, 0.25, 0.3, 0.35, 0.4, 0.45, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0,
+				1.25, 1.5, 1.75, 2.0, 2.5, 3.0, 3.5, 4.0, 4.5, 5, 6, 7, 8, 9, 10, 15, 20, 25, 30, 40, 50, 60},
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		RequeueAfter: internalmetrics.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "controller_runtime_reconcile_requeue_after_seconds",
+			Help:        "Requested delay of requeues through RequeueAfter per controller",
+			Buckets:     []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600, 7200, 21600, 43200, 86400},
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		CacheSyncWait: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "controller_runtime_controller_cache_sync_wait_seconds",
+			Help:        "Time spent waiting for caches to sync on start per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		WorkerCount: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "controller_runtime_max_concurrent_reconciles",
+			Help:        "Maximum number of concurrent reconciles per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		ActiveWorkers: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "controller_runtime_active_workers",
+			Help:        "Number of currently used workers per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+		Paused: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "controller_runtime_controller_paused",
+			Help:        "Whether the controller is paused (1) or not (0) per controller",
+			ConstLabels: constLabels,
+		}, []string{"controller"}),
+	}
+}
+
+func (v *controllerVecs) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		v.ReconcileTotal,
+		v.ReconcileErrors,
+		v.TerminalReconcileErrors,
+		v.ReconcileTimeouts,
+		v.ReconcilePanics,
+		v.DeadLetters,
+		v.Events,
+		v.ReconcileTime,
+		v.RequeueAfter,
+		v.CacheSyncWait,
+		v.WorkerCount,
+		v.ActiveWorkers,
+		v.Paused,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (v *controllerVecs) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range v.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (v *controllerVecs) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range v.collectors() {
+		c.Collect(ch)
+	}
+}
+
 func init() {
+	metrics.Registry.MustRegister(defaultVecs.collectors()...)
 	metrics.Registry.MustRegister(
-		ReconcileTotal,
-		ReconcileErrors,
-		ReconcileTime,
-		WorkerCount,
-		ActiveWorkers,
+		// the metrics of controllers with extra labels, see ForController.
+		labelledVecs,
 		// expose process metrics like CPU, Memory, file descriptor usage etc.
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 		// expose Go runtime metrics like GC stats, memory stats etc.
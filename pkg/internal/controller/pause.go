@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import "context"
+
+// Pause implements controller.Controller. The workers stop taking requests off the
+// queue, and the reconciliations in flight complete.
+func (c *Controller[request]) Pause() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	if c.resumed != nil {
+		return
+	}
+	c.resumed = make(chan struct{})
+	c.metrics().Paused.WithLabelValues().Set(1)
+	c.LogConstructor(nil).Info("Paused controller")
+}
+
+// Resume implements controller.Controller.
+func (c *Controller[request]) Resume() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	if c.resumed == nil {
+		return
+	}
+	close(c.resumed)
+	c.resumed = nil
+	c.metrics().Paused.WithLabelValues().Set(0)
+	c.LogConstructor(nil).Info("Resumed controller")
+}
+
+// IsPaused implements controller.Controller.
+func (c *Controller[request]) IsPaused() bool {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	return c.resumed != nil
+}
+
+// waitWhilePaused blocks while the controller is paused, and returns false if the
+// context is done first.
+func (c *Controller[request]) waitWhilePaused(ctx context.Context) bool {
+	for {
+		c.pauseMu.Lock()
+		resumed := c.resumed
+		c.pauseMu.Unlock()
+		if resumed == nil {
+			return true
+		}
+
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
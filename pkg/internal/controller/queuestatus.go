@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"time"
+)
+
+// QueueStatus is a snapshot of the queue of a controller and of its requests being processed.
+type QueueStatus struct {
+	// Depth is the number of requests waiting in the queue.
+	Depth int
+
+	// InFlight are the requests being processed, the longest running first.
+	InFlight []InFlightRequest
+
+	// OldestInFlightAge is how long the longest running request has been processed for,
+	// or 0 if no request is being processed. A growing age hints at a stuck reconciliation.
+	OldestInFlightAge time.Duration
+}
+
+// InFlightRequest is a request being processed by a worker of a controller.
+type InFlightRequest struct {
+	// Request is the request, e.g. a reconcile.Request.
+	Request interface{}
+
+	// Started is when the worker took the request off the queue.
+	Started time.Time
+
+	// Retries is the number of times the request was requeued with rate limiting since it
+	// last succeeded.
+	Retries int
+}
+
+// QueueStatus implements controller.Controller.
+func (c *Controller[request]) QueueStatus() QueueStatus {
+	c.queueMu.RLock()
+	queue := c.Queue
+	c.queueMu.RUnlock()
+
+	var status QueueStatus
+	if queue == nil {
+		// The controller isn't started.
+		return status
+	}
+	status.Depth = queue.Len()
+
+	c.inFlightMu.Lock()
+	for item, started := range c.inFlight {
+		status.InFlight = append(status.InFlight, InFlightRequest{Request: item, Started: started})
+	}
+	c.inFlightMu.Unlock()
+
+	sort.Slice(status.InFlight, func(i, j int) bool {
+		return status.InFlight[i].Started.Before(status.InFlight[j].Started)
+	})
+	for i := range status.InFlight {
+		status.InFlight[i].Retries = queue.NumRequeues(status.InFlight[i].Request)
+		if cr, ok := status.InFlight[i].Request.(clusterRequest); ok {
+			status.InFlight[i].Request = cr.Request
+		}
+	}
+	if len(status.InFlight) > 0 {
+		status.OldestInFlightAge = time.Since(status.InFlight[0].Started)
+	}
+	return status
+}
+
+// trackInFlight records that the item is being processed, and returns a function that
+// records that it's done.
+func (c *Controller[request]) trackInFlight(item interface{}) func() {
+	c.inFlightMu.Lock()
+	defer c.inFlightMu.Unlock()
+	if c.inFlight == nil {
+		c.inFlight = map[interface{}]time.Time{}
+	}
+	c.inFlight[item] = time.Now()
+	return func() {
+		c.inFlightMu.Lock()
+		defer c.inFlightMu.Unlock()
+		delete(c.inFlight, item)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+// ReconcileIDAnnotation is the annotation that holds the ID of the reconciliation
+// that emitted an event, if the controller is configured to add it.
+const ReconcileIDAnnotation = "controller-runtime.sigs.k8s.io/reconcile-id"
+
+type eventRecorderKey struct{}
+
+type reconcileIDKey struct{}
+
+// EventRecorderIntoContext returns a copy of ctx that holds the event recorder.
+func EventRecorderIntoContext(ctx context.Context, recorder record.EventRecorder) context.Context {
+	return context.WithValue(ctx, eventRecorderKey{}, recorder)
+}
+
+// EventRecorderFromContext returns the event recorder held by ctx, or nil.
+func EventRecorderFromContext(ctx context.Context) record.EventRecorder {
+	recorder, _ := ctx.Value(eventRecorderKey{}).(record.EventRecorder)
+	return recorder
+}
+
+// ReconcileIDIntoContext returns a copy of ctx that holds the ID of a reconciliation.
+func ReconcileIDIntoContext(ctx context.Context, reconcileID types.UID) context.Context {
+	return context.WithValue(ctx, reconcileIDKey{}, reconcileID)
+}
+
+// ReconcileIDFromContext returns the ID of the reconciliation held by ctx, or "".
+func ReconcileIDFromContext(ctx context.Context) types.UID {
+	reconcileID, _ := ctx.Value(reconcileIDKey{}).(types.UID)
+	return reconcileID
+}
+
+// reconcileIDRecorder adds the ID of a reconciliation to the annotations of the events
+// it records.
+type reconcileIDRecorder struct {
+	record.EventRecorder
+	reconcileID types.UID
+}
+
+func (r *reconcileIDRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, "%s", message)
+}
+
+func (r *reconcileIDRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.AnnotatedEventf(object, nil, eventtype, reason, messageFmt, args...)
+}
+
+func (r *reconcileIDRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	withID := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		withID[k] = v
+	}
+	withID[ReconcileIDAnnotation] = string(r.reconcileID)
+	r.EventRecorder.AnnotatedEventf(object, withID, eventtype, reason, messageFmt, args...)
+}
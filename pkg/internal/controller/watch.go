@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// runningWatch is a watch whose source was started. Cancelling it stops the source.
+type runningWatch struct {
+	src    source.Source
+	cancel context.CancelFunc
+}
+
+// startWatch starts the source of a watch with a context that is cancelled when the
+// watch is removed with Unwatch. It must be called with the lock held.
+func (c *Controller[request]) startWatch(ctx context.Context, watch watchDescription) error {
+	ctx, cancel := context.WithCancel(ctx)
+	c.runningWatches = append(c.runningWatches, runningWatch{src: watch.src, cancel: cancel})
+	return watch.src.Start(ctx, watch.handler, c.sourceQueue(), watch.predicates...)
+}
+
+// Unwatch implements controller.Controller.
+func (c *Controller[request]) Unwatch(src source.Source) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var pending, cluster, running bool
+	c.startWatches, pending = removeWatches(c.startWatches, src)
+	c.clusterWatches, cluster = removeWatches(c.clusterWatches, src)
+	c.runningWatches, running = stopWatches(c.runningWatches, src)
+	for _, ec := range c.engagedClusters() {
+		ec.watches, _ = stopWatches(ec.watches, src)
+	}
+	if !pending && !cluster && !running {
+		return fmt.Errorf("source %v is not watched by controller %s", src, c.Name)
+	}
+	c.LogConstructor(nil).Info("Stopped EventSource", "source", src)
+	return nil
+}
+
+// removeWatches removes the watches of the source, and returns whether there were any.
+func removeWatches(watches []watchDescription, src source.Source) ([]watchDescription, bool) {
+	var found bool
+	kept := watches[:0]
+	for _, watch := range watches {
+		if sameSource(watch.src, src) {
+			found = true
+			continue
+		}
+		kept = append(kept, watch)
+	}
+	return kept, found
+}
+
+// stopWatches stops and removes the running watches of the source, and returns whether
+// there were any.
+func stopWatches(watches []runningWatch, src source.Source) ([]runningWatch, bool) {
+	var found bool
+	kept := watches[:0]
+	for _, watch := range watches {
+		if sameSource(watch.src, src) {
+			watch.cancel()
+			found = true
+			continue
+		}
+		kept = append(kept, watch)
+	}
+	return kept, found
+}
+
+// sameSource returns whether a and b are the same source. Sources of types that can't be
+// compared, such as source.Func, are never the same as any source.
+func sameSource(a, b source.Source) bool {
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
+	}
+	return a == b
+}
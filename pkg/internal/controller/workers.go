@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// startWorker starts a worker that processes requests until the context is done or the
+// worker is stopped. It must be called with the lock held.
+func (c *Controller[request]) startWorker(ctx context.Context) {
+	stop := make(chan struct{})
+	c.workers = append(c.workers, stop)
+	c.workersWG.Add(1)
+	go func() {
+		defer c.workersWG.Done()
+		// Run a worker thread that just dequeues items, processes them, and marks them done.
+		// It enforces that the reconcileHandler is never invoked concurrently with the same object.
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if !c.processNextWorkItem(ctx) {
+				return
+			}
+		}
+	}()
+}
+
+// SetMaxConcurrentReconciles implements controller.Controller.
+func (c *Controller[request]) SetMaxConcurrentReconciles(n int) error {
+	if n < 1 {
+		return fmt.Errorf("MaxConcurrentReconciles must be at least 1, got %d", n)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ShardedWorkers && c.Started {
+		return fmt.Errorf("the sharded workers of controller %s can't be resized once it's started", c.Name)
+	}
+	c.MaxConcurrentReconciles = n
+	// The workers are started with the controller, or not at all once it's stopping.
+	if !c.Started || c.ctx.Err() != nil {
+		return nil
+	}
+
+	for len(c.workers) < n {
+		c.startWorker(c.ctx)
+	}
+	for len(c.workers) > n {
+		last := len(c.workers) - 1
+		close(c.workers[last])
+		c.workers = c.workers[:last]
+	}
+	c.metrics().WorkerCount.WithLabelValues().Set(float64(n))
+	c.LogConstructor(nil).Info("Resized workers", "worker count", n)
+	return nil
+}
+
+// shardItem is an item taken from the queue of the controller, handed to a shard.
+type shardItem struct {
+	obj      interface{}
+	priority int
+}
+
+// startShardedWorkers starts a worker per shard, and a dispatcher that takes the items
+// off the queue and hands each of them to the shard of its key. As the queue doesn't
+// hand out an item until the processing of the previous one is done, the requests for
+// the same key are processed in order by the same worker. It must be called with the
+// lock held.
+func (c *Controller[request]) startShardedWorkers(ctx context.Context) {
+	shards := make([]workqueue.Interface, c.MaxConcurrentReconciles)
+	for i := range shards {
+		shard := workqueue.New()
+		shards[i] = shard
+		c.workersWG.Add(1)
+		go func() {
+			defer c.workersWG.Done()
+			for {
+				item, shutdown := shard.Get()
+				if shutdown {
+					return
+				}
+				it := item.(shardItem)
+				ok := c.processWorkItem(ctx, it.obj, it.priority)
+				shard.Done(item)
+				if !ok {
+					return
+				}
+			}
+		}()
+	}
+
+	c.workersWG.Add(1)
+	go func() {
+		defer c.workersWG.Done()
+		defer func() {
+			for _, shard := range shards {
+				shard.ShutDown()
+			}
+		}()
+		for c.waitWhilePaused(ctx) {
+			obj, priority, shutdown := c.getNextWorkItem()
+			if shutdown {
+				return
+			}
+			shards[shardOf(obj, len(shards))].Add(shardItem{obj: obj, priority: priority})
+		}
+	}()
+}
+
+// shardOf returns the shard of the given number of shards that the item belongs to.
+func shardOf(item interface{}, shards int) int {
+	h := fnv.New32a()
+	_, _ = fmt.Fprintf(h, "%v", item)
+	return int(h.Sum32() % uint32(shards))
+}
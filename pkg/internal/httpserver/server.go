@@ -1,6 +1,7 @@
 package httpserver
 
 import (
+	"crypto/tls"
 	"net/http"
 	"time"
 )
@@ -14,3 +15,21 @@ func New(handler http.Handler) *http.Server {
 		ReadHeaderTimeout: 32 * time.Second,
 	}
 }
+
+// DisableHTTP2 makes a TLS config only negotiate HTTP/1.1.
+func DisableHTTP2(cfg *tls.Config) {
+	cfg.NextProtos = []string{"http/1.1"}
+}
+
+// ConfigureHTTP2 disables HTTP/2 on a server serving a listener of the given TLS config
+// unless the config negotiates it, so that the server never speaks HTTP/2 on a
+// connection that wasn't negotiated as such.
+func ConfigureHTTP2(srv *http.Server, cfg *tls.Config) {
+	for _, proto := range cfg.NextProtos {
+		if proto == "h2" {
+			return
+		}
+	}
+	// A non-nil empty map disables the HTTP/2 support of the server.
+	srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// CacheMissFallbacks counts the gets of objects that the delegating clients did not find
+// in the cache and read from the API server instead, per result of the live get. It
+// lives here rather than in pkg/client, like SuppressedKubeAPIWarnings, and is
+// registered by pkg/metrics.
+var CacheMissFallbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "controller_runtime_client_cache_miss_fallbacks_total",
+	Help: "Total number of gets of objects not found in the cache that were read from the API server per group, version, kind and result",
+}, []string{"group", "version", "kind", "result"})
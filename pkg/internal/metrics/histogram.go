@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains helpers for the metrics registered by controller-runtime.
+package metrics
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	mu sync.Mutex
+
+	// buckets holds the bucket layouts configured through SetBuckets by metric name.
+	buckets = map[string][]float64{}
+
+	// histograms holds all HistogramVecs created through NewHistogramVec by metric name.
+	// There can be more than one per name if they have different constant labels.
+	histograms = map[string][]*HistogramVec{}
+)
+
+var _ prometheus.ObserverVec = &HistogramVec{}
+
+// HistogramVec is a prometheus.HistogramVec whose buckets can be changed through SetBuckets
+// until it is first used. Changing the buckets does not change the descriptor of the
+// HistogramVec, so it does not have to be registered again.
+type HistogramVec struct {
+	mu     sync.Mutex
+	opts   prometheus.HistogramOpts
+	labels []string
+	vec    *prometheus.HistogramVec
+	inUse  bool
+}
+
+// NewHistogramVec returns a new HistogramVec, using the buckets configured for its name
+// through SetBuckets, if any, and the buckets in opts otherwise.
+func NewHistogramVec(opts prometheus.HistogramOpts, labelNames []string) *HistogramVec {
+	mu.Lock()
+	defer mu.Unlock()
+
+	name := prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name)
+	if b, ok := buckets[name]; ok {
+		opts.Buckets = b
+	}
+	h := &HistogramVec{opts: opts, labels: labelNames, vec: prometheus.NewHistogramVec(opts, labelNames)}
+	histograms[name] = append(histograms[name], h)
+	return h
+}
+
+// SetBuckets configures the buckets of the histogram with the given fully-qualified name.
+// It returns an error if no histogram with that name was created through NewHistogramVec,
+// if the buckets are not strictly increasing, or if the histogram has already been used.
+func SetBuckets(name string, b []float64) error {
+	if len(b) == 0 {
+		return fmt.Errorf("buckets for %s must not be empty", name)
+	}
+	for i := 1; i < len(b); i++ {
+		if b[i] <= b[i-1] {
+			return fmt.Errorf("buckets for %s must be in strictly increasing order", name)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(histograms[name]) == 0 {
+		return fmt.Errorf("%s is not a histogram registered by controller-runtime", name)
+	}
+	for _, h := range histograms[name] {
+		if h.isInUse() {
+			return fmt.Errorf("buckets for %s can not be changed after it has been used", name)
+		}
+	}
+	for _, h := range histograms[name] {
+		h.setBuckets(b)
+	}
+	buckets[name] = b
+	return nil
+}
+
+func (h *HistogramVec) isInUse() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.inUse
+}
+
+func (h *HistogramVec) setBuckets(b []float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.opts.Buckets = b
+	h.vec = prometheus.NewHistogramVec(h.opts, h.labels)
+}
+
+// use marks h as in use and returns the underlying prometheus.HistogramVec.
+func (h *HistogramVec) use() *prometheus.HistogramVec {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.inUse = true
+	return h.vec
+}
+
+func (h *HistogramVec) current() *prometheus.HistogramVec {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.vec
+}
+
+// Describe implements prometheus.Collector.
+func (h *HistogramVec) Describe(ch chan<- *prometheus.Desc) {
+	h.current().Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (h *HistogramVec) Collect(ch chan<- prometheus.Metric) {
+	h.current().Collect(ch)
+}
+
+// Reset deletes all metrics of the HistogramVec.
+func (h *HistogramVec) Reset() {
+	h.current().Reset()
+}
+
+// GetMetricWith implements prometheus.ObserverVec.
+func (h *HistogramVec) GetMetricWith(labels prometheus.Labels) (prometheus.Observer, error) {
+	return h.use().GetMetricWith(labels)
+}
+
+// GetMetricWithLabelValues implements prometheus.ObserverVec.
+func (h *HistogramVec) GetMetricWithLabelValues(lvs ...string) (prometheus.Observer, error) {
+	return h.use().GetMetricWithLabelValues(lvs...)
+}
+
+// With implements prometheus.ObserverVec.
+func (h *HistogramVec) With(labels prometheus.Labels) prometheus.Observer {
+	return h.use().With(labels)
+}
+
+// WithLabelValues implements prometheus.ObserverVec.
+func (h *HistogramVec) WithLabelValues(lvs ...string) prometheus.Observer {
+	return h.use().WithLabelValues(lvs...)
+}
+
+// CurryWith implements prometheus.ObserverVec. Unlike the other methods, it does not mark
+// the HistogramVec as in use, only observing through the returned ObserverVec does.
+func (h *HistogramVec) CurryWith(labels prometheus.Labels) (prometheus.ObserverVec, error) {
+	if _, err := h.current().CurryWith(labels); err != nil {
+		return nil, err
+	}
+	return &curriedHistogramVec{parent: h, labels: labels}, nil
+}
+
+// MustCurryWith implements prometheus.ObserverVec.
+func (h *HistogramVec) MustCurryWith(labels prometheus.Labels) prometheus.ObserverVec {
+	vec, err := h.CurryWith(labels)
+	if err != nil {
+		panic(err)
+	}
+	return vec
+}
+
+// curriedHistogramVec is a HistogramVec curried with labels. It curries the current
+// prometheus.HistogramVec of its parent on every use, so that it follows bucket changes.
+type curriedHistogramVec struct {
+	parent *HistogramVec
+	labels prometheus.Labels
+}
+
+func (c *curriedHistogramVec) use() prometheus.ObserverVec {
+	return c.parent.use().MustCurryWith(c.labels)
+}
+
+// Describe implements prometheus.Collector.
+func (c *curriedHistogramVec) Describe(ch chan<- *prometheus.Desc) {
+	c.parent.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *curriedHistogramVec) Collect(ch chan<- prometheus.Metric) {
+	c.parent.Collect(ch)
+}
+
+// GetMetricWith implements prometheus.ObserverVec.
+func (c *curriedHistogramVec) GetMetricWith(labels prometheus.Labels) (prometheus.Observer, error) {
+	return c.use().GetMetricWith(labels)
+}
+
+// GetMetricWithLabelValues implements prometheus.ObserverVec.
+func (c *curriedHistogramVec) GetMetricWithLabelValues(lvs ...string) (prometheus.Observer, error) {
+	return c.use().GetMetricWithLabelValues(lvs...)
+}
+
+// With implements prometheus.ObserverVec.
+func (c *curriedHistogramVec) With(labels prometheus.Labels) prometheus.Observer {
+	return c.use().With(labels)
+}
+
+// WithLabelValues implements prometheus.ObserverVec.
+func (c *curriedHistogramVec) WithLabelValues(lvs ...string) prometheus.Observer {
+	return c.use().WithLabelValues(lvs...)
+}
+
+// CurryWith implements prometheus.ObserverVec.
+func (c *curriedHistogramVec) CurryWith(labels prometheus.Labels) (prometheus.ObserverVec, error) {
+	merged := prometheus.Labels{}
+	for k, v := range c.labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return c.parent.CurryWith(merged)
+}
+
+// MustCurryWith implements prometheus.ObserverVec.
+func (c *curriedHistogramVec) MustCurryWith(labels prometheus.Labels) prometheus.ObserverVec {
+	vec, err := c.CurryWith(labels)
+	if err != nil {
+		panic(err)
+	}
+	return vec
+}
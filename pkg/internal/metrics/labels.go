@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// workqueueCreationMu serializes WithWorkqueueOptions.
+	workqueueCreationMu sync.Mutex
+
+	workqueueOptionsMu sync.Mutex
+
+	// workqueueOptions holds the metric options of the workqueues that are being
+	// created in WithWorkqueueOptions, by queue name.
+	workqueueOptions = map[string]WorkqueueOptions{}
+)
+
+// WorkqueueOptions are the options for the metrics of a workqueue.
+type WorkqueueOptions struct {
+	// Registry is the registry the metrics of the workqueue report into.
+	Registry prometheus.Registerer
+
+	// ExtraLabels are added to the metrics of the workqueue.
+	ExtraLabels prometheus.Labels
+}
+
+// WithWorkqueueOptions calls newQueue, applying the given options to the metrics of
+// the workqueue with the given name it creates.
+func WithWorkqueueOptions(name string, opts WorkqueueOptions, newQueue func()) {
+	workqueueCreationMu.Lock()
+	defer workqueueCreationMu.Unlock()
+
+	setWorkqueueOptions(name, &opts)
+	defer setWorkqueueOptions(name, nil)
+	newQueue()
+}
+
+func setWorkqueueOptions(name string, opts *WorkqueueOptions) {
+	workqueueOptionsMu.Lock()
+	defer workqueueOptionsMu.Unlock()
+
+	if opts == nil {
+		delete(workqueueOptions, name)
+		return
+	}
+	workqueueOptions[name] = *opts
+}
+
+// WorkqueueOptionsFor returns the options of the metrics of the workqueue with the given
+// name, if it is being created in WithWorkqueueOptions.
+func WorkqueueOptionsFor(name string) (WorkqueueOptions, bool) {
+	workqueueOptionsMu.Lock()
+	defer workqueueOptionsMu.Unlock()
+
+	opts, ok := workqueueOptions[name]
+	return opts, ok
+}
+
+// LabelledCollectors creates and holds a collector per set of constant labels.
+//
+// It does not describe the metrics of its collectors, so it is registered as an unchecked
+// collector. This allows their metrics to be exposed next to metrics of the same name that
+// don't have these constant labels, which the registry would reject for checked collectors.
+type LabelledCollectors[T prometheus.Collector] struct {
+	mu         sync.Mutex
+	newFunc    func(constLabels prometheus.Labels) T
+	collectors map[string]T
+}
+
+// NewLabelledCollectors returns a new LabelledCollectors that creates collectors with newFunc.
+func NewLabelledCollectors[T prometheus.Collector](newFunc func(constLabels prometheus.Labels) T) *LabelledCollectors[T] {
+	return &LabelledCollectors[T]{newFunc: newFunc, collectors: map[string]T{}}
+}
+
+// For returns the collector for the given constant labels, creating it if necessary.
+func (l *LabelledCollectors[T]) For(constLabels prometheus.Labels) T {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := labelsKey(constLabels)
+	c, ok := l.collectors[key]
+	if !ok {
+		c = l.newFunc(constLabels)
+		l.collectors[key] = c
+	}
+	return c
+}
+
+// Describe implements prometheus.Collector. It describes nothing, see LabelledCollectors.
+func (l *LabelledCollectors[T]) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (l *LabelledCollectors[T]) Collect(ch chan<- prometheus.Metric) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, c := range l.collectors {
+		c.Collect(ch)
+	}
+}
+
+func labelsKey(labels prometheus.Labels) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
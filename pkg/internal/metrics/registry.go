@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PerRegistry holds a value per registry, typically the metrics that report into it.
+// Registries are compared by identity, so they must be comparable, e.g. pointers.
+type PerRegistry[T any] struct {
+	mu      sync.Mutex
+	newFunc func(reg prometheus.Registerer) T
+	values  map[prometheus.Registerer]T
+}
+
+// NewPerRegistry returns a new PerRegistry that creates the value of a registry with newFunc,
+// which is also responsible for registering any metrics of the value with the registry.
+func NewPerRegistry[T any](newFunc func(reg prometheus.Registerer) T) *PerRegistry[T] {
+	return &PerRegistry[T]{newFunc: newFunc, values: map[prometheus.Registerer]T{}}
+}
+
+// For returns the value of the given registry, creating it if necessary.
+func (p *PerRegistry[T]) For(reg prometheus.Registerer) T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	v, ok := p.values[reg]
+	if !ok {
+		v = p.newFunc(reg)
+		p.values[reg] = v
+	}
+	return v
+}
+
+// Set sets the value of the given registry, e.g. for metrics that have been
+// registered with it when initializing a package.
+func (p *PerRegistry[T]) Set(reg prometheus.Registerer, v T) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.values[reg] = v
+}
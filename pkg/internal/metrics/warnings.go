@@ -0,0 +1,27 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// SuppressedKubeAPIWarnings counts the warnings of the API server that the warning
+// loggers did not log because they had been logged already. It lives here rather than
+// in pkg/log, which can't import pkg/metrics, and is registered by pkg/metrics.
+var SuppressedKubeAPIWarnings = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "controller_runtime_kube_api_warnings_suppressed_total",
+	Help: "Total number of warnings of the API server that were not logged because they were logged already",
+})
@@ -23,10 +23,15 @@ import (
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	eventsv1client "k8s.io/client-go/kubernetes/typed/events/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/recorder"
 )
 
 // EventBroadcasterProducer makes an event broadcaster, returning
@@ -43,13 +48,20 @@ type Provider struct {
 	// scheme to specify when creating a recorder
 	scheme *runtime.Scheme
 	// logger is the logger to use when logging diagnostic event info
-	logger          logr.Logger
-	evtClient       corev1client.EventInterface
+	logger logr.Logger
+	sink   record.EventSink
+	// additionalSinks are the sinks core/v1 events are written to on top of sink.
+	additionalSinks []record.EventSink
 	makeBroadcaster EventBroadcasterProducer
-
-	broadcasterOnce sync.Once
-	broadcaster     record.EventBroadcaster
-	stopBroadcaster bool
+	// eventsSink is set if events.k8s.io/v1 Events are recorded, in which case
+	// makeBroadcaster isn't used.
+	eventsSink events.EventSink
+
+	broadcasterOnce   sync.Once
+	broadcaster       record.EventBroadcaster
+	eventsBroadcaster events.EventBroadcaster
+	stopEventsCh      chan struct{}
+	stopBroadcaster   bool
 }
 
 // NB(directxman12): this manually implements Stop instead of Being a runnable because we need to
@@ -70,10 +82,15 @@ func (p *Provider) Stop(shutdownCtx context.Context) {
 		// almost certainly already been started (e.g. by leader election).  We
 		// need to invoke this to ensure that we don't inadvertently race with
 		// an invocation of getBroadcaster.
-		broadcaster := p.getBroadcaster()
+		p.getBroadcaster()
 		if p.stopBroadcaster {
 			p.lock.Lock()
-			broadcaster.Shutdown()
+			if p.eventsBroadcaster != nil {
+				close(p.stopEventsCh)
+				p.eventsBroadcaster.Shutdown()
+			} else {
+				p.broadcaster.Shutdown()
+			}
 			p.stopped = true
 			p.lock.Unlock()
 		}
@@ -87,8 +104,8 @@ func (p *Provider) Stop(shutdownCtx context.Context) {
 }
 
 // getBroadcaster ensures that a broadcaster is started for this
-// provider, and returns it.  It's threadsafe.
-func (p *Provider) getBroadcaster() record.EventBroadcaster {
+// provider.  It's threadsafe.
+func (p *Provider) getBroadcaster() {
 	// NB(directxman12): this can technically still leak if something calls
 	// "getBroadcaster" (i.e. Emits an Event) but never calls Start, but if we
 	// create the broadcaster in start, we could race with other things that
@@ -96,8 +113,26 @@ func (p *Provider) getBroadcaster() record.EventBroadcaster {
 	// silently swallowing events and more locking, but that seems suboptimal.
 
 	p.broadcasterOnce.Do(func() {
+		if p.eventsSink != nil {
+			broadcaster := events.NewBroadcaster(p.eventsSink)
+			p.stopEventsCh = make(chan struct{})
+			broadcaster.StartRecordingToSink(p.stopEventsCh)
+			broadcaster.StartEventWatcher(
+				func(obj runtime.Object) {
+					if e, ok := obj.(*eventsv1.Event); ok {
+						p.logger.V(1).Info(e.Note, "type", e.Type, "object", e.Regarding, "reason", e.Reason, "action", e.Action)
+					}
+				})
+			p.eventsBroadcaster = broadcaster
+			p.stopBroadcaster = true
+			return
+		}
+
 		broadcaster, stop := p.makeBroadcaster()
-		broadcaster.StartRecordingToSink(&corev1client.EventSinkImpl{Interface: p.evtClient})
+		broadcaster.StartRecordingToSink(p.sink)
+		for _, sink := range p.additionalSinks {
+			broadcaster.StartRecordingToSink(sink)
+		}
 		broadcaster.StartEventWatcher(
 			func(e *corev1.Event) {
 				p.logger.V(1).Info(e.Message, "type", e.Type, "object", e.InvolvedObject, "reason", e.Reason)
@@ -105,18 +140,35 @@ func (p *Provider) getBroadcaster() record.EventBroadcaster {
 		p.broadcaster = broadcaster
 		p.stopBroadcaster = stop
 	})
-
-	return p.broadcaster
 }
 
-// NewProvider create a new Provider instance.
-func NewProvider(config *rest.Config, scheme *runtime.Scheme, logger logr.Logger, makeBroadcaster EventBroadcasterProducer) (*Provider, error) {
-	corev1Client, err := corev1client.NewForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to init client: %w", err)
+// NewProvider create a new Provider instance. The correlator options are applied by
+// makeBroadcaster, as the broadcaster may be shared.
+func NewProvider(config *rest.Config, scheme *runtime.Scheme, logger logr.Logger, makeBroadcaster EventBroadcasterProducer, options recorder.BroadcasterOptions) (*Provider, error) {
+	p := &Provider{scheme: scheme, logger: logger, makeBroadcaster: makeBroadcaster, sink: options.Sink, additionalSinks: options.AdditionalSinks}
+
+	if options.UseEventsAPI {
+		p.eventsSink = options.EventsSink
+		if p.eventsSink == nil {
+			eventsV1Client, err := eventsv1client.NewForConfig(config)
+			if err != nil {
+				return nil, fmt.Errorf("failed to init client: %w", err)
+			}
+			p.eventsSink = &events.EventSinkImpl{Interface: eventsV1Client}
+		}
+		return p, nil
 	}
 
-	p := &Provider{scheme: scheme, logger: logger, makeBroadcaster: makeBroadcaster, evtClient: corev1Client.Events("")}
+	if p.sink == nil {
+		corev1Client, err := corev1client.NewForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init client: %w", err)
+		}
+		p.sink = &corev1client.EventSinkImpl{Interface: corev1Client.Events("")}
+	}
+	if options.WrapSink != nil {
+		p.sink = options.WrapSink(p.sink)
+	}
 	return p, nil
 }
 
@@ -142,8 +194,12 @@ type lazyRecorder struct {
 // ensureRecording ensures that a concrete recorder is populated for this recorder.
 func (l *lazyRecorder) ensureRecording() {
 	l.recOnce.Do(func() {
-		broadcaster := l.prov.getBroadcaster()
-		l.rec = broadcaster.NewRecorder(l.prov.scheme, corev1.EventSource{Component: l.name})
+		l.prov.getBroadcaster()
+		if l.prov.eventsBroadcaster != nil {
+			l.rec = &eventsRecorder{rec: l.prov.eventsBroadcaster.NewRecorder(l.prov.scheme, l.name)}
+			return
+		}
+		l.rec = l.prov.broadcaster.NewRecorder(l.prov.scheme, corev1.EventSource{Component: l.name})
 	})
 }
 
@@ -174,3 +230,20 @@ func (l *lazyRecorder) AnnotatedEventf(object runtime.Object, annotations map[st
 	}
 	l.prov.lock.RUnlock()
 }
+
+// eventsRecorder records events.k8s.io/v1 Events through the core/v1 recorder
+// interface. The reason of the events is used as their action, and annotations are
+// dropped as the events.k8s.io recorder doesn't support them.
+type eventsRecorder struct {
+	rec events.EventRecorder
+}
+
+func (r *eventsRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.rec.Eventf(object, nil, eventtype, reason, reason, "%s", message)
+}
+func (r *eventsRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.rec.Eventf(object, nil, eventtype, reason, reason, messageFmt, args...)
+}
+func (r *eventsRecorder) AnnotatedEventf(object runtime.Object, _ map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.rec.Eventf(object, nil, eventtype, reason, reason, messageFmt, args...)
+}
@@ -17,19 +17,76 @@ limitations under the License.
 package recorder_test
 
 import (
+	"context"
+	"sync"
+
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/internal/recorder"
+	pubrecorder "sigs.k8s.io/controller-runtime/pkg/recorder"
 )
 
+// fakeSink captures the events written to it.
+type fakeSink struct {
+	mu     sync.Mutex
+	events []runtime.Object
+}
+
+func (s *fakeSink) record(event runtime.Object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+}
+
+func (s *fakeSink) recorded() []runtime.Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]runtime.Object(nil), s.events...)
+}
+
+func (s *fakeSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	s.record(event)
+	return event, nil
+}
+func (s *fakeSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	s.record(event)
+	return event, nil
+}
+func (s *fakeSink) Patch(event *corev1.Event, _ []byte) (*corev1.Event, error) {
+	s.record(event)
+	return event, nil
+}
+
+// fakeEventsSink captures the events.k8s.io events written to it.
+type fakeEventsSink struct {
+	fakeSink
+}
+
+func (s *fakeEventsSink) Create(event *eventsv1.Event) (*eventsv1.Event, error) {
+	s.record(event)
+	return event, nil
+}
+func (s *fakeEventsSink) Update(event *eventsv1.Event) (*eventsv1.Event, error) {
+	s.record(event)
+	return event, nil
+}
+func (s *fakeEventsSink) Patch(event *eventsv1.Event, _ []byte) (*eventsv1.Event, error) {
+	s.record(event)
+	return event, nil
+}
+
 var _ = Describe("recorder.Provider", func() {
 	makeBroadcaster := func() (record.EventBroadcaster, bool) { return record.NewBroadcaster(), true }
 	Describe("NewProvider", func() {
 		It("should return a provider instance and a nil error.", func() {
-			provider, err := recorder.NewProvider(cfg, scheme.Scheme, logr.Discard(), makeBroadcaster)
+			provider, err := recorder.NewProvider(cfg, scheme.Scheme, logr.Discard(), makeBroadcaster, pubrecorder.BroadcasterOptions{})
 			Expect(provider).NotTo(BeNil())
 			Expect(err).NotTo(HaveOccurred())
 		})
@@ -38,18 +95,115 @@ var _ = Describe("recorder.Provider", func() {
 			// Invalid the config
 			cfg1 := *cfg
 			cfg1.Host = "invalid host"
-			_, err := recorder.NewProvider(&cfg1, scheme.Scheme, logr.Discard(), makeBroadcaster)
+			_, err := recorder.NewProvider(&cfg1, scheme.Scheme, logr.Discard(), makeBroadcaster, pubrecorder.BroadcasterOptions{})
 			Expect(err).NotTo(BeNil())
 			Expect(err.Error()).To(ContainSubstring("failed to init client"))
 		})
 	})
 	Describe("GetEventRecorder", func() {
 		It("should return a recorder instance.", func() {
-			provider, err := recorder.NewProvider(cfg, scheme.Scheme, logr.Discard(), makeBroadcaster)
+			provider, err := recorder.NewProvider(cfg, scheme.Scheme, logr.Discard(), makeBroadcaster, pubrecorder.BroadcasterOptions{})
 			Expect(err).NotTo(HaveOccurred())
 
 			recorder := provider.GetEventRecorderFor("test")
 			Expect(recorder).NotTo(BeNil())
 		})
 	})
+
+	Describe("BroadcasterOptions", func() {
+		obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+
+		It("should write the events to the sink and rate limit them with the correlator options", func() {
+			sink := &fakeSink{}
+			opts := pubrecorder.BroadcasterOptions{
+				CorrelatorOptions: record.CorrelatorOptions{BurstSize: 2, QPS: 0.001},
+				Sink:              sink,
+			}
+			makeBroadcaster := func() (record.EventBroadcaster, bool) {
+				return record.NewBroadcasterWithCorrelatorOptions(opts.CorrelatorOptions), true
+			}
+			provider, err := recorder.NewProvider(cfg, scheme.Scheme, logr.Discard(), makeBroadcaster, opts)
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Stop(context.Background())
+
+			rec := provider.GetEventRecorderFor("test")
+			for _, reason := range []string{"First", "Second", "Third", "Fourth"} {
+				rec.Event(obj, corev1.EventTypeNormal, reason, "test-msg")
+			}
+			Eventually(sink.recorded).Should(HaveLen(2))
+			Consistently(sink.recorded, "200ms").Should(HaveLen(2))
+			Expect(sink.recorded()[0].(*corev1.Event).Source.Component).To(Equal("test"))
+		})
+
+		It("should write the events to the additional sinks", func() {
+			sink := &fakeSink{}
+			memory := &pubrecorder.MemorySink{}
+			provider, err := recorder.NewProvider(cfg, scheme.Scheme, logr.Discard(), makeBroadcaster, pubrecorder.BroadcasterOptions{
+				Sink:            sink,
+				AdditionalSinks: []record.EventSink{memory, pubrecorder.NewLogSink(logr.Discard())},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Stop(context.Background())
+
+			rec := provider.GetEventRecorderFor("test")
+			rec.Event(obj, corev1.EventTypeNormal, "Created", "test-msg")
+			Eventually(sink.recorded).Should(HaveLen(1))
+			Eventually(memory.Events).Should(HaveLen(1))
+			Expect(memory.Events()[0].Reason).To(Equal("Created"))
+
+			By("aggregating the same event")
+			rec.Event(obj, corev1.EventTypeNormal, "Created", "test-msg")
+			Eventually(func() int32 {
+				events := memory.Events()
+				return events[len(events)-1].Count
+			}).Should(BeEquivalentTo(2))
+			Expect(memory.Events()).To(HaveLen(1))
+
+			memory.Reset()
+			Expect(memory.Events()).To(BeEmpty())
+		})
+
+		It("should rate limit the events of each object with a rate limited sink", func() {
+			sink := &fakeSink{}
+			provider, err := recorder.NewProvider(cfg, scheme.Scheme, logr.Discard(), makeBroadcaster, pubrecorder.BroadcasterOptions{
+				Sink: sink,
+				WrapSink: func(sink record.EventSink) record.EventSink {
+					return pubrecorder.NewRateLimitedSink(sink, 0.001, 1)
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			defer provider.Stop(context.Background())
+
+			other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}
+			rec := provider.GetEventRecorderFor("test")
+			rec.Event(obj, corev1.EventTypeNormal, "First", "test-msg")
+			rec.Event(obj, corev1.EventTypeNormal, "Second", "test-msg")
+			rec.Event(other, corev1.EventTypeNormal, "First", "test-msg")
+			Eventually(sink.recorded).Should(HaveLen(2))
+			Consistently(sink.recorded, "200ms").Should(HaveLen(2))
+		})
+
+		It("should record events.k8s.io events with UseEventsAPI", func() {
+			sink := &fakeEventsSink{}
+			provider, err := recorder.NewProvider(cfg, scheme.Scheme, logr.Discard(), makeBroadcaster,
+				pubrecorder.BroadcasterOptions{UseEventsAPI: true, EventsSink: sink})
+			Expect(err).NotTo(HaveOccurred())
+
+			rec := provider.GetEventRecorderFor("test")
+			rec.Eventf(obj, corev1.EventTypeWarning, "Failed", "failed %d times", 3)
+			Eventually(sink.recorded).Should(HaveLen(1))
+			event := sink.recorded()[0].(*eventsv1.Event)
+			Expect(event.Type).To(Equal(corev1.EventTypeWarning))
+			Expect(event.Reason).To(Equal("Failed"))
+			Expect(event.Action).To(Equal("Failed"))
+			Expect(event.Note).To(Equal("failed 3 times"))
+			Expect(event.ReportingController).To(Equal("test"))
+			Expect(event.Regarding.Name).To(Equal("test"))
+
+			By("stopping the provider")
+			provider.Stop(context.Background())
+			rec.Event(obj, corev1.EventTypeNormal, "Stopped", "test-msg")
+			Consistently(sink.recorded, "200ms").Should(HaveLen(1))
+		})
+	})
 })
@@ -22,3 +22,9 @@ only one active set of controllers, for active-passive HA.
 It uses built-in Kubernetes leader election APIs.
 */
 package leaderelection
+
+import (
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+)
+
+var log = logf.RuntimeLog.WithName("leader-election")
@@ -34,11 +34,14 @@ import (
 // leader election.
 func NewResourceLock(config *rest.Config, recorderProvider recorder.Provider, options leaderelection.Options) (resourcelock.Interface, error) {
 	// Leader id, needs to be unique
-	id, err := os.Hostname()
-	if err != nil {
-		return nil, err
+	id := options.LeaderElectionIdentity
+	if id == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		id = hostname + "_" + string(uuid.NewUUID())
 	}
-	id = id + "_" + string(uuid.NewUUID())
 
 	return &ResourceLock{
 		id: id,
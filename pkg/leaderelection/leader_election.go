@@ -17,6 +17,7 @@ limitations under the License.
 package leaderelection
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -49,6 +50,96 @@ type Options struct {
 	// LeaderElectionID determines the name of the resource that leader election
 	// will use for holding the leader lock.
 	LeaderElectionID string
+
+	// LeaderElectionLocks configures a primary and a secondary lock that are used instead
+	// of the lock configured by LeaderElectionResourceLock, LeaderElectionNamespace and
+	// LeaderElectionID, which only default their empty fields. See LockConfig.
+	LeaderElectionLocks []LockConfig
+
+	// LeaderElectionIdentity is the identity of this candidate in the lock, which must be
+	// unique among all candidates, e.g. the name and UID of the pod. Defaults to the
+	// hostname followed by a random UUID.
+	LeaderElectionIdentity string
+
+	// LeaderElectionLabels are added to the labels of the objects of the lock, e.g. to
+	// show the version of the leader. They don't change the leader election record.
+	LeaderElectionLabels map[string]string
+
+	// LeaderElectionAnnotations are added to the annotations of the objects of the lock.
+	LeaderElectionAnnotations map[string]string
+}
+
+// Callbacks are called by the manager when its leader election state changes, in
+// addition to its own handling. A panic in a callback is logged and doesn't affect
+// leader election. If leader election is disabled, the manager starts leading once
+// it's started and stops leading once its runnables are stopped.
+type Callbacks struct {
+	// OnStartedLeading is called when this candidate becomes the leader, before the
+	// runnables that need leader election are started, so it must not block for long.
+	// The context is cancelled when it stops leading.
+	OnStartedLeading func(ctx context.Context)
+
+	// OnStoppedLeading is called when this candidate stops being the leader, e.g.
+	// because it lost its lease or because the manager is stopped.
+	OnStoppedLeading func()
+
+	// OnNewLeader is called with the identity of the leader when a new leader is
+	// observed, including this candidate.
+	OnNewLeader func(identity string)
+}
+
+// LockConfig configures one of the two locks used to migrate leader election from one
+// resource lock to another, e.g. to another type, namespace or name, without ever having
+// two leaders. Leading requires holding the primary lock. The leader also holds the
+// secondary lock if it can, but doesn't give up leadership if it can't.
+//
+// To migrate from an old lock to a new one, release a version that uses the old lock as
+// the primary lock and the new lock as the secondary lock. While it rolls out, candidates
+// of this version are blocked by leaders of the previous version, which hold the old lock.
+// Once it rolled out everywhere, a version that only uses the new lock can be released.
+type LockConfig struct {
+	// ResourceLock is the type of the lock, e.g. "leases" or "configmapsleases".
+	// Defaults to LeaderElectionResourceLock.
+	ResourceLock string
+
+	// Namespace is the namespace of the lock. Defaults to LeaderElectionNamespace.
+	Namespace string
+
+	// Name is the name of the lock. Defaults to LeaderElectionID.
+	Name string
+}
+
+// lockedObjects returns the kinds of the objects that lock the given type of resource lock.
+var lockedObjects = map[string][]string{
+	resourcelock.LeasesResourceLock:           {"Lease"},
+	resourcelock.ConfigMapsLeasesResourceLock: {"ConfigMap", "Lease"},
+	resourcelock.EndpointsLeasesResourceLock:  {"Endpoints", "Lease"},
+}
+
+// validateLocks validates the primary and the secondary lock, after defaulting.
+func validateLocks(locks []LockConfig) error {
+	if len(locks) != 2 {
+		return fmt.Errorf("LeaderElectionLocks must contain exactly two locks, the primary and the secondary lock, got %d", len(locks))
+	}
+
+	objects := map[string]bool{}
+	for _, lock := range locks {
+		kinds, ok := lockedObjects[lock.ResourceLock]
+		if !ok {
+			return fmt.Errorf("resource lock %q is not supported in LeaderElectionLocks", lock.ResourceLock)
+		}
+		if lock.Namespace == "" || lock.Name == "" {
+			return fmt.Errorf("namespace and name of the %q lock in LeaderElectionLocks must be set", lock.ResourceLock)
+		}
+		for _, kind := range kinds {
+			object := kind + " " + lock.Namespace + "/" + lock.Name
+			if objects[object] {
+				return fmt.Errorf("the primary and the secondary lock in LeaderElectionLocks must not both use the %s", object)
+			}
+			objects[object] = true
+		}
+	}
+	return nil
 }
 
 // NewResourceLock creates a new resource lock for use in a leader election loop.
@@ -66,12 +157,12 @@ func NewResourceLock(config *rest.Config, recorderProvider recorder.Provider, op
 	}
 
 	// LeaderElectionID must be provided to prevent clashes
-	if options.LeaderElectionID == "" {
+	if options.LeaderElectionID == "" && len(options.LeaderElectionLocks) == 0 {
 		return nil, errors.New("LeaderElectionID must be configured")
 	}
 
 	// Default the namespace (if running in cluster)
-	if options.LeaderElectionNamespace == "" {
+	if options.LeaderElectionNamespace == "" && needsDefaultNamespace(options.LeaderElectionLocks) {
 		var err error
 		options.LeaderElectionNamespace, err = getInClusterNamespace()
 		if err != nil {
@@ -79,12 +170,34 @@ func NewResourceLock(config *rest.Config, recorderProvider recorder.Provider, op
 		}
 	}
 
+	var locks []LockConfig
+	for _, lock := range options.LeaderElectionLocks {
+		if lock.ResourceLock == "" {
+			lock.ResourceLock = options.LeaderElectionResourceLock
+		}
+		if lock.Namespace == "" {
+			lock.Namespace = options.LeaderElectionNamespace
+		}
+		if lock.Name == "" {
+			lock.Name = options.LeaderElectionID
+		}
+		locks = append(locks, lock)
+	}
+	if locks != nil {
+		if err := validateLocks(locks); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateMetadata(options.LeaderElectionLabels, options.LeaderElectionAnnotations); err != nil {
+		return nil, err
+	}
+
 	// Leader id, needs to be unique
-	id, err := os.Hostname()
+	id, err := identity(options)
 	if err != nil {
 		return nil, err
 	}
-	id = id + "_" + string(uuid.NewUUID())
 
 	// Construct clients for leader election
 	rest.AddUserAgent(config, "leader-election")
@@ -98,15 +211,64 @@ func NewResourceLock(config *rest.Config, recorderProvider recorder.Provider, op
 		return nil, err
 	}
 
-	return resourcelock.New(options.LeaderElectionResourceLock,
-		options.LeaderElectionNamespace,
-		options.LeaderElectionID,
-		corev1Client,
-		coordinationClient,
-		resourcelock.ResourceLockConfig{
-			Identity:      id,
-			EventRecorder: recorderProvider.GetEventRecorderFor(id),
+	newLock := func(lock LockConfig) (resourcelock.Interface, error) {
+		l, err := resourcelock.New(lock.ResourceLock,
+			lock.Namespace,
+			lock.Name,
+			corev1Client,
+			coordinationClient,
+			resourcelock.ResourceLockConfig{
+				Identity:      id,
+				EventRecorder: recorderProvider.GetEventRecorderFor(id),
+			})
+		if err != nil {
+			return nil, err
+		}
+		return newMetadataLock(l, lock, options.LeaderElectionLabels, options.LeaderElectionAnnotations, corev1Client, coordinationClient)
+	}
+
+	if locks == nil {
+		return newLock(LockConfig{
+			ResourceLock: options.LeaderElectionResourceLock,
+			Namespace:    options.LeaderElectionNamespace,
+			Name:         options.LeaderElectionID,
 		})
+	}
+
+	primary, err := newLock(locks[0])
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := newLock(locks[1])
+	if err != nil {
+		return nil, err
+	}
+	return &migrationLock{primary: primary, secondary: secondary, log: log}, nil
+}
+
+// identity returns the configured identity of the candidate, or a unique one.
+func identity(options Options) (string, error) {
+	if options.LeaderElectionIdentity != "" {
+		return options.LeaderElectionIdentity, nil
+	}
+	id, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	return id + "_" + string(uuid.NewUUID()), nil
+}
+
+// needsDefaultNamespace returns whether any lock uses the default namespace.
+func needsDefaultNamespace(locks []LockConfig) bool {
+	if len(locks) == 0 {
+		return true
+	}
+	for _, lock := range locks {
+		if lock.Namespace == "" {
+			return true
+		}
+	}
+	return false
 }
 
 func getInClusterNamespace() (string, error) {
@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// validateMetadata validates the labels and annotations of the lock objects.
+func validateMetadata(labels, annotations map[string]string) error {
+	for k, v := range labels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q in LeaderElectionLabels: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q of %q in LeaderElectionLabels: %s", v, k, strings.Join(errs, "; "))
+		}
+	}
+	for k := range annotations {
+		if errs := validation.IsQualifiedName(strings.ToLower(k)); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q in LeaderElectionAnnotations: %s", k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// metadataLock is a resourcelock.Interface that adds labels and annotations to the
+// objects of the lock it wraps. They are added with a merge patch once this candidate
+// created or updated the lock, and again after another candidate held it, so labels
+// and annotations of other writers are kept.
+type metadataLock struct {
+	resourcelock.Interface
+
+	// patch patches the objects of the lock with the given merge patch.
+	patch func(ctx context.Context, data []byte) error
+	data  []byte
+
+	lock    sync.Mutex
+	applied bool
+}
+
+// newMetadataLock wraps the lock of the given type, namespace and name, returning
+// it as is if there are no labels and annotations.
+func newMetadataLock(lock resourcelock.Interface, config LockConfig, labels, annotations map[string]string, corev1Client corev1client.CoreV1Interface, coordinationClient coordinationv1client.CoordinationV1Interface) (resourcelock.Interface, error) {
+	if len(labels) == 0 && len(annotations) == 0 {
+		return lock, nil
+	}
+	kinds, ok := lockedObjects[config.ResourceLock]
+	if !ok {
+		return nil, fmt.Errorf("LeaderElectionLabels and LeaderElectionAnnotations are not supported with resource lock %q", config.ResourceLock)
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels":      labels,
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	patch := func(ctx context.Context, data []byte) error {
+		for _, kind := range kinds {
+			var err error
+			switch kind {
+			case "Lease":
+				_, err = coordinationClient.Leases(config.Namespace).Patch(ctx, config.Name, types.MergePatchType, data, metav1.PatchOptions{})
+			case "ConfigMap":
+				_, err = corev1Client.ConfigMaps(config.Namespace).Patch(ctx, config.Name, types.MergePatchType, data, metav1.PatchOptions{})
+			case "Endpoints":
+				_, err = corev1Client.Endpoints(config.Namespace).Patch(ctx, config.Name, types.MergePatchType, data, metav1.PatchOptions{})
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return &metadataLock{Interface: lock, patch: patch, data: data}, nil
+}
+
+// Get implements resourcelock.Interface.
+func (l *metadataLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	record, raw, err := l.Interface.Get(ctx)
+	if err == nil && record.HolderIdentity != l.Identity() {
+		l.lock.Lock()
+		l.applied = false
+		l.lock.Unlock()
+	}
+	return record, raw, err
+}
+
+// Create implements resourcelock.Interface.
+func (l *metadataLock) Create(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if err := l.Interface.Create(ctx, ler); err != nil {
+		return err
+	}
+	l.apply(ctx)
+	return nil
+}
+
+// Update implements resourcelock.Interface.
+func (l *metadataLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if err := l.Interface.Update(ctx, ler); err != nil {
+		return err
+	}
+	l.apply(ctx)
+	return nil
+}
+
+// apply patches the objects of the lock unless it already did. Failing to patch them
+// must not fail the election, so it is retried on the next renewal instead.
+func (l *metadataLock) apply(ctx context.Context) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.applied {
+		return
+	}
+	l.applied = l.patch(ctx, l.data) == nil
+}
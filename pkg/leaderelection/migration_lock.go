@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// migrationLock is the lock of the LeaderElectionLocks. Like resourcelock.MultiLock,
+// it reports an unknown leader while the secondary lock is held by another candidate,
+// so that candidates of the next version, which only use the secondary lock, and of
+// this version never lead at the same time. Unlike resourcelock.MultiLock, only the
+// errors of the primary lock fail the creations and updates: the errors of writing the
+// secondary lock are logged, so that the leader keeps renewing the primary lock while the
+// secondary lock can't be written. As a candidate of the next version may hold the
+// secondary lock, failing to read it still fails the Get.
+type migrationLock struct {
+	primary   resourcelock.Interface
+	secondary resourcelock.Interface
+	log       logr.Logger
+}
+
+var _ resourcelock.Interface = &migrationLock{}
+
+// Get implements resourcelock.Interface.
+func (l *migrationLock) Get(ctx context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	primary, primaryRaw, err := l.primary.Get(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The secondary lock may be held by a candidate of the next version unless it's
+	// known to be free, so failing to read it fails the Get.
+	secondary, secondaryRaw, err := l.secondary.Get(ctx)
+	if apierrors.IsNotFound(err) {
+		return primary, primaryRaw, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the secondary leader election lock %s: %w", l.secondary.Describe(), err)
+	}
+
+	if secondary.HolderIdentity != "" && primary.HolderIdentity != secondary.HolderIdentity {
+		primary.HolderIdentity = resourcelock.UnknownLeader
+		if primaryRaw, err = json.Marshal(primary); err != nil {
+			return nil, nil, err
+		}
+	}
+	return primary, resourcelock.ConcatRawRecord(primaryRaw, secondaryRaw), nil
+}
+
+// Create implements resourcelock.Interface.
+func (l *migrationLock) Create(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if err := l.primary.Create(ctx, ler); err != nil {
+		return err
+	}
+	l.updateSecondary(ctx, ler)
+	return nil
+}
+
+// Update implements resourcelock.Interface.
+func (l *migrationLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if err := l.primary.Update(ctx, ler); err != nil {
+		return err
+	}
+	l.updateSecondary(ctx, ler)
+	return nil
+}
+
+// updateSecondary creates or updates the secondary lock, logging its errors.
+func (l *migrationLock) updateSecondary(ctx context.Context, ler resourcelock.LeaderElectionRecord) {
+	_, _, err := l.secondary.Get(ctx)
+	switch {
+	case apierrors.IsNotFound(err):
+		err = l.secondary.Create(ctx, ler)
+	case err == nil:
+		err = l.secondary.Update(ctx, ler)
+	}
+	if err != nil {
+		l.log.Error(err, "Failed to hold the secondary leader election lock, still leading with the primary lock", "lock", l.secondary.Describe())
+	}
+}
+
+// RecordEvent implements resourcelock.Interface.
+func (l *migrationLock) RecordEvent(s string) {
+	l.primary.RecordEvent(s)
+	l.secondary.RecordEvent(s)
+}
+
+// Describe implements resourcelock.Interface.
+func (l *migrationLock) Describe() string {
+	return l.primary.Describe()
+}
+
+// Identity implements resourcelock.Interface.
+func (l *migrationLock) Identity() string {
+	return l.primary.Identity()
+}
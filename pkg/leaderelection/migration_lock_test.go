@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("migrationLock", func() {
+	var (
+		ctx       context.Context
+		primary   *fakeLock
+		secondary *fakeLock
+		lock      *migrationLock
+		ler       resourcelock.LeaderElectionRecord
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		primary = &fakeLock{name: "primary"}
+		secondary = &fakeLock{name: "secondary"}
+		lock = &migrationLock{primary: primary, secondary: secondary, log: logr.Discard()}
+		ler = resourcelock.LeaderElectionRecord{HolderIdentity: "me", LeaseDurationSeconds: 15}
+	})
+
+	It("should hold both locks", func() {
+		Expect(lock.Create(ctx, ler)).To(Succeed())
+		Expect(primary.record).To(Equal(&ler))
+		Expect(secondary.record).To(Equal(&ler))
+
+		actual, _, err := lock.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.HolderIdentity).To(Equal("me"))
+	})
+
+	It("should keep renewing the primary lock if the secondary lock can't be written", func() {
+		Expect(lock.Create(ctx, ler)).To(Succeed())
+		secondary.writeErr = errors.New("secondary unavailable")
+
+		By("renewing the primary lock")
+		ler.RenewTime.Time = ler.RenewTime.Add(1)
+		Expect(lock.Update(ctx, ler)).To(Succeed())
+		Expect(primary.record).To(Equal(&ler))
+
+		By("getting the record of the primary lock")
+		actual, _, err := lock.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.HolderIdentity).To(Equal("me"))
+	})
+
+	It("should fail to get the lock if the secondary lock can't be read", func() {
+		Expect(lock.Create(ctx, ler)).To(Succeed())
+		secondary.err = errors.New("secondary unavailable")
+
+		_, _, err := lock.Get(ctx)
+		Expect(err).To(MatchError(ContainSubstring("secondary unavailable")))
+	})
+
+	It("should fail to create the lock if the primary lock was created concurrently", func() {
+		// Another candidate creates the primary lock after it was found missing.
+		primary.record = &resourcelock.LeaderElectionRecord{HolderIdentity: "other", LeaseDurationSeconds: 15}
+		primary.hidden = true
+
+		Expect(apierrors.IsAlreadyExists(lock.Create(ctx, ler))).To(BeTrue())
+		Expect(secondary.record).To(BeNil())
+
+		By("never leading with a leader elector")
+		started := make(chan struct{})
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:          lock,
+			LeaseDuration: 2 * time.Second,
+			RenewDeadline: time.Second,
+			RetryPeriod:   50 * time.Millisecond,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(context.Context) { close(started) },
+				OnStoppedLeading: func() {},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go elector.Run(ctx)
+		Consistently(started, "500ms").ShouldNot(BeClosed())
+	})
+
+	It("should fail if the primary lock fails", func() {
+		Expect(lock.Create(ctx, ler)).To(Succeed())
+		primary.err = errors.New("primary unavailable")
+
+		Expect(lock.Update(ctx, ler)).To(MatchError("primary unavailable"))
+		_, _, err := lock.Get(ctx)
+		Expect(err).To(MatchError("primary unavailable"))
+	})
+
+	It("should report an unknown leader while the secondary lock is held by another candidate", func() {
+		Expect(primary.Create(ctx, resourcelock.LeaderElectionRecord{})).To(Succeed())
+		Expect(secondary.Create(ctx, resourcelock.LeaderElectionRecord{HolderIdentity: "next-version"})).To(Succeed())
+
+		actual, _, err := lock.Get(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(actual.HolderIdentity).To(Equal(resourcelock.UnknownLeader))
+	})
+
+	It("should be created over the LeaderElectionLocks", func() {
+		l, err := NewResourceLock(&rest.Config{Host: "https://example.com"}, fakeRecorderProvider{}, Options{
+			LeaderElection:          true,
+			LeaderElectionID:        "controller-runtime",
+			LeaderElectionNamespace: "my-ns",
+			LeaderElectionLocks: []LockConfig{
+				{ResourceLock: resourcelock.ConfigMapsLeasesResourceLock, Namespace: "kube-system"},
+				{Name: "controller-runtime-new"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(l).To(BeAssignableToTypeOf(&migrationLock{}))
+		Expect(l.(*migrationLock).primary).To(BeAssignableToTypeOf(&resourcelock.MultiLock{}))
+		Expect(l.(*migrationLock).primary.Describe()).To(Equal("kube-system/controller-runtime"))
+		Expect(l.(*migrationLock).secondary).To(BeAssignableToTypeOf(&resourcelock.LeaseLock{}))
+		Expect(l.(*migrationLock).secondary.Describe()).To(Equal("my-ns/controller-runtime-new"))
+	})
+})
+
+// fakeLock is an in-memory resourcelock.Interface that fails with err if set, and
+// fails to be written with writeErr if set. While hidden, its record isn't found.
+type fakeLock struct {
+	name     string
+	record   *resourcelock.LeaderElectionRecord
+	hidden   bool
+	err      error
+	writeErr error
+}
+
+func (l *fakeLock) Get(context.Context) (*resourcelock.LeaderElectionRecord, []byte, error) {
+	if l.err != nil {
+		return nil, nil, l.err
+	}
+	if l.record == nil || l.hidden {
+		return nil, nil, apierrors.NewNotFound(schema.GroupResource{Resource: "leases"}, l.name)
+	}
+	current := *l.record
+	raw, err := json.Marshal(current)
+	return &current, raw, err
+}
+
+func (l *fakeLock) Create(_ context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if l.err != nil {
+		return l.err
+	}
+	if l.writeErr != nil {
+		return l.writeErr
+	}
+	if l.record != nil {
+		return apierrors.NewAlreadyExists(schema.GroupResource{Resource: "leases"}, l.name)
+	}
+	l.record = &ler
+	return nil
+}
+
+func (l *fakeLock) Update(_ context.Context, ler resourcelock.LeaderElectionRecord) error {
+	if l.err != nil {
+		return l.err
+	}
+	if l.writeErr != nil {
+		return l.writeErr
+	}
+	l.record = &ler
+	return nil
+}
+
+func (l *fakeLock) RecordEvent(string) {}
+
+func (l *fakeLock) Describe() string {
+	return l.name
+}
+
+func (l *fakeLock) Identity() string {
+	return "me"
+}
+
+type fakeRecorderProvider struct{}
+
+func (fakeRecorderProvider) GetEventRecorderFor(string) record.EventRecorder {
+	return record.NewFakeRecorder(10)
+}
@@ -151,7 +151,7 @@ func (l *DelegatingLogSink) WithName(name string) logr.LogSink {
 		return sink
 	}
 
-	res := &DelegatingLogSink{logger: l.logger}
+	res := &DelegatingLogSink{logger: l.logger.WithName(name)}
 	promise := l.promise.WithName(res, name)
 	res.promise = promise
 
@@ -171,13 +171,20 @@ func (l *DelegatingLogSink) WithValues(tags ...interface{}) logr.LogSink {
 		return sink
 	}
 
-	res := &DelegatingLogSink{logger: l.logger}
+	res := &DelegatingLogSink{logger: l.logger.WithValues(tags...)}
 	promise := l.promise.WithValues(res, tags...)
 	res.promise = promise
 
 	return res
 }
 
+// delegate returns the sink that is currently delegated to.
+func (l *DelegatingLogSink) delegate() logr.LogSink {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.logger
+}
+
 // Fulfill switches the logger over to use the actual logger
 // provided, instead of the temporary initial one, if this method
 // has not been previously called.
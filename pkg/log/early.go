@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+)
+
+// SetEarlyLogBufferSize makes Log buffer up to size records that are logged before
+// SetLogger is called, and write them to the logger passed to SetLogger. Records that
+// don't fit into the buffer are dropped, and their number is logged when the buffer is
+// written. The buffer is discarded if SetLogger is not called before the timeout, see
+// SetLoggerTimeout. By default, no records are buffered.
+//
+// It should be called as early as possible, e.g. at the start of main, and has no
+// effect once SetLogger has been called.
+func SetEarlyLogBufferSize(size int) {
+	earlyLog.setBufferSize(size)
+}
+
+// SetEarlyLogHook sets a function that is called for every record that is logged
+// through Log before SetLogger is called. Tests can use it to panic or to warn about
+// logs that would otherwise be lost, e.g.:
+//
+//	log.SetEarlyLogHook(func(msg string) { panic("logged before SetLogger: " + msg) })
+//
+// It has no effect once SetLogger has been called.
+func SetEarlyLogHook(hook func(msg string)) {
+	earlyLog.setHook(hook)
+}
+
+// earlyLogs holds the records that are logged before a logger is set.
+type earlyLogs struct {
+	lock    sync.Mutex
+	size    int
+	records []earlyRecord
+	dropped int
+	hook    func(msg string)
+	// done is set once the records have been written or discarded.
+	done bool
+}
+
+// earlyRecord is a record that was logged before a logger was set.
+type earlyRecord struct {
+	derivations   []derivation
+	level         int
+	err           error
+	isError       bool
+	msg           string
+	keysAndValues []interface{}
+}
+
+// derivation is a call of WithName or WithValues on a logr.LogSink.
+type derivation struct {
+	name   *string
+	values []interface{}
+}
+
+func (d derivation) apply(sink logr.LogSink) logr.LogSink {
+	if d.name != nil {
+		return sink.WithName(*d.name)
+	}
+	return sink.WithValues(d.values...)
+}
+
+func newEarlyLogs() *earlyLogs {
+	return &earlyLogs{}
+}
+
+func (e *earlyLogs) setBufferSize(size int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.size = size
+}
+
+func (e *earlyLogs) setHook(hook func(msg string)) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.hook = hook
+}
+
+// sink returns the sink that records into e.
+func (e *earlyLogs) sink() logr.LogSink {
+	return &earlyLogSink{logs: e}
+}
+
+// enabled returns whether records are buffered or passed to the hook.
+func (e *earlyLogs) enabled() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return !e.done && (e.size > 0 || e.hook != nil)
+}
+
+func (e *earlyLogs) record(r earlyRecord) {
+	e.lock.Lock()
+	if e.done {
+		e.lock.Unlock()
+		return
+	}
+	hook := e.hook
+	switch {
+	case len(e.records) < e.size:
+		e.records = append(e.records, r)
+	case e.size > 0:
+		e.dropped++
+	}
+	e.lock.Unlock()
+
+	// The hook is called without holding the lock, as it may panic.
+	if hook != nil {
+		hook(r.msg)
+	}
+}
+
+// flush writes the buffered records to the given sink in the order they were
+// logged and stops buffering. A nil sink discards them.
+func (e *earlyLogs) flush(sink logr.LogSink) {
+	e.lock.Lock()
+	records, dropped := e.records, e.dropped
+	e.records, e.dropped, e.done = nil, 0, true
+	e.lock.Unlock()
+
+	if sink == nil {
+		return
+	}
+	for _, r := range records {
+		s := sink
+		for _, d := range r.derivations {
+			s = d.apply(s)
+		}
+		if r.isError {
+			s.Error(r.err, r.msg, r.keysAndValues...)
+		} else if s.Enabled(r.level) {
+			s.Info(r.level, r.msg, r.keysAndValues...)
+		}
+	}
+	if dropped > 0 {
+		sink.Info(0, "Dropped log records that were logged before the logger was set, as the buffer was full", "count", dropped)
+	}
+}
+
+// earlyLogSink is a logr.LogSink that records into earlyLogs.
+type earlyLogSink struct {
+	logs        *earlyLogs
+	derivations []derivation
+}
+
+var _ logr.LogSink = &earlyLogSink{}
+
+// Init implements logr.LogSink.
+func (s *earlyLogSink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink. The verbosity of the logger passed to SetLogger
+// is not known yet, so all levels are enabled while records are buffered.
+func (s *earlyLogSink) Enabled(int) bool {
+	return s.logs.enabled()
+}
+
+// Info implements logr.LogSink.
+func (s *earlyLogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.logs.record(earlyRecord{derivations: s.derivations, level: level, msg: msg, keysAndValues: keysAndValues})
+}
+
+// Error implements logr.LogSink.
+func (s *earlyLogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.logs.record(earlyRecord{derivations: s.derivations, err: err, isError: true, msg: msg, keysAndValues: keysAndValues})
+}
+
+// WithName implements logr.LogSink.
+func (s *earlyLogSink) WithName(name string) logr.LogSink {
+	return s.derive(derivation{name: &name})
+}
+
+// WithValues implements logr.LogSink.
+func (s *earlyLogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return s.derive(derivation{values: keysAndValues})
+}
+
+func (s *earlyLogSink) derive(d derivation) logr.LogSink {
+	derivations := make([]derivation, 0, len(s.derivations)+1)
+	derivations = append(derivations, s.derivations...)
+	return &earlyLogSink{logs: s.logs, derivations: append(derivations, d)}
+}
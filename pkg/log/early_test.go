@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"errors"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("early logs", func() {
+	var (
+		early    *earlyLogs
+		delegLog *DelegatingLogSink
+		root     *fakeLoggerRoot
+		baseLog  logr.LogSink
+	)
+
+	BeforeEach(func() {
+		early = newEarlyLogs()
+		delegLog = NewDelegatingLogSink(early.sink())
+		root = &fakeLoggerRoot{}
+		baseLog = &fakeLogger{root: root}
+	})
+
+	setLogger := func() {
+		delegLog.Fulfill(baseLog)
+		early.flush(baseLog)
+	}
+
+	It("should not buffer records by default", func() {
+		logr.New(delegLog).Info("before")
+		Expect(logr.New(delegLog).Enabled()).To(BeFalse())
+
+		setLogger()
+		Expect(root.messages).To(BeEmpty())
+	})
+
+	It("should write buffered records in the order they were logged", func() {
+		early.setBufferSize(10)
+		err := errors.New("some error")
+
+		l1 := logr.New(delegLog).WithName("runtimeLog").WithValues("tag", "value1")
+		l1.Info("first")
+		logr.New(delegLog).V(1).Info("second")
+		l1.WithName("sub").Error(err, "third", "key", "value")
+
+		setLogger()
+		l1.Info("after")
+
+		Expect(root.messages).To(Equal([]logInfo{
+			{name: []string{"runtimeLog"}, tags: []interface{}{"tag", "value1"}, msg: "first"},
+			{msg: "second"},
+			{name: []string{"runtimeLog", "sub"}, tags: []interface{}{"tag", "value1", "error", err, "key", "value"}, msg: "third"},
+			{name: []string{"runtimeLog"}, tags: []interface{}{"tag", "value1"}, msg: "after"},
+		}))
+	})
+
+	It("should drop records that don't fit into the buffer", func() {
+		early.setBufferSize(2)
+
+		l := logr.New(delegLog)
+		for _, msg := range []string{"first", "second", "third", "fourth"} {
+			l.Info(msg)
+		}
+
+		setLogger()
+		Expect(root.messages).To(HaveLen(3))
+		Expect(root.messages[0].msg).To(Equal("first"))
+		Expect(root.messages[1].msg).To(Equal("second"))
+		Expect(root.messages[2].tags).To(Equal([]interface{}{"count", 2}))
+	})
+
+	It("should discard buffered records when falling back to the null logger", func() {
+		early.setBufferSize(10)
+		logr.New(delegLog).Info("before")
+
+		delegLog.Fulfill(NullLogSink{})
+		early.flush(nil)
+		Expect(early.records).To(BeEmpty())
+		Expect(logr.New(early.sink()).Enabled()).To(BeFalse())
+	})
+
+	It("should call the hook for records logged before the logger is set", func() {
+		early.setHook(func(msg string) {
+			panic("logged before SetLogger: " + msg)
+		})
+
+		l := logr.New(delegLog).WithName("runtimeLog")
+		Expect(func() { l.Info("before") }).To(PanicWith("logged before SetLogger: before"))
+
+		setLogger()
+		Expect(func() { l.Info("after") }).NotTo(Panic())
+		Expect(root.messages).To(ConsistOf(logInfo{name: []string{"runtimeLog"}, msg: "after"}))
+	})
+})
@@ -31,6 +31,8 @@ limitations under the License.
 // defined by a package called logr
 // (https://pkg.go.dev/github.com/go-logr/logr).  The sub-package zap provides
 // helpers for setting up logr backed by Zap (go.uber.org/zap).
+// FromSlog and ToSlogHandler bridge logr and log/slog when built with Go 1.21
+// or newer.
 package log
 
 import (
@@ -42,12 +44,36 @@ import (
 )
 
 // SetLogger sets a concrete logging implementation for all deferred Loggers.
+// Records that were buffered before, see SetEarlyLogBufferSize, are written to it.
 func SetLogger(l logr.Logger) {
 	loggerWasSetLock.Lock()
 	defer loggerWasSetLock.Unlock()
 
 	loggerWasSet = true
 	dlog.Fulfill(l.GetSink())
+	earlyLog.flush(l.GetSink())
+}
+
+// SetLoggerTimeout sets the time after the start of the binary after which Log falls
+// back to a NullLogSink if SetLogger has not been called, 30 seconds by default. If it
+// has passed already, Log falls back immediately. Zero or a negative timeout disables
+// the fallback. It has no effect once SetLogger has been called or Log fell back.
+func SetLoggerTimeout(timeout time.Duration) {
+	loggerWasSetLock.Lock()
+	defer loggerWasSetLock.Unlock()
+
+	if loggerWasSet || loggerFellBack {
+		return
+	}
+	loggerTimeoutTimer.Stop()
+	if timeout <= 0 {
+		return
+	}
+	remaining := timeout - time.Since(loggerTimeoutStart)
+	if remaining < 0 {
+		remaining = 0
+	}
+	loggerTimeoutTimer = time.AfterFunc(remaining, fallBackToNullLogger)
 }
 
 // It is safe to assume that if this wasn't set within the first 30 seconds of a binaries
@@ -58,30 +84,43 @@ func SetLogger(l logr.Logger) {
 // here. They will always get executed before any code that imports controller-runtime
 // has a chance to run and hence to set an actual logger.
 func init() {
-	// Init is blocking, so start a new goroutine
-	go func() {
-		time.Sleep(30 * time.Second)
-		loggerWasSetLock.Lock()
-		defer loggerWasSetLock.Unlock()
-		if !loggerWasSet {
-			dlog.Fulfill(NullLogSink{})
-		}
-	}()
+	loggerWasSetLock.Lock()
+	defer loggerWasSetLock.Unlock()
+
+	loggerTimeoutStart = time.Now()
+	loggerTimeoutTimer = time.AfterFunc(defaultLoggerTimeout, fallBackToNullLogger)
 }
 
+func fallBackToNullLogger() {
+	loggerWasSetLock.Lock()
+	defer loggerWasSetLock.Unlock()
+
+	if !loggerWasSet && !loggerFellBack {
+		loggerFellBack = true
+		dlog.Fulfill(NullLogSink{})
+		earlyLog.flush(nil)
+	}
+}
+
+const defaultLoggerTimeout = 30 * time.Second
+
 var (
-	loggerWasSetLock sync.Mutex
-	loggerWasSet     bool
+	loggerWasSetLock   sync.Mutex
+	loggerWasSet       bool
+	loggerFellBack     bool
+	loggerTimeoutStart time.Time
+	loggerTimeoutTimer *time.Timer
 )
 
 // Log is the base logger used by kubebuilder.  It delegates
 // to another logr.Logger. You *must* call SetLogger to
 // get any actual logging. If SetLogger is not called within
 // the first 30 seconds of a binaries lifetime, it will get
-// set to a NullLogSink.
+// set to a NullLogSink, see SetLoggerTimeout.
 var (
-	dlog = NewDelegatingLogSink(NullLogSink{})
-	Log  = logr.New(dlog)
+	earlyLog = newEarlyLogs()
+	dlog     = NewDelegatingLogSink(earlyLog.sink())
+	Log      = logr.New(dlog)
 )
 
 // FromContext returns a logger with predefined values from a context.Context.
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import "sync"
+
+// ReconcileLogField is a field that controller-runtime adds to the loggers of controllers
+// and the loggers it passes to reconcilers through the context.
+type ReconcileLogField string
+
+const (
+	// ReconcileLogFieldController is the name of the controller.
+	ReconcileLogFieldController ReconcileLogField = "controller"
+	// ReconcileLogFieldControllerGroup is the group of the type reconciled by the controller,
+	// if the controller was created by the builder.
+	ReconcileLogFieldControllerGroup ReconcileLogField = "controllerGroup"
+	// ReconcileLogFieldControllerKind is the kind of the type reconciled by the controller,
+	// if the controller was created by the builder.
+	ReconcileLogFieldControllerKind ReconcileLogField = "controllerKind"
+	// ReconcileLogFieldObject is the reference to the reconciled object, keyed by its kind
+	// if the controller was created by the builder and by "object" otherwise.
+	ReconcileLogFieldObject ReconcileLogField = "object"
+	// ReconcileLogFieldNamespace is the namespace of the reconciled object.
+	ReconcileLogFieldNamespace ReconcileLogField = "namespace"
+	// ReconcileLogFieldName is the name of the reconciled object.
+	ReconcileLogFieldName ReconcileLogField = "name"
+	// ReconcileLogFieldReconcileID is the unique ID of the reconciliation.
+	ReconcileLogFieldReconcileID ReconcileLogField = "reconcileID"
+	// ReconcileLogFieldObjectUID is the UID of the reconciled object, once it has been
+	// fetched by a reconciler created with reconcile.AsReconciler.
+	ReconcileLogFieldObjectUID ReconcileLogField = "objectUID"
+	// ReconcileLogFieldObjectGVK is the GroupVersionKind of the reconciled object, once it
+	// has been fetched by a reconciler created with reconcile.AsReconciler.
+	ReconcileLogFieldObjectGVK ReconcileLogField = "objectGVK"
+)
+
+// defaultReconcileLogFields are the fields that are logged unless SetReconcileLogFields is called.
+var defaultReconcileLogFields = []ReconcileLogField{
+	ReconcileLogFieldController,
+	ReconcileLogFieldControllerGroup,
+	ReconcileLogFieldControllerKind,
+	ReconcileLogFieldObject,
+	ReconcileLogFieldNamespace,
+	ReconcileLogFieldName,
+	ReconcileLogFieldReconcileID,
+	ReconcileLogFieldObjectUID,
+	ReconcileLogFieldObjectGVK,
+}
+
+var (
+	reconcileLogFieldsLock sync.RWMutex
+	reconcileLogFields     = fieldSet(defaultReconcileLogFields)
+)
+
+// DefaultReconcileLogFields returns the fields that are logged unless SetReconcileLogFields
+// is called, e.g. to restore them.
+func DefaultReconcileLogFields() []ReconcileLogField {
+	return append([]ReconcileLogField(nil), defaultReconcileLogFields...)
+}
+
+// SetReconcileLogFields sets the fields that controller-runtime adds to the loggers of
+// controllers and reconciliations, e.g. to cut log volume or to add fields that are not
+// logged by default. It must be called before controllers are created. The fields of the
+// controller and the reconciled request are only added by the default LogConstructor.
+func SetReconcileLogFields(fields ...ReconcileLogField) {
+	reconcileLogFieldsLock.Lock()
+	defer reconcileLogFieldsLock.Unlock()
+
+	reconcileLogFields = fieldSet(fields)
+}
+
+// HasReconcileLogField returns whether the given field is logged.
+func HasReconcileLogField(field ReconcileLogField) bool {
+	reconcileLogFieldsLock.RLock()
+	defer reconcileLogFieldsLock.RUnlock()
+
+	return reconcileLogFields[field]
+}
+
+func fieldSet(fields []ReconcileLogField) map[ReconcileLogField]bool {
+	set := make(map[ReconcileLogField]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+	return set
+}
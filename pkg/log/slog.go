@@ -0,0 +1,217 @@
+//go:build go1.21
+
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	// SlogErrorKey is the key of the error passed to logr.Logger.Error in slog records.
+	SlogErrorKey = "err"
+
+	// SlogLoggerKey is the key of the name of a logr.Logger in slog records. The
+	// names passed to WithName are joined with dots, like the zap loggers do.
+	SlogLoggerKey = "logger"
+)
+
+// FromSlog returns a logr.Logger that writes to the given slog.Handler, e.g. to pass
+// it to SetLogger. V levels are mapped to negative slog levels, so V(1) is logged at
+// slog.Level(-1) and V(4) at slog.LevelDebug. Errors are logged at slog.LevelError,
+// with the error under SlogErrorKey.
+func FromSlog(h slog.Handler) logr.Logger {
+	return logr.New(&slogSink{handler: h})
+}
+
+// slogSink is a logr.LogSink that writes to a slog.Handler.
+type slogSink struct {
+	handler   slog.Handler
+	name      string
+	callDepth int
+}
+
+var _ logr.CallDepthLogSink = &slogSink{}
+
+// Init implements logr.LogSink.
+func (s *slogSink) Init(info logr.RuntimeInfo) {
+	s.callDepth = info.CallDepth
+}
+
+// Enabled implements logr.LogSink.
+func (s *slogSink) Enabled(level int) bool {
+	return s.handler.Enabled(context.Background(), slog.Level(-level))
+}
+
+// Info implements logr.LogSink.
+func (s *slogSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.log(slog.Level(-level), msg, keysAndValues)
+}
+
+// Error implements logr.LogSink.
+func (s *slogSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.log(slog.LevelError, msg, append([]interface{}{SlogErrorKey, err}, keysAndValues...))
+}
+
+func (s *slogSink) log(level slog.Level, msg string, keysAndValues []interface{}) {
+	ctx := context.Background()
+	if !s.handler.Enabled(ctx, level) {
+		return
+	}
+
+	// Skip runtime.Callers, log, Info or Error and the frames added by logr.
+	var pcs [1]uintptr
+	runtime.Callers(3+s.callDepth, pcs[:])
+
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if s.name != "" {
+		r.AddAttrs(slog.String(SlogLoggerKey, s.name))
+	}
+	r.Add(keysAndValues...)
+	_ = s.handler.Handle(ctx, r)
+}
+
+// WithValues implements logr.LogSink.
+func (s *slogSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	r := slog.Record{}
+	r.Add(keysAndValues...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	res := *s
+	res.handler = s.handler.WithAttrs(attrs)
+	return &res
+}
+
+// WithName implements logr.LogSink.
+func (s *slogSink) WithName(name string) logr.LogSink {
+	res := *s
+	if s.name != "" {
+		name = s.name + "." + name
+	}
+	res.name = name
+	return &res
+}
+
+// WithCallDepth implements logr.CallDepthLogSink.
+func (s *slogSink) WithCallDepth(depth int) logr.LogSink {
+	res := *s
+	res.callDepth += depth
+	return &res
+}
+
+// ToSlogHandler returns a slog.Handler that writes to the given logr.Logger, e.g. to
+// use the logger of a controller with libraries that log with slog. Records at
+// slog.LevelError and above are logged with logr.Logger.Error, using an error under
+// SlogErrorKey if there is one. Records below are logged at the V level that is the
+// negated slog level, and at V(0) if that is negative, e.g. for slog.LevelWarn. Attributes
+// in groups are logged with keys prefixed by the group names, joined with dots.
+func ToSlogHandler(l logr.Logger) slog.Handler {
+	return &logrHandler{logger: l}
+}
+
+// logrHandler is a slog.Handler that writes to a logr.Logger.
+type logrHandler struct {
+	logger logr.Logger
+	// prefix is the prefix of the keys of attributes, made of the names of the groups.
+	prefix string
+}
+
+var _ slog.Handler = &logrHandler{}
+
+// Enabled implements slog.Handler.
+func (h *logrHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if level >= slog.LevelError {
+		return h.logger.GetSink() != nil
+	}
+	return h.logger.V(verbosityOf(level)).Enabled()
+}
+
+// Handle implements slog.Handler.
+func (h *logrHandler) Handle(_ context.Context, r slog.Record) error {
+	var err error
+	keysAndValues := make([]interface{}, 0, 2*r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		if e, ok := a.Value.Any().(error); ok && err == nil && h.prefix == "" && a.Key == SlogErrorKey && r.Level >= slog.LevelError {
+			err = e
+			return true
+		}
+		keysAndValues = appendAttr(keysAndValues, h.prefix, a)
+		return true
+	})
+
+	if r.Level >= slog.LevelError {
+		h.logger.Error(err, r.Message, keysAndValues...)
+		return nil
+	}
+	h.logger.V(verbosityOf(r.Level)).Info(r.Message, keysAndValues...)
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *logrHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	keysAndValues := make([]interface{}, 0, 2*len(attrs))
+	for _, a := range attrs {
+		keysAndValues = appendAttr(keysAndValues, h.prefix, a)
+	}
+	return &logrHandler{logger: h.logger.WithValues(keysAndValues...), prefix: h.prefix}
+}
+
+// WithGroup implements slog.Handler.
+func (h *logrHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &logrHandler{logger: h.logger, prefix: h.prefix + name + "."}
+}
+
+// verbosityOf returns the V level of records below slog.LevelError.
+func verbosityOf(level slog.Level) int {
+	if level > 0 {
+		return 0
+	}
+	return -int(level)
+}
+
+// appendAttr appends the key and value of the attribute to keysAndValues, flattening groups.
+func appendAttr(keysAndValues []interface{}, prefix string, a slog.Attr) []interface{} {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		if a.Key == "" {
+			return keysAndValues
+		}
+		return append(keysAndValues, prefix+a.Key, a.Value.Any())
+	}
+
+	// Attributes of groups without a key are inlined, as described by slog.Handler.
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, ga := range a.Value.Group() {
+		keysAndValues = appendAttr(keysAndValues, prefix, ga)
+	}
+	return keysAndValues
+}
@@ -0,0 +1,137 @@
+//go:build go1.21
+
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("slog", func() {
+	Describe("FromSlog", func() {
+		var (
+			out    *bytes.Buffer
+			logger logr.Logger
+		)
+
+		// lines returns the logged records as JSON, without their time.
+		lines := func() []string {
+			return strings.Split(strings.TrimSpace(out.String()), "\n")
+		}
+
+		BeforeEach(func() {
+			out = new(bytes.Buffer)
+			logger = FromSlog(slog.NewJSONHandler(out, &slog.HandlerOptions{
+				Level: slog.Level(-2),
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+		})
+
+		It("should render the logs of a reconciliation", func() {
+			log := logger.WithName("controller-runtime").WithName("controller").
+				WithValues("controller", "pod", "reconcileID", "abc")
+
+			log.Info("Reconciling", "object", "default/foo")
+			log.V(1).Info("Reconcile successful")
+			log.Error(errors.New("boom"), "Reconciler error", "requeue", true)
+
+			Expect(lines()).To(HaveLen(3))
+			Expect(lines()[0]).To(MatchJSON(`{"level":"INFO","msg":"Reconciling","controller":"pod","reconcileID":"abc","logger":"controller-runtime.controller","object":"default/foo"}`))
+			Expect(lines()[1]).To(MatchJSON(`{"level":"DEBUG+3","msg":"Reconcile successful","controller":"pod","reconcileID":"abc","logger":"controller-runtime.controller"}`))
+			Expect(lines()[2]).To(MatchJSON(`{"level":"ERROR","msg":"Reconciler error","controller":"pod","reconcileID":"abc","logger":"controller-runtime.controller","err":"boom","requeue":true}`))
+		})
+
+		It("should map V levels to slog levels", func() {
+			Expect(logger.V(1).Enabled()).To(BeTrue())
+			Expect(logger.V(2).Enabled()).To(BeTrue())
+			Expect(logger.V(3).Enabled()).To(BeFalse())
+
+			logger.V(2).Info("two")
+			logger.V(3).Info("three")
+			Expect(lines()).To(HaveLen(1))
+			Expect(lines()[0]).To(MatchJSON(`{"level":"DEBUG+2","msg":"two"}`))
+		})
+
+		It("should report the caller of the logger", func() {
+			logger = FromSlog(slog.NewTextHandler(out, &slog.HandlerOptions{AddSource: true}))
+			logger.WithName("webhook").Info("Serving")
+			Expect(out.String()).To(ContainSubstring("slog_test.go"))
+		})
+	})
+
+	Describe("ToSlogHandler", func() {
+		var (
+			root *fakeLoggerRoot
+			log  *slog.Logger
+		)
+
+		BeforeEach(func() {
+			root = &fakeLoggerRoot{}
+			log = slog.New(ToSlogHandler(logr.New(&fakeLogger{root: root})))
+		})
+
+		It("should log attributes and groups as keys and values", func() {
+			log.With("controller", "pod").WithGroup("request").Info("Reconciling", "name", "foo", slog.Group("meta", "uid", "123"))
+
+			Expect(root.messages).To(ConsistOf(logInfo{
+				tags: []interface{}{"controller", "pod", "request.name", "foo", "request.meta.uid", "123"},
+				msg:  "Reconciling",
+			}))
+		})
+
+		It("should log errors with the error under the error key", func() {
+			err := errors.New("boom")
+			log.Error("Reconciler error", SlogErrorKey, err, "requeue", true)
+
+			Expect(root.messages).To(ConsistOf(logInfo{
+				tags: []interface{}{"error", err, "requeue", true},
+				msg:  "Reconciler error",
+			}))
+		})
+
+		It("should check the verbosity of the logger", func() {
+			sink := &verbosityLogger{fakeLogger: fakeLogger{root: root}, verbosity: 1}
+			log = slog.New(ToSlogHandler(logr.New(sink)))
+
+			Expect(log.Enabled(context.Background(), slog.Level(-1))).To(BeTrue())
+			Expect(log.Enabled(context.Background(), slog.Level(-2))).To(BeFalse())
+			Expect(log.Enabled(context.Background(), slog.LevelWarn)).To(BeTrue())
+		})
+	})
+})
+
+// verbosityLogger is a fakeLogger that only logs up to the given verbosity.
+type verbosityLogger struct {
+	fakeLogger
+	verbosity int
+}
+
+func (l *verbosityLogger) Enabled(level int) bool { return level <= l.verbosity }
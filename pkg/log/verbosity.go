@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+)
+
+// VerbosityChanger is implemented by logr.LogSinks whose verbosity can be changed
+// at runtime. The verbosity is shared with all loggers derived from the sink.
+type VerbosityChanger interface {
+	// Verbosity returns the highest V level that is logged.
+	Verbosity() int
+
+	// SetVerbosity sets the highest V level that is logged.
+	SetVerbosity(level int) error
+}
+
+// VerbosityChangerFor returns the VerbosityChanger of the sink of the given
+// logger, if it supports changing its verbosity. Loggers derived from Log
+// support it once SetLogger has been called with a logger that does.
+func VerbosityChangerFor(l logr.Logger) (VerbosityChanger, bool) {
+	sink := l.GetSink()
+	if dl, ok := sink.(*DelegatingLogSink); ok {
+		sink = dl.delegate()
+	}
+	vc, ok := sink.(VerbosityChanger)
+	return vc, ok
+}
+
+// verbosity is the body of the requests to and the responses of the verbosity handler.
+type verbosity struct {
+	Level *int `json:"level"`
+}
+
+// NewVerbosityHandler returns a handler that gets the verbosity of the given logger
+// on GET requests and sets it on PUT requests with a body like {"level": 4}. It is
+// meant to be added to the metrics server of a manager, e.g.:
+//
+//	mgr.AddMetricsExtraHandler("/debug/loglevel", log.NewVerbosityHandler(mgr.GetLogger()))
+//
+// The handler itself does not authenticate or authorize requests. Set the
+// Metrics.FilterProvider of the manager to filters.WithAuthenticationAndAuthorization
+// for the metrics server to do so, in which case changing the verbosity requires e.g. a
+// ClusterRole with the following rules, while reading it only requires the get verb:
+//
+//	rules:
+//	- nonResourceURLs: ["/debug/loglevel"]
+//	  verbs: ["get", "put"]
+//
+// It responds with 501 Not Implemented if the sink of the logger doesn't implement
+// VerbosityChanger.
+func NewVerbosityHandler(l logr.Logger) http.Handler {
+	return &verbosityHandler{logger: l}
+}
+
+type verbosityHandler struct {
+	logger logr.Logger
+}
+
+func (h *verbosityHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodPut {
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, fmt.Sprintf("method %s is not allowed", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	vc, ok := VerbosityChangerFor(h.logger)
+	if !ok {
+		http.Error(w, "the logger does not support changing its verbosity", http.StatusNotImplemented)
+		return
+	}
+
+	if req.Method == http.MethodPut {
+		var body verbosity
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(w, fmt.Sprintf("invalid body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if body.Level == nil || *body.Level < 0 {
+			http.Error(w, "level must be set to a non-negative number", http.StatusBadRequest)
+			return
+		}
+		if err := vc.SetVerbosity(*body.Level); err != nil {
+			http.Error(w, fmt.Sprintf("unable to set the verbosity: %v", err), http.StatusInternalServerError)
+			return
+		}
+		h.logger.Info("Changed the verbosity of the logger", "level", *body.Level)
+	}
+
+	level := vc.Verbosity()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(verbosity{Level: &level})
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var _ = Describe("verbosity handler", func() {
+	serve := func(h http.Handler, method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/debug/loglevel", strings.NewReader(body)))
+		return rec
+	}
+
+	It("should change the verbosity of the logger", func() {
+		logOut := new(bytes.Buffer)
+		logger := zap.New(zap.WriteTo(logOut)).WithName("manager")
+		h := NewVerbosityHandler(logger)
+
+		logger.V(4).Info("before")
+		Expect(logOut.String()).NotTo(ContainSubstring(`"msg":"before"`))
+
+		rec := serve(h, http.MethodPut, `{"level": 4}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(MatchJSON(`{"level": 4}`))
+
+		logger.V(4).Info("after")
+		Expect(logOut.String()).To(ContainSubstring(`"msg":"after"`))
+
+		rec = serve(h, http.MethodGet, "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(MatchJSON(`{"level": 4}`))
+	})
+
+	It("should change the verbosity of a delegating logger once it has been fulfilled", func() {
+		logOut := new(bytes.Buffer)
+		dl := NewDelegatingLogSink(NullLogSink{})
+		h := NewVerbosityHandler(logr.New(dl).WithName("manager"))
+
+		Expect(serve(h, http.MethodPut, `{"level": 4}`).Code).To(Equal(http.StatusNotImplemented))
+
+		dl.Fulfill(zap.New(zap.WriteTo(logOut)).GetSink())
+		Expect(serve(h, http.MethodPut, `{"level": 4}`).Code).To(Equal(http.StatusOK))
+		logr.New(dl).V(4).Info("after")
+		Expect(logOut.String()).To(ContainSubstring(`"msg":"after"`))
+	})
+
+	It("should reject invalid requests", func() {
+		h := NewVerbosityHandler(zap.New(zap.WriteTo(new(bytes.Buffer))))
+
+		Expect(serve(h, http.MethodPost, `{"level": 4}`).Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(serve(h, http.MethodPut, `{"level": "4"}`).Code).To(Equal(http.StatusBadRequest))
+		Expect(serve(h, http.MethodPut, `{}`).Code).To(Equal(http.StatusBadRequest))
+		Expect(serve(h, http.MethodPut, `{"level": -1}`).Code).To(Equal(http.StatusBadRequest))
+	})
+})
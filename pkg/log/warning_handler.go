@@ -17,9 +17,13 @@ limitations under the License.
 package log
 
 import (
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
+
+	"sigs.k8s.io/controller-runtime/pkg/internal/metrics"
 )
 
 // KubeAPIWarningLoggerOptions controls the behavior
@@ -29,6 +33,16 @@ type KubeAPIWarningLoggerOptions struct {
 	// Setting this to true in a long-running process handling many warnings can
 	// result in increased memory use.
 	Deduplicate bool
+
+	// DeduplicateTTL is the time after which a deduplicated warning message is
+	// written again, e.g. an hour to log a recurring warning once per hour. It
+	// also bounds the memory used for deduplication. Zero means that a warning
+	// message is only written once. Only used if Deduplicate is true.
+	DeduplicateTTL time.Duration
+
+	// ErrorSubstrings escalates warning messages that contain any of these
+	// substrings to the error level, e.g. for removals that must be acted on.
+	ErrorSubstrings []string
 }
 
 // KubeAPIWarningLogger is a wrapper around
@@ -39,11 +53,15 @@ type KubeAPIWarningLogger struct {
 	logger logr.Logger
 	// opts contain options controlling warning output
 	opts KubeAPIWarningLoggerOptions
-	// writtenLock gurads written
+	// writtenLock gurads written and lastPruned
 	writtenLock sync.Mutex
 	// used to keep track of already logged messages
-	// and help in de-duplication.
-	written map[string]struct{}
+	// and when they were logged to help in de-duplication.
+	written map[string]time.Time
+	// lastPruned is when expired messages were last removed from written.
+	lastPruned time.Time
+	// now returns the current time, it is overridden in tests.
+	now func() time.Time
 }
 
 // HandleWarningHeader handles logging for responses from API server that are
@@ -53,24 +71,50 @@ func (l *KubeAPIWarningLogger) HandleWarningHeader(code int, agent string, messa
 		return
 	}
 
-	if l.opts.Deduplicate {
-		l.writtenLock.Lock()
-		defer l.writtenLock.Unlock()
-
-		if _, alreadyLogged := l.written[message]; alreadyLogged {
+	if l.opts.Deduplicate && !l.shouldWrite(message) {
+		metrics.SuppressedKubeAPIWarnings.Inc()
+		return
+	}
+	for _, substr := range l.opts.ErrorSubstrings {
+		if strings.Contains(message, substr) {
+			l.logger.Error(nil, message)
 			return
 		}
-		l.written[message] = struct{}{}
 	}
 	l.logger.Info(message)
 }
 
+// shouldWrite returns whether the deduplicated message should be written,
+// recording that it is.
+func (l *KubeAPIWarningLogger) shouldWrite(message string) bool {
+	l.writtenLock.Lock()
+	defer l.writtenLock.Unlock()
+
+	now := l.now()
+	ttl := l.opts.DeduplicateTTL
+	if writtenAt, alreadyLogged := l.written[message]; alreadyLogged && (ttl <= 0 || now.Sub(writtenAt) < ttl) {
+		return false
+	}
+	l.written[message] = now
+
+	if ttl > 0 && now.Sub(l.lastPruned) >= ttl {
+		for m, writtenAt := range l.written {
+			if now.Sub(writtenAt) >= ttl {
+				delete(l.written, m)
+			}
+		}
+		l.lastPruned = now
+	}
+	return true
+}
+
 // NewKubeAPIWarningLogger returns an implementation of rest.WarningHandler that logs warnings
-// with code = 299 to the provided logr.Logger.
+// with code = 299 to the provided logr.Logger. Warnings that are not logged because of
+// deduplication are counted by the controller_runtime_kube_api_warnings_suppressed_total metric.
 func NewKubeAPIWarningLogger(l logr.Logger, opts KubeAPIWarningLoggerOptions) *KubeAPIWarningLogger {
-	h := &KubeAPIWarningLogger{logger: l, opts: opts}
+	h := &KubeAPIWarningLogger{logger: l, opts: opts, now: time.Now}
 	if opts.Deduplicate {
-		h.written = map[string]struct{}{}
+		h.written = map[string]time.Time{}
 	}
 	return h
 }
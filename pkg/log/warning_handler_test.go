@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"sigs.k8s.io/controller-runtime/pkg/internal/metrics"
+)
+
+var _ = Describe("KubeAPIWarningLogger", func() {
+	const deprecated = "v1beta1 Foo is deprecated in v1.25+, unavailable in v1.26+; use v1 Foo"
+
+	var (
+		logged []string
+		now    time.Time
+	)
+
+	newLogger := func(opts KubeAPIWarningLoggerOptions) *KubeAPIWarningLogger {
+		logged = nil
+		l := NewKubeAPIWarningLogger(funcr.New(func(prefix, args string) {
+			logged = append(logged, args)
+		}, funcr.Options{}), opts)
+		now = time.Now()
+		l.now = func() time.Time { return now }
+		return l
+	}
+
+	It("should log every warning if not deduplicating", func() {
+		l := newLogger(KubeAPIWarningLoggerOptions{})
+		for i := 0; i < 3; i++ {
+			l.HandleWarningHeader(299, "", deprecated)
+		}
+		Expect(logged).To(HaveLen(3))
+	})
+
+	It("should ignore warnings without the 299 code or a message", func() {
+		l := newLogger(KubeAPIWarningLoggerOptions{})
+		l.HandleWarningHeader(199, "", deprecated)
+		l.HandleWarningHeader(299, "", "")
+		Expect(logged).To(BeEmpty())
+	})
+
+	It("should log a warning once if deduplicating without a TTL", func() {
+		l := newLogger(KubeAPIWarningLoggerOptions{Deduplicate: true})
+		suppressed := testutil.ToFloat64(metrics.SuppressedKubeAPIWarnings)
+
+		for i := 0; i < 3; i++ {
+			l.HandleWarningHeader(299, "", deprecated)
+			now = now.Add(24 * time.Hour)
+		}
+		l.HandleWarningHeader(299, "", "another warning")
+
+		Expect(logged).To(HaveLen(2))
+		Expect(testutil.ToFloat64(metrics.SuppressedKubeAPIWarnings)).To(Equal(suppressed + 2))
+	})
+
+	It("should log a warning again once the TTL has passed", func() {
+		l := newLogger(KubeAPIWarningLoggerOptions{Deduplicate: true, DeduplicateTTL: time.Hour})
+		suppressed := testutil.ToFloat64(metrics.SuppressedKubeAPIWarnings)
+
+		l.HandleWarningHeader(299, "", deprecated)
+		now = now.Add(30 * time.Minute)
+		l.HandleWarningHeader(299, "", deprecated)
+		Expect(logged).To(HaveLen(1))
+
+		now = now.Add(30 * time.Minute)
+		l.HandleWarningHeader(299, "", deprecated)
+		Expect(logged).To(HaveLen(2))
+		Expect(testutil.ToFloat64(metrics.SuppressedKubeAPIWarnings)).To(Equal(suppressed + 1))
+	})
+
+	It("should forget expired warnings", func() {
+		l := newLogger(KubeAPIWarningLoggerOptions{Deduplicate: true, DeduplicateTTL: time.Hour})
+
+		l.HandleWarningHeader(299, "", deprecated)
+		now = now.Add(2 * time.Hour)
+		l.HandleWarningHeader(299, "", "another warning")
+		Expect(l.written).To(HaveLen(1))
+		Expect(l.written).To(HaveKey("another warning"))
+	})
+
+	It("should escalate warnings with the configured substrings to errors", func() {
+		l := newLogger(KubeAPIWarningLoggerOptions{ErrorSubstrings: []string{"unavailable in"}})
+
+		l.HandleWarningHeader(299, "", deprecated)
+		l.HandleWarningHeader(299, "", "spec.foo is deprecated")
+
+		Expect(logged).To(HaveLen(2))
+		Expect(logged[0]).To(ContainSubstring(`"error"=null`))
+		Expect(logged[1]).NotTo(ContainSubstring(`"error"`))
+	})
+})
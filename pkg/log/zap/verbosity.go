@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zap
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// verbositySink is a logr.LogSink created by zapr whose verbosity can be changed
+// through the zap.AtomicLevel of its core. It implements log.VerbosityChanger.
+type verbositySink struct {
+	logr.LogSink
+	level zap.AtomicLevel
+}
+
+// Init implements logr.LogSink. The wrapped sink has already been initialized
+// by zapr.NewLogger, initializing it again would skip another caller frame.
+func (s *verbositySink) Init(logr.RuntimeInfo) {}
+
+// Verbosity returns the highest V level that is logged. It is negative if
+// not even V(0) messages are logged, e.g. -2 for zap's error level.
+func (s *verbositySink) Verbosity() int {
+	return -int(s.level.Level())
+}
+
+// SetVerbosity sets the highest V level that is logged.
+func (s *verbositySink) SetVerbosity(level int) error {
+	if level < 0 || level > -math.MinInt8 {
+		return fmt.Errorf("verbosity %d is out of range [0, %d]", level, -math.MinInt8)
+	}
+	s.level.SetLevel(zapcore.Level(-level))
+	return nil
+}
+
+// WithValues implements logr.LogSink.
+func (s *verbositySink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &verbositySink{LogSink: s.LogSink.WithValues(keysAndValues...), level: s.level}
+}
+
+// WithName implements logr.LogSink.
+func (s *verbositySink) WithName(name string) logr.LogSink {
+	return &verbositySink{LogSink: s.LogSink.WithName(name), level: s.level}
+}
+
+// WithCallDepth implements logr.CallDepthLogSink.
+func (s *verbositySink) WithCallDepth(depth int) logr.LogSink {
+	if withCallDepth, ok := s.LogSink.(logr.CallDepthLogSink); ok {
+		return &verbositySink{LogSink: withCallDepth.WithCallDepth(depth), level: s.level}
+	}
+	return s
+}
+
+// GetUnderlying implements zapr.Underlier.
+func (s *verbositySink) GetUnderlying() *zap.Logger {
+	return s.LogSink.(zapr.Underlier).GetUnderlying()
+}
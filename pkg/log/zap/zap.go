@@ -39,8 +39,23 @@ type NewEncoderFunc func(...EncoderConfigOption) zapcore.Encoder
 // New returns a brand new Logger configured with Opts. It
 // uses KubeAwareEncoder which adds Type information and
 // Namespace/Name to the log.
+//
+// If the Level is a zap.AtomicLevel, which it is by default, the
+// sink of the logger implements log.VerbosityChanger.
 func New(opts ...Opts) logr.Logger {
-	return zapr.NewLogger(NewRaw(opts...))
+	o := newOptions(opts...)
+	logger := zapr.NewLogger(newRaw(o))
+
+	var level zap.AtomicLevel
+	switch l := o.Level.(type) {
+	case zap.AtomicLevel:
+		level = l
+	case *zap.AtomicLevel:
+		level = *l
+	default:
+		return logger
+	}
+	return logr.New(&verbositySink{LogSink: logger.GetSink(), level: level})
 }
 
 // Opts allows to manipulate Options.
@@ -235,12 +250,20 @@ func (o *Options) addDefaults() {
 // or their defaults. It uses KubeAwareEncoder which adds Type
 // information and Namespace/Name to the log.
 func NewRaw(opts ...Opts) *zap.Logger {
+	return newRaw(newOptions(opts...))
+}
+
+// newOptions returns the Options configured with opts, with defaults added.
+func newOptions(opts ...Opts) *Options {
 	o := &Options{}
 	for _, opt := range opts {
 		opt(o)
 	}
 	o.addDefaults()
+	return o
+}
 
+func newRaw(o *Options) *zap.Logger {
 	// this basically mimics New<type>Config, but with a custom sink
 	sink := zapcore.AddSync(o.DestWriter)
 
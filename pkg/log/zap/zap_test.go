@@ -30,6 +30,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // testStringer is a fmt.Stringer.
@@ -598,5 +600,43 @@ var _ = Describe("Zap log level flag options setup", func() {
 				Expect(logOut.String()).To(BeEmpty())
 			})
 		})
+
+		Context("changing the verbosity", func() {
+			var logOut *bytes.Buffer
+
+			BeforeEach(func() {
+				logOut = new(bytes.Buffer)
+			})
+
+			It("changes the verbosity of the logger and the loggers derived from it", func() {
+				logger := New(WriteTo(logOut))
+				derived := logger.WithName("derived").WithValues("key", "value")
+
+				vc, ok := logf.VerbosityChangerFor(logger)
+				Expect(ok).To(BeTrue())
+				Expect(vc.Verbosity()).To(Equal(0))
+
+				derived.V(4).Info("test 4")
+				Expect(logOut.String()).To(BeEmpty())
+
+				Expect(vc.SetVerbosity(4)).To(Succeed())
+				Expect(vc.Verbosity()).To(Equal(4))
+				derived.V(4).Info("test 4")
+				Expect(logOut.String()).To(ContainSubstring(`"msg":"test 4"`))
+				Expect(logOut.String()).To(ContainSubstring(`"logger":"derived"`))
+			})
+
+			It("does not support changing the verbosity of levels that are not atomic", func() {
+				_, ok := logf.VerbosityChangerFor(New(WriteTo(logOut), Level(zapcore.Level(-3))))
+				Expect(ok).To(BeFalse())
+			})
+
+			It("rejects verbosities that are out of range", func() {
+				vc, ok := logf.VerbosityChangerFor(New(WriteTo(logOut)))
+				Expect(ok).To(BeTrue())
+				Expect(vc.SetVerbosity(-1)).NotTo(Succeed())
+				Expect(vc.SetVerbosity(1000)).NotTo(Succeed())
+			})
+		})
 	})
 })
@@ -0,0 +1,224 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+)
+
+// engagedCluster is a cluster engaged by the manager at runtime.
+type engagedCluster struct {
+	cluster cluster.Cluster
+	// ctx is the context the cluster runs with, which is passed to the runnables it's
+	// engaged with, and cancel stops it.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// stopped is closed once the cluster stopped.
+	stopped chan struct{}
+	// engaged is true once the cache of the cluster synced and the runnables were engaged
+	// with it.
+	engaged bool
+}
+
+// Engage implements cluster.Aware.
+func (cm *controllerManager) Engage(ctx context.Context, name string, cl cluster.Cluster) error {
+	if name == "" {
+		return errors.New("the name of an engaged cluster must not be empty")
+	}
+
+	cm.clustersMu.Lock()
+	if cm.clustersCtx == nil {
+		cm.clustersMu.Unlock()
+		return errors.New("the manager must be started before engaging clusters")
+	}
+	if _, ok := cm.clusters[name]; ok {
+		cm.clustersMu.Unlock()
+		return fmt.Errorf("cluster %q is already engaged", name)
+	}
+	clusterCtx, cancel := context.WithCancel(cm.clustersCtx)
+	ec := &engagedCluster{cluster: cl, ctx: clusterCtx, cancel: cancel, stopped: make(chan struct{})}
+	if cm.clusters == nil {
+		cm.clusters = map[string]*engagedCluster{}
+	}
+	cm.clusters[name] = ec
+	cm.clustersMu.Unlock()
+
+	log := cm.logger.WithValues("cluster", name)
+	log.Info("Starting engaged cluster")
+	go func() {
+		defer close(ec.stopped)
+		if err := cl.Start(clusterCtx); err != nil {
+			log.Error(err, "Engaged cluster stopped with an error")
+		}
+	}()
+
+	// The lock isn't held while waiting for the cache, so that other clusters can be
+	// engaged and disengaged meanwhile.
+	if !cl.GetCache().WaitForCacheSync(ctx) {
+		cm.clustersMu.Lock()
+		if cm.clusters[name] == ec {
+			delete(cm.clusters, name)
+		}
+		cm.clustersMu.Unlock()
+		cancel()
+		<-ec.stopped
+		return fmt.Errorf("failed to wait for the cache of cluster %q to sync", name)
+	}
+
+	cm.clustersMu.Lock()
+	defer cm.clustersMu.Unlock()
+	if cm.clusters[name] != ec {
+		return fmt.Errorf("cluster %q was disengaged while it was engaged", name)
+	}
+	for i, aware := range cm.awares {
+		if err := aware.Engage(clusterCtx, name, cl); err != nil {
+			for _, engaged := range cm.awares[:i] {
+				if err := engaged.Disengage(ctx, name); err != nil {
+					log.Error(err, "Failed to disengage cluster after a failed engagement")
+				}
+			}
+			delete(cm.clusters, name)
+			cancel()
+			return fmt.Errorf("failed to engage cluster %q: %w", name, err)
+		}
+	}
+	ec.engaged = true
+	log.Info("Engaged cluster")
+	return nil
+}
+
+// Disengage implements cluster.Aware. It waits for the cluster to stop until the
+// context is done.
+func (cm *controllerManager) Disengage(ctx context.Context, name string) error {
+	cm.clustersMu.Lock()
+	ec, ok := cm.clusters[name]
+	if !ok {
+		cm.clustersMu.Unlock()
+		return fmt.Errorf("cluster %q is not engaged", name)
+	}
+	delete(cm.clusters, name)
+	var errs []error
+	if ec.engaged {
+		for _, aware := range cm.awares {
+			if err := aware.Disengage(ctx, name); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	cm.clustersMu.Unlock()
+
+	ec.cancel()
+	select {
+	case <-ec.stopped:
+		cm.logger.Info("Disengaged cluster", "cluster", name)
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("failed waiting for cluster %q to stop: %w", name, ctx.Err()))
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// GetCluster implements Manager.
+func (cm *controllerManager) GetCluster(name string) (cluster.Cluster, error) {
+	if name == "" {
+		return cm.cluster, nil
+	}
+	cm.clustersMu.Lock()
+	defer cm.clustersMu.Unlock()
+	if ec, ok := cm.clusters[name]; ok && ec.engaged {
+		return ec.cluster, nil
+	}
+	return nil, fmt.Errorf("cluster %q is not engaged", name)
+}
+
+// addAware engages the runnable with the engaged clusters, and makes the manager engage
+// it with the clusters that are engaged later on.
+func (cm *controllerManager) addAware(aware cluster.Aware) error {
+	cm.clustersMu.Lock()
+	defer cm.clustersMu.Unlock()
+	for name, ec := range cm.clusters {
+		if !ec.engaged {
+			continue
+		}
+		if err := aware.Engage(ec.ctx, name, ec.cluster); err != nil {
+			return fmt.Errorf("failed to engage cluster %q: %w", name, err)
+		}
+	}
+	cm.awares = append(cm.awares, aware)
+	return nil
+}
+
+// removeAware disengages the runnable from the engaged clusters, and makes the manager
+// stop engaging it. It returns false if the runnable wasn't added.
+func (cm *controllerManager) removeAware(ctx context.Context, aware cluster.Aware) bool {
+	cm.clustersMu.Lock()
+	defer cm.clustersMu.Unlock()
+	for i, existing := range cm.awares {
+		if !sameRunnable(existing, aware) {
+			continue
+		}
+		cm.awares = append(cm.awares[:i], cm.awares[i+1:]...)
+		for name, ec := range cm.clusters {
+			if !ec.engaged {
+				continue
+			}
+			if err := aware.Disengage(ctx, name); err != nil {
+				cm.logger.Error(err, "Failed to disengage removed runnable", "cluster", name)
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// waitForEngagedClusters waits until the engaged clusters stopped, or the context is done.
+func (cm *controllerManager) waitForEngagedClusters(ctx context.Context) {
+	cm.clustersMu.Lock()
+	stopped := make([]chan struct{}, 0, len(cm.clusters))
+	for _, ec := range cm.clusters {
+		stopped = append(stopped, ec.stopped)
+	}
+	cm.clustersMu.Unlock()
+
+	for _, ch := range stopped {
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// clusterProviderRunnable runs the cluster provider of the manager. It runs on every
+// replica, as the clusters must be engaged before the controllers are elected.
+type clusterProviderRunnable struct {
+	provider cluster.Provider
+	aware    cluster.Aware
+}
+
+func (r *clusterProviderRunnable) Start(ctx context.Context) error {
+	return r.provider.Run(ctx, r.aware)
+}
+
+func (r *clusterProviderRunnable) NeedLeaderElection() bool {
+	return false
+}
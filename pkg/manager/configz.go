@@ -0,0 +1,195 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// Configz is the effective configuration of a manager, which is served as JSON on the
+// ConfigzEndpointName of the health probe server.
+type Configz struct {
+	// Cache is the configuration of the cache of the manager.
+	Cache CacheConfigz `json:"cache"`
+
+	// LeaderElection is the configuration of the leader election of the manager.
+	LeaderElection LeaderElectionConfigz `json:"leaderElection"`
+
+	// Controllers are the configurations of the controllers added to the manager.
+	Controllers []ControllerConfigz `json:"controllers"`
+
+	// MetricsBindAddress is the address the metrics are served on, empty if they're not.
+	MetricsBindAddress string `json:"metricsBindAddress,omitempty"`
+
+	// HealthProbeBindAddress is the address the health probes are served on.
+	HealthProbeBindAddress string `json:"healthProbeBindAddress,omitempty"`
+
+	// PprofBindAddress is the address pprof is served on, empty if it's not.
+	PprofBindAddress string `json:"pprofBindAddress,omitempty"`
+
+	// GracefulShutdownTimeout is the duration given to the Runnables to stop.
+	GracefulShutdownTimeout metav1.Duration `json:"gracefulShutdownTimeout"`
+}
+
+// CacheConfigz is the configuration of the cache of a manager.
+type CacheConfigz struct {
+	// Namespaces are the namespaces the cache is restricted to, empty if it watches
+	// all the namespaces.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// SyncPeriod is the resync period of the informers of the cache, nil if it's the
+	// default one of the cache.
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+
+	// Lazy is whether the cache is started once it's needed.
+	Lazy bool `json:"lazy,omitempty"`
+}
+
+// LeaderElectionConfigz is the configuration of the leader election of a manager.
+type LeaderElectionConfigz struct {
+	// Enabled is whether the manager uses leader election.
+	Enabled bool `json:"enabled"`
+
+	// Lock describes the resource lock, e.g. its namespace and name.
+	Lock string `json:"lock,omitempty"`
+
+	// Identity is the identity of the manager in the leader election.
+	Identity string `json:"identity,omitempty"`
+
+	// LeaseDuration, RenewDeadline and RetryPeriod are the timings of the leader election.
+	LeaseDuration metav1.Duration `json:"leaseDuration,omitempty"`
+	RenewDeadline metav1.Duration `json:"renewDeadline,omitempty"`
+	RetryPeriod   metav1.Duration `json:"retryPeriod,omitempty"`
+
+	// ReleaseOnCancel is whether the manager releases the lease when it's stopped.
+	ReleaseOnCancel bool `json:"releaseOnCancel,omitempty"`
+}
+
+// ControllerConfigz is the configuration of a controller added to a manager.
+type ControllerConfigz struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciliations of
+	// the controller.
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles"`
+
+	// RecoverPanic is whether the panics of the reconciler are recovered.
+	RecoverPanic bool `json:"recoverPanic,omitempty"`
+
+	// CacheSyncTimeout is how long the controller waits for its sources to sync.
+	CacheSyncTimeout metav1.Duration `json:"cacheSyncTimeout,omitempty"`
+
+	// Paused is whether the controller is paused.
+	Paused bool `json:"paused,omitempty"`
+}
+
+// configzRunnable is a Runnable that reports its configuration, such as a controller.
+type configzRunnable interface {
+	Runnable
+	Configz() ControllerConfigz
+}
+
+// controllersConfigz returns the configurations of the controllers of the group.
+func (r *runnableGroup) controllersConfigz() []ControllerConfigz {
+	r.start.Lock()
+	defer r.start.Unlock()
+	var configz []ControllerConfigz
+	for _, rn := range r.tracked {
+		if c, ok := rn.Runnable.(configzRunnable); ok {
+			configz = append(configz, c.Configz())
+		}
+	}
+	return configz
+}
+
+// controllersConfigz returns the configurations of the controllers, in the order their
+// groups are started. The warmup of the controllers is not included.
+func (r *runnables) controllersConfigz() []ControllerConfigz {
+	var configz []ControllerConfigz
+	configz = append(configz, r.Others.controllersConfigz()...)
+	configz = append(configz, r.LeaderElection.controllersConfigz()...)
+	for _, group := range r.sortedLeaderElectionGroups() {
+		configz = append(configz, group.controllersConfigz()...)
+	}
+	return configz
+}
+
+// configz returns the effective configuration of the manager.
+func (cm *controllerManager) configz() Configz {
+	configz := cm.staticConfigz
+	configz.Controllers = cm.runnables.controllersConfigz()
+	if namespaced, ok := cm.GetCache().(cache.NamespaceScopedCache); ok {
+		configz.Cache.Namespaces = namespaced.Namespaces()
+	}
+	if cm.resourceLock != nil {
+		configz.LeaderElection.Enabled = true
+		configz.LeaderElection.Lock = cm.resourceLock.Describe()
+		configz.LeaderElection.Identity = cm.resourceLock.Identity()
+	}
+	return configz
+}
+
+// configzHandler serves the effective configuration of the manager as JSON.
+func (cm *controllerManager) configzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(cm.configz()); err != nil {
+			cm.logger.Error(err, "unable to write the configuration of the manager")
+		}
+	})
+}
+
+// newStaticConfigz returns the configuration of a manager that doesn't change once it's
+// created, from its options.
+func newStaticConfigz(options Options, metricsListener, healthProbeListener, pprofListener net.Listener) Configz {
+	configz := Configz{
+		Cache: CacheConfigz{
+			Lazy: options.LazyCacheStart,
+		},
+		LeaderElection: LeaderElectionConfigz{
+			LeaseDuration:   metav1.Duration{Duration: *options.LeaseDuration},
+			RenewDeadline:   metav1.Duration{Duration: *options.RenewDeadline},
+			RetryPeriod:     metav1.Duration{Duration: *options.RetryPeriod},
+			ReleaseOnCancel: options.LeaderElectionReleaseOnCancel,
+		},
+		MetricsBindAddress:      listenerAddress(metricsListener),
+		HealthProbeBindAddress:  listenerAddress(healthProbeListener),
+		PprofBindAddress:        listenerAddress(pprofListener),
+		GracefulShutdownTimeout: metav1.Duration{Duration: *options.GracefulShutdownTimeout},
+	}
+	if options.Namespace != "" {
+		configz.Cache.Namespaces = []string{options.Namespace}
+	}
+	if options.SyncPeriod != nil {
+		configz.Cache.SyncPeriod = &metav1.Duration{Duration: *options.SyncPeriod}
+	}
+	return configz
+}
+
+func listenerAddress(ln net.Listener) string {
+	if ln == nil {
+		return ""
+	}
+	return ln.Addr().String()
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RunnableError is an error returned by a Runnable of the manager.
+type RunnableError struct {
+	// Runnable is the name of the Runnable, i.e. its type.
+	Runnable string
+	// Err is the error returned by the Runnable.
+	Err error
+}
+
+func newRunnableError(rn Runnable, err error) *RunnableError {
+	return &RunnableError{Runnable: fmt.Sprintf("%T", rn), Err: err}
+}
+
+// Error implements error.
+func (e *RunnableError) Error() string {
+	return fmt.Sprintf("runnable %s: %v", e.Runnable, e.Err)
+}
+
+// Unwrap returns the error returned by the Runnable.
+func (e *RunnableError) Unwrap() error {
+	return e.Err
+}
+
+// ShutdownError is the error returned by Start when the manager stopped because of
+// errors, or failed to stop. It lists the error that made the manager stop, followed
+// by the errors returned while it was stopping, e.g. by Runnables that failed to stop
+// or that didn't return within the graceful shutdown timeout.
+//
+// The errors returned by Runnables are RunnableErrors, so that callers can tell which
+// Runnable failed with errors.As, and errors.Is and errors.As match any of the errors.
+type ShutdownError struct {
+	Errors []error
+}
+
+// Error implements error.
+func (e *ShutdownError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return "[" + strings.Join(msgs, ", ") + "]"
+}
+
+// Is returns whether any of the errors is the target, for errors.Is.
+func (e *ShutdownError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches the target, for errors.As.
+func (e *ShutdownError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunnableErrors returns the errors returned by Runnables.
+func (e *ShutdownError) RunnableErrors() []*RunnableError {
+	var errs []*RunnableError
+	for _, err := range e.Errors {
+		var rerr *RunnableError
+		if errors.As(err, &rerr) {
+			errs = append(errs, rerr)
+		}
+	}
+	return errs
+}
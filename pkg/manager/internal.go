@@ -23,15 +23,16 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/pprof"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
-	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection"
@@ -42,9 +43,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/budget"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
+	crleaderelection "sigs.k8s.io/controller-runtime/pkg/leaderelection"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -60,6 +63,9 @@ const (
 	defaultReadinessEndpoint = "/readyz"
 	defaultLivenessEndpoint  = "/healthz"
 	defaultMetricsEndpoint   = "/metrics"
+
+	defaultLeaderElectionHealthzCheckName = "leaderElection"
+	cacheSyncReadyzCheckName              = "cacheSync"
 )
 
 var _ Runnable = &controllerManager{}
@@ -86,21 +92,56 @@ type controllerManager struct {
 	// on shutdown
 	leaderElectionReleaseOnCancel bool
 
+	// leaderElectionCallbacks are called in addition to the manager's own leader election callbacks.
+	leaderElectionCallbacks crleaderelection.Callbacks
+
+	// checkLeaderElectionLock makes the manager fail to start if it can't read the leader
+	// election lock, as it's in another cluster than the one that is reconciled.
+	checkLeaderElectionLock bool
+
+	// leaderElectionWatchDog fails the healthz check of the leader election if the leader
+	// fails to renew its lease without stepping down, nil if the check is disabled.
+	leaderElectionWatchDog *leaderelection.HealthzAdaptor
+
 	// metricsListener is used to serve prometheus metrics
 	metricsListener net.Listener
 
+	// metricsRegistry is the registry the metrics of the controllers and the webhook server report into.
+	metricsRegistry metrics.RegistererGatherer
+
 	// metricsExtraHandlers contains extra handlers to register on http server that serves metrics.
 	metricsExtraHandlers map[string]http.Handler
 
+	// metricsFilter wraps the handlers of the metrics endpoint, nil if they're not filtered.
+	metricsFilter metrics.Filter
+
+	// metricsTLS is the TLS configuration the metrics are served with, nil if they're served over HTTP.
+	metricsTLS *metricsTLSConfig
+
 	// healthProbeListener is used to serve liveness probe
 	healthProbeListener net.Listener
 
+	// pprofListener is used to serve pprof
+	pprofListener net.Listener
+
 	// Readiness probe endpoint name
 	readinessEndpointName string
 
 	// Liveness probe endpoint name
 	livenessEndpointName string
 
+	// Status endpoint name, which is not served if empty
+	statusEndpointName string
+
+	// Configz endpoint name, which is not served if empty
+	configzEndpointName string
+
+	// staticConfigz is the configuration of the manager that doesn't change once it's created.
+	staticConfigz Configz
+
+	// healthProbeIncludeErrors makes the health probes include the errors of failed checks.
+	healthProbeIncludeErrors bool
+
 	// Readyz probe handler
 	readyzHandler *healthz.Handler
 
@@ -110,6 +151,10 @@ type controllerManager struct {
 	// controllerOptions are the global controller options.
 	controllerOptions v1alpha1.ControllerConfigurationSpec
 
+	// reconcileBudget caps the concurrent reconciliations of the controllers, if
+	// controllerOptions.MaxConcurrentReconcilesTotal is set.
+	reconcileBudget *budget.Budget
+
 	// Logger is the logger that should be used by this manager.
 	// If none is set, it defaults to log.Log global logger.
 	logger logr.Logger
@@ -138,6 +183,8 @@ type controllerManager struct {
 	certDir string
 	// tlsOpts is used to allow configuring the TLS config used for the webhook server.
 	tlsOpts []func(*tls.Config)
+	// webhookListener is the listener of the webhook server, if not nil.
+	webhookListener net.Listener
 
 	webhookServer *webhook.Server
 	// webhookServerOnce will be called in GetWebhookServer() to optionally initialize
@@ -173,6 +220,30 @@ type controllerManager struct {
 	// internalProceduresStop channel is used internally to the manager when coordinating
 	// the proper shutdown of servers. This channel is also used for dependency injection.
 	internalProceduresStop chan struct{}
+
+	// clusterProvider engages and disengages clusters while the manager runs, if set.
+	clusterProvider cluster.Provider
+
+	// clustersMu guards the fields below, which are about the clusters engaged at runtime.
+	clustersMu sync.Mutex
+	// clustersCtx is the context the engaged clusters run with, nil until the manager starts.
+	clustersCtx context.Context
+	// clusters are the engaged clusters by name.
+	clusters map[string]*engagedCluster
+	// awares are the runnables that are engaged with the clusters.
+	awares []cluster.Aware
+
+	// newLeaderElectionGroupLock creates the lock of a leader election group from its
+	// lease name, nil without leader election.
+	newLeaderElectionGroupLock func(name string) (resourcelock.Interface, error)
+
+	// leaderElectionGroupsMu guards the fields below, which are about the leader election groups.
+	leaderElectionGroupsMu sync.Mutex
+	// leaderElectionGroupsStarted is set while the leader election groups are started.
+	leaderElectionGroupsStarted bool
+	// leaderElectionGroups are the leader electors of the leader election groups by lease
+	// name, nil for the groups started without leader election.
+	leaderElectionGroups map[string]*leaderElectionGroupElector
 }
 
 type hasCache interface {
@@ -181,18 +252,44 @@ type hasCache interface {
 }
 
 // Add sets dependencies on i, and adds it to the list of Runnables to start.
-func (cm *controllerManager) Add(r Runnable) error {
+func (cm *controllerManager) Add(r Runnable, opts ...AddOption) error {
 	cm.Lock()
 	defer cm.Unlock()
-	return cm.add(r)
+	return cm.add(r, opts...)
 }
 
-func (cm *controllerManager) add(r Runnable) error {
+func (cm *controllerManager) add(r Runnable, opts ...AddOption) error {
 	// Set dependencies on the object
 	if err := cm.SetFields(r); err != nil {
 		return err
 	}
-	return cm.runnables.Add(r)
+	if aware, ok := r.(cluster.Aware); ok {
+		if err := cm.addAware(aware); err != nil {
+			return err
+		}
+	}
+	if err := cm.runnables.Add(r, opts...); err != nil {
+		return err
+	}
+	return cm.addToLeaderElectionGroup(opts...)
+}
+
+// Remove implements Manager.
+func (cm *controllerManager) Remove(r Runnable) error {
+	cm.Lock()
+	defer cm.Unlock()
+
+	var found bool
+	if aware, ok := r.(cluster.Aware); ok {
+		found = cm.removeAware(context.Background(), aware)
+	}
+	if cm.runnables.Remove(r) {
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("runnable %T was not added to the manager, or already returned", r)
+	}
+	return nil
 }
 
 // Deprecated: use the equivalent Options field to set a field. This method will be removed in v0.10.
@@ -240,32 +337,54 @@ func (cm *controllerManager) AddHealthzCheck(name string, check healthz.Checker)
 	cm.Lock()
 	defer cm.Unlock()
 
-	if cm.started {
-		return fmt.Errorf("unable to add new checker because healthz endpoint has already been created")
-	}
-
 	if cm.healthzHandler == nil {
-		cm.healthzHandler = &healthz.Handler{Checks: map[string]healthz.Checker{}}
+		cm.healthzHandler = &healthz.Handler{Checks: map[string]healthz.Checker{}, IncludeErrors: cm.healthProbeIncludeErrors}
 	}
 
-	cm.healthzHandler.Checks[name] = check
+	cm.healthzHandler.AddCheck(name, check)
 	return nil
 }
 
-// AddReadyzCheck allows you to add Readyz checker.
-func (cm *controllerManager) AddReadyzCheck(name string, check healthz.Checker) error {
+// RemoveHealthzCheck removes the Healthz checker of the given name.
+func (cm *controllerManager) RemoveHealthzCheck(name string) error {
 	cm.Lock()
 	defer cm.Unlock()
 
-	if cm.started {
-		return fmt.Errorf("unable to add new checker because healthz endpoint has already been created")
+	if cm.healthzHandler == nil || !cm.healthzHandler.RemoveCheck(name) {
+		return fmt.Errorf("healthz check %q was not added to the manager", name)
 	}
+	return nil
+}
+
+// addLeaderElectionHealthzCheck adds a healthz check with the given name that fails if
+// the leader did not renew its lease in time without stepping down.
+func (cm *controllerManager) addLeaderElectionHealthzCheck(name string) error {
+	// Allow the lease to be expired for as long as the leader may try to renew it.
+	cm.leaderElectionWatchDog = leaderelection.NewLeaderHealthzAdaptor(cm.renewDeadline)
+	return cm.AddHealthzCheck(name, cm.leaderElectionWatchDog.Check)
+}
+
+// AddReadyzCheck allows you to add Readyz checker.
+func (cm *controllerManager) AddReadyzCheck(name string, check healthz.Checker) error {
+	cm.Lock()
+	defer cm.Unlock()
 
 	if cm.readyzHandler == nil {
-		cm.readyzHandler = &healthz.Handler{Checks: map[string]healthz.Checker{}}
+		cm.readyzHandler = &healthz.Handler{Checks: map[string]healthz.Checker{}, IncludeErrors: cm.healthProbeIncludeErrors}
 	}
 
-	cm.readyzHandler.Checks[name] = check
+	cm.readyzHandler.AddCheck(name, check)
+	return nil
+}
+
+// RemoveReadyzCheck removes the Readyz checker of the given name.
+func (cm *controllerManager) RemoveReadyzCheck(name string) error {
+	cm.Lock()
+	defer cm.Unlock()
+
+	if cm.readyzHandler == nil || !cm.readyzHandler.RemoveCheck(name) {
+		return fmt.Errorf("readyz check %q was not added to the manager", name)
+	}
 	return nil
 }
 
@@ -305,10 +424,13 @@ func (cm *controllerManager) GetWebhookServer() *webhook.Server {
 	cm.webhookServerOnce.Do(func() {
 		if cm.webhookServer == nil {
 			cm.webhookServer = &webhook.Server{
-				Port:    cm.port,
-				Host:    cm.host,
-				CertDir: cm.certDir,
-				TLSOpts: cm.tlsOpts,
+				Port:     cm.port,
+				Host:     cm.host,
+				CertDir:  cm.certDir,
+				TLSOpts:  cm.tlsOpts,
+				Listener: cm.webhookListener,
+
+				MetricsRegistry: cm.metricsRegistry,
 			}
 		}
 		if err := cm.Add(cm.webhookServer); err != nil {
@@ -326,9 +448,22 @@ func (cm *controllerManager) GetControllerOptions() v1alpha1.ControllerConfigura
 	return cm.controllerOptions
 }
 
-func (cm *controllerManager) serveMetrics() {
-	handler := promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{
+// GetReconcileBudget returns the budget shared by the controllers of the manager, or nil
+// if Options.Controller.MaxConcurrentReconcilesTotal isn't set.
+func (cm *controllerManager) GetReconcileBudget() *budget.Budget {
+	return cm.reconcileBudget
+}
+
+func (cm *controllerManager) GetMetricsRegistry() metrics.RegistererGatherer {
+	return cm.metricsRegistry
+}
+
+func (cm *controllerManager) serveMetrics() error {
+	log := cm.logger.WithValues("path", defaultMetricsEndpoint)
+	handler := promhttp.HandlerFor(cm.metricsRegistry, promhttp.HandlerOpts{
 		ErrorHandling: promhttp.HTTPErrorOnError,
+		// Serve the OpenMetrics format if it is negotiated, so that exemplars are exposed.
+		EnableOpenMetrics: true,
 	})
 	// TODO(JoelSpeed): Use existing Kubernetes machinery for serving metrics
 	mux := http.NewServeMux()
@@ -337,28 +472,62 @@ func (cm *controllerManager) serveMetrics() {
 		mux.Handle(path, extraHandler)
 	}
 
-	server := httpserver.New(mux)
-	go cm.httpServe("metrics", cm.logger.WithValues("path", defaultMetricsEndpoint), server, cm.metricsListener)
+	var root http.Handler = mux
+	if cm.metricsFilter != nil {
+		var err error
+		if root, err = cm.metricsFilter(log, mux); err != nil {
+			return fmt.Errorf("failed to filter the metrics endpoint: %w", err)
+		}
+	}
+
+	server := httpserver.New(root)
+	ln := cm.metricsListener
+	if cm.metricsTLS != nil {
+		ln = cm.metricsTLS.listen(cm.internalCtx, log, server, ln)
+	}
+	go cm.httpServe("metrics", log, server, ln)
+	return nil
 }
 
 func (cm *controllerManager) serveHealthProbes() {
 	mux := http.NewServeMux()
 	server := httpserver.New(mux)
 
-	if cm.readyzHandler != nil {
-		mux.Handle(cm.readinessEndpointName, http.StripPrefix(cm.readinessEndpointName, cm.readyzHandler))
-		// Append '/' suffix to handle subpaths
-		mux.Handle(cm.readinessEndpointName+"/", http.StripPrefix(cm.readinessEndpointName, cm.readyzHandler))
+	// The handlers are created even if no check was added yet, as checks can be added
+	// once the manager is started.
+	if cm.readyzHandler == nil {
+		cm.readyzHandler = &healthz.Handler{Checks: map[string]healthz.Checker{}, IncludeErrors: cm.healthProbeIncludeErrors}
+	}
+	if cm.healthzHandler == nil {
+		cm.healthzHandler = &healthz.Handler{Checks: map[string]healthz.Checker{}, IncludeErrors: cm.healthProbeIncludeErrors}
+	}
+	mux.Handle(cm.readinessEndpointName, http.StripPrefix(cm.readinessEndpointName, cm.readyzHandler))
+	// Append '/' suffix to handle subpaths
+	mux.Handle(cm.readinessEndpointName+"/", http.StripPrefix(cm.readinessEndpointName, cm.readyzHandler))
+	mux.Handle(cm.livenessEndpointName, http.StripPrefix(cm.livenessEndpointName, cm.healthzHandler))
+	// Append '/' suffix to handle subpaths
+	mux.Handle(cm.livenessEndpointName+"/", http.StripPrefix(cm.livenessEndpointName, cm.healthzHandler))
+	if cm.statusEndpointName != "" {
+		mux.Handle(cm.statusEndpointName, cm.statusHandler())
 	}
-	if cm.healthzHandler != nil {
-		mux.Handle(cm.livenessEndpointName, http.StripPrefix(cm.livenessEndpointName, cm.healthzHandler))
-		// Append '/' suffix to handle subpaths
-		mux.Handle(cm.livenessEndpointName+"/", http.StripPrefix(cm.livenessEndpointName, cm.healthzHandler))
+	if cm.configzEndpointName != "" {
+		mux.Handle(cm.configzEndpointName, cm.configzHandler())
 	}
 
 	go cm.httpServe("health probe", cm.logger, server, cm.healthProbeListener)
 }
 
+func (cm *controllerManager) addPprofServer() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return cm.add(NewHTTPServerRunnable(httpserver.New(mux), cm.pprofListener))
+}
+
 func (cm *controllerManager) httpServe(kind string, log logr.Logger, server *http.Server, ln net.Listener) {
 	log = log.WithValues("kind", kind, "addr", ln.Addr())
 
@@ -417,6 +586,9 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 
 	// Initialize the internal context.
 	cm.internalCtx, cm.internalCancel = context.WithCancel(ctx)
+	cm.clustersMu.Lock()
+	cm.clustersCtx = cm.internalCtx
+	cm.clustersMu.Unlock()
 
 	// This chan indicates that stop is complete, in other words all runnables have returned or timeout on stop request
 	stopComplete := make(chan struct{})
@@ -424,16 +596,17 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 	// This must be deferred after closing stopComplete, otherwise we deadlock.
 	defer func() {
 		// https://hips.hearstapps.com/hmg-prod.s3.amazonaws.com/images/gettyimages-459889618-1533579787.jpg
-		stopErr := cm.engageStopProcedure(stopComplete)
-		if stopErr != nil {
-			if err != nil {
-				// Utilerrors.Aggregate allows to use errors.Is for all contained errors
-				// whereas fmt.Errorf allows wrapping at most one error which means the
-				// other one can not be found anymore.
-				err = kerrors.NewAggregate([]error{err, stopErr})
-			} else {
-				err = stopErr
-			}
+		stopErrs := cm.engageStopProcedure(stopComplete)
+		var errs []error
+		if err != nil {
+			errs = append(errs, err)
+		}
+		errs = append(errs, stopErrs...)
+		if len(errs) > 0 {
+			// The ShutdownError allows to use errors.Is and errors.As for all contained
+			// errors, whereas fmt.Errorf allows wrapping at most one error which means
+			// the others can not be found anymore.
+			err = &ShutdownError{Errors: errs}
 		}
 	}()
 
@@ -442,11 +615,21 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		return fmt.Errorf("failed to add cluster to runnables: %w", err)
 	}
 
+	// Add the cluster provider, which is started along with the other non-leader election
+	// runnables once the cache synced.
+	if cm.clusterProvider != nil {
+		if err := cm.add(&clusterProviderRunnable{provider: cm.clusterProvider, aware: cm}); err != nil {
+			return fmt.Errorf("failed to add cluster provider to runnables: %w", err)
+		}
+	}
+
 	// Metrics should be served whether the controller is leader or not.
 	// (If we don't serve metrics for non-leaders, prometheus will still scrape
 	// the pod but will get a connection refused).
 	if cm.metricsListener != nil {
-		cm.serveMetrics()
+		if err := cm.serveMetrics(); err != nil {
+			return err
+		}
 	}
 
 	// Serve health probes.
@@ -454,6 +637,14 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		cm.serveHealthProbes()
 	}
 
+	// Add the pprof server, which is started along with the other non-leader election
+	// runnables and shut down gracefully along with them.
+	if cm.pprofListener != nil {
+		if err := cm.addPprofServer(); err != nil {
+			return fmt.Errorf("failed to add pprof server: %w", err)
+		}
+	}
+
 	// First start any webhook servers, which includes conversion, validation, and defaulting
 	// webhooks that are registered.
 	//
@@ -480,6 +671,14 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		}
 	}
 
+	// Warm up the runnables that support it before leader election, e.g. to sync the caches
+	// of the controllers of standby managers.
+	if err := cm.runnables.Warmup.Start(cm.internalCtx); err != nil {
+		if !errors.Is(err, wait.ErrWaitTimeout) {
+			return err
+		}
+	}
+
 	// Start the leader election and all required runnables.
 	{
 		ctx, cancel := context.WithCancel(context.Background())
@@ -487,10 +686,15 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		go func() {
 			if cm.resourceLock != nil {
 				if err := cm.startLeaderElection(ctx); err != nil {
+					// The leader elector didn't start, so there is nothing to wait for on shutdown.
+					close(cm.leaderElectionStopped)
 					cm.errChan <- err
 				}
 			} else {
 				// Treat not having leader election enabled the same as being elected.
+				if hook := cm.leaderElectionCallbacks.OnStartedLeading; hook != nil {
+					cm.runLeaderElectionCallback("OnStartedLeading", func() { hook(cm.internalCtx) })
+				}
 				if err := cm.startLeaderElectionRunnables(); err != nil {
 					cm.errChan <- err
 				}
@@ -499,6 +703,11 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 		}()
 	}
 
+	// Start the leader election groups, which are led independently of the manager.
+	if err := cm.startLeaderElectionGroups(); err != nil {
+		return err
+	}
+
 	ready = true
 	cm.Unlock()
 	select {
@@ -512,24 +721,45 @@ func (cm *controllerManager) Start(ctx context.Context) (err error) {
 }
 
 // engageStopProcedure signals all runnables to stop, reads potential errors
-// from the errChan and waits for them to end. It must not be called more than once.
-func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) error {
+// from the errChan and waits for them to end. It returns the errors received
+// while stopping, followed by those of the stop procedure itself. It must not
+// be called more than once.
+func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) []error {
 	if !atomic.CompareAndSwapInt64(cm.stopProcedureEngaged, 0, 1) {
-		return errors.New("stop procedure already engaged")
+		return []error{errors.New("stop procedure already engaged")}
 	}
 
 	// Populate the shutdown context, this operation MUST be done before
 	// closing the internalProceduresStop channel.
 	//
 	// The shutdown context immediately expires if the gracefulShutdownTimeout is not set.
+	// The runnables are waited for until the gracefulShutdownTimeout, except for those that
+	// have their own timeout, which starts once their group is stopped. The shutdown context
+	// then lasts long enough for the longest of them to be waited for after the others.
+	shutdownTimeout := cm.gracefulShutdownTimeout
+	if longest := cm.runnables.gracefulShutdownTimeout(); longest > 0 {
+		if shutdownTimeout < 0 {
+			shutdownTimeout = 0
+		}
+		shutdownTimeout += longest
+	}
 	var shutdownCancel context.CancelFunc
-	cm.shutdownCtx, shutdownCancel = context.WithTimeout(context.Background(), cm.gracefulShutdownTimeout)
+	cm.shutdownCtx, shutdownCancel = context.WithTimeout(context.Background(), shutdownTimeout)
 	defer shutdownCancel()
+	gracePeriodCtx, gracePeriodCancel := context.WithTimeout(context.Background(), cm.gracefulShutdownTimeout)
+	defer gracePeriodCancel()
 
 	// Start draining the errors before acquiring the lock to make sure we don't deadlock
 	// if something that has the lock is blocked on trying to write into the unbuffered
 	// channel after something else already wrote into it.
 	var closeOnce sync.Once
+	var receivedMu sync.Mutex
+	var received []error
+	receivedErrs := func() []error {
+		receivedMu.Lock()
+		defer receivedMu.Unlock()
+		return append([]error(nil), received...)
+	}
 	go func() {
 		for {
 			// Closing in the for loop is required to avoid race conditions between
@@ -543,6 +773,9 @@ func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) e
 			case err, ok := <-cm.errChan:
 				if ok {
 					cm.logger.Error(err, "error received after stop sequence was engaged")
+					receivedMu.Lock()
+					received = append(received, err)
+					receivedMu.Unlock()
 				}
 			case <-stopComplete:
 				return
@@ -556,6 +789,7 @@ func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) e
 	defer cm.recorderProvider.Stop(cm.shutdownCtx)
 	defer func() {
 		// Cancel leader election only after we waited. It will os.Exit() the app for safety.
+		cm.stopLeaderElectionGroups()
 		if cm.resourceLock != nil {
 			// After asking the context to be cancelled, make sure
 			// we wait for the leader stopped channel to be closed, otherwise
@@ -563,46 +797,76 @@ func (cm *controllerManager) engageStopProcedure(stopComplete <-chan struct{}) e
 			// and the event recorder, which is used within leader election code.
 			cm.leaderElectionCancel()
 			<-cm.leaderElectionStopped
+			return
+		}
+		// Without leader election, the manager stops leading once the runnables stopped.
+		if hook := cm.leaderElectionCallbacks.OnStoppedLeading; hook != nil && cm.isElected() {
+			cm.runLeaderElectionCallback("OnStoppedLeading", hook)
 		}
 	}()
 
+	// stopErrs receives the errors of the runnables that didn't return within their own
+	// graceful shutdown timeout, before the shutdown context is cancelled.
+	stopErrs := make(chan []error, 1)
 	go func() {
+		var errs []error
+
 		// First stop the non-leader election runnables.
 		cm.logger.Info("Stopping and waiting for non leader election runnables")
-		cm.runnables.Others.StopAndWait(cm.shutdownCtx)
+		errs = append(errs, cm.runnables.Others.StopAndWait(gracePeriodCtx)...)
 
 		// Stop all the leader election runnables, which includes reconcilers.
 		cm.logger.Info("Stopping and waiting for leader election runnables")
-		cm.runnables.LeaderElection.StopAndWait(cm.shutdownCtx)
+		errs = append(errs, cm.runnables.LeaderElection.StopAndWait(gracePeriodCtx)...)
+
+		// Stop the runnables of the leader election groups along with them.
+		for _, group := range cm.runnables.sortedLeaderElectionGroups() {
+			errs = append(errs, group.StopAndWait(gracePeriodCtx)...)
+		}
+
+		// The warmed up sources are stopped once the runnables using them are stopped.
+		cm.logger.Info("Stopping and waiting for warmup runnables")
+		errs = append(errs, cm.runnables.Warmup.StopAndWait(gracePeriodCtx)...)
 
 		// Stop the caches before the leader election runnables, this is an important
 		// step to make sure that we don't race with the reconcilers by receiving more events
 		// from the API servers and enqueueing them.
 		cm.logger.Info("Stopping and waiting for caches")
-		cm.runnables.Caches.StopAndWait(cm.shutdownCtx)
+		errs = append(errs, cm.runnables.Caches.StopAndWait(gracePeriodCtx)...)
+
+		// The engaged clusters are stopped along with the caches, as they're stopped by
+		// canceling the internal context too.
+		cm.logger.Info("Stopping and waiting for engaged clusters")
+		cm.waitForEngagedClusters(gracePeriodCtx)
 
 		// Webhooks should come last, as they might be still serving some requests.
 		cm.logger.Info("Stopping and waiting for webhooks")
-		cm.runnables.Webhooks.StopAndWait(cm.shutdownCtx)
+		errs = append(errs, cm.runnables.Webhooks.StopAndWait(gracePeriodCtx)...)
 
 		// Proceed to close the manager and overall shutdown context.
 		cm.logger.Info("Wait completed, proceeding to shutdown the manager")
+		stopErrs <- errs
 		shutdownCancel()
 	}()
 
 	<-cm.shutdownCtx.Done()
 	if err := cm.shutdownCtx.Err(); err != nil && !errors.Is(err, context.Canceled) {
 		if errors.Is(err, context.DeadlineExceeded) {
-			if cm.gracefulShutdownTimeout > 0 {
-				return fmt.Errorf("failed waiting for all runnables to end within grace period of %s: %w", cm.gracefulShutdownTimeout, err)
+			if shutdownTimeout > 0 {
+				return append(receivedErrs(), fmt.Errorf("failed waiting for all runnables to end within grace period of %s: %w", shutdownTimeout, err))
 			}
-			return nil
+			return receivedErrs()
 		}
 		// For any other error, return the error.
-		return err
+		return append(receivedErrs(), err)
 	}
 
-	return nil
+	select {
+	case errs := <-stopErrs:
+		return append(receivedErrs(), errs...)
+	default:
+		return receivedErrs()
+	}
 }
 
 func (cm *controllerManager) startLeaderElectionRunnables() error {
@@ -610,13 +874,34 @@ func (cm *controllerManager) startLeaderElectionRunnables() error {
 }
 
 func (cm *controllerManager) startLeaderElection(ctx context.Context) (err error) {
+	// Otherwise, the elector would retry forever without ever leading.
+	if cm.checkLeaderElectionLock {
+		if _, _, err := cm.resourceLock.Get(ctx); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("unable to get the leader election lock %s: %w", cm.resourceLock.Describe(), err)
+		}
+	}
+
+	// leading is set once this manager started leading, as the elector also calls
+	// OnStoppedLeading if it stops before it ever led.
+	var leading atomic.Bool
+	var onNewLeader func(string)
+	if hook := cm.leaderElectionCallbacks.OnNewLeader; hook != nil {
+		onNewLeader = func(identity string) {
+			cm.runLeaderElectionCallback("OnNewLeader", func() { hook(identity) })
+		}
+	}
+
 	l, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
 		Lock:          cm.resourceLock,
 		LeaseDuration: cm.leaseDuration,
 		RenewDeadline: cm.renewDeadline,
 		RetryPeriod:   cm.retryPeriod,
 		Callbacks: leaderelection.LeaderCallbacks{
-			OnStartedLeading: func(_ context.Context) {
+			OnStartedLeading: func(ctx context.Context) {
+				leading.Store(true)
+				if hook := cm.leaderElectionCallbacks.OnStartedLeading; hook != nil {
+					cm.runLeaderElectionCallback("OnStartedLeading", func() { hook(ctx) })
+				}
 				if err := cm.startLeaderElectionRunnables(); err != nil {
 					cm.errChan <- err
 					return
@@ -624,6 +909,9 @@ func (cm *controllerManager) startLeaderElection(ctx context.Context) (err error
 				close(cm.elected)
 			},
 			OnStoppedLeading: func() {
+				if hook := cm.leaderElectionCallbacks.OnStoppedLeading; hook != nil && leading.Load() {
+					cm.runLeaderElectionCallback("OnStoppedLeading", hook)
+				}
 				if cm.onStoppedLeading != nil {
 					cm.onStoppedLeading()
 				}
@@ -635,12 +923,18 @@ func (cm *controllerManager) startLeaderElection(ctx context.Context) (err error
 				// an error here which will cause the program to exit.
 				cm.errChan <- errors.New("leader election lost")
 			},
+			OnNewLeader: onNewLeader,
 		},
 		ReleaseOnCancel: cm.leaderElectionReleaseOnCancel,
+		WatchDog:        cm.leaderElectionWatchDog,
 	})
 	if err != nil {
 		return err
 	}
+	// Unlike RunOrDie, NewLeaderElector doesn't hand the elector to the watchdog.
+	if cm.leaderElectionWatchDog != nil {
+		cm.leaderElectionWatchDog.SetLeaderElection(l)
+	}
 
 	// Start the leader elector process
 	go func() {
@@ -651,6 +945,27 @@ func (cm *controllerManager) startLeaderElection(ctx context.Context) (err error
 	return nil
 }
 
+// isElected returns whether the manager was elected.
+func (cm *controllerManager) isElected() bool {
+	select {
+	case <-cm.elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// runLeaderElectionCallback runs a callback of the user, logging instead of propagating
+// its panics so that they don't break leader election.
+func (cm *controllerManager) runLeaderElectionCallback(name string, callback func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			cm.logger.Error(fmt.Errorf("panic: %v", r), "Leader election callback panicked", "callback", name)
+		}
+	}()
+	callback()
+}
+
 func (cm *controllerManager) Elected() <-chan struct{} {
 	return cm.elected
 }
@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"k8s.io/client-go/tools/leaderelection"
+)
+
+// addToLeaderElectionGroup starts the leader elector of the leader election group of a
+// runnable added after the manager started the leader election groups.
+func (cm *controllerManager) addToLeaderElectionGroup(opts ...AddOption) error {
+	options := &addOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.leaderElectionGroup == "" {
+		return nil
+	}
+	cm.leaderElectionGroupsMu.Lock()
+	defer cm.leaderElectionGroupsMu.Unlock()
+	return cm.startLeaderElectionGroup(options.leaderElectionGroup)
+}
+
+// leaderElectionGroup returns the group of the runnables of the leader election group
+// with the given lease name, creating it if needed.
+func (r *runnables) leaderElectionGroup(name string) *runnableGroup {
+	r.leaderElectionGroupsMu.Lock()
+	defer r.leaderElectionGroupsMu.Unlock()
+	group, ok := r.leaderElectionGroups[name]
+	if !ok {
+		group = newRunnableGroup(r.baseContext, r.errChan)
+		group.restartPolicy = r.restartPolicy
+		if r.logger.GetSink() != nil {
+			group.logger = r.logger
+		}
+		r.leaderElectionGroups[name] = group
+	}
+	return group
+}
+
+// leaderElectionGroupNames returns the lease names of the leader election groups, sorted.
+func (r *runnables) leaderElectionGroupNames() []string {
+	r.leaderElectionGroupsMu.Lock()
+	defer r.leaderElectionGroupsMu.Unlock()
+	names := make([]string, 0, len(r.leaderElectionGroups))
+	for name := range r.leaderElectionGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedLeaderElectionGroups returns the groups of the leader election groups, sorted by
+// lease name.
+func (r *runnables) sortedLeaderElectionGroups() []*runnableGroup {
+	names := r.leaderElectionGroupNames()
+	groups := make([]*runnableGroup, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, r.leaderElectionGroup(name))
+	}
+	return groups
+}
+
+// leaderElectionGroupElector is the leader elector of a leader election group.
+type leaderElectionGroupElector struct {
+	// leading is set once the manager started leading the group.
+	leading atomic.Bool
+	cancel  context.CancelFunc
+	// stopped is closed once the elector returned.
+	stopped chan struct{}
+}
+
+// startLeaderElectionGroups starts the leader electors of the leader election groups,
+// and of the groups that runnables are added to afterwards.
+func (cm *controllerManager) startLeaderElectionGroups() error {
+	cm.leaderElectionGroupsMu.Lock()
+	defer cm.leaderElectionGroupsMu.Unlock()
+	cm.leaderElectionGroupsStarted = true
+	for _, name := range cm.runnables.leaderElectionGroupNames() {
+		if err := cm.startLeaderElectionGroup(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startLeaderElectionGroup starts the leader elector of the leader election group with
+// the given lease name, which starts the runnables of the group once the manager holds
+// the lease. Without leader election, the runnables are started right away. It does
+// nothing until the manager starts the leader election groups. It must be called with
+// leaderElectionGroupsMu held.
+func (cm *controllerManager) startLeaderElectionGroup(name string) error {
+	if !cm.leaderElectionGroupsStarted {
+		return nil
+	}
+	if _, ok := cm.leaderElectionGroups[name]; ok {
+		return nil
+	}
+	group := cm.runnables.leaderElectionGroup(name)
+	if cm.newLeaderElectionGroupLock == nil {
+		cm.leaderElectionGroups[name] = nil
+		go func() {
+			if err := group.Start(cm.internalCtx); err != nil {
+				cm.errChan <- err
+			}
+		}()
+		return nil
+	}
+
+	lock, err := cm.newLeaderElectionGroupLock(name)
+	if err != nil {
+		return fmt.Errorf("failed to create the lock of leader election group %q: %w", name, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	elector := &leaderElectionGroupElector{cancel: cancel, stopped: make(chan struct{})}
+	log := cm.logger.WithValues("leaderElectionGroup", name)
+
+	l, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: cm.leaseDuration,
+		RenewDeadline: cm.renewDeadline,
+		RetryPeriod:   cm.retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(_ context.Context) {
+				elector.leading.Store(true)
+				log.Info("Started leading the leader election group")
+				if err := group.Start(cm.internalCtx); err != nil {
+					cm.errChan <- err
+				}
+			},
+			OnStoppedLeading: func() {
+				// As for the lease of the manager, the runnables of the group can't keep
+				// running once the lease is lost, so the manager is stopped.
+				if elector.leading.Load() && ctx.Err() == nil {
+					cm.errChan <- fmt.Errorf("leader election of group %q lost", name)
+				}
+			},
+		},
+		ReleaseOnCancel: cm.leaderElectionReleaseOnCancel,
+		Name:            name,
+	})
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to create the leader elector of leader election group %q: %w", name, err)
+	}
+	cm.leaderElectionGroups[name] = elector
+
+	go func() {
+		defer close(elector.stopped)
+		l.Run(ctx)
+	}()
+	return nil
+}
+
+// stopLeaderElectionGroups stops the leader electors of the leader election groups, and
+// waits for them to return. It's called once the runnables of the groups are stopped.
+func (cm *controllerManager) stopLeaderElectionGroups() {
+	cm.leaderElectionGroupsMu.Lock()
+	cm.leaderElectionGroupsStarted = false
+	electors := make([]*leaderElectionGroupElector, 0, len(cm.leaderElectionGroups))
+	for _, elector := range cm.leaderElectionGroups {
+		if elector != nil {
+			electors = append(electors, elector)
+		}
+	}
+	cm.leaderElectionGroupsMu.Unlock()
+	for _, elector := range electors {
+		elector.cancel()
+		<-elector.stopped
+	}
+}
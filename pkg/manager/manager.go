@@ -38,6 +38,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/budget"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	intrec "sigs.k8s.io/controller-runtime/pkg/internal/recorder"
 	"sigs.k8s.io/controller-runtime/pkg/leaderelection"
@@ -59,7 +60,17 @@ type Manager interface {
 	// implements the inject interface - e.g. inject.Client.
 	// Depending on if a Runnable implements LeaderElectionRunnable interface, a Runnable can be run in either
 	// non-leaderelection mode (always running) or leader election mode (managed by leader election if enabled).
-	Add(Runnable) error
+	// A Runnable that implements PhasedRunnable is started in the StartPhase it declares instead.
+	// Whether a Runnable needs leader election can also be set when it's added, with
+	// RequireLeaderElection.
+	Add(Runnable, ...AddOption) error
+
+	// Remove stops a Runnable that was added with Add, and blocks until it returns, so that
+	// runnables added dynamically, e.g. the controllers of a tenant, can be stopped without
+	// stopping the manager. A Runnable removed before the manager is started is never started.
+	// The errors returned by a removed Runnable don't stop the manager. Runnables of types that
+	// can't be compared, such as RunnableFunc, can't be removed.
+	Remove(Runnable) error
 
 	// Elected is closed when this manager is elected leader of a group of
 	// managers, either because it won a leader election or because no leader
@@ -73,12 +84,22 @@ type Manager interface {
 	// Runnable to the manager via Add method.
 	AddMetricsExtraHandler(path string, handler http.Handler) error
 
-	// AddHealthzCheck allows you to add Healthz checker
+	// AddHealthzCheck allows you to add Healthz checker. Checks can be added once the manager
+	// is started, e.g. by the controllers of a tenant, and replace the check of the same name.
 	AddHealthzCheck(name string, check healthz.Checker) error
 
-	// AddReadyzCheck allows you to add Readyz checker
+	// RemoveHealthzCheck removes the Healthz checker of the given name, e.g. once the
+	// component it checks is removed.
+	RemoveHealthzCheck(name string) error
+
+	// AddReadyzCheck allows you to add Readyz checker. Checks can be added once the manager
+	// is started, e.g. by the controllers of a tenant, and replace the check of the same name.
 	AddReadyzCheck(name string, check healthz.Checker) error
 
+	// RemoveReadyzCheck removes the Readyz checker of the given name, e.g. once the
+	// component it checks is removed.
+	RemoveReadyzCheck(name string) error
+
 	// Start starts all registered Controllers and blocks until the context is cancelled.
 	// Returns an error if there is an error starting any controller.
 	//
@@ -95,6 +116,20 @@ type Manager interface {
 
 	// GetControllerOptions returns controller global configuration options.
 	GetControllerOptions() v1alpha1.ControllerConfigurationSpec
+
+	// GetMetricsRegistry returns the registry that the metrics of this manager's
+	// controllers and webhook server report into.
+	GetMetricsRegistry() metrics.RegistererGatherer
+
+	// Aware engages and disengages clusters at runtime, once the manager is started.
+	// Engage starts the cluster, waits for its cache to sync and engages the runnables
+	// implementing cluster.Aware, such as controllers, with it until it's disengaged or
+	// the manager stops. Disengage disengages the runnables and stops the cluster.
+	cluster.Aware
+
+	// GetCluster returns the engaged cluster with the given name, or the cluster of the
+	// manager if the name is empty, as in reconcile.Request.
+	GetCluster(name string) (cluster.Cluster, error)
 }
 
 // Options are the arguments for creating a new Manager.
@@ -183,8 +218,35 @@ type Options struct {
 	// will use for holding the leader lock.
 	LeaderElectionID string
 
+	// LeaderElectionLocks configures a primary and a secondary lock to migrate leader
+	// election to another resource lock, namespace or name without ever having two
+	// leaders. It must contain exactly two locks, whose empty fields default to
+	// LeaderElectionResourceLock, LeaderElectionNamespace and LeaderElectionID.
+	// See leaderelection.LockConfig for how to migrate.
+	LeaderElectionLocks []leaderelection.LockConfig
+
+	// LeaderElectionIdentity is the identity of this manager in the leader election lock,
+	// which must be unique among all candidates, e.g. the name and UID of the pod.
+	// Defaults to the hostname followed by a random UUID. It is ignored if
+	// LeaderElectionResourceLockInterface is set.
+	LeaderElectionIdentity string
+
+	// LeaderElectionLabels and LeaderElectionAnnotations are added to the objects of the
+	// leader election lock, e.g. the Lease, when this manager creates or acquires it, e.g.
+	// to show the image or version of the leader. Labels and annotations of other writers
+	// are kept. They are ignored if LeaderElectionResourceLockInterface is set.
+	LeaderElectionLabels      map[string]string
+	LeaderElectionAnnotations map[string]string
+
+	// LeaderElectionCallbacks are called when this manager starts or stops leading and
+	// when a new leader is observed, in addition to the manager's own handling.
+	LeaderElectionCallbacks leaderelection.Callbacks
+
 	// LeaderElectionConfig can be specified to override the default configuration
-	// that is used to build the leader election client.
+	// that is used to build the leader election client, e.g. to elect the leader in
+	// another cluster than the one that is reconciled. It is ignored unless
+	// LeaderElection is enabled. The manager fails to start if it can't read the
+	// leader election lock with it.
 	LeaderElectionConfig *rest.Config
 
 	// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
@@ -200,6 +262,16 @@ type Options struct {
 	// want to use a locking mechanism that is currently not supported, like a MultiLock across two Kubernetes clusters.
 	LeaderElectionResourceLockInterface resourcelock.Interface
 
+	// LeaderElectionHealthzCheckName is the name of the healthz check that fails if the
+	// leader did not renew its lease within LeaseDuration plus RenewDeadline without
+	// stepping down, e.g. because leader election is deadlocked. It is added when leader
+	// election is enabled and defaults to "leaderElection".
+	LeaderElectionHealthzCheckName string
+
+	// DisableLeaderElectionHealthzCheck disables the healthz check of leader election,
+	// see LeaderElectionHealthzCheckName.
+	DisableLeaderElectionHealthzCheck bool
+
 	// LeaseDuration is the duration that non-leader candidates will
 	// wait to force acquire leadership. This is measured against time of
 	// last observed ack. Default is 15 seconds.
@@ -224,17 +296,61 @@ type Options struct {
 	// It can be set to "0" to disable the metrics serving.
 	MetricsBindAddress string
 
+	// MetricsListener is the listener the metrics are served on instead of one bound
+	// to MetricsBindAddress, e.g. a unix socket or a socket passed by systemd. The
+	// manager closes it when it stops.
+	MetricsListener net.Listener
+
+	// Metrics contains options for the metrics of the manager.
+	Metrics MetricsOptions
+
 	// HealthProbeBindAddress is the TCP address that the controller should bind to
 	// for serving health probes
 	// It can be set to "0" or "" to disable serving the health probe.
 	HealthProbeBindAddress string
 
+	// HealthProbeListener is the listener the health probes are served on instead of
+	// one bound to HealthProbeBindAddress. The manager closes it when it stops.
+	HealthProbeListener net.Listener
+
+	// PprofBindAddress is the TCP address that the controller should bind to
+	// for serving the net/http/pprof handlers on /debug/pprof/.
+	// It can be set to "0" or "" to disable serving pprof, which is the default.
+	PprofBindAddress string
+
+	// PprofListener is the listener pprof is served on instead of one bound to
+	// PprofBindAddress. The manager closes it when it stops.
+	PprofListener net.Listener
+
 	// Readiness probe endpoint name, defaults to "readyz"
 	ReadinessEndpointName string
 
 	// Liveness probe endpoint name, defaults to "healthz"
 	LivenessEndpointName string
 
+	// StatusEndpointName is the endpoint of the health probe server on which the Status
+	// of the manager is served as JSON, e.g. "/statusz", which reports the state of each
+	// Runnable, the sync status of the cache and the state of the leader election, for
+	// debugging managers that are stuck at startup. As the Status includes the errors
+	// of the Runnables, it's not served by default.
+	StatusEndpointName string
+
+	// ConfigzEndpointName is the endpoint of the health probe server on which the
+	// effective configuration of the manager is served as JSON, e.g. "/configz", which
+	// reports the scope of the cache, the settings of the leader election and the
+	// controllers with their concurrency, for debugging what a manager is configured
+	// to do. It's not served by default.
+	ConfigzEndpointName string
+
+	// HealthProbeIncludeErrors makes the health probes include the errors of failed
+	// checks in their responses. They are withheld by default, as the health probes
+	// are usually served without authentication.
+	HealthProbeIncludeErrors bool
+
+	// ReadinessIncludesCacheSync adds a readiness check named "cacheSync" that fails
+	// until the cache has been started and all of its informers have synced.
+	ReadinessIncludesCacheSync bool
+
 	// Port is the port that the webhook server serves at.
 	// It is used to set webhook.Server.Port if WebhookServer is not set.
 	Port int
@@ -242,6 +358,11 @@ type Options struct {
 	// It is used to set webhook.Server.Host if WebhookServer is not set.
 	Host string
 
+	// WebhookListener is the listener the webhook server serves on instead of one
+	// bound to Host and Port.
+	// It is used to set webhook.Server.Listener if WebhookServer is not set.
+	WebhookListener net.Listener
+
 	// CertDir is the directory that contains the server key and certificate.
 	// If not set, webhook server would look up the server key and certificate in
 	// {TempDir}/k8s-webhook-server/serving-certs. The server key and certificate
@@ -263,6 +384,12 @@ type Options struct {
 	// by the manager. If not set this will use the default new cache function.
 	NewCache cache.NewCacheFunc
 
+	// LazyCacheStart defers starting the cache of the manager until it's needed, i.e.
+	// until an object is read from it, e.g. with the client, or one of its informers is
+	// requested, e.g. by a controller watching a kind. A manager that only runs webhooks
+	// then doesn't list and watch any object. Defaults to false.
+	LazyCacheStart bool
+
 	// NewClient is the func that creates the client to be used by the manager.
 	// If not set this will create the default DelegatingClient that will
 	// use the cache for reads and the client for writes.
@@ -277,6 +404,27 @@ type Options struct {
 	// for the given objects.
 	ClientDisableCacheFor []client.Object
 
+	// ClientCacheMissFallback, if set, decides whether the gets of the client of the
+	// manager that don't find an object in the cache fall back to a get from the API
+	// server, so that reconcilers don't act on a stale "not found" right after the
+	// object was created. It's only used by the default NewClient.
+	ClientCacheMissFallback client.CacheMissFallbackPolicy
+
+	// ClientInterceptors are called around every call of the client and of the
+	// APIReader of the manager, whether it's served by the cache or by the API server,
+	// e.g. to audit the calls or to record metrics. The first interceptor is the
+	// outermost one.
+	ClientInterceptors []client.Interceptor
+
+	// ClientWarningHandlerOptions configures how the clients of the manager
+	// surface the warnings sent by the API server, e.g. how they are deduplicated.
+	ClientWarningHandlerOptions client.WarningHandlerOptions
+
+	// ClientRateLimitOptions configures the rate limiting of the requests of the
+	// clients of the manager, e.g. their own QPS and burst instead of the ones of the
+	// rest.Config.
+	ClientRateLimitOptions client.RateLimitOptions
+
 	// DryRunClient specifies whether the client should be configured to enforce
 	// dryRun mode.
 	DryRunClient bool
@@ -288,17 +436,36 @@ type Options struct {
 	// is shorter than the lifetime of your process.
 	EventBroadcaster record.EventBroadcaster
 
+	// EventBroadcasterOptions configures how the event recorders of the manager record
+	// events, e.g. the rate limits of the event correlator or the sink events are written
+	// to. The correlator options are ignored if EventBroadcaster is set.
+	EventBroadcasterOptions recorder.BroadcasterOptions
+
 	// GracefulShutdownTimeout is the duration given to runnable to stop before the manager actually returns on stop.
+	// Runnables that implement GracefulShutdownRunnable are given their own timeout instead.
 	// To disable graceful shutdown, set to time.Duration(0)
 	// To use graceful shutdown without timeout, set to a negative duration, e.G. time.Duration(-1)
 	// The graceful shutdown is skipped for safety reasons in case the leader election lease is lost.
 	GracefulShutdownTimeout *time.Duration
 
+	// RestartPolicy makes the manager supervise the Runnables added with the Restartable
+	// option: such a Runnable that returns an error is restarted according to the policy,
+	// instead of stopping the manager. Runnables that implement SupervisedRunnable are
+	// restarted with their own policy regardless. The error of any other Runnable stops
+	// the manager. Defaults to nil, in which case only the SupervisedRunnables are restarted.
+	RestartPolicy *RestartPolicy
+
 	// Controller contains global configuration options for controllers
 	// registered within this manager.
 	// +optional
 	Controller v1alpha1.ControllerConfigurationSpec
 
+	// ClusterProvider engages and disengages clusters in addition to the cluster of the
+	// manager while it runs, e.g. the member clusters of a fleet, such as the clusters
+	// whose kubeconfigs are stored in Secrets, see cluster.NewKubeconfigSecretProvider.
+	// It's run on every replica once the cache of the manager is synced. See Manager.Engage.
+	ClusterProvider cluster.Provider
+
 	// makeBroadcaster allows deferring the creation of the broadcaster to
 	// avoid leaking goroutines if we never call Start on this manager.  It also
 	// returns whether or not this is a "owned" broadcaster, and as such should be
@@ -306,10 +473,57 @@ type Options struct {
 	makeBroadcaster intrec.EventBroadcasterProducer
 
 	// Dependency injection for testing
-	newRecorderProvider    func(config *rest.Config, scheme *runtime.Scheme, logger logr.Logger, makeBroadcaster intrec.EventBroadcasterProducer) (*intrec.Provider, error)
+	newRecorderProvider    func(config *rest.Config, scheme *runtime.Scheme, logger logr.Logger, makeBroadcaster intrec.EventBroadcasterProducer, options recorder.BroadcasterOptions) (*intrec.Provider, error)
 	newResourceLock        func(config *rest.Config, recorderProvider recorder.Provider, options leaderelection.Options) (resourcelock.Interface, error)
 	newMetricsListener     func(addr string) (net.Listener, error)
 	newHealthProbeListener func(addr string) (net.Listener, error)
+	newPprofListener       func(addr string) (net.Listener, error)
+}
+
+// MetricsOptions are the options for the metrics of a Manager.
+type MetricsOptions struct {
+	// Registry is the registry that the metrics of the controllers and the webhook
+	// server of the manager report into, and that is served on the metrics endpoint.
+	// It allows a host application to pass its own registry, or several managers in
+	// one process to report into separate registries.
+	//
+	// The metrics of client-go's REST clients and of the caches are reported per
+	// process and always go into metrics.Registry.
+	//
+	// Defaults to metrics.Registry.
+	Registry metrics.RegistererGatherer
+
+	// DisableClientGoMetrics stops the manager from setting up client-go to report the
+	// metrics of its REST clients and workqueues into metrics.Registry, see
+	// metrics.RegisterClientGoMetrics, for users that register their own adapters with
+	// client-go. client-go only accepts the first adapters registered with it, so it must
+	// be set by every manager of the process.
+	DisableClientGoMetrics bool
+
+	// SecureServing serves the metrics endpoint over HTTPS instead of HTTP.
+	SecureServing bool
+
+	// CertDir is the directory of the certificate and key the metrics endpoint is served
+	// with when SecureServing is set. They are reloaded when they change. If CertDir is
+	// empty, the endpoint is served with a self-signed certificate generated at startup.
+	CertDir string
+
+	// CertName is the name of the certificate file in CertDir. Defaults to "tls.crt".
+	CertName string
+
+	// KeyName is the name of the key file in CertDir. Defaults to "tls.key".
+	KeyName string
+
+	// TLSOpts are called on the TLS configuration of the metrics endpoint when
+	// SecureServing is set, e.g. to set its minimum version or its cipher suites.
+	TLSOpts []func(*tls.Config)
+
+	// FilterProvider provides the Filter that wraps the handlers of the metrics endpoint,
+	// including the extra handlers, e.g. filters.WithAuthenticationAndAuthorization to
+	// authenticate and authorize the requests with TokenReviews and SubjectAccessReviews.
+	// It's called with the config of the manager. Defaults to nil, which serves the
+	// requests of anyone who can reach the endpoint.
+	FilterProvider func(config *rest.Config) (metrics.Filter, error)
 }
 
 // BaseContextFunc is a function used to provide a base Context to Runnables
@@ -336,6 +550,105 @@ func (r RunnableFunc) Start(ctx context.Context) error {
 	return r(ctx)
 }
 
+// WarmupRunnable is a Runnable that can be warmed up before it's started, e.g. a controller that
+// starts its sources and waits for their caches to sync while the manager waits to be elected,
+// so that it reconciles right away once it's started.
+type WarmupRunnable interface {
+	// Warmup is called once the caches of the manager are synced, before leader election.
+	// It must return once the Runnable is warmed up, and must not block until the context is
+	// done. The context is done once the manager stops.
+	Warmup(context.Context) error
+}
+
+// GracefulShutdownRunnable is a Runnable with its own graceful shutdown timeout, which
+// overrides the GracefulShutdownTimeout of the manager, e.g. a webhook server that needs
+// longer to drain its in-flight requests than the controllers need to return.
+type GracefulShutdownRunnable interface {
+	Runnable
+
+	// GracefulShutdownTimeout returns the duration the Runnable is given to return once
+	// it's stopped. The manager returns an error naming the Runnable if it doesn't.
+	GracefulShutdownTimeout() time.Duration
+}
+
+// AddOption configures how a Runnable added with Manager.Add is run.
+type AddOption func(*addOptions)
+
+type addOptions struct {
+	needLeaderElection  *bool
+	leaderElectionGroup string
+	restartable         bool
+}
+
+// RequireLeaderElection sets whether a Runnable is only started once the manager is elected
+// leader, regardless of whether it implements LeaderElectionRunnable or PhasedRunnable.
+// Runnables that don't require leader election run on every replica, including standbys,
+// e.g. webhook servers or cache warmers, while those that do wait for leadership, e.g.
+// controllers. Runnables with a cache, e.g. clusters, are still only ready once their cache
+// is synced. Webhook servers can't require leader election.
+func RequireLeaderElection(required bool) AddOption {
+	return func(o *addOptions) {
+		o.needLeaderElection = &required
+	}
+}
+
+// InLeaderElectionGroup adds a Runnable to the leader election group with the given lease
+// name. The Runnables of a group are only started once the manager holds the lease of the
+// group, independently of the lease of the manager, so that the groups of a manager can be
+// led by different replicas, e.g. to spread heavy singleton controllers over the replicas
+// while the other controllers run on every replica. The leases of the groups are created
+// like the one of the manager, in the LeaderElectionNamespace. Without leader election, the
+// Runnables of the groups are started along with the other leader election Runnables.
+// It takes precedence over RequireLeaderElection.
+func InLeaderElectionGroup(leaseName string) AddOption {
+	return func(o *addOptions) {
+		o.leaderElectionGroup = leaseName
+	}
+}
+
+// Restartable makes the manager restart a Runnable that returns an error according to the
+// RestartPolicy of the manager, instead of stopping. Only Runnables that can be started again
+// once they returned should be restartable, e.g. auxiliary servers, unlike the caches and
+// the clusters.
+func Restartable() AddOption {
+	return func(o *addOptions) {
+		o.restartable = true
+	}
+}
+
+// StartPhase is a phase of the start of a manager, in which it starts the Runnables of the phase.
+// The manager starts the phases in the order below, waiting for the Runnables of a phase to be
+// ready before starting the next phase, and stops them in the reverse order.
+type StartPhase string
+
+const (
+	// StartPhaseWebhooks is the phase of the webhook servers, which are started first so that
+	// the conversion webhooks are serving before the caches list the objects they convert.
+	StartPhaseWebhooks StartPhase = "Webhooks"
+
+	// StartPhaseCaches is the phase of the caches. Runnables of this phase that have a cache,
+	// i.e. a GetCache() cache.Cache method, are ready once their cache is synced.
+	StartPhaseCaches StartPhase = "Caches"
+
+	// StartPhaseAfterCaches is the phase of the Runnables that don't need leader election,
+	// which are started once the caches are synced.
+	StartPhaseAfterCaches StartPhase = "AfterCaches"
+
+	// StartPhaseLeaderElection is the phase of the Runnables that need leader election, which
+	// are started once the manager is elected, after the Runnables of the other phases.
+	StartPhaseLeaderElection StartPhase = "LeaderElection"
+)
+
+// PhasedRunnable is a Runnable that declares the phase in which the manager starts it, instead
+// of the phase inferred from its type, e.g. a Runnable that needs to be started once the caches
+// are synced but before leader election. It takes precedence over LeaderElectionRunnable.
+type PhasedRunnable interface {
+	Runnable
+
+	// StartPhase returns the phase in which the manager starts the Runnable.
+	StartPhase() StartPhase
+}
+
 // LeaderElectionRunnable knows if a Runnable needs to be run in the leader election mode.
 type LeaderElectionRunnable interface {
 	// NeedLeaderElection returns true if the Runnable needs to be run in the leader election mode.
@@ -348,6 +661,10 @@ func New(config *rest.Config, options Options) (Manager, error) {
 	// Set default values for options fields
 	options = setOptionsDefaults(options)
 
+	if !options.Metrics.DisableClientGoMetrics {
+		metrics.RegisterClientGoMetrics()
+	}
+
 	cluster, err := cluster.New(config, func(clusterOptions *cluster.Options) {
 		clusterOptions.Scheme = options.Scheme
 		clusterOptions.MapperProvider = options.MapperProvider
@@ -355,19 +672,32 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		clusterOptions.SyncPeriod = options.SyncPeriod
 		clusterOptions.Namespace = options.Namespace
 		clusterOptions.NewCache = options.NewCache
+		clusterOptions.LazyCacheStart = options.LazyCacheStart
 		clusterOptions.NewClient = options.NewClient
 		clusterOptions.ClientDisableCacheFor = options.ClientDisableCacheFor
+		clusterOptions.ClientCacheMissFallback = options.ClientCacheMissFallback
+		clusterOptions.ClientWarningHandlerOptions = options.ClientWarningHandlerOptions
+		clusterOptions.ClientRateLimitOptions = options.ClientRateLimitOptions
+		clusterOptions.ClientInterceptors = options.ClientInterceptors
 		clusterOptions.DryRunClient = options.DryRunClient
 		clusterOptions.EventBroadcaster = options.EventBroadcaster //nolint:staticcheck
+		clusterOptions.EventBroadcasterOptions = options.EventBroadcasterOptions
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	var reconcileBudget *budget.Budget
+	if options.Controller.MaxConcurrentReconcilesTotal != 0 {
+		if reconcileBudget, err = budget.NewForRegistry(options.Metrics.Registry, "manager", options.Controller.MaxConcurrentReconcilesTotal); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create the recorder provider to inject event recorders for the components.
 	// TODO(directxman12): the log for the event provider should have a context (name, tags, etc) specific
 	// to the particular controller that it's being injected into, rather than a generic one like is here.
-	recorderProvider, err := options.newRecorderProvider(config, cluster.GetScheme(), options.Logger.WithName("events"), options.makeBroadcaster)
+	recorderProvider, err := options.newRecorderProvider(config, cluster.GetScheme(), options.Logger.WithName("events"), options.makeBroadcaster, options.EventBroadcasterOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -376,12 +706,12 @@ func New(config *rest.Config, options Options) (Manager, error) {
 	var leaderConfig *rest.Config
 	var leaderRecorderProvider *intrec.Provider
 
-	if options.LeaderElectionConfig == nil {
+	if options.LeaderElectionConfig == nil || !options.LeaderElection {
 		leaderConfig = rest.CopyConfig(config)
 		leaderRecorderProvider = recorderProvider
 	} else {
 		leaderConfig = rest.CopyConfig(options.LeaderElectionConfig)
-		leaderRecorderProvider, err = options.newRecorderProvider(leaderConfig, cluster.GetScheme(), options.Logger.WithName("events"), options.makeBroadcaster)
+		leaderRecorderProvider, err = options.newRecorderProvider(leaderConfig, cluster.GetScheme(), options.Logger.WithName("events"), options.makeBroadcaster, options.EventBroadcasterOptions)
 		if err != nil {
 			return nil, err
 		}
@@ -396,12 +726,37 @@ func New(config *rest.Config, options Options) (Manager, error) {
 			LeaderElectionResourceLock: options.LeaderElectionResourceLock,
 			LeaderElectionID:           options.LeaderElectionID,
 			LeaderElectionNamespace:    options.LeaderElectionNamespace,
+			LeaderElectionLocks:        options.LeaderElectionLocks,
+			LeaderElectionIdentity:     options.LeaderElectionIdentity,
+			LeaderElectionLabels:       options.LeaderElectionLabels,
+			LeaderElectionAnnotations:  options.LeaderElectionAnnotations,
 		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	// The leases of the leader election groups are created like the one of the manager,
+	// with the same identity.
+	var newLeaderElectionGroupLock func(name string) (resourcelock.Interface, error)
+	if options.LeaderElection {
+		identity := options.LeaderElectionIdentity
+		if identity == "" {
+			identity = resourceLock.Identity()
+		}
+		newLeaderElectionGroupLock = func(name string) (resourcelock.Interface, error) {
+			return options.newResourceLock(leaderConfig, leaderRecorderProvider, leaderelection.Options{
+				LeaderElection:             true,
+				LeaderElectionResourceLock: options.LeaderElectionResourceLock,
+				LeaderElectionID:           name,
+				LeaderElectionNamespace:    options.LeaderElectionNamespace,
+				LeaderElectionIdentity:     identity,
+				LeaderElectionLabels:       options.LeaderElectionLabels,
+				LeaderElectionAnnotations:  options.LeaderElectionAnnotations,
+			})
+		}
+	}
+
 	// Create the metrics listener. This will throw an error if the metrics bind
 	// address is invalid or already in use.
 	metricsListener, err := options.newMetricsListener(options.MetricsBindAddress)
@@ -412,6 +767,22 @@ func New(config *rest.Config, options Options) (Manager, error) {
 	// By default we have no extra endpoints to expose on metrics http server.
 	metricsExtraHandlers := make(map[string]http.Handler)
 
+	var metricsFilter metrics.Filter
+	if options.Metrics.FilterProvider != nil {
+		metricsFilter, err = options.Metrics.FilterProvider(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the filter of the metrics endpoint: %w", err)
+		}
+	}
+
+	var metricsTLS *metricsTLSConfig
+	if options.Metrics.SecureServing && metricsListener != nil {
+		metricsTLS, err = newMetricsTLSConfig(options.Metrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create health probes listener. This will throw an error if the bind
 	// address is invalid or already in use.
 	healthProbeListener, err := options.newHealthProbeListener(options.HealthProbeBindAddress)
@@ -419,10 +790,18 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		return nil, err
 	}
 
+	// Create pprof listener. This will throw an error if the bind
+	// address is invalid or already in use.
+	pprofListener, err := options.newPprofListener(options.PprofBindAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the pprof listener: %w", err)
+	}
+
 	errChan := make(chan error)
 	runnables := newRunnables(options.BaseContext, errChan)
+	runnables.supervise(options.RestartPolicy, options.Logger)
 
-	return &controllerManager{
+	cm := &controllerManager{
 		stopProcedureEngaged:          pointer.Int64(0),
 		cluster:                       cluster,
 		runnables:                     runnables,
@@ -430,26 +809,55 @@ func New(config *rest.Config, options Options) (Manager, error) {
 		recorderProvider:              recorderProvider,
 		resourceLock:                  resourceLock,
 		metricsListener:               metricsListener,
+		metricsRegistry:               options.Metrics.Registry,
 		metricsExtraHandlers:          metricsExtraHandlers,
+		metricsFilter:                 metricsFilter,
+		metricsTLS:                    metricsTLS,
 		controllerOptions:             options.Controller,
+		reconcileBudget:               reconcileBudget,
 		logger:                        options.Logger,
 		elected:                       make(chan struct{}),
 		port:                          options.Port,
 		host:                          options.Host,
 		certDir:                       options.CertDir,
 		tlsOpts:                       options.TLSOpts,
+		webhookListener:               options.WebhookListener,
 		webhookServer:                 options.WebhookServer,
 		leaseDuration:                 *options.LeaseDuration,
 		renewDeadline:                 *options.RenewDeadline,
 		retryPeriod:                   *options.RetryPeriod,
 		healthProbeListener:           healthProbeListener,
+		pprofListener:                 pprofListener,
 		readinessEndpointName:         options.ReadinessEndpointName,
 		livenessEndpointName:          options.LivenessEndpointName,
+		statusEndpointName:            options.StatusEndpointName,
+		configzEndpointName:           options.ConfigzEndpointName,
+		staticConfigz:                 newStaticConfigz(options, metricsListener, healthProbeListener, pprofListener),
+		healthProbeIncludeErrors:      options.HealthProbeIncludeErrors,
 		gracefulShutdownTimeout:       *options.GracefulShutdownTimeout,
 		internalProceduresStop:        make(chan struct{}),
 		leaderElectionStopped:         make(chan struct{}),
+		newLeaderElectionGroupLock:    newLeaderElectionGroupLock,
+		leaderElectionGroups:          map[string]*leaderElectionGroupElector{},
 		leaderElectionReleaseOnCancel: options.LeaderElectionReleaseOnCancel,
-	}, nil
+		leaderElectionCallbacks:       options.LeaderElectionCallbacks,
+		checkLeaderElectionLock:       options.LeaderElectionConfig != nil && options.LeaderElectionResourceLockInterface == nil,
+		clusterProvider:               options.ClusterProvider,
+	}
+
+	if resourceLock != nil && !options.DisableLeaderElectionHealthzCheck {
+		if err := cm.addLeaderElectionHealthzCheck(options.LeaderElectionHealthzCheckName); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.ReadinessIncludesCacheSync {
+		if err := cm.AddReadyzCheck(cacheSyncReadyzCheckName, healthz.CacheSyncChecker(cluster.GetCache())); err != nil {
+			return nil, err
+		}
+	}
+
+	return cm, nil
 }
 
 // AndFrom will use a supplied type and convert to Options
@@ -514,6 +922,14 @@ func (o Options) AndFrom(loader config.ControllerManagerConfiguration) (Options,
 		if len(o.Controller.GroupKindConcurrency) == 0 && len(newObj.Controller.GroupKindConcurrency) > 0 {
 			o.Controller.GroupKindConcurrency = newObj.Controller.GroupKindConcurrency
 		}
+
+		if o.Controller.MaxConcurrentReconcilesTotal == 0 && newObj.Controller.MaxConcurrentReconcilesTotal > 0 {
+			o.Controller.MaxConcurrentReconcilesTotal = newObj.Controller.MaxConcurrentReconcilesTotal
+		}
+
+		if o.Controller.SkipNameValidation == nil && newObj.Controller.SkipNameValidation != nil {
+			o.Controller.SkipNameValidation = newObj.Controller.SkipNameValidation
+		}
 	}
 
 	return o, nil
@@ -578,6 +994,27 @@ func defaultHealthProbeListener(addr string) (net.Listener, error) {
 	return ln, nil
 }
 
+// defaultPprofListener creates the default pprof listener bound to the given address.
+func defaultPprofListener(addr string) (net.Listener, error) {
+	if addr == "" || addr == "0" {
+		return nil, nil
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("error listening on %s: %w", addr, err)
+	}
+	return ln, nil
+}
+
+// prebuiltListener returns a function that returns the given listener, regardless of
+// the address it's given.
+func prebuiltListener(ln net.Listener) func(addr string) (net.Listener, error) {
+	return func(string) (net.Listener, error) {
+		return ln, nil
+	}
+}
+
 // defaultBaseContext is used as the BaseContext value in Options if one
 // has not already been set.
 func defaultBaseContext() context.Context {
@@ -602,7 +1039,7 @@ func setOptionsDefaults(options Options) Options {
 	if options.EventBroadcaster == nil {
 		// defer initialization to avoid leaking by default
 		options.makeBroadcaster = func() (record.EventBroadcaster, bool) {
-			return record.NewBroadcaster(), true
+			return record.NewBroadcasterWithCorrelatorOptions(options.EventBroadcasterOptions.CorrelatorOptions), true
 		}
 	} else {
 		options.makeBroadcaster = func() (record.EventBroadcaster, bool) {
@@ -610,8 +1047,15 @@ func setOptionsDefaults(options Options) Options {
 		}
 	}
 
+	if options.Metrics.Registry == nil {
+		options.Metrics.Registry = metrics.Registry
+	}
+
 	if options.newMetricsListener == nil {
 		options.newMetricsListener = metrics.NewListener
+		if options.MetricsListener != nil {
+			options.newMetricsListener = prebuiltListener(options.MetricsListener)
+		}
 	}
 	leaseDuration, renewDeadline, retryPeriod := defaultLeaseDuration, defaultRenewDeadline, defaultRetryPeriod
 	if options.LeaseDuration == nil {
@@ -634,8 +1078,22 @@ func setOptionsDefaults(options Options) Options {
 		options.LivenessEndpointName = defaultLivenessEndpoint
 	}
 
+	if options.LeaderElectionHealthzCheckName == "" {
+		options.LeaderElectionHealthzCheckName = defaultLeaderElectionHealthzCheckName
+	}
+
 	if options.newHealthProbeListener == nil {
 		options.newHealthProbeListener = defaultHealthProbeListener
+		if options.HealthProbeListener != nil {
+			options.newHealthProbeListener = prebuiltListener(options.HealthProbeListener)
+		}
+	}
+
+	if options.newPprofListener == nil {
+		options.newPprofListener = defaultPprofListener
+		if options.PprofListener != nil {
+			options.newPprofListener = prebuiltListener(options.PprofListener)
+		}
 	}
 
 	if options.GracefulShutdownTimeout == nil {
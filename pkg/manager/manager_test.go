@@ -19,13 +19,16 @@ package manager
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"path"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -35,12 +38,15 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/goleak"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/util/workqueue"
 	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -113,7 +119,7 @@ var _ = Describe("manger.Manager", func() {
 
 		It("should return an error it can't create a recorder.Provider", func() {
 			m, err := New(cfg, Options{
-				newRecorderProvider: func(_ *rest.Config, _ *runtime.Scheme, _ logr.Logger, _ intrec.EventBroadcasterProducer) (*intrec.Provider, error) {
+				newRecorderProvider: func(_ *rest.Config, _ *runtime.Scheme, _ logr.Logger, _ intrec.EventBroadcasterProducer, _ recorder.BroadcasterOptions) (*intrec.Provider, error) {
 					return nil, fmt.Errorf("expected error")
 				},
 			})
@@ -266,6 +272,60 @@ var _ = Describe("manger.Manager", func() {
 			Expect(svr).NotTo(BeNil())
 			Expect(svr.Port).To(Equal(9440))
 			Expect(svr.Host).To(Equal("foo.com"))
+			Expect(svr.MetricsRegistry).To(BeIdenticalTo(metrics.Registry))
+		})
+
+		It("should pass the metrics registry to the webhook server", func() {
+			registry := prometheus.NewRegistry()
+			m, err := New(cfg, Options{Metrics: MetricsOptions{Registry: registry}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.GetWebhookServer().MetricsRegistry).To(BeIdenticalTo(registry))
+		})
+
+		It("should only register the client-go metrics if they're not disabled", func() {
+			// gatheredNames returns the names of the metric families of metrics.Registry.
+			gatheredNames := func() []string {
+				metricFamilies, err := metrics.Registry.Gather()
+				Expect(err).NotTo(HaveOccurred())
+				var names []string
+				for _, mf := range metricFamilies {
+					names = append(names, mf.GetName())
+				}
+				return names
+			}
+
+			// Make sure that client-go reports into the metrics of controller-runtime.
+			metrics.RegisterClientGoMetrics()
+			queue := workqueue.NewNamed("client-go-metrics-test")
+			defer queue.ShutDown()
+			queue.Add("item")
+
+			metrics.UnregisterClientGoMetrics()
+			defer metrics.RegisterClientGoMetrics()
+			_, err := New(cfg, Options{Metrics: MetricsOptions{DisableClientGoMetrics: true}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gatheredNames()).NotTo(ContainElement("workqueue_adds_total"))
+
+			_, err = New(cfg, Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gatheredNames()).To(ContainElement("workqueue_adds_total"))
+		})
+
+		It("should report the metrics of the reconcile budget into the metrics registry", func() {
+			registry := prometheus.NewRegistry()
+			_, err := New(cfg, Options{
+				Metrics:    MetricsOptions{Registry: registry},
+				Controller: v1alpha1.ControllerConfigurationSpec{MaxConcurrentReconcilesTotal: 3},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			metricFamilies, err := registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, mf := range metricFamilies {
+				names = append(names, mf.GetName())
+			}
+			Expect(names).To(ContainElement("controller_runtime_reconcile_budget_capacity"))
 		})
 
 		It("should not initialize a webhook server if Options.WebhookServer is set", func() {
@@ -354,6 +414,113 @@ var _ = Describe("manger.Manager", func() {
 
 				Expect(cm.gracefulShutdownTimeout.Nanoseconds()).To(Equal(int64(0)))
 			})
+			It("should call the LeaderElectionCallbacks around starting the leader election runnables", func() {
+				var lock sync.Mutex
+				var events []string
+				record := func(event string) {
+					lock.Lock()
+					defer lock.Unlock()
+					events = append(events, event)
+				}
+				getEvents := func() []string {
+					lock.Lock()
+					defer lock.Unlock()
+					return append([]string(nil), events...)
+				}
+
+				m, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionNamespace: "default",
+					LeaderElectionID:        "test-leader-election-id-callbacks",
+					LeaderElectionIdentity:  "test-identity",
+					LeaderElectionCallbacks: leaderelection.Callbacks{
+						OnStartedLeading: func(ctx context.Context) {
+							record("started leading")
+						},
+						OnStoppedLeading: func() {
+							record("stopped leading")
+						},
+						OnNewLeader: func(identity string) {
+							record("new leader " + identity)
+							panic("the election loop should survive this")
+						},
+					},
+					HealthProbeBindAddress: "0",
+					MetricsBindAddress:     "0",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(m.(*controllerManager).resourceLock.Identity()).To(Equal("test-identity"))
+
+				Expect(m.Add(RunnableFunc(func(ctx context.Context) error {
+					record("runnable started")
+					<-ctx.Done()
+					return nil
+				}))).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				mgrDone := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).To(Succeed())
+					close(mgrDone)
+				}()
+				<-m.Elected()
+				Eventually(getEvents).Should(ContainElement("new leader test-identity"))
+				Eventually(getEvents).Should(ContainElement("runnable started"))
+
+				cancel()
+				<-mgrDone
+
+				var ordered []string
+				for _, event := range getEvents() {
+					if !strings.HasPrefix(event, "new leader") {
+						ordered = append(ordered, event)
+					}
+				}
+				Expect(ordered).To(Equal([]string{"started leading", "runnable started", "stopped leading"}))
+			})
+			It("should call the LeaderElectionCallbacks when leader election is disabled", func() {
+				var lock sync.Mutex
+				var events []string
+				record := func(event string) {
+					lock.Lock()
+					defer lock.Unlock()
+					events = append(events, event)
+				}
+
+				m, err := New(cfg, Options{
+					LeaderElectionCallbacks: leaderelection.Callbacks{
+						OnStartedLeading: func(ctx context.Context) {
+							record("started leading")
+						},
+						OnStoppedLeading: func() {
+							record("stopped leading")
+						},
+					},
+					HealthProbeBindAddress: "0",
+					MetricsBindAddress:     "0",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(m.Add(RunnableFunc(func(ctx context.Context) error {
+					record("runnable started")
+					<-ctx.Done()
+					record("runnable stopped")
+					return nil
+				}))).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				mgrDone := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).To(Succeed())
+					close(mgrDone)
+				}()
+				<-m.Elected()
+				cancel()
+				<-mgrDone
+
+				Expect(events).To(Equal([]string{"started leading", "runnable started", "runnable stopped", "stopped leading"}))
+			})
 			It("should default ID to controller-runtime if ID is not set", func() {
 				var rl resourcelock.Interface
 				m1, err := New(cfg, Options{
@@ -420,6 +587,14 @@ var _ = Describe("manger.Manager", func() {
 					return nil
 				}))).To(Succeed())
 
+				// Runnables that don't require leader election run on standbys too.
+				c3 := make(chan struct{})
+				Expect(m2.Add(RunnableFunc(func(context.Context) error {
+					defer GinkgoRecover()
+					close(c3)
+					return nil
+				}), RequireLeaderElection(false))).To(Succeed())
+
 				ctx2, cancel := context.WithCancel(context.Background())
 				m2done := make(chan struct{})
 				go func() {
@@ -430,6 +605,7 @@ var _ = Describe("manger.Manager", func() {
 				Consistently(m2.Elected()).ShouldNot(Receive())
 
 				Consistently(c2).ShouldNot(Receive())
+				Eventually(c3).Should(BeClosed())
 				cancel()
 				<-m2done
 			})
@@ -484,6 +660,331 @@ var _ = Describe("manger.Manager", func() {
 				_, secondaryIsLeaseLock := multilock.Secondary.(*resourcelock.LeaseLock)
 				Expect(secondaryIsLeaseLock).To(BeTrue())
 			})
+			It("should build the resource lock with the LeaderElectionConfig", func() {
+				leaderConfig := rest.CopyConfig(cfg)
+				leaderConfig.UserAgent = "coordination-cluster"
+				var lockConfig *rest.Config
+				_, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionID:        "controller-runtime",
+					LeaderElectionNamespace: "my-ns",
+					LeaderElectionConfig:    leaderConfig,
+					newResourceLock: func(config *rest.Config, recorderProvider recorder.Provider, options leaderelection.Options) (resourcelock.Interface, error) {
+						lockConfig = config
+						return fakeleaderelection.NewResourceLock(config, recorderProvider, options)
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lockConfig.UserAgent).To(Equal("coordination-cluster"))
+
+				By("ignoring it if leader election is disabled")
+				m, err := New(cfg, Options{LeaderElectionConfig: &rest.Config{Host: "http://127.0.0.1:1"}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(m.(*controllerManager).resourceLock).To(BeNil())
+			})
+			It("should fail to start if the LeaderElectionConfig can't reach the lock", func() {
+				m, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionID:        "controller-runtime",
+					LeaderElectionNamespace: "default",
+					LeaderElectionConfig:    &rest.Config{Host: "http://127.0.0.1:1"},
+					HealthProbeBindAddress:  "0",
+					MetricsBindAddress:      "0",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				err = m.Start(ctx)
+				Expect(err).To(MatchError(ContainSubstring("unable to get the leader election lock")))
+			})
+			It("should use a migration lock over the LeaderElectionLocks", func() {
+				m, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionID:        "controller-runtime",
+					LeaderElectionNamespace: "my-ns",
+					LeaderElectionLocks: []leaderelection.LockConfig{
+						{ResourceLock: resourcelock.ConfigMapsLeasesResourceLock, Namespace: "kube-system"},
+						{Name: "controller-runtime-new"},
+					},
+				})
+				Expect(err).ToNot(HaveOccurred())
+				cm, ok := m.(*controllerManager)
+				Expect(ok).To(BeTrue())
+				Expect(cm.resourceLock.Describe()).To(Equal("kube-system/controller-runtime"))
+			})
+
+			It("should add the LeaderElectionLabels and LeaderElectionAnnotations to the lease", func() {
+				By("creating the lease with a label of another writer")
+				_, err := clientset.CoordinationV1().Leases("default").Create(context.Background(), &coordinationv1.Lease{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "test-leader-election-metadata",
+						Labels: map[string]string{"owner": "someone-else"},
+					},
+				}, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				m, err := New(cfg, Options{
+					LeaderElection:            true,
+					LeaderElectionNamespace:   "default",
+					LeaderElectionID:          "test-leader-election-metadata",
+					LeaderElectionIdentity:    "test-identity",
+					LeaderElectionLabels:      map[string]string{"version": "v1.2.3"},
+					LeaderElectionAnnotations: map[string]string{"example.com/image": "controller:v1.2.3"},
+					HealthProbeBindAddress:    "0",
+					MetricsBindAddress:        "0",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				m.(*controllerManager).onStoppedLeading = func() {}
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).To(Succeed())
+				}()
+				<-m.Elected()
+
+				Eventually(func(g Gomega) {
+					lease, err := clientset.CoordinationV1().Leases("default").Get(context.Background(), "test-leader-election-metadata", metav1.GetOptions{})
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(lease.Labels).To(Equal(map[string]string{"owner": "someone-else", "version": "v1.2.3"}))
+					g.Expect(lease.Annotations).To(HaveKeyWithValue("example.com/image", "controller:v1.2.3"))
+					g.Expect(*lease.Spec.HolderIdentity).To(Equal("test-identity"))
+				}).Should(Succeed())
+			})
+			It("should reject invalid LeaderElectionLabels", func() {
+				_, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionNamespace: "default",
+					LeaderElectionID:        "test-leader-election-metadata",
+					LeaderElectionLabels:    map[string]string{"version": "not a label value"},
+				})
+				Expect(err).To(MatchError(ContainSubstring("LeaderElectionLabels")))
+			})
+			It("should reject invalid LeaderElectionLocks", func() {
+				for _, locks := range [][]leaderelection.LockConfig{
+					{{Name: "new"}},
+					{{}, {Name: "new"}, {Name: "newer"}},
+					{{ResourceLock: "configmaps"}, {Name: "new"}},
+					{{}, {}},
+					{{ResourceLock: resourcelock.ConfigMapsLeasesResourceLock}, {}},
+				} {
+					_, err := New(cfg, Options{
+						LeaderElection:          true,
+						LeaderElectionID:        "controller-runtime",
+						LeaderElectionNamespace: "my-ns",
+						LeaderElectionLocks:     locks,
+					})
+					Expect(err).To(HaveOccurred(), "locks: %v", locks)
+					Expect(err.Error()).To(ContainSubstring("LeaderElectionLocks"))
+				}
+			})
+
+			It("should not elect a candidate using LeaderElectionLocks while the old lock is held", func() {
+				m1, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionNamespace: "default",
+					LeaderElectionID:        "test-leader-election-old",
+					HealthProbeBindAddress:  "0",
+					MetricsBindAddress:      "0",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				m1.(*controllerManager).onStoppedLeading = func() {}
+
+				m2, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionNamespace: "default",
+					LeaderElectionLocks: []leaderelection.LockConfig{
+						{Name: "test-leader-election-old"},
+						{Name: "test-leader-election-new"},
+					},
+					HealthProbeBindAddress: "0",
+					MetricsBindAddress:     "0",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				m2.(*controllerManager).onStoppedLeading = func() {}
+
+				ctx1, cancel1 := context.WithCancel(context.Background())
+				defer cancel1()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m1.Start(ctx1)).NotTo(HaveOccurred())
+				}()
+				<-m1.Elected()
+
+				ctx2, cancel2 := context.WithCancel(context.Background())
+				m2done := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					Expect(m2.Start(ctx2)).NotTo(HaveOccurred())
+					close(m2done)
+				}()
+				Consistently(m2.Elected()).ShouldNot(BeClosed())
+
+				By("checking that the new lock was not taken either")
+				_, err = clientset.CoordinationV1().Leases("default").Get(context.Background(), "test-leader-election-new", metav1.GetOptions{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+				cancel2()
+				<-m2done
+			})
+
+			It("should start the runnables of a leader election group once it leads the group", func() {
+				m1, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionNamespace: "default",
+					LeaderElectionID:        "test-leader-election-groups",
+					HealthProbeBindAddress:  "0",
+					MetricsBindAddress:      "0",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				m1.(*controllerManager).onStoppedLeading = func() {}
+
+				m2, err := New(cfg, Options{
+					LeaderElection:          true,
+					LeaderElectionNamespace: "default",
+					LeaderElectionID:        "test-leader-election-groups",
+					LeaderElectionIdentity:  "test-identity",
+					HealthProbeBindAddress:  "0",
+					MetricsBindAddress:      "0",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				m2.(*controllerManager).onStoppedLeading = func() {}
+				leaderElectionStarted := make(chan struct{})
+				Expect(m2.Add(RunnableFunc(func(ctx context.Context) error {
+					close(leaderElectionStarted)
+					<-ctx.Done()
+					return nil
+				}))).To(Succeed())
+				groupStarted := make(chan struct{})
+				Expect(m2.Add(RunnableFunc(func(ctx context.Context) error {
+					close(groupStarted)
+					<-ctx.Done()
+					return nil
+				}), InLeaderElectionGroup("test-leader-election-group"))).To(Succeed())
+
+				ctx1, cancel1 := context.WithCancel(context.Background())
+				defer cancel1()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m1.Start(ctx1)).NotTo(HaveOccurred())
+				}()
+				<-m1.Elected()
+
+				By("leading the group on the manager that isn't elected")
+				ctx2, cancel2 := context.WithCancel(context.Background())
+				m2done := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					Expect(m2.Start(ctx2)).NotTo(HaveOccurred())
+					close(m2done)
+				}()
+				Eventually(groupStarted).Should(BeClosed())
+				Consistently(leaderElectionStarted).ShouldNot(BeClosed())
+
+				lease, err := clientset.CoordinationV1().Leases("default").Get(context.Background(), "test-leader-election-group", metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*lease.Spec.HolderIdentity).To(Equal("test-identity"))
+
+				By("starting the runnables added to another group once started")
+				otherGroupStarted := make(chan struct{})
+				Expect(m2.Add(RunnableFunc(func(ctx context.Context) error {
+					close(otherGroupStarted)
+					<-ctx.Done()
+					return nil
+				}), InLeaderElectionGroup("test-leader-election-other-group"))).To(Succeed())
+				Eventually(otherGroupStarted).Should(BeClosed())
+
+				cancel2()
+				<-m2done
+			})
+
+			It("should start the runnables of the leader election groups without leader election", func() {
+				m, err := New(cfg, Options{
+					HealthProbeBindAddress: "0",
+					MetricsBindAddress:     "0",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				started := make(chan struct{})
+				Expect(m.Add(RunnableFunc(func(ctx context.Context) error {
+					close(started)
+					<-ctx.Done()
+					return nil
+				}), InLeaderElectionGroup("test-leader-election-group"))).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				Eventually(started).Should(BeClosed())
+			})
+
+			It("should add a healthz check for leader election", func() {
+				m, err := New(cfg, Options{LeaderElection: true, LeaderElectionID: "controller-runtime", LeaderElectionNamespace: "my-ns"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.(*controllerManager).healthzHandler.Checks).To(HaveKey("leaderElection"))
+
+				m, err = New(cfg, Options{
+					LeaderElection:                 true,
+					LeaderElectionID:               "controller-runtime",
+					LeaderElectionNamespace:        "my-ns",
+					LeaderElectionHealthzCheckName: "leader",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.(*controllerManager).healthzHandler.Checks).To(HaveKey("leader"))
+
+				m, err = New(cfg, Options{
+					LeaderElection:                    true,
+					LeaderElectionID:                  "controller-runtime",
+					LeaderElectionNamespace:           "my-ns",
+					DisableLeaderElectionHealthzCheck: true,
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.(*controllerManager).healthzHandler).To(BeNil())
+
+				m, err = New(cfg, Options{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m.(*controllerManager).healthzHandler).To(BeNil())
+			})
+
+			It("should fail the leader election healthz check if the lease is not renewed", func() {
+				rl, err := fakeleaderelection.NewResourceLock(nil, nil, leaderelection.Options{})
+				Expect(err).NotTo(HaveOccurred())
+				lock := &stallingResourceLock{Interface: rl, stalled: make(chan struct{}), released: make(chan struct{})}
+				defer close(lock.released)
+
+				leaseDuration, renewDeadline, retryPeriod := 2*time.Second, time.Second, 100*time.Millisecond
+				m, err := New(cfg, Options{
+					LeaderElection:                      true,
+					LeaderElectionResourceLockInterface: lock,
+					LeaseDuration:                       &leaseDuration,
+					RenewDeadline:                       &renewDeadline,
+					RetryPeriod:                         &retryPeriod,
+					HealthProbeBindAddress:              "0",
+					MetricsBindAddress:                  "0",
+				})
+				Expect(err).ToNot(HaveOccurred())
+				cm := m.(*controllerManager)
+				check := cm.healthzHandler.Checks["leaderElection"]
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).To(Succeed())
+				}()
+				<-m.Elected()
+				Expect(check(nil)).To(Succeed())
+
+				By("stalling the renewal of the lease")
+				close(lock.stalled)
+				Consistently(func() error { return check(nil) }, leaseDuration).Should(Succeed())
+				Eventually(func() error { return check(nil) }, 3*renewDeadline).Should(MatchError(ContainSubstring("failed election to renew leadership")))
+			})
+
 			It("should release lease if ElectionReleaseOnCancel is true", func() {
 				var rl resourcelock.Interface
 				m, err := New(cfg, Options{
@@ -724,6 +1225,33 @@ var _ = Describe("manger.Manager", func() {
 				<-runnableWasStarted
 			})
 
+			It("should warm up the warmup runnables before starting them", func() {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				for _, cb := range callbacks {
+					cb(m)
+				}
+
+				runnable := &warmupRunnable{warmedUp: make(chan struct{})}
+				started := make(chan struct{})
+				Expect(m.Add(runnable)).To(Succeed())
+				Expect(m.Add(RunnableFunc(func(ctx context.Context) error {
+					defer GinkgoRecover()
+					Expect(runnable.warmedUp).To(BeClosed())
+					close(started)
+					return nil
+				}))).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).ToNot(HaveOccurred())
+				}()
+
+				<-started
+			})
+
 			It("should start additional clusters before anything else", func() {
 				fakeCache := &startSignalingInformer{Cache: &informertest.FakeInformers{}}
 				options.NewCache = func(_ *rest.Config, _ cache.Options) (cache.Cache, error) {
@@ -795,7 +1323,7 @@ var _ = Describe("manger.Manager", func() {
 				defer cancel()
 				err = m.Start(ctx)
 				Expect(err).ToNot(BeNil())
-				Expect(err.Error()).To(Equal("expected error"))
+				Expect(err.Error()).To(Equal("runnable manager.RunnableFunc: expected error"))
 			})
 
 			It("should start caches added after Manager has started", func() {
@@ -984,12 +1512,54 @@ var _ = Describe("manger.Manager", func() {
 				defer cancel()
 				err = m.Start(ctx)
 				Expect(err).ToNot(BeNil())
-				eMsg := "[not feeling like that, failed waiting for all runnables to end within grace period of 1ns: context deadline exceeded]"
+				eMsg := "[runnable manager.RunnableFunc: not feeling like that, failed waiting for all runnables to end within grace period of 1ns: context deadline exceeded]"
 				Expect(err.Error()).To(Equal(eMsg))
 				Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
 				Expect(errors.Is(err, runnableError{})).To(BeTrue())
 			})
 
+			It("should wait for runnables until their own graceful shutdown timeout", func() {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				for _, cb := range callbacks {
+					cb(m)
+				}
+				m.(*controllerManager).gracefulShutdownTimeout = 1 * time.Nanosecond
+				slow := &drainingRunnable{timeout: 5 * time.Second, drain: 100 * time.Millisecond}
+				Expect(m.Add(slow)).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				managerStopDone := make(chan struct{})
+				go func() { err = m.Start(ctx); close(managerStopDone) }()
+				<-m.(*controllerManager).elected
+				Eventually(slow.started.Load).Should(BeTrue())
+				cancel()
+				<-managerStopDone
+				Expect(err).NotTo(HaveOccurred())
+				Expect(slow.returned.Load()).To(BeTrue())
+			})
+
+			It("should return the runnables that did not return within their own graceful shutdown timeout", func() {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				for _, cb := range callbacks {
+					cb(m)
+				}
+				release := make(chan struct{})
+				defer close(release)
+				stuck := &drainingRunnable{timeout: 10 * time.Millisecond, release: release}
+				Expect(m.Add(stuck)).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				managerStopDone := make(chan struct{})
+				go func() { err = m.Start(ctx); close(managerStopDone) }()
+				<-m.(*controllerManager).elected
+				Eventually(stuck.started.Load).Should(BeTrue())
+				cancel()
+				<-managerStopDone
+				Expect(err).To(MatchError(ContainSubstring("*manager.drainingRunnable")))
+			})
+
 			It("should return only stop errors if runnables dont error", func() {
 				m, err := New(cfg, options)
 				Expect(err).NotTo(HaveOccurred())
@@ -1041,11 +1611,39 @@ var _ = Describe("manger.Manager", func() {
 				defer cancel()
 				err = m.Start(ctx)
 				Expect(err).ToNot(BeNil())
-				Expect(err.Error()).To(Equal("not feeling like that"))
+				Expect(err.Error()).To(Equal("runnable manager.RunnableFunc: not feeling like that"))
 				Expect(errors.Is(err, context.DeadlineExceeded)).ToNot(BeTrue())
 				Expect(errors.Is(err, runnableError{})).To(BeTrue())
 			})
 
+			It("should return the errors of the runnables along with their names", func() {
+				m, err := New(cfg, options)
+				Expect(err).NotTo(HaveOccurred())
+				for _, cb := range callbacks {
+					cb(m)
+				}
+				Expect(m.Add(RunnableFunc(func(context.Context) error {
+					return runnableError{}
+				})))
+				Expect(m.Add(&stoppableRunnable{started: make(chan struct{}), stopped: make(chan struct{})})).To(Succeed())
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				err = m.Start(ctx)
+
+				var shutdownErr *ShutdownError
+				Expect(errors.As(err, &shutdownErr)).To(BeTrue())
+				runnableErrs := shutdownErr.RunnableErrors()
+				Expect(runnableErrs).To(HaveLen(2))
+				Expect(runnableErrs[0].Runnable).To(Equal("manager.RunnableFunc"))
+				Expect(runnableErrs[0].Err).To(Equal(runnableError{}))
+				Expect(runnableErrs[1].Runnable).To(Equal("*manager.stoppableRunnable"))
+				Expect(runnableErrs[1].Err).To(MatchError("stopped"))
+
+				var runnableErr *RunnableError
+				Expect(errors.As(err, &runnableErr)).To(BeTrue())
+				Expect(runnableErr).To(BeIdenticalTo(runnableErrs[0]))
+			})
+
 			It("should not wait for runnables if gracefulShutdownTimeout is 0", func() {
 				m, err := New(cfg, options)
 				Expect(err).NotTo(HaveOccurred())
@@ -1164,6 +1762,106 @@ var _ = Describe("manger.Manager", func() {
 				Expect(resp.StatusCode).To(Equal(200))
 			})
 
+			It("should serve metrics endpoint on the MetricsListener", func() {
+				socket := filepath.Join(GinkgoT().TempDir(), "metrics.sock")
+				ln, err := net.Listen("unix", socket)
+				Expect(err).NotTo(HaveOccurred())
+				m, err := New(cfg, Options{MetricsListener: ln})
+				Expect(err).NotTo(HaveOccurred())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				<-m.Elected()
+
+				client := http.Client{Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+					},
+				}}
+				resp, err := client.Get("http://localhost/metrics")
+				Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(200))
+			})
+
+			It("should serve metrics endpoint over HTTPS with a self-signed certificate", func() {
+				opts.MetricsBindAddress = ":0"
+				opts.Metrics.SecureServing = true
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				<-m.Elected()
+
+				client := &http.Client{Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+				}}
+				resp, err := client.Get(fmt.Sprintf("https://%s/metrics", listener.Addr().String()))
+				Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(200))
+				Expect(resp.TLS).NotTo(BeNil())
+			})
+
+			It("should filter the requests to the metrics endpoints", func() {
+				opts.MetricsBindAddress = ":0"
+				opts.Metrics.FilterProvider = func(*rest.Config) (metrics.Filter, error) {
+					return func(_ logr.Logger, handler http.Handler) (http.Handler, error) {
+						return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+							if req.Header.Get("Authorization") == "" {
+								http.Error(w, "Unauthorized", http.StatusUnauthorized)
+								return
+							}
+							handler.ServeHTTP(w, req)
+						}), nil
+					}, nil
+				}
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(m.AddMetricsExtraHandler("/debug", http.NotFoundHandler())).To(Succeed())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				<-m.Elected()
+
+				for _, path := range []string{"/metrics", "/debug"} {
+					resp, err := http.Get(fmt.Sprintf("http://%s%s", listener.Addr().String(), path))
+					Expect(err).NotTo(HaveOccurred())
+					resp.Body.Close()
+					Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+				}
+
+				req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/metrics", listener.Addr().String()), nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Authorization", "Bearer token")
+				resp, err := http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			It("should fail to create the manager if the filter can't be created", func() {
+				opts.MetricsBindAddress = ":0"
+				opts.Metrics.FilterProvider = func(*rest.Config) (metrics.Filter, error) {
+					return nil, errors.New("no filter")
+				}
+				_, err := New(cfg, opts)
+				Expect(err).To(MatchError(ContainSubstring("no filter")))
+			})
+
 			It("should not serve anything other than metrics endpoint by default", func() {
 				opts.MetricsBindAddress = ":0"
 				m, err := New(cfg, opts)
@@ -1224,6 +1922,79 @@ var _ = Describe("manger.Manager", func() {
 				Expect(ok).To(BeTrue())
 			})
 
+			It("should serve metrics in the registry passed in the options", func() {
+				registry := prometheus.NewRegistry()
+				one := prometheus.NewCounter(prometheus.CounterOpts{
+					Name: "test_registry_one",
+					Help: "test metric for testing",
+				})
+				one.Inc()
+				Expect(registry.Register(one)).To(Succeed())
+
+				opts.MetricsBindAddress = ":0"
+				opts.Metrics.Registry = registry
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(m.GetMetricsRegistry()).To(BeIdenticalTo(registry))
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				<-m.Elected()
+
+				metricsEndpoint := fmt.Sprintf("http://%s/metrics", listener.Addr().String())
+				resp, err := http.Get(metricsEndpoint)
+				Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(200))
+
+				data, err := io.ReadAll(resp.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(ContainSubstring("test_registry_one 1"))
+				Expect(string(data)).NotTo(ContainSubstring("go_goroutines"))
+			})
+
+			It("should serve exemplars in the OpenMetrics format", func() {
+				one := prometheus.NewHistogram(prometheus.HistogramOpts{
+					Name:    "test_one",
+					Help:    "test metric for testing",
+					Buckets: []float64{1},
+				})
+				one.(prometheus.ExemplarObserver).ObserveWithExemplar(0.5, prometheus.Labels{"trace_id": "abc"})
+				err := metrics.Registry.Register(one)
+				Expect(err).NotTo(HaveOccurred())
+				defer metrics.Registry.Unregister(one)
+
+				opts.MetricsBindAddress = ":0"
+				m, err := New(cfg, opts)
+				Expect(err).NotTo(HaveOccurred())
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go func() {
+					defer GinkgoRecover()
+					Expect(m.Start(ctx)).NotTo(HaveOccurred())
+				}()
+				<-m.Elected()
+
+				metricsEndpoint := fmt.Sprintf("http://%s/metrics", listener.Addr().String())
+				req, err := http.NewRequest(http.MethodGet, metricsEndpoint, nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+				resp, err := http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(200))
+				Expect(resp.Header.Get("Content-Type")).To(HavePrefix("application/openmetrics-text"))
+
+				data, err := io.ReadAll(resp.Body)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(ContainSubstring(`test_one_bucket{le="1.0"} 1 # {trace_id="abc"} 0.5`))
+			})
+
 			It("should serve extra endpoints", func() {
 				opts.MetricsBindAddress = ":0"
 				m, err := New(cfg, opts)
@@ -1261,6 +2032,66 @@ var _ = Describe("manger.Manager", func() {
 		})
 	})
 
+	Context("should start serving pprof", func() {
+		var listener net.Listener
+		var opts Options
+
+		BeforeEach(func() {
+			listener = nil
+			opts = Options{
+				newPprofListener: func(addr string) (net.Listener, error) {
+					var err error
+					listener, err = defaultPprofListener(addr)
+					return listener, err
+				},
+			}
+		})
+
+		AfterEach(func() {
+			if listener != nil {
+				listener.Close()
+			}
+		})
+
+		It("should not serve pprof by default", func() {
+			m, err := New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.(*controllerManager).pprofListener).To(BeNil())
+		})
+
+		It("should serve pprof until stop is called", func() {
+			opts.PprofBindAddress = ":0"
+			m, err := New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			endpoint := fmt.Sprintf("http://%s/debug/pprof/", listener.Addr().String())
+			Eventually(func() (int, error) {
+				resp, err := http.Get(endpoint)
+				if err != nil {
+					return 0, err
+				}
+				defer resp.Body.Close()
+				return resp.StatusCode, nil
+			}).Should(Equal(http.StatusOK))
+
+			// Shutdown the server
+			cancel()
+
+			// Expect the pprof server to shutdown
+			Eventually(func() error {
+				_, err = http.Get(endpoint)
+				return err
+			}, 10*time.Second).ShouldNot(Succeed())
+		})
+	})
+
 	Context("should start serving health probes", func() {
 		var listener net.Listener
 		var opts Options
@@ -1364,6 +2195,92 @@ var _ = Describe("manger.Manager", func() {
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 		})
 
+		It("should include the errors of failed checks if HealthProbeIncludeErrors is set", func() {
+			opts.HealthProbeBindAddress = ":0"
+			opts.HealthProbeIncludeErrors = true
+			m, err := New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.AddReadyzCheck("check", func(_ *http.Request) error { return fmt.Errorf("not ready yet") })).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			resp, err := http.Get(fmt.Sprint("http://", listener.Addr().String(), defaultReadinessEndpoint))
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusInternalServerError))
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(body)).To(ContainSubstring("[-]check failed: not ready yet"))
+		})
+
+		It("should serve the checks added and removed once the manager is started", func() {
+			opts.HealthProbeBindAddress = ":0"
+			m, err := New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			get := func(endpoint string) int {
+				resp, err := http.Get(fmt.Sprint("http://", listener.Addr().String(), endpoint))
+				Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				return resp.StatusCode
+			}
+			Eventually(func() error {
+				_, err := http.Get(fmt.Sprint("http://", listener.Addr().String(), defaultReadinessEndpoint))
+				return err
+			}).Should(Succeed())
+
+			Expect(m.AddReadyzCheck("tenant", func(_ *http.Request) error { return fmt.Errorf("not ready yet") })).To(Succeed())
+			Expect(m.AddHealthzCheck("tenant", func(_ *http.Request) error { return fmt.Errorf("not alive") })).To(Succeed())
+			Expect(get(defaultReadinessEndpoint)).To(Equal(http.StatusInternalServerError))
+			Expect(get(defaultLivenessEndpoint)).To(Equal(http.StatusInternalServerError))
+
+			Expect(m.RemoveReadyzCheck("tenant")).To(Succeed())
+			Expect(m.RemoveHealthzCheck("tenant")).To(Succeed())
+			Expect(get(defaultReadinessEndpoint)).To(Equal(http.StatusOK))
+			Expect(get(defaultLivenessEndpoint)).To(Equal(http.StatusOK))
+
+			Expect(m.RemoveReadyzCheck("tenant")).NotTo(Succeed())
+		})
+
+		It("should add a readiness check for the sync status of the cache if ReadinessIncludesCacheSync is set", func() {
+			opts.HealthProbeBindAddress = ":0"
+			opts.ReadinessIncludesCacheSync = true
+			m, err := New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			checkEndpoint := fmt.Sprint("http://", listener.Addr().String(), path.Join(defaultReadinessEndpoint, cacheSyncReadyzCheckName))
+			Eventually(func() (int, error) {
+				resp, err := http.Get(checkEndpoint)
+				if err != nil {
+					return 0, err
+				}
+				defer resp.Body.Close()
+				return resp.StatusCode, nil
+			}).Should(Equal(http.StatusOK))
+		})
+
 		It("should serve liveness endpoint", func() {
 			opts.HealthProbeBindAddress = ":0"
 			m, err := New(cfg, opts)
@@ -1418,6 +2335,106 @@ var _ = Describe("manger.Manager", func() {
 			defer resp.Body.Close()
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 		})
+
+		It("should serve health probes on the HealthProbeListener", func() {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			m, err := New(cfg, Options{HealthProbeListener: ln})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.AddHealthzCheck("ping", func(_ *http.Request) error { return nil })).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			resp, err := http.Get(fmt.Sprint("http://", ln.Addr().String(), defaultLivenessEndpoint))
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		It("should serve the status of the manager", func() {
+			opts.HealthProbeBindAddress = ":0"
+			opts.StatusEndpointName = "/statusz"
+			m, err := New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Add(RunnableFunc(func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			}))).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			statusEndpoint := fmt.Sprint("http://", listener.Addr().String(), "/statusz")
+			getStatus := func(g Gomega) Status {
+				resp, err := http.Get(statusEndpoint)
+				g.Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				g.Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+				var status Status
+				g.Expect(json.NewDecoder(resp.Body).Decode(&status)).To(Succeed())
+				return status
+			}
+			Eventually(func(g Gomega) {
+				status := getStatus(g)
+				g.Expect(status.LeaderElection).To(Equal(LeaderElectionStatus{Elected: true}))
+				g.Expect(status.Cache.Started).To(BeTrue())
+				g.Expect(status.Runnables).To(ContainElement(RunnableStatus{
+					Name:  "manager.RunnableFunc",
+					Group: string(StartPhaseLeaderElection),
+					State: RunnableStateRunning,
+				}))
+			}).Should(Succeed())
+		})
+
+		It("should serve the effective configuration of the manager", func() {
+			opts.HealthProbeBindAddress = ":0"
+			opts.ConfigzEndpointName = "/configz"
+			opts.Namespace = "default"
+			syncPeriod := time.Hour
+			opts.SyncPeriod = &syncPeriod
+			m, err := New(cfg, opts)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(m.Add(&configzController{ControllerConfigz{Name: "foo", MaxConcurrentReconciles: 2}})).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			configzEndpoint := fmt.Sprint("http://", listener.Addr().String(), "/configz")
+			Eventually(func(g Gomega) {
+				resp, err := http.Get(configzEndpoint)
+				g.Expect(err).NotTo(HaveOccurred())
+				defer resp.Body.Close()
+				g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				var configz Configz
+				g.Expect(json.NewDecoder(resp.Body).Decode(&configz)).To(Succeed())
+				g.Expect(configz.Cache).To(Equal(CacheConfigz{
+					Namespaces: []string{"default"},
+					SyncPeriod: &metav1.Duration{Duration: time.Hour},
+				}))
+				g.Expect(configz.LeaderElection.Enabled).To(BeFalse())
+				g.Expect(configz.HealthProbeBindAddress).To(Equal(listener.Addr().String()))
+				g.Expect(configz.Controllers).To(Equal([]ControllerConfigz{{Name: "foo", MaxConcurrentReconciles: 2}}))
+			}).Should(Succeed())
+		})
 	})
 
 	Describe("Add", func() {
@@ -1493,6 +2510,55 @@ var _ = Describe("manger.Manager", func() {
 			Expect(m.Add(&failRec{})).To(HaveOccurred())
 		})
 	})
+	Describe("Remove", func() {
+		It("should stop the Runnable without stopping the Manager", func() {
+			m, err := New(cfg, Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			mgrDone := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(mgrDone)
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+
+			rn := &stoppableRunnable{started: make(chan struct{}), stopped: make(chan struct{})}
+			Expect(m.Add(rn)).To(Succeed())
+			<-rn.started
+
+			Expect(m.Remove(rn)).To(Succeed())
+			Expect(rn.stopped).To(BeClosed())
+			Consistently(mgrDone).ShouldNot(BeClosed())
+			Expect(m.Remove(rn)).To(HaveOccurred())
+		})
+
+		It("should not start a Runnable removed before the Manager is started", func() {
+			m, err := New(cfg, Options{})
+			Expect(err).NotTo(HaveOccurred())
+
+			rn := &stoppableRunnable{started: make(chan struct{}), stopped: make(chan struct{})}
+			Expect(m.Add(rn)).To(Succeed())
+			Expect(m.Remove(rn)).To(Succeed())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.Start(ctx)).NotTo(HaveOccurred())
+			}()
+			<-m.Elected()
+			Consistently(rn.started).ShouldNot(BeClosed())
+		})
+
+		It("should fail if the Runnable was not added", func() {
+			m, err := New(cfg, Options{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m.Remove(&stoppableRunnable{})).To(HaveOccurred())
+		})
+	})
 	Describe("SetFields", func() {
 		It("should inject field values", func() {
 			m, err := New(cfg, Options{
@@ -1858,3 +2924,48 @@ func (c *startClusterAfterManager) Start(ctx context.Context) error {
 func (c *startClusterAfterManager) GetCache() cache.Cache {
 	return c.informer
 }
+
+// stallingResourceLock is a resourcelock.Interface whose updates block once stalled
+// is closed, like a leader elector that is deadlocked, until released is closed.
+type stallingResourceLock struct {
+	resourcelock.Interface
+	stalled  chan struct{}
+	released chan struct{}
+}
+
+func (l *stallingResourceLock) Update(ctx context.Context, ler resourcelock.LeaderElectionRecord) error {
+	select {
+	case <-l.stalled:
+		<-l.released
+		return errors.New("stalled")
+	default:
+		return l.Interface.Update(ctx, ler)
+	}
+}
+
+// stoppableRunnable returns an error once it's stopped.
+type stoppableRunnable struct {
+	started chan struct{}
+	stopped chan struct{}
+}
+
+func (r *stoppableRunnable) Start(ctx context.Context) error {
+	close(r.started)
+	<-ctx.Done()
+	close(r.stopped)
+	return errors.New("stopped")
+}
+
+// configzController is a Runnable that reports its configuration like a controller.
+type configzController struct {
+	configz ControllerConfigz
+}
+
+func (c *configzController) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (c *configzController) Configz() ControllerConfigz {
+	return c.configz
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	certutil "k8s.io/client-go/util/cert"
+
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
+)
+
+// metricsTLSConfig is the TLS configuration of the metrics endpoint when it's served over HTTPS.
+type metricsTLSConfig struct {
+	config *tls.Config
+	// watcher reloads the certificate of the endpoint, nil if it's self-signed.
+	watcher *certwatcher.CertWatcher
+}
+
+// newMetricsTLSConfig reads the certificate of the metrics endpoint, or generates a
+// self-signed one if it has no CertDir.
+func newMetricsTLSConfig(o MetricsOptions) (*metricsTLSConfig, error) {
+	c := &metricsTLSConfig{
+		config: &tls.Config{ //nolint:gosec // The minimum version can be set with TLSOpts.
+			NextProtos: []string{"h2"},
+		},
+	}
+
+	if o.CertDir == "" {
+		certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("localhost", nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate the self-signed certificate of the metrics endpoint: %w", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the self-signed certificate of the metrics endpoint: %w", err)
+		}
+		c.config.Certificates = []tls.Certificate{cert}
+	} else {
+		certName, keyName := o.CertName, o.KeyName
+		if certName == "" {
+			certName = "tls.crt"
+		}
+		if keyName == "" {
+			keyName = "tls.key"
+		}
+		certPath, keyPath := filepath.Join(o.CertDir, certName), filepath.Join(o.CertDir, keyName)
+		watcher, err := certwatcher.New(certPath, keyPath, certwatcher.WithName("metrics"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the certificate of the metrics endpoint: %w", err)
+		}
+		c.watcher = watcher
+		c.config.GetCertificate = watcher.GetCertificate
+	}
+
+	for _, op := range o.TLSOpts {
+		op(c.config)
+	}
+	return c, nil
+}
+
+// listen returns a TLS listener wrapping the listener of the metrics endpoint, and
+// reloads the certificate until the context is done.
+func (c *metricsTLSConfig) listen(ctx context.Context, log logr.Logger, server *http.Server, ln net.Listener) net.Listener {
+	if c.watcher != nil {
+		go func() {
+			if err := c.watcher.Start(ctx); err != nil {
+				log.Error(err, "certificate watcher error")
+			}
+		}()
+	}
+	httpserver.ConfigureHTTP2(server, c.config)
+	return tls.NewListener(ln, c.config)
+}
@@ -3,8 +3,14 @@ package manager
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
+	"github.com/go-logr/logr"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
@@ -18,6 +24,21 @@ type readyRunnable struct {
 	Runnable
 	Check       runnableCheck
 	signalReady bool
+	// restartable is whether the runnable is restarted with the restart policy of
+	// the group when it returns an error.
+	restartable bool
+
+	// ctx is the context the runnable is started with, which is cancelled when
+	// the runnable is removed.
+	ctx    context.Context
+	cancel context.CancelFunc
+	// done is closed once the runnable returned, or was dropped.
+	done chan struct{}
+
+	// state and err are the state of the runnable and its last error, which are
+	// guarded by the start lock of the group.
+	state RunnableState
+	err   error
 }
 
 // runnableCheck can be passed to Add() to let the runnable group determine that a
@@ -31,7 +52,20 @@ type runnables struct {
 	Webhooks       *runnableGroup
 	Caches         *runnableGroup
 	LeaderElection *runnableGroup
+	Warmup         *runnableGroup
 	Others         *runnableGroup
+
+	// baseContext, errChan, restartPolicy and logger are those of the groups of the
+	// leader election groups.
+	baseContext   BaseContextFunc
+	errChan       chan error
+	restartPolicy *RestartPolicy
+	logger        logr.Logger
+
+	// leaderElectionGroups are the groups of the runnables that are started once the
+	// manager holds the lease of their group, by the name of the lease.
+	leaderElectionGroupsMu sync.Mutex
+	leaderElectionGroups   map[string]*runnableGroup
 }
 
 // newRunnables creates a new runnables object.
@@ -40,7 +74,12 @@ func newRunnables(baseContext BaseContextFunc, errChan chan error) *runnables {
 		Webhooks:       newRunnableGroup(baseContext, errChan),
 		Caches:         newRunnableGroup(baseContext, errChan),
 		LeaderElection: newRunnableGroup(baseContext, errChan),
+		Warmup:         newRunnableGroup(baseContext, errChan),
 		Others:         newRunnableGroup(baseContext, errChan),
+
+		baseContext:          baseContext,
+		errChan:              errChan,
+		leaderElectionGroups: map[string]*runnableGroup{},
 	}
 }
 
@@ -50,22 +89,136 @@ func newRunnables(baseContext BaseContextFunc, errChan chan error) *runnables {
 // Add should return an error when called during StopAndWait.
 // The runnables added before Start are started when Start is called.
 // The runnables added after Start are started directly.
-func (r *runnables) Add(fn Runnable) error {
+func (r *runnables) Add(fn Runnable, opts ...AddOption) error {
+	options := &addOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	// The warmup of a runnable is run on its own, before the runnable is started.
+	if _, ok := fn.(WarmupRunnable); ok {
+		if err := r.Warmup.Add(&runnableWarmup{runnable: fn}, nil); err != nil {
+			return err
+		}
+	}
+
+	// The webhooks must be served by every replica, as the API server calls any of them.
+	if _, ok := fn.(*webhook.Server); ok && (options.leaderElectionGroup != "" || (options.needLeaderElection != nil && *options.needLeaderElection)) {
+		return errors.New("a webhook server can't require leader election, as the webhooks must be served by every replica")
+	}
+
+	if options.leaderElectionGroup != "" {
+		return r.leaderElectionGroup(options.leaderElectionGroup).Add(fn, readyCheckOf(fn), opts...)
+	}
+
+	phase := startPhaseOf(fn)
+	if options.needLeaderElection != nil {
+		switch {
+		case *options.needLeaderElection:
+			phase = StartPhaseLeaderElection
+		case phase == StartPhaseLeaderElection:
+			phase = StartPhaseAfterCaches
+		}
+	}
+	return r.addToPhase(fn, phase, opts...)
+}
+
+// startPhaseOf returns the start phase of a runnable, which is the one it declares if
+// it's a PhasedRunnable.
+func startPhaseOf(fn Runnable) StartPhase {
+	if phased, ok := fn.(PhasedRunnable); ok {
+		return phased.StartPhase()
+	}
+
 	switch runnable := fn.(type) {
 	case hasCache:
-		return r.Caches.Add(fn, func(ctx context.Context) bool {
-			return runnable.GetCache().WaitForCacheSync(ctx)
-		})
+		return StartPhaseCaches
 	case *webhook.Server:
-		return r.Webhooks.Add(fn, nil)
+		return StartPhaseWebhooks
 	case LeaderElectionRunnable:
 		if !runnable.NeedLeaderElection() {
-			return r.Others.Add(fn, nil)
+			return StartPhaseAfterCaches
 		}
-		return r.LeaderElection.Add(fn, nil)
+		return StartPhaseLeaderElection
 	default:
-		return r.LeaderElection.Add(fn, nil)
+		return StartPhaseLeaderElection
+	}
+}
+
+// readyCheckOf returns the ready check of a runnable. Runnables with a cache are ready
+// once their cache is synced, whatever the group they're started in.
+func readyCheckOf(fn Runnable) runnableCheck {
+	if runnable, ok := fn.(hasCache); ok {
+		return func(ctx context.Context) bool {
+			return runnable.GetCache().WaitForCacheSync(ctx)
+		}
+	}
+	return nil
+}
+
+// addToPhase adds a runnable to the group of the given start phase.
+func (r *runnables) addToPhase(fn Runnable, phase StartPhase, opts ...AddOption) error {
+	ready := readyCheckOf(fn)
+	switch phase {
+	case StartPhaseWebhooks:
+		return r.Webhooks.Add(fn, ready, opts...)
+	case StartPhaseCaches:
+		return r.Caches.Add(fn, ready, opts...)
+	case StartPhaseAfterCaches:
+		return r.Others.Add(fn, ready, opts...)
+	case StartPhaseLeaderElection:
+		return r.LeaderElection.Add(fn, ready, opts...)
+	default:
+		return fmt.Errorf("unknown start phase %q of runnable %T", phase, fn)
+	}
+}
+
+// Remove stops a runnable and removes it from its group, along with its warmup. It
+// returns false if the runnable wasn't added, or already returned.
+func (r *runnables) Remove(fn Runnable) bool {
+	removed := r.Warmup.Remove(func(rn Runnable) bool {
+		w, ok := rn.(*runnableWarmup)
+		return ok && sameRunnable(w.runnable, fn)
+	})
+	for _, group := range append([]*runnableGroup{r.Webhooks, r.Caches, r.LeaderElection, r.Others}, r.sortedLeaderElectionGroups()...) {
+		if group.Remove(func(rn Runnable) bool { return sameRunnable(rn, fn) }) {
+			removed = true
+		}
+	}
+	return removed
+}
+
+// gracefulShutdownTimeout returns the longest graceful shutdown timeout of the
+// GracefulShutdownRunnables, or zero if there are none.
+func (r *runnables) gracefulShutdownTimeout() time.Duration {
+	var timeout time.Duration
+	for _, group := range append([]*runnableGroup{r.Webhooks, r.Caches, r.LeaderElection, r.Warmup, r.Others}, r.sortedLeaderElectionGroups()...) {
+		if t := group.gracefulShutdownTimeout(); t > timeout {
+			timeout = t
+		}
+	}
+	return timeout
+}
+
+// runnableWarmup is the warmup of a WarmupRunnable, which is run as a runnable of
+// the warmup group.
+type runnableWarmup struct {
+	runnable Runnable
+}
+
+// Start implements Runnable.
+func (w *runnableWarmup) Start(ctx context.Context) error {
+	return w.runnable.(WarmupRunnable).Warmup(ctx)
+}
+
+// sameRunnable returns whether a and b are the same runnable. Runnables of types that
+// can't be compared, such as RunnableFunc, are never the same as any runnable.
+func sameRunnable(a, b interface{}) bool {
+	t := reflect.TypeOf(a)
+	if t == nil || t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
 	}
+	return a == b
 }
 
 // runnableGroup manages a group of runnables that are
@@ -82,6 +235,11 @@ type runnableGroup struct {
 	started      bool
 	startQueue   []*readyRunnable
 	startReadyCh chan *readyRunnable
+	// runnables are the runnables that were added and are yet to return.
+	runnables []*readyRunnable
+	// tracked are the runnables that were added and not removed, whose status is
+	// reported by statuses.
+	tracked []*readyRunnable
 
 	stop     sync.RWMutex
 	stopOnce sync.Once
@@ -98,6 +256,11 @@ type runnableGroup struct {
 	// wg is an internal sync.WaitGroup that allows us to properly stop
 	// and wait for all the runnables to finish before returning.
 	wg *sync.WaitGroup
+
+	// restartPolicy is the policy with which the restartable runnables that return
+	// an error are restarted, unless they have their own. See supervise.
+	restartPolicy *RestartPolicy
+	logger        logr.Logger
 }
 
 func newRunnableGroup(baseContext BaseContextFunc, errChan chan error) *runnableGroup {
@@ -106,6 +269,7 @@ func newRunnableGroup(baseContext BaseContextFunc, errChan chan error) *runnable
 		errChan:      errChan,
 		ch:           make(chan *readyRunnable),
 		wg:           new(sync.WaitGroup),
+		logger:       logf.RuntimeLog.WithName("runnable-group"),
 	}
 
 	r.ctx, r.cancel = context.WithCancel(baseContext())
@@ -187,6 +351,7 @@ func (r *runnableGroup) reconcile() {
 				// Drop any runnables if we're stopped.
 				r.errChan <- errRunnableGroupStopped
 				r.stop.RUnlock()
+				r.forget(runnable)
 				continue
 			}
 
@@ -202,7 +367,8 @@ func (r *runnableGroup) reconcile() {
 		// Start the runnable.
 		go func(rn *readyRunnable) {
 			go func() {
-				if rn.Check(r.ctx) {
+				// A runnable that is removed before it's ready doesn't hold up Start.
+				if rn.Check(rn.ctx) || r.removed(rn) {
 					if rn.signalReady {
 						r.startReadyCh <- rn
 					}
@@ -214,10 +380,19 @@ func (r *runnableGroup) reconcile() {
 			//
 			// We should always decrement the WaitGroup here.
 			defer r.wg.Done()
-
-			// Start the runnable.
-			if err := rn.Start(r.ctx); err != nil {
-				r.errChan <- err
+			defer r.forget(rn)
+
+			// Start the runnable. The errors of a runnable that is removed don't
+			// stop the manager.
+			r.setState(rn, RunnableStateRunning, nil)
+			err := r.run(rn)
+			if err != nil {
+				r.setState(rn, RunnableStateErrored, err)
+			} else {
+				r.setState(rn, RunnableStateStopped, nil)
+			}
+			if err != nil && !r.removed(rn) {
+				r.errChan <- newRunnableError(rn.Runnable, err)
 			}
 		}(runnable)
 	}
@@ -225,7 +400,12 @@ func (r *runnableGroup) reconcile() {
 
 // Add should be able to be called before and after Start, but not after StopAndWait.
 // Add should return an error when called during StopAndWait.
-func (r *runnableGroup) Add(rn Runnable, ready runnableCheck) error {
+func (r *runnableGroup) Add(rn Runnable, ready runnableCheck, opts ...AddOption) error {
+	options := &addOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	r.stop.RLock()
 	if r.stopped {
 		r.stop.RUnlock()
@@ -238,9 +418,13 @@ func (r *runnableGroup) Add(rn Runnable, ready runnableCheck) error {
 	}
 
 	readyRunnable := &readyRunnable{
-		Runnable: rn,
-		Check:    ready,
+		Runnable:    rn,
+		Check:       ready,
+		restartable: options.restartable,
+		done:        make(chan struct{}),
+		state:       RunnableStatePending,
 	}
+	readyRunnable.ctx, readyRunnable.cancel = context.WithCancel(r.ctx)
 
 	// Handle start.
 	// If the overall runnable group isn't started yet
@@ -248,6 +432,8 @@ func (r *runnableGroup) Add(rn Runnable, ready runnableCheck) error {
 	// queue them up again later.
 	{
 		r.start.Lock()
+		r.runnables = append(r.runnables, readyRunnable)
+		r.tracked = append(r.tracked, readyRunnable)
 
 		// Check if we're already started.
 		if !r.started {
@@ -264,8 +450,82 @@ func (r *runnableGroup) Add(rn Runnable, ready runnableCheck) error {
 	return nil
 }
 
-// StopAndWait waits for all the runnables to finish before returning.
-func (r *runnableGroup) StopAndWait(ctx context.Context) {
+// Remove cancels the context of the first runnable that matches, and waits for it to
+// return. A runnable that is removed before the group starts is never started. It
+// returns false if no runnable that is yet to return matches.
+func (r *runnableGroup) Remove(match func(Runnable) bool) bool {
+	r.start.Lock()
+	var rn *readyRunnable
+	for i, existing := range r.runnables {
+		if match(existing.Runnable) {
+			rn = existing
+			r.runnables = append(r.runnables[:i], r.runnables[i+1:]...)
+			break
+		}
+	}
+	if rn == nil {
+		r.start.Unlock()
+		return false
+	}
+	for i, existing := range r.tracked {
+		if existing == rn {
+			r.tracked = append(r.tracked[:i], r.tracked[i+1:]...)
+			break
+		}
+	}
+	// Once the group started, Start waits for the runnables of the start queue to
+	// signal, which they do when they're removed.
+	started := r.started
+	if !started {
+		for i, existing := range r.startQueue {
+			if existing == rn {
+				r.startQueue = append(r.startQueue[:i], r.startQueue[i+1:]...)
+				break
+			}
+		}
+	}
+	r.start.Unlock()
+
+	rn.cancel()
+	if started {
+		<-rn.done
+	}
+	return true
+}
+
+// setState sets the state of a runnable, along with its last error.
+func (r *runnableGroup) setState(rn *readyRunnable, state RunnableState, err error) {
+	r.start.Lock()
+	defer r.start.Unlock()
+	rn.state, rn.err = state, err
+}
+
+// removed returns whether the runnable was removed, rather than stopped along with
+// the group.
+func (r *runnableGroup) removed(rn *readyRunnable) bool {
+	return rn.ctx.Err() != nil && r.ctx.Err() == nil
+}
+
+// forget forgets about a runnable that returned, or was dropped.
+func (r *runnableGroup) forget(rn *readyRunnable) {
+	r.start.Lock()
+	for i, existing := range r.runnables {
+		if existing == rn {
+			r.runnables = append(r.runnables[:i], r.runnables[i+1:]...)
+			break
+		}
+	}
+	r.start.Unlock()
+	rn.cancel()
+	close(rn.done)
+}
+
+// StopAndWait waits for all the runnables to finish before returning, or until the
+// context is done. A GracefulShutdownRunnable is waited for until its own graceful
+// shutdown timeout instead, which starts once the group is stopped, and a RunnableError
+// is returned for each of those that didn't return in time.
+func (r *runnableGroup) StopAndWait(ctx context.Context) []error {
+	var errs []error
 	r.stopOnce.Do(func() {
 		// Close the reconciler channel once we're done.
 		defer close(r.ch)
@@ -277,9 +537,41 @@ func (r *runnableGroup) StopAndWait(ctx context.Context) {
 		r.stopped = true
 		r.stop.Unlock()
 
+		// The graceful shutdown timeouts of the runnables start once they're stopped.
+		r.start.Lock()
+		runnables := append([]*readyRunnable(nil), r.runnables...)
+		deadlines := make([]context.Context, len(runnables))
+		timeouts := make([]time.Duration, len(runnables))
+		own := make([]bool, len(runnables))
+		for i, rn := range runnables {
+			deadlines[i] = ctx
+			if runnable, ok := rn.Runnable.(GracefulShutdownRunnable); ok {
+				own[i] = true
+				timeouts[i] = runnable.GracefulShutdownTimeout()
+				var cancel context.CancelFunc
+				deadlines[i], cancel = context.WithTimeout(context.Background(), timeouts[i])
+				defer cancel()
+			}
+		}
+		r.start.Unlock()
+
 		// Cancel the internal channel.
 		r.cancel()
 
+		for i, rn := range runnables {
+			select {
+			case <-rn.done:
+			case <-deadlines[i].Done():
+				if own[i] {
+					errs = append(errs, newRunnableError(rn.Runnable, fmt.Errorf("did not return within its graceful shutdown timeout of %s: %w", timeouts[i], context.DeadlineExceeded)))
+				}
+			}
+		}
+		if len(errs) > 0 {
+			// The runnables that are late are not waited for any longer.
+			return
+		}
+
 		done := make(chan struct{})
 		go func() {
 			defer close(done)
@@ -294,4 +586,19 @@ func (r *runnableGroup) StopAndWait(ctx context.Context) {
 			// Calling context has expired, exit.
 		}
 	})
+	return errs
+}
+
+// gracefulShutdownTimeout returns the longest graceful shutdown timeout of the
+// GracefulShutdownRunnables of the group, or zero if there are none.
+func (r *runnableGroup) gracefulShutdownTimeout() time.Duration {
+	r.start.Lock()
+	defer r.start.Unlock()
+	var timeout time.Duration
+	for _, rn := range r.runnables {
+		if runnable, ok := rn.Runnable.(GracefulShutdownRunnable); ok && runnable.GracefulShutdownTimeout() > timeout {
+			timeout = runnable.GracefulShutdownTimeout()
+		}
+	}
+	return timeout
 }
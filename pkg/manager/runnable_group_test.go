@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/utils/pointer"
@@ -35,6 +36,41 @@ var _ = Describe("runnables", func() {
 		Expect(r.Webhooks.startQueue).To(HaveLen(1))
 	})
 
+	It("should add the warmup of warmup runnables to the warmup group", func() {
+		r := newRunnables(defaultBaseContext, errCh)
+		Expect(r.Add(&warmupRunnable{})).To(Succeed())
+		Expect(r.Warmup.startQueue).To(HaveLen(1))
+		Expect(r.LeaderElection.startQueue).To(HaveLen(1))
+	})
+
+	It("should remove runnables along with their warmup", func() {
+		r := newRunnables(defaultBaseContext, errCh)
+		rn := &warmupRunnable{}
+		Expect(r.Add(rn)).To(Succeed())
+		Expect(r.Remove(rn)).To(BeTrue())
+		Expect(r.Warmup.startQueue).To(BeEmpty())
+		Expect(r.LeaderElection.startQueue).To(BeEmpty())
+		Expect(r.Remove(rn)).To(BeFalse())
+	})
+
+	It("should add phased runnables to the group of their start phase", func() {
+		r := newRunnables(defaultBaseContext, errCh)
+		Expect(r.Add(&phasedRunnable{phase: StartPhaseWebhooks})).To(Succeed())
+		Expect(r.Add(&phasedRunnable{phase: StartPhaseCaches})).To(Succeed())
+		Expect(r.Add(&phasedRunnable{phase: StartPhaseAfterCaches})).To(Succeed())
+		Expect(r.Add(&phasedRunnable{phase: StartPhaseAfterCaches})).To(Succeed())
+		Expect(r.Add(&phasedRunnable{phase: StartPhaseLeaderElection})).To(Succeed())
+		Expect(r.Webhooks.startQueue).To(HaveLen(1))
+		Expect(r.Caches.startQueue).To(HaveLen(1))
+		Expect(r.Others.startQueue).To(HaveLen(2))
+		Expect(r.LeaderElection.startQueue).To(HaveLen(1))
+	})
+
+	It("should return an error for runnables of an unknown start phase", func() {
+		r := newRunnables(defaultBaseContext, errCh)
+		Expect(r.Add(&phasedRunnable{phase: "Unknown"})).To(MatchError(ContainSubstring(`unknown start phase "Unknown"`)))
+	})
+
 	It("should add any runnable to the leader election group", func() {
 		err := errors.New("runnable func")
 		runnable := RunnableFunc(func(c context.Context) error {
@@ -45,6 +81,58 @@ var _ = Describe("runnables", func() {
 		Expect(r.Add(runnable)).To(Succeed())
 		Expect(r.LeaderElection.startQueue).To(HaveLen(1))
 	})
+
+	It("should add runnables to the group required by RequireLeaderElection", func() {
+		runnable := RunnableFunc(func(c context.Context) error {
+			<-c.Done()
+			return nil
+		})
+
+		r := newRunnables(defaultBaseContext, errCh)
+		Expect(r.Add(runnable, RequireLeaderElection(false))).To(Succeed())
+		Expect(r.Add(&phasedRunnable{phase: StartPhaseLeaderElection}, RequireLeaderElection(false))).To(Succeed())
+		Expect(r.Add(&phasedRunnable{phase: StartPhaseAfterCaches}, RequireLeaderElection(true))).To(Succeed())
+		Expect(r.Add(&webhook.Server{}, RequireLeaderElection(false))).To(Succeed())
+		Expect(r.Others.startQueue).To(HaveLen(2))
+		Expect(r.LeaderElection.startQueue).To(HaveLen(1))
+		Expect(r.Webhooks.startQueue).To(HaveLen(1))
+	})
+
+	It("should keep the ready check of the caches that require leader election", func() {
+		synced := false
+		r := newRunnables(defaultBaseContext, errCh)
+		Expect(r.Add(&cacheProvider{cache: &informertest.FakeInformers{Synced: &synced}}, RequireLeaderElection(true))).To(Succeed())
+		Expect(r.Add(&cacheProvider{cache: &informertest.FakeInformers{Synced: &synced}}, InLeaderElectionGroup("group"))).To(Succeed())
+		Expect(r.LeaderElection.startQueue).To(HaveLen(1))
+		Expect(r.LeaderElection.startQueue[0].Check(context.Background())).To(BeFalse())
+		Expect(r.leaderElectionGroup("group").startQueue).To(HaveLen(1))
+		Expect(r.leaderElectionGroup("group").startQueue[0].Check(context.Background())).To(BeFalse())
+
+		synced = true
+		Expect(r.LeaderElection.startQueue[0].Check(context.Background())).To(BeTrue())
+	})
+
+	It("should only restart the runnables added with the Restartable option", func() {
+		r := newRunnables(defaultBaseContext, errCh)
+		r.supervise(&RestartPolicy{}, logr.Discard())
+		Expect(r.Add(RunnableFunc(func(context.Context) error { return nil }), Restartable())).To(Succeed())
+		Expect(r.Add(RunnableFunc(func(context.Context) error { return nil }), InLeaderElectionGroup("group"), Restartable())).To(Succeed())
+		Expect(r.Add(&phasedRunnable{phase: StartPhaseAfterCaches})).To(Succeed())
+		Expect(r.LeaderElection.startQueue).To(HaveLen(1))
+		Expect(r.LeaderElection.startQueue[0].restartable).To(BeTrue())
+		Expect(r.leaderElectionGroup("group").startQueue).To(HaveLen(1))
+		Expect(r.leaderElectionGroup("group").startQueue[0].restartable).To(BeTrue())
+		Expect(r.Others.startQueue).To(HaveLen(1))
+		Expect(r.Others.startQueue[0].restartable).To(BeFalse())
+	})
+
+	It("should reject webhook servers that require leader election", func() {
+		r := newRunnables(defaultBaseContext, errCh)
+		Expect(r.Add(&webhook.Server{}, RequireLeaderElection(true))).NotTo(Succeed())
+		Expect(r.Add(&webhook.Server{}, InLeaderElectionGroup("group"))).NotTo(Succeed())
+		Expect(r.LeaderElection.startQueue).To(BeEmpty())
+		Expect(r.Webhooks.startQueue).To(BeEmpty())
+	})
 })
 
 var _ = Describe("runnableGroup", func() {
@@ -102,6 +190,133 @@ var _ = Describe("runnableGroup", func() {
 		}
 	})
 
+	It("should report the states of its runnables", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errs := make(chan error, 1)
+		rg := newRunnableGroup(defaultBaseContext, errs)
+		Expect(rg.Add(RunnableFunc(func(c context.Context) error {
+			<-c.Done()
+			return nil
+		}), nil)).To(Succeed())
+		Expect(rg.Add(RunnableFunc(func(context.Context) error {
+			return nil
+		}), nil)).To(Succeed())
+		Expect(rg.Add(RunnableFunc(func(context.Context) error {
+			return errors.New("expected error")
+		}), nil)).To(Succeed())
+
+		states := func() []RunnableState {
+			var states []RunnableState
+			for _, status := range rg.statuses("Test") {
+				states = append(states, status.State)
+			}
+			return states
+		}
+		Expect(states()).To(Equal([]RunnableState{RunnableStatePending, RunnableStatePending, RunnableStatePending}))
+
+		Expect(rg.Start(ctx)).To(Succeed())
+		Eventually(states).Should(Equal([]RunnableState{RunnableStateRunning, RunnableStateStopped, RunnableStateErrored}))
+		statuses := rg.statuses("Test")
+		Expect(statuses[0].Name).To(Equal("manager.RunnableFunc"))
+		Expect(statuses[0].Group).To(Equal("Test"))
+		Expect(statuses[2].Error).To(Equal("expected error"))
+		Eventually(errs).Should(Receive())
+	})
+
+	It("should stop a removed runnable and wait for it to return", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rg := newRunnableGroup(defaultBaseContext, errCh)
+		Expect(rg.Start(ctx)).To(Succeed())
+
+		rn := &phasedRunnable{}
+		Expect(rg.Add(rn, nil)).To(Succeed())
+		other := &phasedRunnable{}
+		Expect(rg.Add(other, nil)).To(Succeed())
+		Expect(rg.Remove(func(r Runnable) bool { return r == rn })).To(BeTrue())
+		Expect(rg.runnables).To(HaveLen(1))
+		Expect(rg.runnables[0].Runnable).To(BeIdenticalTo(other))
+		Expect(rg.runnables[0].ctx.Err()).NotTo(HaveOccurred())
+		Expect(rg.Remove(func(r Runnable) bool { return r == rn })).To(BeFalse())
+	})
+
+	It("should restart the runnables that fail with the restart policy", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errs := make(chan error, 1)
+		rg := newRunnableGroup(defaultBaseContext, errs)
+		rg.restartPolicy = &RestartPolicy{InitialBackoff: time.Millisecond}
+		Expect(rg.Start(ctx)).To(Succeed())
+
+		var starts atomic.Int32
+		Expect(rg.Add(RunnableFunc(func(c context.Context) error {
+			if starts.Add(1) < 3 {
+				return errors.New("failed")
+			}
+			<-c.Done()
+			return nil
+		}), nil, Restartable())).To(Succeed())
+		Eventually(starts.Load).Should(BeEquivalentTo(3))
+		Consistently(errs).ShouldNot(Receive())
+	})
+
+	It("should return the error of the runnables that aren't restartable", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errs := make(chan error, 1)
+		rg := newRunnableGroup(defaultBaseContext, errs)
+		rg.restartPolicy = &RestartPolicy{InitialBackoff: time.Millisecond}
+		Expect(rg.Start(ctx)).To(Succeed())
+
+		var starts atomic.Int32
+		Expect(rg.Add(RunnableFunc(func(context.Context) error {
+			starts.Add(1)
+			return errors.New("informer already started")
+		}), nil)).To(Succeed())
+		Eventually(errs).Should(Receive(MatchError(ContainSubstring("informer already started"))))
+		Consistently(starts.Load).Should(BeEquivalentTo(1))
+	})
+
+	It("should stop restarting a runnable once it ran out of restarts", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errs := make(chan error, 1)
+		rg := newRunnableGroup(defaultBaseContext, errs)
+		Expect(rg.Start(ctx)).To(Succeed())
+
+		rn := &failingRunnable{policy: RestartPolicy{InitialBackoff: time.Millisecond, MaxRestarts: 2}}
+		Expect(rg.Add(rn, nil)).To(Succeed())
+		var err error
+		Eventually(errs).Should(Receive(&err))
+		Expect(err).To(MatchError(ContainSubstring("failed after 2 restarts")))
+		Expect(rn.starts.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should wait for runnables until their own graceful shutdown timeout", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rg := newRunnableGroup(defaultBaseContext, errCh)
+		Expect(rg.Start(ctx)).To(Succeed())
+
+		release := make(chan struct{})
+		defer close(release)
+		slow := &drainingRunnable{timeout: time.Second, drain: 100 * time.Millisecond}
+		stuck := &drainingRunnable{timeout: 10 * time.Millisecond, release: release}
+		Expect(rg.Add(slow, nil)).To(Succeed())
+		Expect(rg.Add(stuck, nil)).To(Succeed())
+		Eventually(slow.started.Load).Should(BeTrue())
+		Eventually(stuck.started.Load).Should(BeTrue())
+
+		stopCtx, stopCancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer stopCancel()
+		errs := rg.StopAndWait(stopCtx)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).To(MatchError("runnable *manager.drainingRunnable: did not return within its graceful shutdown timeout of 10ms: context deadline exceeded"))
+		Expect(slow.returned.Load()).To(BeTrue())
+		Expect(stuck.returned.Load()).To(BeFalse())
+	})
+
 	It("should be able to close the group and wait for all runnables to finish", func() {
 		ctx, cancel := context.WithCancel(context.Background())
 
@@ -180,3 +395,71 @@ var _ = Describe("runnableGroup", func() {
 		}
 	})
 })
+
+type warmupRunnable struct {
+	warmedUp chan struct{}
+}
+
+func (r *warmupRunnable) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (r *warmupRunnable) Warmup(context.Context) error {
+	if r.warmedUp != nil {
+		close(r.warmedUp)
+	}
+	return nil
+}
+
+type phasedRunnable struct {
+	phase StartPhase
+}
+
+func (r *phasedRunnable) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (r *phasedRunnable) StartPhase() StartPhase {
+	return r.phase
+}
+
+type failingRunnable struct {
+	policy RestartPolicy
+	starts atomic.Int32
+}
+
+func (r *failingRunnable) Start(context.Context) error {
+	r.starts.Add(1)
+	return errors.New("failed")
+}
+
+func (r *failingRunnable) RestartPolicy() RestartPolicy {
+	return r.policy
+}
+
+// drainingRunnable takes drain to return once it's stopped, or until it's released.
+type drainingRunnable struct {
+	timeout  time.Duration
+	drain    time.Duration
+	release  chan struct{}
+	started  atomic.Bool
+	returned atomic.Bool
+}
+
+func (r *drainingRunnable) Start(ctx context.Context) error {
+	r.started.Store(true)
+	<-ctx.Done()
+	if r.release != nil {
+		<-r.release
+	} else {
+		time.Sleep(r.drain)
+	}
+	r.returned.Store(true)
+	return nil
+}
+
+func (r *drainingRunnable) GracefulShutdownTimeout() time.Duration {
+	return r.timeout
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/internal/httpserver"
+	logf "sigs.k8s.io/controller-runtime/pkg/internal/log"
+)
+
+// defaultHTTPServerShutdownTimeout is the default time given to in-flight requests to
+// complete when the server is shut down.
+const defaultHTTPServerShutdownTimeout = 30 * time.Second
+
+// HTTPServerOption configures an HTTPServerRunnable.
+type HTTPServerOption func(*HTTPServerRunnable)
+
+// WithShutdownTimeout sets the time given to in-flight requests to complete once the
+// manager is stopped, after which the remaining connections are closed. It defaults
+// to 30 seconds.
+func WithShutdownTimeout(timeout time.Duration) HTTPServerOption {
+	return func(s *HTTPServerRunnable) {
+		s.shutdownTimeout = timeout
+	}
+}
+
+// WithCertWatcher serves TLS with the certificate of the given watcher, which is
+// started and stopped along with the server. The TLS config can be further mutated
+// with tlsOpts.
+func WithCertWatcher(watcher *certwatcher.CertWatcher, tlsOpts ...func(*tls.Config)) HTTPServerOption {
+	return func(s *HTTPServerRunnable) {
+		s.certWatcher = watcher
+		s.tlsOpts = tlsOpts
+	}
+}
+
+// WithHTTP2Disabled makes a server serving TLS only negotiate HTTP/1.1. The TLS options
+// of WithCertWatcher are applied afterwards, so they can override it either way through
+// the NextProtos of the config, which the server follows.
+func WithHTTP2Disabled() HTTPServerOption {
+	return func(s *HTTPServerRunnable) {
+		s.disableHTTP2 = true
+	}
+}
+
+// HTTPServerRunnable is a Runnable that serves an http.Server until the manager is
+// stopped. It runs on every replica, regardless of leader election.
+type HTTPServerRunnable struct {
+	server          *http.Server
+	listener        net.Listener
+	shutdownTimeout time.Duration
+	certWatcher     *certwatcher.CertWatcher
+	tlsOpts         []func(*tls.Config)
+	disableHTTP2    bool
+	logger          logr.Logger
+
+	mu      sync.Mutex
+	serving bool
+}
+
+var (
+	_ Runnable               = &HTTPServerRunnable{}
+	_ LeaderElectionRunnable = &HTTPServerRunnable{}
+)
+
+// NewHTTPServerRunnable returns a Runnable that serves srv on the given listener, or on
+// srv.Addr if listener is nil, in which case failing to bind the address makes the
+// manager fail to start.
+func NewHTTPServerRunnable(srv *http.Server, listener net.Listener, opts ...HTTPServerOption) *HTTPServerRunnable {
+	s := &HTTPServerRunnable{
+		server:          srv,
+		listener:        listener,
+		shutdownTimeout: defaultHTTPServerShutdownTimeout,
+		logger:          logf.RuntimeLog.WithName("http-server"),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// InjectLogger sets the logger of the server, which is done by the manager when the
+// server is added to it.
+func (s *HTTPServerRunnable) InjectLogger(l logr.Logger) error {
+	s.logger = l.WithName("http-server")
+	return nil
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable.
+func (s *HTTPServerRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the server until the context is done, and then shuts it down gracefully.
+func (s *HTTPServerRunnable) Start(ctx context.Context) error {
+	listener := s.listener
+	if listener == nil {
+		var err error
+		if listener, err = net.Listen("tcp", s.server.Addr); err != nil {
+			return fmt.Errorf("failed to listen on %q: %w", s.server.Addr, err)
+		}
+	}
+
+	if s.certWatcher != nil {
+		go func() {
+			if err := s.certWatcher.Start(ctx); err != nil {
+				s.logger.Error(err, "certificate watcher error")
+			}
+		}()
+
+		cfg := &tls.Config{ //nolint:gosec // the minimum version can be set with the TLS options.
+			NextProtos:     []string{"h2"},
+			GetCertificate: s.certWatcher.GetCertificate,
+		}
+		if s.disableHTTP2 {
+			httpserver.DisableHTTP2(cfg)
+		}
+		for _, op := range s.tlsOpts {
+			op(cfg)
+		}
+		httpserver.ConfigureHTTP2(s.server, cfg)
+		listener = tls.NewListener(listener, cfg)
+	}
+
+	log := s.logger.WithValues("addr", listener.Addr().String())
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		log.Info("Shutting down HTTP server")
+		shutdownErr <- s.shutdown()
+	}()
+
+	s.mu.Lock()
+	s.serving = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.serving = false
+		s.mu.Unlock()
+	}()
+
+	log.Info("Serving HTTP server")
+	if err := s.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return <-shutdownErr
+}
+
+// shutdown waits for the in-flight requests to complete until the shutdown timeout,
+// and then closes the remaining connections.
+func (s *HTTPServerRunnable) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		if closeErr := s.server.Close(); closeErr != nil {
+			s.logger.Error(closeErr, "error closing the HTTP server")
+		}
+		return fmt.Errorf("failed to shut down the HTTP server gracefully: %w", err)
+	}
+	return nil
+}
+
+// StartedChecker returns a healthz.Checker which is healthy once the server listens,
+// to be added with AddReadyzCheck.
+func (s *HTTPServerRunnable) StartedChecker() healthz.Checker {
+	return func(_ *http.Request) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !s.serving {
+			return errors.New("HTTP server is not serving")
+		}
+		return nil
+	}
+}
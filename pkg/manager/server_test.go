@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+var _ = Describe("HTTPServerRunnable", func() {
+	var (
+		listener net.Listener
+		url      string
+	)
+
+	BeforeEach(func() {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		url = fmt.Sprintf("http://%s/", listener.Addr())
+	})
+
+	get := func() (string, error) {
+		resp, err := http.Get(url)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	It("should serve until the manager is stopped and then shut down cleanly", func() {
+		m, err := New(cfg, Options{MetricsBindAddress: "0"})
+		Expect(err).NotTo(HaveOccurred())
+
+		srv := NewHTTPServerRunnable(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { //nolint:gosec
+			_, _ = w.Write([]byte("ok"))
+		})}, listener)
+		Expect(srv.NeedLeaderElection()).To(BeFalse())
+		Expect(srv.StartedChecker()(nil)).NotTo(Succeed())
+		Expect(m.Add(srv)).To(Succeed())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- m.Start(ctx)
+		}()
+
+		Eventually(get).Should(Equal("ok"))
+		Expect(srv.StartedChecker()(nil)).To(Succeed())
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+		Expect(srv.StartedChecker()(nil)).NotTo(Succeed())
+		_, err = get()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should close the remaining connections after the shutdown timeout", func() {
+		inFlight, release := make(chan struct{}), make(chan struct{})
+		defer close(release)
+		srv := NewHTTPServerRunnable(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { //nolint:gosec
+			close(inFlight)
+			<-release
+		})}, listener, WithShutdownTimeout(100*time.Millisecond))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- srv.Start(ctx)
+		}()
+		requestErr := make(chan error)
+		go func() {
+			_, err := get()
+			requestErr <- err
+		}()
+		Eventually(inFlight).Should(BeClosed())
+
+		cancel()
+		var err error
+		Eventually(done).Should(Receive(&err))
+		Expect(err).To(MatchError(ContainSubstring("failed to shut down the HTTP server gracefully")))
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Eventually(requestErr).Should(Receive(HaveOccurred()))
+	})
+
+	It("should make the manager fail to start if the address can't be bound", func() {
+		m, err := New(cfg, Options{MetricsBindAddress: "0"})
+		Expect(err).NotTo(HaveOccurred())
+
+		// The address is still bound by the listener of the spec.
+		srv := NewHTTPServerRunnable(&http.Server{Addr: listener.Addr().String()}, nil) //nolint:gosec
+		Expect(m.Add(srv)).To(Succeed())
+
+		err = m.Start(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("failed to listen on %q", listener.Addr().String())))
+		Expect(listener.Close()).To(Succeed())
+	})
+
+	It("should only negotiate HTTP/1.1 over TLS if HTTP/2 is disabled", func() {
+		servingOpts := envtest.WebhookInstallOptions{}
+		Expect(servingOpts.PrepWithoutInstalling()).To(Succeed())
+		defer func() {
+			Expect(servingOpts.Cleanup()).To(Succeed())
+		}()
+		watcher, err := certwatcher.New(filepath.Join(servingOpts.LocalServingCertDir, "tls.crt"), filepath.Join(servingOpts.LocalServingCertDir, "tls.key"))
+		Expect(err).NotTo(HaveOccurred())
+
+		srv := NewHTTPServerRunnable(&http.Server{}, listener, WithCertWatcher(watcher), WithHTTP2Disabled()) //nolint:gosec
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- srv.Start(ctx)
+		}()
+
+		caPool := x509.NewCertPool()
+		caPool.AppendCertsFromPEM(servingOpts.LocalServingCAData)
+		var conn *tls.Conn
+		Eventually(func() (err error) {
+			conn, err = tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+				RootCAs:    caPool,
+				ServerName: servingOpts.LocalServingHost,
+				NextProtos: []string{"h2", "http/1.1"},
+				MinVersion: tls.VersionTLS12,
+			})
+			return err
+		}).Should(Succeed())
+		Expect(conn.ConnectionState().NegotiatedProtocol).To(Equal("http/1.1"))
+		Expect(conn.Close()).To(Succeed())
+
+		cancel()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
@@ -24,22 +24,109 @@ import (
 
 var onlyOneSignalHandler = make(chan struct{})
 
+// exit terminates the program on a second shutdown signal, it's replaced in tests.
+var exit = os.Exit
+
+// Options configures the signal handler set up by SetupSignalHandlerWithOptions.
+type Options struct {
+	// ShutdownSignals are the signals that cancel the returned context.
+	// Defaults to SIGTERM and SIGINT, or only os.Interrupt on Windows.
+	ShutdownSignals []os.Signal
+
+	// NotifySignals are signals that are passed to OnSignal without canceling the
+	// context, e.g. SIGUSR1 to reload the configuration.
+	NotifySignals []os.Signal
+
+	// OnSignal is called with every signal that is caught, before the context is
+	// canceled in the case of a shutdown signal. It's called synchronously, so the
+	// next signal isn't handled until it returns.
+	OnSignal func(os.Signal)
+
+	// BeforeShutdown is called once the first shutdown signal is caught, and the context
+	// is canceled once it returns, e.g. to fail the readiness probe and wait for the
+	// endpoints to be updated before the manager stops. The signals keep being handled
+	// while it runs, so a second shutdown signal still terminates the program.
+	BeforeShutdown func()
+
+	// OnSecondSignal is called with the second shutdown signal that is caught, instead
+	// of terminating the program with exit code 1, e.g. to exit with another code once
+	// the logs are flushed.
+	OnSecondSignal func(os.Signal)
+
+	// DisableExitOnSecondSignal keeps the program running if a second shutdown signal
+	// is caught, for callers that handle it themselves, e.g. through OnSignal.
+	// OnSecondSignal is not called either.
+	DisableExitOnSecondSignal bool
+}
+
 // SetupSignalHandler registers for SIGTERM and SIGINT. A context is returned
 // which is canceled on one of these signals. If a second signal is caught, the program
 // is terminated with exit code 1.
 func SetupSignalHandler() context.Context {
+	return SetupSignalHandlerWithOptions(Options{})
+}
+
+// SetupSignalHandlerWithOptions registers for the given signals. A context is returned
+// which is canceled on the first shutdown signal, once BeforeShutdown returns. If a second
+// shutdown signal is caught, OnSecondSignal is called, or the program is terminated with
+// exit code 1, unless DisableExitOnSecondSignal is set.
+// Like SetupSignalHandler, it must only be called once.
+func SetupSignalHandlerWithOptions(opts Options) context.Context {
 	close(onlyOneSignalHandler) // panics when called twice
 
-	ctx, cancel := context.WithCancel(context.Background())
+	if opts.ShutdownSignals == nil {
+		opts.ShutdownSignals = shutdownSignals
+	}
 
 	c := make(chan os.Signal, 2)
-	signal.Notify(c, shutdownSignals...)
+	signal.Notify(c, append(append([]os.Signal{}, opts.ShutdownSignals...), opts.NotifySignals...)...)
+	return newSignalHandler(c, opts)
+}
+
+// newSignalHandler returns a context which is canceled once a shutdown signal is
+// received on c, and handles the signals received on c according to opts.
+func newSignalHandler(c <-chan os.Signal, opts Options) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	go func() {
-		<-c
-		cancel()
-		<-c
-		os.Exit(1) // second signal. Exit directly.
+		shuttingDown := false
+		for sig := range c {
+			if opts.OnSignal != nil {
+				opts.OnSignal(sig)
+			}
+			if !isSignal(sig, opts.ShutdownSignals) {
+				continue
+			}
+			if !shuttingDown {
+				shuttingDown = true
+				if opts.BeforeShutdown == nil {
+					cancel()
+					continue
+				}
+				go func() {
+					opts.BeforeShutdown()
+					cancel()
+				}()
+				continue
+			}
+			switch {
+			case opts.DisableExitOnSecondSignal:
+			case opts.OnSecondSignal != nil:
+				opts.OnSecondSignal(sig)
+			default:
+				exit(1) // second signal. Exit directly.
+			}
+		}
 	}()
 
 	return ctx
 }
+
+func isSignal(sig os.Signal, signals []os.Signal) bool {
+	for _, s := range signals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
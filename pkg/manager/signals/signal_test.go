@@ -17,6 +17,7 @@ limitations under the License.
 package signals
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -54,6 +55,111 @@ var _ = Describe("runtime signal", func() {
 
 	})
 
+	Context("SignalHandler with options", func() {
+		var (
+			c      chan os.Signal
+			exited chan int
+		)
+
+		BeforeEach(func() {
+			c = make(chan os.Signal, 2)
+			exited = make(chan int, 1)
+			exit = func(code int) { exited <- code }
+			DeferCleanup(func() { exit = os.Exit })
+		})
+
+		It("should call OnSignal before canceling the context", func() {
+			var canceledOnSignal []bool
+			var ctx context.Context
+			ctx = newSignalHandler(c, Options{
+				ShutdownSignals: []os.Signal{os.Interrupt},
+				OnSignal: func(sig os.Signal) {
+					Expect(sig).To(Equal(os.Interrupt))
+					canceledOnSignal = append(canceledOnSignal, ctx.Err() != nil)
+				},
+			})
+			c <- os.Interrupt
+			Eventually(ctx.Done()).Should(BeClosed())
+			Expect(canceledOnSignal).To(Equal([]bool{false}))
+		})
+
+		It("should pass notify signals to OnSignal without canceling the context", func() {
+			received := make(chan os.Signal, 2)
+			ctx := newSignalHandler(c, Options{
+				ShutdownSignals: []os.Signal{os.Interrupt},
+				NotifySignals:   []os.Signal{os.Kill},
+				OnSignal:        func(sig os.Signal) { received <- sig },
+			})
+			c <- os.Kill
+			Eventually(received).Should(Receive(Equal(os.Kill)))
+			Consistently(ctx.Done(), "100ms").ShouldNot(BeClosed())
+
+			c <- os.Interrupt
+			Eventually(received).Should(Receive(Equal(os.Interrupt)))
+			Eventually(ctx.Done()).Should(BeClosed())
+		})
+
+		It("should exit on a second shutdown signal", func() {
+			ctx := newSignalHandler(c, Options{ShutdownSignals: []os.Signal{os.Interrupt}})
+			c <- os.Interrupt
+			Eventually(ctx.Done()).Should(BeClosed())
+			Consistently(exited, "100ms").ShouldNot(Receive())
+			c <- os.Interrupt
+			Eventually(exited).Should(Receive(Equal(1)))
+		})
+
+		It("should cancel the context once BeforeShutdown returns", func() {
+			shutdown := make(chan struct{})
+			release := make(chan struct{})
+			ctx := newSignalHandler(c, Options{
+				ShutdownSignals: []os.Signal{os.Interrupt},
+				BeforeShutdown: func() {
+					close(shutdown)
+					<-release
+				},
+			})
+			c <- os.Interrupt
+			Eventually(shutdown).Should(BeClosed())
+			Consistently(ctx.Done(), "100ms").ShouldNot(BeClosed())
+
+			By("exiting on a second shutdown signal while BeforeShutdown runs")
+			c <- os.Interrupt
+			Eventually(exited).Should(Receive(Equal(1)))
+
+			close(release)
+			Eventually(ctx.Done()).Should(BeClosed())
+		})
+
+		It("should call OnSecondSignal instead of exiting on a second shutdown signal", func() {
+			second := make(chan os.Signal, 1)
+			ctx := newSignalHandler(c, Options{
+				ShutdownSignals: []os.Signal{os.Interrupt},
+				OnSecondSignal:  func(sig os.Signal) { second <- sig },
+			})
+			c <- os.Interrupt
+			Eventually(ctx.Done()).Should(BeClosed())
+			Consistently(second, "100ms").ShouldNot(Receive())
+			c <- os.Interrupt
+			Eventually(second).Should(Receive(Equal(os.Interrupt)))
+			Consistently(exited, "100ms").ShouldNot(Receive())
+		})
+
+		It("should not exit on a second shutdown signal with DisableExitOnSecondSignal", func() {
+			received := make(chan os.Signal, 2)
+			ctx := newSignalHandler(c, Options{
+				ShutdownSignals:           []os.Signal{os.Interrupt},
+				OnSignal:                  func(sig os.Signal) { received <- sig },
+				DisableExitOnSecondSignal: true,
+			})
+			c <- os.Interrupt
+			c <- os.Interrupt
+			Eventually(ctx.Done()).Should(BeClosed())
+			Eventually(received).Should(Receive())
+			Eventually(received).Should(Receive())
+			Consistently(exited, "100ms").ShouldNot(Receive())
+		})
+	})
+
 })
 
 type Task struct {
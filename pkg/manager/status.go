@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// RunnableState is the state of a Runnable added to a manager.
+type RunnableState string
+
+const (
+	// RunnableStatePending is the state of a Runnable that has not been started yet.
+	RunnableStatePending RunnableState = "Pending"
+
+	// RunnableStateRunning is the state of a Runnable that has been started and has
+	// not returned yet.
+	RunnableStateRunning RunnableState = "Running"
+
+	// RunnableStateStopped is the state of a Runnable that returned without an error.
+	RunnableStateStopped RunnableState = "Stopped"
+
+	// RunnableStateErrored is the state of a Runnable that returned an error, or that
+	// failed and waits to be restarted according to its RestartPolicy.
+	RunnableStateErrored RunnableState = "Errored"
+)
+
+// Status is the status of a manager, which is served as JSON on the StatusEndpointName
+// of the health probe server.
+type Status struct {
+	// LeaderElection is the status of the leader election of the manager.
+	LeaderElection LeaderElectionStatus `json:"leaderElection"`
+
+	// Cache is the sync status of the cache of the manager.
+	Cache CacheStatus `json:"cache"`
+
+	// Runnables are the statuses of the Runnables added to the manager, by group.
+	Runnables []RunnableStatus `json:"runnables"`
+}
+
+// LeaderElectionStatus is the status of the leader election of a manager.
+type LeaderElectionStatus struct {
+	// Enabled is whether the manager uses leader election.
+	Enabled bool `json:"enabled"`
+
+	// Identity is the identity of the manager in the leader election.
+	Identity string `json:"identity,omitempty"`
+
+	// Elected is whether the manager was elected leader, which it is as soon as it's
+	// started if leader election is disabled.
+	Elected bool `json:"elected"`
+}
+
+// CacheStatus is the sync status of the cache of a manager.
+type CacheStatus struct {
+	// Started is whether the cache has been started.
+	Started bool `json:"started"`
+
+	// Unsynced are the GroupVersionKinds of the informers of the cache that have not
+	// synced yet.
+	Unsynced []string `json:"unsynced,omitempty"`
+}
+
+// RunnableStatus is the status of a Runnable added to a manager.
+type RunnableStatus struct {
+	// Name is the type of the Runnable.
+	Name string `json:"name"`
+
+	// Group is the group of Runnables the Runnable is started with, which is its
+	// StartPhase, or Warmup for the warmup of a WarmupRunnable. The Runnables of a
+	// leader election group are in the LeaderElection/<lease name> group.
+	Group string `json:"group"`
+
+	// State is the state of the Runnable.
+	State RunnableState `json:"state"`
+
+	// Error is the last error returned by the Runnable, if it's Errored.
+	Error string `json:"error,omitempty"`
+}
+
+// statuses returns the statuses of the runnables of the group.
+func (r *runnableGroup) statuses(group string) []RunnableStatus {
+	r.start.Lock()
+	defer r.start.Unlock()
+	statuses := make([]RunnableStatus, 0, len(r.tracked))
+	for _, rn := range r.tracked {
+		status := RunnableStatus{Group: group, State: rn.state}
+		if w, ok := rn.Runnable.(*runnableWarmup); ok {
+			status.Name = fmt.Sprintf("%T", w.runnable)
+		} else {
+			status.Name = fmt.Sprintf("%T", rn.Runnable)
+		}
+		if rn.err != nil {
+			status.Error = rn.err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// statuses returns the statuses of the runnables, in the order their groups are started.
+func (r *runnables) statuses() []RunnableStatus {
+	var statuses []RunnableStatus
+	statuses = append(statuses, r.Webhooks.statuses(string(StartPhaseWebhooks))...)
+	statuses = append(statuses, r.Caches.statuses(string(StartPhaseCaches))...)
+	statuses = append(statuses, r.Warmup.statuses("Warmup")...)
+	statuses = append(statuses, r.Others.statuses(string(StartPhaseAfterCaches))...)
+	statuses = append(statuses, r.LeaderElection.statuses(string(StartPhaseLeaderElection))...)
+	for _, name := range r.leaderElectionGroupNames() {
+		statuses = append(statuses, r.leaderElectionGroup(name).statuses(string(StartPhaseLeaderElection)+"/"+name)...)
+	}
+	return statuses
+}
+
+// status returns the status of the manager.
+func (cm *controllerManager) status() Status {
+	status := Status{
+		LeaderElection: LeaderElectionStatus{
+			Enabled: cm.resourceLock != nil,
+			Elected: cm.isElected(),
+		},
+		Runnables: cm.runnables.statuses(),
+	}
+	if cm.resourceLock != nil {
+		status.LeaderElection.Identity = cm.resourceLock.Identity()
+	}
+	if syncStatus, ok := cm.GetCache().(cache.InformerSyncStatus); ok {
+		started, unsynced := syncStatus.UnsyncedInformers()
+		status.Cache.Started = started
+		for _, gvk := range unsynced {
+			status.Cache.Unsynced = append(status.Cache.Unsynced, gvk.String())
+		}
+	}
+	return status
+}
+
+// statusHandler serves the status of the manager as JSON.
+func (cm *controllerManager) statusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(cm.status()); err != nil {
+			cm.logger.Error(err, "unable to write the status of the manager")
+		}
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	defaultRestartInitialBackoff = time.Second
+	defaultRestartMaxBackoff     = 5 * time.Minute
+)
+
+// RestartPolicy is the policy with which a Runnable that returns an error is restarted,
+// instead of stopping the manager. The policy of the manager only applies to the Runnables
+// added with the Restartable option, as many Runnables can't be started twice.
+//
+// The Runnable is restarted with an exponential backoff, which starts at InitialBackoff and
+// doubles with every restart up to MaxBackoff. The backoff and the count of restarts are
+// reset once the Runnable ran for longer than MaxBackoff before it failed.
+type RestartPolicy struct {
+	// InitialBackoff is the delay before the first restart. Defaults to 1 second.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between restarts. Defaults to 5 minutes.
+	MaxBackoff time.Duration
+
+	// MaxRestarts is the number of consecutive restarts after which the error of the
+	// Runnable stops the manager. Zero means that the Runnable is always restarted.
+	MaxRestarts int
+}
+
+// SupervisedRunnable is a Runnable that is restarted with its own RestartPolicy when it
+// returns an error, whether or not the manager has a RestartPolicy.
+type SupervisedRunnable interface {
+	Runnable
+
+	// RestartPolicy returns the policy with which the Runnable is restarted.
+	RestartPolicy() RestartPolicy
+}
+
+// supervise makes the groups restart the restartable runnables that return an error with
+// the given policy, unless they have their own. A nil policy restarts only the
+// SupervisedRunnables.
+func (r *runnables) supervise(policy *RestartPolicy, log logr.Logger) {
+	r.restartPolicy, r.logger = policy, log
+	for _, group := range []*runnableGroup{r.Webhooks, r.Caches, r.LeaderElection, r.Warmup, r.Others} {
+		group.restartPolicy = policy
+		group.logger = log
+	}
+}
+
+// run starts a runnable of the group, and restarts it according to its restart policy
+// until it returns without an error, its context is done, or it ran out of restarts.
+func (r *runnableGroup) run(rn *readyRunnable) error {
+	var policy *RestartPolicy
+	if rn.restartable {
+		policy = r.restartPolicy
+	}
+	if supervised, ok := rn.Runnable.(SupervisedRunnable); ok {
+		p := supervised.RestartPolicy()
+		policy = &p
+	}
+	if policy == nil {
+		return rn.Start(rn.ctx)
+	}
+
+	initialBackoff, maxBackoff := policy.InitialBackoff, policy.MaxBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = defaultRestartInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRestartMaxBackoff
+	}
+	if maxBackoff < initialBackoff {
+		maxBackoff = initialBackoff
+	}
+
+	backoff, restarts := initialBackoff, 0
+	for {
+		startedAt := time.Now()
+		err := rn.Start(rn.ctx)
+		if err == nil || rn.ctx.Err() != nil {
+			return err
+		}
+		if time.Since(startedAt) > maxBackoff {
+			backoff, restarts = initialBackoff, 0
+		}
+		if policy.MaxRestarts > 0 && restarts >= policy.MaxRestarts {
+			return fmt.Errorf("runnable %T failed after %d restarts: %w", rn.Runnable, restarts, err)
+		}
+		restarts++
+		r.setState(rn, RunnableStateErrored, err)
+		r.logger.Error(err, "Runnable failed, restarting it", "runnable", fmt.Sprintf("%T", rn.Runnable), "backoff", backoff, "restarts", restarts)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-rn.ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		r.setState(rn, RunnableStateRunning, nil)
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
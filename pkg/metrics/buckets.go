@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	internalmetrics "sigs.k8s.io/controller-runtime/pkg/internal/metrics"
+)
+
+// SetReconcileTimeBuckets sets the buckets of the controller_runtime_reconcile_time_seconds
+// histogram. See SetHistogramBuckets for when it can be called.
+func SetReconcileTimeBuckets(buckets []float64) error {
+	return SetHistogramBuckets("controller_runtime_reconcile_time_seconds", buckets)
+}
+
+// SetWebhookLatencyBuckets sets the buckets of the controller_runtime_webhook_latency_seconds
+// histogram. See SetHistogramBuckets for when it can be called.
+func SetWebhookLatencyBuckets(buckets []float64) error {
+	return SetHistogramBuckets("controller_runtime_webhook_latency_seconds", buckets)
+}
+
+// SetHistogramBuckets sets the buckets of the histogram with the given name that is
+// registered by controller-runtime, e.g. workqueue_queue_duration_seconds. It returns an
+// error for the names of other metrics, e.g. if the name is misspelled.
+//
+// It must be called before the histogram is first used, i.e. before the manager or any
+// controller or webhook is started, and returns an error otherwise. The histogram stays
+// registered under the same name, so changing its buckets never creates a second metric family.
+func SetHistogramBuckets(name string, buckets []float64) error {
+	return internalmetrics.SetBuckets(name, buckets)
+}
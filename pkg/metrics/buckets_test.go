@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/client-go/util/workqueue"
+
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/internal/controller/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("histogram buckets", func() {
+	gatherFamilies := func(name string) []*dto.MetricFamily {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		var matching []*dto.MetricFamily
+		for _, family := range families {
+			if family.GetName() == name {
+				matching = append(matching, family)
+			}
+		}
+		return matching
+	}
+
+	upperBounds := func(family *dto.MetricFamily) []float64 {
+		var bounds []float64
+		for _, bucket := range family.GetMetric()[0].GetHistogram().GetBucket() {
+			bounds = append(bounds, bucket.GetUpperBound())
+		}
+		return bounds
+	}
+
+	It("should use the configured reconcile time buckets until the histogram is used", func() {
+		Expect(metrics.SetReconcileTimeBuckets([]float64{10, 60, 120})).To(Succeed())
+		ctrlmetrics.ReconcileTime.WithLabelValues("buckets-test").Observe(90)
+
+		families := gatherFamilies("controller_runtime_reconcile_time_seconds")
+		Expect(families).To(HaveLen(1))
+		Expect(upperBounds(families[0])).To(Equal([]float64{10, 60, 120}))
+
+		By("refusing to change the buckets after the histogram has been used")
+		Expect(metrics.SetReconcileTimeBuckets([]float64{1, 2})).NotTo(Succeed())
+		families = gatherFamilies("controller_runtime_reconcile_time_seconds")
+		Expect(families).To(HaveLen(1))
+		Expect(upperBounds(families[0])).To(Equal([]float64{10, 60, 120}))
+	})
+
+	It("should use the configured workqueue buckets", func() {
+		metrics.RegisterClientGoMetrics()
+		Expect(metrics.SetHistogramBuckets("workqueue_queue_duration_seconds", []float64{0.5, 1})).To(Succeed())
+		queue := workqueue.NewNamed("buckets-test")
+		defer queue.ShutDown()
+		queue.Add("item")
+		item, _ := queue.Get()
+		queue.Done(item)
+
+		families := gatherFamilies("workqueue_queue_duration_seconds")
+		Expect(families).To(HaveLen(1))
+		Expect(upperBounds(families[0])).To(Equal([]float64{0.5, 1}))
+	})
+
+	It("should reject the names of metrics that aren't registered histograms", func() {
+		Expect(metrics.SetHistogramBuckets("workqueue_queue_duration_second", []float64{0.5, 1})).
+			To(MatchError(ContainSubstring("is not a histogram registered by controller-runtime")))
+		Expect(metrics.SetHistogramBuckets("workqueue_depth", []float64{0.5, 1})).NotTo(Succeed())
+	})
+
+	It("should reject buckets that are empty or not strictly increasing", func() {
+		Expect(metrics.SetWebhookLatencyBuckets(nil)).NotTo(Succeed())
+		Expect(metrics.SetWebhookLatencyBuckets([]float64{1, 1})).NotTo(Succeed())
+		Expect(metrics.SetWebhookLatencyBuckets([]float64{2, 1})).NotTo(Succeed())
+	})
+})
@@ -18,11 +18,15 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	clientmetrics "k8s.io/client-go/tools/metrics"
+	"k8s.io/client-go/util/workqueue"
 )
 
 // this file contains setup logic to initialize the myriad of places
@@ -69,19 +73,75 @@ var (
 	}, []string{"code", "method", "host"})
 )
 
-func init() {
-	registerClientMetrics()
+var registerClientGoAdaptersOnce sync.Once
+
+// RegisterClientGoMetrics registers the metrics of client-go's REST clients and
+// workqueues with Registry, and sets up client-go to report into them. It is
+// called by manager.New unless its DisableClientGoMetrics metrics option is set,
+// and can safely be called again, e.g. after UnregisterClientGoMetrics. Programs
+// that don't use a manager call it themselves.
+//
+// client-go only accepts the first set of adapters registered with it, so the
+// metrics stay empty if another package registered its adapters first, and
+// programs that register their own adapters must not call it.
+func RegisterClientGoMetrics() {
+	registerClientGoAdaptersOnce.Do(func() {
+		clientmetrics.Register(clientmetrics.RegisterOpts{
+			RequestResult: &resultAdapter{metric: requestResult},
+		})
+		workqueue.SetProvider(WorkqueueMetricsProvider{})
+		Registry.MustRegister(labelledWorkqueueCollector{})
+	})
+
+	for _, c := range clientGoCollectors() {
+		if err := Registry.Register(c); err != nil && !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+			panic(err)
+		}
+	}
+	labelledWorkqueueMetricsEnabled.Store(true)
 }
 
-// registerClientMetrics sets up the client latency metrics from client-go.
-func registerClientMetrics() {
-	// register the metrics with our registry
-	Registry.MustRegister(requestResult)
+// UnregisterClientGoMetrics removes the metrics of client-go's REST clients and
+// workqueues from Registry, for users that expose them through other means.
+func UnregisterClientGoMetrics() {
+	for _, c := range clientGoCollectors() {
+		Registry.Unregister(c)
+	}
+	labelledWorkqueueMetricsEnabled.Store(false)
+}
 
-	// register the metrics with client-go
-	clientmetrics.Register(clientmetrics.RegisterOpts{
-		RequestResult: &resultAdapter{metric: requestResult},
-	})
+// labelledWorkqueueMetricsEnabled is whether labelledWorkqueueCollector collects metrics.
+var labelledWorkqueueMetricsEnabled atomic.Bool
+
+// labelledWorkqueueCollector collects the metrics of workqueues with extra labels while
+// the client-go metrics are registered. Being an unchecked collector, it can't be
+// unregistered, so it is registered once and disabled instead.
+type labelledWorkqueueCollector struct{}
+
+// Describe implements prometheus.Collector.
+func (labelledWorkqueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	labelledWorkqueueVecs.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (labelledWorkqueueCollector) Collect(ch chan<- prometheus.Metric) {
+	if labelledWorkqueueMetricsEnabled.Load() {
+		labelledWorkqueueVecs.Collect(ch)
+	}
+}
+
+// clientGoCollectors returns the collectors registered by RegisterClientGoMetrics.
+func clientGoCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		requestResult,
+		depth,
+		adds,
+		latency,
+		workDuration,
+		unfinished,
+		longestRunningProcessor,
+		retries,
+	}
 }
 
 // this section contains adapters, implementations, and other sundry organic, artisanally
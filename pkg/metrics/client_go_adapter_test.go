@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var _ = Describe("client-go metrics", func() {
+	BeforeEach(func() {
+		metrics.RegisterClientGoMetrics()
+	})
+
+	gatheredNames := func() []string {
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+
+		var names []string
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+		return names
+	}
+
+	It("should report workqueue metrics into the Registry with the queue name as label", func() {
+		queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "client-go-test")
+		defer queue.ShutDown()
+		queue.Add("item")
+
+		families, err := metrics.Registry.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		var found bool
+		for _, family := range families {
+			if family.GetName() != "workqueue_depth" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				if metric.GetLabel()[0].GetValue() == "client-go-test" {
+					found = true
+					Expect(metric.GetGauge().GetValue()).To(Equal(1.0))
+				}
+			}
+		}
+		Expect(found).To(BeTrue())
+	})
+
+	It("should not panic when registering the metrics more than once", func() {
+		Expect(metrics.RegisterClientGoMetrics).NotTo(Panic())
+		Expect(metrics.RegisterClientGoMetrics).NotTo(Panic())
+	})
+
+	It("should allow unregistering and registering the metrics again", func() {
+		queue := workqueue.NewNamed("client-go-reregister-test")
+		defer queue.ShutDown()
+		queue.Add("item")
+		Expect(gatheredNames()).To(ContainElement("workqueue_adds_total"))
+
+		metrics.UnregisterClientGoMetrics()
+		Expect(gatheredNames()).NotTo(ContainElement("workqueue_adds_total"))
+
+		metrics.RegisterClientGoMetrics()
+		Expect(gatheredNames()).To(ContainElement("workqueue_adds_total"))
+	})
+})
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package filters contains Filters of the metrics endpoint.
+package filters
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// WithAuthenticationAndAuthorization returns a Filter that authenticates the requests to the
+// metrics endpoint with a TokenReview of their bearer token, and authorizes them with a
+// SubjectAccessReview of the non-resource URL they request, so that the endpoint can be
+// exposed without a proxy such as kube-rbac-proxy. Scraping the metrics then requires e.g.
+// a ClusterRole with the following rules:
+//
+//	rules:
+//	- nonResourceURLs: ["/metrics"]
+//	  verbs: ["get"]
+//
+// The identity of the manager must be allowed to create TokenReviews and SubjectAccessReviews.
+func WithAuthenticationAndAuthorization(config *rest.Config) (metrics.Filter, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the clientset of the metrics filter: %w", err)
+	}
+	return newAuthFilter(clientset.AuthenticationV1().TokenReviews(), clientset.AuthorizationV1().SubjectAccessReviews()), nil
+}
+
+func newAuthFilter(tokenReviews authenticationv1client.TokenReviewInterface, accessReviews authorizationv1client.SubjectAccessReviewInterface) metrics.Filter {
+	return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+
+			token, ok := bearerToken(req)
+			if !ok {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			review, err := tokenReviews.Create(ctx, &authenticationv1.TokenReview{
+				Spec: authenticationv1.TokenReviewSpec{Token: token},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				log.Error(err, "Failed to authenticate a request to the metrics endpoint")
+				http.Error(w, "Authentication failed", http.StatusInternalServerError)
+				return
+			}
+			if !review.Status.Authenticated {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			user := review.Status.User
+			extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+			for k, v := range user.Extra {
+				extra[k] = authorizationv1.ExtraValue(v)
+			}
+			access, err := accessReviews.Create(ctx, &authorizationv1.SubjectAccessReview{
+				Spec: authorizationv1.SubjectAccessReviewSpec{
+					User:   user.Username,
+					UID:    user.UID,
+					Groups: user.Groups,
+					Extra:  extra,
+					NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+						Path: req.URL.Path,
+						Verb: strings.ToLower(req.Method),
+					},
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				log.Error(err, "Failed to authorize a request to the metrics endpoint", "user", user.Username)
+				http.Error(w, "Authorization failed", http.StatusInternalServerError)
+				return
+			}
+			if !access.Status.Allowed {
+				log.V(4).Info("Forbidden request to the metrics endpoint", "user", user.Username, "reason", access.Status.Reason)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			handler.ServeHTTP(w, req)
+		}), nil
+	}
+}
+
+// bearerToken returns the bearer token of the Authorization header of the request.
+func bearerToken(req *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(req.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
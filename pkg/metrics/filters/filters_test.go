@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filters
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("WithAuthenticationAndAuthorization", func() {
+	var (
+		clientset *fake.Clientset
+		handler   http.Handler
+		access    *authorizationv1.SubjectAccessReview
+	)
+
+	BeforeEach(func() {
+		access = nil
+		clientset = fake.NewSimpleClientset()
+		clientset.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			review := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+			if review.Spec.Token == "valid" {
+				review.Status.Authenticated = true
+				review.Status.User = authenticationv1.UserInfo{Username: "prometheus", Groups: []string{"monitoring"}}
+			}
+			return true, review, nil
+		})
+		clientset.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			access = action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+			access.Status.Allowed = access.Spec.User == "prometheus"
+			return true, access, nil
+		})
+
+		filter := newAuthFilter(clientset.AuthenticationV1().TokenReviews(), clientset.AuthorizationV1().SubjectAccessReviews())
+		var err error
+		handler, err = filter(logr.Discard(), http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("metrics"))
+		}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	serve := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	It("should reject requests without a bearer token", func() {
+		Expect(serve("").Code).To(Equal(http.StatusUnauthorized))
+		Expect(serve("Basic dXNlcjpwYXNz").Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should reject requests whose token is not authenticated", func() {
+		Expect(serve("Bearer invalid").Code).To(Equal(http.StatusUnauthorized))
+		Expect(access).To(BeNil())
+	})
+
+	It("should serve the requests of authorized users", func() {
+		w := serve("Bearer valid")
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(Equal("metrics"))
+		Expect(access.Spec.Groups).To(Equal([]string{"monitoring"}))
+		Expect(access.Spec.NonResourceAttributes).To(Equal(&authorizationv1.NonResourceAttributes{Path: "/metrics", Verb: "get"}))
+	})
+
+	It("should reject the requests of users that are not authorized", func() {
+		clientset.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, action.(clienttesting.CreateAction).GetObject(), nil
+		})
+		Expect(serve("Bearer valid").Code).To(Equal(http.StatusForbidden))
+	})
+})
@@ -19,6 +19,8 @@ package metrics
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/client-go/util/workqueue"
+
+	internalmetrics "sigs.k8s.io/controller-runtime/pkg/internal/metrics"
 )
 
 // This file is copied and adapted from k8s.io/kubernetes/pkg/util/workqueue/prometheus
@@ -38,93 +40,199 @@ const (
 )
 
 var (
-	depth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Subsystem: WorkQueueSubsystem,
-		Name:      DepthKey,
-		Help:      "Current depth of workqueue",
-	}, []string{"name"})
-
-	adds = prometheus.NewCounterVec(prometheus.CounterOpts{
-		Subsystem: WorkQueueSubsystem,
-		Name:      AddsKey,
-		Help:      "Total number of adds handled by workqueue",
-	}, []string{"name"})
-
-	latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
-		Subsystem: WorkQueueSubsystem,
-		Name:      QueueLatencyKey,
-		Help:      "How long in seconds an item stays in workqueue before being requested",
-		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 10),
-	}, []string{"name"})
-
-	workDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
-		Subsystem: WorkQueueSubsystem,
-		Name:      WorkDurationKey,
-		Help:      "How long in seconds processing an item from workqueue takes.",
-		Buckets:   prometheus.ExponentialBuckets(10e-9, 10, 10),
-	}, []string{"name"})
-
-	unfinished = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Subsystem: WorkQueueSubsystem,
-		Name:      UnfinishedWorkKey,
-		Help: "How many seconds of work has been done that " +
-			"is in progress and hasn't been observed by work_duration. Large " +
-			"values indicate stuck threads. One can deduce the number of stuck " +
-			"threads by observing the rate at which this increases.",
-	}, []string{"name"})
-
-	longestRunningProcessor = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Subsystem: WorkQueueSubsystem,
-		Name:      LongestRunningProcessorKey,
-		Help: "How many seconds has the longest running " +
-			"processor for workqueue been running.",
-	}, []string{"name"})
-
-	retries = prometheus.NewCounterVec(prometheus.CounterOpts{
-		Subsystem: WorkQueueSubsystem,
-		Name:      RetriesKey,
-		Help:      "Total number of retries handled by workqueue",
-	}, []string{"name"})
+	depth                   = defaultWorkqueueVecs.depth
+	adds                    = defaultWorkqueueVecs.adds
+	latency                 = defaultWorkqueueVecs.latency
+	workDuration            = defaultWorkqueueVecs.workDuration
+	unfinished              = defaultWorkqueueVecs.unfinished
+	longestRunningProcessor = defaultWorkqueueVecs.longestRunningProcessor
+	retries                 = defaultWorkqueueVecs.retries
 )
 
-func init() {
-	Registry.MustRegister(depth)
-	Registry.MustRegister(adds)
-	Registry.MustRegister(latency)
-	Registry.MustRegister(workDuration)
-	Registry.MustRegister(unfinished)
-	Registry.MustRegister(longestRunningProcessor)
-	Registry.MustRegister(retries)
+var (
+	// defaultWorkqueueVecs holds the metrics of workqueues without extra labels.
+	defaultWorkqueueVecs = newWorkqueueVecs(nil)
+
+	// labelledWorkqueueVecs holds the metrics of workqueues with extra labels, by their labels.
+	// Workqueues get extra labels from the MetricLabels of their controller.
+	labelledWorkqueueVecs = internalmetrics.NewLabelledCollectors(newWorkqueueVecs)
+
+	// workqueueVecsByRegistry holds the metrics of workqueues by the registry they report into.
+	// Workqueues report into the metrics registry of their controller.
+	workqueueVecsByRegistry = func() *internalmetrics.PerRegistry[*registryWorkqueueVecs] {
+		p := internalmetrics.NewPerRegistry(newRegistryWorkqueueVecs)
+		// The metrics of Registry are registered by RegisterClientGoMetrics.
+		p.Set(Registry, &registryWorkqueueVecs{defaults: defaultWorkqueueVecs, labelled: labelledWorkqueueVecs})
+		return p
+	}()
+)
+
+// registryWorkqueueVecs are the metric vectors of the workqueues that report into a registry.
+type registryWorkqueueVecs struct {
+	defaults *workqueueVecs
+	labelled *internalmetrics.LabelledCollectors[*workqueueVecs]
+}
+
+func newRegistryWorkqueueVecs(reg prometheus.Registerer) *registryWorkqueueVecs {
+	v := &registryWorkqueueVecs{
+		defaults: newWorkqueueVecs(nil),
+		labelled: internalmetrics.NewLabelledCollectors(newWorkqueueVecs),
+	}
+	reg.MustRegister(v.defaults, v.labelled)
+	return v
+}
+
+// workqueueVecs are the metric vectors of workqueues with the same extra labels,
+// which are set as constant labels.
+type workqueueVecs struct {
+	depth                   *prometheus.GaugeVec
+	adds                    *prometheus.CounterVec
+	latency                 *internalmetrics.HistogramVec
+	workDuration            *internalmetrics.HistogramVec
+	unfinished              *prometheus.GaugeVec
+	longestRunningProcessor *prometheus.GaugeVec
+	retries                 *prometheus.CounterVec
+}
+
+func newWorkqueueVecs(constLabels prometheus.Labels) *workqueueVecs {
+	return &workqueueVecs{
+		depth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem:   WorkQueueSubsystem,
+			Name:        DepthKey,
+			Help:        "Current depth of workqueue",
+			ConstLabels: constLabels,
+		}, []string{"name"}),
+
+		adds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem:   WorkQueueSubsystem,
+			Name:        AddsKey,
+			Help:        "Total number of adds handled by workqueue",
+			ConstLabels: constLabels,
+		}, []string{"name"}),
+
+		latency: internalmetrics.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem:   WorkQueueSubsystem,
+			Name:        QueueLatencyKey,
+			Help:        "How long in seconds an item stays in workqueue before being requested",
+			Buckets:     prometheus.ExponentialBuckets(10e-9, 10, 10),
+			ConstLabels: constLabels,
+		}, []string{"name"}),
+
+		workDuration: internalmetrics.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem:   WorkQueueSubsystem,
+			Name:        WorkDurationKey,
+			Help:        "How long in seconds processing an item from workqueue takes.",
+			Buckets:     prometheus.ExponentialBuckets(10e-9, 10, 10),
+			ConstLabels: constLabels,
+		}, []string{"name"}),
+
+		unfinished: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: WorkQueueSubsystem,
+			Name:      UnfinishedWorkKey,
+			Help: "How many seconds of work has been done that " +
+				"is in progress and hasn't been observed by work_duration. Large " +
+				"values indicate stuck threads. One can deduce the number of stuck " +
+				"threads by observing the rate at which this increases.",
+			ConstLabels: constLabels,
+		}, []string{"name"}),
+
+		longestRunningProcessor: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: WorkQueueSubsystem,
+			Name:      LongestRunningProcessorKey,
+			Help: "How many seconds has the longest running " +
+				"processor for workqueue been running.",
+			ConstLabels: constLabels,
+		}, []string{"name"}),
+
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem:   WorkQueueSubsystem,
+			Name:        RetriesKey,
+			Help:        "Total number of retries handled by workqueue",
+			ConstLabels: constLabels,
+		}, []string{"name"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (v *workqueueVecs) Describe(ch chan<- *prometheus.Desc) {
+	v.depth.Describe(ch)
+	v.adds.Describe(ch)
+	v.latency.Describe(ch)
+	v.workDuration.Describe(ch)
+	v.unfinished.Describe(ch)
+	v.longestRunningProcessor.Describe(ch)
+	v.retries.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (v *workqueueVecs) Collect(ch chan<- prometheus.Metric) {
+	v.depth.Collect(ch)
+	v.adds.Collect(ch)
+	v.latency.Collect(ch)
+	v.workDuration.Collect(ch)
+	v.unfinished.Collect(ch)
+	v.longestRunningProcessor.Collect(ch)
+	v.retries.Collect(ch)
+}
 
-	workqueue.SetProvider(workqueueMetricsProvider{})
+// workqueueVecsFor returns the metric vectors of the workqueue with the given name.
+func workqueueVecsFor(name string) *workqueueVecs {
+	opts, ok := internalmetrics.WorkqueueOptionsFor(name)
+	if !ok {
+		return defaultWorkqueueVecs
+	}
+	if opts.Registry == nil {
+		opts.Registry = Registry
+	}
+	vecs := workqueueVecsByRegistry.For(opts.Registry)
+	if len(opts.ExtraLabels) > 0 {
+		return vecs.labelled.For(opts.ExtraLabels)
+	}
+	return vecs.defaults
 }
 
-type workqueueMetricsProvider struct{}
+// The metrics in this file are registered by RegisterClientGoMetrics.
+
+// WorkqueueMetricsProvider is the workqueue.MetricsProvider of the workqueues of
+// controllers, which report into the workqueue metrics of controller-runtime.
+// It allows workqueues implemented outside of client-go to report the same metrics.
+type WorkqueueMetricsProvider struct{}
+
+func (WorkqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return workqueueVecsFor(name).depth.WithLabelValues(name)
+}
+
+func (WorkqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return workqueueVecsFor(name).adds.WithLabelValues(name)
+}
 
-func (workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
-	return depth.WithLabelValues(name)
+func (WorkqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return histogramMetric{vec: workqueueVecsFor(name).latency.MustCurryWith(prometheus.Labels{"name": name})}
 }
 
-func (workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
-	return adds.WithLabelValues(name)
+func (WorkqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return histogramMetric{vec: workqueueVecsFor(name).workDuration.MustCurryWith(prometheus.Labels{"name": name})}
 }
 
-func (workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
-	return latency.WithLabelValues(name)
+func (WorkqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueVecsFor(name).unfinished.WithLabelValues(name)
 }
 
-func (workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
-	return workDuration.WithLabelValues(name)
+func (WorkqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return workqueueVecsFor(name).longestRunningProcessor.WithLabelValues(name)
 }
 
-func (workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
-	return unfinished.WithLabelValues(name)
+func (WorkqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return workqueueVecsFor(name).retries.WithLabelValues(name)
 }
 
-func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
-	return longestRunningProcessor.WithLabelValues(name)
+// histogramMetric observes into a histogram curried with all its labels. It looks the
+// histogram up on every observation, so that creating a workqueue doesn't prevent
+// its buckets from being configured until it is used.
+type histogramMetric struct {
+	vec prometheus.ObserverVec
 }
 
-func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
-	return retries.WithLabelValues(name)
+func (m histogramMetric) Observe(v float64) {
+	m.vec.WithLabelValues().Observe(v)
 }
@@ -18,5 +18,12 @@ limitations under the License.
 Package ratelimiter defines rate limiters used by Controllers to limit how frequently requests may be queued.
 
 Typical rate limiters that can be used are implemented in client-go's workqueue package.
+This package provides helpers to compose them, e.g. an exponential per-item delay capped
+at a maximum combined with an overall budget:
+
+	ratelimiter.MaxOf(
+		ratelimiter.NewExponential(5*time.Millisecond, 5*time.Minute),
+		ratelimiter.NewBudget(10, 100),
+	)
 */
 package ratelimiter
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// NewExponential returns a rate limiter that delays each item by base*2^failures,
+// capped at max. The failures of an item are reset by Forget.
+func NewExponential(base, max time.Duration) RateLimiter {
+	return workqueue.NewItemExponentialFailureRateLimiter(base, max)
+}
+
+// NewBudget returns a rate limiter that delays the items to stay within an overall
+// budget of qps, allowing bursts of burst items. It doesn't track individual items,
+// so NumRequeues always returns 0 and Forget does nothing.
+func NewBudget(qps float64, burst int) RateLimiter {
+	return &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)}
+}
+
+// MaxOf returns a rate limiter that delays each item by the longest delay of the given
+// rate limiters, e.g. an exponential per-item delay combined with an overall budget.
+// NumRequeues returns the highest number of requeues, and Forget forgets the item in
+// all the rate limiters.
+func MaxOf(limiters ...RateLimiter) RateLimiter {
+	wqLimiters := make([]workqueue.RateLimiter, 0, len(limiters))
+	for _, limiter := range limiters {
+		wqLimiters = append(wqLimiters, limiter)
+	}
+	return workqueue.NewMaxOfRateLimiter(wqLimiters...)
+}
+
+// PerErrorClass returns a rate limiter that delays each item with the rate limiter of
+// its class, e.g. to back off from errors that are unlikely to resolve quickly more
+// slowly than from transient ones. classify typically looks up the last error of the
+// item, as recorded by the reconciler. Items whose class has no rate limiter use the
+// one of the empty class, and aren't delayed if there is none.
+//
+// NumRequeues returns the number of requeues of the item summed over the rate limiters,
+// as an item may change classes between failures, and Forget forgets the item in all
+// the rate limiters.
+func PerErrorClass(classify func(item interface{}) string, limiters map[string]RateLimiter) RateLimiter {
+	r := &perErrorClass{classify: classify, limiters: limiters}
+	seen := map[RateLimiter]bool{}
+	for _, limiter := range limiters {
+		if !seen[limiter] {
+			seen[limiter] = true
+			r.distinct = append(r.distinct, limiter)
+		}
+	}
+	return r
+}
+
+type perErrorClass struct {
+	classify func(item interface{}) string
+	limiters map[string]RateLimiter
+	// distinct are the rate limiters without duplicates, as several classes may share
+	// a rate limiter.
+	distinct []RateLimiter
+}
+
+func (r *perErrorClass) When(item interface{}) time.Duration {
+	limiter, ok := r.limiters[r.classify(item)]
+	if !ok {
+		limiter, ok = r.limiters[""]
+	}
+	if !ok {
+		return 0
+	}
+	return limiter.When(item)
+}
+
+func (r *perErrorClass) Forget(item interface{}) {
+	for _, limiter := range r.distinct {
+		limiter.Forget(item)
+	}
+}
+
+func (r *perErrorClass) NumRequeues(item interface{}) int {
+	requeues := 0
+	for _, limiter := range r.distinct {
+		requeues += limiter.NumRequeues(item)
+	}
+	return requeues
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimiter_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+)
+
+var _ = Describe("ratelimiter", func() {
+	Describe("NewExponential", func() {
+		It("should double the delay of an item up to the cap", func() {
+			r := ratelimiter.NewExponential(time.Millisecond, 5*time.Millisecond)
+			Expect(r.When("a")).To(Equal(time.Millisecond))
+			Expect(r.When("a")).To(Equal(2 * time.Millisecond))
+			Expect(r.When("a")).To(Equal(4 * time.Millisecond))
+			Expect(r.When("a")).To(Equal(5 * time.Millisecond))
+			Expect(r.When("a")).To(Equal(5 * time.Millisecond))
+			Expect(r.NumRequeues("a")).To(Equal(5))
+
+			By("tracking items independently")
+			Expect(r.When("b")).To(Equal(time.Millisecond))
+			Expect(r.NumRequeues("b")).To(Equal(1))
+		})
+
+		It("should start over after Forget", func() {
+			r := ratelimiter.NewExponential(time.Millisecond, time.Second)
+			r.When("a")
+			r.When("a")
+			r.Forget("a")
+			Expect(r.NumRequeues("a")).To(Equal(0))
+			Expect(r.When("a")).To(Equal(time.Millisecond))
+		})
+	})
+
+	Describe("NewBudget", func() {
+		It("should not delay the items of a burst and delay the items exceeding it", func() {
+			r := ratelimiter.NewBudget(1, 2)
+			Expect(r.When("a")).To(Equal(time.Duration(0)))
+			Expect(r.When("b")).To(Equal(time.Duration(0)))
+			Expect(r.When("c")).To(BeNumerically(">", 500*time.Millisecond))
+		})
+
+		It("should not track items", func() {
+			r := ratelimiter.NewBudget(1, 1)
+			r.When("a")
+			Expect(r.NumRequeues("a")).To(Equal(0))
+			r.Forget("a")
+			Expect(r.When("a")).To(BeNumerically(">", 0))
+		})
+	})
+
+	Describe("MaxOf", func() {
+		It("should delay items by the longest delay of the rate limiters", func() {
+			r := ratelimiter.MaxOf(
+				ratelimiter.NewExponential(time.Millisecond, time.Second),
+				ratelimiter.NewExponential(10*time.Millisecond, 20*time.Millisecond),
+			)
+			Expect(r.When("a")).To(Equal(10 * time.Millisecond))
+			Expect(r.When("a")).To(Equal(20 * time.Millisecond))
+			Expect(r.When("a")).To(Equal(20 * time.Millisecond))
+			Expect(r.When("a")).To(Equal(20 * time.Millisecond))
+			Expect(r.When("a")).To(Equal(20 * time.Millisecond))
+			Expect(r.When("a")).To(Equal(32 * time.Millisecond))
+			Expect(r.NumRequeues("a")).To(Equal(6))
+		})
+
+		It("should forget items in all the rate limiters", func() {
+			r := ratelimiter.MaxOf(
+				ratelimiter.NewExponential(time.Millisecond, time.Second),
+				ratelimiter.NewExponential(2*time.Millisecond, time.Second),
+			)
+			r.When("a")
+			r.When("a")
+			r.Forget("a")
+			Expect(r.NumRequeues("a")).To(Equal(0))
+			Expect(r.When("a")).To(Equal(2 * time.Millisecond))
+		})
+
+		It("should be usable as a workqueue rate limiter", func() {
+			var r workqueue.RateLimiter = ratelimiter.MaxOf(workqueue.DefaultControllerRateLimiter())
+			Expect(r.When("a")).To(Equal(5 * time.Millisecond))
+		})
+	})
+
+	Describe("PerErrorClass", func() {
+		var (
+			classes   map[string]string
+			transient ratelimiter.RateLimiter
+			terminal  ratelimiter.RateLimiter
+			r         ratelimiter.RateLimiter
+		)
+
+		BeforeEach(func() {
+			classes = map[string]string{}
+			transient = ratelimiter.NewExponential(time.Millisecond, time.Second)
+			terminal = ratelimiter.NewExponential(time.Minute, time.Hour)
+			r = ratelimiter.PerErrorClass(func(item interface{}) string {
+				return classes[item.(string)]
+			}, map[string]ratelimiter.RateLimiter{"": transient, "terminal": terminal})
+		})
+
+		It("should delay items with the rate limiter of their class", func() {
+			classes["b"] = "terminal"
+			Expect(r.When("a")).To(Equal(time.Millisecond))
+			Expect(r.When("a")).To(Equal(2 * time.Millisecond))
+			Expect(r.When("b")).To(Equal(time.Minute))
+			Expect(r.When("b")).To(Equal(2 * time.Minute))
+			Expect(r.NumRequeues("a")).To(Equal(2))
+			Expect(r.NumRequeues("b")).To(Equal(2))
+		})
+
+		It("should use the rate limiter of the empty class for unknown classes", func() {
+			classes["a"] = "unknown"
+			Expect(r.When("a")).To(Equal(time.Millisecond))
+		})
+
+		It("should not delay items without a rate limiter", func() {
+			r := ratelimiter.PerErrorClass(func(interface{}) string { return "unknown" },
+				map[string]ratelimiter.RateLimiter{"terminal": terminal})
+			Expect(r.When("a")).To(Equal(time.Duration(0)))
+			Expect(r.NumRequeues("a")).To(Equal(0))
+		})
+
+		It("should count the requeues of items that changed classes", func() {
+			Expect(r.When("a")).To(Equal(time.Millisecond))
+			classes["a"] = "terminal"
+			Expect(r.When("a")).To(Equal(time.Minute))
+			Expect(r.NumRequeues("a")).To(Equal(2))
+		})
+
+		It("should forget items in all the rate limiters after a success", func() {
+			r.When("a")
+			classes["a"] = "terminal"
+			r.When("a")
+			r.Forget("a")
+			Expect(r.NumRequeues("a")).To(Equal(0))
+			Expect(transient.NumRequeues("a")).To(Equal(0))
+			Expect(terminal.NumRequeues("a")).To(Equal(0))
+			Expect(r.When("a")).To(Equal(time.Minute))
+		})
+
+		It("should not count the requeues of rate limiters shared by classes twice", func() {
+			r := ratelimiter.PerErrorClass(func(item interface{}) string { return item.(string) },
+				map[string]ratelimiter.RateLimiter{"a": transient, "b": transient})
+			r.When("a")
+			Expect(r.NumRequeues("a")).To(Equal(1))
+		})
+	})
+})
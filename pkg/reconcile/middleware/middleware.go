@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package middleware contains common middlewares that can be wrapped around a
+// reconcile.Reconciler with reconcile.Chain.
+//
+// All middlewares return the Result and error of the wrapped Reconciler as is,
+// unless altering them is their job, as documented on each of them.
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// WithTimeout cancels the context passed to the wrapped Reconciler after the given duration.
+func WithTimeout(timeout time.Duration) func(reconcile.Reconciler) reconcile.Reconciler {
+	return func(next reconcile.Reconciler) reconcile.Reconciler {
+		return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next.Reconcile(ctx, req)
+		})
+	}
+}
+
+// WithRecover recovers panics of the wrapped Reconciler and returns them as an error instead.
+// The registered utilruntime.PanicHandlers are called with the recovered value.
+func WithRecover() func(reconcile.Reconciler) reconcile.Reconciler {
+	return func(next reconcile.Reconciler) reconcile.Reconciler {
+		return reconcile.Func(func(ctx context.Context, req reconcile.Request) (_ reconcile.Result, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					for _, fn := range utilruntime.PanicHandlers {
+						fn(r)
+					}
+					err = fmt.Errorf("panic: %v [recovered]", r)
+				}
+			}()
+			return next.Reconcile(ctx, req)
+		})
+	}
+}
+
+// WithLogging adds the given key/value pairs to the logger in the context passed to the
+// wrapped Reconciler, and logs the duration and outcome of each reconcile at V(1).
+func WithLogging(keysAndValues ...interface{}) func(reconcile.Reconciler) reconcile.Reconciler {
+	return func(next reconcile.Reconciler) reconcile.Reconciler {
+		return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			log := logf.FromContext(ctx).WithValues(keysAndValues...)
+			ctx = logf.IntoContext(ctx, log)
+
+			start := time.Now()
+			res, err := next.Reconcile(ctx, req)
+			log.V(1).Info("Reconcile finished", "duration", time.Since(start),
+				"requeue", res.Requeue, "requeueAfter", res.RequeueAfter, "failed", err != nil)
+			return res, err
+		})
+	}
+}
+
+// WithConcurrencyLimit limits the number of concurrent reconciles to the capacity of sem.
+// The same sem can be shared by the reconcilers of multiple controllers to limit their
+// total concurrency. If the context is cancelled while waiting for sem, the context's
+// error is returned without calling the wrapped Reconciler.
+func WithConcurrencyLimit(sem chan struct{}) func(reconcile.Reconciler) reconcile.Reconciler {
+	return func(next reconcile.Reconciler) reconcile.Reconciler {
+		return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return reconcile.Result{}, ctx.Err()
+			}
+			defer func() { <-sem }()
+			return next.Reconcile(ctx, req)
+		})
+	}
+}
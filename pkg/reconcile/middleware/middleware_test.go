@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile/middleware"
+)
+
+var _ = Describe("middleware", func() {
+	var expectedErr = fmt.Errorf("expected error")
+	var expectedResult = reconcile.Result{RequeueAfter: time.Minute}
+
+	Describe("WithTimeout", func() {
+		It("should pass a context with a deadline and preserve the result", func() {
+			r := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				deadline, ok := ctx.Deadline()
+				Expect(ok).To(BeTrue())
+				Expect(time.Until(deadline)).To(BeNumerically("<=", time.Hour))
+				return expectedResult, expectedErr
+			})
+
+			res, err := middleware.WithTimeout(time.Hour)(r).Reconcile(context.Background(), reconcile.Request{})
+			Expect(err).To(BeIdenticalTo(expectedErr))
+			Expect(res).To(Equal(expectedResult))
+		})
+
+		It("should cancel the context after the timeout", func() {
+			r := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				<-ctx.Done()
+				return reconcile.Result{}, ctx.Err()
+			})
+
+			_, err := middleware.WithTimeout(10*time.Millisecond)(r).Reconcile(context.Background(), reconcile.Request{})
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+	})
+
+	Describe("WithRecover", func() {
+		It("should return a panic as an error", func() {
+			r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				panic("boom")
+			})
+
+			_, err := middleware.WithRecover()(r).Reconcile(context.Background(), reconcile.Request{})
+			Expect(err).To(MatchError("panic: boom [recovered]"))
+		})
+
+		It("should preserve the result if there is no panic", func() {
+			r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				return expectedResult, expectedErr
+			})
+
+			res, err := middleware.WithRecover()(r).Reconcile(context.Background(), reconcile.Request{})
+			Expect(err).To(BeIdenticalTo(expectedErr))
+			Expect(res).To(Equal(expectedResult))
+		})
+	})
+
+	Describe("WithLogging", func() {
+		It("should add the fields to the logger in the context and log the outcome", func() {
+			var lines []string
+			log := funcr.New(func(prefix, args string) {
+				lines = append(lines, args)
+			}, funcr.Options{Verbosity: 1})
+
+			r := reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				logf.FromContext(ctx).Info("reconciling")
+				return expectedResult, expectedErr
+			})
+
+			ctx := logf.IntoContext(context.Background(), log)
+			res, err := middleware.WithLogging("team", "storage")(r).Reconcile(ctx, reconcile.Request{})
+			Expect(err).To(BeIdenticalTo(expectedErr))
+			Expect(res).To(Equal(expectedResult))
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[0]).To(ContainSubstring(`"msg"="reconciling" "team"="storage"`))
+			Expect(lines[1]).To(ContainSubstring(`"msg"="Reconcile finished" "team"="storage"`))
+			Expect(lines[1]).To(ContainSubstring(`"failed"=true`))
+		})
+	})
+
+	Describe("WithConcurrencyLimit", func() {
+		It("should never exceed the capacity of the semaphore", func() {
+			sem := make(chan struct{}, 2)
+			var inFlight, maxInFlight int32
+			r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				current := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					prev := atomic.LoadInt32(&maxInFlight)
+					if current <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, current) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				return expectedResult, nil
+			})
+
+			// Two reconcilers share the semaphore, like two controllers would.
+			first := middleware.WithConcurrencyLimit(sem)(r)
+			second := middleware.WithConcurrencyLimit(sem)(r)
+
+			wg := sync.WaitGroup{}
+			for i := 0; i < 10; i++ {
+				for _, rec := range []reconcile.Reconciler{first, second} {
+					wg.Add(1)
+					go func(rec reconcile.Reconciler) {
+						defer GinkgoRecover()
+						defer wg.Done()
+						res, err := rec.Reconcile(context.Background(), reconcile.Request{})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(res).To(Equal(expectedResult))
+					}(rec)
+				}
+			}
+			wg.Wait()
+			Expect(atomic.LoadInt32(&maxInFlight)).To(BeNumerically("<=", 2))
+		})
+
+		It("should return the context error if the context is cancelled while waiting", func() {
+			sem := make(chan struct{}, 1)
+			sem <- struct{}{}
+			called := false
+			r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				called = true
+				return reconcile.Result{}, nil
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := middleware.WithConcurrencyLimit(sem)(r).Reconcile(ctx, reconcile.Request{})
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(called).To(BeFalse())
+		})
+	})
+
+	It("should compose with reconcile.Chain", func() {
+		r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+			panic("boom")
+		})
+
+		chained := reconcile.Chain(r,
+			middleware.WithLogging("key", "value"),
+			middleware.WithTimeout(time.Minute),
+			middleware.WithRecover(),
+			middleware.WithConcurrencyLimit(make(chan struct{}, 1)),
+		)
+		_, err := chained.Reconcile(context.Background(), reconcile.Request{})
+		Expect(err).To(MatchError(ContainSubstring("[recovered]")))
+	})
+})
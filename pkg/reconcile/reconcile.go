@@ -18,12 +18,32 @@ package reconcile
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"time"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 // Result contains the result of a Reconciler invocation.
+//
+// The fields of Result take effect in the following order of precedence:
+//
+//	error    | RequeueAfter | Requeue | Priority | outcome
+//	---------+--------------+---------+----------+---------------------------------------------------
+//	terminal | any          | any     | any      | no requeue
+//	non-nil  | any          | any     | any      | rate limited requeue at the priority of the Request
+//	nil      | > 0          | any     | any      | requeue after RequeueAfter at Priority
+//	nil      | 0            | true    | any      | rate limited requeue at Priority
+//	nil      | 0            | false   | any      | no requeue
+//
+// Priority only affects requeues and is ignored if the Controller's queue does not
+// implement priorityqueue.PriorityQueue.
 type Result struct {
 	// Requeue tells the Controller to requeue the reconcile key.  Defaults to false.
 	Requeue bool
@@ -31,6 +51,11 @@ type Result struct {
 	// RequeueAfter if greater than 0, tells the Controller to requeue the reconcile key after the Duration.
 	// Implies that Requeue is true, there is no need to set Requeue to true at the same time as RequeueAfter.
 	RequeueAfter time.Duration
+
+	// Priority is the priority with which the reconcile key is requeued. It does not cause a requeue by
+	// itself. If unset, the key is requeued with the priority it was dequeued with, which is the one
+	// assigned by the event handler that enqueued it.
+	Priority *int
 }
 
 // IsZero returns true if this result is empty.
@@ -47,8 +72,17 @@ func (r *Result) IsZero() bool {
 type Request struct {
 	// NamespacedName is the name and namespace of the object to reconcile.
 	types.NamespacedName
+
+	// ClusterName is the name of the cluster engaged by the manager that the object is in,
+	// or empty if it's in the cluster of the manager. See cluster.Aware.
+	ClusterName string
 }
 
+// ClusterAwareRequest is a Request qualified with the name of the cluster its object is
+// in. The reconcilers of the requests of several clusters get the cluster of a request,
+// and so its client, with cluster.FromContext.
+type ClusterAwareRequest = Request
+
 /*
 Reconciler implements a Kubernetes API for a specific Resource by Creating, Updating or Deleting Kubernetes
 objects, or by making changes to systems external to the cluster (e.g. cloudproviders, github, etc).
@@ -86,12 +120,7 @@ driven by actual cluster state read from the apiserver or a local cache.
 For example if responding to a Pod Delete Event, the Request won't contain that a Pod was deleted,
 instead the reconcile function observes this when reading the cluster state and seeing the Pod as missing.
 */
-type Reconciler interface {
-	// Reconcile performs a full reconciliation for the object referred to by the Request.
-	// The Controller will requeue the Request to be processed again if an error is non-nil or
-	// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
-	Reconcile(context.Context, Request) (Result, error)
-}
+type Reconciler = TypedReconciler[Request]
 
 // Func is a function that implements the reconcile interface.
 type Func func(context.Context, Request) (Result, error)
@@ -100,3 +129,141 @@ var _ Reconciler = Func(nil)
 
 // Reconcile implements Reconciler.
 func (r Func) Reconcile(ctx context.Context, o Request) (Result, error) { return r(ctx, o) }
+
+// TypedReconciler is a Reconciler of requests of a custom type, e.g. to reconcile external
+// resources keyed by their ARN, or objects keyed by a cluster and a name. The type of the
+// requests is used as a map key by the queue of the controller, so it must be comparable.
+type TypedReconciler[request comparable] interface {
+	// Reconcile performs a full reconciliation for the object referred to by the request.
+	// The Controller will requeue the request to be processed again if an error is non-nil or
+	// Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
+	// Errors wrapped with TerminalError are never requeued.
+	Reconcile(context.Context, request) (Result, error)
+}
+
+// TypedFunc is a function that implements the TypedReconciler interface.
+type TypedFunc[request comparable] func(context.Context, request) (Result, error)
+
+var _ TypedReconciler[Request] = TypedFunc[Request](nil)
+
+// Reconcile implements TypedReconciler.
+func (r TypedFunc[request]) Reconcile(ctx context.Context, req request) (Result, error) {
+	return r(ctx, req)
+}
+
+// TypedBatchReconciler is implemented by reconcilers that can reconcile many requests with one call,
+// e.g. to satisfy many objects with one expensive call to an external API. A controller whose
+// Reconciler implements it hands the requests that are queued at the same time to a single call of
+// ReconcileBatch instead of calling Reconcile for each of them.
+type TypedBatchReconciler[request comparable] interface {
+	TypedReconciler[request]
+
+	// ReconcileBatch reconciles the given requests, which are distinct. The Result and error
+	// apply to all of them, e.g. all the requests are requeued if the error is non-nil.
+	ReconcileBatch(context.Context, []request) (Result, error)
+}
+
+// BatchReconciler is a TypedBatchReconciler of Requests.
+type BatchReconciler = TypedBatchReconciler[Request]
+
+// Chain wraps r with the given middlewares. The first middleware is the outermost one, i.e.
+// Chain(r, a, b) is equivalent to a(b(r)), and a observes each Request first and each Result last.
+func Chain(r Reconciler, middlewares ...func(Reconciler) Reconciler) Reconciler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		r = middlewares[i](r)
+	}
+	return r
+}
+
+// ObjectReconciler is a specialized version of Reconciler that acts on instances of client.Object. Each
+// reconciliation event gets the associated object from the API server (or the cache backing the reader)
+// before passing it to Reconcile. An ObjectReconciler can be used in Builder.Complete by wrapping it with
+// AsReconciler. See Reconciler for more details.
+type ObjectReconciler[object client.Object] interface {
+	Reconcile(context.Context, object) (Result, error)
+}
+
+// AsReconciler creates a Reconciler based on the given ObjectReconciler. The returned Reconciler:
+//
+// * Gets the object referred to by the Request using the given reader, typically the manager's client.
+// * Returns an empty Result and no error if the object is not found, as it was deleted in the meantime.
+// * Returns any other error from Get as is, so that the Request is retried with backoff.
+// * Sets the GroupVersionKind of the object if the reader exposes its Scheme, as client.Client does.
+// * Forwards objects with a deletionTimestamp like any other object, leaving finalization to rec.
+// * Adds the UID and GroupVersionKind of the object to the logger in the context passed to rec, see log.SetReconcileLogFields.
+//
+// The object type must be a pointer to a struct, e.g. *corev1.Pod. Unstructured objects are not supported,
+// as their GroupVersionKind can not be inferred from their type.
+func AsReconciler[object client.Object](reader client.Reader, rec ObjectReconciler[object]) Reconciler {
+	return &objectReconcilerAdapter[object]{
+		objReconciler: rec,
+		reader:        reader,
+	}
+}
+
+type objectReconcilerAdapter[object client.Object] struct {
+	objReconciler ObjectReconciler[object]
+	reader        client.Reader
+}
+
+// Reconcile implements Reconciler.
+func (a *objectReconcilerAdapter[object]) Reconcile(ctx context.Context, req Request) (Result, error) {
+	o := reflect.New(reflect.TypeOf(*new(object)).Elem()).Interface().(object)
+	if err := a.reader.Get(ctx, req.NamespacedName, o); err != nil {
+		return Result{}, client.IgnoreNotFound(err)
+	}
+
+	if withScheme, ok := a.reader.(interface{ Scheme() *runtime.Scheme }); ok {
+		gvk, err := apiutil.GVKForObject(o, withScheme.Scheme())
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to get GroupVersionKind for %T: %w", o, err)
+		}
+		o.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	var keysAndValues []interface{}
+	if logf.HasReconcileLogField(logf.ReconcileLogFieldObjectUID) {
+		keysAndValues = append(keysAndValues, "objectUID", o.GetUID())
+	}
+	if gvk := o.GetObjectKind().GroupVersionKind(); !gvk.Empty() && logf.HasReconcileLogField(logf.ReconcileLogFieldObjectGVK) {
+		keysAndValues = append(keysAndValues, "objectGVK", gvk.String())
+	}
+	if len(keysAndValues) > 0 {
+		ctx = logf.IntoContext(ctx, logf.FromContext(ctx, keysAndValues...))
+	}
+
+	return a.objReconciler.Reconcile(ctx, o)
+}
+
+// TerminalError is an error that will not be retried but still be logged
+// and recorded in metrics. The controller forgets the request's rate limiter
+// entry instead of requeueing it. When a TerminalError is returned together
+// with a non-zero Result, the error wins and the request is not requeued.
+//
+// The wrapped error can still be inspected with errors.Is and errors.As.
+func TerminalError(wrapped error) error {
+	return &terminalError{err: wrapped}
+}
+
+type terminalError struct {
+	err error
+}
+
+// Unwrap returns the wrapped error. It returns nil if the wrapped error is nil.
+func (te *terminalError) Unwrap() error {
+	return te.err
+}
+
+func (te *terminalError) Error() string {
+	if te.err == nil {
+		return "nil terminal error"
+	}
+	return "terminal error: " + te.err.Error()
+}
+
+// Is allows errors.Is(err, TerminalError(nil)) to detect a terminal error
+// anywhere in the chain of err.
+func (te *terminalError) Is(target error) bool {
+	tp := &terminalError{}
+	return errors.As(target, &tp)
+}
@@ -18,12 +18,20 @@ package reconcile_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -47,6 +55,12 @@ var _ = Describe("reconcile", func() {
 			res := reconcile.Result{RequeueAfter: 1 * time.Second}
 			Expect(res.IsZero()).To(BeFalse())
 		})
+
+		It("IsZero should return false if Priority is set", func() {
+			priority := 0
+			res := reconcile.Result{Priority: &priority}
+			Expect(res.IsZero()).To(BeFalse())
+		})
 	})
 
 	Describe("Func", func() {
@@ -89,4 +103,196 @@ var _ = Describe("reconcile", func() {
 			Expect(actualErr).To(Equal(err))
 		})
 	})
+
+	Describe("TypedFunc", func() {
+		It("should call the function with the request of a custom type.", func() {
+			type externalRequest struct {
+				ARN string
+			}
+			result := reconcile.Result{Requeue: true}
+
+			var instance reconcile.TypedReconciler[externalRequest] = reconcile.TypedFunc[externalRequest](func(_ context.Context, r externalRequest) (reconcile.Result, error) {
+				defer GinkgoRecover()
+				Expect(r.ARN).To(Equal("arn:aws:s3:::bucket"))
+
+				return result, nil
+			})
+			actualResult, actualErr := instance.Reconcile(context.Background(), externalRequest{ARN: "arn:aws:s3:::bucket"})
+			Expect(actualResult).To(Equal(result))
+			Expect(actualErr).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Chain", func() {
+		It("should return the reconciler if there are no middlewares", func() {
+			var calls int
+			r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				calls++
+				return reconcile.Result{}, nil
+			})
+			_, err := reconcile.Chain(r).Reconcile(context.Background(), reconcile.Request{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should run the middlewares outermost first", func() {
+			var calls []string
+			record := func(name string) func(reconcile.Reconciler) reconcile.Reconciler {
+				return func(next reconcile.Reconciler) reconcile.Reconciler {
+					return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+						calls = append(calls, name+" before")
+						res, err := next.Reconcile(ctx, req)
+						calls = append(calls, name+" after")
+						return res, err
+					})
+				}
+			}
+			err := fmt.Errorf("expected error")
+			r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				calls = append(calls, "reconciler")
+				return reconcile.Result{RequeueAfter: time.Second}, err
+			})
+
+			res, actualErr := reconcile.Chain(r, record("first"), record("second")).Reconcile(context.Background(), reconcile.Request{})
+			Expect(actualErr).To(BeIdenticalTo(err))
+			Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+			Expect(calls).To(Equal([]string{"first before", "second before", "reconciler", "second after", "first after"}))
+		})
+	})
+
+	Describe("AsReconciler", func() {
+		var request = reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"},
+		}
+
+		It("should forward the object with its GroupVersionKind set", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+			c := fake.NewClientBuilder().WithObjects(pod).Build()
+
+			rec := &fakeObjectReconciler{result: reconcile.Result{Requeue: true}}
+			res, err := reconcile.AsReconciler[*corev1.Pod](c, rec).Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res).To(Equal(reconcile.Result{Requeue: true}))
+			Expect(rec.calls).To(HaveLen(1))
+			Expect(rec.calls[0].Name).To(Equal("foo"))
+			Expect(rec.calls[0].GetObjectKind().GroupVersionKind()).To(Equal(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}))
+		})
+
+		It("should add the UID and GroupVersionKind of the object to the logger", func() {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", UID: "uid-foo"}}
+			c := fake.NewClientBuilder().WithObjects(pod).Build()
+
+			var logged []string
+			ctx := logf.IntoContext(context.Background(), funcr.New(func(prefix, args string) {
+				logged = append(logged, args)
+			}, funcr.Options{}))
+
+			_, err := reconcile.AsReconciler[*corev1.Pod](c, &fakeObjectReconciler{}).Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logged).To(ConsistOf(And(
+				ContainSubstring(`"objectUID"="uid-foo"`),
+				ContainSubstring(`"objectGVK"="/v1, Kind=Pod"`),
+			)))
+
+			By("omitting fields that are not logged")
+			logf.SetReconcileLogFields(logf.ReconcileLogFieldObjectGVK)
+			defer logf.SetReconcileLogFields(logf.DefaultReconcileLogFields()...)
+			logged = nil
+
+			_, err = reconcile.AsReconciler[*corev1.Pod](c, &fakeObjectReconciler{}).Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logged).To(ConsistOf(And(
+				Not(ContainSubstring("objectUID")),
+				ContainSubstring(`"objectGVK"="/v1, Kind=Pod"`),
+			)))
+		})
+
+		It("should not call the ObjectReconciler if the object is not found", func() {
+			c := fake.NewClientBuilder().Build()
+
+			rec := &fakeObjectReconciler{}
+			res, err := reconcile.AsReconciler[*corev1.Pod](c, rec).Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.IsZero()).To(BeTrue())
+			Expect(rec.calls).To(BeEmpty())
+		})
+
+		It("should return errors from Get other than NotFound", func() {
+			getErr := fmt.Errorf("connection refused")
+			c := &erroringReader{Reader: fake.NewClientBuilder().Build(), err: getErr}
+
+			rec := &fakeObjectReconciler{}
+			_, err := reconcile.AsReconciler[*corev1.Pod](c, rec).Reconcile(context.Background(), request)
+			Expect(err).To(MatchError(getErr))
+			Expect(rec.calls).To(BeEmpty())
+		})
+
+		It("should forward objects that have a deletionTimestamp", func() {
+			now := metav1.Now()
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo",
+				Namespace:         "bar",
+				DeletionTimestamp: &now,
+				Finalizers:        []string{"example.com/finalizer"},
+			}}
+			c := fake.NewClientBuilder().WithObjects(pod).Build()
+
+			rec := &fakeObjectReconciler{}
+			_, err := reconcile.AsReconciler[*corev1.Pod](c, rec).Reconcile(context.Background(), request)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rec.calls).To(HaveLen(1))
+			Expect(rec.calls[0].DeletionTimestamp).NotTo(BeNil())
+		})
+	})
+
+	Describe("TerminalError", func() {
+		It("should be detectable with errors.Is", func() {
+			err := reconcile.TerminalError(fmt.Errorf("invalid spec"))
+			Expect(errors.Is(err, reconcile.TerminalError(nil))).To(BeTrue())
+			Expect(errors.Is(fmt.Errorf("wrapped: %w", err), reconcile.TerminalError(nil))).To(BeTrue())
+			Expect(errors.Is(fmt.Errorf("invalid spec"), reconcile.TerminalError(nil))).To(BeFalse())
+		})
+
+		It("should let errors.Is and errors.As see the wrapped error", func() {
+			cause := &fakeError{msg: "unsupported configuration"}
+			err := reconcile.TerminalError(cause)
+			Expect(errors.Is(err, cause)).To(BeTrue())
+
+			var target *fakeError
+			Expect(errors.As(err, &target)).To(BeTrue())
+			Expect(target).To(BeIdenticalTo(cause))
+			Expect(errors.Unwrap(err)).To(BeIdenticalTo(cause))
+		})
+
+		It("should include the wrapped error in its message", func() {
+			Expect(reconcile.TerminalError(fmt.Errorf("boom")).Error()).To(Equal("terminal error: boom"))
+			Expect(reconcile.TerminalError(nil).Error()).To(Equal("nil terminal error"))
+		})
+	})
 })
+
+type fakeError struct {
+	msg string
+}
+
+func (f *fakeError) Error() string { return f.msg }
+
+type fakeObjectReconciler struct {
+	calls  []*corev1.Pod
+	result reconcile.Result
+}
+
+func (f *fakeObjectReconciler) Reconcile(ctx context.Context, pod *corev1.Pod) (reconcile.Result, error) {
+	logf.FromContext(ctx).Info("Reconciling")
+	f.calls = append(f.calls, pod)
+	return f.result, nil
+}
+
+type erroringReader struct {
+	client.Reader
+	err error
+}
+
+func (e *erroringReader) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	return e.err
+}
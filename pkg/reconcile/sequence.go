@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// Step is a single phase of a reconciliation, e.g. ensuring a finalizer or a dependent object.
+type Step func(ctx context.Context) (Result, error)
+
+// Sequence returns a Step that runs the given steps in order and stops at the first error.
+// The Results of all steps that ran are merged with CombineResults.
+func Sequence(steps ...Step) Step {
+	return func(ctx context.Context) (Result, error) {
+		var res Result
+		for _, step := range steps {
+			stepRes, err := step(ctx)
+			res = CombineResults(res, stepRes)
+			if err != nil {
+				return res, err
+			}
+		}
+		return res, nil
+	}
+}
+
+// SequenceContinueOnError returns a Step that runs all the given steps in order, even if some of
+// them fail. The Results of all steps are merged with CombineResults and their errors are
+// returned as an aggregate.
+func SequenceContinueOnError(steps ...Step) Step {
+	return func(ctx context.Context) (Result, error) {
+		var res Result
+		var errs []error
+		for _, step := range steps {
+			stepRes, err := step(ctx)
+			res = CombineResults(res, stepRes)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return res, kerrors.NewAggregate(errs)
+	}
+}
+
+// CombineResults merges two Results so that neither of the requested requeues is lost:
+//
+// * Requeue is true if it is true in either of them.
+// * RequeueAfter is the shortest non-zero RequeueAfter of the two.
+// * Priority is the highest Priority set in either of them, or unset if neither sets it.
+func CombineResults(a, b Result) Result {
+	res := Result{
+		Requeue:      a.Requeue || b.Requeue,
+		RequeueAfter: a.RequeueAfter,
+		Priority:     a.Priority,
+	}
+	if b.RequeueAfter > 0 && (res.RequeueAfter <= 0 || b.RequeueAfter < res.RequeueAfter) {
+		res.RequeueAfter = b.RequeueAfter
+	}
+	if b.Priority != nil && (res.Priority == nil || *b.Priority > *res.Priority) {
+		res.Priority = b.Priority
+	}
+	return res
+}
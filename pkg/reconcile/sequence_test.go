@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Sequence", func() {
+	var calls []string
+	step := func(name string, res reconcile.Result, err error) reconcile.Step {
+		return func(context.Context) (reconcile.Result, error) {
+			calls = append(calls, name)
+			return res, err
+		}
+	}
+
+	BeforeEach(func() {
+		calls = nil
+	})
+
+	It("should run all steps in order and combine their results", func() {
+		res, err := reconcile.Sequence(
+			step("finalizer", reconcile.Result{}, nil),
+			step("deployment", reconcile.Result{RequeueAfter: time.Minute}, nil),
+			step("service", reconcile.Result{Requeue: true, RequeueAfter: time.Hour}, nil),
+		)(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.Result{Requeue: true, RequeueAfter: time.Minute}))
+		Expect(calls).To(Equal([]string{"finalizer", "deployment", "service"}))
+	})
+
+	It("should stop at the first error", func() {
+		expectedErr := fmt.Errorf("expected error")
+		res, err := reconcile.Sequence(
+			step("finalizer", reconcile.Result{RequeueAfter: time.Minute}, nil),
+			step("deployment", reconcile.Result{}, expectedErr),
+			step("service", reconcile.Result{}, nil),
+		)(context.Background())
+		Expect(err).To(BeIdenticalTo(expectedErr))
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+		Expect(calls).To(Equal([]string{"finalizer", "deployment"}))
+	})
+
+	It("should be nestable", func() {
+		res, err := reconcile.Sequence(
+			step("finalizer", reconcile.Result{}, nil),
+			reconcile.Sequence(
+				step("deployment", reconcile.Result{RequeueAfter: time.Hour}, nil),
+				step("service", reconcile.Result{RequeueAfter: time.Minute}, nil),
+			),
+		)(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+		Expect(calls).To(Equal([]string{"finalizer", "deployment", "service"}))
+	})
+
+	Describe("SequenceContinueOnError", func() {
+		It("should run all steps and aggregate their errors", func() {
+			firstErr := fmt.Errorf("first error")
+			secondErr := fmt.Errorf("second error")
+			res, err := reconcile.SequenceContinueOnError(
+				step("finalizer", reconcile.Result{}, firstErr),
+				step("deployment", reconcile.Result{RequeueAfter: time.Minute}, nil),
+				step("service", reconcile.Result{}, secondErr),
+			)(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, firstErr)).To(BeTrue())
+			Expect(errors.Is(err, secondErr)).To(BeTrue())
+			Expect(res).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+			Expect(calls).To(Equal([]string{"finalizer", "deployment", "service"}))
+		})
+
+		It("should return a nil error if no step fails", func() {
+			_, err := reconcile.SequenceContinueOnError(
+				step("finalizer", reconcile.Result{}, nil),
+			)(context.Background())
+			Expect(err).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("CombineResults", func() {
+	priority := func(p int) *int { return &p }
+
+	DescribeTable("should merge results without losing requeues",
+		func(a, b, expected reconcile.Result) {
+			Expect(reconcile.CombineResults(a, b)).To(Equal(expected))
+			Expect(reconcile.CombineResults(b, a)).To(Equal(expected))
+		},
+		Entry("both empty",
+			reconcile.Result{}, reconcile.Result{}, reconcile.Result{}),
+		Entry("Requeue on one side",
+			reconcile.Result{Requeue: true}, reconcile.Result{}, reconcile.Result{Requeue: true}),
+		Entry("Requeue on both sides",
+			reconcile.Result{Requeue: true}, reconcile.Result{Requeue: true}, reconcile.Result{Requeue: true}),
+		Entry("RequeueAfter on one side",
+			reconcile.Result{RequeueAfter: time.Minute}, reconcile.Result{}, reconcile.Result{RequeueAfter: time.Minute}),
+		Entry("shortest RequeueAfter wins",
+			reconcile.Result{RequeueAfter: time.Minute}, reconcile.Result{RequeueAfter: time.Second}, reconcile.Result{RequeueAfter: time.Second}),
+		Entry("equal RequeueAfter",
+			reconcile.Result{RequeueAfter: time.Minute}, reconcile.Result{RequeueAfter: time.Minute}, reconcile.Result{RequeueAfter: time.Minute}),
+		Entry("negative RequeueAfter is ignored",
+			reconcile.Result{RequeueAfter: -time.Minute}, reconcile.Result{RequeueAfter: time.Second}, reconcile.Result{RequeueAfter: time.Second}),
+		Entry("Requeue and RequeueAfter on different sides",
+			reconcile.Result{Requeue: true}, reconcile.Result{RequeueAfter: time.Minute}, reconcile.Result{Requeue: true, RequeueAfter: time.Minute}),
+		Entry("Requeue and RequeueAfter on both sides",
+			reconcile.Result{Requeue: true, RequeueAfter: time.Hour}, reconcile.Result{RequeueAfter: time.Minute}, reconcile.Result{Requeue: true, RequeueAfter: time.Minute}),
+		Entry("Priority on one side",
+			reconcile.Result{Priority: priority(1)}, reconcile.Result{}, reconcile.Result{Priority: priority(1)}),
+		Entry("highest Priority wins",
+			reconcile.Result{Priority: priority(-1)}, reconcile.Result{Priority: priority(1)}, reconcile.Result{Priority: priority(1)}),
+	)
+})
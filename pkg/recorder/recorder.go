@@ -21,6 +21,7 @@ limitations under the License.
 package recorder
 
 import (
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/tools/record"
 )
 
@@ -29,3 +30,36 @@ type Provider interface {
 	// NewRecorder returns an EventRecorder with given name.
 	GetEventRecorderFor(name string) record.EventRecorder
 }
+
+// BroadcasterOptions configures how the event recorders of a manager or cluster
+// record events.
+type BroadcasterOptions struct {
+	// CorrelatorOptions configures the correlator of the core/v1 event broadcaster,
+	// which aggregates similar events, filters spam and rate limits the events of each
+	// object with a token bucket of BurstSize and QPS. Zero values use the client-go
+	// defaults.
+	CorrelatorOptions record.CorrelatorOptions
+
+	// Sink is the sink core/v1 events are written to, e.g. to tee them to another
+	// pipeline. Defaults to the Events API of the cluster.
+	Sink record.EventSink
+
+	// WrapSink wraps the sink core/v1 events are written to, whether it's Sink or the
+	// default one, e.g. with NewRateLimitedSink to rate limit the events of each object.
+	WrapSink func(record.EventSink) record.EventSink
+
+	// AdditionalSinks are sinks core/v1 events are written to on top of Sink, e.g. a
+	// sink created with NewLogSink, or a MemorySink in tests. The events are correlated
+	// for each sink on its own.
+	AdditionalSinks []record.EventSink
+
+	// UseEventsAPI makes the recorders emit events.k8s.io/v1 Events instead of core/v1
+	// ones. Their reason is used as action, and annotations are dropped. The correlator
+	// options don't apply, as the events.k8s.io broadcaster deduplicates events into
+	// series on its own.
+	UseEventsAPI bool
+
+	// EventsSink is the sink events.k8s.io/v1 Events are written to with UseEventsAPI.
+	// Defaults to the events.k8s.io API of the cluster.
+	EventsSink events.EventSink
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/lru"
+)
+
+// NewLogSink returns a sink that logs the events written to it, instead of writing them
+// to the API server.
+func NewLogSink(log logr.Logger) record.EventSink {
+	return &logSink{log: log}
+}
+
+type logSink struct {
+	log logr.Logger
+}
+
+func (s *logSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	s.logEvent(event)
+	return event, nil
+}
+
+func (s *logSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	s.logEvent(event)
+	return event, nil
+}
+
+func (s *logSink) Patch(oldEvent *corev1.Event, data []byte) (*corev1.Event, error) {
+	event, err := patchEvent(oldEvent, data)
+	if err != nil {
+		return nil, err
+	}
+	s.logEvent(event)
+	return event, nil
+}
+
+func (s *logSink) logEvent(event *corev1.Event) {
+	s.log.Info(event.Message, "type", event.Type, "object", event.InvolvedObject, "reason", event.Reason, "count", event.Count)
+}
+
+// MemorySink is a sink that keeps the events written to it in memory, e.g. to check
+// the events recorded in tests. The events that are aggregated by the correlator of
+// the broadcaster are updated in place.
+type MemorySink struct {
+	mu     sync.Mutex
+	events []*corev1.Event
+}
+
+var _ record.EventSink = &MemorySink{}
+
+// Create implements record.EventSink.
+func (s *MemorySink) Create(event *corev1.Event) (*corev1.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event.DeepCopy())
+	return event, nil
+}
+
+// Update implements record.EventSink.
+func (s *MemorySink) Update(event *corev1.Event) (*corev1.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(event)
+	return event, nil
+}
+
+// Patch implements record.EventSink.
+func (s *MemorySink) Patch(oldEvent *corev1.Event, data []byte) (*corev1.Event, error) {
+	event, err := patchEvent(oldEvent, data)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(event)
+	return event, nil
+}
+
+// store replaces the event of the same name, or appends it. It must be called with the
+// lock held.
+func (s *MemorySink) store(event *corev1.Event) {
+	for i, existing := range s.events {
+		if existing.Namespace == event.Namespace && existing.Name == event.Name {
+			s.events[i] = event.DeepCopy()
+			return
+		}
+	}
+	s.events = append(s.events, event.DeepCopy())
+}
+
+// Events returns the events written to the sink, in the order they were created.
+func (s *MemorySink) Events() []corev1.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make([]corev1.Event, 0, len(s.events))
+	for _, event := range s.events {
+		events = append(events, *event.DeepCopy())
+	}
+	return events
+}
+
+// Reset forgets the events written to the sink so far.
+func (s *MemorySink) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = nil
+}
+
+// rateLimitedObjects is the number of objects whose rate limiters are kept by a rate
+// limited sink, the least recently used ones being forgotten.
+const rateLimitedObjects = 4096
+
+// NewRateLimitedSink returns a sink that writes the events of each object to the given
+// sink at most qps times per second, with bursts of up to burst events, and drops the
+// other ones. Unlike the correlator options, which rate limit the events of an object
+// for each broadcaster, it applies to all the events written to the sink.
+func NewRateLimitedSink(sink record.EventSink, qps float64, burst int) record.EventSink {
+	return &rateLimitedSink{
+		sink:     sink,
+		qps:      rate.Limit(qps),
+		burst:    burst,
+		limiters: lru.New(rateLimitedObjects),
+	}
+}
+
+type rateLimitedSink struct {
+	sink  record.EventSink
+	qps   rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters *lru.Cache
+}
+
+// Create writes the event if the rate limiter of its object allows it. The events
+// that are dropped are reported as written, so that the broadcaster doesn't retry them.
+func (s *rateLimitedSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	if !s.allow(event) {
+		return event, nil
+	}
+	return s.sink.Create(event)
+}
+
+// Update writes the event, as it was already created.
+func (s *rateLimitedSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	return s.sink.Update(event)
+}
+
+// Patch writes the event, as it was already created. The broadcaster creates the
+// events that were dropped instead, once the patch fails as they don't exist.
+func (s *rateLimitedSink) Patch(oldEvent *corev1.Event, data []byte) (*corev1.Event, error) {
+	return s.sink.Patch(oldEvent, data)
+}
+
+func (s *rateLimitedSink) allow(event *corev1.Event) bool {
+	key := event.InvolvedObject.UID
+	if key == "" {
+		obj := event.InvolvedObject
+		key = types.UID(fmt.Sprintf("%s/%s/%s/%s", obj.APIVersion, obj.Kind, obj.Namespace, obj.Name))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limiter, ok := s.limiters.Get(key)
+	if !ok {
+		limiter = rate.NewLimiter(s.qps, s.burst)
+		s.limiters.Add(key, limiter)
+	}
+	return limiter.(*rate.Limiter).Allow()
+}
+
+// patchEvent returns the event patched with the strategic merge patch of the correlator.
+func patchEvent(event *corev1.Event, data []byte) (*corev1.Event, error) {
+	original, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := strategicpatch.StrategicMergePatch(original, data, &corev1.Event{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch event %s/%s: %w", event.Namespace, event.Name, err)
+	}
+	result := &corev1.Event{}
+	if err := json.Unmarshal(patched, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -51,12 +51,25 @@ limitations under the License.
 //		})
 //		// ...
 //	}
+//
+// Projects with several API groups, or which also need third-party types, can
+// assemble the builders into an Aggregate instead:
+//
+//	var AddToScheme = scheme.NewAggregate(
+//		myapigroupv1.SchemeBuilder,
+//		myothergroupv1beta1.SchemeBuilder,
+//		scheme.AddToSchemeFunc(kubernetesscheme.AddToScheme),
+//	).AddToScheme
 package scheme
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 // Builder builds a new Scheme for mapping go types to Kubernetes GroupVersionKinds.
@@ -68,8 +81,38 @@ type Builder struct {
 // Register adds one or more objects to the SchemeBuilder so they can be added to a Scheme.  Register mutates bld.
 func (bld *Builder) Register(object ...runtime.Object) *Builder {
 	bld.SchemeBuilder.Register(func(scheme *runtime.Scheme) error {
-		scheme.AddKnownTypes(bld.GroupVersion, object...)
-		metav1.AddToGroupVersion(scheme, bld.GroupVersion)
+		return addKnownTypes(scheme, bld.GroupVersion, object...)
+	})
+	return bld
+}
+
+// RegisterIn adds one or more objects to the SchemeBuilder under the given GroupVersion
+// rather than the one of the Builder, e.g. to register the types of several versions of
+// a group with a single Builder.  RegisterIn mutates bld.
+func (bld *Builder) RegisterIn(gv schema.GroupVersion, object ...runtime.Object) *Builder {
+	bld.SchemeBuilder.Register(func(scheme *runtime.Scheme) error {
+		return addKnownTypes(scheme, gv, object...)
+	})
+	return bld
+}
+
+// RegisterDefaultingFunc adds a function defaulting objects of the type of obj to the
+// SchemeBuilder.  RegisterDefaultingFunc mutates bld.
+func (bld *Builder) RegisterDefaultingFunc(obj runtime.Object, fn func(obj interface{})) *Builder {
+	bld.SchemeBuilder.Register(func(scheme *runtime.Scheme) error {
+		scheme.AddTypeDefaultingFunc(obj, fn)
+		return nil
+	})
+	return bld
+}
+
+// RegisterConversionFunc adds a function converting objects of the type of a into the
+// type of b to the SchemeBuilder.  RegisterConversionFunc mutates bld.
+func (bld *Builder) RegisterConversionFunc(a, b interface{}, fn conversion.ConversionFunc) *Builder {
+	bld.SchemeBuilder.Register(func(scheme *runtime.Scheme) error {
+		if err := scheme.AddConversionFunc(a, b, fn); err != nil {
+			return fmt.Errorf("failed to register conversion from %T to %T: %w", a, b, err)
+		}
 		return nil
 	})
 	return bld
@@ -91,3 +134,90 @@ func (bld *Builder) Build() (*runtime.Scheme, error) {
 	s := runtime.NewScheme()
 	return s, bld.AddToScheme(s)
 }
+
+// addKnownTypes adds the objects to the scheme under gv, and returns an error naming
+// the object that couldn't be added instead of panicking.
+func addKnownTypes(scheme *runtime.Scheme, gv schema.GroupVersion, objects ...runtime.Object) error {
+	for _, obj := range objects {
+		if err := addKnownType(scheme, gv, obj); err != nil {
+			return err
+		}
+	}
+	metav1.AddToGroupVersion(scheme, gv)
+	return nil
+}
+
+func addKnownType(scheme *runtime.Scheme, gv schema.GroupVersion, obj runtime.Object) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to register %T in %s: %v", obj, gv, r)
+		}
+	}()
+	scheme.AddKnownTypes(gv, obj)
+	return nil
+}
+
+// Adder adds types to a Scheme, like Builder, Aggregate or runtime.SchemeBuilder.
+type Adder interface {
+	AddToScheme(*runtime.Scheme) error
+}
+
+// AddToSchemeFunc turns an AddToScheme function, like the ones of the Kubernetes API
+// packages, into an Adder.
+type AddToSchemeFunc func(*runtime.Scheme) error
+
+// AddToScheme calls f.
+func (f AddToSchemeFunc) AddToScheme(s *runtime.Scheme) error {
+	return f(s)
+}
+
+// Aggregate adds the types of several Adders to a Scheme, e.g. the Builders of all the API
+// groups of a project as well as the third-party types it uses.
+type Aggregate struct {
+	adders []Adder
+}
+
+// NewAggregate returns an Aggregate of the given Adders.
+func NewAggregate(adders ...Adder) *Aggregate {
+	return &Aggregate{adders: adders}
+}
+
+// Add adds one or more Adders to the Aggregate.  Add mutates a.
+func (a *Aggregate) Add(adders ...Adder) *Aggregate {
+	a.adders = append(a.adders, adders...)
+	return a
+}
+
+// AddToScheme adds the types of all the Adders to s, in order. The error identifies the
+// Adder that failed.
+func (a *Aggregate) AddToScheme(s *runtime.Scheme) error {
+	for i, adder := range a.adders {
+		if err := adder.AddToScheme(s); err != nil {
+			if bld, ok := adder.(*Builder); ok {
+				return fmt.Errorf("failed to add the types of builder %d for %s to scheme: %w", i, bld.GroupVersion, err)
+			}
+			return fmt.Errorf("failed to add the types of builder %d (%T) to scheme: %w", i, adder, err)
+		}
+	}
+	return nil
+}
+
+// Build returns a new Scheme containing the types of all the Adders.
+func (a *Aggregate) Build() (*runtime.Scheme, error) {
+	s := runtime.NewScheme()
+	return s, a.AddToScheme(s)
+}
+
+// CheckRegistered returns an error listing the objects whose types aren't registered in s.
+// Use it in the tests of a manager with the types its controllers reconcile, own and
+// watch, to catch missing registrations before the controllers start rather than on
+// their first reconciliation.
+func CheckRegistered(s *runtime.Scheme, objs ...runtime.Object) error {
+	var errs []error
+	for _, obj := range objs {
+		if _, _, err := s.ObjectKinds(obj); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package scheme_test
 
 import (
+	"errors"
 	"reflect"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -24,6 +25,9 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/conversion"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
 )
@@ -115,5 +119,108 @@ var _ = Describe("Scheme", func() {
 				emptyGv.WithKind("Status"):          Ignore(),
 			}))
 		})
+
+		It("should register types in other GroupVersions with RegisterIn", func() {
+			gv := schema.GroupVersion{Group: "apps", Version: "v1"}
+			otherGv := schema.GroupVersion{Group: "apps", Version: "v2"}
+
+			s, err := (&scheme.Builder{GroupVersion: gv}).
+				Register(&appsv1.Deployment{}).
+				RegisterIn(otherGv, &appsv1.Deployment{}, &appsv1.DeploymentList{}).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Recognizes(gv.WithKind("Deployment"))).To(BeTrue())
+			Expect(s.Recognizes(gv.WithKind("DeploymentList"))).To(BeFalse())
+			Expect(s.Recognizes(otherGv.WithKind("Deployment"))).To(BeTrue())
+			Expect(s.Recognizes(otherGv.WithKind("DeploymentList"))).To(BeTrue())
+		})
+
+		It("should return an error identifying the type that couldn't be registered", func() {
+			gv := schema.GroupVersion{Group: "core", Version: "v1"}
+
+			_, err := (&scheme.Builder{GroupVersion: gv}).
+				Register(&corev1.Pod{}, &Pod{}).
+				Build()
+			Expect(err).To(MatchError(ContainSubstring("failed to register *scheme_test.Pod in core/v1")))
+		})
+
+		It("should register defaulting and conversion functions", func() {
+			gv := schema.GroupVersion{Group: "core", Version: "v1"}
+
+			s, err := (&scheme.Builder{GroupVersion: gv}).
+				Register(&corev1.Pod{}, &corev1.ConfigMap{}).
+				RegisterDefaultingFunc(&corev1.Pod{}, func(obj interface{}) {
+					obj.(*corev1.Pod).Spec.RestartPolicy = corev1.RestartPolicyNever
+				}).
+				RegisterConversionFunc(&corev1.Pod{}, &corev1.ConfigMap{}, func(a, b interface{}, _ conversion.Scope) error {
+					b.(*corev1.ConfigMap).Name = a.(*corev1.Pod).Name
+					return nil
+				}).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+			s.Default(pod)
+			Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+
+			cm := &corev1.ConfigMap{}
+			Expect(s.Convert(pod, cm, nil)).To(Succeed())
+			Expect(cm.Name).To(Equal("foo"))
+		})
+	})
+
+	Describe("Aggregate", func() {
+		It("should provide a Scheme with the types of all the builders", func() {
+			gv1 := schema.GroupVersion{Group: "core", Version: "v1"}
+			gv2 := schema.GroupVersion{Group: "apps", Version: "v1"}
+
+			s, err := scheme.NewAggregate(
+				(&scheme.Builder{GroupVersion: gv1}).Register(&corev1.Pod{}),
+				scheme.AddToSchemeFunc(func(s *runtime.Scheme) error {
+					s.AddKnownTypes(gv2, &appsv1.Deployment{})
+					return nil
+				}),
+			).Add((&scheme.Builder{GroupVersion: gv2}).Register(&appsv1.DeploymentList{})).Build()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Recognizes(gv1.WithKind("Pod"))).To(BeTrue())
+			Expect(s.Recognizes(gv2.WithKind("Deployment"))).To(BeTrue())
+			Expect(s.Recognizes(gv2.WithKind("DeploymentList"))).To(BeTrue())
+		})
+
+		It("should return an error identifying the builder that failed", func() {
+			gv := schema.GroupVersion{Group: "core", Version: "v1"}
+
+			_, err := scheme.NewAggregate(
+				(&scheme.Builder{GroupVersion: gv}).Register(&corev1.Pod{}),
+				(&scheme.Builder{GroupVersion: gv}).Register(&Pod{}),
+			).Build()
+			Expect(err).To(MatchError(ContainSubstring("failed to add the types of builder 1 for core/v1 to scheme")))
+
+			_, err = scheme.NewAggregate(scheme.AddToSchemeFunc(func(*runtime.Scheme) error {
+				return errors.New("expected error")
+			})).Build()
+			Expect(err).To(MatchError("failed to add the types of builder 0 (scheme.AddToSchemeFunc) to scheme: expected error"))
+		})
+	})
+
+	Describe("CheckRegistered", func() {
+		It("should return an error listing the types that aren't registered", func() {
+			s, err := (&scheme.Builder{GroupVersion: schema.GroupVersion{Group: "", Version: "v1"}}).
+				Register(&corev1.Pod{}).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(scheme.CheckRegistered(s, &corev1.Pod{})).To(Succeed())
+			err = scheme.CheckRegistered(s, &corev1.Pod{}, &corev1.ConfigMap{}, &appsv1.Deployment{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("v1.ConfigMap"))
+			Expect(err.Error()).To(ContainSubstring("v1.Deployment"))
+			Expect(err.Error()).NotTo(ContainSubstring("v1.Pod"))
+		})
 	})
 })
+
+// Pod is a type whose kind conflicts with corev1.Pod.
+type Pod struct {
+	corev1.Pod
+}
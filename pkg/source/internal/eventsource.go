@@ -17,6 +17,7 @@ limitations under the License.
 package internal
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/client-go/tools/cache"
@@ -35,13 +36,24 @@ var _ cache.ResourceEventHandler = EventHandler{}
 
 // EventHandler adapts a handler.EventHandler interface to a cache.ResourceEventHandler interface.
 type EventHandler struct {
+	// Ctx, if set, stops the handling of events once it's done, as handlers can't be
+	// removed from informers.
+	Ctx          context.Context
 	EventHandler handler.EventHandler
 	Queue        workqueue.RateLimitingInterface
 	Predicates   []predicate.Predicate
 }
 
+// stopped returns whether the context of the handler is done.
+func (e EventHandler) stopped() bool {
+	return e.Ctx != nil && e.Ctx.Err() != nil
+}
+
 // OnAdd creates CreateEvent and calls Create on EventHandler.
 func (e EventHandler) OnAdd(obj interface{}) {
+	if e.stopped() {
+		return
+	}
 	c := event.CreateEvent{}
 
 	// Pull Object out of the object
@@ -65,6 +77,9 @@ func (e EventHandler) OnAdd(obj interface{}) {
 
 // OnUpdate creates UpdateEvent and calls Update on EventHandler.
 func (e EventHandler) OnUpdate(oldObj, newObj interface{}) {
+	if e.stopped() {
+		return
+	}
 	u := event.UpdateEvent{}
 
 	if o, ok := oldObj.(client.Object); ok {
@@ -96,6 +111,9 @@ func (e EventHandler) OnUpdate(oldObj, newObj interface{}) {
 
 // OnDelete creates DeleteEvent and calls Delete on EventHandler.
 func (e EventHandler) OnDelete(obj interface{}) {
+	if e.stopped() {
+		return
+	}
 	d := event.DeleteEvent{}
 
 	// Deal with tombstone events by pulling the object out.  Tombstone events wrap the object in a
@@ -17,6 +17,8 @@ limitations under the License.
 package internal_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/client-go/tools/cache"
@@ -100,6 +102,23 @@ var _ = Describe("Internal", func() {
 			instance.OnAdd(pod)
 		})
 
+		It("should not handle events once its context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			instance.Ctx = ctx
+			instance.EventHandler = setfuncs
+
+			set = false
+			instance.OnAdd(pod)
+			Expect(set).To(BeTrue())
+
+			cancel()
+			set = false
+			instance.OnAdd(pod)
+			instance.OnUpdate(pod, newPod)
+			instance.OnDelete(pod)
+			Expect(set).To(BeFalse())
+		})
+
 		It("should used Predicates to filter CreateEvents", func() {
 			instance = internal.EventHandler{
 				Queue:        controllertest.Queue{},
@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source/internal"
 
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
 
@@ -56,7 +57,8 @@ const (
 // interfaces, the dependencies will be injected by the Controller when Watch is called.
 type Source interface {
 	// Start is internal and should be called only by the Controller to register an EventHandler with the Informer
-	// to enqueue reconcile.Requests.
+	// to enqueue reconcile.Requests. The source stops once the context is cancelled, e.g. when the Controller
+	// stops or the source is removed with Unwatch.
 	Start(context.Context, handler.EventHandler, workqueue.RateLimitingInterface, ...predicate.Predicate) error
 }
 
@@ -87,6 +89,42 @@ func (ks *kindWithCache) WaitForSync(ctx context.Context) error {
 	return ks.kind.WaitForSync(ctx)
 }
 
+// ClusterSource is a source of events in the clusters engaged by the manager. Controllers
+// implementing cluster.Aware start the Source returned by ForCluster for every engaged
+// cluster, and stop it once the cluster is disengaged. A ClusterSource can't be started
+// by itself.
+type ClusterSource interface {
+	Source
+
+	// ForCluster returns the Source of events in the cluster with the given name.
+	ForCluster(name string, cl cluster.Cluster) (Source, error)
+}
+
+// ClusterKind returns a ClusterSource of events for the type of the object in the cache
+// of each engaged cluster.
+func ClusterKind(object client.Object) ClusterSource {
+	return &clusterKind{Type: object}
+}
+
+type clusterKind struct {
+	Type client.Object
+}
+
+func (ks *clusterKind) Start(context.Context, handler.EventHandler, workqueue.RateLimitingInterface, ...predicate.Predicate) error {
+	return fmt.Errorf("%s can only be started in the engaged clusters by a controller", ks)
+}
+
+func (ks *clusterKind) ForCluster(_ string, cl cluster.Cluster) (Source, error) {
+	if ks.Type == nil {
+		return nil, fmt.Errorf("must specify the type of ClusterKind")
+	}
+	return NewKindWithCache(ks.Type, cl.GetCache()), nil
+}
+
+func (ks *clusterKind) String() string {
+	return fmt.Sprintf("cluster kind source: %T", ks.Type)
+}
+
 // Kind is used to provide a source of events originating inside the cluster from Watches (e.g. Pod Create).
 type Kind struct {
 	// Type is the type of object to watch.  e.g. &v1.Pod{}
@@ -117,6 +155,9 @@ func (ks *Kind) Start(ctx context.Context, handler handler.EventHandler, queue w
 		return fmt.Errorf("must call CacheInto on Kind before calling Start")
 	}
 
+	// The events are handled until the context is cancelled.
+	handlerCtx := ctx
+
 	// cache.GetInformer will block until its context is cancelled if the cache was already started and it can not
 	// sync that informer (most commonly due to RBAC issues).
 	ctx, ks.startCancel = context.WithCancel(ctx)
@@ -155,7 +196,7 @@ func (ks *Kind) Start(ctx context.Context, handler handler.EventHandler, queue w
 			return
 		}
 
-		i.AddEventHandler(internal.EventHandler{Queue: queue, EventHandler: handler, Predicates: prct})
+		i.AddEventHandler(internal.EventHandler{Ctx: handlerCtx, Queue: queue, EventHandler: handler, Predicates: prct})
 		if !ks.cache.WaitForCacheSync(ctx) {
 			// Would be great to return something more informative here
 			ks.started <- errors.New("cache did not sync")
@@ -205,26 +246,34 @@ var _ Source = &Channel{}
 // (e.g. GitHub Webhook callback).  Channel requires the user to wire the external
 // source (eh.g. http handler) to write GenericEvents to the underlying channel.
 type Channel struct {
-	// once ensures the event distribution goroutine will be performed only once
-	once sync.Once
-
 	// Source is the source channel to fetch GenericEvents
 	Source <-chan event.GenericEvent
 
 	// stop is to end ongoing goroutine, and close the channels
 	stop <-chan struct{}
 
-	// dest is the destination channels of the added event handlers
-	dest []chan event.GenericEvent
-
 	// DestBufferSize is the specified buffer size of dest channels.
 	// Default to 1024 if not specified.
 	DestBufferSize int
 
-	// destLock is to ensure the destination channels are safely added/removed
+	// loop is the running event distribution goroutine, if any. A new one is started
+	// by the first Start after the previous one stopped, e.g. when the controller
+	// is restarted with a new context.
+	loop *channelLoop
+
+	// destLock is to ensure the loop and its destination channels are safely added/removed
 	destLock sync.Mutex
 }
 
+// channelLoop is an event distribution goroutine of a Channel.
+type channelLoop struct {
+	// ctx is the context the loop was started with
+	ctx context.Context
+
+	// dest is the destination channels of the event handlers added while the loop runs
+	dest []chan event.GenericEvent
+}
+
 func (cs *Channel) String() string {
 	return fmt.Sprintf("channel source: %p", cs)
 }
@@ -265,13 +314,15 @@ func (cs *Channel) Start(
 	dst := make(chan event.GenericEvent, cs.DestBufferSize)
 
 	cs.destLock.Lock()
-	cs.dest = append(cs.dest, dst)
-	cs.destLock.Unlock()
-
-	cs.once.Do(func() {
+	// A loop whose context is done is stopping, and closes only its own destination
+	// channels, so a restarted controller gets a new loop.
+	if cs.loop == nil || cs.loop.ctx.Err() != nil {
+		cs.loop = &channelLoop{ctx: ctx}
 		// Distribute GenericEvents to all EventHandler / Queue pairs Watching this source
-		go cs.syncLoop(ctx)
-	})
+		go cs.syncLoop(cs.loop)
+	}
+	cs.loop.dest = append(cs.loop.dest, dst)
+	cs.destLock.Unlock()
 
 	go func() {
 		for evt := range dst {
@@ -292,20 +343,30 @@ func (cs *Channel) Start(
 	return nil
 }
 
-func (cs *Channel) doStop() {
+func (cs *Channel) doStop(loop *channelLoop) {
 	cs.destLock.Lock()
 	defer cs.destLock.Unlock()
 
-	for _, dst := range cs.dest {
+	for _, dst := range loop.dest {
 		close(dst)
 	}
+	loop.dest = nil
+	if cs.loop == loop {
+		cs.loop = nil
+	}
 }
 
-func (cs *Channel) distribute(evt event.GenericEvent) {
+func (cs *Channel) distribute(loop *channelLoop, evt event.GenericEvent) {
 	cs.destLock.Lock()
 	defer cs.destLock.Unlock()
 
-	for _, dst := range cs.dest {
+	// A stopping loop may still receive an event, which belongs to the loop of the
+	// restarted controller, if any.
+	if loop.ctx.Err() != nil && cs.loop != nil {
+		loop = cs.loop
+	}
+
+	for _, dst := range loop.dest {
 		// We cannot make it under goroutine here, or we'll meet the
 		// race condition of writing message to closed channels.
 		// To avoid blocking, the dest channels are expected to be of
@@ -315,21 +376,21 @@ func (cs *Channel) distribute(evt event.GenericEvent) {
 	}
 }
 
-func (cs *Channel) syncLoop(ctx context.Context) {
+func (cs *Channel) syncLoop(loop *channelLoop) {
 	for {
 		select {
-		case <-ctx.Done():
+		case <-loop.ctx.Done():
 			// Close destination channels
-			cs.doStop()
+			cs.doStop(loop)
 			return
 		case evt, stillOpen := <-cs.Source:
 			if !stillOpen {
 				// if the source channel is closed, we're never gonna get
 				// anything more on it, so stop & bail
-				cs.doStop()
+				cs.doStop(loop)
 				return
 			}
-			cs.distribute(evt)
+			cs.distribute(loop, evt)
 		}
 	}
 }
@@ -351,7 +412,7 @@ func (is *Informer) Start(ctx context.Context, handler handler.EventHandler, que
 		return fmt.Errorf("must specify Informer.Informer")
 	}
 
-	is.Informer.AddEventHandler(internal.EventHandler{Queue: queue, EventHandler: handler, Predicates: prct})
+	is.Informer.AddEventHandler(internal.EventHandler{Ctx: ctx, Queue: queue, EventHandler: handler, Predicates: prct})
 	return nil
 }
 
@@ -479,6 +479,27 @@ var _ = Describe("Source", func() {
 				Eventually(processed).Should(Receive())
 				Consistently(processed).ShouldNot(Receive())
 			})
+			It("should provide GenericEvents once started again with a new context", func() {
+				ch := make(chan event.GenericEvent, 1)
+				src := &source.Channel{Source: ch}
+				Expect(inject.StopChannelInto(ctx.Done(), src)).To(BeTrue())
+
+				for i := 0; i < 2; i++ {
+					By(fmt.Sprintf("starting the source, attempt %d", i+1))
+					startCtx, startCancel := context.WithCancel(ctx)
+					q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
+					processed := make(chan struct{}, 1)
+					Expect(src.Start(startCtx, handler.Funcs{
+						GenericFunc: func(event.GenericEvent, workqueue.RateLimitingInterface) {
+							processed <- struct{}{}
+						},
+					}, q)).To(Succeed())
+
+					ch <- event.GenericEvent{}
+					Eventually(processed).Should(Receive())
+					startCancel()
+				}
+			})
 			It("should get error if no source specified", func() {
 				q := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test")
 				instance := &source.Channel{ /*no source specified*/ }
@@ -274,7 +274,7 @@ func StandaloneWebhook(hook *Webhook, opts StandaloneOptions) (http.Handler, err
 	if opts.MetricsPath == "" {
 		return hook, nil
 	}
-	return metrics.InstrumentedHook(opts.MetricsPath, hook), nil
+	return metrics.InstrumentedHook(nil, opts.MetricsPath, hook), nil
 }
 
 // requestContextKey is how we find the admission.Request in a context.Context.
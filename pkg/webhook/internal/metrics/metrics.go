@@ -22,54 +22,89 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	internalmetrics "sigs.k8s.io/controller-runtime/pkg/internal/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
 	// RequestLatency is a prometheus metric which is a histogram of the latency
-	// of processing admission requests.
-	RequestLatency = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name: "controller_runtime_webhook_latency_seconds",
-			Help: "Histogram of the latency of processing admission requests",
-		},
-		[]string{"webhook"},
-	)
+	// of processing admission requests. Its buckets can be configured with
+	// metrics.SetWebhookLatencyBuckets.
+	RequestLatency = defaultVecs.requestLatency
 
 	// RequestTotal is a prometheus metric which is a counter of the total processed admission requests.
-	RequestTotal = func() *prometheus.CounterVec {
-		return prometheus.NewCounterVec(
+	RequestTotal = defaultVecs.requestTotal
+
+	// RequestInFlight is a prometheus metric which is a gauge of the in-flight admission requests.
+	RequestInFlight = defaultVecs.requestInFlight
+)
+
+var (
+	// defaultVecs holds the metrics of webhooks that report into metrics.Registry.
+	defaultVecs = newWebhookVecs()
+
+	// vecsByRegistry holds the metrics of webhooks by the registry they report into.
+	vecsByRegistry = func() *internalmetrics.PerRegistry[*webhookVecs] {
+		p := internalmetrics.NewPerRegistry(func(reg prometheus.Registerer) *webhookVecs {
+			v := newWebhookVecs()
+			reg.MustRegister(v.requestLatency, v.requestTotal, v.requestInFlight)
+			return v
+		})
+		// The metrics of metrics.Registry are registered in init.
+		p.Set(metrics.Registry, defaultVecs)
+		return p
+	}()
+)
+
+// webhookVecs are the metric vectors of the webhooks that report into a registry.
+type webhookVecs struct {
+	requestLatency  *internalmetrics.HistogramVec
+	requestTotal    *prometheus.CounterVec
+	requestInFlight *prometheus.GaugeVec
+}
+
+func newWebhookVecs() *webhookVecs {
+	return &webhookVecs{
+		requestLatency: internalmetrics.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: "controller_runtime_webhook_latency_seconds",
+				Help: "Histogram of the latency of processing admission requests",
+			},
+			[]string{"webhook"},
+		),
+		requestTotal: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: "controller_runtime_webhook_requests_total",
 				Help: "Total number of admission requests by HTTP status code.",
 			},
 			[]string{"webhook", "code"},
-		)
-	}()
-
-	// RequestInFlight is a prometheus metric which is a gauge of the in-flight admission requests.
-	RequestInFlight = func() *prometheus.GaugeVec {
-		return prometheus.NewGaugeVec(
+		),
+		requestInFlight: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Name: "controller_runtime_webhook_requests_in_flight",
 				Help: "Current number of admission requests being served.",
 			},
 			[]string{"webhook"},
-		)
-	}()
-)
+		),
+	}
+}
 
 func init() {
 	metrics.Registry.MustRegister(RequestLatency, RequestTotal, RequestInFlight)
 }
 
-// InstrumentedHook adds some instrumentation on top of the given webhook.
-func InstrumentedHook(path string, hookRaw http.Handler) http.Handler {
+// InstrumentedHook adds some instrumentation on top of the given webhook, reporting
+// into the given registry. The registry defaults to metrics.Registry.
+func InstrumentedHook(reg prometheus.Registerer, path string, hookRaw http.Handler) http.Handler {
+	if reg == nil {
+		reg = metrics.Registry
+	}
+	vecs := vecsByRegistry.For(reg)
 	lbl := prometheus.Labels{"webhook": path}
 
-	lat := RequestLatency.MustCurryWith(lbl)
-	cnt := RequestTotal.MustCurryWith(lbl)
-	gge := RequestInFlight.With(lbl)
+	lat := vecs.requestLatency.MustCurryWith(lbl)
+	cnt := vecs.requestTotal.MustCurryWith(lbl)
+	gge := vecs.requestInFlight.With(lbl)
 
 	// Initialize the most likely HTTP status codes.
 	cnt.WithLabelValues("200")
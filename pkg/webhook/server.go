@@ -29,6 +29,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/runtime"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
@@ -58,6 +59,11 @@ type Server struct {
 	// It will be defaulted to 9443 if unspecified.
 	Port int
 
+	// Listener is the listener the server serves on instead of one bound to Host and
+	// Port, e.g. a unix socket or a socket passed by systemd. The server serves TLS on
+	// it, and closes it when it stops.
+	Listener net.Listener
+
 	// CertDir is the directory that contains the server key and certificate. The
 	// server key and certificate.
 	CertDir string
@@ -76,12 +82,22 @@ type Server struct {
 	// "", "1.0", "1.1", "1.2" and "1.3" only ("" is equivalent to "1.0" for backwards compatibility)
 	TLSMinVersion string
 
-	// TLSOpts is used to allow configuring the TLS config used for the server
+	// DisableHTTP2 makes the server only negotiate HTTP/1.1. HTTP/2 is negotiated
+	// by default.
+	DisableHTTP2 bool
+
+	// TLSOpts is used to allow configuring the TLS config used for the server.
+	// They are applied after DisableHTTP2, so they can override it either way
+	// through the NextProtos of the config, which the server follows.
 	TLSOpts []func(*tls.Config)
 
 	// WebhookMux is the multiplexer that handles different webhooks.
 	WebhookMux *http.ServeMux
 
+	// MetricsRegistry is the registry the metrics of the webhooks report into.
+	// Defaults to metrics.Registry.
+	MetricsRegistry prometheus.Registerer
+
 	// webhooks keep track of all registered webhooks for dependency injection,
 	// and to provide better panic messages on duplicate webhook registration.
 	webhooks map[string]http.Handler
@@ -142,7 +158,7 @@ func (s *Server) Register(path string, hook http.Handler) {
 	}
 	// TODO(directxman12): call setfields if we've already started the server
 	s.webhooks[path] = hook
-	s.WebhookMux.Handle(path, metrics.InstrumentedHook(path, hook))
+	s.WebhookMux.Handle(path, metrics.InstrumentedHook(s.MetricsRegistry, path, hook))
 
 	regLog := log.WithValues("path", path)
 	regLog.Info("Registering webhook")
@@ -239,6 +255,9 @@ func (s *Server) Start(ctx context.Context) error {
 		GetCertificate: certWatcher.GetCertificate,
 		MinVersion:     tlsMinVersion,
 	}
+	if s.DisableHTTP2 {
+		httpserver.DisableHTTP2(cfg)
+	}
 
 	// load CA to verify client certificate
 	if s.ClientCAName != "" {
@@ -262,14 +281,20 @@ func (s *Server) Start(ctx context.Context) error {
 		op(cfg)
 	}
 
-	listener, err := tls.Listen("tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)), cfg)
-	if err != nil {
-		return err
+	var listener net.Listener
+	if s.Listener != nil {
+		listener = tls.NewListener(s.Listener, cfg)
+		log.Info("Serving webhook server", "address", s.Listener.Addr().String())
+	} else {
+		listener, err = tls.Listen("tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)), cfg)
+		if err != nil {
+			return err
+		}
+		log.Info("Serving webhook server", "host", s.Host, "port", s.Port)
 	}
 
-	log.Info("Serving webhook server", "host", s.Host, "port", s.Port)
-
 	srv := httpserver.New(s.WebhookMux)
+	httpserver.ConfigureHTTP2(srv, cfg)
 
 	idleConnsClosed := make(chan struct{})
 	go func() {
@@ -309,8 +334,12 @@ func (s *Server) StartedChecker() healthz.Checker {
 			return fmt.Errorf("webhook server has not been started yet")
 		}
 
+		network, address := "tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+		if s.Listener != nil {
+			network, address = s.Listener.Addr().Network(), s.Listener.Addr().String()
+		}
 		d := &net.Dialer{Timeout: 10 * time.Second}
-		conn, err := tls.DialWithDialer(d, "tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)), config)
+		conn, err := tls.DialWithDialer(d, network, address, config)
 		if err != nil {
 			return fmt.Errorf("webhook server is not reachable: %w", err)
 		}
@@ -19,6 +19,7 @@ package webhook_test
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"net"
@@ -108,8 +109,85 @@ var _ = Describe("Webhook Server", func() {
 		PIt("should verify the client CA name when asked to", func() {
 
 		})
-		PIt("should support HTTP/2", func() {
+		negotiatedProtocol := func() (string, error) {
+			caPool := x509.NewCertPool()
+			caPool.AppendCertsFromPEM(servingOpts.LocalServingCAData)
+			conn, err := tls.Dial("tcp", testHostPort, &tls.Config{
+				RootCAs:    caPool,
+				NextProtos: []string{"h2", "http/1.1"},
+				MinVersion: tls.VersionTLS12,
+			})
+			if err != nil {
+				return "", err
+			}
+			defer conn.Close()
+			return conn.ConnectionState().NegotiatedProtocol, nil
+		}
+
+		It("should support HTTP/2", func() {
+			doneCh := startServer()
+
+			Expect(negotiatedProtocol()).To(Equal("h2"))
+
+			ctxCancel()
+			Eventually(doneCh, "4s").Should(BeClosed())
+		})
+
+		It("should only negotiate HTTP/1.1 if HTTP/2 is disabled", func() {
+			server.DisableHTTP2 = true
+			doneCh := startServer()
+
+			Expect(negotiatedProtocol()).To(Equal("http/1.1"))
+			resp, err := client.Get(fmt.Sprintf("https://%s/unservedpath", testHostPort))
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			Expect(resp.ProtoMajor).To(Equal(1))
+
+			ctxCancel()
+			Eventually(doneCh, "4s").Should(BeClosed())
+		})
+
+		It("should let the TLS options override disabling HTTP/2", func() {
+			server.DisableHTTP2 = true
+			server.TLSOpts = []func(*tls.Config){func(cfg *tls.Config) {
+				cfg.NextProtos = []string{"h2", "http/1.1"}
+			}}
+			doneCh := startServer()
+
+			Expect(negotiatedProtocol()).To(Equal("h2"))
 
+			ctxCancel()
+			Eventually(doneCh, "4s").Should(BeClosed())
+		})
+
+		It("should let the TLS options disable HTTP/2", func() {
+			server.TLSOpts = []func(*tls.Config){func(cfg *tls.Config) {
+				cfg.NextProtos = []string{"http/1.1"}
+			}}
+			doneCh := startServer()
+
+			Expect(negotiatedProtocol()).To(Equal("http/1.1"))
+			resp, err := client.Get(fmt.Sprintf("https://%s/unservedpath", testHostPort))
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			Expect(resp.ProtoMajor).To(Equal(1))
+
+			ctxCancel()
+			Eventually(doneCh, "4s").Should(BeClosed())
+		})
+
+		It("should serve on the given Listener", func() {
+			ln, err := net.Listen("tcp", net.JoinHostPort(servingOpts.LocalServingHost, "0"))
+			Expect(err).NotTo(HaveOccurred())
+			server.Listener = ln
+			testHostPort = ln.Addr().String()
+			doneCh := startServer()
+
+			Expect(negotiatedProtocol()).To(Equal("h2"))
+			Expect(server.StartedChecker()(nil)).To(Succeed())
+
+			ctxCancel()
+			Eventually(doneCh, "4s").Should(BeClosed())
 		})
 
 		// TODO(directxman12): figure out a good way to test the port default, etc
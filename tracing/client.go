@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Attributes of the spans of API calls.
+const (
+	VerbKey        = attribute.Key("k8s.verb")
+	GroupKey       = attribute.Key("k8s.group")
+	VersionKey     = attribute.Key("k8s.version")
+	KindKey        = attribute.Key("k8s.kind")
+	SubresourceKey = attribute.Key("k8s.subresource")
+)
+
+// NewClient returns a client that runs each API call of c in a span named after the verb,
+// with the verb and GroupVersionKind of the object as attributes. The spans are children
+// of the span of the context of the call, e.g. the span of a reconciliation.
+func NewClient(c client.Client, opts ...Option) client.Client {
+	return &tracingClient{Client: c, tracer: newConfig(opts).tracer()}
+}
+
+type tracingClient struct {
+	client.Client
+	tracer trace.Tracer
+}
+
+var _ client.Client = &tracingClient{}
+
+// start starts the span of an API call, whose attributes are only computed if the span
+// is recorded.
+func start(ctx context.Context, tracer trace.Tracer, c client.Client, verb, subresource string, obj runtime.Object) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(ctx, verb, trace.WithSpanKind(trace.SpanKindClient))
+	if !span.IsRecording() {
+		return ctx, span
+	}
+	span.SetAttributes(VerbKey.String(verb))
+	if subresource != "" {
+		span.SetAttributes(SubresourceKey.String(subresource))
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err == nil {
+		span.SetAttributes(GroupKey.String(gvk.Group), VersionKey.String(gvk.Version), KindKey.String(gvk.Kind))
+	}
+	return ctx, span
+}
+
+// end records the error of an API call, and ends its span.
+func end(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+	return err
+}
+
+func (c *tracingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	ctx, span := start(ctx, c.tracer, c.Client, "get", "", obj)
+	return end(span, c.Client.Get(ctx, key, obj, opts...))
+}
+
+func (c *tracingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	ctx, span := start(ctx, c.tracer, c.Client, "list", "", list)
+	return end(span, c.Client.List(ctx, list, opts...))
+}
+
+func (c *tracingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, span := start(ctx, c.tracer, c.Client, "create", "", obj)
+	return end(span, c.Client.Create(ctx, obj, opts...))
+}
+
+func (c *tracingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, span := start(ctx, c.tracer, c.Client, "delete", "", obj)
+	return end(span, c.Client.Delete(ctx, obj, opts...))
+}
+
+func (c *tracingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := start(ctx, c.tracer, c.Client, "update", "", obj)
+	return end(span, c.Client.Update(ctx, obj, opts...))
+}
+
+func (c *tracingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, span := start(ctx, c.tracer, c.Client, "patch", "", obj)
+	return end(span, c.Client.Patch(ctx, obj, patch, opts...))
+}
+
+func (c *tracingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	ctx, span := start(ctx, c.tracer, c.Client, "deletecollection", "", obj)
+	return end(span, c.Client.DeleteAllOf(ctx, obj, opts...))
+}
+
+func (c *tracingClient) Status() client.StatusWriter {
+	return &tracingStatusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+type tracingStatusWriter struct {
+	client.StatusWriter
+	client *tracingClient
+}
+
+func (w *tracingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := start(ctx, w.client.tracer, w.client.Client, "update", "status", obj)
+	return end(span, w.StatusWriter.Update(ctx, obj, opts...))
+}
+
+func (w *tracingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, span := start(ctx, w.client.tracer, w.client.Client, "patch", "status", obj)
+	return end(span, w.StatusWriter.Patch(ctx, obj, patch, opts...))
+}
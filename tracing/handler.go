@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+)
+
+// PathKey is the attribute of the spans of webhook requests holding the path of the webhook.
+const PathKey = attribute.Key("http.target")
+
+// NewHandler returns a handler that extracts the trace context from the headers of the
+// requests of the webhook handler h, and serves them in a span that continues the trace.
+// The context of the admission and conversion requests passed to h holds the span, so
+// the calls of a client returned by NewClient are its children.
+func NewHandler(h http.Handler, opts ...Option) http.Handler {
+	c := newConfig(opts)
+	return &tracingHandler{handler: h, tracer: c.tracer(), propagator: c.propagator}
+}
+
+type tracingHandler struct {
+	handler    http.Handler
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+var (
+	_ inject.Injector = &tracingHandler{}
+	_ inject.Logger   = &tracingHandler{}
+)
+
+// InjectFunc injects the dependencies of the wrapped handler, as the webhook server
+// only injects them into the handlers it's registered with.
+func (h *tracingHandler) InjectFunc(f inject.Func) error {
+	return f(h.handler)
+}
+
+// InjectLogger injects the logger of the wrapped handler.
+func (h *tracingHandler) InjectLogger(l logr.Logger) error {
+	_, err := inject.LoggerInto(l, h.handler)
+	return err
+}
+
+func (h *tracingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := h.propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := h.tracer.Start(ctx, r.URL.Path, trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+	if span.IsRecording() {
+		span.SetAttributes(PathKey.String(r.URL.Path))
+	}
+	h.handler.ServeHTTP(w, r.WithContext(ctx))
+}
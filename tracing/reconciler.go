@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Attributes of the spans of reconciliations.
+const (
+	ControllerKey   = attribute.Key("controller")
+	NamespaceKey    = attribute.Key("k8s.namespace.name")
+	NameKey         = attribute.Key("k8s.object.name")
+	ReconcileIDKey  = attribute.Key("controller.reconcile_id")
+	RequeueKey      = attribute.Key("controller.requeue")
+	RequeueAfterKey = attribute.Key("controller.requeue_after")
+)
+
+// NewReconciler returns a reconciler that runs each reconciliation of r in a span named
+// after the controller, with the key of the object and the ID of the reconciliation as
+// attributes. The error of a failed reconciliation is recorded on the span.
+func NewReconciler(name string, r reconcile.Reconciler, opts ...Option) reconcile.Reconciler {
+	return &tracingReconciler{name: name, reconciler: r, tracer: newConfig(opts).tracer()}
+}
+
+type tracingReconciler struct {
+	name       string
+	reconciler reconcile.Reconciler
+	tracer     trace.Tracer
+}
+
+func (r *tracingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, span := r.tracer.Start(ctx, r.name, trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+	if span.IsRecording() {
+		span.SetAttributes(
+			ControllerKey.String(r.name),
+			NamespaceKey.String(req.Namespace),
+			NameKey.String(req.Name),
+		)
+		if reconcileID := controller.ReconcileIDFromContext(ctx); reconcileID != "" {
+			span.SetAttributes(ReconcileIDKey.String(string(reconcileID)))
+		}
+	}
+
+	result, err := r.reconciler.Reconcile(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return result, err
+	}
+	if span.IsRecording() {
+		span.SetAttributes(RequeueKey.Bool(result.Requeue))
+		if result.RequeueAfter > 0 {
+			span.SetAttributes(RequeueAfterKey.String(result.RequeueAfter.String()))
+		}
+	}
+	return result, nil
+}
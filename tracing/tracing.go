@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing integrates controller-runtime with OpenTelemetry tracing. It lives in
+// its own module, so that controller-runtime doesn't depend on OpenTelemetry.
+//
+// NewReconciler starts a span per reconciliation, NewClient a child span per API call,
+// and NewHandler continues the trace of the requests of webhooks:
+//
+//	c, err := controller.New("replicaset", mgr, controller.Options{
+//		Reconciler: tracing.NewReconciler("replicaset", &ReconcileReplicaSet{
+//			client: tracing.NewClient(mgr.GetClient()),
+//		}),
+//	})
+//
+//	mgr.GetWebhookServer().Register("/validate", tracing.NewHandler(hook))
+//
+// The spans are created with the global TracerProvider of OpenTelemetry unless another
+// one is passed with WithTracerProvider. Without a configured TracerProvider, the spans
+// are no-ops and their attributes aren't computed.
+package tracing
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer of controller-runtime.
+const instrumentationName = "sigs.k8s.io/controller-runtime"
+
+// Option configures the tracing of reconcilers, clients and handlers.
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider used to create spans instead of the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets the propagator used to extract the trace context of the requests
+// of webhooks instead of the global one.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+	if c.propagator == nil {
+		c.propagator = otel.GetTextMapPropagator()
+	}
+	return c
+}
+
+func (c *config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		exporter *tracetest.InMemoryExporter
+		provider *sdktrace.TracerProvider
+		cl       client.Client
+	)
+
+	BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		cl = NewClient(fake.NewClientBuilder().WithObjects(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"}},
+		).Build(), WithTracerProvider(provider))
+	})
+
+	// spans returns the ended spans by name.
+	spans := func() map[string]tracetest.SpanStub {
+		byName := map[string]tracetest.SpanStub{}
+		for _, span := range exporter.GetSpans() {
+			byName[span.Name] = span
+		}
+		return byName
+	}
+
+	attributes := func(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+		attrs := map[attribute.Key]attribute.Value{}
+		for _, kv := range span.Attributes {
+			attrs[kv.Key] = kv.Value
+		}
+		return attrs
+	}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"}}
+
+	It("should run reconciliations in spans whose children are the API calls", func() {
+		r := NewReconciler("configmap", reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			cm := &corev1.ConfigMap{}
+			if err := cl.Get(ctx, req.NamespacedName, cm); err != nil {
+				return reconcile.Result{}, err
+			}
+			cm.Data = map[string]string{"reconciled": "true"}
+			if err := cl.Update(ctx, cm); err != nil {
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{RequeueAfter: time.Minute}, nil
+		}), WithTracerProvider(provider))
+
+		Expect(r.Reconcile(context.Background(), request)).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+
+		Expect(exporter.GetSpans()).To(HaveLen(3))
+		byName := spans()
+		reconcileSpan := byName["configmap"]
+		Expect(reconcileSpan.Parent.IsValid()).To(BeFalse())
+		Expect(attributes(reconcileSpan)).To(And(
+			HaveKeyWithValue(ControllerKey, attribute.StringValue("configmap")),
+			HaveKeyWithValue(NamespaceKey, attribute.StringValue("default")),
+			HaveKeyWithValue(NameKey, attribute.StringValue("foo")),
+			HaveKeyWithValue(RequeueAfterKey, attribute.StringValue("1m0s")),
+			Not(HaveKey(ReconcileIDKey)),
+		))
+
+		for _, verb := range []string{"get", "update"} {
+			span := byName[verb]
+			Expect(span.Parent.SpanID()).To(Equal(reconcileSpan.SpanContext.SpanID()), verb)
+			Expect(span.SpanKind).To(Equal(trace.SpanKindClient))
+			Expect(attributes(span)).To(And(
+				HaveKeyWithValue(VerbKey, attribute.StringValue(verb)),
+				HaveKeyWithValue(GroupKey, attribute.StringValue("")),
+				HaveKeyWithValue(VersionKey, attribute.StringValue("v1")),
+				HaveKeyWithValue(KindKey, attribute.StringValue("ConfigMap")),
+			), verb)
+		}
+	})
+
+	It("should record the errors of reconciliations and API calls", func() {
+		r := NewReconciler("configmap", reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			return reconcile.Result{}, cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: "missing"}, &corev1.ConfigMap{})
+		}), WithTracerProvider(provider))
+
+		_, err := r.Reconcile(context.Background(), request)
+		Expect(err).To(HaveOccurred())
+
+		byName := spans()
+		Expect(byName["configmap"].Status.Code).To(Equal(codes.Error))
+		Expect(byName["configmap"].Events).To(ContainElement(HaveField("Name", "exception")))
+		Expect(byName["get"].Status.Code).To(Equal(codes.Error))
+	})
+
+	It("should trace the status writes with their subresource", func() {
+		cm := &corev1.ConfigMap{}
+		Expect(cl.Get(context.Background(), request.NamespacedName, cm)).To(Succeed())
+		// The fake client doesn't support the status of config maps.
+		_ = cl.Status().Update(context.Background(), cm)
+
+		Expect(attributes(spans()["update"])).To(HaveKeyWithValue(SubresourceKey, attribute.StringValue("status")))
+	})
+
+	It("should continue the trace of webhook requests", func() {
+		propagator := propagation.TraceContext{}
+		handler := NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(cl.Get(r.Context(), request.NamespacedName, &corev1.ConfigMap{})).To(Succeed())
+		}), WithTracerProvider(provider), WithPropagator(propagator))
+
+		By("starting the trace of the caller")
+		callerCtx, callerSpan := provider.Tracer("caller").Start(context.Background(), "caller")
+		req := httptest.NewRequest(http.MethodPost, "/validate", nil)
+		propagator.Inject(callerCtx, propagation.HeaderCarrier(req.Header))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		callerSpan.End()
+
+		byName := spans()
+		webhookSpan := byName["/validate"]
+		Expect(webhookSpan.SpanKind).To(Equal(trace.SpanKindServer))
+		Expect(webhookSpan.SpanContext.TraceID()).To(Equal(callerSpan.SpanContext().TraceID()))
+		Expect(webhookSpan.Parent.SpanID()).To(Equal(callerSpan.SpanContext().SpanID()))
+		Expect(webhookSpan.Parent.IsRemote()).To(BeTrue())
+		Expect(byName["get"].Parent.SpanID()).To(Equal(webhookSpan.SpanContext.SpanID()))
+	})
+
+	It("should not record spans without a tracer provider", func() {
+		noop := trace.NewNoopTracerProvider()
+		cl := NewClient(fake.NewClientBuilder().Build(), WithTracerProvider(noop))
+		err := errors.New("failed")
+		r := NewReconciler("noop", reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+			Expect(trace.SpanFromContext(ctx).IsRecording()).To(BeFalse())
+			_ = cl.Get(ctx, req.NamespacedName, &corev1.ConfigMap{})
+			return reconcile.Result{Requeue: true}, err
+		}), WithTracerProvider(noop))
+
+		result, reconcileErr := r.Reconcile(context.Background(), request)
+		Expect(result).To(Equal(reconcile.Result{Requeue: true}))
+		Expect(reconcileErr).To(BeIdenticalTo(err))
+		Expect(exporter.GetSpans()).To(BeEmpty())
+	})
+})